@@ -0,0 +1,304 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/network"
+)
+
+// build makes release builds: the native client for Windows, macOS and Linux, the
+// server, the browser bundle (WASM with the static files) and the Android/iOS
+// libraries. Every binary is stamped with the version and commit, which client and
+// server exchange at login.
+//
+//	go run ./cmd/build                        # everything into dist/
+//	go run ./cmd/build -targets wasm,server   # only these
+//
+// The macOS and Linux clients need cgo, so each is only built on its own OS. A target
+// that fails is reported and the rest carry on; the exit status is 1 if any failed.
+func main() {
+	out := flag.String("out", "dist", "Output directory")
+	targets := flag.String("targets", strings.Join(allTargets, ","), "Comma-separated targets: "+strings.Join(allTargets, ", "))
+	version := flag.String("version", config.GameVersion, "Version to stamp")
+	commit := flag.String("commit", gitCommit(), "Commit to stamp")
+	flag.Parse()
+
+	b := &builder{out: *out, version: *version, commit: *commit}
+	for _, target := range strings.Split(*targets, ",") {
+		target = strings.TrimSpace(target)
+		if !slices.Contains(allTargets, target) {
+			log.Fatalf("Unknown target %q (want %s)", target, strings.Join(allTargets, ", "))
+		}
+		b.targets = append(b.targets, target)
+	}
+	if err := os.MkdirAll(b.out, 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Building %s (protocol %s) into %s", b.stamp(), network.ProtocolFingerprint(), b.out)
+	for _, target := range b.targets {
+		switch target {
+		case "native":
+			b.native()
+		case "server":
+			b.server()
+		case "wasm":
+			b.wasm()
+		case "mobile":
+			b.mobile()
+		}
+	}
+	if err := b.writeManifest(); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, failure := range b.failed {
+		log.Printf("FAILED %s", failure)
+	}
+	if len(b.failed) > 0 {
+		os.Exit(1)
+	}
+}
+
+var allTargets = []string{"native", "server", "wasm", "mobile"}
+
+// Native client builds
+var nativePlatforms = []struct {
+	GOOS, GOARCH string
+	Cgo          bool // The windowing code needs a C toolchain for this OS
+}{
+	{"windows", "amd64", false},
+	{"darwin", "amd64", true},
+	{"darwin", "arm64", true},
+	{"linux", "amd64", true},
+}
+
+const (
+	clientPkg = "./cmd/client"
+	serverPkg = "./cmd/server"
+	mobilePkg = "./cmd/client/mobile"
+	staticDir = "static"
+	javaPkg   = "com.henry.mmorpg" // Android package of the bound library
+)
+
+type builder struct {
+	out             string
+	version, commit string
+	targets         []string
+	artifacts       []string // Relative to out
+	failed          []string
+}
+
+// Manifest is written to build.json next to the artifacts
+type Manifest struct {
+	Version   string
+	Commit    string
+	Protocol  string // network.ProtocolFingerprint(): clients and servers must match
+	Built     time.Time
+	Artifacts []string
+}
+
+func (b *builder) stamp() string {
+	return b.version + " (" + b.commit + ")"
+}
+
+func (b *builder) ldflags() string {
+	return fmt.Sprintf("-s -w -X henry/pkg/shared/config.Version=%s -X henry/pkg/shared/config.Commit=%s", b.version, b.commit)
+}
+
+func (b *builder) native() {
+	for _, p := range nativePlatforms {
+		name := fmt.Sprintf("client-%s-%s", p.GOOS, p.GOARCH)
+		if p.GOOS == "windows" {
+			name += ".exe"
+		}
+		env := []string{"GOOS=" + p.GOOS, "GOARCH=" + p.GOARCH}
+		if p.Cgo {
+			if p.GOOS != runtime.GOOS {
+				log.Printf("Skipping %s: it needs cgo, build it on %s", name, p.GOOS)
+				continue
+			}
+			env = append(env, "CGO_ENABLED=1")
+		}
+		b.goBuild(name, clientPkg, env...)
+	}
+}
+
+// server builds for this machine
+func (b *builder) server() {
+	name := "server"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	b.goBuild(name, serverPkg)
+}
+
+// wasm builds the browser bundle into web/: the static files, the wasm_exec.js that
+// matches this Go toolchain and client.wasm
+func (b *builder) wasm() {
+	web := filepath.Join(b.out, "web")
+	if err := os.RemoveAll(web); err != nil {
+		b.fail("web", err)
+		return
+	}
+	if err := copyDir(staticDir, web, func(path string) bool { return filepath.Base(path) != "client.wasm" }); err != nil {
+		b.fail("web", err)
+		return
+	}
+	if src := wasmExecJS(); src != "" {
+		if err := copyFile(src, filepath.Join(web, "wasm_exec.js")); err != nil {
+			b.fail("web", err)
+			return
+		}
+	} else {
+		log.Printf("No wasm_exec.js in this Go install, keeping the one in %s", staticDir)
+	}
+	b.goBuild(filepath.Join("web", "client.wasm"), clientPkg, "GOOS=js", "GOARCH=wasm")
+}
+
+// mobile binds the client for Android (and iOS on macOS) with ebitenmobile, when it's
+// installed
+func (b *builder) mobile() {
+	if _, err := exec.LookPath("ebitenmobile"); err != nil {
+		log.Printf("Skipping mobile: ebitenmobile is not installed (go install github.com/hajimehoshi/ebiten/v2/cmd/ebitenmobile@%s)", ebitenVersion())
+		return
+	}
+	dir := filepath.Join(b.out, "mobile")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		b.fail("mobile", err)
+		return
+	}
+	b.run(filepath.Join("mobile", "henry.aar"), "ebitenmobile", nil,
+		"bind", "-target", "android", "-javapkg", javaPkg, "-ldflags", b.ldflags(), "-o", filepath.Join(dir, "henry.aar"), mobilePkg)
+	if runtime.GOOS == "darwin" {
+		b.run(filepath.Join("mobile", "Henry.xcframework"), "ebitenmobile", nil,
+			"bind", "-target", "ios", "-ldflags", b.ldflags(), "-o", filepath.Join(dir, "Henry.xcframework"), mobilePkg)
+	} else {
+		log.Printf("Skipping iOS: it can only be built on macOS")
+	}
+}
+
+// goBuild builds pkg into out/name with extra environment (GOOS, GOARCH)
+func (b *builder) goBuild(name, pkg string, env ...string) {
+	b.run(name, "go", env, "build", "-trimpath", "-ldflags", b.ldflags(), "-o", filepath.Join(b.out, name), pkg)
+}
+
+// run runs a build command and records the artifact, or the failure
+func (b *builder) run(artifact, command string, env []string, args ...string) {
+	log.Printf("Building %s", artifact)
+	cmd := exec.Command(command, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		b.fail(artifact, err)
+		return
+	}
+	b.artifacts = append(b.artifacts, filepath.ToSlash(artifact))
+}
+
+func (b *builder) fail(artifact string, err error) {
+	log.Printf("%s: %v", artifact, err)
+	b.failed = append(b.failed, artifact)
+}
+
+func (b *builder) writeManifest() error {
+	data, err := json.MarshalIndent(Manifest{
+		Version:   b.version,
+		Commit:    b.commit,
+		Protocol:  network.ProtocolFingerprint(),
+		Built:     time.Now().UTC(),
+		Artifacts: b.artifacts,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(b.out, "build.json"), append(data, '\n'), 0644)
+}
+
+// gitCommit is the short hash of HEAD, marked -dirty with uncommitted changes
+func gitCommit() string {
+	hash, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return "unknown"
+	}
+	commit := strings.TrimSpace(string(hash))
+	if status, err := exec.Command("git", "status", "--porcelain").Output(); err == nil && len(status) > 0 {
+		commit += "-dirty"
+	}
+	return commit
+}
+
+// ebitenVersion is the Ebitengine version in go.mod (ebitenmobile must match it)
+func ebitenVersion() string {
+	version, err := exec.Command("go", "list", "-m", "-f", "{{.Version}}", "github.com/hajimehoshi/ebiten/v2").Output()
+	if err != nil {
+		return "latest"
+	}
+	return strings.TrimSpace(string(version))
+}
+
+// wasmExecJS finds the JS glue of the Go toolchain in use ("" if there is none)
+func wasmExecJS() string {
+	root, err := exec.Command("go", "env", "GOROOT").Output()
+	if err != nil {
+		return ""
+	}
+	for _, dir := range []string{"lib/wasm", "misc/wasm"} { // Go 1.24 moved it to lib/
+		path := filepath.Join(strings.TrimSpace(string(root)), dir, "wasm_exec.js")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// copyDir copies the files under src that keep accepts into dst
+func copyDir(src, dst string, keep func(path string) bool) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		if !keep(path) {
+			return nil
+		}
+		return copyFile(path, filepath.Join(dst, rel))
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 
 	"henry/pkg/client"
@@ -9,7 +10,17 @@ import (
 )
 
 func main() {
+	record := flag.String("record", "", "Record what the server sends after logging in to this file")
+	replay := flag.String("replay", "", "Play back a recording instead of connecting")
+	flag.Parse()
+
 	game := client.NewGame()
+	game.RecordPath = *record
+	if *replay != "" {
+		if err := game.StartReplay(*replay); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	ebiten.SetWindowSize(client.ScreenWidth, client.ScreenHeight)
 	ebiten.SetWindowTitle("Henry MMORPG (WASM Ready)")
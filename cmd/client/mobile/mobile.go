@@ -0,0 +1,17 @@
+// Package mobile is the client as an Android/iOS library, built by cmd/build with
+// ebitenmobile bind. The host app shows it in an EbitenView.
+package mobile
+
+import (
+	"henry/pkg/client"
+
+	ebitenmobile "github.com/hajimehoshi/ebiten/v2/mobile"
+)
+
+func init() {
+	ebitenmobile.SetGame(client.NewGame())
+}
+
+// Dummy gives gomobile something to export: bind refuses packages without exported
+// functions
+func Dummy() {}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/world"
+)
+
+// mapcheck verifies that the player spawn, every spawner, waypoint and teleport in the
+// map directory are reachable on foot (or through teleports). It exits non-zero on any
+// unreachable point, and with -strict also on walled-off walkable pockets.
+func main() {
+	mapDir := flag.String("maps", config.MapDir, "Directory with level_*.json maps")
+	strict := flag.Bool("strict", false, "Also fail on walkable pockets nothing leads to")
+	maxPockets := flag.Int("pockets", 10, "Pockets to list (largest first, 0 = all)")
+	flag.Parse()
+
+	maps, err := world.LoadMaps(*mapDir)
+	if err != nil {
+		log.Fatalf("Failed to load maps: %v", err)
+	}
+	if _, ok := maps[0]; !ok {
+		log.Fatalf("No level 0 map in %s", *mapDir)
+	}
+
+	spawn := world.MapPoint{Kind: "spawn", Name: "player", X: config.PlayerSpawnX, Y: config.PlayerSpawnY}
+	report := world.ValidateMaps(maps, spawn, config.TileSize, components.DefaultColliderSize, config.ShorePushRange)
+
+	for _, p := range report.Unreachable {
+		fmt.Printf("ERROR unreachable %s\n", p)
+	}
+	for i, p := range report.Pockets {
+		if *maxPockets > 0 && i == *maxPockets {
+			fmt.Printf("... and %d more pocket(s)\n", len(report.Pockets)-i)
+			break
+		}
+		fmt.Printf("WARN  pocket of %d sub-cell(s) at level %d tile %d,%d\n", p.Cells, p.Z, p.TX, p.TY)
+	}
+	fmt.Printf("%d map(s): %d unreachable, %d pocket(s)\n", len(maps), len(report.Unreachable), len(report.Pockets))
+
+	if !report.OK() || (*strict && len(report.Pockets) > 0) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"math/rand"
+
+	"henry/pkg/shared/world"
+)
+
+// Decoration densities (chance per eligible tile), set by flags
+type decorOptions struct {
+	Forest        float64 // Trees on grass far from paths
+	Flowers       float64 // Flowers inside meadow clusters
+	Rocks         float64 // Rocks on and around beaches
+	Reeds         float64 // Reeds along the waterline
+	PathClearance int     // Tiles either side of a path kept free of trees
+	Meadows       int     // Number of flower meadows
+}
+
+// Trees thin out towards paths: full Forest density is reached this many tiles past the clearance
+const forestRamp = 6
+
+// decorate fills the object layer according to the biome under each tile. Objects only go
+// on empty tiles, so earlier passes (trees) win over later ones (flowers).
+func decorate(ground, objects [][]int, opts decorOptions) {
+	height, width := len(ground), len(ground[0])
+	pathDist := distanceTo(ground, isPath)
+	waterDist := distanceTo(ground, isWater)
+	sandDist := distanceTo(ground, func(t world.TileType) bool { return t == world.TileSand })
+	place := func(x, y, id int, chance float64) {
+		if objects[y][x] == 0 && rand.Float64() < chance {
+			objects[y][x] = id
+		}
+	}
+
+	// Forests: none beside paths, thickening with distance
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if !isGrass(world.TileType(ground[y][x])) {
+				continue
+			}
+			ramp := float64(pathDist[y][x]-opts.PathClearance) / forestRamp
+			if ramp > 1 {
+				ramp = 1
+			}
+			if ramp > 0 {
+				place(x, y, world.ObjectTree, opts.Forest*ramp)
+			}
+		}
+	}
+
+	// Rocks on the beach and the grass just behind it
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			t := world.TileType(ground[y][x])
+			if sandDist[y][x] <= 1 && (t == world.TileSand || isGrass(t)) && pathDist[y][x] > 0 {
+				place(x, y, world.ObjectRock, opts.Rocks)
+			}
+		}
+	}
+
+	// Reeds where shallow water meets land, and on the shore next to it
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			t := world.TileType(ground[y][x])
+			atEdge := (t == world.TileWaterShallow && touchesLand(ground, x, y)) ||
+				(!isWater(t) && !isPath(t) && waterDist[y][x] == 1)
+			if atEdge {
+				place(x, y, world.ObjectReeds, opts.Reeds)
+			}
+		}
+	}
+
+	// Meadows: flower clusters, densest at their center
+	for i := 0; i < opts.Meadows; i++ {
+		cx, cy := rand.Intn(width), rand.Intn(height)
+		radius := 2 + rand.Intn(3)
+		for y := cy - radius; y <= cy+radius; y++ {
+			for x := cx - radius; x <= cx+radius; x++ {
+				if x < 0 || y < 0 || x >= width || y >= height || !isGrass(world.TileType(ground[y][x])) {
+					continue
+				}
+				dx, dy := x-cx, y-cy
+				if falloff := 1 - float64(dx*dx+dy*dy)/float64(radius*radius+1); falloff > 0 {
+					place(x, y, world.ObjectFlowers, opts.Flowers*falloff)
+				}
+			}
+		}
+	}
+}
+
+func isGrass(t world.TileType) bool {
+	return t == world.TileGrass || t == world.TileGrassFlowers
+}
+
+func isWater(t world.TileType) bool {
+	return t == world.TileWater || t == world.TileWaterDeep || t == world.TileWaterShallow
+}
+
+func isPath(t world.TileType) bool {
+	return t == world.TileDirtPath || t == world.TileCobblePath || t == world.TileBridge || t == world.TileDock
+}
+
+// distanceTo returns, for every tile, how many steps (8-way) away the nearest tile
+// matching match is. Tiles with no match anywhere get width+height.
+func distanceTo(ground [][]int, match func(world.TileType) bool) [][]int {
+	height, width := len(ground), len(ground[0])
+	dist := make([][]int, height)
+	var queue [][2]int
+	for y := range dist {
+		dist[y] = make([]int, width)
+		for x := range dist[y] {
+			dist[y][x] = width + height
+			if match(world.TileType(ground[y][x])) {
+				dist[y][x] = 0
+				queue = append(queue, [2]int{x, y})
+			}
+		}
+	}
+	for len(queue) > 0 {
+		x, y := queue[0][0], queue[0][1]
+		queue = queue[1:]
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				nx, ny := x+dx, y+dy
+				if nx < 0 || ny < 0 || nx >= width || ny >= height || dist[ny][nx] <= dist[y][x]+1 {
+					continue
+				}
+				dist[ny][nx] = dist[y][x] + 1
+				queue = append(queue, [2]int{nx, ny})
+			}
+		}
+	}
+	return dist
+}
+
+// touchesLand reports whether any of a tile's 8 neighbours is dry
+func touchesLand(ground [][]int, x, y int) bool {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			nx, ny := x+dx, y+dy
+			if ny >= 0 && ny < len(ground) && nx >= 0 && nx < len(ground[ny]) && !isWater(world.TileType(ground[ny][nx])) {
+				return true
+			}
+		}
+	}
+	return false
+}
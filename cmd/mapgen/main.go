@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"math/rand"
 	"os"
@@ -10,11 +11,16 @@ import (
 )
 
 type MapData struct {
-	Level    int       `json:"level"`
-	Width    int       `json:"width"`
-	Height   int       `json:"height"`
-	Layers   Layers    `json:"layers"`
-	Spawners []Spawner `json:"spawners"`
+	Level     int                 `json:"level"`
+	Width     int                 `json:"width"`
+	Height    int                 `json:"height"`
+	Layers    Layers              `json:"layers"`
+	Spawners  []Spawner           `json:"spawners"`
+	Zones     []world.ZoneDef     `json:"zones,omitempty"`
+	Waypoints []world.WaypointDef `json:"waypoints,omitempty"`
+	Triggers  []world.TriggerDef  `json:"triggers,omitempty"`
+
+	FriendlyFire bool `json:"friendly_fire,omitempty"`
 }
 
 type Layers struct {
@@ -29,6 +35,15 @@ type Spawner struct {
 }
 
 func main() {
+	var decor decorOptions
+	flag.Float64Var(&decor.Forest, "forest", 0.15, "Tree density far from paths (0-1)")
+	flag.IntVar(&decor.PathClearance, "path-clearance", 1, "Tiles beside paths kept free of trees")
+	flag.Float64Var(&decor.Flowers, "flowers", 0.6, "Flower density inside meadows (0-1)")
+	flag.IntVar(&decor.Meadows, "meadows", 12, "Number of flower meadows")
+	flag.Float64Var(&decor.Rocks, "rocks", 0.12, "Rock density on and around beaches (0-1)")
+	flag.Float64Var(&decor.Reeds, "reeds", 0.5, "Reed density along the waterline (0-1)")
+	flag.Parse()
+
 	width := 60
 	height := 60
 
@@ -68,39 +83,31 @@ func main() {
 		}
 	}
 
-	// Paths: Cross from W->E and N->S
+	// Paths: Cross from W->E and N->S, on docks over the shallows and a bridge over deep water
 	for i := 0; i < width; i++ {
 		// Horizontal Path
-		if i < 20 || i > 40 { // Don't bridge the lake automatically, let's make a dock/bridge
-			ground[30][i] = int(world.TileCobblePath)
-		} else {
-			// Bridge over lake
-			ground[30][i] = int(world.TileCobblePath) // Wooden bridge in future?
-		}
+		ground[30][i] = pathTile(ground[30][i], world.TileCobblePath)
 
 		// Vertical Path
-		ground[i][30] = int(world.TileDirtPath)
+		ground[i][30] = pathTile(ground[i][30], world.TileDirtPath)
 	}
 
-	// Objects (Trees)
-	for y := 0; y < height; y++ {
-		for x := 0; x < width; x++ {
-			t := world.TileType(ground[y][x])
-
-			// Trees only on Grass
-			if t == world.TileGrass || t == world.TileGrassFlowers {
-				if rand.Float64() < 0.1 { // 10% density
-					objects[y][x] = int(world.TileTree) // Tree ID
-				}
-			}
+	// Farmland near town (trees only grow on grass, so it stays clear for crops)
+	for y := 9; y <= 11; y++ {
+		for x := 1; x <= 4; x++ {
+			ground[y][x] = int(world.TileFarmland)
 		}
 	}
 
+	// Objects (Trees, rocks, reeds, flowers)
+	decorate(ground, objects, decor)
+
 	// Spawners
 	spawners := []Spawner{
 		{X: 100, Y: 100, CharacterID: "guard_melee"},
 		{X: 150, Y: 100, CharacterID: "guard_melee"},
 		{X: 500, Y: 500, CharacterID: "guard_ranged"},
+		{X: 256, Y: 320, CharacterID: "training_dummy"},
 	}
 
 	// Add random NPCs
@@ -140,7 +147,7 @@ func main() {
 					valid = false
 					break
 				}
-				if objects[cy][cx] > 0 {
+				if world.IsSolidObject(objects[cy][cx]) {
 					valid = false
 					break
 				}
@@ -167,6 +174,29 @@ func main() {
 		})
 	}
 
+	// Zones (World coordinates, 64px tiles)
+	zones := []world.ZoneDef{
+		{ID: "town", Name: "Henry Town", MinLevel: 1, MaxLevel: 3, Music: "town", X: 0, Y: 0, Width: 768, Height: 768},
+		{ID: "mirror_lake", Name: "Mirror Lake", MinLevel: 3, MaxLevel: 6, Music: "lake", X: 1280, Y: 1280, Width: 1280, Height: 1280},
+		{ID: "goblin_fields", Name: "Goblin Fields", MinLevel: 5, MaxLevel: 10, PvP: true, Music: "danger", HealthScale: 1.2, DamageScale: 1.2, XPScale: 1.5, LevelScaling: true, X: 0, Y: 2560, Width: 3840, Height: 1280},
+	}
+
+	// Waypoints (Fast Travel)
+	waypoints := []world.WaypointDef{
+		{ID: "town_square", Name: "Town Square", X: 320, Y: 256},
+		{ID: "lakeside", Name: "Lakeside", X: 1216, Y: 1280},
+		{ID: "eastern_outpost", Name: "Eastern Outpost", X: 3200, Y: 640},
+		{ID: "goblin_camp", Name: "Goblin Camp", X: 2048, Y: 3200},
+	}
+
+	// Triggers (Tutorial popups, traps)
+	triggers := []world.TriggerDef{
+		{ID: "tutorial_welcome", X: 0, Y: 0, Width: 448, Height: 448, Action: "message", Message: "Welcome to Henry! Right-click to walk, pick things up or follow.", Once: true},
+		{ID: "thorn_patch", X: 704, Y: 512, Width: 128, Height: 64, Action: "damage", Damage: 10, Message: "Ouch! Thorns."},
+		{ID: "house_door", X: 512, Y: 192, Width: 32, Height: 32, Action: "house", TargetX: 512, TargetY: 256},
+		{ID: "goblin_fields_edge", X: 0, Y: 2496, Width: 3840, Height: 64, Action: "cutscene", Cutscene: "goblin_fields_vista"},
+	}
+
 	output := MapData{
 		Level:  0,
 		Width:  width,
@@ -175,10 +205,25 @@ func main() {
 			Ground:  ground,
 			Objects: objects,
 		},
-		Spawners: spawners,
+		Spawners:  spawners,
+		Zones:     zones,
+		Waypoints: waypoints,
+		Triggers:  triggers,
 	}
 
 	file, _ := json.MarshalIndent(output, "", "  ")
 	os.WriteFile("data/maps/level_0.json", file, 0644)
 	fmt.Println("Generated level_0.json")
 }
+
+// pathTile returns the tile a path leaves on ground: planks over water (kept where two
+// paths cross), the path's own surface elsewhere
+func pathTile(ground int, path world.TileType) int {
+	switch world.TileType(ground) {
+	case world.TileWaterDeep, world.TileBridge:
+		return int(world.TileBridge)
+	case world.TileWaterShallow, world.TileDock:
+		return int(world.TileDock)
+	}
+	return int(path)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"henry/pkg/shared/network"
+)
+
+// protodoc writes a JSON description of the wire protocol: packet IDs, directions and
+// payload types, and the fields of every type they carry. Diff two outputs to see what
+// changed between client and server builds.
+func main() {
+	out := flag.String("o", "", "Write to this file instead of stdout")
+	flag.Parse()
+
+	data, err := json.MarshalIndent(network.DescribeProtocol(), "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	data = append(data, '\n')
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
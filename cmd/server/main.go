@@ -1,10 +1,94 @@
 package main
 
 import (
+	"flag"
+	"log"
+	"os"
+	"runtime/pprof"
+	"time"
+
+	"henry/pkg/network"
 	"henry/pkg/server"
+	"henry/pkg/shared/config"
+	"henry/pkg/storage"
 )
 
 func main() {
-	gameServer := server.NewGameServer()
+	persistNPCs := flag.Bool("persist-npcs", false, "Checkpoint NPC state and resume it after a restart")
+	maintenance := flag.Bool("maintenance", false, "Start in maintenance mode (only admins can log in)")
+	motd := flag.String("motd", "", "Message of the day shown to players after login")
+	afkKickAbove := flag.Int("afk-kick-above", 0, "Disconnect AFK players while more than this many are online (0 = never)")
+	maxPlayers := flag.Int("max-players", config.MaxPlayers, "Players online at once before logins are queued (0 = unlimited)")
+	maxConnsPerIP := flag.Int("max-conns-per-ip", config.MaxConnectionsPerIP, "Simultaneous connections allowed from one address (0 = unlimited)")
+	trustedProxies := flag.String("trusted-proxies", "", "Comma separated IPs/CIDRs of reverse proxies whose X-Forwarded-For is honored on the WebSocket endpoint")
+	mapDir := flag.String("maps", config.MapDir, "Directory with level_*.json maps (a default map is generated if level 0 is missing)")
+	importRealm := flag.String("import", "", "Replace the data folder with a realm export (from the \"export\" console command) before starting; the old one is kept as data-before-import-<time>")
+	telemetry := flag.Float64("telemetry", 0, "Collect gameplay telemetry and export it to data/telemetry every N seconds (0 = off)")
+
+	headlessSim := flag.Int("headless-sim", 0, "Run N ticks on a generated map with scripted bots, print stats and exit (no network, no data/ needed)")
+	simSize := flag.Int("sim-size", 128, "Headless sim: map width/height in tiles")
+	simSpawners := flag.Int("sim-spawners", 200, "Headless sim: NPC spawners on the generated map")
+	simBots := flag.Int("sim-bots", 20, "Headless sim: scripted player bots")
+	simSeed := flag.Int64("sim-seed", 1, "Headless sim: random seed for the map and bots")
+	simVerbose := flag.Bool("sim-verbose", false, "Headless sim: keep per-event server logs")
+	cpuProfile := flag.String("cpuprofile", "", "Headless sim: write a CPU profile to this file")
+	flag.Parse()
+
+	if *headlessSim > 0 {
+		if *cpuProfile != "" {
+			f, err := os.Create(*cpuProfile)
+			if err != nil {
+				log.Fatalf("Failed to create CPU profile: %v", err)
+			}
+			pprof.StartCPUProfile(f)
+		}
+		stats := server.RunHeadlessSim(server.SimConfig{
+			Ticks:    *headlessSim,
+			MapSize:  *simSize,
+			Spawners: *simSpawners,
+			Bots:     *simBots,
+			Seed:     *simSeed,
+			Verbose:  *simVerbose,
+		})
+		if *cpuProfile != "" {
+			pprof.StopCPUProfile()
+		}
+		if stats.Panics > 0 {
+			os.Exit(1) // Fail CI smoke runs
+		}
+		return
+	}
+
+	if *importRealm != "" {
+		previous, n, err := storage.ImportRealm(*importRealm, time.Now())
+		if err != nil {
+			log.Fatalf("Import failed, data folder unchanged: %v", err)
+		}
+		log.Printf("Imported %d file(s) from %s", n, *importRealm)
+		if previous != "" {
+			log.Printf("The previous data folder was kept as %s", previous)
+		}
+	}
+
+	if n, err := storage.MigratePasswords(); err != nil {
+		log.Printf("Password migration: %v", err)
+	} else if n > 0 {
+		log.Printf("Hashed the plaintext passwords of %d player save(s)", n)
+	}
+
+	proxies, err := network.ParseTrustedProxies(*trustedProxies)
+	if err != nil {
+		log.Fatalf("Bad -trusted-proxies: %v", err)
+	}
+
+	gameServer := server.NewGameServer(*mapDir)
+	gameServer.PersistNPCs = *persistNPCs
+	gameServer.Maintenance = *maintenance
+	gameServer.MOTD = *motd
+	gameServer.AFKKickAbove = *afkKickAbove
+	gameServer.MaxPlayers = *maxPlayers
+	gameServer.MaxConnsPerIP = *maxConnsPerIP
+	gameServer.TrustedProxies = proxies
+	gameServer.TelemetrySystem.Interval = *telemetry
 	gameServer.Run(":8080")
 }
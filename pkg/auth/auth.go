@@ -0,0 +1,104 @@
+// Package auth hashes and checks account passwords and throttles repeated failed logins.
+// Passwords are only ever stored as argon2id hashes in the PHC string format
+// ($argon2id$v=19$m=...,t=...,p=...$salt$hash), so the cost can be raised later without
+// breaking existing accounts.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Hash cost (OWASP's argon2id minimum: 19 MiB, 2 passes, 1 thread)
+const (
+	hashMemory  = 19 * 1024 // KiB
+	hashTime    = 2
+	hashThreads = 1
+	saltLen     = 16
+	keyLen      = 32
+)
+
+const hashPrefix = "$argon2id$"
+
+var ErrBadHash = errors.New("malformed password hash")
+
+// Hash returns the encoded argon2id hash of a password, with a fresh random salt
+func Hash(password string) (string, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, hashTime, hashMemory, hashThreads, keyLen)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", hashPrefix, argon2.Version, hashMemory, hashTime, hashThreads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify reports whether password matches an encoded hash, using the cost the hash was
+// made with
+func Verify(encoded, password string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || !IsHash(encoded) {
+		return false, ErrBadHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, ErrBadHash
+	}
+	var memory, passes uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &passes, &threads); err != nil || passes == 0 || threads == 0 {
+		return false, ErrBadHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, ErrBadHash
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(want) == 0 {
+		return false, ErrBadHash
+	}
+	got := argon2.IDKey([]byte(password), salt, passes, memory, threads, uint32(len(want)))
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+// IsHash reports whether a stored value is a hash from Hash (and not, say, a password
+// saved in plain text by an older server)
+func IsHash(s string) bool {
+	return strings.HasPrefix(s, hashPrefix)
+}
+
+// dummyHash is checked against when an account doesn't exist, so a login for an unknown
+// name takes as long as a wrong password
+var (
+	dummyHash     string
+	dummyHashOnce sync.Once
+)
+
+// VerifyMissing burns the time of one Verify. Call it when there is no account to check.
+func VerifyMissing(password string) {
+	dummyHashOnce.Do(func() { dummyHash, _ = Hash("no such account") })
+	Verify(dummyHash, password)
+}
+
+// ValidUsername reports whether a name can be an account: 1-32 letters, digits, '_', '-'
+// or '.', not starting with '.'. Names are file names under data/players, so this also
+// keeps them from reaching outside it.
+func ValidUsername(name string) bool {
+	if name == "" || len(name) > 32 || name[0] == '.' {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHashAndVerify(t *testing.T) {
+	hash, err := Hash("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsHash(hash) || strings.Contains(hash, "hunter2") {
+		t.Fatalf("hash = %q", hash)
+	}
+	if again, _ := Hash("hunter2"); again == hash {
+		t.Error("two hashes of one password are identical (no salt?)")
+	}
+
+	if ok, err := Verify(hash, "hunter2"); !ok || err != nil {
+		t.Errorf("right password: %v, %v", ok, err)
+	}
+	if ok, err := Verify(hash, "hunter3"); ok || err != nil {
+		t.Errorf("wrong password: %v, %v", ok, err)
+	}
+	if _, err := Verify("hunter2", "hunter2"); err != ErrBadHash {
+		t.Errorf("plaintext as hash: err = %v, want ErrBadHash", err)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	th := NewThrottle(3, time.Minute)
+	for i := 0; i < 3; i++ {
+		if ok, _ := th.Allow("bob", now); !ok {
+			t.Fatalf("refused after %d failure(s)", i)
+		}
+		th.Fail("bob", now.Add(time.Duration(i)*10*time.Second))
+	}
+	now = now.Add(30 * time.Second)
+	if ok, wait := th.Allow("bob", now); ok || wait != 30*time.Second {
+		t.Errorf("after 3 failures: allowed %v, wait %v; want refused for 30s", ok, wait)
+	}
+	if ok, _ := th.Allow("alice", now); !ok {
+		t.Error("other keys are throttled too")
+	}
+	if ok, _ := th.Allow("bob", now.Add(30*time.Second)); !ok {
+		t.Error("still refused once the first failure aged out")
+	}
+
+	th.Succeed("bob")
+	if ok, _ := th.Allow("bob", now); !ok {
+		t.Error("still refused after a successful login")
+	}
+}
+
+func TestValidUsername(t *testing.T) {
+	for name, want := range map[string]bool{
+		"admin": true, "Sir_Henry-2": true, "a.b": true,
+		"": false, "../admin": false, "a/b": false, `a\b`: false, ".hidden": false, "sp ace": false,
+		strings.Repeat("x", 33): false,
+	} {
+		if got := ValidUsername(name); got != want {
+			t.Errorf("ValidUsername(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
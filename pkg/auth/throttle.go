@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// Throttle refuses logins for a key (an account name or an address) after too many
+// failures within a window. Safe for use from many connections at once.
+type Throttle struct {
+	MaxFailures int           // Failures allowed per window (0 = never throttle)
+	Window      time.Duration // How long a failure counts
+
+	mu       sync.Mutex
+	failures map[string][]time.Time // Oldest first
+}
+
+func NewThrottle(maxFailures int, window time.Duration) *Throttle {
+	return &Throttle{MaxFailures: maxFailures, Window: window, failures: make(map[string][]time.Time)}
+}
+
+// Allow reports whether key may try to log in, or how long it has to wait
+func (t *Throttle) Allow(key string, now time.Time) (bool, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	recent := t.recent(key, now)
+	if t.MaxFailures <= 0 || len(recent) < t.MaxFailures {
+		return true, 0
+	}
+	// Allowed again once enough of the failures have aged out
+	return false, recent[len(recent)-t.MaxFailures].Add(t.Window).Sub(now)
+}
+
+// Fail records a failed login for key
+func (t *Throttle) Fail(key string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failures[key] = append(t.recent(key, now), now)
+	if len(t.failures) > 1000 {
+		for k := range t.failures {
+			t.recent(k, now)
+		}
+	}
+}
+
+// Succeed forgets the failures of key
+func (t *Throttle) Succeed(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.failures, key)
+}
+
+// recent drops the failures of key older than the window and returns the rest
+func (t *Throttle) recent(key string, now time.Time) []time.Time {
+	times := t.failures[key]
+	i := 0
+	for i < len(times) && now.Sub(times[i]) >= t.Window {
+		i++
+	}
+	if i == len(times) {
+		delete(t.failures, key)
+		return nil
+	}
+	times = times[i:]
+	t.failures[key] = times
+	return times
+}
@@ -0,0 +1,65 @@
+package behavior
+
+// Leaves the server's NPC AI knows. Conditions succeed or fail right away; actions
+// report Running while they're still moving the NPC.
+const (
+	// Conditions
+	Fleeing    = "fleeing"     // Running for help right now
+	Returning  = "returning"   // Walking back to the spawn point right now
+	Leashed    = "leashed"     // Farther from the spawn point than the leash allows
+	HasTarget  = "has_target"  // Has a living target in reach on its level (drops a lost one)
+	Hurt       = "hurt"        // Health below the character's FleeThreshold
+	HasFled    = "has_fled"    // Already ran for help this fight
+	InRange    = "in_range"    // Target within weapon range (and in sight, for bows)
+	TooClose   = "too_close"   // Target within half of the weapon range
+	FindTarget = "find_target" // Aggressive and picked a hostile in aggro range as its target
+	OnPost     = "on_post"     // Scheduled to stand guard or keep shop
+	OnPatrol   = "on_patrol"   // Has a patrol route to walk
+
+	// Actions
+	Flee     = "flee"      // Run to the nearest ally (or home) and call for help
+	Return   = "return"    // Drop the target and walk home
+	Attack   = "attack"    // Swing or shoot at the target
+	Chase    = "chase"     // Path towards the target
+	Kite     = "kite"      // Back away from the target, shooting while in range
+	HoldPost = "hold_post" // Stand at the spawn point
+	Patrol   = "patrol"    // Walk the patrol route, point after point, in a loop
+	Wander   = "wander"    // Idle and stroll around at random
+	Idle     = "idle"      // Stand still
+)
+
+// Stock NPC trees. Characters without a Behavior get Default.
+var (
+	// Default fights in melee or at range, runs for help once when hurt
+	Default = npc(Selector(fleeOnce, attackOrChase))
+
+	// Archer keeps its distance: it backs off from anyone who gets close
+	Archer = npc(Selector(fleeOnce, Sequence(Leaf(TooClose), Leaf(Kite)), attackOrChase))
+
+	// Coward runs for help when hurt and then stays out of the fight until it has healed
+	Coward = npc(Selector(
+		Sequence(Leaf(Hurt), Selector(Sequence(Invert(Leaf(HasFled)), Leaf(Flee)), Leaf(Idle))),
+		attackOrChase,
+	))
+)
+
+var (
+	fleeOnce      = Sequence(Leaf(Hurt), Invert(Leaf(HasFled)), Leaf(Flee))
+	attackOrChase = Selector(Sequence(Leaf(InRange), Leaf(Attack)), Leaf(Chase))
+)
+
+// npc wraps how an NPC fights (run while it has a target) in what every NPC does:
+// finish running for help or going home, respect the leash, look for trouble, keep to
+// its schedule and otherwise walk its patrol or wander
+func npc(fight Node) Node {
+	return Selector(
+		Sequence(Leaf(Fleeing), Leaf(Flee)),
+		Sequence(Leaf(Returning), Leaf(Return)),
+		Sequence(Leaf(Leashed), Leaf(Return)),
+		Sequence(Leaf(HasTarget), fight),
+		Leaf(FindTarget),
+		Sequence(Leaf(OnPost), Leaf(HoldPost)),
+		Sequence(Leaf(OnPatrol), Leaf(Patrol)),
+		Leaf(Wander),
+	)
+}
@@ -0,0 +1,88 @@
+package behavior
+
+// Status is what a node reports to its parent after a tick
+type Status int
+
+const (
+	Failure Status = iota
+	Success
+	Running // Still busy (walking somewhere); counts as "not failed" for composites
+)
+
+type Kind int
+
+const (
+	KindLeaf     Kind = iota
+	KindSelector      // First child that doesn't fail
+	KindSequence      // Every child in turn until one doesn't succeed
+	KindInvert        // Success and Failure swapped
+	KindSucceed       // Always Success
+)
+
+// Node is a behavior tree node. Composites and decorators are built in; leaves are only
+// named, and whoever ticks the tree runs them (the server's AISystem for NPCs). Trees
+// are plain values, so they can be authored next to the characters that use them.
+//
+// Trees are ticked from the root every frame and keep no memory of their own: anything
+// a leaf must remember between frames (a path, a flee destination) lives in the agent.
+type Node struct {
+	Kind     Kind
+	Leaf     string // KindLeaf: the condition or action to run
+	Children []Node
+}
+
+func Selector(children ...Node) Node { return Node{Kind: KindSelector, Children: children} }
+func Sequence(children ...Node) Node { return Node{Kind: KindSequence, Children: children} }
+func Invert(child Node) Node         { return Node{Kind: KindInvert, Children: []Node{child}} }
+func Succeed(child Node) Node        { return Node{Kind: KindSucceed, Children: []Node{child}} }
+func Leaf(name string) Node          { return Node{Kind: KindLeaf, Leaf: name} }
+
+// Empty reports whether the node is the zero Node (no tree configured)
+func (n Node) Empty() bool {
+	return n.Kind == KindLeaf && n.Leaf == ""
+}
+
+// Tick runs the tree once; run performs a named leaf
+func (n Node) Tick(run func(leaf string) Status) Status {
+	switch n.Kind {
+	case KindSelector:
+		for _, child := range n.Children {
+			if status := child.Tick(run); status != Failure {
+				return status
+			}
+		}
+		return Failure
+	case KindSequence:
+		for _, child := range n.Children {
+			if status := child.Tick(run); status != Success {
+				return status
+			}
+		}
+		return Success
+	case KindInvert:
+		switch n.Children[0].Tick(run) {
+		case Success:
+			return Failure
+		case Failure:
+			return Success
+		}
+		return Running
+	case KindSucceed:
+		n.Children[0].Tick(run)
+		return Success
+	default:
+		return run(n.Leaf)
+	}
+}
+
+// Leaves lists the leaf names used in the tree, in order, with repeats
+func (n Node) Leaves() []string {
+	if n.Kind == KindLeaf {
+		return []string{n.Leaf}
+	}
+	var leaves []string
+	for _, child := range n.Children {
+		leaves = append(leaves, child.Leaves()...)
+	}
+	return leaves
+}
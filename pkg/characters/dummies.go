@@ -0,0 +1,22 @@
+package characters
+
+import (
+	"henry/pkg/shared/components"
+	"image/color"
+)
+
+func init() {
+	// Training Dummy (Brown) - Invulnerable, reports DPS
+	Register(CharacterDefinition{
+		ID:           "training_dummy",
+		Name:         "Training Dummy",
+		Description:  "A sturdy straw target. Hit it to measure your damage output.",
+		SpriteWidth:  32,
+		SpriteHeight: 32,
+		Color:        color.RGBA{R: 160, G: 110, B: 60, A: 255}, // Brown
+		AIType:       "dummy",
+		Faction:      components.FactionNeutral,
+		MaxHealth:    1000,
+		Speed:        0,
+	})
+}
@@ -1,6 +1,16 @@
 package characters
 
-import "image/color"
+import (
+	"henry/pkg/behavior"
+	"henry/pkg/shared/components"
+	"image/color"
+)
+
+// Guards patrol the streets by day and hold their posts at night
+var guardSchedule = []components.ScheduleEntry{
+	{Start: 6, End: 20, Activity: "patrol"},
+	{Start: 20, End: 6, Activity: "post"},
+}
 
 func init() {
 	// Melee Guard (Yellow)
@@ -15,25 +25,45 @@ func init() {
 		AIType:       "guard",
 		Faction:      1,    // Guards
 		IsAggressive: true, // Aggressive to monsters/enemies, but logic handles factions
+		HelpRadius:   300,
+		AggroRange:   250,
+		Schedule:     guardSchedule,
+		Dialogue:     "city_guard",
 		MaxHealth:    50,
 		Speed:        1.0,
+		Level:        3,
+		XP:           10,
 		WeaponID:     "sword_starter",
+		Drops: []components.LootEntry{
+			{ItemID: "potion_health_small", Quantity: 1, Chance: 0.25},
+		},
 	})
 
 	// Ranged Guard (Blue)
 	Register(CharacterDefinition{
-		ID:           "guard_ranged",
-		Name:         "City Archer",
-		Description:  "A sharpshooter guard armed with a bow.",
-		SpriteID:     "guard",
-		SpriteWidth:  32,
-		SpriteHeight: 32,
-		Color:        color.RGBA{R: 0, G: 0, B: 255, A: 255}, // Blue
-		AIType:       "guard",
-		Faction:      1, // Guards
-		IsAggressive: true,
-		MaxHealth:    40,
-		Speed:        1.0,
-		WeaponID:     "bow_starter",
+		ID:            "guard_ranged",
+		Name:          "City Archer",
+		Description:   "A sharpshooter guard armed with a bow.",
+		SpriteID:      "guard",
+		SpriteWidth:   32,
+		SpriteHeight:  32,
+		Color:         color.RGBA{R: 0, G: 0, B: 255, A: 255}, // Blue
+		AIType:        "guard",
+		Faction:       1, // Guards
+		IsAggressive:  true,
+		FleeThreshold: 0.3, // Archers retreat to allies when hurt
+		Behavior:      behavior.Archer,
+		HelpRadius:    300,
+		AggroRange:    250,
+		Schedule:      guardSchedule,
+		Dialogue:      "city_guard",
+		MaxHealth:     40,
+		Speed:         1.0,
+		Level:         3,
+		XP:            10,
+		WeaponID:      "bow_starter",
+		Drops: []components.LootEntry{
+			{ItemID: "potion_health_small", Quantity: 1, Chance: 0.25},
+		},
 	})
 }
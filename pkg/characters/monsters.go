@@ -0,0 +1,164 @@
+package characters
+
+import (
+	"henry/pkg/behavior"
+	"henry/pkg/shared/components"
+	"image/color"
+)
+
+func init() {
+	// Goblin Raider (Green) - Invasion fodder
+	Register(CharacterDefinition{
+		ID:            "goblin_raider",
+		Name:          "Goblin Raider",
+		Description:   "A scrappy goblin looking for trouble.",
+		SpriteWidth:   32,
+		SpriteHeight:  32,
+		Color:         color.RGBA{R: 60, G: 160, B: 60, A: 255}, // Green
+		AIType:        "monster",
+		Faction:       components.FactionMonsters,
+		IsAggressive:  true,
+		FleeThreshold: 0.25, // Runs to the pack when hurt and lets them finish the fight
+		Behavior:      behavior.Coward,
+		HelpRadius:    250,
+		AggroRange:    300,
+		MaxHealth:     40,
+		Speed:         1.0,
+		Level:         5,
+		XP:            20,
+		WeaponID:      "sword_starter",
+		Drops: []components.LootEntry{
+			{ItemID: "potion_health_small", Quantity: 1, Chance: 0.1},
+		},
+	})
+
+	// Goblin Warlord (Dark Red) - Rare world boss
+	Register(CharacterDefinition{
+		ID:           "goblin_warlord",
+		Intro:        "goblin_warlord_intro",
+		Name:         "Goblin Warlord",
+		Description:  "A hulking goblin chieftain. Rarely seen, never alone for long.",
+		SpriteWidth:  48,
+		SpriteHeight: 48,
+		Color:        color.RGBA{R: 140, G: 20, B: 20, A: 255}, // Dark Red
+		AIType:       "monster",
+		Faction:      components.FactionMonsters,
+		IsAggressive: true,
+		HelpRadius:   400,
+		AggroRange:   350,
+		MaxHealth:    400,
+		Speed:        1.2,
+		Level:        8,
+		XP:           150,
+		Attributes:   components.Attributes{Str: 10},
+		WeaponID:     "sword_starter",
+		Loot: []components.LootEntry{
+			{ItemID: "bow_starter", Quantity: 1, Chance: 0.5},
+			{ItemID: "potion_health_small", Quantity: 3, Chance: 1.0},
+			{ItemID: "elixir_might", Quantity: 1, Chance: 0.5},
+			{ItemID: "amulet_sage", Quantity: 1, Chance: 0.25},
+			{ItemID: "gloves_swift", Quantity: 1, Chance: 0.25},
+			{ItemID: "build_banner", Quantity: 1, Chance: 0.5},
+			{ItemID: "build_wall_wood", Quantity: 5, Chance: 1.0},
+			{ItemID: "seed_carrot", Quantity: 3, Chance: 0.5},
+		},
+	})
+
+	// Goblin Behemoth (Purple) - Zone world boss, scales with its attackers
+	Register(CharacterDefinition{
+		ID:           "goblin_behemoth",
+		Intro:        "goblin_behemoth_intro",
+		Name:         "Goblin Behemoth",
+		Description:  "A towering brute that takes a whole warband to bring down.",
+		SpriteWidth:  64,
+		SpriteHeight: 64,
+		Color:        color.RGBA{R: 90, G: 30, B: 120, A: 255}, // Purple
+		AIType:       "monster",
+		Faction:      components.FactionMonsters,
+		IsAggressive: true,
+		HelpRadius:   400,
+		AggroRange:   400,
+		MaxHealth:    1200,
+		Speed:        0.9,
+		Level:        10,
+		XP:           400,
+		WeaponID:     "sword_starter",
+	})
+
+	// Wildlife and the restless dead, kept topped up by the map's spawn regions
+
+	// Slime (Teal) - Slow lakeside pest
+	Register(CharacterDefinition{
+		ID:             "slime",
+		Name:           "Slime",
+		Description:    "A quivering blob that oozes out of the lake shallows.",
+		SpriteWidth:    24,
+		SpriteHeight:   24,
+		Color:          color.RGBA{R: 60, G: 200, B: 180, A: 255}, // Teal
+		AIType:         "monster",
+		Faction:        components.FactionMonsters,
+		IsAggressive:   true,
+		AggroRange:     150,
+		MaxHealth:      25,
+		Speed:          0.6,
+		Level:          2,
+		XP:             8,
+		WeaponID:       "goo_slime",
+		RespawnSeconds: 20,
+		Drops: []components.LootEntry{
+			{ItemID: "slime_gel", Quantity: 1, Chance: 0.6},
+			{ItemID: "potion_health_small", Quantity: 1, Chance: 0.05},
+		},
+	})
+
+	// Wolf (Grey) - Hunts in packs, keen nose
+	Register(CharacterDefinition{
+		ID:             "wolf",
+		Name:           "Grey Wolf",
+		Description:    "Lean and fast. Where there's one, the pack isn't far.",
+		SpriteWidth:    32,
+		SpriteHeight:   24,
+		Color:          color.RGBA{R: 130, G: 130, B: 140, A: 255}, // Grey
+		AIType:         "monster",
+		Faction:        components.FactionMonsters,
+		IsAggressive:   true,
+		HelpRadius:     300,
+		AggroRange:     300,
+		MaxHealth:      45,
+		Speed:          1.4,
+		Level:          4,
+		XP:             15,
+		WeaponID:       "fangs_wolf",
+		RespawnSeconds: 45,
+		Drops: []components.LootEntry{
+			{ItemID: "wolf_pelt", Quantity: 1, Chance: 0.5},
+		},
+	})
+
+	// Skeleton (Bone White) - Tough and relentless
+	Register(CharacterDefinition{
+		ID:             "skeleton",
+		Name:           "Skeleton",
+		Description:    "Old bones in older armor, still guarding the fields.",
+		SpriteWidth:    32,
+		SpriteHeight:   32,
+		Color:          color.RGBA{R: 225, G: 220, B: 200, A: 255}, // Bone White
+		AIType:         "monster",
+		Faction:        components.FactionMonsters,
+		IsAggressive:   true,
+		HelpRadius:     200,
+		AggroRange:     250,
+		MaxHealth:      70,
+		Speed:          0.8,
+		Level:          6,
+		XP:             30,
+		Attributes:     components.Attributes{Str: 3},
+		WeaponID:       "sword_starter",
+		RespawnSeconds: 60,
+		Drops: []components.LootEntry{
+			{ItemID: "bone_fragment", Quantity: 2, Chance: 0.7},
+			{ItemID: "sword_starter", Quantity: 1, Chance: 0.05},
+			{ItemID: "potion_health_small", Quantity: 1, Chance: 0.15},
+		},
+	})
+}
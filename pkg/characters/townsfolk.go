@@ -0,0 +1,88 @@
+package characters
+
+import (
+	"henry/pkg/shared/components"
+	"image/color"
+)
+
+// Shopkeepers trade by day and shut their shops at night
+var shopkeeperSchedule = []components.ScheduleEntry{
+	{Start: 6, End: 20, Activity: "trade"},
+}
+
+func init() {
+	// Arena Master (Red) - Stands in town, queues players for the arena
+	Register(CharacterDefinition{
+		ID:           "arena_master",
+		Name:         "Arena Master",
+		Description:  "Signs up challengers for the arena. Right-click to join the queue.",
+		SpriteID:     "guard",
+		SpriteWidth:  32,
+		SpriteHeight: 32,
+		Color:        color.RGBA{R: 200, G: 30, B: 30, A: 255}, // Red
+		AIType:       "static",
+		Faction:      components.FactionPlayer, // Players can't hit it outside PvP zones
+		MaxHealth:    500,
+		Speed:        0,
+		Markers:      components.MarkerArena,
+	})
+
+	// Housing Steward (Green) - Stands by the house door, sells deeds and lets guests in
+	Register(CharacterDefinition{
+		ID:           "housing_steward",
+		Name:         "Housing Steward",
+		Description:  "Sells house deeds and shows guests to the houses they're invited to. Right-click for options, or talk to them to learn more.",
+		SpriteID:     "guard",
+		SpriteWidth:  32,
+		SpriteHeight: 32,
+		Color:        color.RGBA{R: 40, G: 140, B: 60, A: 255}, // Green
+		AIType:       "static",
+		Faction:      components.FactionPlayer,
+		MaxHealth:    500,
+		Speed:        0,
+		Markers:      components.MarkerHouse,
+		Dialogue:     "housing_steward",
+	})
+
+	// General Merchant (Gold) - Sells consumables and starter weapons, buys anything of value
+	Register(CharacterDefinition{
+		ID:           "merchant",
+		Name:         "General Merchant",
+		Description:  "Sells potions, elixirs and starter weapons, and buys anything of value. Talk to them to trade.",
+		SpriteID:     "guard",
+		SpriteWidth:  32,
+		SpriteHeight: 32,
+		Color:        color.RGBA{R: 210, G: 170, B: 40, A: 255}, // Gold
+		AIType:       "static",
+		Faction:      components.FactionPlayer,
+		MaxHealth:    500,
+		Speed:        0,
+		Markers:      components.MarkerVendor,
+		Schedule:     shopkeeperSchedule,
+		Dialogue:     "merchant",
+		Shop: []components.ShopItem{
+			{ItemID: "potion_health_small"},
+			{ItemID: "elixir_might"},
+			{ItemID: "elixir_swiftness"},
+			{ItemID: "sword_starter"},
+			{ItemID: "bow_starter"},
+		},
+	})
+
+	// Festival Vendor (Orange) - Only spawned by seasonal events, which give it its stock
+	Register(CharacterDefinition{
+		ID:           "festival_vendor",
+		Name:         "Festival Vendor",
+		Description:  "Sells seasonal goods while a festival is on. Talk to them to trade.",
+		SpriteID:     "guard",
+		SpriteWidth:  32,
+		SpriteHeight: 32,
+		Color:        color.RGBA{R: 240, G: 130, B: 30, A: 255}, // Orange
+		AIType:       "static",
+		Faction:      components.FactionPlayer,
+		MaxHealth:    500,
+		Speed:        0,
+		Markers:      components.MarkerVendor,
+		Dialogue:     "festival_vendor",
+	})
+}
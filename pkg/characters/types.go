@@ -1,6 +1,8 @@
 package characters
 
 import (
+	"henry/pkg/behavior"
+	"henry/pkg/shared/components"
 	"image/color"
 )
 
@@ -18,16 +20,48 @@ type CharacterDefinition struct {
 	Color        color.RGBA
 
 	// AI Configuration
-	AIType       string // "wander", "guard", etc.
-	Faction      int    // 0: Player, 1: Guards, 2: Monsters
-	IsAggressive bool
+	AIType        string // "wander", "guard", etc.
+	Faction       int    // 0: Player, 1: Guards, 2: Monsters
+	IsAggressive  bool
+	FleeThreshold float64 // Health fraction to flee at (0 = fight to death)
+	HelpRadius    float64 // Radius (px) to call same-faction allies for help
+	AggroRange    float64 // Radius (px) to engage hostile factions (IsAggressive only)
+	Schedule      []components.ScheduleEntry
+	Behavior      behavior.Node // How it acts and fights (zero = behavior.Default, see pkg/behavior)
+	Patrol        [][2]float64  // Route walked in a loop instead of wandering, relative to the spawn point (px)
+
+	// Seconds dead before its spawner brings it back (0 = config.NPCRespawnSeconds)
+	RespawnSeconds float64
 
 	// Stats
 	MaxHealth float64
 	Speed     float64
+	Level     int // Base level (0 = 1); zone level scaling works relative to it
+	XP        int // Combat XP for the player who kills it
+
+	// Primary attributes (gear adds to them, see systems.Stats)
+	Attributes components.Attributes
 
 	// Starting Equipment
 	WeaponID string // e.g. "sword_starter"
+
+	// Overhead Markers (components.Marker* flags)
+	Markers int
+
+	// Boss/Elite Loot (rolled need/greed among attackers)
+	Loot []components.LootEntry
+
+	// Common Drops (left on the ground for the killer; gold comes from the economy config)
+	Drops []components.LootEntry
+
+	// Cutscene (pkg/cutscenes) played to each player who first fights it
+	Intro string
+
+	// Dialogue tree (data/dialogues) players open by interacting with it
+	Dialogue string
+
+	// Vendor stock (players reach it through a "shop" dialogue action)
+	Shop []components.ShopItem
 }
 
 var Registry = make(map[string]CharacterDefinition)
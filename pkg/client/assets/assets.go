@@ -7,9 +7,11 @@ import (
 	"image"
 	_ "image/png"
 	"log"
+	"math"
 	"path/filepath"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 //go:embed images/*.png characters projectiles/*.png
@@ -38,6 +40,7 @@ func Load() {
 	// Load Projectiles
 	loadHasIcon("fireball", "images/fireball.png")
 	loadHasIcon("arrow", "projectiles/arrow.png")
+	images["slash"] = slashImage(40)
 
 	// Load Player Character
 	if err := LoadCharacter("player", "characters/player/metadata.json"); err != nil {
@@ -135,6 +138,19 @@ func loadHasIcon(name, path string) {
 	log.Printf("Loaded asset %s (%dx%d)", path, w, h)
 }
 
+// slashImage draws the melee swing (there is no art for it): a pale crescent bulging to
+// the right, the way components.TextureSlash faces
+func slashImage(size int) *ebiten.Image {
+	img := ebiten.NewImage(size, size)
+	c := float32(size) / 2
+	var arc vector.Path
+	arc.Arc(c-c/3, c, c*0.9, -math.Pi/3, math.Pi/3, vector.Clockwise)
+	draw := &vector.DrawPathOptions{AntiAlias: true}
+	draw.ColorScale.Scale(0.85, 0.92, 1, 0.9)
+	vector.StrokePath(img, &arc, &vector.StrokeOptions{Width: float32(size) / 8, LineCap: vector.LineCapRound}, draw)
+	return img
+}
+
 func GetImage(name string) *ebiten.Image {
 	return images[name]
 }
@@ -146,6 +162,12 @@ func GetCharacterSprite(name, direction string) *ebiten.Image {
 	return nil
 }
 
+// HasCharacterAnimation reports whether a character has frames for an animation
+func HasCharacterAnimation(charName, animName string) bool {
+	_, ok := characterAnimations[charName][animName]
+	return ok
+}
+
 func GetCharacterFrame(charName, animName, direction string, frameIndex int) *ebiten.Image {
 	if charName == "" || animName == "" || direction == "" {
 		return nil
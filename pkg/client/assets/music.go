@@ -0,0 +1,243 @@
+package assets
+
+import (
+	"encoding/binary"
+	"math"
+
+	"henry/pkg/shared/config"
+)
+
+// Music is synthesized from the note patterns below the first time a track plays, the
+// way the slash sprite is drawn rather than loaded.
+
+// MusicPlaylist is what plays in a zone: the exploration tracks in turn, and the combat
+// or boss track while hostiles are after the player
+type MusicPlaylist struct {
+	Explore []string
+	Combat  string
+	Boss    string
+}
+
+// Playlists by zone music ID (world.Zone.Music); "" is the wilderness
+var playlists = map[string]MusicPlaylist{
+	"":       {Explore: []string{"explore_meadow", "explore_wander"}, Combat: "combat", Boss: "boss"},
+	"town":   {Explore: []string{"town_square", "town_evening"}, Combat: "combat", Boss: "boss"},
+	"lake":   {Explore: []string{"lake_calm", "explore_wander"}, Combat: "combat", Boss: "boss"},
+	"danger": {Explore: []string{"danger_dark"}, Combat: "combat_danger", Boss: "boss"},
+}
+
+// Playlist returns a zone's playlist, the wilderness one for zones without their own
+func Playlist(music string) MusicPlaylist {
+	if p, ok := playlists[music]; ok {
+		return p
+	}
+	return playlists[""]
+}
+
+const (
+	rest = -100 // Silence for the step
+	hold = -99  // Keep the previous note sounding
+)
+
+type waveform int
+
+const (
+	waveSine waveform = iota
+	waveTriangle
+	waveSquare
+)
+
+var (
+	scaleMajor      = []int{0, 2, 4, 5, 7, 9, 11}
+	scaleMinor      = []int{0, 2, 3, 5, 7, 8, 10}
+	scalePentatonic = []int{0, 2, 4, 7, 9}
+	scalePhrygian   = []int{0, 1, 3, 5, 7, 8, 10}
+)
+
+// song is a short loop: a lead melody in eighth notes over a bass line in quarter
+// notes, both as degrees of the scale (negative and past-the-end degrees change octave)
+type song struct {
+	tempo  float64 // Beats per minute
+	root   float64 // Hz of degree 0
+	scale  []int   // Semitones of each degree
+	lead   waveform
+	melody []int
+	bass   []int
+	drums  bool // Kick on the beat, hi-hat off it
+	repeat int  // Times the pattern plays per track (0 = 1)
+}
+
+var songs = map[string]song{
+	"explore_meadow": {
+		tempo: 96, root: 261.63, scale: scaleMajor, lead: waveTriangle, repeat: 2,
+		melody: []int{0, hold, 2, 4, 5, hold, 4, 2, 4, hold, hold, rest, 2, 3, 4, hold,
+			7, hold, 5, 4, 2, hold, 4, 5, 4, hold, hold, hold, rest, rest, rest, rest},
+		bass: []int{0, 0, 3, 3, 4, 4, 0, 0, 5, 5, 3, 3, 4, 4, 0, 0},
+	},
+	"explore_wander": {
+		tempo: 84, root: 293.66, scale: scalePentatonic, lead: waveSine, repeat: 2,
+		melody: []int{2, hold, 1, 0, 1, hold, 2, hold, 3, hold, 2, 1, 2, hold, hold, hold,
+			4, hold, 3, 2, 1, hold, 0, hold, 1, hold, 2, 1, 0, hold, hold, hold},
+		bass: []int{0, 0, 3, 3, 0, 0, 2, 2, 3, 3, 0, 0, 2, 2, 0, 0},
+	},
+	"town_square": {
+		tempo: 112, root: 349.23, scale: scaleMajor, lead: waveSquare, repeat: 2,
+		melody: []int{0, 2, 4, 2, 0, 2, 4, hold, 5, 4, 3, 2, 1, hold, rest, rest,
+			4, 5, 6, 5, 4, 3, 2, hold, 3, 2, 1, -1, 0, hold, rest, rest},
+		bass: []int{0, 4, 0, 4, 3, 0, 4, 4, 0, 4, 0, 4, 3, 4, 0, 0},
+	},
+	"town_evening": {
+		tempo: 76, root: 220, scale: scaleMajor, lead: waveTriangle, repeat: 2,
+		melody: []int{4, hold, 3, hold, 2, hold, 0, hold, 1, hold, 2, 3, 2, hold, hold, hold,
+			4, hold, 5, hold, 4, hold, 2, hold, 1, hold, 0, 1, 0, hold, hold, hold},
+		bass: []int{0, 0, 5, 5, 3, 3, 4, 4, 0, 0, 5, 5, 3, 4, 0, 0},
+	},
+	"lake_calm": {
+		tempo: 70, root: 329.63, scale: scalePentatonic, lead: waveSine, repeat: 2,
+		melody: []int{0, hold, hold, 2, 1, hold, hold, hold, 3, hold, hold, 2, 4, hold, hold, hold,
+			3, hold, 2, hold, 1, hold, 0, hold, 1, hold, hold, hold, rest, rest, rest, rest},
+		bass: []int{0, 0, 0, 0, 3, 3, 3, 3, 2, 2, 2, 2, 0, 0, 0, 0},
+	},
+	"danger_dark": {
+		tempo: 88, root: 220, scale: scaleMinor, lead: waveTriangle, repeat: 2,
+		melody: []int{0, hold, hold, 2, 1, hold, 0, hold, -2, hold, hold, hold, rest, rest, rest, rest,
+			2, hold, 3, hold, 4, hold, 3, 2, 1, hold, hold, hold, rest, rest, rest, rest},
+		bass: []int{0, 0, 0, 0, 5, 5, 5, 5, 3, 3, 3, 3, 4, 4, 4, 4},
+	},
+	"combat": {
+		tempo: 140, root: 220, scale: scaleMinor, lead: waveSquare, drums: true, repeat: 2,
+		melody: []int{0, 0, 2, 0, 3, 0, 4, 3, 2, 2, 4, 2, 5, 4, 3, 2,
+			0, 0, 2, 0, 3, 0, 4, 5, 7, hold, 6, hold, 4, hold, rest, rest},
+		bass: []int{0, 0, 0, 0, 5, 5, 6, 6, 0, 0, 0, 0, 3, 3, 4, 4},
+	},
+	"combat_danger": {
+		tempo: 150, root: 196, scale: scalePhrygian, lead: waveSquare, drums: true, repeat: 2,
+		melody: []int{0, 1, 0, rest, 0, 1, 3, rest, 0, 1, 0, rest, 4, 3, 1, rest,
+			0, 1, 0, rest, 0, 1, 3, 4, 5, hold, 4, hold, 3, 1, 0, rest},
+		bass: []int{0, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 5, 5, 4, 4},
+	},
+	"boss": {
+		tempo: 160, root: 164.81, scale: scalePhrygian, lead: waveSquare, drums: true, repeat: 2,
+		melody: []int{7, 7, 8, 7, 5, 5, 4, 5, 7, 7, 8, 7, 10, hold, 8, hold,
+			7, 7, 8, 7, 5, 4, 3, 1, 0, hold, 1, hold, 0, hold, rest, rest},
+		bass: []int{0, 0, 0, 0, 1, 1, 1, 1, 0, 0, 0, 0, 5, 5, 4, 1},
+	},
+}
+
+var musicCache = make(map[string][]byte)
+
+// MusicTrack returns a track as 16-bit little-endian stereo PCM at config.MusicSampleRate,
+// or nil for unknown IDs. Tracks are rendered once and kept.
+func MusicTrack(id string) []byte {
+	if pcm, ok := musicCache[id]; ok {
+		return pcm
+	}
+	def, ok := songs[id]
+	if !ok {
+		return nil
+	}
+	pcm := def.render()
+	musicCache[id] = pcm
+	return pcm
+}
+
+// freq is the pitch of a scale degree
+func (s song) freq(degree int) float64 {
+	n := len(s.scale)
+	octave := int(math.Floor(float64(degree) / float64(n)))
+	semitones := octave*12 + s.scale[degree-octave*n]
+	return s.root * math.Pow(2, float64(semitones)/12)
+}
+
+func (s song) render() []byte {
+	const rate = float64(config.MusicSampleRate)
+	step := 60 / s.tempo / 2 // Seconds per eighth note
+	repeat := max(s.repeat, 1)
+	loop := int(float64(len(s.melody)) * step * rate)
+	buf := make([]float64, loop*repeat)
+
+	for r := 0; r < repeat; r++ {
+		offset := r * loop
+		for i := 0; i < len(s.melody); i++ {
+			if s.melody[i] == rest || s.melody[i] == hold {
+				continue
+			}
+			length := 1
+			for i+length < len(s.melody) && s.melody[i+length] == hold {
+				length++
+			}
+			start := offset + int(float64(i)*step*rate)
+			addNote(buf[start:], int(float64(length)*step*rate), s.freq(s.melody[i]), s.lead, 0.22)
+		}
+		for beat, degree := range s.bass {
+			start := offset + int(float64(beat)*2*step*rate)
+			addNote(buf[start:], int(2*step*rate), s.freq(degree)/2, waveTriangle, 0.2)
+			if s.drums {
+				addKick(buf[start:])
+				addHat(buf[start+int(step*rate):], beat)
+			}
+		}
+	}
+
+	pcm := make([]byte, len(buf)*4)
+	for i, v := range buf {
+		sample := int16(math.Tanh(v) * 32000) // Soft clip where voices pile up
+		binary.LittleEndian.PutUint16(pcm[i*4:], uint16(sample))
+		binary.LittleEndian.PutUint16(pcm[i*4+2:], uint16(sample))
+	}
+	return pcm
+}
+
+// addNote mixes a plucked note into buf: a quick attack, a decay and a short release
+func addNote(buf []float64, length int, freq float64, wave waveform, amp float64) {
+	const rate = float64(config.MusicSampleRate)
+	length = min(length, len(buf))
+	release := int(0.02 * rate)
+	for i := 0; i < length; i++ {
+		t := float64(i) / rate
+		env := math.Min(1, t/0.005) * (0.4 + 0.6*math.Exp(-t*4))
+		if left := length - i; left < release {
+			env *= float64(left) / float64(release)
+		}
+		phase := math.Mod(t*freq, 1)
+		var v float64
+		switch wave {
+		case waveSine:
+			v = math.Sin(2 * math.Pi * phase)
+		case waveTriangle:
+			v = 4*math.Abs(phase-0.5) - 1
+		case waveSquare:
+			v = 0.5 // Squares are loud: half amplitude
+			if phase >= 0.5 {
+				v = -0.5
+			}
+		}
+		buf[i] += v * env * amp
+	}
+}
+
+// addKick mixes a bass drum: a sine sweeping down from 120 Hz
+func addKick(buf []float64) {
+	const rate = float64(config.MusicSampleRate)
+	length := min(int(0.15*rate), len(buf))
+	phase := 0.0
+	for i := 0; i < length; i++ {
+		t := float64(i) / rate
+		phase += (50 + 70*math.Exp(-t*30)) / rate
+		buf[i] += math.Sin(2*math.Pi*phase) * math.Exp(-t*20) * 0.45
+	}
+}
+
+// addHat mixes a hi-hat: a short burst of noise (seeded so every render is the same)
+func addHat(buf []float64, seed int) {
+	const rate = float64(config.MusicSampleRate)
+	length := min(int(0.04*rate), len(buf))
+	noise := uint32(seed*2654435761 + 1)
+	for i := 0; i < length; i++ {
+		noise ^= noise << 13
+		noise ^= noise >> 17
+		noise ^= noise << 5
+		t := float64(i) / rate
+		buf[i] += (float64(noise)/math.MaxUint32*2 - 1) * math.Exp(-t*90) * 0.08
+	}
+}
@@ -7,10 +7,12 @@ import (
 	"henry/pkg/client/assets"
 	"henry/pkg/client/systems"
 	"henry/pkg/network"
+	"henry/pkg/shared/components"
 	"henry/pkg/shared/config"
 	protocol "henry/pkg/shared/network"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 const (
@@ -25,12 +27,18 @@ type Game struct {
 	UISystem     *systems.UISystem
 	InputSystem  *systems.InputSystem
 	RenderSystem *systems.RenderSystem
+	MusicSystem  *systems.MusicSystem
+	Cutscenes    *systems.CutsceneSystem
+	Prediction   *systems.PredictionSystem
 
 	// State
 	InGame   bool
 	LoggedIn bool
 	Username string
 
+	RecordPath string                // Record the session here after logging in (cmd/client -record)
+	Replay     *network.ReplayPlayer // Set when playing a recording instead of a live server
+
 	// Inputs
 	Keys map[string]ebiten.Key
 }
@@ -64,6 +72,10 @@ func NewGame() *Game {
 	g.Keys["Menu"] = ebiten.KeyEscape
 	g.Keys["Bind"] = ebiten.KeyB
 	g.Keys[config.ActionRun] = ebiten.KeyShift
+	g.Keys[config.ActionSneak] = ebiten.KeyC
+	g.Keys[config.ActionDodge] = ebiten.KeyQ
+	g.Keys[config.ActionMusic] = ebiten.KeyN
+	g.Keys[config.ActionInteract] = ebiten.KeyF
 	// MouseButtonLeft is handled separately as it's not ebiten.Key
 
 	// Initialize Systems
@@ -73,8 +85,12 @@ func NewGame() *Game {
 
 	g.UISystem.RegisterDisconnectCallback(func() {
 		g.LoggedIn = false
+		g.Replay = nil
 		g.Client.Close()
 		g.UISystem.ResetUI()
+		g.MusicSystem.Stop()
+		g.Cutscenes.Reset()
+		g.Prediction.Reset()
 		g.UISystem.SpellsWidget.UnlockedSpells = make(map[string]bool)
 	})
 
@@ -92,17 +108,23 @@ func NewGame() *Game {
 		} else {
 			var debugSettings map[string]bool
 			var openMenus map[string]bool
-			var isRunning bool // Declare isRunning
-			keys, debugSettings, openMenus, isRunning, err = g.Client.Connect("127.0.0.1:8080", user, pass)
+			var stance string
+			keys, debugSettings, openMenus, stance, err = g.Client.Connect("127.0.0.1:8080", user, pass)
 			if err != nil {
 				fmt.Printf("Login Error: %v\n", err)
 				return
 			}
 			g.LoggedIn = true
 			g.Username = user
+			g.UISystem.Username = user
 			g.UISystem.HideLogin()
 			g.UISystem.ApplyOpenMenus(openMenus)
-			g.InputSystem.SetRunning(isRunning) // Pass the persisted state
+			if g.RecordPath != "" {
+				if err := g.Client.StartRecording(g.RecordPath); err != nil {
+					fmt.Printf("Recording Error: %v\n", err)
+				}
+			}
+			g.InputSystem.SetStance(stance) // Pass the persisted state
 
 			// Apply Keys
 			if keys != nil {
@@ -113,12 +135,14 @@ func NewGame() *Game {
 				}
 			}
 
-			// Apply Debug Settings
-			if debugSettings != nil {
-				g.UISystem.DebugFlags.ShowFPS = debugSettings["ShowFPS"]
-				g.UISystem.DebugFlags.ShowInfo = debugSettings["ShowInfo"]
-				g.UISystem.DebugFlags.ShowLogs = debugSettings["ShowLogs"]
-				g.UISystem.DebugFlags.ShowLogs = debugSettings["ShowLogs"]
+			// Apply Debug Settings (and the other saved toggles)
+			g.UISystem.ApplySettings(debugSettings)
+
+			// The server's spells, in its order
+			for i, spellID := range components.SpellList {
+				if i < len(g.UISystem.SpellsWidget.Slots) {
+					g.UISystem.SpellsWidget.Slots[i] = spellID
+				}
 			}
 
 			// Sync Unlocked Spells
@@ -134,6 +158,14 @@ func NewGame() *Game {
 
 	g.InputSystem = systems.NewInputSystem(g.Client, g.UISystem, g.Keys)
 	g.RenderSystem = systems.NewRenderSystem(g.Client, g.UISystem)
+	g.MusicSystem = systems.NewMusicSystem(g.Client)
+	g.InputSystem.MusicSystem = g.MusicSystem
+	g.Cutscenes = systems.NewCutsceneSystem(g.Client)
+	g.RenderSystem.Cutscene = g.Cutscenes
+	g.Prediction = systems.NewPredictionSystem(g.Client)
+	g.InputSystem.Prediction = g.Prediction
+	g.RenderSystem.Prediction = g.Prediction
+	g.UISystem.Prediction = g.Prediction
 
 	return g
 }
@@ -144,16 +176,32 @@ func (g *Game) Update() error {
 
 	g.UISystem.Update()
 
+	if g.Replay != nil {
+		g.updateReplay()
+		return nil
+	}
+
 	if !g.LoggedIn {
 		return nil
 	}
 
+	g.Cutscenes.Update()
 	g.HandleInput()
+	g.Prediction.Update()
+	g.MusicSystem.Update()
 
 	return nil
 }
 
 func (g *Game) HandleInput() {
+	// The server ignores input during a cutscene; the menu key skips it
+	if g.Cutscenes.Active() {
+		if inpututil.IsKeyJustPressed(g.Keys[config.ActionMenu]) {
+			g.Cutscenes.Skip()
+		}
+		return
+	}
+
 	// Global Toggles via System
 	g.InputSystem.HandleGlobalKeys()
 
@@ -174,6 +222,9 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	}
 
 	g.RenderSystem.Draw(screen)
+	if g.Replay != nil {
+		g.drawReplayBar(screen)
+	}
 
 	// UI is drawn by RenderSystem
 }
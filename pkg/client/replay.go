@@ -0,0 +1,123 @@
+package client
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+
+	"henry/pkg/network"
+	"henry/pkg/shared/ecs"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Replay controls: Space pause, Left/Right seek, Up/Down speed, Home restart,
+// Tab follow the next player. Clicking the bar at the bottom seeks too.
+const (
+	replaySeekStep = 5.0 // Seconds per Left/Right press
+	replayBarH     = 24
+)
+
+var replaySpeeds = []float64{0.25, 0.5, 1, 2, 4}
+
+// StartReplay plays a recording instead of logging in
+func (g *Game) StartReplay(path string) error {
+	r, err := network.LoadReplay(path)
+	if err != nil {
+		return err
+	}
+	g.Replay = network.NewReplayPlayer(g.Client, r)
+	g.LoggedIn = true
+	g.UISystem.HideLogin()
+	return nil
+}
+
+func (g *Game) updateReplay() {
+	p := g.Replay
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		if p.Paused && p.Time >= p.Replay.Duration() {
+			p.Seek(0)
+		}
+		p.Paused = !p.Paused
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+		p.Seek(p.Time - replaySeekStep)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+		p.Seek(p.Time + replaySeekStep)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyHome) {
+		p.Seek(0)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+		p.Speed = nextReplaySpeed(p.Speed, 1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+		p.Speed = nextReplaySpeed(p.Speed, -1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		g.followNextPlayer()
+	}
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		mx, my := ebiten.CursorPosition()
+		if my >= ScreenHeight-replayBarH && p.Replay.Duration() > 0 {
+			p.Seek(float64(mx) / ScreenWidth * p.Replay.Duration())
+		}
+	}
+
+	p.Advance(1.0 / float64(ebiten.TPS()))
+}
+
+func nextReplaySpeed(speed float64, step int) float64 {
+	i := sort.SearchFloat64s(replaySpeeds, speed)
+	i = max(0, min(i+step, len(replaySpeeds)-1))
+	return replaySpeeds[i]
+}
+
+// followNextPlayer moves the camera to the next player in the snapshot
+func (g *Game) followNextPlayer() {
+	state := g.Client.GetState()
+	var players []ecs.Entity
+	for _, e := range state.Entities {
+		if e.Stance != nil {
+			players = append(players, e.ID)
+		}
+	}
+	if len(players) == 0 {
+		return
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i] < players[j] })
+	next := players[0]
+	for _, id := range players {
+		if id > g.Client.PlayerEntityID {
+			next = id
+			break
+		}
+	}
+	g.Replay.SetPlayer(next)
+}
+
+// drawReplayBar draws the scrub bar and playback state along the bottom of the screen
+func (g *Game) drawReplayBar(screen *ebiten.Image) {
+	p := g.Replay
+	y := float32(ScreenHeight - replayBarH)
+	vector.DrawFilledRect(screen, 0, y, ScreenWidth, replayBarH, color.RGBA{0, 0, 0, 180}, false)
+	if d := p.Replay.Duration(); d > 0 {
+		vector.DrawFilledRect(screen, 0, y, float32(p.Time/d)*ScreenWidth, 3, color.RGBA{220, 180, 60, 255}, false)
+	}
+
+	state := "Playing"
+	if p.Paused {
+		state = "Paused"
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("REPLAY %s %s / %s  x%g   Space pause  <- -> seek  Up/Down speed  Home restart  Tab follow",
+		state, replayClock(p.Time), replayClock(p.Replay.Duration()), p.Speed), 6, int(y)+6)
+}
+
+func replayClock(seconds float64) string {
+	s := int(seconds)
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
@@ -0,0 +1,64 @@
+package systems
+
+import (
+	"fmt"
+
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/ui"
+)
+
+func (s *UISystem) InitAwayUI() {
+	w := ui.NewWindow(260, 160, 280, 170, "While You Were Away")
+	w.ShowScrollbar = false
+	w.Visible = false
+	s.AwayWindow = w
+	s.Manager.AddElement(w)
+}
+
+// updateAway shows the summary the server sends at login, if any
+func (s *UISystem) updateAway() {
+	if away := s.Client.PopAway(); away != nil {
+		s.showAway(*away)
+	}
+}
+
+// showAway fills the away window with one line per thing that happened
+func (s *UISystem) showAway(away protocol.AwaySummaryPacket) {
+	w := s.AwayWindow
+	w.Children = nil
+
+	lines := []string{"You were away for " + formatAway(away.AwaySeconds) + "."}
+	if away.Mail > 0 {
+		mail := fmt.Sprintf("%d new mail", away.Mail)
+		switch {
+		case away.MailGold > 0 && away.MailItems > 0:
+			mail += fmt.Sprintf(": %d gold, %d items", away.MailGold, away.MailItems)
+		case away.MailGold > 0:
+			mail += fmt.Sprintf(": %d gold", away.MailGold)
+		case away.MailItems > 0:
+			mail += fmt.Sprintf(": %d items", away.MailItems)
+		}
+		lines = append(lines, mail+".")
+	}
+	if away.RipeCrops == 1 {
+		lines = append(lines, "A crop of yours is ripe.")
+	} else if away.RipeCrops > 1 {
+		lines = append(lines, fmt.Sprintf("%d crops of yours are ripe.", away.RipeCrops))
+	}
+
+	yOffset := 10.0
+	for _, line := range lines {
+		w.AddChild(ui.NewLabel(10, yOffset, line))
+		yOffset += 22
+	}
+	w.AddChild(ui.NewButton(w.Width-90, w.Height-65, 80, 30, "OK", func() { w.Visible = false }))
+	w.Visible = true
+}
+
+// formatAway renders seconds offline as e.g. "2d 3h" or "3h 25m"
+func formatAway(seconds int64) string {
+	if hours := seconds / 3600; hours >= 24 {
+		return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+	}
+	return formatPlaytime(float64(seconds))
+}
@@ -0,0 +1,206 @@
+package systems
+
+import (
+	"strings"
+
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Chat window layout
+const (
+	chatLineHeight  = 14
+	chatLineChars   = 45 // Characters per line before wrapping (the debug font is 6px wide)
+	chatBubbleChars = 24 // Characters per speech bubble line
+)
+
+// chatBubble is a line said by someone nearby, drawn above them until it times out
+type chatBubble struct {
+	Lines    []string
+	TimeLeft float64
+}
+
+// Chat commands typed at the start of a line. Anything else is said in local chat.
+//
+//	/g, /global <text>
+//	/z, /zone <text> (everyone in a zone of the same name, on any level)
+//	/s, /say, /l, /local <text>
+//	/w, /whisper, /tell <player> <text>
+//	/r, /reply <text> (whisper to whoever whispered last)
+var chatChannels = map[string]string{
+	"g": protocol.ChatGlobal, "global": protocol.ChatGlobal,
+	"z": protocol.ChatZone, "zone": protocol.ChatZone,
+	"s": protocol.ChatLocal, "say": protocol.ChatLocal, "l": protocol.ChatLocal, "local": protocol.ChatLocal,
+	"w": protocol.ChatWhisper, "whisper": protocol.ChatWhisper, "tell": protocol.ChatWhisper,
+	"r": protocol.ChatWhisper, "reply": protocol.ChatWhisper,
+}
+
+func (s *UISystem) InitChatUI() {
+	w := ui.NewWindow(5, 400, 285, 195, "Chat (Enter to talk)")
+	w.FooterHeight = 30
+	s.ChatInput = ui.NewTextInput(5, w.Height-50, w.Width-10, 25, "/g global, /w name whisper")
+	w.AddChildOption(s.ChatInput, true)
+	w.Visible = false
+	s.ChatWindow = w
+	s.Manager.AddElement(w)
+}
+
+// updateChat adds received lines to the history and handles Enter: it starts typing,
+// and sends once there's something typed
+func (s *UISystem) updateChat() {
+	msgs := s.Client.PopChat()
+	for _, msg := range msgs {
+		if msg.Channel == protocol.ChatWhisper && !strings.EqualFold(msg.From, s.Username) {
+			s.lastWhisperFrom = msg.From
+		}
+		s.addChatLine(formatChat(msg, s.Username))
+		s.addChatBubble(msg)
+	}
+	if len(msgs) > 0 {
+		s.refreshChat()
+	}
+
+	if !s.ChatWindow.Visible || !(inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter)) {
+		return
+	}
+	if !s.ChatInput.Focused {
+		if !s.IsInputCaptured() {
+			s.ChatInput.Focused = true
+		}
+		return
+	}
+	s.SendChat(s.ChatInput.Text)
+	s.ChatInput.Text = ""
+	s.ChatInput.Focused = false
+}
+
+// SendChat sends a typed line, reading a leading chat command
+func (s *UISystem) SendChat(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+	channel, to, text := protocol.ChatLocal, "", line
+	if strings.HasPrefix(line, "/") {
+		cmd, rest, _ := strings.Cut(line[1:], " ")
+		cmd = strings.ToLower(cmd)
+		var ok bool
+		if channel, ok = chatChannels[cmd]; !ok {
+			s.addChatNotice("Unknown chat command /" + cmd)
+			return
+		}
+		text = strings.TrimSpace(rest)
+		switch cmd {
+		case "r", "reply":
+			if s.lastWhisperFrom == "" {
+				s.addChatNotice("Nobody has whispered to you yet")
+				return
+			}
+			to = s.lastWhisperFrom
+		case "w", "whisper", "tell":
+			to, text, _ = strings.Cut(text, " ")
+			text = strings.TrimSpace(text)
+		}
+	}
+	if text == "" {
+		return
+	}
+	s.Client.SendChat(channel, to, text)
+}
+
+func (s *UISystem) addChatNotice(text string) {
+	s.addChatLine(formatChat(protocol.ChatBroadcastPacket{Channel: protocol.ChatNotice, Text: text}, s.Username))
+	s.refreshChat()
+}
+
+// addChatLine appends a line, wrapped to the window, dropping the oldest past the limit
+func (s *UISystem) addChatLine(line string) {
+	for len(line) > chatLineChars {
+		cut := strings.LastIndex(line[:chatLineChars], " ")
+		if cut <= 0 {
+			cut = chatLineChars
+		}
+		s.chatLines = append(s.chatLines, line[:cut])
+		line = "  " + strings.TrimLeft(line[cut:], " ")
+	}
+	s.chatLines = append(s.chatLines, line)
+	if len(s.chatLines) > config.ChatHistoryLines {
+		s.chatLines = s.chatLines[len(s.chatLines)-config.ChatHistoryLines:]
+	}
+}
+
+// addChatBubble shows a short line above its speaker, replacing their previous one
+func (s *UISystem) addChatBubble(msg protocol.ChatBroadcastPacket) {
+	if s.HideChatBubbles || msg.Speaker == 0 || len([]rune(msg.Text)) > config.ChatBubbleMaxLength {
+		return
+	}
+	if s.chatBubbles == nil {
+		s.chatBubbles = make(map[ecs.Entity]*chatBubble)
+	}
+	s.chatBubbles[msg.Speaker] = &chatBubble{Lines: wrapText(msg.Text, chatBubbleChars), TimeLeft: config.ChatBubbleSeconds}
+}
+
+// SetChatBubbles shows or hides speech bubbles and saves the choice with the account
+func (s *UISystem) SetChatBubbles(show bool) {
+	s.HideChatBubbles = !show
+	if !show {
+		s.chatBubbles = nil
+	}
+	s.refreshBubblesButton()
+	s.syncSettings()
+}
+
+func (s *UISystem) refreshBubblesButton() {
+	if s.bubblesBtn == nil {
+		return
+	}
+	s.bubblesBtn.Text = "Chat Bubbles: On"
+	if s.HideChatBubbles {
+		s.bubblesBtn.Text = "Chat Bubbles: Off"
+	}
+}
+
+// formatChat renders a chat line, e.g. "[G] alice: hi" or "[To bob] psst"
+func formatChat(msg protocol.ChatBroadcastPacket, self string) string {
+	switch msg.Channel {
+	case protocol.ChatGlobal:
+		return "[G] " + msg.From + ": " + msg.Text
+	case protocol.ChatZone:
+		return "[Z] " + msg.From + ": " + msg.Text
+	case protocol.ChatWhisper:
+		if strings.EqualFold(msg.From, self) {
+			return "[To " + msg.To + "] " + msg.Text
+		}
+		return "[" + msg.From + "] " + msg.Text
+	case protocol.ChatNotice:
+		return "* " + msg.Text
+	default:
+		return msg.From + ": " + msg.Text
+	}
+}
+
+// refreshChat rebuilds the history labels, staying at the bottom unless the player
+// scrolled up to read
+func (s *UISystem) refreshChat() {
+	w := s.ChatWindow
+	viewHeight := w.Height - 20 - w.FooterHeight
+	atBottom := w.ScrollY >= w.ContentHeight-viewHeight-chatLineHeight
+
+	w.Children = w.Children[:0]
+	w.ContentHeight = 0
+	for i, line := range s.chatLines {
+		label := ui.NewLabel(5, float64(i*chatLineHeight+4), line)
+		label.Height = chatLineHeight
+		w.AddChild(label)
+	}
+	w.AddChildOption(s.ChatInput, true)
+
+	if maxScroll := w.ContentHeight - viewHeight; atBottom && maxScroll > 0 {
+		w.ScrollY = maxScroll
+	}
+}
@@ -0,0 +1,163 @@
+package systems
+
+import (
+	"image/color"
+
+	"henry/pkg/network"
+	"henry/pkg/shared/config"
+	protocol "henry/pkg/shared/network"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const letterboxHeight = 60
+
+// CutsceneSystem is the cutscene director. While the server has a cutscene playing for
+// the player it moves the camera shot by shot, draws the letterbox bars and subtitles,
+// and takes the place of the HUD and UI.
+type CutsceneSystem struct {
+	Client *network.NetworkClient
+
+	active  *protocol.CutscenePacket
+	elapsed float64 // Seconds into the cutscene
+	shot    int     // Shot the camera is on (-1 before the first)
+
+	fromX, fromY float64 // Camera when the current shot began
+	toX, toY     float64 // Where the current shot looks
+	camX, camY   float64 // Camera last frame
+}
+
+func NewCutsceneSystem(client *network.NetworkClient) *CutsceneSystem {
+	return &CutsceneSystem{Client: client}
+}
+
+// Active reports whether a cutscene has the camera
+func (s *CutsceneSystem) Active() bool {
+	return s.active != nil
+}
+
+// Update starts and ends cutscenes sent by the server and runs the clock. Call once per
+// frame while in game.
+func (s *CutsceneSystem) Update() {
+	for _, packet := range s.Client.PopCutscenes() {
+		if len(packet.Shots) == 0 {
+			if s.active != nil && s.active.ID == packet.ID {
+				s.Reset()
+			}
+			continue
+		}
+		s.active = &packet
+		s.elapsed = 0
+		s.shot = -1
+	}
+	if s.active == nil {
+		return
+	}
+	if s.elapsed += 1 / float64(ebiten.TPS()); s.elapsed >= s.length() {
+		s.Reset()
+	}
+}
+
+// Skip ends a skippable cutscene and tells the server. Returns false if it can't be skipped.
+func (s *CutsceneSystem) Skip() bool {
+	if s.active == nil || !s.active.Skippable {
+		return false
+	}
+	s.Client.SendCutsceneSkip(s.active.ID)
+	s.Reset()
+	return true
+}
+
+// Reset drops the cutscene (ended, or logging out)
+func (s *CutsceneSystem) Reset() {
+	s.active = nil
+}
+
+// Camera returns the camera's top left corner for this frame, panning from wherever it
+// was to the current shot's target. ok is false when no cutscene is playing; defX, defY
+// is the normal camera over the player.
+func (s *CutsceneSystem) Camera(state protocol.StateUpdatePacket, defX, defY float64) (x, y float64, ok bool) {
+	if s.active == nil {
+		s.camX, s.camY = defX, defY
+		return defX, defY, false
+	}
+
+	// Find the shot and how far into it we are
+	index, into := len(s.active.Shots)-1, 0.0
+	start := 0.0
+	for i, shot := range s.active.Shots {
+		if s.elapsed < start+shot.Duration {
+			index, into = i, s.elapsed-start
+			break
+		}
+		start += shot.Duration
+	}
+	shot := s.active.Shots[index]
+	if index != s.shot {
+		s.shot = index
+		s.fromX, s.fromY = s.camX, s.camY
+	}
+
+	// Focused entities are followed; ones out of view keep their last spot
+	half := float64(config.TileSize) / 2
+	if shot.Focus != 0 {
+		for _, entity := range state.Entities {
+			if entity.ID == shot.Focus && entity.Transform != nil {
+				s.toX, s.toY = entity.Transform.X-400+half, entity.Transform.Y-300+half
+				break
+			}
+		}
+	} else {
+		s.toX, s.toY = shot.X-400, shot.Y-300
+	}
+
+	t := 1.0
+	if shot.Pan > 0 && into < shot.Pan {
+		t = into / shot.Pan
+		t = t * t * (3 - 2*t) // Ease in and out
+	}
+	s.camX = s.fromX + (s.toX-s.fromX)*t
+	s.camY = s.fromY + (s.toY-s.fromY)*t
+	return s.camX, s.camY, true
+}
+
+// Draw renders the letterbox bars, the current subtitle and the skip hint
+func (s *CutsceneSystem) Draw(screen *ebiten.Image) {
+	if s.active == nil {
+		return
+	}
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	black := color.RGBA{0, 0, 0, 255}
+	if s.active.Letterbox {
+		vector.DrawFilledRect(screen, 0, 0, float32(w), letterboxHeight, black, false)
+		vector.DrawFilledRect(screen, 0, float32(h-letterboxHeight), float32(w), letterboxHeight, black, false)
+	}
+
+	if s.shot >= 0 && s.shot < len(s.active.Shots) {
+		shot := s.active.Shots[s.shot]
+		if text := shot.Subtitle; text != "" {
+			if shot.Speaker != "" {
+				text = shot.Speaker + ": " + text
+			}
+			x, y := w/2-len(text)*3, h-letterboxHeight/2-8 // Debug font is 6px wide
+			if !s.active.Letterbox {
+				vector.DrawFilledRect(screen, float32(x-6), float32(y-4), float32(len(text)*6+12), 24, color.RGBA{0, 0, 0, 160}, false)
+			}
+			ebitenutil.DebugPrintAt(screen, text, x, y)
+		}
+	}
+
+	if s.active.Skippable {
+		ebitenutil.DebugPrintAt(screen, "Esc: skip", w-70, 10)
+	}
+}
+
+func (s *CutsceneSystem) length() float64 {
+	total := 0.0
+	for _, shot := range s.active.Shots {
+		total += shot.Duration
+	}
+	return min(total, config.CutsceneMaxDuration)
+}
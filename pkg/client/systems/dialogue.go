@@ -0,0 +1,81 @@
+package systems
+
+import (
+	"strings"
+
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/ui"
+)
+
+// Dialogue window layout
+const (
+	dialogueWidth     = 360.0
+	dialogueLineChars = 55 // Characters per line before wrapping (the debug font is 6px wide)
+)
+
+func (s *UISystem) InitDialogueUI() {
+	w := ui.NewWindow((800-dialogueWidth)/2, 300, dialogueWidth, 260, "")
+	w.Visible = false
+	s.DialogueWindow = w
+	s.Manager.AddElement(w)
+}
+
+// updateDialogue shows the node the server sent, or closes the window when the
+// conversation ended on its side
+func (s *UISystem) updateDialogue() {
+	dialogue, changed := s.Client.PopDialogue()
+	if !changed {
+		return
+	}
+	if dialogue.NPC == 0 {
+		s.DialogueWindow.Visible = false
+		return
+	}
+	s.refreshDialogue(dialogue)
+	s.DialogueWindow.Visible = true
+}
+
+// refreshDialogue rebuilds the dialogue window: what the NPC says, then a button per
+// reply and a goodbye
+func (s *UISystem) refreshDialogue(dialogue protocol.DialoguePacket) {
+	w := s.DialogueWindow
+	w.Title = dialogue.Speaker
+	w.Children = nil
+	w.ContentHeight = 0
+	w.ScrollY = 0
+
+	yOffset := 10.0
+	for _, line := range wrapText(dialogue.Text, dialogueLineChars) {
+		w.AddChild(ui.NewLabel(10, yOffset, line))
+		yOffset += 16
+	}
+	yOffset += 10
+	for i, option := range dialogue.Options {
+		choice := i
+		w.AddChild(ui.NewButton(10, yOffset, w.Width-20, 25, option, func() {
+			s.Client.SendDialogueChoice(choice)
+		}))
+		yOffset += 30
+	}
+
+	w.FooterHeight = 40
+	byeBtn := ui.NewSecondaryButton(10, w.Height-55, w.Width-20, 30, "Goodbye", func() {
+		s.Client.SendDialogueChoice(-1)
+		w.Visible = false
+	})
+	w.AddChildOption(byeBtn, true)
+}
+
+// wrapText breaks text into lines of at most width characters, at spaces where it can
+func wrapText(text string, width int) []string {
+	var lines []string
+	for len(text) > width {
+		cut := strings.LastIndex(text[:width], " ")
+		if cut <= 0 {
+			cut = width
+		}
+		lines = append(lines, text[:cut])
+		text = strings.TrimLeft(text[cut:], " ")
+	}
+	return append(lines, text)
+}
@@ -0,0 +1,116 @@
+package systems
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	protocol "henry/pkg/shared/network"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Particle is one dot of a hit spark or death puff, in world px
+type Particle struct {
+	X, Y       float64
+	VelX, VelY float64 // px/s
+	Drag       float64 // Fraction of velocity kept per second
+	Life       float64 // Seconds left
+	MaxLife    float64
+	Size       float64 // Radius at birth
+	Grow       float64 // Radius gained over its life
+	Color      color.RGBA
+}
+
+// Effect tuning
+const (
+	hitSparks   = 8
+	hitLife     = 0.3
+	deathPuffs  = 14
+	deathLife   = 0.8
+	levelSparks = 24
+	levelLife   = 1.2
+	maxParticle = 600 // Oldest are dropped beyond this (big fights)
+)
+
+// spawnEffects turns the combat events received since the last frame into particles
+func (s *RenderSystem) spawnEffects() {
+	for _, event := range s.Client.PopEvents() {
+		switch event.Kind {
+		case protocol.EventHit:
+			// Small burst of sparks flying out of the impact point
+			for i := 0; i < hitSparks; i++ {
+				angle := rand.Float64() * 2 * math.Pi
+				speed := 60 + rand.Float64()*80
+				s.Particles = append(s.Particles, &Particle{
+					X: event.X, Y: event.Y,
+					VelX: math.Cos(angle) * speed, VelY: math.Sin(angle) * speed,
+					Drag: 0.05, Life: hitLife, MaxLife: hitLife,
+					Size: 2.5, Color: event.Color,
+				})
+			}
+		case protocol.EventDeath:
+			// Puff of smoke and the body's color, drifting up as it fades
+			for i := 0; i < deathPuffs; i++ {
+				angle := rand.Float64() * 2 * math.Pi
+				speed := 10 + rand.Float64()*30
+				c := color.RGBA{170, 170, 170, 255}
+				if i%2 == 0 {
+					c = event.Color
+				}
+				life := deathLife * (0.7 + rand.Float64()*0.3)
+				s.Particles = append(s.Particles, &Particle{
+					X: event.X + math.Cos(angle)*8, Y: event.Y + math.Sin(angle)*8,
+					VelX: math.Cos(angle) * speed, VelY: math.Sin(angle)*speed - 25,
+					Drag: 0.2, Life: life, MaxLife: life,
+					Size: 4, Grow: 8, Color: c,
+				})
+			}
+		case protocol.EventLevelUp:
+			// Ring of golden sparks rising around the player
+			for i := 0; i < levelSparks; i++ {
+				angle := 2 * math.Pi * float64(i) / levelSparks
+				life := levelLife * (0.8 + rand.Float64()*0.2)
+				s.Particles = append(s.Particles, &Particle{
+					X: event.X + math.Cos(angle)*16, Y: event.Y + math.Sin(angle)*8,
+					VelX: math.Cos(angle) * 10, VelY: -40 - rand.Float64()*30,
+					Drag: 0.5, Life: life, MaxLife: life,
+					Size: 3, Color: event.Color,
+				})
+			}
+		}
+	}
+	if extra := len(s.Particles) - maxParticle; extra > 0 {
+		s.Particles = s.Particles[extra:]
+	}
+}
+
+// drawEffects moves, fades and draws the live particles, dropping finished ones
+func (s *RenderSystem) drawEffects(screen *ebiten.Image, dt, camX, camY float64) {
+	s.spawnEffects()
+	live := s.Particles[:0]
+	for _, p := range s.Particles {
+		p.Life -= dt
+		if p.Life <= 0 {
+			continue
+		}
+		keep := math.Pow(p.Drag, dt)
+		p.VelX *= keep
+		p.VelY *= keep
+		p.X += p.VelX * dt
+		p.Y += p.VelY * dt
+
+		left := p.Life / p.MaxLife // 1 at birth, 0 when gone
+		radius := p.Size + p.Grow*(1-left)
+		c := p.Color
+		c.R, c.G, c.B, c.A = scale8(c.R, left), scale8(c.G, left), scale8(c.B, left), scale8(c.A, left) // Premultiplied fade
+		vector.DrawFilledCircle(screen, float32(p.X-camX), float32(p.Y-camY), float32(radius), c, true)
+		live = append(live, p)
+	}
+	s.Particles = live
+}
+
+func scale8(v uint8, f float64) uint8 {
+	return uint8(float64(v) * f)
+}
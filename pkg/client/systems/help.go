@@ -0,0 +1,169 @@
+package systems
+
+import (
+	"slices"
+	"strings"
+
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/ui"
+)
+
+// helpWrap is how many characters fit on a line of the help window
+const helpWrap = 60
+
+func (s *UISystem) InitHelpUI() {
+	w := ui.NewWindow(200, 80, 400, 440, "Help")
+	w.Visible = false
+	s.HelpWindow = w
+	s.HelpInput = ui.NewTextInput(10, w.Height-95, w.Width-20, 25, "Search help...")
+	s.Manager.AddElement(w)
+
+	// "?" buttons on the windows that need explaining
+	links := map[*ui.Window]string{
+		s.KeybindingsWindow: "controls",
+		s.ShopWindow:        "shops",
+		s.WardrobeWindow:    "wardrobe",
+		s.HouseWindow:       "housing",
+		s.FishingWindow:     "fishing",
+		s.ArenaWindow:       "arena",
+		s.CharacterWindow:   "character",
+	}
+	for window, topicID := range links {
+		window.OnHelp = func() { s.OpenHelp(topicID) }
+	}
+}
+
+// OpenHelp shows the help window on a topic ("" or an unknown topic shows the list)
+func (s *UISystem) OpenHelp(topicID string) {
+	s.helpTopic = topicID
+	s.HelpInput.Text = ""
+	s.helpQuery = ""
+	s.refreshHelp()
+	s.HelpWindow.Visible = true
+}
+
+// updateHelp takes the topics sent at login and searches as the player types
+func (s *UISystem) updateHelp() {
+	if topics, changed := s.Client.PopHelp(); changed {
+		s.helpTopics = topics
+		if s.HelpWindow.Visible {
+			s.refreshHelp()
+		}
+	}
+	if s.HelpWindow.Visible && s.HelpInput.Text != s.helpQuery {
+		s.helpQuery = s.HelpInput.Text
+		s.helpTopic = "" // Searching goes back to the list
+		s.refreshHelp()
+	}
+}
+
+// refreshHelp rebuilds the help window: the open topic, or the topics matching the
+// search. The search box and Close button sit in the footer so the page scrolls
+// under neither.
+func (s *UISystem) refreshHelp() {
+	w := s.HelpWindow
+	w.Children = nil
+	w.ContentHeight = 0
+	w.ScrollY = 0
+
+	yOffset := 10.0
+	i := slices.IndexFunc(s.helpTopics, func(t protocol.HelpTopic) bool { return t.ID == s.helpTopic })
+	if i >= 0 {
+		topic := s.helpTopics[i]
+		w.AddChild(ui.NewButton(10, yOffset, 120, 25, "< All topics", func() {
+			s.helpTopic = ""
+			s.refreshHelp()
+		}))
+		yOffset += 35
+		w.AddChild(ui.NewLabel(10, yOffset, strings.ToUpper(topic.Title)))
+		yOffset += 25
+		for _, line := range strings.Split(topic.Body, "\n") {
+			switch {
+			case strings.TrimSpace(line) == "":
+				yOffset += 8
+			case strings.HasPrefix(line, "#"):
+				yOffset += 6
+				w.AddChild(ui.NewLabel(10, yOffset, strings.TrimSpace(strings.TrimLeft(line, "#"))))
+				yOffset += 20
+			case strings.HasPrefix(line, "- "):
+				for j, part := range wrapText(strings.TrimPrefix(line, "- "), helpWrap-2) {
+					prefix := "  "
+					if j == 0 {
+						prefix = "- "
+					}
+					w.AddChild(ui.NewLabel(15, yOffset, prefix+part))
+					yOffset += 16
+				}
+			default:
+				for _, part := range wrapText(line, helpWrap) {
+					w.AddChild(ui.NewLabel(10, yOffset, part))
+					yOffset += 16
+				}
+			}
+		}
+	} else {
+		results := searchHelp(s.helpTopics, s.helpQuery)
+		switch {
+		case len(s.helpTopics) == 0:
+			w.AddChild(ui.NewLabel(10, yOffset, "No help available on this server."))
+		case len(results) == 0:
+			w.AddChild(ui.NewLabel(10, yOffset, "No topics match \""+s.helpQuery+"\"."))
+		}
+		for _, topic := range results {
+			w.AddChild(ui.NewButton(10, yOffset, w.Width-30, 25, topic.Title, func() {
+				s.helpTopic = topic.ID
+				s.refreshHelp()
+			}))
+			yOffset += 30
+		}
+	}
+
+	w.FooterHeight = 80
+	w.AddChildOption(s.HelpInput, true)
+	closeBtn := ui.NewSecondaryButton(10, w.Height-55, w.Width-20, 30, "Close", func() {
+		w.Visible = false
+		s.HelpInput.Focused = false
+	})
+	w.AddChildOption(closeBtn, true)
+}
+
+// searchHelp returns the topics containing every word of the query, best first: a
+// word in the title counts most, then a keyword, then the body. An empty query
+// returns every topic.
+func searchHelp(topics []protocol.HelpTopic, query string) []protocol.HelpTopic {
+	words := strings.Fields(strings.ToLower(query))
+	type result struct {
+		topic protocol.HelpTopic
+		score int
+	}
+	var results []result
+	for _, topic := range topics {
+		title, body := strings.ToLower(topic.Title), strings.ToLower(topic.Body)
+		score := 0
+		for _, word := range words {
+			switch {
+			case strings.Contains(title, word):
+				score += 3
+			case slices.ContainsFunc(topic.Keywords, func(k string) bool { return strings.Contains(k, word) }):
+				score += 2
+			case strings.Contains(body, word):
+				score++
+			default:
+				score = -1
+			}
+			if score < 0 {
+				break
+			}
+		}
+		if score >= 0 {
+			results = append(results, result{topic, score})
+		}
+	}
+	slices.SortStableFunc(results, func(a, b result) int { return b.score - a.score })
+
+	matches := make([]protocol.HelpTopic, len(results))
+	for i, r := range results {
+		matches[i] = r.topic
+	}
+	return matches
+}
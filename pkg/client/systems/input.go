@@ -5,16 +5,25 @@ import (
 	"henry/pkg/network"
 	"henry/pkg/shared/components"
 	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/shared/world"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 type InputSystem struct {
-	Client    *network.NetworkClient
-	UISystem  *UISystem // Use UISystem instead of Manager
-	Keys      map[string]ebiten.Key
-	isRunning bool // Local toggle state
+	Client      *network.NetworkClient
+	UISystem    *UISystem // Use UISystem instead of Manager
+	MusicSystem *MusicSystem
+	Prediction  *PredictionSystem
+	Keys        map[string]ebiten.Key
+	stance      string // Local toggle state (components.StanceWalk/Run/Sneak)
+
+	pickupAsked map[ecs.Entity]float64 // Seconds until the loot filter may ask for an item again
 }
 
 func NewInputSystem(client *network.NetworkClient, uiSystem *UISystem, keys map[string]ebiten.Key) *InputSystem {
@@ -25,8 +34,8 @@ func NewInputSystem(client *network.NetworkClient, uiSystem *UISystem, keys map[
 	}
 }
 
-func (s *InputSystem) SetRunning(isRunning bool) {
-	s.isRunning = isRunning
+func (s *InputSystem) SetStance(stance string) {
+	s.stance = stance
 }
 
 func (s *InputSystem) Update() {
@@ -46,11 +55,15 @@ func (s *InputSystem) Update() {
 		input.Right = true
 	}
 
-	// Running Toggle (Shift)
+	// Stance Toggles (Shift runs, C sneaks; pressing again walks)
 	if inpututil.IsKeyJustPressed(s.Keys[config.ActionRun]) {
-		s.isRunning = !s.isRunning
+		s.toggleStance(components.StanceRun)
+	}
+	if inpututil.IsKeyJustPressed(s.Keys[config.ActionSneak]) {
+		s.toggleStance(components.StanceSneak)
 	}
-	input.IsRunning = s.isRunning
+	input.Stance = s.stance
+	input.Dodge = inpututil.IsKeyJustPressed(s.Keys[config.ActionDodge])
 
 	// Always capture mouse position for rotation/facing
 	if !s.UISystem.IsMouseOverUI() {
@@ -58,7 +71,10 @@ func (s *InputSystem) Update() {
 
 		// Account for camera offset
 		var camX, camY float64
-		state := s.Client.GetState()
+		state := s.Client.GetInterpolatedState()
+		if s.Prediction != nil {
+			state = s.Prediction.Apply(state) // Same camera as RenderSystem
+		}
 		playerID := s.Client.PlayerEntityID
 		for _, entity := range state.Entities {
 			if entity.ID == playerID && entity.Transform != nil {
@@ -70,13 +86,29 @@ func (s *InputSystem) Update() {
 
 		input.MouseX = float64(mx) + camX
 		input.MouseY = float64(my) + camY
+
+		if s.UISystem.BuildItemID != "" {
+			// Build Mode: left click places on the hovered tile, right click stops
+			if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+				tileSize := float64(config.TileSize)
+				tx := int(math.Floor(input.MouseX / tileSize))
+				ty := int(math.Floor(input.MouseY / tileSize))
+				s.Client.SendBuild("place", s.UISystem.BuildItemID, tx, ty, 0)
+			}
+			if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+				s.UISystem.CancelBuild()
+			}
+		} else if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+			// Right Click: Follow clicked character, or click-to-move on ground
+			s.handleWorldRightClick(state, input.MouseX, input.MouseY)
+		}
 	}
 
 	// Active Spell
 	input.ActiveSpell = s.UISystem.ActiveSpellID
 
 	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-		if !s.UISystem.IsMouseOverUI() {
+		if !s.UISystem.IsMouseOverUI() && s.UISystem.BuildItemID == "" {
 			input.Attack = true
 		}
 	}
@@ -117,11 +149,128 @@ func (s *InputSystem) Update() {
 		}
 	}
 
+	s.autoPickup(s.Client.GetInterpolatedState(), 1.0/60.0)
+
 	// Send Input
+	if s.Prediction != nil {
+		s.Prediction.SetInput(input)
+	}
 	s.Client.SendInput(input)
 }
 
+func (s *InputSystem) handleWorldRightClick(state protocol.StateUpdatePacket, worldX, worldY float64) {
+	tileSize := float64(config.TileSize)
+
+	// Ground Items take priority (small, drawn below characters)
+	for _, entity := range state.Entities {
+		if entity.Item == nil || entity.Transform == nil || entity.Sprite == nil {
+			continue
+		}
+		if def, ok := groundItemDef(entity); ok && s.UISystem.LootFilter.Ignores(def) {
+			continue // Filtered junk: click through to what's under it
+		}
+		if worldX >= entity.Transform.X && worldX <= entity.Transform.X+entity.Sprite.Width &&
+			worldY >= entity.Transform.Y && worldY <= entity.Transform.Y+entity.Sprite.Height {
+			s.Client.SendPickup(entity.ID)
+			s.UISystem.AddLog(fmt.Sprintf("Picking up %s", entity.Item.ItemID))
+			return
+		}
+	}
+
+	// Waypoints: open travel list when close, otherwise walk there
+	for _, entity := range state.Entities {
+		if entity.Waypoint == nil || entity.Transform == nil {
+			continue
+		}
+		if worldX >= entity.Transform.X && worldX <= entity.Transform.X+tileSize &&
+			worldY >= entity.Transform.Y && worldY <= entity.Transform.Y+tileSize {
+			if s.isNearPlayer(state, entity.Transform.X, entity.Transform.Y, 128) {
+				s.UISystem.OpenTravelWindow(entity.Waypoint.WaypointID)
+			} else {
+				s.Client.SendMoveTo(entity.Transform.X, entity.Transform.Y)
+			}
+			return
+		}
+	}
+
+	// Structures: demolish menu
+	for _, entity := range state.Entities {
+		if entity.Structure == nil || entity.Transform == nil {
+			continue
+		}
+		if worldX >= entity.Transform.X && worldX <= entity.Transform.X+tileSize &&
+			worldY >= entity.Transform.Y && worldY <= entity.Transform.Y+tileSize {
+			mx, my := ebiten.CursorPosition()
+			s.UISystem.OpenStructureMenu(entity.ID, entity.Structure, mx, my)
+			return
+		}
+	}
+
+	for _, entity := range state.Entities {
+		if entity.ID == s.Client.PlayerEntityID || entity.Transform == nil || entity.Sprite == nil || entity.Sprite.CharType == "" {
+			continue
+		}
+		if worldX >= entity.Transform.X && worldX <= entity.Transform.X+tileSize &&
+			worldY >= entity.Transform.Y && worldY <= entity.Transform.Y+tileSize {
+			if entity.Marker != nil && entity.Marker.Flags&components.MarkerArena != 0 {
+				mx, my := ebiten.CursorPosition()
+				s.UISystem.OpenArenaMenu(mx, my)
+				return
+			}
+			if entity.Marker != nil && entity.Marker.Flags&components.MarkerHouse != 0 {
+				mx, my := ebiten.CursorPosition()
+				s.UISystem.OpenHouseMenu(mx, my)
+				return
+			}
+			if entity.Sprite.CharType == "player" {
+				mx, my := ebiten.CursorPosition()
+				s.UISystem.OpenPlayerMenu(entity.ID, mx, my)
+				return
+			}
+			if s.Client.IsAdmin {
+				mx, my := ebiten.CursorPosition()
+				s.UISystem.OpenCharacterMenu(entity.ID, mx, my)
+				return
+			}
+			s.Client.SendFollow(entity.ID)
+			s.UISystem.AddLog(fmt.Sprintf("Following entity %d", entity.ID))
+			return
+		}
+	}
+
+	// Farmland: plant or harvest when close, otherwise walk there
+	tx, ty := int(math.Floor(worldX/tileSize)), int(math.Floor(worldY/tileSize))
+	if tile, obj, ok := s.Client.TileAt(tx, ty); ok && tile == world.TileFarmland &&
+		s.isNearPlayer(state, float64(tx)*tileSize, float64(ty)*tileSize, 128) {
+		mx, my := ebiten.CursorPosition()
+		s.UISystem.OpenFarmMenu(tx, ty, obj, mx, my)
+		return
+	}
+
+	// Ground: path to the clicked point (Transform is top-left, center the tile)
+	s.Client.SendMoveTo(worldX-tileSize/2, worldY-tileSize/2)
+}
+
+// isNearPlayer reports whether a world position is within dist of the local player
+func (s *InputSystem) isNearPlayer(state protocol.StateUpdatePacket, x, y, dist float64) bool {
+	for _, entity := range state.Entities {
+		if entity.ID != s.Client.PlayerEntityID || entity.Transform == nil {
+			continue
+		}
+		return geom.Within(entity.Transform.X, entity.Transform.Y, x, y, dist)
+	}
+	return false
+}
+
 func (s *InputSystem) HandleGlobalKeys() {
+	// Typing into a text field: only the Menu key (close) is handled
+	if s.UISystem.IsTyping() {
+		if inpututil.IsKeyJustPressed(s.Keys["Menu"]) {
+			s.UISystem.ToggleMenu()
+		}
+		return
+	}
+
 	if inpututil.IsKeyJustPressed(s.Keys["Inventory"]) {
 		s.UISystem.ToggleInventory()
 	}
@@ -136,8 +285,30 @@ func (s *InputSystem) HandleGlobalKeys() {
 		s.UISystem.ToggleBindMenu()
 	}
 
+	if inpututil.IsKeyJustPressed(s.Keys[config.ActionInteract]) {
+		s.Client.SendInteract(0) // The server picks the nearest NPC to talk to
+	}
+
+	if inpututil.IsKeyJustPressed(s.Keys[config.ActionMusic]) && s.MusicSystem != nil {
+		s.MusicSystem.ToggleMute()
+		if s.MusicSystem.Muted {
+			s.UISystem.AddLog("Music off")
+		} else {
+			s.UISystem.AddLog("Music on")
+		}
+	}
+
 	if inpututil.IsKeyJustPressed(s.Keys["Menu"]) {
-		s.UISystem.ToggleMenu()
+		if s.UISystem.BuildItemID != "" {
+			s.UISystem.CancelBuild()
+		} else {
+			s.UISystem.ToggleMenu()
+		}
+	}
+
+	// Fishing: Space hooks the fish
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		s.UISystem.HookFish()
 	}
 
 	// Debug Toggles
@@ -150,4 +321,17 @@ func (s *InputSystem) HandleGlobalKeys() {
 	if inpututil.IsKeyJustPressed(ebiten.KeyF3) {
 		s.UISystem.ToggleDebug(3)
 	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF4) {
+		s.UISystem.ToggleDebug(4)
+	}
+}
+
+// toggleStance switches into a stance, or back to walking if already in it
+func (s *InputSystem) toggleStance(stance string) {
+	if s.stance == stance {
+		s.stance = components.StanceWalk
+	} else {
+		s.stance = stance
+	}
+	s.UISystem.AddLog("Stance: " + s.stance)
 }
@@ -0,0 +1,43 @@
+package systems
+
+import (
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/ui"
+)
+
+// inventoryTabHeight is the height of the inventory window's tab row
+const inventoryTabHeight = 22
+
+// initKeyringTab adds the Bag and Keyring tabs to the inventory window. The keyring shows
+// keys and quest items, which the server keeps apart from the bag: they can't be moved,
+// dropped or traded, so its slots only answer tooltips.
+func (s *UISystem) initKeyringTab() {
+	s.KeyringWidget = ui.NewInventoryWidget(0, inventoryTabHeight, 5, 5, 40)
+	s.KeyringWidget.Visible = false
+	s.bagTab = ui.NewButton(0, 0, 100, inventoryTabHeight-2, "Bag", func() { s.showKeyring(false) })
+	s.keyringTab = ui.NewButton(100, 0, 100, inventoryTabHeight-2, "Keyring", func() { s.showKeyring(true) })
+	s.Inventory.AddChild(s.bagTab)
+	s.Inventory.AddChild(s.keyringTab)
+	s.Inventory.AddChild(s.KeyringWidget)
+	s.showKeyring(false)
+}
+
+// showKeyring switches the inventory window between the bag and the keyring
+func (s *UISystem) showKeyring(show bool) {
+	s.InvWidget.Visible = !show
+	s.KeyringWidget.Visible = show
+	s.bagTab.Style, s.keyringTab.Style = ui.ButtonStylePrimary, ui.ButtonStyleSecondary
+	if show {
+		s.bagTab.Style, s.keyringTab.Style = ui.ButtonStyleSecondary, ui.ButtonStylePrimary
+	}
+}
+
+// syncKeyring fills the keyring tab from the last inventory sync
+func (s *UISystem) syncKeyring(keyring []protocol.KeyringSlot) {
+	for i := range s.KeyringWidget.Slots {
+		s.KeyringWidget.Slots[i] = ""
+		if i < len(keyring) {
+			s.KeyringWidget.Slots[i] = keyring[i].ItemID
+		}
+	}
+}
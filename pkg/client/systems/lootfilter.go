@@ -0,0 +1,128 @@
+package systems
+
+import (
+	"henry/pkg/items"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/ui"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// lootToggle is one of the loot filter window's switches
+type lootToggle struct {
+	label string
+	flag  func(f *items.LootFilter) *bool
+}
+
+var lootToggles = []lootToggle{
+	{"Auto-pickup Gold", func(f *items.LootFilter) *bool { return &f.AutoGold }},
+	{"Auto-pickup Quest Items", func(f *items.LootFilter) *bool { return &f.AutoQuest }},
+	{"Ignore Junk", func(f *items.LootFilter) *bool { return &f.IgnoreJunk }},
+	{"Highlight Rares", func(f *items.LootFilter) *bool { return &f.HighlightRares }},
+}
+
+// InitLootFilterUI builds the loot filter window, opened from the menu
+func (s *UISystem) InitLootFilterUI() {
+	w := ui.NewWindow(270, 170, 260, 40+float64(len(lootToggles))*40, "Loot Filter")
+	s.lootFilterBtns = nil
+	for i, toggle := range lootToggles {
+		btn := ui.NewButton(10, 30+float64(i)*40, 240, 30, "", func() {
+			flag := toggle.flag(&s.LootFilter)
+			*flag = !*flag
+			s.refreshLootFilter()
+			s.syncSettings()
+		})
+		s.lootFilterBtns = append(s.lootFilterBtns, btn)
+		w.AddChild(btn)
+	}
+	s.refreshLootFilter()
+	w.Visible = false
+	s.LootFilterWindow = w
+	s.Manager.AddElement(w)
+}
+
+func (s *UISystem) refreshLootFilter() {
+	for i, btn := range s.lootFilterBtns {
+		btn.Text = lootToggles[i].label + ": Off"
+		if *lootToggles[i].flag(&s.LootFilter) {
+			btn.Text = lootToggles[i].label + ": On"
+		}
+	}
+}
+
+// groundItemDef looks up the definition of a ground item snapshot
+func groundItemDef(entity protocol.EntitySnapshot) (items.ItemDefinition, bool) {
+	if entity.Item == nil {
+		return items.ItemDefinition{}, false
+	}
+	return items.Get(entity.Item.ItemID)
+}
+
+// drawGroundItem draws a ground item's marker, grayed out when the loot filter ignores
+// it and outlined in its rarity color when it highlights it
+func (s *RenderSystem) drawGroundItem(screen *ebiten.Image, entity protocol.EntitySnapshot, x, y float64) {
+	w, h := float32(entity.Sprite.Width), float32(entity.Sprite.Height)
+	c := entity.Sprite.Color
+	def, ok := groundItemDef(entity)
+	if ok && s.UISystem.LootFilter.Ignores(def) {
+		c = color.RGBA{R: 110, G: 110, B: 110, A: 120}
+	}
+	vector.DrawFilledRect(screen, float32(x), float32(y), w, h, c, true)
+	if ok && s.UISystem.LootFilter.Highlights(def) {
+		vector.StrokeRect(screen, float32(x)-3, float32(y)-3, w+6, h+6, 2, def.Rarity.Color(), true)
+	}
+}
+
+// autoPickup asks the server for the ground items in reach that the loot filter takes
+// without a click, each at most every config.AutoPickupRetry seconds
+func (s *InputSystem) autoPickup(state protocol.StateUpdatePacket, dt float64) {
+	for id, left := range s.pickupAsked {
+		if left -= dt; left <= 0 {
+			delete(s.pickupAsked, id)
+		} else {
+			s.pickupAsked[id] = left
+		}
+	}
+	filter := s.UISystem.LootFilter
+	if !filter.AutoGold && !filter.AutoQuest {
+		return
+	}
+
+	playerID := s.Client.PlayerEntityID
+	var px, py float64
+	found := false
+	for _, entity := range state.Entities {
+		if entity.ID == playerID && entity.Transform != nil {
+			px, py = entity.Transform.X+float64(config.TileSize)/2, entity.Transform.Y+float64(config.TileSize)/2
+			found = true
+			break
+		}
+	}
+	if !found {
+		return
+	}
+
+	for _, entity := range state.Entities {
+		def, ok := groundItemDef(entity)
+		if !ok || entity.Transform == nil || entity.Sprite == nil || !filter.AutoPickup(def) {
+			continue
+		}
+		if item := entity.Item; item.OwnerTimer > 0 && item.OwnerID != 0 && item.OwnerID != playerID {
+			continue // Someone else's loot for now
+		}
+		center := geom.Square(entity.Transform.X, entity.Transform.Y, entity.Sprite.Width).Center()
+		if !geom.Within(center.X, center.Y, px, py, config.GroundItemPickupDist) || s.pickupAsked[entity.ID] > 0 {
+			continue
+		}
+		if s.pickupAsked == nil {
+			s.pickupAsked = make(map[ecs.Entity]float64)
+		}
+		s.pickupAsked[entity.ID] = config.AutoPickupRetry
+		s.Client.SendAutoPickup(entity.ID)
+	}
+}
@@ -0,0 +1,133 @@
+package systems
+
+import (
+	"bytes"
+	"log"
+
+	"henry/pkg/client/assets"
+	"henry/pkg/network"
+	"henry/pkg/shared/config"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// MusicSystem is the music director. It plays the current zone's playlist: exploration
+// tracks one after another, or the combat or boss track while the server says hostiles
+// are after the player, crossfading whenever the choice changes.
+type MusicSystem struct {
+	Client *network.NetworkClient
+	Muted  bool
+
+	context *audio.Context
+	current *musicVoice   // Fading in (or playing)
+	fading  []*musicVoice // Fading out, closed once silent
+	explore int           // Exploration track of the zone's playlist to play next
+}
+
+// musicVoice is one track being played
+type musicVoice struct {
+	track  string
+	player *audio.Player
+	level  float64 // Fade level (0-1), scaled by config.MusicVolume
+}
+
+func NewMusicSystem(client *network.NetworkClient) *MusicSystem {
+	return &MusicSystem{
+		Client:  client,
+		context: audio.NewContext(config.MusicSampleRate),
+	}
+}
+
+// Update picks the track for the player's situation and advances the fades. Call once
+// per frame while in game.
+func (s *MusicSystem) Update() {
+	playlist := assets.Playlist(s.Client.GetZone().Music)
+	combat := s.Client.GetCombat()
+	track, loops := "", true
+	switch {
+	case combat.Boss:
+		track = playlist.Boss
+	case combat.InCombat:
+		track = playlist.Combat
+	case len(playlist.Explore) > 0:
+		track, loops = playlist.Explore[s.explore%len(playlist.Explore)], false
+		if s.current != nil && s.current.track == track && !s.current.player.IsPlaying() {
+			// Finished: on to the next one
+			s.explore++
+			track = playlist.Explore[s.explore%len(playlist.Explore)]
+			s.fadeOut()
+		}
+	}
+	if s.current == nil || s.current.track != track {
+		s.fadeOut()
+		s.play(track, loops)
+	}
+
+	step := 1 / (config.MusicFadeTime * float64(ebiten.TPS()))
+	if s.current != nil {
+		s.current.level = min(1, s.current.level+step)
+		s.setVolume(s.current)
+	}
+	live := s.fading[:0]
+	for _, v := range s.fading {
+		if v.level = max(0, v.level-step); v.level == 0 {
+			v.player.Close()
+			continue
+		}
+		s.setVolume(v)
+		live = append(live, v)
+	}
+	s.fading = live
+}
+
+// ToggleMute silences the music or brings it back
+func (s *MusicSystem) ToggleMute() {
+	s.Muted = !s.Muted
+}
+
+// Stop cuts the music (logging out)
+func (s *MusicSystem) Stop() {
+	s.fadeOut()
+	for _, v := range s.fading {
+		v.player.Close()
+	}
+	s.fading = nil
+}
+
+// play starts a track silent, to fade in. Combat tracks loop; exploration tracks end
+// and Update moves on to the next one.
+func (s *MusicSystem) play(track string, loops bool) {
+	pcm := assets.MusicTrack(track)
+	if pcm == nil {
+		return // Silence
+	}
+	var player *audio.Player
+	if loops {
+		var err error
+		if player, err = s.context.NewPlayer(audio.NewInfiniteLoop(bytes.NewReader(pcm), int64(len(pcm)))); err != nil {
+			log.Printf("Failed to play %s: %v", track, err)
+			return
+		}
+	} else {
+		player = s.context.NewPlayerFromBytes(pcm)
+	}
+	s.current = &musicVoice{track: track, player: player}
+	s.setVolume(s.current)
+	player.Play()
+}
+
+func (s *MusicSystem) fadeOut() {
+	if s.current != nil {
+		s.fading = append(s.fading, s.current)
+		s.current = nil
+	}
+}
+
+func (s *MusicSystem) setVolume(v *musicVoice) {
+	if s.Muted {
+		v.player.SetVolume(0)
+		return
+	}
+	v.player.SetVolume(v.level * config.MusicVolume)
+}
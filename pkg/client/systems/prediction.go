@@ -0,0 +1,190 @@
+package systems
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"henry/pkg/network"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/geom"
+	protocol "henry/pkg/shared/network"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// PredictionSystem draws the local player a little ahead of the interpolated snapshots,
+// where their held movement keys are taking them, so moving feels immediate although
+// snapshots are a broadcast behind. When the server rejects a move (MoveCorrectionPacket)
+// the lead is eased away and held off for a moment, so walking into a wall doesn't jitter
+// between the prediction and the snapshots.
+type PredictionSystem struct {
+	Client *network.NetworkClient
+
+	input components.InputComponent
+	fresh bool // SetInput was called since the last Update
+
+	lead  geom.Vec2 // Offset drawn ahead of the snapshot position
+	from  geom.Vec2 // Lead when the last correction came in, eased away
+	blend float64   // Seconds of easing left
+	hold  float64   // Seconds before leading again
+
+	// Debug overlay
+	counts map[string]int // Corrections by reason
+	last   protocol.MoveCorrectionPacket
+	lastAt time.Time
+}
+
+func NewPredictionSystem(client *network.NetworkClient) *PredictionSystem {
+	return &PredictionSystem{Client: client, counts: make(map[string]int)}
+}
+
+// SetInput records the movement input sent this frame
+func (s *PredictionSystem) SetInput(input components.InputComponent) {
+	s.input = input
+	s.fresh = true
+}
+
+// Update reconciles with the server's corrections and moves the lead toward where the
+// input points. Call once per frame while in game, after input.
+func (s *PredictionSystem) Update() {
+	if !s.fresh {
+		s.input = components.InputComponent{} // Input captured by UI or a cutscene
+	}
+	s.fresh = false
+	dt := 1 / float64(ebiten.TPS())
+
+	for _, correction := range s.Client.PopCorrections() {
+		s.counts[correction.Reason]++
+		s.last, s.lastAt = correction, time.Now()
+		s.from = s.Offset()
+		s.lead = geom.Vec2{}
+		s.blend, s.hold = config.CorrectionBlendTime, config.MoveCorrectionInterval
+	}
+	s.blend = max(s.blend-dt, 0)
+	if s.hold > 0 {
+		s.hold -= dt
+		return
+	}
+	// Ease toward the wanted lead so starting and stopping don't pop
+	s.lead = s.lead.Lerp(s.wantedLead(), min(dt/config.PredictionLead, 1))
+}
+
+// Offset is how far from its snapshot position the local player is drawn
+func (s *PredictionSystem) Offset() geom.Vec2 {
+	offset := s.lead
+	if s.blend > 0 {
+		t := s.blend / config.CorrectionBlendTime
+		offset = offset.Add(s.from.Scale(t * t * (3 - 2*t)))
+	}
+	return offset
+}
+
+// Apply returns the state with the local player moved to its predicted position
+func (s *PredictionSystem) Apply(state protocol.StateUpdatePacket) protocol.StateUpdatePacket {
+	offset := s.Offset()
+	if offset == (geom.Vec2{}) {
+		return state
+	}
+	for i, e := range state.Entities {
+		if e.ID != s.Client.PlayerEntityID || e.Transform == nil {
+			continue
+		}
+		// Copy so the offset doesn't leak into the snapshot
+		entities := make([]protocol.EntitySnapshot, len(state.Entities))
+		copy(entities, state.Entities)
+		t := *e.Transform
+		t.X += offset.X
+		t.Y += offset.Y
+		entities[i].Transform = &t
+		state.Entities = entities
+		break
+	}
+	return state
+}
+
+// Error is how far (px) from the server's latest position the local player is drawn:
+// the interpolation lag plus the prediction's lead
+func (s *PredictionSystem) Error() float64 {
+	var drawn, actual *components.TransformComponent
+	for _, e := range s.Client.GetInterpolatedState().Entities {
+		if e.ID == s.Client.PlayerEntityID {
+			drawn = e.Transform
+		}
+	}
+	for _, e := range s.Client.GetState().Entities {
+		if e.ID == s.Client.PlayerEntityID {
+			actual = e.Transform
+		}
+	}
+	if drawn == nil || actual == nil {
+		return 0
+	}
+	offset := s.Offset()
+	return geom.Dist(drawn.X+offset.X, drawn.Y+offset.Y, actual.X, actual.Y)
+}
+
+// Reset drops the prediction and the correction stats (logging out)
+func (s *PredictionSystem) Reset() {
+	*s = PredictionSystem{Client: s.Client, counts: make(map[string]int)}
+}
+
+// DrawDebug lists the corrections received by reason and the latest one
+func (s *PredictionSystem) DrawDebug(screen *ebiten.Image, x, y int) {
+	reasons := make([]string, 0, len(s.counts))
+	for reason := range s.counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	msg := "Corrections:"
+	for _, reason := range reasons {
+		msg += fmt.Sprintf(" %s %d", reason, s.counts[reason])
+	}
+	if !s.lastAt.IsZero() {
+		msg += fmt.Sprintf("\nLast: %s at %.0f,%.0f (%.1fs ago)", s.last.Reason, s.last.X, s.last.Y, time.Since(s.lastAt).Seconds())
+	}
+	ebitenutil.DebugPrintAt(screen, msg, x, y)
+}
+
+// wantedLead is where the held keys would take the player in config.PredictionLead
+// seconds, at the speed the server moves them
+func (s *PredictionSystem) wantedLead() geom.Vec2 {
+	var dir geom.Vec2
+	if s.input.Up {
+		dir.Y--
+	}
+	if s.input.Down {
+		dir.Y++
+	}
+	if s.input.Left {
+		dir.X--
+	}
+	if s.input.Right {
+		dir.X++
+	}
+	if dir == (geom.Vec2{}) {
+		return dir
+	}
+
+	speed := 0.0 // Px per tick
+	for _, e := range s.Client.GetState().Entities {
+		if e.ID != s.Client.PlayerEntityID || e.Physics == nil {
+			continue
+		}
+		speed = e.Physics.Speed
+		if e.Stance != nil {
+			switch e.Stance.Stance {
+			case components.StanceRun:
+				speed *= config.RunSpeedMultiplier
+			case components.StanceSneak:
+				speed *= config.SneakSpeedMultiplier
+			case components.StanceDodge:
+				speed *= config.DodgeSpeedMultiplier
+			}
+		}
+		break
+	}
+	return dir.Normalize().Scale(speed * config.ServerTickRate * config.PredictionLead)
+}
@@ -1,25 +1,35 @@
 package systems
 
 import (
+	"fmt"
 	"image/color"
 	"math"
 
 	"henry/pkg/client/assets"
+	"henry/pkg/items"
 	"henry/pkg/network"
+	"henry/pkg/shared/components"
 	"henry/pkg/shared/config"
+	"henry/pkg/shared/geom"
+	protocol "henry/pkg/shared/network"
 	"henry/pkg/shared/world"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 type RenderSystem struct {
-	Client   *network.NetworkClient
-	UISystem *UISystem // Use UISystem
+	Client     *network.NetworkClient
+	UISystem   *UISystem // Use UISystem
+	Cutscene   *CutsceneSystem
+	Prediction *PredictionSystem
 
 	// Health Tracking for Dynamic Bars
 	HealthTrackers    map[uint64]*HealthTracker
 	AnimationTrackers map[uint64]*AnimationTracker
+
+	Particles []*Particle // Hit and death effects
 }
 
 type HealthTracker struct {
@@ -46,7 +56,10 @@ func NewRenderSystem(client *network.NetworkClient, uiSystem *UISystem) *RenderS
 }
 
 func (s *RenderSystem) Draw(screen *ebiten.Image) {
-	state := s.Client.GetState()
+	state := s.Client.GetInterpolatedState()
+	if s.Prediction != nil {
+		state = s.Prediction.Apply(state)
+	}
 	playerID := s.Client.PlayerEntityID
 
 	tileSize := float64(config.TileSize) // Should be 64.0
@@ -60,6 +73,11 @@ func (s *RenderSystem) Draw(screen *ebiten.Image) {
 			break
 		}
 	}
+	// A cutscene has the camera (and the screen instead of the HUD and UI)
+	cutscene := false
+	if s.Cutscene != nil {
+		camX, camY, cutscene = s.Cutscene.Camera(state, camX, camY)
+	}
 
 	// Draw Map
 	var width, height int
@@ -99,25 +117,16 @@ func (s *RenderSystem) Draw(screen *ebiten.Image) {
 
 				// 1. Draw Ground Layer
 				var c color.Color
-				var tileType world.TileType
-
-				if s.Client.WorldMap != nil {
-					tileType = s.Client.WorldMap.Tiles[y][x].Type
-				} else {
-					m := s.Client.GetMap()
-					if len(m.Tiles) > y*width+x {
-						tileType = world.TileType(m.Tiles[y*width+x])
-					}
-				}
+				tileType := s.tileTypeAt(x, y)
 
 				switch tileType {
 				case world.TileGrass:
 					c = color.RGBA{34, 139, 34, 255}
 				case world.TileGrassFlowers:
 					c = color.RGBA{50, 205, 50, 255}
-				case world.TileWater, world.TileWaterShallow:
+				case world.TileWater, world.TileWaterShallow, world.TileDock:
 					c = color.RGBA{0, 191, 255, 255}
-				case world.TileWaterDeep:
+				case world.TileWaterDeep, world.TileBridge:
 					c = color.RGBA{0, 0, 139, 255}
 				case world.TileSand:
 					c = color.RGBA{238, 214, 175, 255}
@@ -129,17 +138,28 @@ func (s *RenderSystem) Draw(screen *ebiten.Image) {
 					c = color.RGBA{105, 105, 105, 255}
 				case world.TileWoodFloor:
 					c = color.RGBA{160, 82, 45, 255}
+				case world.TileFarmland:
+					c = color.RGBA{92, 60, 30, 255}
 				case world.TileSnow:
 					c = color.RGBA{255, 250, 250, 255}
 				case world.TileIce:
 					c = color.RGBA{176, 224, 230, 255}
 				case world.TileLava:
 					c = color.RGBA{255, 69, 0, 255}
+				case world.TileWaterEdgeTop, world.TileWaterEdgeBottom, world.TileWaterEdgeLeft, world.TileWaterEdgeRight,
+					world.TileWaterCornerTL, world.TileWaterCornerTR, world.TileWaterCornerBL, world.TileWaterCornerBR:
+					c = color.RGBA{34, 139, 34, 255} // Shore, water drawn over its solid cells below
 				default:
 					c = color.RGBA{0, 100, 0, 255} // Fallback
 				}
 				// Draw Rect
 				vector.DrawFilledRect(screen, float32(tx-camX), float32(ty-camY), float32(tileSize), float32(tileSize), c, false)
+				if tileType != world.TileTree {
+					s.drawPartialWater(screen, tileType.CollisionMask(), tx-camX, ty-camY)
+				}
+				if tileType == world.TileBridge || tileType == world.TileDock {
+					s.drawPlanks(screen, x, y, tx-camX, ty-camY)
+				}
 
 				// 2. Draw Objects Layer
 				var obj int
@@ -154,19 +174,54 @@ func (s *RenderSystem) Draw(screen *ebiten.Image) {
 					}
 				}
 
-				if obj > 0 {
-					treeColor := color.RGBA{1, 50, 32, 200}
-					margin := float32(tileSize * 0.1)
-					vector.DrawFilledRect(screen, float32(tx-camX)+margin, float32(ty-camY)+margin, float32(tileSize)-margin*2, float32(tileSize)-margin*2, treeColor, true)
+				if kind, stage, ok := world.CropFromObject(obj); ok {
+					s.drawCrop(screen, kind, stage, tx-camX, ty-camY)
+				} else if obj > 0 {
+					s.drawDecoration(screen, obj, x, y, tx-camX, ty-camY)
 				}
 			}
 		}
 	}
 
+	// Duel Ring (positions are tile top-left, like the server's ring check)
+	if duel := s.Client.GetDuel(); duel.Active {
+		half := float64(config.TileSize) / 2
+		ringColor := color.RGBA{255, 200, 0, 200}
+		if duel.Countdown > 0 {
+			ringColor = color.RGBA{200, 200, 200, 160}
+		}
+		vector.StrokeCircle(screen, float32(duel.CenterX+half-camX), float32(duel.CenterY+half-camY), float32(duel.Radius), 3, ringColor, true)
+	}
+
+	// Land Claims (faint ring around each claim banner)
+	for _, entity := range state.Entities {
+		if entity.Structure == nil || !entity.Structure.Claim || entity.Transform == nil {
+			continue
+		}
+		half := float64(config.TileSize) / 2
+		vector.StrokeCircle(screen, float32(entity.Transform.X+half-camX), float32(entity.Transform.Y+half-camY), float32(config.ClaimRadius), 1, color.RGBA{200, 30, 60, 90}, true)
+	}
+
+	// Build Mode Preview (hovered tile)
+	if s.UISystem.BuildItemID != "" && !s.UISystem.IsMouseOverUI() {
+		mx, my := ebiten.CursorPosition()
+		tileSize := float64(config.TileSize)
+		tx := math.Floor((float64(mx)+camX)/tileSize) * tileSize
+		ty := math.Floor((float64(my)+camY)/tileSize) * tileSize
+		vector.DrawFilledRect(screen, float32(tx-camX), float32(ty-camY), float32(tileSize), float32(tileSize), color.RGBA{255, 255, 255, 60}, true)
+		vector.StrokeRect(screen, float32(tx-camX), float32(ty-camY), float32(tileSize), float32(tileSize), 2, color.RGBA{255, 255, 255, 180}, true)
+	}
+
 	dt := 1.0 / 60.0
 
+	// Spectators only see through their camera, their own body isn't drawn
+	spectating := s.Client.GetSpectate().Active
+
 	// Draw Entities
 	for _, entity := range state.Entities {
+		if spectating && entity.ID == playerID {
+			continue
+		}
 		if entity.Transform != nil {
 			x := float64(entity.Transform.X - camX)
 			y := float64(entity.Transform.Y - camY)
@@ -189,11 +244,7 @@ func (s *RenderSystem) Draw(screen *ebiten.Image) {
 				}
 
 				// Motion Check (Squared Distance)
-				dx := entity.Transform.X - tracker.LastX
-				dy := entity.Transform.Y - tracker.LastY
-				distSq := dx*dx + dy*dy
-
-				if distSq > 0.01 {
+				if !geom.Within(tracker.LastX, tracker.LastY, entity.Transform.X, entity.Transform.Y, 0.1) {
 					tracker.IsMoving = true
 					tracker.MoveDecayTimer = 0.2
 				} else {
@@ -207,8 +258,21 @@ func (s *RenderSystem) Draw(screen *ebiten.Image) {
 				tracker.LastY = entity.Transform.Y
 
 				desiredAnim := "breathing-idle"
+				frameDuration := 0.1
 				if tracker.IsMoving {
 					desiredAnim = "walk"
+					// Stances without their own frames speed up or slow down the walk
+					if entity.Stance != nil && entity.Stance.Stance != components.StanceWalk {
+						if assets.HasCharacterAnimation(charName, entity.Stance.Stance) {
+							desiredAnim = entity.Stance.Stance
+						} else if entity.Stance.Stance == components.StanceDodge {
+							frameDuration = 0.04
+						} else if entity.Stance.Stance == components.StanceRun {
+							frameDuration = 0.06
+						} else {
+							frameDuration = 0.16
+						}
+					}
 				}
 
 				if tracker.CurrentAnimation != desiredAnim {
@@ -219,7 +283,6 @@ func (s *RenderSystem) Draw(screen *ebiten.Image) {
 
 				// Advance Frame
 				tracker.Timer += dt
-				frameDuration := 0.1
 				if tracker.Timer >= frameDuration {
 					tracker.Timer = 0
 					tracker.FrameIndex++
@@ -236,6 +299,12 @@ func (s *RenderSystem) Draw(screen *ebiten.Image) {
 					// Sprite 56x56
 					// Offset = (64 - 56) / 2 = 4
 					opts.GeoM.Translate(x+4, y+4)
+					if entity.Stance != nil && entity.Stance.Stance == components.StanceSneak {
+						opts.ColorScale.ScaleAlpha(0.6)
+					}
+					if hasStatus(entity.Status, components.StatusInvisible) {
+						opts.ColorScale.ScaleAlpha(0.35) // Only its owner sees it at all
+					}
 					screen.DrawImage(img, opts)
 					spriteDrawn = true
 				}
@@ -259,11 +328,17 @@ func (s *RenderSystem) Draw(screen *ebiten.Image) {
 			}
 
 			// Fallback
-			if !spriteDrawn && entity.Sprite != nil {
+			if !spriteDrawn && entity.Sprite != nil && entity.Item != nil {
+				s.drawGroundItem(screen, entity, x, y)
+			} else if !spriteDrawn && entity.Sprite != nil {
 				c := entity.Sprite.Color
 				vector.DrawFilledRect(screen, float32(x), float32(y), float32(entity.Sprite.Width), float32(entity.Sprite.Height), c, true)
 			}
 
+			if entity.Appearance != nil && charName != "" {
+				s.drawAppearance(screen, entity.Appearance, x, y)
+			}
+
 			// Health Bar
 			if entity.Stats != nil {
 				tracker, exists := s.HealthTrackers[uint64(entity.ID)]
@@ -298,6 +373,47 @@ func (s *RenderSystem) Draw(screen *ebiten.Image) {
 					vector.DrawFilledRect(screen, barX, float32(y)-10, barWidth*healthPct, 5, color.RGBA{0, 255, 0, 255}, true)
 				}
 			}
+
+			// Status Icons (above the health bar)
+			if entity.Status != nil && len(entity.Status.Effects) > 0 {
+				s.drawStatusIcons(screen, entity.Status, x, y)
+			}
+
+			// Waypoint Name
+			if entity.Waypoint != nil {
+				nameX := int(x) + config.TileSize/2 - len(entity.Waypoint.Name)*3
+				ebitenutil.DebugPrintAt(screen, entity.Waypoint.Name, nameX, int(y)-16)
+			}
+
+			// Overhead Markers (Quest/Vendor)
+			if entity.Marker != nil && entity.Marker.Flags != 0 {
+				s.drawMarker(screen, entity.Marker.Flags, x, y)
+			}
+		}
+	}
+
+	// Hit Sparks / Death Puffs
+	s.drawEffects(screen, dt, camX, camY)
+
+	// Speech Bubbles (on top of everything in the world)
+	s.drawChatBubbles(screen, state, dt, camX, camY)
+
+	// Day/Night Tint
+	if daylight := world.DaylightFactor(state.WorldHour); daylight < 1 {
+		alpha := uint8((1 - daylight) * 140)
+		vector.DrawFilledRect(screen, 0, 0, 800, 600, color.NRGBA{10, 10, 40, alpha}, false)
+	}
+
+	if cutscene {
+		s.Cutscene.Draw(screen)
+		return
+	}
+
+	// HUD (own health and stamina)
+	for _, entity := range state.Entities {
+		if entity.ID == playerID {
+			s.drawHUD(screen, entity)
+			break
 		}
 	}
 
@@ -305,6 +421,323 @@ func (s *RenderSystem) Draw(screen *ebiten.Image) {
 	s.UISystem.Draw(screen)
 }
 
+// drawHUD renders the player's resource bars and combat XP in the top left corner, below
+// the FPS counter
+func (s *RenderSystem) drawHUD(screen *ebiten.Image, player protocol.EntitySnapshot) {
+	x, y := float32(10), float32(40)
+	if player.Stats != nil && player.Stats.MaxHealth > 0 {
+		drawHUDBar(screen, x, y, player.Stats.CurrentHealth/player.Stats.MaxHealth, color.RGBA{200, 40, 40, 255})
+		y += 14
+	}
+	if player.Stance != nil {
+		drawHUDBar(screen, x, y, player.Stance.Stamina/config.MaxStamina, color.RGBA{230, 200, 40, 255})
+		y += 14
+	}
+	if player.Stats != nil && player.Stats.Level > 0 {
+		level := player.Stats.Level
+		progress := 1.0 // Max level: the bar stays full
+		if level < config.MaxCombatLevel {
+			start, next := components.XPForLevel(level), components.XPForLevel(level+1)
+			progress = float64(player.Stats.XP-start) / float64(next-start)
+		}
+		drawHUDBar(screen, x, y, progress, color.RGBA{120, 90, 230, 255})
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Lv %d", level), int(x)+156, int(y)-3)
+	}
+}
+
+// drawHUDBar draws one 150x10 bar filled to pct (0-1)
+func drawHUDBar(screen *ebiten.Image, x, y float32, pct float64, fill color.RGBA) {
+	pct = math.Max(0, math.Min(1, pct))
+	vector.DrawFilledRect(screen, x-1, y-1, 152, 12, color.RGBA{0, 0, 0, 200}, false)
+	vector.DrawFilledRect(screen, x, y, 150*float32(pct), 10, fill, false)
+}
+
+// tileTypeAt returns the ground tile at (x, y), from the full map or the last map sync
+func (s *RenderSystem) tileTypeAt(x, y int) world.TileType {
+	if s.Client.WorldMap != nil {
+		m := s.Client.WorldMap
+		if x < 0 || y < 0 || x >= m.Width || y >= m.Height {
+			return world.TileGrass
+		}
+		return m.Tiles[y][x].Type
+	}
+	m := s.Client.GetMap()
+	if x < 0 || y < 0 || x >= m.Width || y >= m.Height || len(m.Tiles) <= y*m.Width+x {
+		return world.TileGrass
+	}
+	return world.TileType(m.Tiles[y*m.Width+x])
+}
+
+// drawPlanks draws a bridge or dock tile's boards over the water under it, with a railing
+// on every side that faces open water
+func (s *RenderSystem) drawPlanks(screen *ebiten.Image, x, y int, sx, sy float64) {
+	size := float32(config.TileSize)
+	px, py := float32(sx), float32(sy)
+	vector.DrawFilledRect(screen, px, py, size, size, color.RGBA{150, 105, 60, 255}, false)
+	for i := float32(1); i < 4; i++ {
+		vector.StrokeLine(screen, px+size*i/4, py, px+size*i/4, py+size, 1, color.RGBA{100, 65, 35, 255}, false)
+	}
+
+	rail := color.RGBA{90, 55, 25, 255}
+	isWater := func(t world.TileType) bool {
+		return t == world.TileWaterDeep || t == world.TileWaterShallow || t == world.TileWater
+	}
+	if isWater(s.tileTypeAt(x, y-1)) {
+		vector.DrawFilledRect(screen, px, py, size, 4, rail, false)
+	}
+	if isWater(s.tileTypeAt(x, y+1)) {
+		vector.DrawFilledRect(screen, px, py+size-4, size, 4, rail, false)
+	}
+	if isWater(s.tileTypeAt(x-1, y)) {
+		vector.DrawFilledRect(screen, px, py, 4, size, rail, false)
+	}
+	if isWater(s.tileTypeAt(x+1, y)) {
+		vector.DrawFilledRect(screen, px+size-4, py, 4, size, rail, false)
+	}
+}
+
+// drawPartialWater paints the solid collision cells of shore tiles as water, so what
+// looks like water is exactly what blocks movement
+func (s *RenderSystem) drawPartialWater(screen *ebiten.Image, mask uint16, x, y float64) {
+	if mask == 0 || mask == 0xFFFF {
+		return
+	}
+	cell := float64(config.TileSize) / world.CollisionCells
+	for i := 0; i < world.CollisionCells*world.CollisionCells; i++ {
+		if mask&(1<<i) != 0 {
+			cx := x + float64(i%world.CollisionCells)*cell
+			cy := y + float64(i/world.CollisionCells)*cell
+			vector.DrawFilledRect(screen, float32(cx), float32(cy), float32(cell), float32(cell), color.RGBA{0, 191, 255, 255}, false)
+		}
+	}
+}
+
+// flowerColors tint meadow flowers
+var flowerColors = []color.RGBA{
+	{240, 220, 60, 255},
+	{230, 90, 150, 255},
+	{250, 250, 250, 255},
+	{150, 110, 230, 255},
+}
+
+// drawDecoration renders a map object (tree, rock, flowers, reeds, seasonal pumpkins and
+// lanterns). Tile coordinates pick
+// a stable variation so neighbouring flowers and reeds don't look stamped.
+func (s *RenderSystem) drawDecoration(screen *ebiten.Image, obj, tileX, tileY int, x, y float64) {
+	size := float32(config.TileSize)
+	px, py := float32(x), float32(y)
+	variant := (tileX*7 + tileY*13) & 0xff
+
+	switch obj {
+	case world.ObjectRock:
+		r := size * (0.22 + float32(variant%3)*0.03)
+		vector.DrawFilledCircle(screen, px+size/2, py+size*0.55, r, color.RGBA{120, 120, 115, 255}, true)
+		vector.DrawFilledCircle(screen, px+size/2-r*0.3, py+size*0.55-r*0.3, r*0.4, color.RGBA{160, 160, 150, 255}, true)
+	case world.ObjectFlowers:
+		for i := 0; i < 5; i++ {
+			v := variant + i*37
+			fx := px + size*(0.15+float32(v%7)*0.1)
+			fy := py + size*(0.15+float32((v/7)%7)*0.1)
+			vector.DrawFilledCircle(screen, fx, fy, 3, flowerColors[(variant+i)%len(flowerColors)], true)
+		}
+	case world.ObjectReeds:
+		for i := 0; i < 6; i++ {
+			rx := px + size*(0.15+float32(i)*0.14)
+			top := py + size*(0.25+float32((variant+i*3)%4)*0.07)
+			vector.StrokeLine(screen, rx, py+size*0.9, rx, top, 2, color.RGBA{90, 120, 50, 255}, true)
+			vector.DrawFilledRect(screen, rx-1.5, top, 3, 6, color.RGBA{110, 75, 40, 255}, true)
+		}
+	case world.ObjectPumpkin:
+		r := size * 0.25
+		vector.DrawFilledCircle(screen, px+size/2, py+size*0.6, r, color.RGBA{230, 120, 20, 255}, true)
+		vector.StrokeLine(screen, px+size/2, py+size*0.6-r*0.9, px+size/2, py+size*0.6+r*0.9, 1.5, color.RGBA{170, 80, 10, 255}, true)
+		vector.DrawFilledRect(screen, px+size/2-2, py+size*0.6-r-5, 4, 6, color.RGBA{80, 110, 40, 255}, true)
+	case world.ObjectLantern:
+		vector.StrokeLine(screen, px+size/2, py+size*0.95, px+size/2, py+size*0.3, 2, color.RGBA{60, 50, 40, 255}, true)
+		vector.DrawFilledCircle(screen, px+size/2, py+size*0.3, 9, color.RGBA{255, 200, 80, 60}, true)
+		vector.DrawFilledRect(screen, px+size/2-4, py+size*0.3-5, 8, 10, color.RGBA{255, 210, 90, 255}, true)
+	default:
+		// Trees (and any unknown obstacle)
+		treeColor := color.RGBA{1, 50, 32, 200}
+		margin := size * 0.1
+		vector.DrawFilledRect(screen, px+margin, py+margin, size-margin*2, size-margin*2, treeColor, true)
+	}
+}
+
+// cropRipeColors tints ripe crops by kind (see items.CropStats.Kind)
+var cropRipeColors = []color.RGBA{
+	{230, 200, 60, 255}, // Wheat
+	{240, 120, 20, 255}, // Carrot
+}
+
+// drawCrop renders a crop on its tile, growing taller with each stage
+func (s *RenderSystem) drawCrop(screen *ebiten.Image, kind, stage int, x, y float64) {
+	tileSize := float64(config.TileSize)
+	c := color.RGBA{60, 160, 40, 255}
+	if stage >= world.CropRipe && kind < len(cropRipeColors) {
+		c = cropRipeColors[kind]
+	}
+	// Three rows of plants, from seedlings (stage 0) to full height
+	height := tileSize * 0.15 * float64(stage+1)
+	for row := 0; row < 3; row++ {
+		px := x + tileSize*(0.2+0.3*float64(row))
+		vector.DrawFilledRect(screen, float32(px), float32(y+tileSize*0.85-height), float32(tileSize*0.1), float32(height), c, false)
+	}
+}
+
+// drawMarker renders overhead indicators centered above a 64x64 entity tile
+// statusColors tells effects apart in the icon row
+var statusColors = map[string]color.RGBA{
+	components.BuffDamage:      {220, 60, 60, 255},
+	components.BuffDamageTaken: {150, 150, 150, 255},
+	components.BuffSpeed:       {80, 220, 80, 255},
+	components.StatusSlow:      {80, 140, 255, 255},
+	components.StatusBurn:      {255, 140, 30, 255},
+	components.StatusShield:    {200, 200, 255, 255},
+	components.StatusInvisible: {150, 60, 150, 255},
+}
+
+// drawStatusIcons draws a row of small squares over the health bar, one per effect.
+// Each drains from the top as the effect runs out; burns show their stack count.
+func (s *RenderSystem) drawStatusIcons(screen *ebiten.Image, status *components.StatusEffectComponent, x, y float64) {
+	const size, gap = 8, 2
+	n := len(status.Effects)
+	left := float32(x) + float32(config.TileSize)/2 - float32(n*(size+gap)-gap)/2
+	top := float32(y) - 22
+	for i, effect := range status.Effects {
+		c, ok := statusColors[effect.Kind]
+		if !ok {
+			c = color.RGBA{200, 200, 200, 255}
+		}
+		ix := left + float32(i*(size+gap))
+		vector.DrawFilledRect(screen, ix, top, size, size, color.RGBA{30, 30, 30, 200}, true)
+		remaining := float32(1)
+		if effect.Duration > 0 {
+			remaining = float32(math.Min(effect.TimeLeft/effect.Duration, 1))
+		}
+		vector.DrawFilledRect(screen, ix, top+size*(1-remaining), size, size*remaining, c, true)
+		if effect.Kind == components.StatusBurn && effect.Stacks > 1 {
+			ebitenutil.DebugPrintAt(screen, fmt.Sprint(effect.Stacks), int(ix)+1, int(top)-14)
+		}
+	}
+}
+
+// drawChatBubbles counts down the speech bubbles and draws them above their speakers
+// (above the status icons). Bubbles of speakers out of sight just wait or expire.
+func (s *RenderSystem) drawChatBubbles(screen *ebiten.Image, state protocol.StateUpdatePacket, dt, camX, camY float64) {
+	bubbles := s.UISystem.chatBubbles
+	for id, bubble := range bubbles {
+		if bubble.TimeLeft -= dt; bubble.TimeLeft <= 0 {
+			delete(bubbles, id)
+		}
+	}
+	if len(bubbles) == 0 {
+		return
+	}
+	const lineHeight, pad = 14, 4
+	for _, entity := range state.Entities {
+		bubble, ok := bubbles[entity.ID]
+		if !ok || entity.Transform == nil {
+			continue
+		}
+		width := 0
+		for _, line := range bubble.Lines {
+			width = max(width, len(line)*6)
+		}
+		w := float32(width + 2*pad)
+		h := float32(len(bubble.Lines)*lineHeight + 2*pad)
+		left := float32(entity.Transform.X-camX) + float32(config.TileSize)/2 - w/2
+		top := float32(entity.Transform.Y-camY) - 30 - h
+		vector.DrawFilledRect(screen, left, top, w, h, color.RGBA{20, 20, 30, 210}, true)
+		vector.DrawFilledRect(screen, left+w/2-3, top+h, 6, 4, color.RGBA{20, 20, 30, 210}, true)
+		for i, line := range bubble.Lines {
+			ebitenutil.DebugPrintAt(screen, line, int(left)+pad, int(top)+pad+i*lineHeight-1)
+		}
+	}
+}
+
+// hasStatus reports whether a snapshot's effects include a kind
+func hasStatus(status *components.StatusEffectComponent, kind string) bool {
+	if status == nil {
+		return false
+	}
+	for _, effect := range status.Effects {
+		if effect.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// drawAppearance draws the look of each worn item (or wardrobe cosmetic) over a
+// character's sprite
+func (s *RenderSystem) drawAppearance(screen *ebiten.Image, look *components.AppearanceComponent, x, y float64) {
+	cx := float32(x + float64(config.TileSize)/2)
+	top := float32(y)
+	for slot, itemID := range look.Slots {
+		def, ok := items.Get(itemID)
+		if itemID == "" || !ok || def.Look.A == 0 {
+			continue
+		}
+		c := def.Look
+		tint := color.RGBA{c.R, c.G, c.B, 110} // Cloth over the sprite, still showing it
+		switch slot {
+		case components.SlotHead:
+			vector.DrawFilledRect(screen, cx-8, top+6, 16, 8, c, true)
+			vector.StrokeLine(screen, cx-12, top+14, cx+12, top+14, 2, c, true)
+		case components.SlotNeck:
+			vector.StrokeLine(screen, cx-7, top+25, cx+7, top+25, 3, c, true)
+		case components.SlotBack:
+			vector.DrawFilledRect(screen, cx-16, top+24, 4, 22, c, true)
+			vector.DrawFilledRect(screen, cx+12, top+24, 4, 22, c, true)
+		case components.SlotBody:
+			vector.DrawFilledRect(screen, cx-10, top+26, 20, 14, tint, true)
+		case components.SlotLegs:
+			vector.DrawFilledRect(screen, cx-9, top+40, 18, 10, tint, true)
+		case components.SlotFeet:
+			vector.DrawFilledRect(screen, cx-9, top+52, 7, 4, c, true)
+			vector.DrawFilledRect(screen, cx+2, top+52, 7, 4, c, true)
+		case components.SlotHands:
+			vector.DrawFilledCircle(screen, cx-13, top+38, 3, c, true)
+			vector.DrawFilledCircle(screen, cx+13, top+38, 3, c, true)
+		case components.SlotWeapon:
+			vector.StrokeLine(screen, cx+14, top+44, cx+22, top+24, 3, c, true)
+		case components.SlotShield:
+			vector.DrawFilledCircle(screen, cx-16, top+36, 6, c, true)
+		}
+	}
+}
+
+func (s *RenderSystem) drawMarker(screen *ebiten.Image, flags int, x, y float64) {
+	// Above the health bar (y-10)
+	cx := x + float64(config.TileSize)/2
+	markerY := y - 28
+
+	if flags&components.MarkerQuestTurnIn != 0 {
+		// Turn-in takes priority over new quests
+		vector.DrawFilledCircle(screen, float32(cx), float32(markerY+8), 8, color.RGBA{40, 40, 40, 200}, true)
+		ebitenutil.DebugPrintAt(screen, "?", int(cx)-3, int(markerY))
+	} else if flags&components.MarkerQuestAvailable != 0 {
+		vector.DrawFilledCircle(screen, float32(cx), float32(markerY+8), 8, color.RGBA{40, 40, 40, 200}, true)
+		ebitenutil.DebugPrintAt(screen, "!", int(cx)-3, int(markerY))
+	} else if flags&components.MarkerVendor != 0 {
+		// Coin Icon
+		vector.DrawFilledCircle(screen, float32(cx), float32(markerY+8), 6, color.RGBA{255, 215, 0, 255}, true)
+		vector.StrokeCircle(screen, float32(cx), float32(markerY+8), 6, 1, color.RGBA{160, 120, 0, 255}, true)
+	} else if flags&components.MarkerArena != 0 {
+		// Crossed Swords
+		vector.DrawFilledCircle(screen, float32(cx), float32(markerY+8), 8, color.RGBA{120, 20, 20, 220}, true)
+		vector.StrokeLine(screen, float32(cx-5), float32(markerY+3), float32(cx+5), float32(markerY+13), 2, color.White, true)
+		vector.StrokeLine(screen, float32(cx+5), float32(markerY+3), float32(cx-5), float32(markerY+13), 2, color.White, true)
+	} else if flags&components.MarkerHouse != 0 {
+		// House: roof over a square
+		vector.DrawFilledCircle(screen, float32(cx), float32(markerY+8), 8, color.RGBA{40, 90, 40, 220}, true)
+		vector.StrokeLine(screen, float32(cx-5), float32(markerY+8), float32(cx), float32(markerY+3), 2, color.White, true)
+		vector.StrokeLine(screen, float32(cx), float32(markerY+3), float32(cx+5), float32(markerY+8), 2, color.White, true)
+		vector.StrokeRect(screen, float32(cx-3), float32(markerY+8), 6, 5, 1.5, color.White, true)
+	}
+}
+
 func getDirectionFromAngle(angle float64) string {
 	// angle is radians.
 	// math.Atan2 returns -PI to PI.
@@ -0,0 +1,99 @@
+package systems
+
+import (
+	"fmt"
+	"slices"
+
+	"henry/pkg/items"
+	"henry/pkg/ui"
+)
+
+func (s *UISystem) InitShopUI() {
+	w := ui.NewWindow(220, 100, 360, 400, "Shop")
+	w.Visible = false
+	s.ShopWindow = w
+	s.Manager.AddElement(w)
+}
+
+// updateShop opens or closes the shop window as the server says, and rebuilds it when a
+// trade changes the player's gold or bag
+func (s *UISystem) updateShop() {
+	if shop, changed := s.Client.PopShop(); changed {
+		s.shop = shop
+		if shop.NPC == 0 {
+			s.ShopWindow.Visible = false
+			return
+		}
+		s.refreshShop()
+		s.ShopWindow.Visible = true
+		return
+	}
+	if !s.ShopWindow.Visible {
+		return
+	}
+	inv := s.Client.GetInventory()
+	if inv.Gold != s.shopInv.Gold || !slices.Equal(inv.Slots, s.shopInv.Slots) {
+		s.refreshShop()
+	}
+}
+
+// refreshShop rebuilds the shop window: the player's gold, a buy button per item in
+// stock, then a sell button per stack the vendor will take
+func (s *UISystem) refreshShop() {
+	w := s.ShopWindow
+	shop := s.shop
+	inv := s.Client.GetInventory()
+	s.shopInv = inv
+	w.Title = shop.Name
+	w.Children = nil
+	w.ContentHeight = 0
+
+	w.AddChild(ui.NewLabel(10, 10, fmt.Sprintf("Gold: %d", inv.Gold)))
+	yOffset := 35.0
+	w.AddChild(ui.NewLabel(10, yOffset, "For sale"))
+	yOffset += 20
+	for _, entry := range shop.Stock {
+		itemID := entry.ItemID
+		w.AddChild(ui.NewLabel(10, yOffset+6, fmt.Sprintf("%s - %dg", itemName(itemID), entry.Price)))
+		w.AddChild(ui.NewButton(w.Width-90, yOffset, 70, 25, "Buy", func() {
+			s.Client.SendShopBuy(shop.NPC, itemID, 1)
+		}))
+		yOffset += 30
+	}
+
+	yOffset += 10
+	w.AddChild(ui.NewLabel(10, yOffset, "Sell"))
+	yOffset += 20
+	selling := false
+	for _, slot := range inv.Slots {
+		price, ok := shop.SellPrices[slot.ItemID]
+		if !ok || slot.Quantity <= 0 || slot.Locked {
+			continue
+		}
+		instanceID := slot.InstanceID
+		label := fmt.Sprintf("%s x%d - %dg each", itemName(slot.ItemID), slot.Quantity, price)
+		w.AddChild(ui.NewLabel(10, yOffset+6, label))
+		w.AddChild(ui.NewButton(w.Width-90, yOffset, 70, 25, "Sell", func() {
+			s.Client.SendShopSell(shop.NPC, instanceID, 1)
+		}))
+		yOffset += 30
+		selling = true
+	}
+	if !selling {
+		w.AddChild(ui.NewLabel(10, yOffset, "Nothing the vendor wants."))
+	}
+
+	w.FooterHeight = 40
+	closeBtn := ui.NewSecondaryButton(10, w.Height-55, w.Width-20, 30, "Close", func() {
+		w.Visible = false
+	})
+	w.AddChildOption(closeBtn, true)
+}
+
+// itemName is an item's display name (its ID if it isn't registered)
+func itemName(itemID string) string {
+	if def, ok := items.Get(itemID); ok && def.Name != "" {
+		return def.Name
+	}
+	return itemID
+}
@@ -0,0 +1,147 @@
+package systems
+
+import (
+	"fmt"
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// hoveredItem returns the item under the mouse in the inventory (either tab), hotbar or
+// equipment window, and whether it's one being worn
+func (s *UISystem) hoveredItem(mx, my int) (def items.ItemDefinition, worn, ok bool) {
+	itemID := ""
+	switch {
+	case s.Inventory.Visible && s.InvWidget.IsHovered(mx, my):
+		if i := s.InvWidget.GetSlotAt(mx, my); i != -1 {
+			itemID = s.InvWidget.Slots[i]
+		}
+	case s.Inventory.Visible && s.KeyringWidget.IsHovered(mx, my):
+		if i := s.KeyringWidget.GetSlotAt(mx, my); i != -1 {
+			itemID = s.KeyringWidget.Slots[i]
+		}
+	case s.BindWindow.Visible && s.BindWidget.IsHovered(mx, my):
+		if i := s.BindWidget.GetSlotAt(mx, my); i != -1 {
+			itemID = s.BindWidget.Slots[i]
+		}
+	case s.EquipWindow.Visible && s.EquipWidget.IsHovered(mx, my):
+		itemID = s.EquipWidget.Slots[s.EquipWidget.GetSlotAt(mx, my)]
+		worn = true
+	}
+	def, ok = items.Get(itemID)
+	return def, worn, ok
+}
+
+// drawItemTooltip describes the hovered item: name and rarity, description and stats.
+// Holding Shift over gear that isn't worn compares it with what's worn in its slot.
+func (s *UISystem) drawItemTooltip(screen *ebiten.Image) {
+	if s.DragSourceWidget != nil || s.ContextMenu.Visible {
+		return
+	}
+	mx, my := ebiten.CursorPosition()
+	def, worn, ok := s.hoveredItem(mx, my)
+	if !ok {
+		return
+	}
+
+	lines := []string{def.Name, def.Rarity.String()}
+	lines = append(lines, wrapText(def.Description, 36)...)
+	lines = append(lines, itemStats(def)...)
+
+	if equippable(def) && !worn {
+		if !ebiten.IsKeyPressed(ebiten.KeyShift) {
+			lines = append(lines, "", "Hold Shift to compare")
+		} else if current, ok := items.Get(s.EquipWidget.Slots[def.EquipmentSlot]); !ok {
+			lines = append(lines, "", "Nothing worn in this slot")
+		} else if deltas := statDeltas(current, def); len(deltas) == 0 {
+			lines = append(lines, "", "Same stats as "+current.Name)
+		} else {
+			lines = append(lines, "", "Versus "+current.Name+":")
+			lines = append(lines, deltas...)
+		}
+	}
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, len(line))
+	}
+	tipW, tipH := float64(width*6+16), float64(len(lines)*16+8)
+	x, y := float64(mx)+15, float64(my)+15
+	if x+tipW > 800 {
+		x = float64(mx) - tipW - 5
+	}
+	if y+tipH > 600 {
+		y = 600 - tipH
+	}
+
+	ebitenutil.DrawRect(screen, x, y, tipW, tipH, color.RGBA{0, 0, 0, 220})
+	ebitenutil.DrawRect(screen, x, y, 4, tipH, def.Rarity.Color())
+	for i, line := range lines {
+		ebitenutil.DebugPrintAt(screen, line, int(x+10), int(y+4)+i*16)
+	}
+}
+
+// equippable reports whether an item goes in an equipment slot (cosmetics only add a look)
+func equippable(def items.ItemDefinition) bool {
+	return def.EquipmentSlot >= 0 && def.EquipmentSlot < len(slotNames) && def.Type != items.ItemTypeCosmetic
+}
+
+// itemStats lists what an item does when worn or wielded
+func itemStats(def items.ItemDefinition) []string {
+	var lines []string
+	if equippable(def) {
+		lines = append(lines, "Slot: "+slotNames[def.EquipmentSlot])
+	}
+	if w := def.WeaponStats; w != nil {
+		lines = append(lines,
+			fmt.Sprintf("Damage %g", w.Damage),
+			fmt.Sprintf("Range %g", w.Range),
+			fmt.Sprintf("Attacks every %gs", w.Cooldown))
+	}
+	for _, a := range attributeStats(def.Attributes) {
+		if a.value != 0 {
+			lines = append(lines, fmt.Sprintf("%+d%s", a.value, a.name))
+		}
+	}
+	return lines
+}
+
+// statDeltas lists how wearing next instead of current changes each stat that differs
+func statDeltas(current, next items.ItemDefinition) []string {
+	var lines []string
+	var cur, nxt components.AttackComponent
+	if current.WeaponStats != nil {
+		cur = *current.WeaponStats
+	}
+	if next.WeaponStats != nil {
+		nxt = *next.WeaponStats
+	}
+	if d := nxt.Damage - cur.Damage; d != 0 {
+		lines = append(lines, fmt.Sprintf("%+g Damage", d))
+	}
+	if d := nxt.Range - cur.Range; d != 0 {
+		lines = append(lines, fmt.Sprintf("%+g Range", d))
+	}
+	if d := nxt.Cooldown - cur.Cooldown; d != 0 {
+		lines = append(lines, fmt.Sprintf("%+.2gs between attacks", d))
+	}
+	was := attributeStats(current.Attributes)
+	for i, a := range attributeStats(next.Attributes) {
+		if d := a.value - was[i].value; d != 0 {
+			lines = append(lines, fmt.Sprintf("%+d%s", d, a.name))
+		}
+	}
+	return lines
+}
+
+type namedStat struct {
+	name  string
+	value int
+}
+
+func attributeStats(a components.Attributes) []namedStat {
+	return []namedStat{{" STR", a.Str}, {" DEX", a.Dex}, {" INT", a.Int}, {"% haste", a.Haste}}
+}
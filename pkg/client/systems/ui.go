@@ -2,9 +2,13 @@ package systems
 
 import (
 	"fmt"
+	"henry/pkg/items"
 	"henry/pkg/network"
 	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
 	protocol "henry/pkg/shared/network"
+	"henry/pkg/shared/world"
 	"henry/pkg/ui"
 	"image/color"
 	"strings"
@@ -15,9 +19,10 @@ import (
 )
 
 type UISystem struct {
-	Client  *network.NetworkClient
-	Manager *ui.Manager
-	Keys    map[string]ebiten.Key
+	Client     *network.NetworkClient
+	Manager    *ui.Manager
+	Keys       map[string]ebiten.Key
+	Prediction *PredictionSystem // Correction stats for the F2 overlay
 
 	// Windows
 	LoginWindow       *ui.Window
@@ -27,6 +32,24 @@ type UISystem struct {
 	EquipWindow       *ui.Window
 	SpellsWindow      *ui.Window
 	KeybindingsWindow *ui.Window
+	TravelWindow      *ui.Window
+	BugReportWindow   *ui.Window
+	LootWindow        *ui.Window
+	DuelInviteWindow  *ui.Window
+	DuelWindow        *ui.Window // Shown while dueling (opponent, countdown, forfeit)
+	ArenaWindow       *ui.Window // Shown while queued for or playing an arena match
+	SpectateWindow    *ui.Window // Shown while spectating (target, free camera, stop)
+	FishingWindow     *ui.Window // Fishing minigame (wait for the bite, then hook)
+	HouseWindow       *ui.Window // Invites guests to the player's house
+	MailWindow        *ui.Window // Unclaimed mail (filled by refreshMail)
+	DialogueWindow    *ui.Window // Conversation with an NPC (filled by refreshDialogue)
+	ShopWindow        *ui.Window // Vendor's stock and what it buys (filled by refreshShop)
+	WardrobeWindow    *ui.Window // Collected looks and collections (filled by refreshWardrobe)
+	CharacterWindow   *ui.Window // Character sheet: playtime, skills, leaderboard (filled by refreshCharacter)
+	ChatWindow        *ui.Window // Chat history and input (shown while logged in)
+	HelpWindow        *ui.Window // Searchable help topics (filled by refreshHelp)
+	LootFilterWindow  *ui.Window // Loot filter toggles (see lootfilter.go)
+	AwayWindow        *ui.Window // What happened while offline, shown at login (see away.go)
 	ContextMenu       *ui.ContextMenu
 
 	// Callbacks
@@ -35,6 +58,7 @@ type UISystem struct {
 	// Widgets
 	BindWidget     *ui.InventoryWidget
 	InvWidget      *ui.InventoryWidget
+	KeyringWidget  *ui.InventoryWidget // Keys and quest items, the inventory's second tab
 	SpellsWidget   *ui.SpellsWidget
 	EquipWidget    *ui.EquipmentWidget
 	BindWindow     *ui.Window
@@ -42,8 +66,22 @@ type UISystem struct {
 		Action string
 		Btn    *ui.Button
 	}
-	LoginInputs  []*ui.TextInput
-	SignupInputs []*ui.TextInput
+	LoginInputs     []*ui.TextInput
+	LoginStatus     *ui.Label // Login queue position on a full server
+	SignupInputs    []*ui.TextInput
+	BugReportInput  *ui.TextInput
+	LootItemLabel   *ui.Label
+	LootTimerLabel  *ui.Label
+	DuelInviteLabel *ui.Label
+	DuelStatusLabel *ui.Label
+	ArenaLabel      *ui.Label
+	SpectateLabel   *ui.Label
+	FishStatusLabel *ui.Label
+	FishSkillLabel  *ui.Label
+	HouseLabel      *ui.Label
+	HouseInput      *ui.TextInput
+	ChatInput       *ui.TextInput
+	HelpInput       *ui.TextInput
 
 	// State
 	selectedSlotA  int
@@ -51,6 +89,8 @@ type UISystem struct {
 	RebindAction   string
 	ActiveSpellID  string
 	BindingSpellID string // Spell ID waiting to be bound
+	BuildItemID    string // Placeable item being positioned (build mode)
+	Username       string // Logged-in player
 
 	// Drag State
 	DragSourceWidget ui.Element
@@ -70,8 +110,68 @@ type UISystem struct {
 		ShowFPS  bool
 		ShowInfo bool
 		ShowLogs bool
+		ShowNet  bool
 	}
 	LogHistory []string
+
+	// Banner (Announcements, Zone Changes)
+	BannerText  string
+	BannerTimer float64
+	bannerQueue []queuedBanner // Shown after the current banner expires
+
+	// Need/Greed Rolls (first one is shown in LootWindow)
+	lootRolls []pendingLootRoll
+
+	// Duel Challenges (first one is shown in DuelInviteWindow)
+	duelInvites []pendingDuelInvite
+
+	// Open vendor and the inventory its window was built from (see shop.go)
+	shop    protocol.ShopOpenPacket
+	shopInv protocol.InventorySyncPacket
+
+	// Collected looks (see wardrobe.go)
+	wardrobe       protocol.WardrobeSyncPacket
+	wardrobeLoaded bool // The first sync after login isn't logged as new looks
+
+	// Chat (wrapped lines, oldest first; see chat.go)
+	chatLines       []string
+	lastWhisperFrom string // Target of /r
+	chatBubbles     map[ecs.Entity]*chatBubble
+	bubblesBtn      *ui.Button
+	HideChatBubbles bool // Player turned speech bubbles off (saved with the account)
+
+	// Loot filter (saved with the account; see lootfilter.go)
+	LootFilter     items.LootFilter
+	lootFilterBtns []*ui.Button
+
+	// Inventory tabs (see keyring.go)
+	bagTab, keyringTab *ui.Button
+
+	// Help (see help.go)
+	helpTopics []protocol.HelpTopic
+	helpTopic  string // Topic being read ("" = the topic list)
+	helpQuery  string // Search the window was last built for
+
+	// Fishing (seconds left to hook, counted down locally from the bite)
+	fishState      string
+	fishWindowLeft float64
+}
+
+type pendingDuelInvite struct {
+	Invite   protocol.DuelInvitePacket
+	TimeLeft float64
+}
+
+type pendingLootRoll struct {
+	Roll     protocol.LootRollPacket
+	TimeLeft float64
+}
+
+const visibleLogLines = 10 // Log lines drawn by the F3 overlay
+
+type queuedBanner struct {
+	Text     string
+	Duration float64
 }
 
 func NewUISystem(client *network.NetworkClient, keys map[string]ebiten.Key) *UISystem {
@@ -110,15 +210,16 @@ func (s *UISystem) Init() {
 	s.Manager.AddElement(s.EquipWindow)
 
 	// --- Inventory ---
-	// 5x5 Grid, 40px slots
+	// 5x5 Grid, 40px slots, under a row of tabs
 	// Window Width: 5 * 40 = 200
-	// Window Height: 5 * 40 + 20 (title) = 220
-	// Pos: Bottom Right (800x600) -> X: 600-200=400? No, 800-200-10=590. Y: 600-220-10=370.
-	s.InvWidget = ui.NewInventoryWidget(0, 0, 5, 5, 40)
+	// Window Height: 5 * 40 + 22 (tabs) + 20 (title) = 242
+	// Pos: Bottom Right (800x600) -> X: 800-200-10=590. Y: 600-242-10=348.
+	s.InvWidget = ui.NewInventoryWidget(0, inventoryTabHeight, 5, 5, 40)
 	s.InvWidget.SlotOffset = 0 // Using direct 0-indexed slots matching server component
-	s.Inventory = ui.NewWindow(590, 370, 200, 220, "Inventory")
+	s.Inventory = ui.NewWindow(590, 348, 200, 242, "Inventory")
 	s.Inventory.ShowScrollbar = false
 	s.Inventory.AddChild(s.InvWidget)
+	s.initKeyringTab()
 	s.Inventory.Visible = false
 	s.Manager.AddElement(s.Inventory)
 
@@ -149,6 +250,7 @@ func (s *UISystem) Init() {
 				s.SpellsWidget.Cooldowns[k] = v
 			}
 		}
+		s.SpellsWidget.CooldownReduction = s.Client.CooldownReduction
 		s.Client.Mutex.RUnlock()
 	} else {
 		// Default unlocks for testing if empty/nil (or handle new player defaults in server)
@@ -289,6 +391,7 @@ func (s *UISystem) Init() {
 				s.SpellsWidget.Cooldowns[k] = v
 			}
 		}
+		s.SpellsWidget.CooldownReduction = s.Client.CooldownReduction
 		s.Client.Mutex.RUnlock()
 	} else {
 		// Default unlocks for testing if empty/nil (or handle new player defaults in server)
@@ -319,7 +422,7 @@ func (s *UISystem) Init() {
 	s.InitKeybindingsUI()
 
 	// --- Game Menu ---
-	s.GameMenu = ui.NewWindow(300, 200, 200, 200, "Menu")
+	s.GameMenu = ui.NewWindow(300, 130, 200, 410, "Menu")
 
 	resumeBtn := ui.NewButton(10, 30, 180, 30, "Resume", func() {
 		s.GameMenu.Visible = false
@@ -333,9 +436,108 @@ func (s *UISystem) Init() {
 	})
 	s.GameMenu.AddChild(kbBtn)
 
+	s.bubblesBtn = ui.NewButton(10, 110, 180, 30, "", func() {
+		s.SetChatBubbles(s.HideChatBubbles)
+	})
+	s.refreshBubblesButton()
+	s.GameMenu.AddChild(s.bubblesBtn)
+
+	bugBtn := ui.NewButton(10, 150, 180, 30, "Report Bug", func() {
+		s.GameMenu.Visible = false
+		s.BugReportInput.Text = ""
+		s.BugReportInput.Focused = true
+		s.BugReportWindow.Visible = true
+	})
+	s.GameMenu.AddChild(bugBtn)
+
+	mailBtn := ui.NewButton(10, 190, 180, 30, "Mailbox", func() {
+		s.GameMenu.Visible = false
+		s.OpenMail()
+	})
+	s.GameMenu.AddChild(mailBtn)
+
+	charBtn := ui.NewButton(10, 230, 180, 30, "Character", func() {
+		s.GameMenu.Visible = false
+		s.Client.SendCharacter()
+		s.CharacterWindow.Visible = true
+	})
+	s.GameMenu.AddChild(charBtn)
+
+	wardrobeBtn := ui.NewButton(10, 270, 180, 30, "Wardrobe", func() {
+		s.GameMenu.Visible = false
+		s.OpenWardrobe()
+	})
+	s.GameMenu.AddChild(wardrobeBtn)
+
+	helpBtn := ui.NewButton(10, 310, 180, 30, "Help", func() {
+		s.GameMenu.Visible = false
+		s.OpenHelp("")
+	})
+	s.GameMenu.AddChild(helpBtn)
+
+	lootBtn := ui.NewButton(10, 350, 180, 30, "Loot Filter", func() {
+		s.GameMenu.Visible = false
+		s.LootFilterWindow.Visible = true
+	})
+	s.GameMenu.AddChild(lootBtn)
+
 	s.GameMenu.Visible = false
 	s.Manager.AddElement(s.GameMenu)
 
+	// --- Bug Report ---
+	s.InitBugReportUI()
+
+	// --- Loot Rolls ---
+	s.InitLootUI()
+
+	// --- Duels ---
+	s.InitDuelUI()
+
+	// --- Arena ---
+	s.InitArenaUI()
+
+	// --- Spectating ---
+	s.InitSpectateUI()
+	s.InitFishingUI()
+
+	// --- Housing ---
+	s.InitHouseUI()
+
+	// --- Chat ---
+	s.InitChatUI()
+
+	// --- NPC Dialogue ---
+	s.InitDialogueUI()
+
+	// --- Vendors ---
+	s.InitShopUI()
+
+	// --- Wardrobe ---
+	s.InitWardrobeUI()
+
+	// --- Loot Filter ---
+	s.InitLootFilterUI()
+
+	// --- Mail (Filled by refreshMail) ---
+	s.MailWindow = ui.NewWindow(240, 150, 320, 300, "Mailbox")
+	s.MailWindow.Visible = false
+	s.Manager.AddElement(s.MailWindow)
+
+	// --- Character Sheet (Filled by refreshCharacter) ---
+	s.CharacterWindow = ui.NewWindow(250, 120, 300, 360, "Character")
+	s.CharacterWindow.Visible = false
+	s.Manager.AddElement(s.CharacterWindow)
+
+	// --- Fast Travel (Filled by OpenTravelWindow) ---
+	s.TravelWindow = ui.NewWindow(280, 150, 240, 300, "Waypoints")
+	s.TravelWindow.Visible = false
+	s.Manager.AddElement(s.TravelWindow)
+
+	s.InitAwayUI()
+
+	// --- Help (on top, linked from the other windows' "?" buttons) ---
+	s.InitHelpUI()
+
 	s.AddLog("Welcome to Henry!")
 }
 
@@ -349,7 +551,7 @@ func (s *UISystem) InitKeybindingsUI() {
 		"Keybindings",
 	)
 
-	actions := []string{"Menu", "Up", "Down", "Left", "Right", "Run", "Inventory", "Equipment", "Spells", "Bind",
+	actions := []string{"Menu", "Up", "Down", "Left", "Right", "Run", "Sneak", "Dodge", "Inventory", "Equipment", "Spells", "Bind", "Music", "Interact",
 		"Hotbar1", "Hotbar2", "Hotbar3", "Hotbar4", "Hotbar5", "Hotbar6", "Hotbar7", "Hotbar8", "Hotbar9", "Hotbar0"}
 	yOffset := 30.0
 
@@ -454,6 +656,9 @@ func (s *UISystem) InitAuthUI() {
 	})
 	loginWin.AddChild(btnToSignup)
 
+	s.LoginStatus = ui.NewLabel(20, 255, "")
+	loginWin.AddChild(s.LoginStatus)
+
 	s.LoginWindow = loginWin
 	s.Manager.AddElement(loginWin)
 
@@ -525,12 +730,82 @@ func (s *UISystem) ResetUI() {
 	if s.KeybindingsWindow != nil {
 		s.KeybindingsWindow.Visible = false
 	}
+	if s.TravelWindow != nil {
+		s.TravelWindow.Visible = false
+	}
+	if s.BugReportWindow != nil {
+		s.BugReportWindow.Visible = false
+	}
+	if s.LootWindow != nil {
+		s.LootWindow.Visible = false
+	}
+	if s.DuelInviteWindow != nil {
+		s.DuelInviteWindow.Visible = false
+	}
+	if s.DuelWindow != nil {
+		s.DuelWindow.Visible = false
+	}
+	if s.ArenaWindow != nil {
+		s.ArenaWindow.Visible = false
+	}
+	if s.SpectateWindow != nil {
+		s.SpectateWindow.Visible = false
+	}
+	if s.FishingWindow != nil {
+		s.FishingWindow.Visible = false
+	}
+	if s.HouseWindow != nil {
+		s.HouseWindow.Visible = false
+	}
+	if s.MailWindow != nil {
+		s.MailWindow.Visible = false
+	}
+	if s.DialogueWindow != nil {
+		s.DialogueWindow.Visible = false
+	}
+	if s.ShopWindow != nil {
+		s.ShopWindow.Visible = false
+	}
+	if s.WardrobeWindow != nil {
+		s.WardrobeWindow.Visible = false
+	}
+	if s.LootFilterWindow != nil {
+		s.LootFilterWindow.Visible = false
+	}
+	if s.AwayWindow != nil {
+		s.AwayWindow.Visible = false
+	}
+	s.wardrobe = protocol.WardrobeSyncPacket{}
+	s.wardrobeLoaded = false
+	if s.HelpWindow != nil {
+		s.HelpWindow.Visible = false
+		s.HelpInput.Focused = false
+	}
+	s.helpTopics = nil
+	if s.CharacterWindow != nil {
+		s.CharacterWindow.Visible = false
+	}
+	if s.ChatWindow != nil {
+		s.ChatWindow.Visible = false
+		s.ChatInput.Text = ""
+		s.ChatInput.Focused = false
+		s.chatLines = nil
+		s.lastWhisperFrom = ""
+		s.chatBubbles = nil
+		s.refreshChat()
+	}
 	if s.ContextMenu != nil {
 		s.ContextMenu.Visible = false
 	}
 	if s.LoginWindow != nil {
 		s.LoginWindow.Visible = true
 	}
+	s.BuildItemID = ""
+	s.fishState = ""
+	s.BannerTimer = 0
+	s.bannerQueue = nil
+	s.lootRolls = nil
+	s.duelInvites = nil
 }
 
 func (s *UISystem) RegisterLoginCallback(cb func(user, pass string, isSignup bool)) {
@@ -544,12 +819,21 @@ func (s *UISystem) HideLogin() {
 	if s.SignupWindow != nil {
 		s.SignupWindow.Visible = false
 	}
+	if s.ChatWindow != nil {
+		s.ChatWindow.Visible = true
+	}
 	// BindWindow visibility is handled by ApplyOpenMenus
 }
 
 func (s *UISystem) Update() {
 	s.Manager.Update()
 
+	if queue := s.Client.GetLoginQueue(); queue.Position > 0 {
+		s.LoginStatus.Text = fmt.Sprintf("Server full - queue position %d of %d", queue.Position, queue.Size)
+	} else {
+		s.LoginStatus.Text = ""
+	}
+
 	// Determine Active Inputs
 	var activeInputs []*ui.TextInput
 	var isSignup bool
@@ -663,9 +947,74 @@ func (s *UISystem) Update() {
 		return // If rebind mode, skip other updates like inventory sync?
 	}
 
+	// Chat (before the bug report, whose Enter mustn't also open chat)
+	s.updateChat()
+
+	// Bug Report (Enter sends)
+	if s.IsTyping() && (inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter)) {
+		s.SendBugReport(s.BugReportInput.Text)
+		s.BugReportWindow.Visible = false
+	}
+
+	// Server Announcements
+	for _, msg := range s.Client.PopAnnouncements() {
+		s.AddLog(msg)
+		s.ShowBanner(msg, 5.0)
+	}
+
+	// Zone Transitions
+	if zone, changed := s.Client.PopZoneChange(); changed && zone.Name != "" {
+		msg := "Now entering " + zone.Name
+		if zone.MaxLevel > 0 {
+			msg += fmt.Sprintf(" (Lv %d-%d)", zone.MinLevel, zone.MaxLevel)
+		}
+		if zone.PvP {
+			msg += " [PvP]"
+		}
+		s.AddLog(msg)
+		s.ShowBanner(msg, 3.0)
+	}
+	// Loot Rolls
+	for _, roll := range s.Client.PopLootRolls() {
+		s.AddLog(fmt.Sprintf("Roll for %dx %s", roll.Quantity, roll.ItemName))
+		s.lootRolls = append(s.lootRolls, pendingLootRoll{Roll: roll, TimeLeft: roll.Duration})
+	}
+	s.updateLootRolls()
+	// Duels
+	for _, invite := range s.Client.PopDuelInvites() {
+		s.AddLog(invite.ChallengerName + " challenges you to a duel")
+		s.duelInvites = append(s.duelInvites, pendingDuelInvite{Invite: invite, TimeLeft: invite.Timeout})
+	}
+	s.updateDuel()
+	s.updateArena()
+	s.updateSpectate()
+	s.updateFishing()
+	s.updateHouse()
+	s.updateDialogue()
+	s.updateShop()
+	s.updateWardrobe()
+	s.updateHelp()
+	s.updateAway()
+	if mailbox, changed := s.Client.PopMailbox(); changed && s.MailWindow.Visible {
+		s.refreshMail(mailbox)
+	}
+	if sheet, changed := s.Client.PopCharacterSheet(); changed {
+		s.refreshCharacter(sheet)
+	}
+
+	if s.BannerTimer > 0 {
+		s.BannerTimer -= 1.0 / 60.0
+	} else if len(s.bannerQueue) > 0 {
+		next := s.bannerQueue[0]
+		s.bannerQueue = s.bannerQueue[1:]
+		s.ShowBanner(next.Text, next.Duration)
+	}
+
 	// Sync Data
 	inv := s.Client.GetInventory()
 	if inv.Capacity > 0 {
+		s.Inventory.Title = fmt.Sprintf("Inventory - %dg", inv.Gold)
+
 		// Sync Inventory Widget
 		for i := range s.InvWidget.Slots {
 			if i < len(inv.Slots) {
@@ -679,12 +1028,15 @@ func (s *UISystem) Update() {
 		// Clear first
 		for i := range s.InvWidget.Slots {
 			s.InvWidget.Slots[i] = ""
+			s.InvWidget.Locked[i] = false
 		}
 		for _, v := range inv.Slots {
 			if v.Index >= 0 && v.Index < len(s.InvWidget.Slots) {
 				s.InvWidget.Slots[v.Index] = v.ItemID
+				s.InvWidget.Locked[v.Index] = v.Locked
 			}
 		}
+		s.syncKeyring(inv.Keyring)
 	}
 
 	// Sync Hotbar
@@ -907,10 +1259,35 @@ func (s *UISystem) Draw(screen *ebiten.Image) {
 
 		ebitenutil.DebugPrintAt(screen, msg, int(drawX+5), int(drawY+2))
 	}
+	s.drawItemTooltip(screen)
 
+	s.DrawBanner(screen)
 	s.DrawDebug(screen)
 }
 
+// ShowBanner displays a centered message at the top of the screen for a few seconds.
+// If another banner is still showing, the message waits its turn.
+func (s *UISystem) ShowBanner(msg string, duration float64) {
+	if s.BannerTimer > 0 && s.BannerText != "" {
+		s.bannerQueue = append(s.bannerQueue, queuedBanner{Text: msg, Duration: duration})
+		return
+	}
+	s.BannerText = msg
+	s.BannerTimer = duration
+}
+
+func (s *UISystem) DrawBanner(screen *ebiten.Image) {
+	if s.BannerTimer <= 0 || s.BannerText == "" {
+		return
+	}
+
+	width := float64(len(s.BannerText)*6 + 20)
+	x := (800 - width) / 2
+	y := 40.0
+	ebitenutil.DrawRect(screen, x, y, width, 24, color.RGBA{0, 0, 0, 180})
+	ebitenutil.DebugPrintAt(screen, s.BannerText, int(x+10), int(y+4))
+}
+
 func (s *UISystem) ToggleDebug(mode int) {
 	switch mode {
 	case 1:
@@ -919,23 +1296,45 @@ func (s *UISystem) ToggleDebug(mode int) {
 		s.DebugFlags.ShowInfo = !s.DebugFlags.ShowInfo
 	case 3:
 		s.DebugFlags.ShowLogs = !s.DebugFlags.ShowLogs
+	case 4:
+		s.DebugFlags.ShowNet = !s.DebugFlags.ShowNet
 	}
 
-	// Sync with server
+	s.syncSettings()
+}
+
+// ApplySettings restores the toggles saved with the account (sent at login)
+func (s *UISystem) ApplySettings(settings map[string]bool) {
+	s.DebugFlags.ShowFPS = settings["ShowFPS"]
+	s.DebugFlags.ShowInfo = settings["ShowInfo"]
+	s.DebugFlags.ShowLogs = settings["ShowLogs"]
+	s.DebugFlags.ShowNet = settings["ShowNet"]
+	s.HideChatBubbles = settings["HideChatBubbles"]
+	s.refreshBubblesButton()
+	s.LootFilter = items.LootFilterFrom(settings)
+	s.refreshLootFilter()
+}
+
+// syncSettings saves the toggles with the account on the server
+func (s *UISystem) syncSettings() {
 	if s.Client != nil {
 		settings := map[string]bool{
-			"ShowFPS":  s.DebugFlags.ShowFPS,
-			"ShowInfo": s.DebugFlags.ShowInfo,
-			"ShowLogs": s.DebugFlags.ShowLogs,
+			"ShowFPS":         s.DebugFlags.ShowFPS,
+			"ShowInfo":        s.DebugFlags.ShowInfo,
+			"ShowLogs":        s.DebugFlags.ShowLogs,
+			"ShowNet":         s.DebugFlags.ShowNet,
+			"HideChatBubbles": s.HideChatBubbles,
 		}
+		s.LootFilter.Save(settings)
 		s.Client.SendUpdateDebugSettings(settings)
 	}
 }
 
 func (s *UISystem) AddLog(msg string) {
+	// Keep enough history for bug reports, only the tail is drawn
 	s.LogHistory = append(s.LogHistory, msg)
-	if len(s.LogHistory) > 10 {
-		s.LogHistory = s.LogHistory[len(s.LogHistory)-10:]
+	if len(s.LogHistory) > config.BugReportLogLines {
+		s.LogHistory = s.LogHistory[len(s.LogHistory)-config.BugReportLogLines:]
 	}
 }
 
@@ -952,20 +1351,52 @@ func (s *UISystem) DrawDebug(screen *ebiten.Image) {
 		// Calculate X based on screen width (800) and text length approx
 		x := 800 - 120
 		ebitenutil.DebugPrintAt(screen, msg, x+5, 5)
+
+		// Movement the server rejected, and why
+		if s.Prediction != nil {
+			s.Prediction.DrawDebug(screen, 800-250, 25)
+		}
+	}
+
+	// F4: Network (Left, under FPS)
+	if s.DebugFlags.ShowNet {
+		s.drawNetDebug(screen, 5, 40)
 	}
 
 	// F3: Logs (Bottom Left)
 	if s.DebugFlags.ShowLogs {
-		logH := len(s.LogHistory) * 15
+		lines := s.LogHistory
+		if len(lines) > visibleLogLines {
+			lines = lines[len(lines)-visibleLogLines:]
+		}
+		logH := len(lines) * 15
 		logY := 600 - logH - 5
 
-		for _, log := range s.LogHistory {
+		for _, log := range lines {
 			ebitenutil.DebugPrintAt(screen, log, 5, logY)
 			logY += 15
 		}
 	}
 }
 
+// drawNetDebug shows the connection's health: snapshot flow, bandwidth, latency and how
+// far the drawn player is from the server's
+func (s *UISystem) drawNetDebug(screen *ebiten.Image, x, y int) {
+	stats := s.Client.NetStats()
+	rtt := "-"
+	if stats.RTT > 0 {
+		rtt = fmt.Sprintf("%dms", stats.RTT.Milliseconds())
+	}
+	msg := fmt.Sprintf("Snapshots: %.1f/s (want %d)\nIn: %.1f KB/s  Out: %.1f KB/s\nRTT: %s  Interp: %dms\nDropped: %d  Late: %d",
+		stats.SnapshotRate, config.SnapshotRate, stats.BytesIn/1024, stats.BytesOut/1024,
+		rtt, stats.InterpDelay.Milliseconds(), stats.Dropped, stats.Late)
+	if s.Prediction != nil {
+		msg += fmt.Sprintf("\nPrediction error: %.1fpx", s.Prediction.Error())
+	}
+	ebitenutil.DrawRect(screen, float64(x-3), float64(y-3), 230, 82, color.RGBA{0, 0, 0, 150})
+	ebitenutil.DebugPrintAt(screen, msg, x, y)
+}
+
 // Helpers for InputSystem
 func (s *UISystem) ToggleInventory() {
 	s.Inventory.Visible = !s.Inventory.Visible
@@ -976,6 +1407,535 @@ func (s *UISystem) ToggleInventory() {
 	s.SyncUIState()
 }
 
+func (s *UISystem) InitBugReportUI() {
+	w := ui.NewWindow(200, 180, 400, 170, "Report a Bug")
+
+	w.AddChild(ui.NewLabel(20, 10, "What went wrong? (position, FPS and recent"))
+	w.AddChild(ui.NewLabel(20, 25, "log lines are attached automatically)"))
+
+	s.BugReportInput = ui.NewTextInput(20, 50, 360, 30, "Describe the issue...")
+	w.AddChild(s.BugReportInput)
+
+	sendBtn := ui.NewButton(20, 100, 170, 30, "Send", func() {
+		s.SendBugReport(s.BugReportInput.Text)
+		w.Visible = false
+	})
+	w.AddChild(sendBtn)
+
+	cancelBtn := ui.NewSecondaryButton(210, 100, 170, 30, "Cancel", func() {
+		w.Visible = false
+	})
+	w.AddChild(cancelBtn)
+
+	w.Visible = false
+	s.BugReportWindow = w
+	s.Manager.AddElement(w)
+}
+
+// SendBugReport captures client state (position, FPS, version, recent logs) and sends it with the description
+func (s *UISystem) SendBugReport(description string) {
+	report := protocol.BugReportPacket{
+		Description: strings.TrimSpace(description),
+		FPS:         ebiten.ActualFPS(),
+		Version:     config.BuildString(),
+		Logs:        append([]string(nil), s.LogHistory...),
+	}
+
+	state := s.Client.GetState()
+	for _, e := range state.Entities {
+		if e.ID == s.Client.PlayerEntityID && e.Transform != nil {
+			report.X, report.Y = e.Transform.X, e.Transform.Y
+			break
+		}
+	}
+
+	s.Client.SendBugReport(report)
+	s.AddLog("Bug report sent")
+}
+
+func (s *UISystem) InitLootUI() {
+	w := ui.NewWindow(280, 90, 240, 130, "Loot Roll")
+	w.ShowScrollbar = false
+
+	s.LootItemLabel = ui.NewLabel(10, 10, "")
+	w.AddChild(s.LootItemLabel)
+	s.LootTimerLabel = ui.NewLabel(10, 28, "")
+	w.AddChild(s.LootTimerLabel)
+
+	w.AddChild(ui.NewButton(10, 55, 68, 30, "Need", func() { s.AnswerLootRoll("need") }))
+	w.AddChild(ui.NewButton(86, 55, 68, 30, "Greed", func() { s.AnswerLootRoll("greed") }))
+	w.AddChild(ui.NewSecondaryButton(162, 55, 68, 30, "Pass", func() { s.AnswerLootRoll("pass") }))
+
+	w.Visible = false
+	s.LootWindow = w
+	s.Manager.AddElement(w)
+}
+
+// updateLootRolls counts down pending rolls and shows the oldest one.
+// Expired rolls are dropped (the server treats them as pass).
+func (s *UISystem) updateLootRolls() {
+	for i := range s.lootRolls {
+		s.lootRolls[i].TimeLeft -= 1.0 / 60.0
+	}
+	for len(s.lootRolls) > 0 && s.lootRolls[0].TimeLeft <= 0 {
+		s.lootRolls = s.lootRolls[1:]
+	}
+
+	if len(s.lootRolls) == 0 {
+		s.LootWindow.Visible = false
+		return
+	}
+	current := s.lootRolls[0]
+	s.LootItemLabel.Text = fmt.Sprintf("%dx %s", current.Roll.Quantity, current.Roll.ItemName)
+	s.LootTimerLabel.Text = fmt.Sprintf("%.0fs left", current.TimeLeft)
+	if len(s.lootRolls) > 1 {
+		s.LootTimerLabel.Text += fmt.Sprintf(" (+%d more)", len(s.lootRolls)-1)
+	}
+	s.LootWindow.Visible = true
+}
+
+// AnswerLootRoll sends need/greed/pass for the roll shown in the loot window
+func (s *UISystem) AnswerLootRoll(choice string) {
+	if len(s.lootRolls) == 0 {
+		return
+	}
+	current := s.lootRolls[0]
+	s.lootRolls = s.lootRolls[1:]
+	s.Client.SendLootChoice(current.Roll.RollID, choice)
+	s.AddLog(fmt.Sprintf("%s: %s", strings.ToUpper(choice[:1])+choice[1:], current.Roll.ItemName))
+}
+
+func (s *UISystem) InitDuelUI() {
+	w := ui.NewWindow(280, 90, 240, 130, "Duel Challenge")
+	w.ShowScrollbar = false
+	s.DuelInviteLabel = ui.NewLabel(10, 10, "")
+	w.AddChild(s.DuelInviteLabel)
+	w.AddChild(ui.NewButton(10, 55, 105, 30, "Accept", func() { s.AnswerDuelInvite(true) }))
+	w.AddChild(ui.NewSecondaryButton(125, 55, 105, 30, "Decline", func() { s.AnswerDuelInvite(false) }))
+	w.Visible = false
+	s.DuelInviteWindow = w
+	s.Manager.AddElement(w)
+
+	d := ui.NewWindow(590, 10, 200, 90, "Duel")
+	d.ShowScrollbar = false
+	s.DuelStatusLabel = ui.NewLabel(10, 10, "")
+	d.AddChild(s.DuelStatusLabel)
+	d.AddChild(ui.NewSecondaryButton(10, 35, 180, 25, "Forfeit", func() {
+		s.Client.SendDuel("forfeit", 0)
+	}))
+	d.Visible = false
+	s.DuelWindow = d
+	s.Manager.AddElement(d)
+}
+
+// updateDuel shows the oldest pending challenge and, while dueling, the duel window
+func (s *UISystem) updateDuel() {
+	for i := range s.duelInvites {
+		s.duelInvites[i].TimeLeft -= 1.0 / 60.0
+	}
+	for len(s.duelInvites) > 0 && s.duelInvites[0].TimeLeft <= 0 {
+		s.duelInvites = s.duelInvites[1:]
+	}
+	if len(s.duelInvites) == 0 {
+		s.DuelInviteWindow.Visible = false
+	} else {
+		current := s.duelInvites[0]
+		s.DuelInviteLabel.Text = fmt.Sprintf("%s challenges you (%.0fs)", current.Invite.ChallengerName, current.TimeLeft)
+		s.DuelInviteWindow.Visible = true
+	}
+
+	duel := s.Client.GetDuel()
+	s.DuelWindow.Visible = duel.Active
+	if duel.Active {
+		s.DuelStatusLabel.Text = "vs " + duel.OpponentName
+	}
+}
+
+// AnswerDuelInvite accepts or declines the challenge shown in the invite window
+func (s *UISystem) AnswerDuelInvite(accept bool) {
+	if len(s.duelInvites) == 0 {
+		return
+	}
+	current := s.duelInvites[0]
+	s.duelInvites = s.duelInvites[1:]
+	if accept {
+		s.Client.SendDuel("accept", current.Invite.ChallengerID)
+		// Accepting one challenge voids the rest
+		s.duelInvites = nil
+	} else {
+		s.Client.SendDuel("decline", current.Invite.ChallengerID)
+		s.AddLog("Declined duel with " + current.Invite.ChallengerName)
+	}
+}
+
+func (s *UISystem) InitArenaUI() {
+	w := ui.NewWindow(590, 110, 200, 90, "Arena")
+	w.ShowScrollbar = false
+	s.ArenaLabel = ui.NewLabel(10, 10, "")
+	w.AddChild(s.ArenaLabel)
+	w.AddChild(ui.NewSecondaryButton(10, 35, 180, 25, "Leave", func() {
+		s.Client.SendArena("leave")
+	}))
+	w.Visible = false
+	s.ArenaWindow = w
+	s.Manager.AddElement(w)
+}
+
+// updateArena shows the queue size or the match score while the player is in either
+func (s *UISystem) updateArena() {
+	arena := s.Client.GetArena()
+	switch {
+	case arena.InMatch:
+		us, them := arena.Score[arena.Team], arena.Score[1-arena.Team]
+		s.ArenaLabel.Text = fmt.Sprintf("Round %d  %d - %d", arena.Round, us, them)
+		s.ArenaWindow.Visible = true
+	case arena.Queued:
+		s.ArenaLabel.Text = fmt.Sprintf("Queued %d/%d", arena.QueueSize, arena.QueueNeeded)
+		s.ArenaWindow.Visible = true
+	default:
+		s.ArenaWindow.Visible = false
+	}
+}
+
+// OpenArenaMenu offers the Arena Master's queue options at the cursor
+func (s *UISystem) OpenArenaMenu(mx, my int) {
+	opts := []ui.MenuOption{
+		{Text: "Join Arena", Action: func() { s.Client.SendArena("join") }},
+		{Text: "Watch Match", Action: func() { s.Client.SendSpectate("arena", 0) }},
+	}
+	if arena := s.Client.GetArena(); arena.Queued {
+		opts = []ui.MenuOption{
+			{Text: "Leave Queue", Action: func() { s.Client.SendArena("leave") }},
+		}
+	}
+	s.ContextMenu.Show(float64(mx), float64(my), opts, 0, 0, 800, 600)
+}
+
+// OpenHouseMenu offers the Housing Steward's deed, house and visit options at the cursor
+func (s *UISystem) OpenHouseMenu(mx, my int) {
+	house := s.Client.GetHouse()
+	var opts []ui.MenuOption
+	if !house.Owned {
+		opts = append(opts, ui.MenuOption{Text: fmt.Sprintf("Buy Deed (%d gold)", config.HouseDeedPrice), Action: func() { s.Client.SendHouse("buy", "") }})
+	}
+	opts = append(opts, ui.MenuOption{Text: "Enter My House", Action: func() { s.Client.SendHouse("enter", "") }})
+	for _, owner := range house.InvitedTo {
+		opts = append(opts, ui.MenuOption{Text: "Visit " + owner, Action: func() { s.Client.SendHouse("visit", owner) }})
+	}
+	if house.Owned {
+		opts = append(opts, ui.MenuOption{Text: "Guest List", Action: func() { s.HouseWindow.Visible = true }})
+		for _, guest := range house.Guests {
+			opts = append(opts, ui.MenuOption{Text: "Uninvite " + guest, Action: func() { s.Client.SendHouse("uninvite", guest) }})
+		}
+	}
+	s.ContextMenu.Show(float64(mx), float64(my), opts, 0, 0, 800, 600)
+}
+
+func (s *UISystem) InitHouseUI() {
+	w := ui.NewWindow(250, 180, 300, 150, "Guest List")
+	w.ShowScrollbar = false
+	s.HouseLabel = ui.NewLabel(20, 10, "")
+	w.AddChild(s.HouseLabel)
+	s.HouseInput = ui.NewTextInput(20, 35, 260, 30, "Player name")
+	w.AddChild(s.HouseInput)
+	w.AddChild(ui.NewButton(20, 80, 125, 30, "Invite", func() {
+		if name := strings.TrimSpace(s.HouseInput.Text); name != "" {
+			s.Client.SendHouse("invite", name)
+			s.HouseInput.Text = ""
+		}
+	}))
+	w.AddChild(ui.NewSecondaryButton(155, 80, 125, 30, "Close", func() {
+		w.Visible = false
+	}))
+	w.Visible = false
+	s.HouseWindow = w
+	s.Manager.AddElement(w)
+}
+
+// updateHouse keeps the guest count in the guest list window current
+func (s *UISystem) updateHouse() {
+	if !s.HouseWindow.Visible {
+		return
+	}
+	house := s.Client.GetHouse()
+	s.HouseLabel.Text = fmt.Sprintf("%d/%d guests (uninvite at the Steward)", len(house.Guests), config.HouseMaxGuests)
+}
+
+func (s *UISystem) InitSpectateUI() {
+	w := ui.NewWindow(590, 210, 200, 90, "Spectating")
+	w.ShowScrollbar = false
+	s.SpectateLabel = ui.NewLabel(10, 10, "")
+	w.AddChild(s.SpectateLabel)
+	w.AddChild(ui.NewSecondaryButton(10, 35, 85, 25, "Free Cam", func() {
+		s.Client.SendSpectate("free", 0)
+	}))
+	w.AddChild(ui.NewSecondaryButton(105, 35, 85, 25, "Stop", func() {
+		s.Client.SendSpectate("stop", 0)
+	}))
+	w.Visible = false
+	s.SpectateWindow = w
+	s.Manager.AddElement(w)
+}
+
+// updateSpectate shows whom the player is watching while spectating
+func (s *UISystem) updateSpectate() {
+	spec := s.Client.GetSpectate()
+	s.SpectateWindow.Visible = spec.Active
+	if !spec.Active {
+		return
+	}
+	if spec.TargetID == 0 {
+		s.SpectateLabel.Text = "Free camera"
+	} else {
+		s.SpectateLabel.Text = "Watching " + spec.TargetName
+	}
+}
+
+func (s *UISystem) InitFishingUI() {
+	w := ui.NewWindow(300, 420, 200, 110, "Fishing")
+	w.ShowScrollbar = false
+	s.FishStatusLabel = ui.NewLabel(10, 10, "")
+	w.AddChild(s.FishStatusLabel)
+	s.FishSkillLabel = ui.NewLabel(10, 30, "")
+	w.AddChild(s.FishSkillLabel)
+	w.AddChild(ui.NewButton(10, 55, 85, 25, "Hook!", s.HookFish))
+	w.AddChild(ui.NewSecondaryButton(105, 55, 85, 25, "Reel In", func() {
+		s.Client.SendFish("stop")
+	}))
+	w.Visible = false
+	s.FishingWindow = w
+	s.Manager.AddElement(w)
+}
+
+// updateFishing runs the minigame window: waiting for a bite, then a hook countdown
+func (s *UISystem) updateFishing() {
+	fish := s.Client.GetFish()
+	if fish.State == "bite" && s.fishState != "bite" {
+		s.fishWindowLeft = fish.Window
+	}
+	s.fishState = fish.State
+
+	s.FishingWindow.Visible = fish.State != ""
+	if fish.State == "" {
+		return
+	}
+	s.FishSkillLabel.Text = fmt.Sprintf("Fishing Lv %d (%d XP)", fish.Level, fish.XP)
+	if fish.State == "bite" {
+		s.fishWindowLeft -= 1.0 / 60.0
+		if s.fishWindowLeft < 0 {
+			s.fishWindowLeft = 0
+		}
+		s.FishStatusLabel.Text = fmt.Sprintf("BITE! Hook it! (%.1fs)", s.fishWindowLeft)
+	} else {
+		s.FishStatusLabel.Text = "Waiting for a bite..."
+	}
+}
+
+// HookFish strikes the line (Space or the Hook button). Too early scares the fish.
+func (s *UISystem) HookFish() {
+	if s.fishState != "" {
+		s.Client.SendFish("hook")
+	}
+}
+
+// OpenCharacterMenu offers Follow and (for GMs) Spectate on an NPC at the cursor
+func (s *UISystem) OpenCharacterMenu(target ecs.Entity, mx, my int) {
+	opts := []ui.MenuOption{
+		{Text: "Follow", Action: func() {
+			s.Client.SendFollow(target)
+			s.AddLog(fmt.Sprintf("Following entity %d", target))
+		}},
+		{Text: "Spectate", Action: func() { s.Client.SendSpectate("follow", target) }},
+	}
+	s.ContextMenu.Show(float64(mx), float64(my), opts, 0, 0, 800, 600)
+}
+
+// OpenPlayerMenu offers interactions with another player at the cursor
+func (s *UISystem) OpenPlayerMenu(target ecs.Entity, mx, my int) {
+	opts := []ui.MenuOption{
+		{Text: "Follow", Action: func() {
+			s.Client.SendFollow(target)
+			s.AddLog(fmt.Sprintf("Following entity %d", target))
+		}},
+		{Text: "Duel", Action: func() {
+			s.Client.SendDuel("request", target)
+		}},
+	}
+	// GMs can watch anyone; arena viewers can switch between fighters
+	if s.Client.IsAdmin || s.Client.GetSpectate().Active {
+		opts = append(opts, ui.MenuOption{Text: "Spectate", Action: func() { s.Client.SendSpectate("follow", target) }})
+	}
+	s.ContextMenu.Show(float64(mx), float64(my), opts, 0, 0, 800, 600)
+}
+
+// StartBuild enters build mode: the item follows the cursor until placed with a left
+// click, right click or Menu cancels
+func (s *UISystem) StartBuild(itemID string) {
+	s.BuildItemID = itemID
+	s.AddLog("Building " + itemID + ": left click to place, right click to stop")
+}
+
+// CancelBuild leaves build mode
+func (s *UISystem) CancelBuild() {
+	if s.BuildItemID != "" {
+		s.BuildItemID = ""
+		s.AddLog("Stopped building")
+	}
+}
+
+// OpenStructureMenu offers to demolish a player-built structure (the server checks claims)
+func (s *UISystem) OpenStructureMenu(target ecs.Entity, structure *components.StructureComponent, mx, my int) {
+	opts := []ui.MenuOption{
+		{Text: "Built by " + structure.Owner, Action: nil},
+		{Text: "Demolish", Action: func() {
+			s.Client.SendBuild("demolish", "", 0, 0, target)
+		}},
+	}
+	if s.Client.GetHouse().Inside != "" {
+		opts[1].Text = "Pick Up" // Furniture goes back to the inventory
+	}
+	s.ContextMenu.Show(float64(mx), float64(my), opts, 0, 0, 800, 600)
+}
+
+// OpenFarmMenu offers to harvest a ripe crop, or to plant one of the player's seeds
+func (s *UISystem) OpenFarmMenu(tx, ty, object int, mx, my int) {
+	var opts []ui.MenuOption
+	if _, stage, ok := world.CropFromObject(object); ok {
+		if stage >= world.CropRipe {
+			opts = append(opts, ui.MenuOption{Text: "Harvest", Action: func() {
+				s.Client.SendFarm("harvest", "", tx, ty)
+			}})
+		} else {
+			opts = append(opts, ui.MenuOption{Text: fmt.Sprintf("Growing (%d/%d)", stage+1, world.CropStages), Action: nil})
+		}
+	} else {
+		for _, slot := range s.Client.GetInventory().Slots {
+			if !strings.HasPrefix(slot.ItemID, "seed_") {
+				continue
+			}
+			seedID := slot.ItemID
+			opts = append(opts, ui.MenuOption{Text: "Plant " + strings.TrimPrefix(seedID, "seed_"), Action: func() {
+				s.Client.SendFarm("plant", seedID, tx, ty)
+			}})
+		}
+		if len(opts) == 0 {
+			opts = append(opts, ui.MenuOption{Text: "No seeds", Action: nil})
+		}
+	}
+	s.ContextMenu.Show(float64(mx), float64(my), opts, 0, 0, 800, 600)
+}
+
+// OpenTravelWindow lists the discovered waypoints as travel buttons
+func (s *UISystem) OpenTravelWindow(currentID string) {
+	w := s.TravelWindow
+	w.Children = nil
+	w.ContentHeight = 0
+	w.ScrollY = 0
+
+	sync := s.Client.GetWaypoints()
+	yOffset := 10.0
+	for _, wp := range sync.Waypoints {
+		if wp.ID == currentID {
+			continue
+		}
+		id, name := wp.ID, wp.Name
+		btn := ui.NewButton(10, yOffset, w.Width-20, 30, fmt.Sprintf("%s (%dg)", name, sync.Fee), func() {
+			s.Client.SendTravel(id)
+			s.AddLog("Travelling to " + name)
+			w.Visible = false
+		})
+		w.AddChild(btn)
+		yOffset += 40
+	}
+	if yOffset == 10.0 {
+		w.AddChild(ui.NewLabel(10, yOffset, "No other waypoints discovered."))
+	}
+
+	w.FooterHeight = 40
+	closeBtn := ui.NewSecondaryButton(10, w.Height-55, w.Width-20, 30, "Close", func() {
+		w.Visible = false
+	})
+	w.AddChildOption(closeBtn, true)
+	w.Visible = true
+}
+
+// OpenMail shows the mailbox and asks the server for a fresh copy
+func (s *UISystem) OpenMail() {
+	s.Client.SendMail("open", 0)
+	mailbox, _ := s.Client.PopMailbox()
+	s.refreshMail(mailbox)
+	s.MailWindow.Visible = true
+}
+
+// refreshMail rebuilds the mailbox window from a mailbox sync
+func (s *UISystem) refreshMail(mailbox protocol.MailboxPacket) {
+	w := s.MailWindow
+	w.Children = nil
+	w.ContentHeight = 0
+	w.ScrollY = 0
+
+	yOffset := 10.0
+	for _, mail := range mailbox.Mail {
+		id := mail.ID
+		w.AddChild(ui.NewLabel(10, yOffset, mail.From+": "+mail.Subject))
+		w.AddChild(ui.NewLabel(10, yOffset+20, strings.Join(mail.Items, ", ")))
+		w.AddChild(ui.NewButton(w.Width-90, yOffset+40, 70, 25, "Take", func() {
+			s.Client.SendMail("take", id)
+		}))
+		yOffset += 75
+	}
+	if yOffset == 10.0 {
+		w.AddChild(ui.NewLabel(10, yOffset, "No mail."))
+	}
+
+	w.FooterHeight = 40
+	closeBtn := ui.NewSecondaryButton(10, w.Height-55, w.Width-20, 30, "Close", func() {
+		w.Visible = false
+	})
+	w.AddChildOption(closeBtn, true)
+}
+
+// refreshCharacter rebuilds the character sheet window
+func (s *UISystem) refreshCharacter(sheet protocol.CharacterSheetPacket) {
+	w := s.CharacterWindow
+	w.Children = nil
+	w.ContentHeight = 0
+	w.ScrollY = 0
+
+	playtime := "Playtime: " + formatPlaytime(sheet.Playtime)
+	if sheet.AFK {
+		playtime += " (AFK)"
+	}
+	w.AddChild(ui.NewLabel(10, 10, playtime))
+	w.AddChild(ui.NewLabel(10, 30, fmt.Sprintf("Combat: Lv %d", sheet.Skills["combat"])))
+	w.AddChild(ui.NewLabel(10, 50, fmt.Sprintf("Fishing: Lv %d", sheet.Skills["fishing"])))
+
+	a := sheet.Attributes
+	w.AddChild(ui.NewLabel(10, 80, fmt.Sprintf("STR %d  DEX %d  INT %d", a.Str, a.Dex, a.Int)))
+	w.AddChild(ui.NewLabel(10, 100, fmt.Sprintf("Melee: %+.0f%%  Ranged: %+.0f%%", (sheet.MeleeDamage-1)*100, (sheet.RangedDamage-1)*100)))
+	w.AddChild(ui.NewLabel(10, 120, fmt.Sprintf("Spell Power: %+.0f%%  CDR: %.0f%%", (sheet.SpellPower-1)*100, sheet.CooldownReduction*100)))
+	w.AddChild(ui.NewLabel(10, 140, fmt.Sprintf("Attack Speed: %+.0f%%", (sheet.AttackSpeed-1)*100)))
+
+	w.AddChild(ui.NewLabel(10, 170, "Most Active Players"))
+	yOffset := 190.0
+	for i, rank := range sheet.TopPlaytime {
+		w.AddChild(ui.NewLabel(10, yOffset, fmt.Sprintf("%2d. %-16s %s", i+1, rank.Username, formatPlaytime(rank.Playtime))))
+		yOffset += 20
+	}
+
+	w.FooterHeight = 40
+	closeBtn := ui.NewSecondaryButton(10, w.Height-55, w.Width-20, 30, "Close", func() {
+		w.Visible = false
+	})
+	w.AddChildOption(closeBtn, true)
+}
+
+// formatPlaytime renders seconds as e.g. "3h 25m"
+func formatPlaytime(seconds float64) string {
+	minutes := int(seconds / 60)
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
 func (s *UISystem) ToggleBindMenu() {
 	s.BindWindow.Visible = !s.BindWindow.Visible
 	s.SyncUIState()
@@ -990,6 +1950,19 @@ func (s *UISystem) ToggleMenu() {
 		s.GameMenu.Visible = true
 		return
 	}
+	if s.LootFilterWindow != nil && s.LootFilterWindow.Visible {
+		s.LootFilterWindow.Visible = false
+		s.GameMenu.Visible = true
+		return
+	}
+	if s.BugReportWindow != nil && s.BugReportWindow.Visible {
+		s.BugReportWindow.Visible = false
+		return
+	}
+	if s.ChatInput != nil && s.ChatInput.Focused {
+		s.ChatInput.Focused = false // Stop typing, keeping what's typed
+		return
+	}
 	s.GameMenu.Visible = !s.GameMenu.Visible
 }
 
@@ -997,9 +1970,19 @@ func (s *UISystem) IsMenuVisible() bool {
 	return s.GameMenu.Visible
 }
 
+// IsTyping reports whether an in-game text field has keyboard focus
+func (s *UISystem) IsTyping() bool {
+	return (s.BugReportWindow != nil && s.BugReportWindow.Visible && s.BugReportInput.Focused) ||
+		(s.ChatWindow != nil && s.ChatWindow.Visible && s.ChatInput.Focused) ||
+		(s.HelpWindow != nil && s.HelpWindow.Visible && s.HelpInput.Focused)
+}
+
 func (s *UISystem) IsInputCaptured() bool {
 	return s.RebindMode || s.GameMenu.Visible ||
 		(s.KeybindingsWindow != nil && s.KeybindingsWindow.Visible) ||
+		(s.BugReportWindow != nil && s.BugReportWindow.Visible) ||
+		(s.ChatWindow != nil && s.ChatWindow.Visible && s.ChatInput.Focused) ||
+		(s.HelpWindow != nil && s.HelpWindow.Visible && s.HelpInput.Focused) ||
 		(s.LoginWindow != nil && s.LoginWindow.Visible) ||
 		(s.SignupWindow != nil && s.SignupWindow.Visible)
 }
@@ -1008,13 +1991,18 @@ func (s *UISystem) IsMouseOverUI() bool {
 	return s.Manager.IsMouseOverUI()
 }
 
-func (s *UISystem) SendInventoryAction(actionType string, slotA, slotB int) {
+// SendInventoryAction acts on the item in an inventory slot; toSlot is the destination of a swap
+func (s *UISystem) SendInventoryAction(actionType string, slot, toSlot int) {
+	instanceID := s.inventoryInstance(slot)
+	if instanceID == "" {
+		return
+	}
 	action := protocol.Packet{
 		Type: protocol.PacketInventoryAction,
 		Data: protocol.InventoryActionPacket{
 			ActionType: actionType,
-			SlotA:      slotA,
-			SlotB:      slotB,
+			InstanceID: instanceID,
+			ToSlot:     toSlot,
 		},
 	}
 	if s.Client.Encoder != nil {
@@ -1048,13 +2036,24 @@ func (s *UISystem) ToggleSpellsMenu() {
 	s.SyncUIState()
 }
 
+// SendEquipmentAction equips the item in an inventory slot into an equipment slot, or
+// unequips the item in the equipment slot (invSlot -1)
 func (s *UISystem) SendEquipmentAction(actionName string, slot int, invSlot int) {
+	var instanceID string
+	if actionName == "Equip" {
+		instanceID = s.inventoryInstance(invSlot)
+	} else if eq := s.Client.GetEquipment(); slot >= 0 && slot < len(eq.Slots) {
+		instanceID = eq.Slots[slot].InstanceID
+	}
+	if instanceID == "" {
+		return
+	}
 	action := protocol.Packet{
 		Type: protocol.PacketEquipmentAction,
 		Data: protocol.EquipmentActionPacket{
-			Action:  actionName,
-			Slot:    slot,
-			InvSlot: invSlot,
+			Action:     actionName,
+			InstanceID: instanceID,
+			Slot:       slot,
 		},
 	}
 	if s.Client.Encoder != nil {
@@ -1062,6 +2061,16 @@ func (s *UISystem) SendEquipmentAction(actionName string, slot int, invSlot int)
 	}
 }
 
+// inventoryInstance returns the instance ID of the item in an inventory slot, "" if empty
+func (s *UISystem) inventoryInstance(index int) string {
+	for _, slot := range s.Client.GetInventory().Slots {
+		if slot.Index == index {
+			return slot.InstanceID
+		}
+	}
+	return ""
+}
+
 func (s *UISystem) HandleDrop(srcW ui.Element, srcIdx int, destW ui.Element, destIdx int) {
 	// Source: Inventory
 	if srcW == s.InvWidget {
@@ -1130,7 +2139,11 @@ func (s *UISystem) OpenContextMenu(w ui.Element, index int, mx, my int) {
 	}
 
 	primaryText := "Use"
-	if strings.Contains(itemID, "potion") {
+	if strings.HasPrefix(itemID, "build_") {
+		primaryText = "Place"
+	} else if strings.HasPrefix(itemID, "rod_") {
+		primaryText = "Fish"
+	} else if strings.Contains(itemID, "potion") || strings.HasPrefix(itemID, "elixir_") {
 		primaryText = "Drink"
 	} else if strings.Contains(itemID, "sword") || strings.Contains(itemID, "bow") {
 		primaryText = "Equip"
@@ -1152,7 +2165,11 @@ func (s *UISystem) OpenContextMenu(w ui.Element, index int, mx, my int) {
 			{
 				Text: primaryText,
 				Action: func() {
-					if primaryText == "Equip" {
+					if primaryText == "Place" {
+						s.StartBuild(itemID)
+					} else if primaryText == "Fish" {
+						s.Client.SendFish("cast")
+					} else if primaryText == "Equip" {
 						// Need to find which slot it goes into.
 						// HACK: Server handles validation, but client needs to pick a slot.
 						// Or we send "Equip" with -1 and server picks?
@@ -1182,12 +2199,34 @@ func (s *UISystem) OpenContextMenu(w ui.Element, index int, mx, my int) {
 					s.SendHotbarAction("Bind", targetSlot, "Item", itemID, -1)
 				},
 			},
-			{
-				Text: "Drop",
-				Action: func() {
-					s.SendInventoryAction("Drop", index, -1)
+		}
+
+		locked := index < len(iw.Locked) && iw.Locked[index]
+		if locked {
+			actions = append(actions,
+				ui.MenuOption{
+					Text: "Unlock",
+					Action: func() {
+						s.SendInventoryAction("ToggleLock", index, -1)
+					},
 				},
-			},
+				ui.MenuOption{Text: "Drop (Locked)", Action: nil},
+			)
+		} else {
+			actions = append(actions,
+				ui.MenuOption{
+					Text: "Lock",
+					Action: func() {
+						s.SendInventoryAction("ToggleLock", index, -1)
+					},
+				},
+				ui.MenuOption{
+					Text: "Drop on Ground",
+					Action: func() {
+						s.OpenDropConfirmMenu(index, mx, my)
+					},
+				},
+			)
 		}
 	}
 
@@ -1210,6 +2249,26 @@ func (s *UISystem) OpenContextMenu(w ui.Element, index int, mx, my int) {
 
 	s.ContextMenu.Show(float64(mx), float64(my), actions, minX, minY, maxX, maxY)
 }
+
+// OpenDropConfirmMenu asks for confirmation before an item is dropped
+func (s *UISystem) OpenDropConfirmMenu(index int, mx, my int) {
+	actions := []ui.MenuOption{
+		{
+			Text: "Confirm Drop",
+			Action: func() {
+				s.SendInventoryAction("Drop", index, -1)
+			},
+		},
+		{Text: "Cancel", Action: nil},
+	}
+
+	minX := s.Inventory.X
+	minY := s.Inventory.Y
+	maxX := minX + s.Inventory.Width
+	maxY := minY + s.Inventory.Height
+	s.ContextMenu.Show(float64(mx), float64(my), actions, minX, minY, maxX, maxY)
+}
+
 func (s *UISystem) ApplyOpenMenus(openMenus map[string]bool) {
 	if openMenus == nil {
 		// Default State if nothing saved (New Player or first time with feature)
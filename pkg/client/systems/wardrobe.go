@@ -0,0 +1,113 @@
+package systems
+
+import (
+	"fmt"
+	"slices"
+
+	"henry/pkg/items"
+	"henry/pkg/ui"
+)
+
+// slotNames labels the equipment slots (components.Slot*)
+var slotNames = [9]string{"Head", "Neck", "Back", "Body", "Legs", "Weapon", "Shield", "Feet", "Hands"}
+
+func (s *UISystem) InitWardrobeUI() {
+	w := ui.NewWindow(230, 90, 340, 420, "Wardrobe")
+	w.Visible = false
+	s.WardrobeWindow = w
+	s.Manager.AddElement(w)
+}
+
+// OpenWardrobe shows the wardrobe window
+func (s *UISystem) OpenWardrobe() {
+	s.refreshWardrobe()
+	s.WardrobeWindow.Visible = true
+}
+
+// updateWardrobe logs newly collected looks and keeps the open window current
+func (s *UISystem) updateWardrobe() {
+	wardrobe, changed := s.Client.PopWardrobe()
+	if !changed {
+		return
+	}
+	if s.wardrobeLoaded {
+		for _, itemID := range wardrobe.Unlocked {
+			if !slices.Contains(s.wardrobe.Unlocked, itemID) {
+				s.AddLog("Added " + itemName(itemID) + " to your wardrobe")
+			}
+		}
+	}
+	s.wardrobe = wardrobe
+	s.wardrobeLoaded = true
+	if s.WardrobeWindow.Visible {
+		s.refreshWardrobe()
+	}
+}
+
+// refreshWardrobe rebuilds the wardrobe window: the look shown in each slot that has any
+// collected (the button cycles through them, then back to the worn item), then the
+// progress of each collection
+func (s *UISystem) refreshWardrobe() {
+	w := s.WardrobeWindow
+	wardrobe := s.wardrobe
+	w.Children = nil
+	w.ContentHeight = 0
+
+	yOffset := 10.0
+	shown := false
+	for slot, name := range slotNames {
+		options := []string{""}
+		for _, itemID := range wardrobe.Unlocked {
+			if def, ok := items.Get(itemID); ok && def.EquipmentSlot == slot {
+				options = append(options, itemID)
+			}
+		}
+		if len(options) == 1 {
+			continue
+		}
+		current := wardrobe.Looks[slot]
+		label := "Worn item"
+		if current != "" {
+			label = itemName(current)
+		}
+		next := options[(slices.Index(options, current)+1)%len(options)]
+		w.AddChild(ui.NewLabel(10, yOffset+6, fmt.Sprintf("%s: %s", name, label)))
+		w.AddChild(ui.NewButton(w.Width-90, yOffset, 70, 25, "Change", func() {
+			s.Client.SendWardrobeLook(slot, next)
+		}))
+		yOffset += 30
+		shown = true
+	}
+	if !shown {
+		w.AddChild(ui.NewLabel(10, yOffset, "No looks collected yet."))
+		yOffset += 20
+	}
+
+	yOffset += 10
+	w.AddChild(ui.NewLabel(10, yOffset, "Collections"))
+	yOffset += 20
+	for _, c := range items.Collections {
+		have := 0
+		for _, itemID := range c.Items {
+			if slices.Contains(wardrobe.Unlocked, itemID) {
+				have++
+			}
+		}
+		status := fmt.Sprintf("%d/%d", have, len(c.Items))
+		if have == len(c.Items) {
+			status = "Complete"
+		}
+		w.AddChild(ui.NewLabel(10, yOffset, fmt.Sprintf("%s - %s", c.Name, status)))
+		yOffset += 20
+		if c.Reward != "" {
+			w.AddChild(ui.NewLabel(20, yOffset, "Reward: "+itemName(c.Reward)))
+			yOffset += 20
+		}
+	}
+
+	w.FooterHeight = 40
+	closeBtn := ui.NewSecondaryButton(10, w.Height-55, w.Width-20, 30, "Close", func() {
+		w.Visible = false
+	})
+	w.AddChildOption(closeBtn, true)
+}
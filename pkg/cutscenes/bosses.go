@@ -0,0 +1,28 @@
+package cutscenes
+
+func init() {
+	// Goblin Warlord - Rare world boss
+	Register(Definition{
+		ID:        "goblin_warlord_intro",
+		Letterbox: true,
+		Skippable: true,
+		Once:      true,
+		Shots: []Shot{
+			{Duration: 3, Pan: 1, Target: TargetSubject, Speaker: "Goblin Warlord", Subtitle: "Another little hero for my collection!"},
+			{Duration: 1, Pan: 1, Target: TargetPlayer},
+		},
+	})
+
+	// Goblin Behemoth - Zone world boss
+	Register(Definition{
+		ID:        "goblin_behemoth_intro",
+		Letterbox: true,
+		Skippable: true,
+		Once:      true,
+		Shots: []Shot{
+			{Duration: 2, Pan: 1.5, Target: TargetSubject, Subtitle: "The ground shakes."},
+			{Duration: 2.5, Target: TargetSubject, Speaker: "Goblin Behemoth", Subtitle: "SMASH!"},
+			{Duration: 1, Pan: 1, Target: TargetPlayer},
+		},
+	})
+}
@@ -0,0 +1,16 @@
+package cutscenes
+
+func init() {
+	// Goblin Fields - First step past the edge of the fields
+	Register(Definition{
+		ID:        "goblin_fields_vista",
+		Letterbox: true,
+		Skippable: true,
+		Once:      true,
+		Shots: []Shot{
+			{Duration: 3, Pan: 2, X: 1920, Y: 2900, Speaker: "Guard", Subtitle: "Beyond here lie the Goblin Fields. Nobody keeps the peace out there."},
+			{Duration: 4, Pan: 2, X: 2048, Y: 3200, Speaker: "Guard", Subtitle: "Their camp is to the south. Bring friends, and mind the Behemoth."},
+			{Duration: 1.5, Pan: 1.5, Target: TargetPlayer},
+		},
+	})
+}
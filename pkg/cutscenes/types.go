@@ -0,0 +1,50 @@
+package cutscenes
+
+// Definition is a scripted camera sequence the server plays to a player: a story beat
+// from a map trigger, or a boss introducing itself (characters.CharacterDefinition.Intro).
+type Definition struct {
+	ID        string
+	Shots     []Shot
+	Letterbox bool // Black bars top and bottom
+	Skippable bool
+	Once      bool // Each player sees it once (remembered in their save)
+}
+
+// Shot is one camera move. Target is what the camera looks at: TargetSubject (the
+// entity the cutscene is about, e.g. the boss), TargetPlayer, or "" for the fixed X, Y.
+type Shot struct {
+	Duration float64 // Seconds
+	Pan      float64 // Seconds of the shot spent moving there (0 = cut)
+	Target   string
+	X, Y     float64 // World px
+	Speaker  string
+	Subtitle string
+}
+
+const (
+	TargetSubject = "subject"
+	TargetPlayer  = "player"
+)
+
+// Length is how long the cutscene plays, in seconds
+func (d Definition) Length() float64 {
+	total := 0.0
+	for _, shot := range d.Shots {
+		total += shot.Duration
+	}
+	return total
+}
+
+var Registry = make(map[string]Definition)
+
+func Register(def Definition) {
+	if _, exists := Registry[def.ID]; exists {
+		panic("Duplicate cutscene ID: " + def.ID)
+	}
+	Registry[def.ID] = def
+}
+
+func Get(id string) (Definition, bool) {
+	def, ok := Registry[id]
+	return def, ok
+}
@@ -1,12 +1,32 @@
 package items
 
+import "henry/pkg/shared/components"
+
 func init() {
-	// Potions, Food, etc will go here
 	Register(ItemDefinition{
 		ID:            "potion_health_small",
 		Name:          "Small Health Potion",
 		Type:          ItemTypeConsumable,
-		Description:   "Restores a small amount of health.",
+		Description:   "Restores 30 health.",
+		EquipmentSlot: -1,
+		Consumable:    &ConsumableEffect{Heal: 30, Cooldown: 10},
+	})
+
+	Register(ItemDefinition{
+		ID:            "elixir_might",
+		Name:          "Elixir of Might",
+		Type:          ItemTypeConsumable,
+		Description:   "Deal 25% more damage for 60 seconds.",
+		EquipmentSlot: -1,
+		Consumable:    &ConsumableEffect{Buff: components.BuffDamage, BuffAmount: 1.25, Duration: 60, Cooldown: 60},
+	})
+
+	Register(ItemDefinition{
+		ID:            "elixir_swiftness",
+		Name:          "Elixir of Swiftness",
+		Type:          ItemTypeConsumable,
+		Description:   "Move 20% faster for 30 seconds.",
 		EquipmentSlot: -1,
+		Consumable:    &ConsumableEffect{Buff: components.BuffSpeed, BuffAmount: 1.2, Duration: 30, Cooldown: 60},
 	})
 }
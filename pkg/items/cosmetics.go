@@ -0,0 +1,94 @@
+package items
+
+import (
+	"henry/pkg/shared/components"
+	"image/color"
+)
+
+// Collection is a set of cosmetic looks. Collecting every one of them also unlocks the
+// Reward look.
+type Collection struct {
+	ID     string
+	Name   string
+	Items  []string
+	Reward string
+}
+
+// Collections lists the cosmetic collections shown in the wardrobe
+var Collections = []Collection{
+	{ID: "harvest", Name: "Harvest Festival", Items: []string{"hat_pumpkin", "cloak_harvest"}, Reward: "crown_harvest"},
+	{ID: "winter", Name: "Winter Feast", Items: []string{"hat_festive", "scarf_festive"}, Reward: "cloak_winter"},
+}
+
+// CollectionsWith returns the collections an item belongs to
+func CollectionsWith(itemID string) []Collection {
+	var found []Collection
+	for _, c := range Collections {
+		for _, id := range c.Items {
+			if id == itemID {
+				found = append(found, c)
+				break
+			}
+		}
+	}
+	return found
+}
+
+func init() {
+	// Cosmetics: worn over the gear in their slot without changing any stats. Using one
+	// adds it to the wardrobe for good.
+	Register(ItemDefinition{
+		ID:            "hat_pumpkin",
+		Name:          "Pumpkin Hat",
+		Type:          ItemTypeCosmetic,
+		Description:   "A hollowed-out pumpkin. Use it to add the look to your wardrobe.",
+		Rarity:        RarityUncommon,
+		EquipmentSlot: components.SlotHead,
+		Look:          color.RGBA{R: 230, G: 120, B: 20, A: 255},
+	})
+	Register(ItemDefinition{
+		ID:            "cloak_harvest",
+		Name:          "Harvest Cloak",
+		Type:          ItemTypeCosmetic,
+		Description:   "Woven from autumn leaves. Use it to add the look to your wardrobe.",
+		Rarity:        RarityUncommon,
+		EquipmentSlot: components.SlotBack,
+		Look:          color.RGBA{R: 150, G: 70, B: 20, A: 255},
+	})
+	Register(ItemDefinition{
+		ID:            "crown_harvest",
+		Name:          "Harvest Crown",
+		Type:          ItemTypeCosmetic,
+		Description:   "Awarded for completing the Harvest Festival collection.",
+		Rarity:        RarityEpic,
+		EquipmentSlot: components.SlotHead,
+		Look:          color.RGBA{R: 240, G: 200, B: 60, A: 255},
+	})
+	Register(ItemDefinition{
+		ID:            "hat_festive",
+		Name:          "Festive Hat",
+		Type:          ItemTypeCosmetic,
+		Description:   "Red with a white bobble. Use it to add the look to your wardrobe.",
+		Rarity:        RarityUncommon,
+		EquipmentSlot: components.SlotHead,
+		Look:          color.RGBA{R: 200, G: 30, B: 40, A: 255},
+	})
+	Register(ItemDefinition{
+		ID:            "scarf_festive",
+		Name:          "Festive Scarf",
+		Type:          ItemTypeCosmetic,
+		Description:   "Long and striped. Use it to add the look to your wardrobe.",
+		Rarity:        RarityUncommon,
+		EquipmentSlot: components.SlotNeck,
+		Look:          color.RGBA{R: 40, G: 140, B: 60, A: 255},
+	})
+	Register(ItemDefinition{
+		ID:            "cloak_winter",
+		Name:          "Winter Cloak",
+		Type:          ItemTypeCosmetic,
+		Description:   "Awarded for completing the Winter Feast collection.",
+		Rarity:        RarityEpic,
+		EquipmentSlot: components.SlotBack,
+		Look:          color.RGBA{R: 200, G: 230, B: 255, A: 255},
+	})
+}
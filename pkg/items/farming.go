@@ -0,0 +1,36 @@
+package items
+
+func init() {
+	// Seeds are planted on farmland and harvested for cooking ingredients (plus the seed back)
+	Register(ItemDefinition{
+		ID:            "seed_wheat",
+		Name:          "Wheat Seeds",
+		Type:          ItemTypeMisc,
+		Description:   "Plant on farmland. Ripens in 5 minutes.",
+		EquipmentSlot: -1,
+		Crop:          &CropStats{Kind: 0, GrowSeconds: 300, Produce: "wheat", Yield: 3},
+	})
+	Register(ItemDefinition{
+		ID:            "seed_carrot",
+		Name:          "Carrot Seeds",
+		Type:          ItemTypeMisc,
+		Description:   "Plant on farmland. Ripens in 10 minutes.",
+		EquipmentSlot: -1,
+		Crop:          &CropStats{Kind: 1, GrowSeconds: 600, Produce: "carrot", Yield: 2},
+	})
+
+	Register(ItemDefinition{
+		ID:            "wheat",
+		Name:          "Wheat",
+		Type:          ItemTypeMisc,
+		Description:   "A cooking ingredient. Ground into flour for bread.",
+		EquipmentSlot: -1,
+	})
+	Register(ItemDefinition{
+		ID:            "carrot",
+		Name:          "Carrot",
+		Type:          ItemTypeMisc,
+		Description:   "A cooking ingredient. Crunchy.",
+		EquipmentSlot: -1,
+	})
+}
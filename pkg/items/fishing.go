@@ -0,0 +1,42 @@
+package items
+
+func init() {
+	// Fishing: the rod is used facing shallow water, the catches are cooking ingredients
+	Register(ItemDefinition{
+		ID:            "rod_fishing",
+		Name:          "Fishing Rod",
+		Type:          ItemTypeMisc,
+		Description:   "Cast it while facing shallow water.",
+		EquipmentSlot: -1,
+	})
+
+	Register(ItemDefinition{
+		ID:            "fish_minnow",
+		Name:          "Minnow",
+		Type:          ItemTypeMisc,
+		Description:   "A cooking ingredient. Barely a mouthful.",
+		EquipmentSlot: -1,
+	})
+	Register(ItemDefinition{
+		ID:            "fish_trout",
+		Name:          "Trout",
+		Type:          ItemTypeMisc,
+		Description:   "A cooking ingredient. Common in the lake.",
+		EquipmentSlot: -1,
+	})
+	Register(ItemDefinition{
+		ID:            "fish_salmon",
+		Name:          "Salmon",
+		Type:          ItemTypeMisc,
+		Description:   "A cooking ingredient. Only skilled anglers land one.",
+		Rarity:        RarityUncommon,
+		EquipmentSlot: -1,
+	})
+	Register(ItemDefinition{
+		ID:            "boot_old",
+		Name:          "Old Boot",
+		Type:          ItemTypeMisc,
+		Description:   "Soggy. Someone lost this a long time ago.",
+		EquipmentSlot: -1,
+	})
+}
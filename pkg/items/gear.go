@@ -0,0 +1,30 @@
+package items
+
+import (
+	"henry/pkg/shared/components"
+	"image/color"
+)
+
+func init() {
+	// Worn gear, only adding attributes
+	Register(ItemDefinition{
+		ID:            "amulet_sage",
+		Name:          "Sage's Amulet",
+		Type:          ItemTypeMisc,
+		Description:   "Sharpens the mind. +4 INT.",
+		Rarity:        RarityRare,
+		EquipmentSlot: components.SlotNeck,
+		Attributes:    components.Attributes{Int: 4},
+		Look:          color.RGBA{R: 90, G: 160, B: 230, A: 255},
+	})
+	Register(ItemDefinition{
+		ID:            "gloves_swift",
+		Name:          "Swift Gloves",
+		Type:          ItemTypeMisc,
+		Description:   "Light leather gloves. +1 DEX, +15% haste.",
+		Rarity:        RarityUncommon,
+		EquipmentSlot: components.SlotHands,
+		Attributes:    components.Attributes{Dex: 1, Haste: 15},
+		Look:          color.RGBA{R: 130, G: 90, B: 50, A: 255},
+	})
+}
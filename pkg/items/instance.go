@@ -0,0 +1,86 @@
+package items
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"henry/pkg/shared/components"
+)
+
+// NewInstanceID returns a new globally unique item instance ID (32 hex digits). Only the
+// server mints them: every stack in a player's inventory, item they wear and item on the
+// ground has one, and it follows the item as it moves, so packets, logs and (later)
+// listings can name exactly which item they mean.
+func NewInstanceID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("items: no randomness for instance IDs: " + err.Error())
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// Stackable reports whether copies of an item share a slot. Equippable items don't, so
+// each keeps its own instance.
+func (d ItemDefinition) Stackable() bool {
+	return d.EquipmentSlot == -1
+}
+
+// AddInstance puts an existing item instance (unequipped, picked up) into the inventory,
+// keeping its ID. Stackable items merge into a stack already held, whose ID wins; an
+// empty instanceID gets a new one. It returns the ID the items are held under, so callers
+// can log an instance that merged away.
+func AddInstance(inv *components.InventoryComponent, itemID, instanceID string, quantity int) (string, error) {
+	def, ok := Registry[itemID]
+	if !ok {
+		return "", errors.New("item not defined: " + itemID)
+	}
+	if def.Stackable() {
+		for i := range inv.Slots {
+			if inv.Slots[i].ItemID == itemID {
+				inv.Slots[i].Quantity += quantity
+				return inv.Slots[i].InstanceID, nil
+			}
+		}
+	}
+	i := emptySlot(inv)
+	if i < 0 {
+		return "", errors.New("inventory full")
+	}
+	if instanceID == "" {
+		instanceID = NewInstanceID()
+	}
+	inv.Slots[i] = components.InventorySlot{ItemID: itemID, Quantity: quantity, InstanceID: instanceID}
+	return instanceID, nil
+}
+
+// FindInstance returns the slot holding an item instance, or -1
+func FindInstance(inv *components.InventoryComponent, instanceID string) int {
+	if instanceID == "" {
+		return -1
+	}
+	for i, slot := range inv.Slots {
+		if slot.InstanceID == instanceID && slot.ItemID != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// AssignInstanceIDs gives every occupied slot without an instance ID a new one (saves
+// from before instance IDs)
+func AssignInstanceIDs(inv *components.InventoryComponent) {
+	for i := range inv.Slots {
+		if inv.Slots[i].ItemID != "" && inv.Slots[i].InstanceID == "" {
+			inv.Slots[i].InstanceID = NewInstanceID()
+		}
+	}
+}
+
+func emptySlot(inv *components.InventoryComponent) int {
+	for i := range inv.Slots {
+		if inv.Slots[i].ItemID == "" || inv.Slots[i].Quantity == 0 {
+			return i
+		}
+	}
+	return -1
+}
@@ -15,35 +15,36 @@ func NewInventory(capacity int) *components.InventoryComponent {
 	}
 }
 
-// AddItem adds an item to the inventory.
-// Tries to stack first, then find empty slot.
+// AddItem adds new items to the inventory.
+// Stackable items stack onto a held stack first, then take an empty slot; equippable
+// items take one empty slot each. Every new slot gets a new instance ID.
 func AddItem(inv *components.InventoryComponent, itemID string, quantity int) error {
-	// 1. Try to stack
-	// Logic: Iterate slots, if same ID, add.
 	// NOTE: We assume infinite stack size for now or need MaxStack in ItemDefinition
-
-	// Check if item exists
-	if _, ok := Registry[itemID]; !ok {
+	def, ok := Registry[itemID]
+	if !ok {
 		return errors.New("item not defined: " + itemID)
 	}
-
-	for i := range inv.Slots {
-		if inv.Slots[i].ItemID == itemID {
-			inv.Slots[i].Quantity += quantity
-			return nil
-		}
+	if def.Stackable() {
+		_, err := AddInstance(inv, itemID, "", quantity)
+		return err
 	}
 
-	// 2. Find empty slot
+	// All or nothing: check there's room for every copy first
+	free := 0
 	for i := range inv.Slots {
 		if inv.Slots[i].ItemID == "" || inv.Slots[i].Quantity == 0 {
-			inv.Slots[i].ItemID = itemID
-			inv.Slots[i].Quantity = quantity
-			return nil
+			free++
 		}
 	}
-
-	return errors.New("inventory full")
+	if free < quantity {
+		return errors.New("inventory full")
+	}
+	for range quantity {
+		if _, err := AddInstance(inv, itemID, "", 1); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // RemoveItem removes a quantity of item from a specific slot
@@ -59,12 +60,70 @@ func RemoveItem(inv *components.InventoryComponent, slotIndex int, quantity int)
 
 	slot.Quantity -= quantity
 	if slot.Quantity <= 0 {
-		slot.ItemID = ""
-		slot.Quantity = 0
+		*slot = components.InventorySlot{}
+	}
+	return nil
+}
+
+// CountItem returns the total quantity of an item across all slots
+func CountItem(inv *components.InventoryComponent, itemID string) int {
+	total := 0
+	for _, slot := range inv.Slots {
+		if slot.ItemID == itemID {
+			total += slot.Quantity
+		}
+	}
+	return total
+}
+
+// RemoveItemByID removes a quantity of an item from any slots holding it.
+// Nothing is removed if the inventory doesn't hold enough.
+func RemoveItemByID(inv *components.InventoryComponent, itemID string, quantity int) error {
+	if CountItem(inv, itemID) < quantity {
+		return errors.New("not enough items")
+	}
+
+	for i := range inv.Slots {
+		if quantity <= 0 {
+			break
+		}
+		if inv.Slots[i].ItemID != itemID {
+			continue
+		}
+		take := inv.Slots[i].Quantity
+		if take > quantity {
+			take = quantity
+		}
+		if err := RemoveItem(inv, i, take); err != nil {
+			return err
+		}
+		quantity -= take
 	}
 	return nil
 }
 
+// ToggleLock flips the lock flag on an occupied slot
+func ToggleLock(inv *components.InventoryComponent, slotIndex int) error {
+	if slotIndex < 0 || slotIndex >= len(inv.Slots) {
+		return errors.New("invalid slot index")
+	}
+
+	slot := &inv.Slots[slotIndex]
+	if slot.ItemID == "" {
+		return errors.New("empty slot")
+	}
+	slot.Locked = !slot.Locked
+	return nil
+}
+
+// IsLocked reports whether the item in a slot is locked
+func IsLocked(inv *components.InventoryComponent, slotIndex int) bool {
+	if slotIndex < 0 || slotIndex >= len(inv.Slots) {
+		return false
+	}
+	return inv.Slots[slotIndex].Locked
+}
+
 // SwapItems swaps content of two slots
 func SwapItems(inv *components.InventoryComponent, slotA, slotB int) error {
 	if slotA < 0 || slotA >= len(inv.Slots) || slotB < 0 || slotB >= len(inv.Slots) {
@@ -0,0 +1,57 @@
+package items
+
+import (
+	"errors"
+	"henry/pkg/shared/components"
+)
+
+// OnKeyring reports whether an item is kept on the keyring rather than in the bag
+func OnKeyring(itemID string) bool {
+	def, ok := Registry[itemID]
+	return ok && def.Quest
+}
+
+// AddToKeyring puts items on the keyring, onto the stack already held if any. A new
+// stack keeps instanceID, or gets a new one if it's empty. Returns the ID the items are
+// held under, as AddInstance does.
+func AddToKeyring(kr *components.KeyringComponent, itemID, instanceID string, quantity int) string {
+	for i := range kr.Items {
+		if kr.Items[i].ItemID == itemID {
+			kr.Items[i].Quantity += quantity
+			return kr.Items[i].InstanceID
+		}
+	}
+	if instanceID == "" {
+		instanceID = NewInstanceID()
+	}
+	kr.Items = append(kr.Items, components.InventorySlot{ItemID: itemID, Quantity: quantity, InstanceID: instanceID})
+	return instanceID
+}
+
+// KeyringCount returns how many of an item are on the keyring
+func KeyringCount(kr *components.KeyringComponent, itemID string) int {
+	for _, slot := range kr.Items {
+		if slot.ItemID == itemID {
+			return slot.Quantity
+		}
+	}
+	return 0
+}
+
+// RemoveFromKeyring takes items off the keyring, all or nothing
+func RemoveFromKeyring(kr *components.KeyringComponent, itemID string, quantity int) error {
+	for i := range kr.Items {
+		if kr.Items[i].ItemID != itemID {
+			continue
+		}
+		if kr.Items[i].Quantity < quantity {
+			break
+		}
+		kr.Items[i].Quantity -= quantity
+		if kr.Items[i].Quantity == 0 {
+			kr.Items = append(kr.Items[:i], kr.Items[i+1:]...)
+		}
+		return nil
+	}
+	return errors.New("not enough items")
+}
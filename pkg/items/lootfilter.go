@@ -0,0 +1,55 @@
+package items
+
+// Loot filter toggles, saved with the account's other settings
+const (
+	SettingLootAutoGold       = "LootAutoGold"
+	SettingLootAutoQuest      = "LootAutoQuest"
+	SettingLootIgnoreJunk     = "LootIgnoreJunk"
+	SettingLootHighlightRares = "LootHighlightRares"
+)
+
+// LootFilter is a player's choice of which ground items are picked up on their own, which
+// are left lying and which stand out. The client applies it; the server only honors
+// automatic pickups the filter allows.
+type LootFilter struct {
+	AutoGold       bool // Pick up coins in range
+	AutoQuest      bool // Pick up quest items in range
+	IgnoreJunk     bool // Gray out junk and skip it when clicking a pile
+	HighlightRares bool // Outline Rare and better items
+}
+
+// LootFilterFrom reads the filter out of the account's settings
+func LootFilterFrom(settings map[string]bool) LootFilter {
+	return LootFilter{
+		AutoGold:       settings[SettingLootAutoGold],
+		AutoQuest:      settings[SettingLootAutoQuest],
+		IgnoreJunk:     settings[SettingLootIgnoreJunk],
+		HighlightRares: settings[SettingLootHighlightRares],
+	}
+}
+
+// Save writes the filter into the account's settings
+func (f LootFilter) Save(settings map[string]bool) {
+	settings[SettingLootAutoGold] = f.AutoGold
+	settings[SettingLootAutoQuest] = f.AutoQuest
+	settings[SettingLootIgnoreJunk] = f.IgnoreJunk
+	settings[SettingLootHighlightRares] = f.HighlightRares
+}
+
+// AutoPickup reports whether the filter picks the item up without a click
+func (f LootFilter) AutoPickup(def ItemDefinition) bool {
+	if def.ID == Gold {
+		return f.AutoGold
+	}
+	return f.AutoQuest && def.Quest
+}
+
+// Ignores reports whether the filter leaves the item lying
+func (f LootFilter) Ignores(def ItemDefinition) bool {
+	return f.IgnoreJunk && def.Junk
+}
+
+// Highlights reports whether the filter makes the item stand out
+func (f LootFilter) Highlights(def ItemDefinition) bool {
+	return f.HighlightRares && def.Rarity >= RarityRare
+}
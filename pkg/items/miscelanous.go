@@ -1,11 +1,67 @@
 package items
 
+// Gold is the currency item. Players hold it in their wallet rather than their bag; coins
+// only exist as items on the ground and in mail.
+const Gold = "coin_gold"
+
 func init() {
 	// Crafting materials, quest items, etc.
 	Register(ItemDefinition{
-		ID:          "coin_gold",
-		Name:        "Gold Coin",
-		Type:        ItemTypeMisc,
-		Description: "Standard currency.",
+		ID:            Gold,
+		Name:          "Gold Coin",
+		Type:          ItemTypeMisc,
+		Description:   "Standard currency.",
+		EquipmentSlot: -1,
+	})
+	Register(ItemDefinition{
+		ID:            "house_deed",
+		Name:          "House Deed",
+		Type:          ItemTypeMisc,
+		Description:   "Show it at the house door in town to move into a house of your own.",
+		Rarity:        RarityRare,
+		Quest:         true,
+		EquipmentSlot: -1,
+	})
+
+	// Monster parts, sold to vendors
+	Register(ItemDefinition{
+		ID:            "slime_gel",
+		Name:          "Slime Gel",
+		Type:          ItemTypeMisc,
+		Description:   "A wobbly blob left behind by a slime.",
+		Junk:          true,
+		EquipmentSlot: -1,
+	})
+	Register(ItemDefinition{
+		ID:            "wolf_pelt",
+		Name:          "Wolf Pelt",
+		Type:          ItemTypeMisc,
+		Description:   "Thick grey fur. Tanners pay well for it.",
+		Junk:          true,
+		EquipmentSlot: -1,
+	})
+	Register(ItemDefinition{
+		ID:            "bone_fragment",
+		Name:          "Bone Fragment",
+		Type:          ItemTypeMisc,
+		Description:   "What's left of a skeleton once it stops moving.",
+		Junk:          true,
+		EquipmentSlot: -1,
+	})
+
+	// Seasonal keepsakes (see data/events/seasonal.json)
+	Register(ItemDefinition{
+		ID:            "harvest_pumpkin",
+		Name:          "Prize Pumpkin",
+		Type:          ItemTypeMisc,
+		Description:   "A keepsake from the Harvest Festival. Purely for show.",
+		EquipmentSlot: -1,
+	})
+	Register(ItemDefinition{
+		ID:            "festive_ribbon",
+		Name:          "Festive Ribbon",
+		Type:          ItemTypeMisc,
+		Description:   "A keepsake from the Winter Feast. Purely for show.",
+		EquipmentSlot: -1,
 	})
 }
@@ -0,0 +1,66 @@
+package items
+
+import "image/color"
+
+func init() {
+	// Structures players can build onto free tiles. IDs start with "build_" so the
+	// client offers "Place" for them.
+	Register(ItemDefinition{
+		ID:            "build_wall_wood",
+		Name:          "Wooden Wall",
+		Type:          ItemTypePlaceable,
+		Description:   "A sturdy palisade section. Blocks movement.",
+		EquipmentSlot: -1,
+		Structure:     &StructureStats{Solid: true, Color: color.RGBA{R: 120, G: 80, B: 40, A: 255}},
+	})
+	Register(ItemDefinition{
+		ID:            "build_campfire",
+		Name:          "Campfire",
+		Type:          ItemTypePlaceable,
+		Description:   "A small fire to gather around.",
+		EquipmentSlot: -1,
+		Structure:     &StructureStats{Color: color.RGBA{R: 255, G: 140, B: 30, A: 255}},
+	})
+	Register(ItemDefinition{
+		ID:            "build_banner",
+		Name:          "Claim Banner",
+		Type:          ItemTypePlaceable,
+		Description:   "Claims the land around it. Only you may build or demolish there.",
+		EquipmentSlot: -1,
+		Structure:     &StructureStats{Claim: true, Color: color.RGBA{R: 200, G: 30, B: 60, A: 255}},
+	})
+
+	// Furniture only goes inside a house (see HousingSystem)
+	Register(ItemDefinition{
+		ID:            "build_table",
+		Name:          "Table",
+		Type:          ItemTypePlaceable,
+		Description:   "Furniture for your house.",
+		EquipmentSlot: -1,
+		Structure:     &StructureStats{Solid: true, Furniture: true, Color: color.RGBA{R: 150, G: 100, B: 50, A: 255}},
+	})
+	Register(ItemDefinition{
+		ID:            "build_chair",
+		Name:          "Chair",
+		Type:          ItemTypePlaceable,
+		Description:   "Furniture for your house.",
+		EquipmentSlot: -1,
+		Structure:     &StructureStats{Furniture: true, Color: color.RGBA{R: 170, G: 120, B: 70, A: 255}},
+	})
+	Register(ItemDefinition{
+		ID:            "build_bed",
+		Name:          "Bed",
+		Type:          ItemTypePlaceable,
+		Description:   "Furniture for your house.",
+		EquipmentSlot: -1,
+		Structure:     &StructureStats{Solid: true, Furniture: true, Color: color.RGBA{R: 180, G: 40, B: 50, A: 255}},
+	})
+	Register(ItemDefinition{
+		ID:            "build_rug",
+		Name:          "Rug",
+		Type:          ItemTypePlaceable,
+		Description:   "Furniture for your house.",
+		EquipmentSlot: -1,
+		Structure:     &StructureStats{Furniture: true, Color: color.RGBA{R: 60, G: 80, B: 160, A: 255}},
+	})
+}
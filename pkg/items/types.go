@@ -1,6 +1,9 @@
 package items
 
-import "henry/pkg/shared/components"
+import (
+	"henry/pkg/shared/components"
+	"image/color"
+)
 
 type ItemType int
 
@@ -8,20 +11,92 @@ const (
 	ItemTypeWeapon ItemType = iota
 	ItemTypeConsumable
 	ItemTypeMisc
+	ItemTypePlaceable // Built onto a tile in the world (see StructureStats)
+	ItemTypeCosmetic  // Only a look: using it adds it to the wardrobe (see cosmetics.go)
+)
+
+// Rarity grades items from Common up. The UI draws slot borders and tooltip titles in
+// its color.
+type Rarity int
+
+const (
+	RarityCommon Rarity = iota
+	RarityUncommon
+	RarityRare
+	RarityEpic
+	RarityLegendary
 )
 
+var (
+	rarityNames  = [...]string{"Common", "Uncommon", "Rare", "Epic", "Legendary"}
+	rarityColors = [...]color.RGBA{
+		{R: 200, G: 200, B: 200, A: 255},
+		{R: 80, G: 200, B: 80, A: 255},
+		{R: 70, G: 130, B: 235, A: 255},
+		{R: 170, G: 80, B: 220, A: 255},
+		{R: 240, G: 150, B: 30, A: 255},
+	}
+)
+
+func (r Rarity) String() string {
+	if r < 0 || int(r) >= len(rarityNames) {
+		return rarityNames[RarityCommon]
+	}
+	return rarityNames[r]
+}
+
+func (r Rarity) Color() color.RGBA {
+	if r < 0 || int(r) >= len(rarityColors) {
+		return rarityColors[RarityCommon]
+	}
+	return rarityColors[r]
+}
+
 // ItemDefinition represents the static data for an item.
 type ItemDefinition struct {
 	ID          string // Unique string ID e.g. "sword_rusty"
 	Name        string
 	Type        ItemType
 	Description string
+	Rarity      Rarity // Zero = Common
+	Quest       bool   // A key or needed for a quest step: kept on the keyring, picked up by the loot filter
+	Junk        bool   // Only worth selling (grayed out by the loot filter)
 
 	// Component Data (Optional, depending on Type)
 	WeaponStats *components.AttackComponent
+	Structure   *StructureStats
+	Crop        *CropStats // Seeds
+	Consumable  *ConsumableEffect
 
 	// Equipment Data
-	EquipmentSlot int // -1 if not equippable
+	EquipmentSlot int                   // -1 if not equippable
+	Attributes    components.Attributes // Added to the wearer's while equipped
+	Look          color.RGBA            // Drawn over the wearer in its slot (zero = not drawn)
+}
+
+// StructureStats describes what a placeable item becomes once built
+type StructureStats struct {
+	Solid     bool // Blocks movement like a wall
+	Claim     bool // Claims the surrounding land for the builder
+	Furniture bool // Only placed inside the owner's house
+	Color     color.RGBA
+}
+
+// ConsumableEffect is what using (drinking, eating) one of a consumable does
+type ConsumableEffect struct {
+	Heal       float64 // Health restored
+	Buff       string  // Buffed stat (components.BuffDamage, ...), "" for none
+	BuffAmount float64 // Multiplier
+	Duration   float64 // Seconds the buff lasts
+	Cooldown   float64 // Seconds before another of the same item can be used
+}
+
+// CropStats describes what a seed grows into once planted on farmland
+type CropStats struct {
+	Kind        int     // Object layer variant (see world.CropObject)
+	GrowSeconds float64 // From planting to ripe
+	Produce     string  // Item harvested
+	Yield       int
 }
 
 var Registry = make(map[string]ItemDefinition)
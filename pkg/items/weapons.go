@@ -2,6 +2,7 @@ package items
 
 import (
 	"henry/pkg/shared/components"
+	"image/color"
 )
 
 func init() {
@@ -18,6 +19,8 @@ func init() {
 			Type:     components.AttackTypeMelee,
 		},
 		EquipmentSlot: components.SlotWeapon,
+		Attributes:    components.Attributes{Str: 2},
+		Look:          color.RGBA{R: 150, G: 140, B: 130, A: 255},
 	})
 
 	// Ranged Weapons
@@ -33,5 +36,35 @@ func init() {
 			Type:     components.AttackTypeRanged,
 		},
 		EquipmentSlot: components.SlotWeapon,
+		Attributes:    components.Attributes{Dex: 2},
+		Look:          color.RGBA{R: 120, G: 80, B: 40, A: 255},
+	})
+
+	// Natural Weapons (monsters' own teeth and goo; never dropped)
+	Register(ItemDefinition{
+		ID:          "fangs_wolf",
+		Name:        "Wolf Fangs",
+		Type:        ItemTypeWeapon,
+		Description: "Quick, tearing bites.",
+		WeaponStats: &components.AttackComponent{
+			Damage:   8,
+			Range:    50,
+			Cooldown: 0.6,
+			Type:     components.AttackTypeMelee,
+		},
+		EquipmentSlot: components.SlotWeapon,
+	})
+	Register(ItemDefinition{
+		ID:          "goo_slime",
+		Name:        "Slime Goo",
+		Type:        ItemTypeWeapon,
+		Description: "A slow, sticky slap.",
+		WeaponStats: &components.AttackComponent{
+			Damage:   6,
+			Range:    45,
+			Cooldown: 1.2,
+			Type:     components.AttackTypeMelee,
+		},
+		EquipmentSlot: components.SlotWeapon,
 	})
 }
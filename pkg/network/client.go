@@ -4,28 +4,72 @@ import (
 	"encoding/gob"
 	"fmt"
 	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
 	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
 	"henry/pkg/shared/network"
 	"henry/pkg/shared/world"
 	"log"
 	"net"
 	"sync"
+	"time"
 )
 
 type NetworkClient struct {
-	Conn           net.Conn
-	Encoder        *gob.Encoder
-	Decoder        *gob.Decoder
-	PlayerEntityID ecs.Entity
-	State          network.StateUpdatePacket
-	Inventory      network.InventorySyncPacket
-	Hotbar         network.HotbarSyncPacket
-	Equipment      network.EquipmentSyncPacket
-	Map            network.MapSyncPacket
-	WorldMap       *world.Map
-	UnlockedSpells []string
-	Cooldowns      map[string]float64
-	Mutex          sync.RWMutex
+	Conn              net.Conn
+	Encoder           *gob.Encoder
+	Decoder           *gob.Decoder
+	PlayerEntityID    ecs.Entity
+	State             network.StateUpdatePacket
+	PrevState         network.StateUpdatePacket // Previous snapshot (interpolation source)
+	StateTime         time.Time                 // Arrival time of State
+	PrevStateTime     time.Time
+	Spawned           map[ecs.Entity]network.EntitySpawnPacket // Static data of the entities in view
+	Inventory         network.InventorySyncPacket
+	Hotbar            network.HotbarSyncPacket
+	Equipment         network.EquipmentSyncPacket
+	Map               network.MapSyncPacket
+	WorldMap          *world.Map
+	UnlockedSpells    []string
+	Cooldowns         map[string]float64
+	CooldownReduction float64  // Fraction off spell cooldowns (player's INT)
+	Announcements     []string // Pending server announcements (drained by UI)
+	Zone              network.ZoneChangePacket
+	ZoneChanged       bool // Set when Zone was updated (cleared by UI)
+	Waypoints         network.WaypointSyncPacket
+	LootRolls         []network.LootRollPacket      // Pending need/greed rolls (drained by UI)
+	DuelInvites       []network.DuelInvitePacket    // Pending duel challenges (drained by UI)
+	Events            []network.EntityEventPacket   // Pending hit/death effects (drained by render)
+	Chat              []network.ChatBroadcastPacket // Pending chat lines (drained by UI)
+	Duel              network.DuelStatePacket       // Current duel (Active=false when none)
+	Arena             network.ArenaStatePacket      // Arena queue / match
+	Spectate          network.SpectateStatePacket
+	Fish              network.FishStatePacket
+	House             network.HouseStatePacket
+	Combat            network.CombatStatePacket      // Hostiles after the player (picks the music)
+	Cutscenes         []network.CutscenePacket       // Pending cutscene starts/ends (drained by the director)
+	Corrections       []network.MoveCorrectionPacket // Pending movement corrections (drained by prediction)
+	Mailbox           network.MailboxPacket
+	MailChanged       bool // Set when Mailbox was updated (cleared by UI)
+	Dialogue          network.DialoguePacket
+	DialogueChanged   bool // Set when Dialogue was updated (cleared by UI)
+	Shop              network.ShopOpenPacket
+	ShopChanged       bool // Set when Shop was updated (cleared by UI)
+	Wardrobe          network.WardrobeSyncPacket
+	WardrobeChanged   bool // Set when Wardrobe was updated (cleared by UI)
+	HelpTopics        []network.HelpTopic
+	HelpChanged       bool                       // Set when HelpTopics arrived (cleared by UI)
+	Away              *network.AwaySummaryPacket // Pending "while you were away" summary (drained by UI)
+	Sheet             network.CharacterSheetPacket
+	SheetChanged      bool                     // Set when Sheet was updated (cleared by UI)
+	LoginQueue        network.LoginQueuePacket // Queue spot while waiting to log in (Position 0 = not queued)
+	IsAdmin           bool                     // GM tools are offered in menus
+	Mutex             sync.RWMutex
+
+	net netCounters // Behind NetStats (see netstats.go)
+
+	recorder *recorder // Set while recording (see replay.go)
+	recMu    sync.Mutex
 }
 
 func (c *NetworkClient) GetEquipment() network.EquipmentSyncPacket {
@@ -75,41 +119,57 @@ func (c *NetworkClient) Signup(address, username, password string) error {
 	return nil
 }
 
-func (c *NetworkClient) Connect(address, username, password string) (map[string]int, map[string]bool, map[string]bool, bool, error) {
+func (c *NetworkClient) Connect(address, username, password string) (map[string]int, map[string]bool, map[string]bool, string, error) {
 	conn, err := Dial(address)
 	if err != nil {
-		return nil, nil, nil, false, err
+		return nil, nil, nil, "", err
 	}
 
-	c.Conn = conn
-	c.Encoder = gob.NewEncoder(conn)
-	c.Decoder = gob.NewDecoder(conn)
+	c.net.reset()
+	c.Conn = countingConn{Conn: conn, counters: &c.net}
+	c.Encoder = gob.NewEncoder(c.Conn)
+	c.Decoder = gob.NewDecoder(c.Conn)
 
 	// Send Login
 	login := network.Packet{
 		Type: network.PacketLogin,
-		Data: network.LoginPacket{Username: username, Password: password},
+		Data: network.LoginPacket{
+			Username:      username,
+			Password:      password,
+			ClientVersion: config.BuildString(),
+			Protocol:      network.ProtocolFingerprint(),
+		},
 	}
 	if err := c.Encoder.Encode(login); err != nil {
-		return nil, nil, nil, false, err
+		return nil, nil, nil, "", err
 	}
 
-	// Wait for Login Response
+	// Wait for Login Response (a full server sends queue updates first)
 	var response network.Packet
-	if err := c.Decoder.Decode(&response); err != nil {
-		return nil, nil, nil, false, err
+	for {
+		if err := c.Decoder.Decode(&response); err != nil {
+			c.setLoginQueue(network.LoginQueuePacket{})
+			return nil, nil, nil, "", err
+		}
+		if response.Type != network.PacketLoginQueue {
+			break
+		}
+		queue := response.Data.(network.LoginQueuePacket)
+		log.Printf("Server full, queue position %d of %d", queue.Position, queue.Size)
+		c.setLoginQueue(queue)
 	}
+	c.setLoginQueue(network.LoginQueuePacket{})
 	if response.Type != network.PacketLoginResponse {
-		return nil, nil, nil, false, fmt.Errorf("unexpected packet type: %d", response.Type)
+		return nil, nil, nil, "", fmt.Errorf("unexpected packet type: %d", response.Type)
 	}
 
 	respData := response.Data.(network.LoginResponsePacket)
 	if !respData.Success {
-		return nil, nil, nil, false, fmt.Errorf("login failed: %s", respData.Error)
+		return nil, nil, nil, "", fmt.Errorf("login failed: %s", respData.Error)
 	}
 
 	c.PlayerEntityID = respData.PlayerEntityID
-	log.Printf("Logged in. EntityID: %d", c.PlayerEntityID)
+	log.Printf("Logged in to server %s. EntityID: %d", respData.ServerVersion, c.PlayerEntityID)
 
 	// Init Map
 	c.WorldMap = &world.Map{
@@ -119,10 +179,12 @@ func (c *NetworkClient) Connect(address, username, password string) (map[string]
 		Objects: world.UnflattenObjects(respData.MapObjects, respData.MapWidth, respData.MapHeight),
 	}
 	c.UnlockedSpells = respData.UnlockedSpells
+	components.RegisterSpells(respData.Spells)
+	c.IsAdmin = respData.IsAdmin
 
 	// Start listening loop
 	go c.ListenLoop()
-	return respData.Keybindings, respData.DebugSettings, respData.OpenMenus, respData.IsRunning, nil
+	return respData.Keybindings, respData.DebugSettings, respData.OpenMenus, respData.Stance, nil
 }
 
 func (c *NetworkClient) ListenLoop() {
@@ -130,47 +192,208 @@ func (c *NetworkClient) ListenLoop() {
 		var packet network.Packet
 		if err := c.Decoder.Decode(&packet); err != nil {
 			log.Printf("Disconnected from server: %v", err)
+			c.StopRecording()
 			return
 		}
+		c.record(packet)
+		c.handlePacket(packet)
+	}
+}
 
-		if packet.Type == network.PacketStateUpdate {
-			state := packet.Data.(network.StateUpdatePacket)
-			c.Mutex.Lock()
-			c.State = state
-			c.Mutex.Unlock()
-		} else if packet.Type == network.PacketInventorySync {
-			inv := packet.Data.(network.InventorySyncPacket)
-			c.Mutex.Lock()
-			c.Inventory = inv
-			c.Mutex.Unlock()
-		} else if packet.Type == network.PacketHotbarSync {
-			hb := packet.Data.(network.HotbarSyncPacket)
-			log.Printf("Client Recv HotbarSync: %v", hb.Slots)
-			c.Mutex.Lock()
-			c.Hotbar = hb
-			c.Mutex.Unlock()
-		} else if packet.Type == network.PacketEquipmentSync {
-			eq := packet.Data.(network.EquipmentSyncPacket)
-			c.Mutex.Lock()
-			c.Equipment = eq
-			c.Mutex.Unlock()
-		} else if packet.Type == network.PacketMapSync {
-			m := packet.Data.(network.MapSyncPacket)
-			c.Mutex.Lock()
-			c.Map = m
-			c.Mutex.Unlock()
-		} else if packet.Type == network.PacketSpellbookSync {
-			sb := packet.Data.(network.SpellbookSyncPacket)
-			c.Mutex.Lock()
-			c.UnlockedSpells = sb.UnlockedSpells
-			// Also sync Cooldowns. Need to add Cooldowns field to Client first!
-			c.Cooldowns = sb.Cooldowns
-			c.Mutex.Unlock()
+// handlePacket applies one server packet to the client state (live, or from a replay)
+func (c *NetworkClient) handlePacket(packet network.Packet) {
+	if packet.Type == network.PacketStateUpdate {
+		state := packet.Data.(network.StateUpdatePacket)
+		c.net.snapshot(state.Seq)
+		c.Mutex.Lock()
+		for i := range state.Entities {
+			c.withStatic(&state.Entities[i])
+		}
+		state.Entities = mergeRetained(state, c.State)
+		c.PrevState, c.PrevStateTime = c.State, c.StateTime
+		c.State, c.StateTime = state, time.Now()
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketEntitySpawn {
+		spawn := packet.Data.(network.EntitySpawnPacket)
+		c.Mutex.Lock()
+		if c.Spawned == nil {
+			c.Spawned = make(map[ecs.Entity]network.EntitySpawnPacket)
 		}
+		c.Spawned[spawn.ID] = spawn
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketEntityDespawn {
+		despawn := packet.Data.(network.EntityDespawnPacket)
+		c.Mutex.Lock()
+		delete(c.Spawned, despawn.EntityID)
+		c.State.Entities = withoutEntity(c.State.Entities, despawn.EntityID)
+		c.PrevState.Entities = withoutEntity(c.PrevState.Entities, despawn.EntityID)
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketInventorySync {
+		inv := packet.Data.(network.InventorySyncPacket)
+		c.Mutex.Lock()
+		c.Inventory = inv
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketHotbarSync {
+		hb := packet.Data.(network.HotbarSyncPacket)
+		log.Printf("Client Recv HotbarSync: %v", hb.Slots)
+		c.Mutex.Lock()
+		c.Hotbar = hb
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketEquipmentSync {
+		eq := packet.Data.(network.EquipmentSyncPacket)
+		c.Mutex.Lock()
+		c.Equipment = eq
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketMapSync {
+		m := packet.Data.(network.MapSyncPacket)
+		c.Mutex.Lock()
+		c.Map = m
+		// Moved to another level (instance): render the new map
+		c.WorldMap = &world.Map{
+			Level:   m.Level,
+			Width:   m.Width,
+			Height:  m.Height,
+			Tiles:   world.UnflattenTiles(m.Tiles, m.Width, m.Height),
+			Objects: world.UnflattenObjects(m.Objects, m.Width, m.Height),
+		}
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketSpellbookSync {
+		sb := packet.Data.(network.SpellbookSyncPacket)
+		c.Mutex.Lock()
+		c.UnlockedSpells = sb.UnlockedSpells
+		// Also sync Cooldowns. Need to add Cooldowns field to Client first!
+		c.Cooldowns = sb.Cooldowns
+		c.CooldownReduction = sb.CooldownReduction
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketAnnouncement {
+		ann := packet.Data.(network.AnnouncementPacket)
+		c.Mutex.Lock()
+		c.Announcements = append(c.Announcements, ann.Message)
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketZoneChange {
+		zone := packet.Data.(network.ZoneChangePacket)
+		c.Mutex.Lock()
+		c.Zone = zone
+		c.ZoneChanged = true
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketWaypointSync {
+		wp := packet.Data.(network.WaypointSyncPacket)
+		c.Mutex.Lock()
+		c.Waypoints = wp
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketLootRoll {
+		roll := packet.Data.(network.LootRollPacket)
+		c.Mutex.Lock()
+		c.LootRolls = append(c.LootRolls, roll)
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketDuelInvite {
+		invite := packet.Data.(network.DuelInvitePacket)
+		c.Mutex.Lock()
+		c.DuelInvites = append(c.DuelInvites, invite)
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketDuelState {
+		duel := packet.Data.(network.DuelStatePacket)
+		c.Mutex.Lock()
+		c.Duel = duel
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketArenaState {
+		arena := packet.Data.(network.ArenaStatePacket)
+		c.Mutex.Lock()
+		c.Arena = arena
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketSpectateState {
+		spec := packet.Data.(network.SpectateStatePacket)
+		c.Mutex.Lock()
+		c.Spectate = spec
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketCombatState {
+		combat := packet.Data.(network.CombatStatePacket)
+		c.Mutex.Lock()
+		c.Combat = combat
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketCutscene {
+		cutscene := packet.Data.(network.CutscenePacket)
+		c.Mutex.Lock()
+		c.Cutscenes = append(c.Cutscenes, cutscene)
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketMoveCorrection {
+		correction := packet.Data.(network.MoveCorrectionPacket)
+		c.Mutex.Lock()
+		c.Corrections = append(c.Corrections, correction)
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketHouseState {
+		house := packet.Data.(network.HouseStatePacket)
+		c.Mutex.Lock()
+		c.House = house
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketFishState {
+		fish := packet.Data.(network.FishStatePacket)
+		c.Mutex.Lock()
+		c.Fish = fish
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketMailbox {
+		mailbox := packet.Data.(network.MailboxPacket)
+		c.Mutex.Lock()
+		c.Mailbox = mailbox
+		c.MailChanged = true
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketPong {
+		c.net.pong(packet.Data.(network.PongPacket).Sent)
+	} else if packet.Type == network.PacketDialogue {
+		dialogue := packet.Data.(network.DialoguePacket)
+		c.Mutex.Lock()
+		c.Dialogue = dialogue
+		c.DialogueChanged = true
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketShopOpen {
+		shop := packet.Data.(network.ShopOpenPacket)
+		c.Mutex.Lock()
+		c.Shop = shop
+		c.ShopChanged = true
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketWardrobeSync {
+		wardrobe := packet.Data.(network.WardrobeSyncPacket)
+		c.Mutex.Lock()
+		c.Wardrobe = wardrobe
+		c.WardrobeChanged = true
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketHelpTopics {
+		help := packet.Data.(network.HelpTopicsPacket)
+		c.Mutex.Lock()
+		c.HelpTopics = help.Topics
+		c.HelpChanged = true
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketAwaySummary {
+		away := packet.Data.(network.AwaySummaryPacket)
+		c.Mutex.Lock()
+		c.Away = &away
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketCharacterSheet {
+		sheet := packet.Data.(network.CharacterSheetPacket)
+		c.Mutex.Lock()
+		c.Sheet = sheet
+		c.SheetChanged = true
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketObjectUpdate {
+		obj := packet.Data.(network.ObjectUpdatePacket)
+		c.Mutex.Lock()
+		c.applyObjectUpdate(obj)
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketEntityEvent {
+		event := packet.Data.(network.EntityEventPacket)
+		c.Mutex.Lock()
+		c.Events = append(c.Events, event)
+		c.Mutex.Unlock()
+	} else if packet.Type == network.PacketChatBroadcast {
+		msg := packet.Data.(network.ChatBroadcastPacket)
+		c.Mutex.Lock()
+		c.Chat = append(c.Chat, msg)
+		c.Mutex.Unlock()
 	}
 }
 
 func (c *NetworkClient) Close() {
+	c.StopRecording()
 	if c.Conn != nil {
 		c.Conn.Close()
 		c.Conn = nil
@@ -180,10 +403,42 @@ func (c *NetworkClient) Close() {
 	c.Hotbar = network.HotbarSyncPacket{}
 	c.Equipment = network.EquipmentSyncPacket{}
 	c.State = network.StateUpdatePacket{}
+	c.PrevState = network.StateUpdatePacket{}
+	c.Spawned = nil
+	c.Zone = network.ZoneChangePacket{}
+	c.ZoneChanged = false
+	c.Waypoints = network.WaypointSyncPacket{}
+	c.LootRolls = nil
+	c.Away = nil
+	c.DuelInvites = nil
+	c.Events = nil
+	c.Chat = nil
+	c.Duel = network.DuelStatePacket{}
+	c.Arena = network.ArenaStatePacket{}
+	c.Spectate = network.SpectateStatePacket{}
+	c.Fish = network.FishStatePacket{}
+	c.House = network.HouseStatePacket{}
+	c.Combat = network.CombatStatePacket{}
+	c.Cutscenes = nil
+	c.Corrections = nil
+	c.Mailbox = network.MailboxPacket{}
+	c.MailChanged = false
+	c.Dialogue = network.DialoguePacket{}
+	c.DialogueChanged = false
+	c.Shop = network.ShopOpenPacket{}
+	c.ShopChanged = false
+	c.Wardrobe = network.WardrobeSyncPacket{}
+	c.WardrobeChanged = false
+	c.HelpTopics = nil
+	c.HelpChanged = false
+	c.Sheet = network.CharacterSheetPacket{}
+	c.SheetChanged = false
+	c.IsAdmin = false
 	c.Mutex.Unlock()
 }
 
 func (c *NetworkClient) SendInput(input components.InputComponent) {
+	c.maybePing() // Input goes out every frame, so pings ride along with it
 	packet := network.Packet{
 		Type: network.PacketInput,
 		Data: network.InputPacket{Input: input},
@@ -198,6 +453,289 @@ func (c *NetworkClient) GetState() network.StateUpdatePacket {
 	return c.State
 }
 
+// GetInterpolatedState returns the latest snapshot with entity positions blended
+// from the previous snapshot, rendering one broadcast interval behind the server.
+func (c *NetworkClient) GetInterpolatedState() network.StateUpdatePacket {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+
+	interval := c.StateTime.Sub(c.PrevStateTime).Seconds()
+	if c.PrevStateTime.IsZero() || interval <= 0 {
+		return c.State
+	}
+	alpha := time.Since(c.StateTime).Seconds() / interval
+	if alpha >= 1 {
+		return c.State
+	}
+
+	prev := make(map[ecs.Entity]*components.TransformComponent, len(c.PrevState.Entities))
+	for _, e := range c.PrevState.Entities {
+		if e.Transform != nil {
+			prev[e.ID] = e.Transform
+		}
+	}
+
+	// Copy so the blended transforms don't overwrite the snapshot
+	out := c.State
+	out.Entities = make([]network.EntitySnapshot, len(c.State.Entities))
+	for i, e := range c.State.Entities {
+		out.Entities[i] = e
+		from, ok := prev[e.ID]
+		if !ok || e.Transform == nil || from.Z != e.Transform.Z {
+			continue
+		}
+
+		if !geom.Within(from.X, from.Y, e.Transform.X, e.Transform.Y, maxInterpolateDist) {
+			continue // Teleported, snap
+		}
+
+		t := *e.Transform
+		p := geom.Vec2{X: from.X, Y: from.Y}.Lerp(geom.Vec2{X: t.X, Y: t.Y}, alpha)
+		t.X, t.Y = p.X, p.Y
+		out.Entities[i].Transform = &t
+	}
+	return out
+}
+
+// mergeRetained carries over entities the server skipped this broadcast (distant, reduced rate)
+func mergeRetained(state, last network.StateUpdatePacket) []network.EntitySnapshot {
+	if len(state.Retained) == 0 {
+		return state.Entities
+	}
+
+	keep := make(map[ecs.Entity]bool, len(state.Retained))
+	for _, id := range state.Retained {
+		keep[id] = true
+	}
+	entities := state.Entities
+	for _, e := range last.Entities {
+		if keep[e.ID] {
+			entities = append(entities, e)
+		}
+	}
+	return entities
+}
+
+// withStatic fills in a snapshot's static data from its entity's EntitySpawnPacket
+func (c *NetworkClient) withStatic(e *network.EntitySnapshot) {
+	spawn, ok := c.Spawned[e.ID]
+	if !ok {
+		return
+	}
+	sprite := spawn.Sprite
+	e.Sprite = &sprite
+	e.Marker, e.Waypoint, e.Structure = spawn.Marker, spawn.Waypoint, spawn.Structure
+	if e.Stats != nil {
+		e.Stats.MaxHealth = spawn.MaxHealth
+	}
+}
+
+// withoutEntity copies a snapshot's entities minus the removed one (snapshots share
+// backing arrays, so it's never filtered in place)
+func withoutEntity(entities []network.EntitySnapshot, id ecs.Entity) []network.EntitySnapshot {
+	out := make([]network.EntitySnapshot, 0, len(entities))
+	for _, e := range entities {
+		if e.ID != id {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// maxInterpolateDist is the largest jump (px) between snapshots that gets smoothed
+const maxInterpolateDist = 256.0
+
+// PopAnnouncements returns and clears pending server announcements
+func (c *NetworkClient) PopAnnouncements() []string {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	msgs := c.Announcements
+	c.Announcements = nil
+	return msgs
+}
+
+// PopLootRolls returns and clears loot rolls offered since the last call
+func (c *NetworkClient) PopLootRolls() []network.LootRollPacket {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	rolls := c.LootRolls
+	c.LootRolls = nil
+	return rolls
+}
+
+// PopAway returns and clears the summary sent at login (nil once taken)
+func (c *NetworkClient) PopAway() *network.AwaySummaryPacket {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	away := c.Away
+	c.Away = nil
+	return away
+}
+
+// PopEvents returns and clears combat events received since the last call
+func (c *NetworkClient) PopEvents() []network.EntityEventPacket {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	events := c.Events
+	c.Events = nil
+	return events
+}
+
+// PopChat returns and clears chat lines received since the last call
+func (c *NetworkClient) PopChat() []network.ChatBroadcastPacket {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	msgs := c.Chat
+	c.Chat = nil
+	return msgs
+}
+
+// PopDuelInvites returns and clears duel challenges received since the last call
+func (c *NetworkClient) PopDuelInvites() []network.DuelInvitePacket {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	invites := c.DuelInvites
+	c.DuelInvites = nil
+	return invites
+}
+
+func (c *NetworkClient) GetDuel() network.DuelStatePacket {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.Duel
+}
+
+func (c *NetworkClient) GetArena() network.ArenaStatePacket {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.Arena
+}
+
+func (c *NetworkClient) GetSpectate() network.SpectateStatePacket {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.Spectate
+}
+
+func (c *NetworkClient) GetFish() network.FishStatePacket {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.Fish
+}
+
+func (c *NetworkClient) GetHouse() network.HouseStatePacket {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.House
+}
+
+func (c *NetworkClient) GetCombat() network.CombatStatePacket {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.Combat
+}
+
+// GetZone returns the zone the player is in (ZoneID "" = wilderness)
+func (c *NetworkClient) GetZone() network.ZoneChangePacket {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.Zone
+}
+
+// PopMailbox returns the mailbox and whether it changed since the last call
+func (c *NetworkClient) PopMailbox() (network.MailboxPacket, bool) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	changed := c.MailChanged
+	c.MailChanged = false
+	return c.Mailbox, changed
+}
+
+// PopDialogue returns the dialogue node shown and whether it changed since the last call
+func (c *NetworkClient) PopDialogue() (network.DialoguePacket, bool) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	changed := c.DialogueChanged
+	c.DialogueChanged = false
+	return c.Dialogue, changed
+}
+
+// PopShop returns the open vendor's shop and whether it changed since the last call
+func (c *NetworkClient) PopShop() (network.ShopOpenPacket, bool) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	changed := c.ShopChanged
+	c.ShopChanged = false
+	return c.Shop, changed
+}
+
+// PopWardrobe returns the player's wardrobe and whether it changed since the last call
+func (c *NetworkClient) PopWardrobe() (network.WardrobeSyncPacket, bool) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	changed := c.WardrobeChanged
+	c.WardrobeChanged = false
+	return c.Wardrobe, changed
+}
+
+// PopHelp returns the help topics and whether they arrived since the last call
+func (c *NetworkClient) PopHelp() ([]network.HelpTopic, bool) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	changed := c.HelpChanged
+	c.HelpChanged = false
+	return c.HelpTopics, changed
+}
+
+// GetLoginQueue returns the queue spot while a login waits for a free slot
+func (c *NetworkClient) GetLoginQueue() network.LoginQueuePacket {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.LoginQueue
+}
+
+func (c *NetworkClient) setLoginQueue(queue network.LoginQueuePacket) {
+	c.Mutex.Lock()
+	c.LoginQueue = queue
+	c.Mutex.Unlock()
+}
+
+// PopCharacterSheet returns the character sheet and whether it changed since the last call
+func (c *NetworkClient) PopCharacterSheet() (network.CharacterSheetPacket, bool) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	changed := c.SheetChanged
+	c.SheetChanged = false
+	return c.Sheet, changed
+}
+
+// PopCutscenes returns and clears cutscenes started (or ended, with no shots) since the last call
+func (c *NetworkClient) PopCutscenes() []network.CutscenePacket {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	cutscenes := c.Cutscenes
+	c.Cutscenes = nil
+	return cutscenes
+}
+
+// PopCorrections returns and clears movement corrections received since the last call
+func (c *NetworkClient) PopCorrections() []network.MoveCorrectionPacket {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	corrections := c.Corrections
+	c.Corrections = nil
+	return corrections
+}
+
+// PopZoneChange returns the current zone and whether it changed since the last call
+func (c *NetworkClient) PopZoneChange() (network.ZoneChangePacket, bool) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	changed := c.ZoneChanged
+	c.ZoneChanged = false
+	return c.Zone, changed
+}
+
 func (c *NetworkClient) GetInventory() network.InventorySyncPacket {
 	c.Mutex.RLock()
 	defer c.Mutex.RUnlock()
@@ -235,6 +773,35 @@ func (c *NetworkClient) GetMap() network.MapSyncPacket {
 	return c.Map
 }
 
+// applyObjectUpdate patches one object layer tile of the current map (e.g. a crop grew).
+// Updates for another level are stale and ignored. Assumes c.Mutex is LOCKED.
+func (c *NetworkClient) applyObjectUpdate(obj network.ObjectUpdatePacket) {
+	if c.WorldMap == nil || c.WorldMap.Level != obj.Level {
+		return
+	}
+	if obj.TileY >= 0 && obj.TileY < len(c.WorldMap.Objects) && obj.TileX >= 0 && obj.TileX < len(c.WorldMap.Objects[obj.TileY]) {
+		c.WorldMap.Objects[obj.TileY][obj.TileX] = obj.Object
+	}
+	if idx := obj.TileY*c.Map.Width + obj.TileX; c.Map.Level == obj.Level && idx >= 0 && idx < len(c.Map.Objects) {
+		c.Map.Objects[idx] = obj.Object
+	}
+}
+
+// TileAt returns the ground tile and object at a tile of the current map
+func (c *NetworkClient) TileAt(tx, ty int) (world.TileType, int, bool) {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	m := c.WorldMap
+	if m == nil || ty < 0 || ty >= len(m.Tiles) || tx < 0 || tx >= len(m.Tiles[ty]) {
+		return 0, 0, false
+	}
+	obj := 0
+	if ty < len(m.Objects) && tx < len(m.Objects[ty]) {
+		obj = m.Objects[ty][tx]
+	}
+	return m.Tiles[ty][tx].Type, obj, true
+}
+
 func (c *NetworkClient) SendCastSpell(spellID string) {
 	if c.Encoder != nil {
 		packet := network.Packet{
@@ -244,3 +811,257 @@ func (c *NetworkClient) SendCastSpell(spellID string) {
 		c.Encoder.Encode(packet)
 	}
 }
+
+func (c *NetworkClient) SendMoveTo(x, y float64) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketMoveTo,
+			Data: network.MoveToPacket{X: x, Y: y},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+func (c *NetworkClient) SendFollow(targetID ecs.Entity) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketFollow,
+			Data: network.FollowPacket{TargetID: targetID},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+func (c *NetworkClient) GetWaypoints() network.WaypointSyncPacket {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.Waypoints
+}
+
+func (c *NetworkClient) SendTravel(waypointID string) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketTravel,
+			Data: network.TravelPacket{WaypointID: waypointID},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+func (c *NetworkClient) SendBugReport(report network.BugReportPacket) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketBugReport,
+			Data: report,
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+func (c *NetworkClient) SendLootChoice(rollID int, choice string) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketLootChoice,
+			Data: network.LootChoicePacket{RollID: rollID, Choice: choice},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendDuel sends a duel action: "request"/"accept"/"decline" name the other player, "forfeit" needs none
+func (c *NetworkClient) SendDuel(action string, target ecs.Entity) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketDuel,
+			Data: network.DuelPacket{Action: action, TargetID: target},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendArena joins ("join", near an Arena Master) or leaves ("leave") the arena queue/match
+func (c *NetworkClient) SendArena(action string) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketArena,
+			Data: network.ArenaPacket{Action: action},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendSpectate sends a spectate action ("follow" needs a target; "free", "arena" and "stop" don't)
+func (c *NetworkClient) SendSpectate(action string, target ecs.Entity) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketSpectate,
+			Data: network.SpectatePacket{Action: action, TargetID: target},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendFish sends a fishing action ("cast", "hook" or "stop")
+func (c *NetworkClient) SendFish(action string) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketFish,
+			Data: network.FishPacket{Action: action},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendMail requests the mailbox ("open") or takes a mail's attachments ("take")
+func (c *NetworkClient) SendMail(action string, mailID int) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketMail,
+			Data: network.MailPacket{Action: action, MailID: mailID},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendHouse sends a house action ("buy", "enter", "leave"; "visit", "invite" and
+// "uninvite" name a player)
+func (c *NetworkClient) SendHouse(action, name string) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketHouse,
+			Data: network.HousePacket{Action: action, Name: name},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendCutsceneSkip tells the server the player skipped a cutscene
+func (c *NetworkClient) SendCutsceneSkip(id string) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketCutsceneSkip,
+			Data: network.CutsceneSkipPacket{ID: id},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendInteract asks to talk to an NPC (0 = the nearest one)
+func (c *NetworkClient) SendInteract(targetID ecs.Entity) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketInteract,
+			Data: network.InteractPacket{TargetID: targetID},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendDialogueChoice picks a reply in the open dialogue (-1 = goodbye)
+func (c *NetworkClient) SendDialogueChoice(option int) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketDialogueChoice,
+			Data: network.DialogueChoicePacket{Option: option},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendShopBuy buys items from a vendor
+func (c *NetworkClient) SendShopBuy(npc ecs.Entity, itemID string, quantity int) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketShopTransaction,
+			Data: network.ShopTransactionPacket{NPC: npc, Action: "buy", ItemID: itemID, Quantity: quantity},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendShopSell sells items from an inventory stack to a vendor
+func (c *NetworkClient) SendShopSell(npc ecs.Entity, instanceID string, quantity int) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketShopTransaction,
+			Data: network.ShopTransactionPacket{NPC: npc, Action: "sell", InstanceID: instanceID, Quantity: quantity},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendWardrobeLook shows a collected look in an equipment slot ("" shows the worn item)
+func (c *NetworkClient) SendWardrobeLook(slot int, itemID string) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketWardrobeAction,
+			Data: network.WardrobeActionPacket{Slot: slot, ItemID: itemID},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendChat sends a chat line on a channel (to names the player for whispers)
+func (c *NetworkClient) SendChat(channel, to, text string) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketChatMessage,
+			Data: network.ChatMessagePacket{Channel: channel, To: to, Text: text},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendCharacter requests the character sheet (with the playtime leaderboard)
+func (c *NetworkClient) SendCharacter() {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketCharacter,
+			Data: network.CharacterPacket{Leaderboard: true},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendFarm plants a seed on ("plant") or harvests ("harvest") a farmland tile
+func (c *NetworkClient) SendFarm(action, seedID string, tileX, tileY int) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketFarm,
+			Data: network.FarmPacket{Action: action, SeedID: seedID, TileX: tileX, TileY: tileY},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendBuild places a placeable item on a tile ("place") or tears down a structure ("demolish")
+func (c *NetworkClient) SendBuild(action, itemID string, tileX, tileY int, target ecs.Entity) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketBuild,
+			Data: network.BuildPacket{Action: action, ItemID: itemID, TileX: tileX, TileY: tileY, TargetID: target},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+func (c *NetworkClient) SendPickup(entityID ecs.Entity) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketPickup,
+			Data: network.PickupPacket{EntityID: entityID},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
+
+// SendAutoPickup asks for a ground item on behalf of the loot filter
+func (c *NetworkClient) SendAutoPickup(entityID ecs.Entity) {
+	if c.Encoder != nil {
+		packet := network.Packet{
+			Type: network.PacketPickup,
+			Data: network.PickupPacket{EntityID: entityID, Auto: true},
+		}
+		c.Encoder.Encode(packet)
+	}
+}
@@ -0,0 +1,147 @@
+package network
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/network"
+)
+
+// NetStats is what the network debug overlay shows, over the last second or so
+type NetStats struct {
+	SnapshotRate float64       // Snapshots received per second
+	BytesIn      float64       // Per second
+	BytesOut     float64       // Per second
+	RTT          time.Duration // Round trip of the last ping (0 = no pong yet)
+	InterpDelay  time.Duration // How far behind the latest snapshot entities are drawn
+	Dropped      int           // Snapshots the server never sent (gaps in Seq), since connecting
+	Late         int           // Snapshots that came over 1.5 broadcast intervals after the last one
+}
+
+// netCounters is the raw tally behind NetStats. The byte counts are bumped by the
+// connection's reader and writer, the rest under its own lock.
+type netCounters struct {
+	bytesIn, bytesOut atomic.Int64
+
+	mu        sync.Mutex
+	snapshots int
+	lastSeq   uint32
+	lastAt    time.Time
+	dropped   int
+	late      int
+	rtt       time.Duration
+	lastPing  time.Time
+
+	// Rates are measured between samples at least a second apart
+	sampleAt                        time.Time
+	sampleIn, sampleOut, sampleSnap int64
+	stats                           NetStats
+}
+
+// countingConn tallies the bytes through a connection
+type countingConn struct {
+	net.Conn
+	counters *netCounters
+}
+
+func (c countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.counters.bytesIn.Add(int64(n))
+	return n, err
+}
+
+func (c countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.counters.bytesOut.Add(int64(n))
+	return n, err
+}
+
+// reset starts the tally over for a new connection
+func (n *netCounters) reset() {
+	n.bytesIn.Store(0)
+	n.bytesOut.Store(0)
+	n.mu.Lock()
+	n.snapshots, n.lastSeq, n.lastAt = 0, 0, time.Time{}
+	n.dropped, n.late, n.rtt, n.lastPing = 0, 0, 0, time.Time{}
+	n.sampleAt, n.sampleIn, n.sampleOut, n.sampleSnap = time.Time{}, 0, 0, 0
+	n.stats = NetStats{}
+	n.mu.Unlock()
+}
+
+// snapshot records a state update arriving
+func (n *netCounters) snapshot(seq uint32) {
+	now := time.Now()
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.snapshots > 0 {
+		if seq > n.lastSeq+1 {
+			n.dropped += int(seq - n.lastSeq - 1)
+		}
+		if now.Sub(n.lastAt).Seconds() > 1.5/config.SnapshotRate {
+			n.late++
+		}
+	}
+	n.snapshots++
+	n.lastSeq, n.lastAt = seq, now
+}
+
+// pong records the answer to a ping
+func (n *netCounters) pong(sent int64) {
+	n.mu.Lock()
+	n.rtt = time.Since(time.Unix(0, sent))
+	n.mu.Unlock()
+}
+
+// pingDue reports whether it's time for another ping, and if so counts it as sent
+func (n *netCounters) pingDue() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if time.Since(n.lastPing).Seconds() < config.PingInterval {
+		return false
+	}
+	n.lastPing = time.Now()
+	return true
+}
+
+// NetStats returns the connection's current network stats
+func (c *NetworkClient) NetStats() NetStats {
+	c.Mutex.RLock()
+	interp := c.StateTime.Sub(c.PrevStateTime)
+	if c.PrevStateTime.IsZero() {
+		interp = 0
+	}
+	c.Mutex.RUnlock()
+
+	n := &c.net
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	now := time.Now()
+	in, out, snaps := n.bytesIn.Load(), n.bytesOut.Load(), int64(n.snapshots)
+	if elapsed := now.Sub(n.sampleAt).Seconds(); elapsed >= 1 {
+		if !n.sampleAt.IsZero() {
+			n.stats.BytesIn = float64(in-n.sampleIn) / elapsed
+			n.stats.BytesOut = float64(out-n.sampleOut) / elapsed
+			n.stats.SnapshotRate = float64(snaps-n.sampleSnap) / elapsed
+		}
+		n.sampleAt, n.sampleIn, n.sampleOut, n.sampleSnap = now, in, out, snaps
+	}
+	n.stats.RTT = n.rtt
+	n.stats.InterpDelay = interp
+	n.stats.Dropped = n.dropped
+	n.stats.Late = n.late
+	return n.stats
+}
+
+// maybePing sends a ping every config.PingInterval to measure the round trip
+func (c *NetworkClient) maybePing() {
+	if c.Encoder == nil || !c.net.pingDue() {
+		return
+	}
+	c.Encoder.Encode(network.Packet{
+		Type: network.PacketPing,
+		Data: network.PingPacket{Sent: time.Now().UnixNano()},
+	})
+}
@@ -0,0 +1,244 @@
+package network
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/network"
+	"henry/pkg/shared/world"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Bumped when the recording layout changes
+const replayVersion = 1
+
+// ReplayHeader starts every recording
+type ReplayHeader struct {
+	Version        int
+	Recorded       time.Time
+	PlayerEntityID ecs.Entity
+	Map            network.MapSyncPacket // Map at the time recording started
+}
+
+// ReplayFrame is one recorded server packet
+type ReplayFrame struct {
+	Time   float64 // Seconds since recording started
+	Packet network.Packet
+}
+
+// Only what's drawn in the world is recorded (menus and inventory aren't replayed)
+var replayPackets = map[network.PacketType]bool{
+	network.PacketStateUpdate:   true,
+	network.PacketMapSync:       true,
+	network.PacketObjectUpdate:  true,
+	network.PacketEntityEvent:   true,
+	network.PacketEntitySpawn:   true,
+	network.PacketEntityDespawn: true,
+	network.PacketZoneChange:    true,
+	network.PacketAnnouncement:  true,
+	network.PacketDuelState:     true,
+	network.PacketArenaState:    true,
+	network.PacketChatBroadcast: true,
+}
+
+type recorder struct {
+	mu    sync.Mutex
+	file  *os.File
+	buf   *bufio.Writer
+	enc   *gob.Encoder
+	start time.Time
+}
+
+// StartRecording writes the packets received from now on to path, for playback with
+// cmd/client -replay. Call after Connect.
+func (c *NetworkClient) StartRecording(path string) error {
+	c.StopRecording()
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	rec := &recorder{file: file, buf: bufio.NewWriter(file), start: time.Now()}
+	rec.enc = gob.NewEncoder(rec.buf)
+
+	c.Mutex.RLock()
+	header := ReplayHeader{Version: replayVersion, Recorded: rec.start, PlayerEntityID: c.PlayerEntityID}
+	if c.WorldMap != nil {
+		header.Map = network.MapSyncPacket{
+			Level:   c.WorldMap.Level,
+			Width:   c.WorldMap.Width,
+			Height:  c.WorldMap.Height,
+			Tiles:   world.FlattenTiles(c.WorldMap.Tiles),
+			Objects: world.FlattenObjects(c.WorldMap.Objects),
+		}
+	}
+	c.Mutex.RUnlock()
+
+	if err := rec.enc.Encode(header); err != nil {
+		file.Close()
+		return err
+	}
+	c.recMu.Lock()
+	c.recorder = rec
+	c.recMu.Unlock()
+	log.Printf("Recording to %s", path)
+	return nil
+}
+
+// StopRecording finishes the current recording, if any
+func (c *NetworkClient) StopRecording() {
+	c.recMu.Lock()
+	rec := c.recorder
+	c.recorder = nil
+	c.recMu.Unlock()
+	if rec == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if err := rec.buf.Flush(); err != nil {
+		log.Printf("Recording: %v", err)
+	}
+	rec.file.Close()
+}
+
+// record appends a received packet to the current recording
+func (c *NetworkClient) record(packet network.Packet) {
+	if !replayPackets[packet.Type] {
+		return
+	}
+	c.recMu.Lock()
+	rec := c.recorder
+	c.recMu.Unlock()
+	if rec == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	frame := ReplayFrame{Time: time.Since(rec.start).Seconds(), Packet: packet}
+	err := rec.enc.Encode(frame)
+	if err == nil && packet.Type == network.PacketStateUpdate {
+		err = rec.buf.Flush() // Keep what's recorded so far if the client is killed
+	}
+	if err != nil {
+		log.Printf("Recording stopped: %v", err)
+		go c.StopRecording()
+	}
+}
+
+// Replay is a recording loaded into memory
+type Replay struct {
+	Header ReplayHeader
+	Frames []ReplayFrame
+}
+
+// LoadReplay reads a recording. A recording cut short (the client was killed) loads
+// up to its last whole frame.
+func LoadReplay(path string) (*Replay, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	dec := gob.NewDecoder(bufio.NewReader(file))
+
+	r := &Replay{}
+	if err := dec.Decode(&r.Header); err != nil {
+		return nil, fmt.Errorf("%s: not a replay: %w", path, err)
+	}
+	if r.Header.Version != replayVersion {
+		return nil, fmt.Errorf("%s: replay version %d, this client reads %d", path, r.Header.Version, replayVersion)
+	}
+	for {
+		var frame ReplayFrame
+		if err := dec.Decode(&frame); err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Printf("%s: stopped reading at frame %d: %v", path, len(r.Frames), err)
+			}
+			break
+		}
+		r.Frames = append(r.Frames, frame)
+	}
+	return r, nil
+}
+
+// Duration is the time of the last frame
+func (r *Replay) Duration() float64 {
+	if len(r.Frames) == 0 {
+		return 0
+	}
+	return r.Frames[len(r.Frames)-1].Time
+}
+
+// ReplayPlayer feeds a recording into a client as if it came from the server
+type ReplayPlayer struct {
+	Client *NetworkClient
+	Replay *Replay
+	Time   float64 // Playback position (s)
+	Speed  float64 // 1 = real time
+	Paused bool
+	next   int // First frame not applied yet
+}
+
+func NewReplayPlayer(c *NetworkClient, r *Replay) *ReplayPlayer {
+	p := &ReplayPlayer{Client: c, Replay: r, Speed: 1}
+	p.reset()
+	return p
+}
+
+// reset puts the client back to where the recording started
+func (p *ReplayPlayer) reset() {
+	p.Client.Close()
+	p.Client.handlePacket(network.Packet{Type: network.PacketMapSync, Data: p.Replay.Header.Map})
+	p.Client.Mutex.Lock()
+	p.Client.PlayerEntityID = p.Replay.Header.PlayerEntityID
+	p.Client.Mutex.Unlock()
+	p.Time, p.next = 0, 0
+}
+
+// Advance moves playback on by dt seconds of real time. Playback pauses at the end.
+func (p *ReplayPlayer) Advance(dt float64) {
+	if p.Paused {
+		return
+	}
+	p.Time += dt * p.Speed
+	if p.Time >= p.Replay.Duration() {
+		p.Time = p.Replay.Duration()
+		p.Paused = true
+	}
+	p.apply(true)
+}
+
+// Seek jumps to t seconds. Snapshots build on the ones before them, so going back
+// replays the recording from the start (hit and death effects are skipped on the way).
+func (p *ReplayPlayer) Seek(t float64) {
+	t = max(0, min(t, p.Replay.Duration()))
+	if t < p.Time {
+		p.reset()
+	}
+	p.Time = t
+	p.apply(false)
+}
+
+// SetPlayer follows another entity with the camera
+func (p *ReplayPlayer) SetPlayer(id ecs.Entity) {
+	p.Client.Mutex.Lock()
+	p.Client.PlayerEntityID = id
+	p.Client.Mutex.Unlock()
+}
+
+func (p *ReplayPlayer) apply(effects bool) {
+	for p.next < len(p.Replay.Frames) && p.Replay.Frames[p.next].Time <= p.Time {
+		packet := p.Replay.Frames[p.next].Packet
+		p.next++
+		if !effects && (packet.Type == network.PacketEntityEvent || packet.Type == network.PacketAnnouncement) {
+			continue
+		}
+		p.Client.handlePacket(packet)
+	}
+}
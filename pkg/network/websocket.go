@@ -2,8 +2,10 @@ package network
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/http"
+	"strings"
 
 	"github.com/coder/websocket"
 )
@@ -15,8 +17,10 @@ func NewWebSocketConn(ctx context.Context, c *websocket.Conn) net.Conn {
 	return websocket.NetConn(ctx, c, websocket.MessageBinary)
 }
 
-// StartWebSocketServer starts a simple HTTP server that upgrades to WebSocket and passes net.Conn to a handler
-func StartWebSocketServer(addr string, handler func(net.Conn)) error {
+// StartWebSocketServer starts a simple HTTP server that upgrades to WebSocket and passes net.Conn to a handler.
+// The conn's RemoteAddr is the client's address, read from X-Forwarded-For when the request
+// comes through one of the trusted proxies.
+func StartWebSocketServer(addr string, trustedProxies []*net.IPNet, handler func(net.Conn)) error {
 	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		c, err := websocket.Accept(w, r, &websocket.AcceptOptions{
 			InsecureSkipVerify: true, // Allow all origins for prototype
@@ -36,7 +40,7 @@ func StartWebSocketServer(addr string, handler func(net.Conn)) error {
 		conn := websocket.NetConn(context.Background(), c, websocket.MessageBinary)
 
 		// Hand off to existing handler
-		go handler(conn)
+		go handler(&clientAddrConn{Conn: conn, addr: &net.IPAddr{IP: net.ParseIP(ClientIP(r, trustedProxies))}})
 	})
 
 	// Also serve static files for the client!
@@ -44,3 +48,80 @@ func StartWebSocketServer(addr string, handler func(net.Conn)) error {
 
 	return http.ListenAndServe(addr, nil)
 }
+
+// clientAddrConn reports the resolved client address instead of the proxy's
+type clientAddrConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c *clientAddrConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// ClientIP returns the address of the client behind a request. X-Forwarded-For is only
+// believed when the direct peer is a trusted proxy, and is read right to left so a client
+// can't spoof its address by sending the header itself.
+func ClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !ipIn(ip, trustedProxies) {
+		return ip
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !ipIn(hop, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+// ParseTrustedProxies reads a comma separated list of IPs and CIDR ranges ("" = none)
+func ParseTrustedProxies(list string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid proxy address %q", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy range %q: %w", entry, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+func ipIn(s string, nets []*net.IPNet) bool {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,356 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/shared/world"
+	"henry/pkg/storage"
+)
+
+// Seconds-remaining marks at which a scheduled shutdown is announced
+var shutdownWarnings = []float64{900, 600, 300, 120, 60, 30, 10, 5}
+
+const MaintenanceMessage = "The server is down for maintenance. Please try again later."
+
+// ScheduleShutdown stops the server after delay seconds, warning players along the way.
+// Call with the server lock held.
+func (s *GameServer) ScheduleShutdown(delay float64, reason string) {
+	s.shutdownTimer = delay
+	s.shutdownReason = reason
+	s.shutdownScheduled = true
+	s.nextWarning = 0
+	for s.nextWarning < len(shutdownWarnings) && shutdownWarnings[s.nextWarning] >= delay {
+		s.nextWarning++
+	}
+	log.Printf("Shutdown scheduled in %.0fs (%s)", delay, reason)
+	s.Announce(shutdownMessage(delay, reason))
+}
+
+// CancelShutdown aborts a scheduled shutdown. Call with the server lock held.
+func (s *GameServer) CancelShutdown() {
+	if !s.shutdownScheduled {
+		return
+	}
+	s.shutdownScheduled = false
+	log.Printf("Scheduled shutdown cancelled")
+	s.Announce("The scheduled server restart has been cancelled.")
+}
+
+func (s *GameServer) updateShutdown(dt float64) {
+	if !s.shutdownScheduled {
+		return
+	}
+
+	s.shutdownTimer -= dt
+	if s.nextWarning < len(shutdownWarnings) && s.shutdownTimer <= shutdownWarnings[s.nextWarning] {
+		s.Announce(shutdownMessage(shutdownWarnings[s.nextWarning], s.shutdownReason))
+		s.nextWarning++
+	}
+
+	if s.shutdownTimer <= 0 {
+		log.Printf("Scheduled shutdown reached, saving and exiting")
+		reason := s.shutdownReason
+		if reason == "" {
+			reason = "Scheduled restart"
+		}
+		s.stop(reason)
+	}
+}
+
+// stop saves everything, lets the webhooks know and exits. Call with the server lock held.
+func (s *GameServer) stop(reason string) {
+	s.saveAll()
+	s.Events.Publish(systems.EventServerStop, "Server offline", reason)
+	s.WebhookSystem.Close(5 * time.Second)
+	os.Exit(0)
+}
+
+// SetBanned bans or unbans an account. A banned player who is online is disconnected.
+// Call with the server lock held.
+func (s *GameServer) SetBanned(username string, banned bool, reason string) error {
+	saved, err := storage.LoadPlayer(username)
+	if err != nil {
+		return err
+	}
+	if saved == nil {
+		return fmt.Errorf("no player named %q", username)
+	}
+	saved.Banned = banned
+	saved.BanReason = ""
+	if banned {
+		saved.BanReason = reason
+	}
+	if err := storage.SavePlayer(*saved); err != nil {
+		return err
+	}
+	if !banned {
+		log.Printf("Unbanned %s", username)
+		return nil
+	}
+
+	log.Printf("Banned %s (%s)", username, reason)
+	message := username + " was banned"
+	if reason != "" {
+		message += ": " + reason
+	}
+	s.Events.Publish(systems.EventBan, "Player banned", message)
+	for _, player := range s.Players {
+		if player.Username != username || player.Kicked || player.Conn == nil {
+			continue
+		}
+		player.Kicked = true
+		packet := protocol.Packet{Type: protocol.PacketAnnouncement, Data: protocol.AnnouncementPacket{Message: BanMessage(reason)}}
+		// The read loop notices the closed connection and removes (and saves) the player
+		player.Send(packet)
+		player.Close()
+	}
+	return nil
+}
+
+// BanMessage is what a banned player is told
+func BanMessage(reason string) string {
+	if reason == "" {
+		return "This account is banned."
+	}
+	return "This account is banned: " + reason
+}
+
+// saveAll persists every connected player, crop growth, the leaderboard, the chat log (and NPC state if enabled). Call with the server lock held.
+func (s *GameServer) saveAll() {
+	for id, player := range s.Players {
+		log.Printf("Saving player %s...", player.Username)
+		s.PersistenceSystem.SavePlayer(id, player.Username)
+	}
+	if s.FarmSystem.Dirty() {
+		s.saveCrops()
+	}
+	s.savePlaytimeLeaderboard()
+	if s.ChatSystem.Log.Dirty() {
+		if err := storage.SaveChatLog(s.ChatSystem.Log.Saved()); err != nil {
+			log.Printf("Failed to save chat log: %v", err)
+		}
+	}
+	s.TelemetrySystem.Flush()
+	s.flushItemAudit()
+	if s.PersistNPCs {
+		if err := s.NPCStateSystem.Checkpoint(); err != nil {
+			log.Printf("NPC checkpoint failed: %v", err)
+		}
+	}
+}
+
+// ExportRealm saves every player and shared file, checkpoints the NPCs, and packs the data
+// folder into an archive that "server -import" restores on another host. Arena matches in
+// progress live only in memory and aren't included. Call with the server lock held.
+func (s *GameServer) ExportRealm(file string) error {
+	s.saveAll()
+	if !s.PersistNPCs {
+		if err := s.NPCStateSystem.Checkpoint(); err != nil {
+			return err
+		}
+	}
+	n, err := storage.ExportRealm(file, time.Now())
+	if err != nil {
+		return err
+	}
+	log.Printf("Exported %d file(s) to %s", n, file)
+	return nil
+}
+
+// ReloadMap re-reads a level's map from the map directory and swaps it in: crops, triggers
+// and waypoints are rebuilt from it, entities now inside solid ground are moved out, and
+// every player on the level gets a fresh map sync. Spawners only change on a restart.
+// Call with the server lock held.
+func (s *GameServer) ReloadMap(level int) error {
+	maps, err := world.LoadMaps(s.mapDir)
+	if err != nil {
+		return err
+	}
+	fresh, ok := maps[level]
+	if !ok {
+		return fmt.Errorf("no level %d map in %s", level, s.mapDir)
+	}
+	if current, ok := s.Maps[level]; ok {
+		*current = *fresh // Systems may hold the *Map itself
+	} else {
+		s.Maps[level] = fresh
+	}
+
+	s.AISystem.InvalidatePaths(s.Maps[level])
+	s.AISystem.PreparePaths()
+	s.FarmSystem.ReloadLevel(level)
+	s.TriggerSystem.ReloadLevel(level)
+	s.WaypointSystem.ReloadLevel(level)
+	s.SeasonalSystem.ReloadLevel(level)
+	moved := s.MovementSystem.RelocateStuck(level)
+
+	synced := 0
+	for id, player := range s.Players {
+		if trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id); ok && trans.Z == level {
+			s.SendMapSync(player)
+			synced++
+		}
+	}
+	log.Printf("Reloaded level %d map (%dx%d): %d player(s) resynced, %d entit(ies) moved out of solids",
+		level, fresh.Width, fresh.Height, synced, moved)
+	checkMaps(s.Maps)
+	return nil
+}
+
+func shutdownMessage(seconds float64, reason string) string {
+	var when string
+	if seconds >= 60 {
+		when = fmt.Sprintf("%.0f minute(s)", seconds/60)
+	} else {
+		when = fmt.Sprintf("%.0f seconds", seconds)
+	}
+	msg := "Server restarting in " + when
+	if reason != "" {
+		msg += ": " + reason
+	}
+	return msg
+}
+
+// RunConsole reads operator commands (one per line) until the reader is closed:
+//
+//	maintenance on|off
+//	motd [text]            (empty clears)
+//	shutdown <seconds> [reason]
+//	shutdown cancel
+//	say <message>
+//	economy reload         (also picked up automatically when the file changes)
+//	leaderboard [playtime] (top duelists, or most active playtime)
+//	status                 (population, login queue and scheduled tasks)
+//	schedule run <id>      (run a scheduled task now)
+//	reload map <level>     (re-read a level's map file; players on it are resynced)
+//	telemetry [flush]      (counts since the last export, or export them now)
+//	export [file]          (save everything and pack the data folder for another host)
+//	ban <player> [reason]  (refuse the account's logins and disconnect it)
+//	unban <player>
+//	season [on|off|auto <id>] (list seasons, or force one on or off, or back to its dates)
+//	macros [clear <player>] (accounts flagged for likely macro use, or drop a player's flags)
+//	chat <player> [lines]  (what a player said lately, on every channel)
+//	chat slow <seconds>|off (slow mode for global chat)
+//	chat filter reload     (re-read the bad-word lists)
+func (s *GameServer) RunConsole(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		cmd, args, _ := strings.Cut(line, " ")
+		args = strings.TrimSpace(args)
+
+		s.Mutex.Lock()
+		switch cmd {
+		case "maintenance":
+			s.Maintenance = args == "on"
+			log.Printf("Maintenance mode: %v", s.Maintenance)
+		case "motd":
+			s.MOTD = args
+			log.Printf("MOTD set to %q", s.MOTD)
+		case "shutdown":
+			if args == "cancel" {
+				s.CancelShutdown()
+				break
+			}
+			delayStr, reason, _ := strings.Cut(args, " ")
+			delay, err := strconv.ParseFloat(delayStr, 64)
+			if err != nil || delay <= 0 {
+				log.Printf("Usage: shutdown <seconds> [reason] | shutdown cancel")
+				break
+			}
+			s.ScheduleShutdown(delay, strings.TrimSpace(reason))
+		case "say":
+			s.Announce(args)
+		case "economy":
+			if args != "reload" {
+				log.Printf("Usage: economy reload")
+				break
+			}
+			if err := s.EconomySystem.Reload(); err != nil {
+				log.Printf("Economy reload failed, keeping previous config: %v", err)
+			}
+		case "leaderboard":
+			if args == "playtime" {
+				s.recordPlaytime()
+				for i, e := range s.Leaderboard.TopPlaytime(10) {
+					log.Printf("%2d. %-16s %.1f h", i+1, e.Username, e.Playtime/3600)
+				}
+				break
+			}
+			for i, e := range s.Leaderboard.TopDuelists(10) {
+				log.Printf("%2d. %-16s %d W / %d L / %d D (arena %d W / %d L)", i+1, e.Username, e.DuelWins, e.DuelLosses, e.DuelDraws, e.ArenaWins, e.ArenaLosses)
+			}
+		case "status":
+			s.logStatus()
+		case "schedule":
+			action, id, _ := strings.Cut(args, " ")
+			if action != "run" || id == "" {
+				log.Printf("Usage: schedule run <id>")
+				break
+			}
+			if err := s.SchedulerSystem.RunNow(strings.TrimSpace(id)); err != nil {
+				log.Printf("Schedule run %s failed: %v", id, err)
+			}
+		case "reload":
+			what, levelStr, _ := strings.Cut(args, " ")
+			level, err := strconv.Atoi(strings.TrimSpace(levelStr))
+			if what != "map" || err != nil {
+				log.Printf("Usage: reload map <level>")
+				break
+			}
+			if err := s.ReloadMap(level); err != nil {
+				log.Printf("Map reload of level %d failed, keeping the current map: %v", level, err)
+			}
+		case "telemetry":
+			if args == "flush" {
+				if path := s.TelemetrySystem.Flush(); path != "" {
+					log.Printf("Telemetry exported to %s", path)
+				}
+				break
+			}
+			s.logTelemetry()
+		case "export":
+			file := args
+			if file == "" {
+				file = storage.ExportPath(time.Now())
+			}
+			if err := s.ExportRealm(file); err != nil {
+				log.Printf("Export failed: %v", err)
+			}
+		case "ban", "unban":
+			name, reason, _ := strings.Cut(args, " ")
+			if name == "" {
+				log.Printf("Usage: ban <player> [reason] | unban <player>")
+				break
+			}
+			if err := s.SetBanned(name, cmd == "ban", strings.TrimSpace(reason)); err != nil {
+				log.Printf("%s %s failed: %v", cmd, name, err)
+			}
+		case "season":
+			s.seasonCommand(args)
+		case "macros":
+			s.macrosCommand(args)
+		case "audit":
+			s.auditCommand(args)
+		case "chat":
+			s.chatCommand(args)
+		default:
+			log.Printf("Unknown console command %q (maintenance, motd, shutdown, say, economy, leaderboard, status, schedule, reload, telemetry, export, ban, unban, season, macros, audit, chat)", cmd)
+		}
+		s.Mutex.Unlock()
+	}
+}
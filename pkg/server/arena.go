@@ -0,0 +1,125 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/storage"
+)
+
+func (s *GameServer) handleArena(player *Player, req protocol.ArenaPacket) {
+	var err error
+	s.withLock(func() {
+		switch req.Action {
+		case "join":
+			if s.DuelSystem.DuelOf(player.EntityID) != nil {
+				err = errors.New("finish your duel first")
+				return
+			}
+			if err = s.ArenaSystem.Join(player.EntityID); err == nil && s.ArenaSystem.IsQueued(player.EntityID) {
+				s.Notify(player, "Joined the arena queue")
+			}
+		case "leave":
+			s.ArenaSystem.Leave(player.EntityID)
+			s.sendArenaState(player.EntityID)
+		default:
+			err = fmt.Errorf("unknown arena action %q", req.Action)
+		}
+	})
+	if err != nil {
+		log.Printf("Player %s arena %s failed: %v", player.Username, req.Action, err)
+		s.Notify(player, "Arena: "+err.Error())
+	}
+}
+
+// sendArenaState tells a player about their queue spot or match. Assumes s.Mutex is LOCKED.
+func (s *GameServer) sendArenaState(id ecs.Entity) {
+	player, ok := s.Players[id]
+	if !ok {
+		return
+	}
+	data := protocol.ArenaStatePacket{QueueNeeded: s.ArenaSystem.QueueNeeded()}
+	if match := s.ArenaSystem.MatchOf(id); match != nil {
+		data.InMatch = true
+		data.Team = match.TeamOf(id)
+		data.Score = match.Score
+		data.Round = match.Round
+		data.Countdown = match.Countdown
+		data.TimeLeft = match.TimeLeft
+	} else if s.ArenaSystem.IsQueued(id) {
+		data.Queued = true
+	}
+	player.Send(protocol.Packet{Type: protocol.PacketArenaState, Data: data})
+}
+
+// syncArenaQueue refreshes the queue size for everyone waiting (and clears it for a
+// player who left). Assumes s.Mutex is LOCKED.
+func (s *GameServer) syncArenaQueue(queued []ecs.Entity, left ecs.Entity) {
+	for _, id := range queued {
+		player, ok := s.Players[id]
+		if !ok {
+			continue
+		}
+		player.Send(protocol.Packet{
+			Type: protocol.PacketArenaState,
+			Data: protocol.ArenaStatePacket{Queued: true, QueueSize: len(queued), QueueNeeded: s.ArenaSystem.QueueNeeded()},
+		})
+	}
+	if left != 0 {
+		s.sendArenaState(left)
+	}
+}
+
+// finishArenaMatch hands out gold, records the result and clears the match UI.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) finishArenaMatch(match *systems.ArenaMatch, winner int) {
+	var msg string
+	if winner < 0 {
+		msg = fmt.Sprintf("The arena match ended in a draw (%d - %d)", match.Score[0], match.Score[1])
+	}
+
+	for team, members := range match.Teams {
+		won := team == winner
+		reward := systems.ArenaLossGold
+		if won {
+			reward = systems.ArenaWinGold
+		}
+		for _, id := range members {
+			player, ok := s.Players[id]
+			if !ok {
+				continue
+			}
+			text := msg
+			if winner >= 0 {
+				text = fmt.Sprintf("Defeat (%d - %d)", match.Score[team], match.Score[1-team])
+				if won {
+					text = fmt.Sprintf("Victory! (%d - %d)", match.Score[team], match.Score[1-team])
+				}
+			}
+			s.Notify(player, fmt.Sprintf("%s You earned %d gold", text, reward))
+			s.giveArenaReward(player, reward)
+			s.sendArenaState(id)
+
+			if winner >= 0 {
+				s.Leaderboard.RecordArena(player.Username, won)
+			}
+		}
+	}
+
+	if winner >= 0 {
+		if err := storage.SaveLeaderboard(s.Leaderboard); err != nil {
+			log.Printf("Failed to save leaderboard: %v", err)
+		}
+	}
+}
+
+// giveArenaReward puts gold in the player's wallet. Assumes s.Mutex is LOCKED.
+func (s *GameServer) giveArenaReward(player *Player, gold int) {
+	systems.AddGold(s.World, player.EntityID, gold)
+	s.PersistenceSystem.SavePlayer(player.EntityID, player.Username)
+	s.SendInventorySync(player)
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"henry/pkg/items"
+	"henry/pkg/storage"
+)
+
+// auditShown is how many of the latest matching entries the "audit" command prints
+const auditShown = 50
+
+// flushItemAudit appends the tick's item events to the audit file. A failing disk is
+// logged once, not every tick; the entries wait for the next successful write.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) flushItemAudit() {
+	if err := s.ItemAudit.Flush(); err != nil {
+		if !s.auditFailing {
+			log.Printf("Item audit write failed, holding %d entries: %v", len(s.ItemAudit.Pending()), err)
+		}
+		s.auditFailing = true
+		return
+	}
+	if s.auditFailing {
+		log.Printf("Item audit writes resumed")
+	}
+	s.auditFailing = false
+}
+
+// auditCommand is the "audit" console command: "audit <player|item|instance>" prints
+// the latest item events involving it, and "audit restore <player> <item> [qty]" mails
+// lost items back. Assumes s.Mutex is LOCKED.
+func (s *GameServer) auditCommand(args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		log.Printf("Usage: audit <player|item|instance> | audit restore <player> <item> [quantity]")
+		return
+	}
+	if fields[0] == "restore" {
+		s.restoreItem(fields[1:])
+		return
+	}
+
+	query := fields[0]
+	s.flushItemAudit() // Include this tick's events
+	entries, err := storage.ReadItemAudit(s.ItemAudit.Path, func(e storage.ItemAuditEntry) bool {
+		return e.Account == query || e.Other == query || e.ItemID == query || e.InstanceID == query
+	})
+	if err != nil {
+		log.Printf("Reading the item audit failed: %v", err)
+		return
+	}
+	if len(entries) == 0 {
+		log.Printf("No item events for %q", query)
+		return
+	}
+	if len(entries) > auditShown {
+		log.Printf("%d events for %q, showing the latest %d", len(entries), query, auditShown)
+		entries = entries[len(entries)-auditShown:]
+	}
+	for _, e := range entries {
+		line := e.Time.Format("2006-01-02 15:04:05") + " " + e.Action + " " + strconv.Itoa(e.Quantity) + "x " + e.ItemID
+		if e.InstanceID != "" {
+			line += " [" + e.InstanceID + "]"
+		}
+		if e.Account != "" {
+			line += " account=" + e.Account
+		}
+		if e.Other != "" {
+			line += " other=" + e.Other
+		}
+		log.Print(line)
+	}
+}
+
+// restoreItem mails items to a player, e.g. ones the audit shows were lost to a bug
+func (s *GameServer) restoreItem(args []string) {
+	if len(args) < 2 || len(args) > 3 {
+		log.Printf("Usage: audit restore <player> <item> [quantity]")
+		return
+	}
+	username, itemID, quantity := args[0], args[1], 1
+	if len(args) == 3 {
+		n, err := strconv.Atoi(args[2])
+		if err != nil || n <= 0 {
+			log.Printf("Usage: audit restore <player> <item> [quantity]")
+			return
+		}
+		quantity = n
+	}
+	if _, ok := items.Get(itemID); !ok {
+		log.Printf("Unknown item %q", itemID)
+		return
+	}
+	if _, err := storage.LoadPlayer(username); err != nil {
+		log.Printf("Unknown player %q: %v", username, err)
+		return
+	}
+	s.MailSystem.Send(username, "Support", "Restored items", []storage.MailItem{{ItemID: itemID, Quantity: quantity}})
+	log.Printf("Mailed %dx %s to %s", quantity, itemID, username)
+}
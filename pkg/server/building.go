@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"log"
+
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/storage"
+)
+
+// handleBuild places a structure from the player's inventory or demolishes one. Inside
+// a house it furnishes the house instead.
+func (s *GameServer) handleBuild(player *Player, req protocol.BuildPacket) {
+	var err error
+	s.withLock(func() {
+		if s.HousingSystem.Inside(player.EntityID) != nil {
+			err = s.furnish(player, req) // The house saves itself
+			return
+		}
+		switch req.Action {
+		case "place":
+			_, err = s.BuildingSystem.Place(player.EntityID, player.Username, req.ItemID, req.TileX, req.TileY)
+		case "demolish":
+			err = s.BuildingSystem.Demolish(player.EntityID, player.Username, req.TargetID)
+		default:
+			err = fmt.Errorf("unknown build action %q", req.Action)
+		}
+		if err != nil {
+			return
+		}
+		// Saved under the lock so concurrent builds can't interleave their writes
+		if saveErr := storage.SaveStructures(s.BuildingSystem.Save()); saveErr != nil {
+			log.Printf("Failed to save structures: %v", saveErr)
+		}
+	})
+	if err != nil {
+		log.Printf("Player %s build %s failed: %v", player.Username, req.Action, err)
+		s.Notify(player, "Build: "+err.Error())
+		return
+	}
+	s.withLock(func() {
+		s.PersistenceSystem.SavePlayer(player.EntityID, player.Username)
+		s.SendInventorySync(player)
+	})
+}
+
+// furnish places or picks up furniture in the player's house. Assumes s.Mutex is LOCKED.
+func (s *GameServer) furnish(player *Player, req protocol.BuildPacket) error {
+	switch req.Action {
+	case "place":
+		_, err := s.HousingSystem.Place(player.EntityID, player.Username, req.ItemID, req.TileX, req.TileY)
+		return err
+	case "demolish":
+		return s.HousingSystem.Remove(player.EntityID, player.Username, req.TargetID)
+	default:
+		return fmt.Errorf("unknown build action %q", req.Action)
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"log"
+
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/storage"
+)
+
+// Players listed in the character sheet's playtime leaderboard
+const playtimeLeaderboardSize = 10
+
+// handleCharacter sends the player's character sheet
+func (s *GameServer) handleCharacter(player *Player, req protocol.CharacterPacket) {
+	s.withLock(func() {
+		id := player.EntityID
+		data := protocol.CharacterSheetPacket{
+			AFK:    s.PlaytimeSystem.IsAFK(id),
+			Skills: make(map[string]int),
+		}
+		if playtime, ok := ecs.GetComponent[components.PlaytimeComponent](s.World, id); ok {
+			data.Playtime = playtime.Seconds
+		}
+		if skills, ok := ecs.GetComponent[components.SkillsComponent](s.World, id); ok {
+			data.Skills[systems.SkillFishing] = systems.FishingLevel(skills.XP[systems.SkillFishing])
+		}
+		if stats, ok := ecs.GetComponent[components.StatsComponent](s.World, id); ok {
+			data.Skills[systems.SkillCombat] = stats.Level
+		}
+		stats := systems.Stats(s.World, id)
+		data.Attributes = stats.Attributes
+		data.MeleeDamage, data.RangedDamage, data.SpellPower = stats.MeleeDamage, stats.RangedDamage, stats.SpellPower
+		data.CooldownReduction, data.AttackSpeed = stats.CooldownReduction, stats.AttackSpeed
+		if req.Leaderboard {
+			// Online players' rows are refreshed first so the ranking isn't an autosave behind
+			s.recordPlaytime()
+			for _, e := range s.Leaderboard.TopPlaytime(playtimeLeaderboardSize) {
+				data.TopPlaytime = append(data.TopPlaytime, protocol.PlaytimeRank{Username: e.Username, Playtime: e.Playtime})
+			}
+		}
+		player.Send(protocol.Packet{Type: protocol.PacketCharacterSheet, Data: data})
+	})
+}
+
+// recordPlaytime copies online players' playtime into the leaderboard. Assumes s.Mutex is LOCKED.
+func (s *GameServer) recordPlaytime() {
+	for id, player := range s.Players {
+		if playtime, ok := ecs.GetComponent[components.PlaytimeComponent](s.World, id); ok {
+			s.Leaderboard.RecordPlaytime(player.Username, playtime.Seconds)
+		}
+	}
+}
+
+// kickAFK disconnects the longest-idle AFK players while more than AFKKickAbove players
+// are online, freeing room for active ones. Admins are never kicked. Assumes s.Mutex is LOCKED.
+func (s *GameServer) kickAFK() {
+	if s.AFKKickAbove <= 0 {
+		return
+	}
+	online := 0
+	for _, player := range s.Players {
+		if !player.Kicked {
+			online++
+		}
+	}
+	for _, id := range s.PlaytimeSystem.AFKPlayers() {
+		if online <= s.AFKKickAbove {
+			return
+		}
+		player, ok := s.Players[id]
+		if !ok || player.Kicked || player.IsAdmin || player.Conn == nil {
+			continue
+		}
+		player.Kicked = true
+		online--
+		log.Printf("Disconnecting AFK player %s (%d online, limit %d)", player.Username, online+1, s.AFKKickAbove)
+		packet := protocol.Packet{
+			Type: protocol.PacketAnnouncement,
+			Data: protocol.AnnouncementPacket{Message: "Disconnected for being AFK while the server is busy"},
+		}
+		// The read loop notices the closed connection and removes (and saves) the player
+		player.Send(packet)
+		player.Close()
+	}
+}
+
+// savePlaytimeLeaderboard records online players' playtime and writes the leaderboard.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) savePlaytimeLeaderboard() {
+	s.recordPlaytime()
+	if err := storage.SaveLeaderboard(s.Leaderboard); err != nil {
+		log.Printf("Failed to save leaderboard: %v", err)
+	}
+}
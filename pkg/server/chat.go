@@ -0,0 +1,167 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/storage"
+)
+
+// handleChat checks a chat line and sends it to everyone on its channel
+func (s *GameServer) handleChat(player *Player, req protocol.ChatMessagePacket) {
+	s.withLock(func() {
+		now := time.Now()
+		text, err := s.ChatSystem.Check(player.EntityID, req.Channel, req.Text, now)
+		if err != nil {
+			s.sendChatNotice(player, "Not sent: "+err.Error())
+			return
+		}
+		msg := protocol.ChatBroadcastPacket{Channel: req.Channel, From: player.Username, Text: text}
+		line := storage.ChatLine{Time: now, Channel: req.Channel, From: player.Username, Text: text}
+		if original := systems.CleanChat(req.Text); original != text {
+			line.Original = original
+		}
+
+		switch req.Channel {
+		case protocol.ChatGlobal:
+			log.Printf("[global] %s: %s", player.Username, text)
+			for id := range s.Players {
+				s.sendSpoken(player, id, msg)
+			}
+		case protocol.ChatLocal:
+			for id := range s.Players {
+				if id == player.EntityID || s.ChatSystem.InRange(player.EntityID, id) {
+					s.sendSpoken(player, id, msg)
+				}
+			}
+		case protocol.ChatZone:
+			zone := s.zoneName(player.EntityID)
+			if zone == "" {
+				s.sendChatNotice(player, "You are not in a zone")
+				return
+			}
+			for id := range s.Players {
+				if s.zoneName(id) == zone {
+					s.sendSpoken(player, id, msg)
+				}
+			}
+		case protocol.ChatWhisper:
+			target := s.playerByName(req.To)
+			if target == nil {
+				s.sendChatNotice(player, req.To+" is not online")
+				return
+			}
+			if target == player {
+				s.sendChatNotice(player, "You can't whisper to yourself")
+				return
+			}
+			msg.To = target.Username
+			line.To = target.Username
+			s.sendChat(target, msg)
+			s.sendChat(player, msg)
+		default:
+			s.sendChatNotice(player, fmt.Sprintf("Unknown chat channel %q", req.Channel))
+			return
+		}
+		s.ChatSystem.Log.Add(line)
+	})
+}
+
+// sendSpoken sends a line said by from to a player, with a speech bubble when they're
+// close enough to see from. Assumes s.Mutex is LOCKED.
+func (s *GameServer) sendSpoken(from *Player, to ecs.Entity, msg protocol.ChatBroadcastPacket) {
+	if to == from.EntityID || s.ChatSystem.InRange(from.EntityID, to) {
+		msg.Speaker = from.EntityID
+	}
+	s.sendChat(s.Players[to], msg)
+}
+
+// playerByName finds an online player, ignoring case. Assumes s.Mutex is LOCKED.
+func (s *GameServer) playerByName(username string) *Player {
+	for _, p := range s.Players {
+		if strings.EqualFold(p.Username, username) {
+			return p
+		}
+	}
+	return nil
+}
+
+// sendChatNotice shows a server message in one player's chat window
+func (s *GameServer) sendChatNotice(player *Player, text string) {
+	s.sendChat(player, protocol.ChatBroadcastPacket{Channel: protocol.ChatNotice, Text: text})
+}
+
+func (s *GameServer) sendChat(player *Player, msg protocol.ChatBroadcastPacket) {
+	player.Send(protocol.Packet{Type: protocol.PacketChatBroadcast, Data: msg})
+}
+
+// chatShown is how many lines "chat <player>" prints when not told
+const chatShown = 20
+
+// chatCommand is the "chat" console command for moderators: "chat <player> [lines]"
+// prints what a player said lately, "chat slow <seconds>|off" paces global chat, and
+// "chat filter reload" re-reads the word lists. Assumes s.Mutex is LOCKED.
+func (s *GameServer) chatCommand(args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		log.Printf("Usage: chat <player> [lines] | chat slow <seconds>|off | chat filter reload")
+		return
+	}
+	switch {
+	case fields[0] == "slow" && len(fields) == 2:
+		seconds := 0.0
+		if fields[1] != "off" {
+			var err error
+			if seconds, err = strconv.ParseFloat(fields[1], 64); err != nil || seconds < 0 {
+				log.Printf("Usage: chat slow <seconds>|off")
+				return
+			}
+		}
+		s.ChatSystem.SlowMode = seconds
+		notice := "Global chat slow mode is off"
+		if seconds > 0 {
+			notice = fmt.Sprintf("Global chat is in slow mode: one message every %gs", seconds)
+		}
+		log.Print(notice)
+		for _, player := range s.Players {
+			s.sendChatNotice(player, notice)
+		}
+	case fields[0] == "filter" && len(fields) == 2 && fields[1] == "reload":
+		words, err := systems.LoadChatFilter(systems.ChatFilterFile)
+		if err != nil {
+			log.Printf("Chat filter reload failed, keeping the current words: %v", err)
+			return
+		}
+		s.ChatSystem.SetFilter(words)
+		log.Printf("Loaded %d chat filter word(s)", len(words))
+	default:
+		shown := chatShown
+		if len(fields) > 1 {
+			if n, err := strconv.Atoi(fields[1]); err == nil && n > 0 {
+				shown = n
+			}
+		}
+		lines := s.ChatSystem.Log.From(fields[0], shown)
+		if len(lines) == 0 {
+			log.Printf("No recent chat from %q", fields[0])
+			return
+		}
+		for _, line := range lines {
+			text := line.Time.Format("2006-01-02 15:04:05") + " [" + line.Channel + "] "
+			if line.To != "" {
+				text += "to " + line.To + ": "
+			}
+			text += line.Text
+			if line.Original != "" {
+				text += " (typed: " + line.Original + ")"
+			}
+			log.Print(text)
+		}
+	}
+}
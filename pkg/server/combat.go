@@ -0,0 +1,112 @@
+package server
+
+import (
+	"fmt"
+	"log"
+
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+)
+
+// subscribeCombat wires the reactions to hits, deaths and pickups. Laying the dead down
+// subscribes last so every other handler still finds the victim as it fell.
+func (s *GameServer) subscribeCombat() {
+	ecs.Subscribe(s.World, func(e components.DamageEvent) {
+		s.WorldEventSystem.RecordDamage(e.Attacker, e.Target, e.Dealt)
+		s.LootSystem.RecordDamage(e.Attacker, e.Target)
+	})
+	ecs.Subscribe(s.World, s.aggroOnDamage)
+	ecs.Subscribe(s.World, s.tallyKill)
+	ecs.Subscribe(s.World, s.awardKill)
+	ecs.Subscribe(s.World, s.dropOnDeath)
+	ecs.Subscribe(s.World, s.syncPickup)
+	ecs.Subscribe(s.World, s.layDown)
+}
+
+// aggroOnDamage makes an NPC that survived a hit chase its attacker, and rallies its allies
+func (s *GameServer) aggroOnDamage(e components.DamageEvent) {
+	if e.Killed || e.Attacker == 0 {
+		return
+	}
+	ai, ok := ecs.GetComponent[components.AIComponent](s.World, e.Target)
+	if !ok {
+		return
+	}
+	if ai.TargetID == 0 {
+		ai.TargetID = e.Attacker
+		ai.State = "chase"
+		ecs.AddComponent(s.World, e.Target, *ai)
+		log.Printf("Entity %d is now chasing Entity %d", e.Target, e.Attacker)
+	}
+	// Nearby allies join in
+	s.AISystem.CallForHelp(e.Target, e.Attacker)
+}
+
+// tallyKill counts a player's kill against the victim's spawner. It subscribes ahead of
+// the XP and drops, which are thinned out once the player farms one spawner too long.
+func (s *GameServer) tallyKill(e components.DeathEvent) {
+	killer, ok := s.Players[e.Killer]
+	spawner := systems.SpawnerOf(s.World, e.Victim)
+	if !ok || spawner == "" {
+		return
+	}
+	if s.Diminishing.Kill(killer.Username, spawner) == systems.DiminishFreeKills+1 {
+		s.Notify(killer, "The hunting here is thinning out: XP and loot drop off until you hunt elsewhere for a while.")
+	}
+}
+
+// rewardScale is how much of the usual XP and loot a kill is worth to the killer
+func (s *GameServer) rewardScale(killer, victim ecs.Entity) float64 {
+	player, ok := s.Players[killer]
+	if !ok {
+		return 1
+	}
+	return s.Diminishing.Scale(player.Username, systems.SpawnerOf(s.World, victim))
+}
+
+// awardKill shows the death, records it and gives a killing player their combat XP
+func (s *GameServer) awardKill(e components.DeathEvent) {
+	s.broadcastDeath(e.Victim)
+	s.recordDeath(e.Victim, e.Killer)
+	killer, ok := s.Players[e.Killer]
+	if !ok {
+		return
+	}
+	xp, leveledUp := systems.AwardCombatXP(s.World, e.Killer, e.Victim, s.rewardScale(e.Killer, e.Victim))
+	if xp <= 0 {
+		return
+	}
+	s.Notify(killer, fmt.Sprintf("+%d combat XP", xp))
+	if leveledUp {
+		stats, _ := ecs.GetComponent[components.StatsComponent](s.World, e.Killer)
+		level := stats.Level
+		s.Notify(killer, fmt.Sprintf("Level %d! Max health %.0f", level, stats.MaxHealth))
+		s.broadcastLevelUp(e.Killer)
+		if level == config.MaxCombatLevel {
+			s.Events.Publish(systems.EventMaxLevel, "Max level reached", fmt.Sprintf("%s reached combat level %d", killer.Username, level))
+		}
+	}
+}
+
+// dropOnDeath leaves the victim's gold and items on the ground and rolls boss loot
+func (s *GameServer) dropOnDeath(e components.DeathEvent) {
+	s.dropGold(e.Victim, e.Killer)
+	s.dropItems(e.Victim, e.Killer)
+	s.LootSystem.DropLoot(e.Victim)
+}
+
+// layDown leaves a dead NPC as a corpse for the DeathSystem to despawn. Players stay
+// where they fell.
+func (s *GameServer) layDown(e components.DeathEvent) {
+	s.DeathSystem.Kill(e.Victim, e.Killer)
+}
+
+// syncPickup saves a player who picked something up and sends them their inventory
+func (s *GameServer) syncPickup(e components.PickupEvent) {
+	if player, ok := s.Players[e.Player]; ok {
+		s.PersistenceSystem.SavePlayer(e.Player, player.Username)
+		s.SendInventorySync(player)
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net"
+	"sync"
+)
+
+// connLimiter caps simultaneous connections per client IP (logged in or not). It has its
+// own lock since connections are accepted outside the game loop.
+type connLimiter struct {
+	mu     sync.Mutex
+	limit  int // 0 = unlimited
+	counts map[string]int
+}
+
+func newConnLimiter(limit int) *connLimiter {
+	return &connLimiter{limit: limit, counts: make(map[string]int)}
+}
+
+// Acquire reserves a connection slot for ip, failing when it already has the limit open
+func (l *connLimiter) Acquire(ip string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limit > 0 && l.counts[ip] >= l.limit {
+		return false
+	}
+	l.counts[ip]++
+	return true
+}
+
+// Release frees a slot taken by Acquire
+func (l *connLimiter) Release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.counts[ip]--; l.counts[ip] <= 0 {
+		delete(l.counts, ip)
+	}
+}
+
+// remoteIP returns the IP part of a connection's remote address. WebSocket connections
+// already report the client behind any trusted proxy (see network.StartWebSocketServer).
+func remoteIP(conn net.Conn) string {
+	switch addr := conn.RemoteAddr().(type) {
+	case *net.TCPAddr:
+		return addr.IP.String()
+	case *net.IPAddr:
+		return addr.IP.String()
+	case nil:
+		return ""
+	default:
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			return addr.String()
+		}
+		return host
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"log"
+
+	"henry/pkg/characters"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+// handleCutsceneSkip ends a cutscene the player skipped on their client
+func (s *GameServer) handleCutsceneSkip(player *Player, req protocol.CutsceneSkipPacket) {
+	var err error
+	s.withLock(func() {
+		err = s.CutsceneSystem.Skip(player.EntityID, req.ID)
+	})
+	if err != nil {
+		log.Printf("Player %s skip cutscene %s failed: %v", player.Username, req.ID, err)
+	}
+}
+
+// sendCutscene starts (or, with no shots, ends) a cutscene on the player's client
+func (s *GameServer) sendCutscene(id ecs.Entity, packet protocol.CutscenePacket) {
+	if player, ok := s.Players[id]; ok {
+		player.Send(protocol.Packet{Type: protocol.PacketCutscene, Data: packet})
+	}
+}
+
+// npcEngaged plays a boss's intro to the player it just turned on. Assumes s.Mutex is LOCKED.
+func (s *GameServer) npcEngaged(player, npc ecs.Entity) {
+	respawn, ok := ecs.GetComponent[components.RespawnComponent](s.World, npc)
+	if !ok {
+		return
+	}
+	def, ok := characters.Get(respawn.CharID)
+	if !ok || def.Intro == "" {
+		return
+	}
+	if err := s.CutsceneSystem.Play(player, def.Intro, npc); err != nil {
+		log.Printf("Entity %d intro %s: %v", player, def.Intro, err)
+	}
+}
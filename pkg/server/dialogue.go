@@ -0,0 +1,47 @@
+package server
+
+import (
+	"log"
+
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+// wireDialogues hooks the dialogue system up to the players' clients
+func (s *GameServer) wireDialogues() {
+	s.DialogueSystem.OnShow = s.sendDialogue
+	s.DialogueSystem.OnTeleport = func(id ecs.Entity) {
+		s.AutoMoveSystem.Stop(id)
+		s.ArenaSystem.OnTeleport(id) // Same as any other move that may change level
+	}
+	s.DialogueSystem.Actions[systems.DialogueShop] = s.openShop
+}
+
+func (s *GameServer) handleInteract(player *Player, req protocol.InteractPacket) {
+	var err error
+	s.withLock(func() {
+		err = s.DialogueSystem.Interact(player.EntityID, req.TargetID)
+	})
+	if err != nil {
+		s.Notify(player, "Cannot talk: "+err.Error())
+	}
+}
+
+func (s *GameServer) handleDialogueChoice(player *Player, req protocol.DialogueChoicePacket) {
+	var err error
+	s.withLock(func() {
+		err = s.DialogueSystem.Choose(player.EntityID, req.Option)
+	})
+	if err != nil {
+		log.Printf("Player %s dialogue choice %d rejected: %v", player.Username, req.Option, err)
+		s.Notify(player, err.Error())
+	}
+}
+
+// sendDialogue shows the player a dialogue node (or, with no NPC, closes their window)
+func (s *GameServer) sendDialogue(id ecs.Entity, packet protocol.DialoguePacket) {
+	if player, ok := s.Players[id]; ok {
+		player.Send(protocol.Packet{Type: protocol.PacketDialogue, Data: packet})
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/storage"
+)
+
+func (s *GameServer) handleDuel(player *Player, req protocol.DuelPacket) {
+	var err error
+	s.withLock(func() {
+		if (req.Action == "request" || req.Action == "accept") &&
+			(s.ArenaSystem.MatchOf(player.EntityID) != nil || s.ArenaSystem.IsQueued(player.EntityID)) {
+			err = errors.New("not while queued for or in the arena")
+			return
+		}
+		switch req.Action {
+		case "request":
+			if err = s.DuelSystem.Request(player.EntityID, req.TargetID); err == nil {
+				s.Notify(player, "Duel challenge sent to "+s.displayName(req.TargetID))
+			}
+		case "accept":
+			_, err = s.DuelSystem.Accept(player.EntityID, req.TargetID)
+		case "decline":
+			s.DuelSystem.Decline(player.EntityID, req.TargetID)
+			if challenger, ok := s.Players[req.TargetID]; ok {
+				s.Notify(challenger, player.Username+" declined your duel")
+			}
+		case "forfeit":
+			s.DuelSystem.Forfeit(player.EntityID)
+		default:
+			err = fmt.Errorf("unknown duel action %q", req.Action)
+		}
+	})
+	if err != nil {
+		log.Printf("Player %s duel %s failed: %v", player.Username, req.Action, err)
+		s.Notify(player, "Duel: "+err.Error())
+	}
+}
+
+// sendDuelInvite asks the target to accept. Assumes s.Mutex is LOCKED.
+func (s *GameServer) sendDuelInvite(target, challenger ecs.Entity) {
+	player, ok := s.Players[target]
+	if !ok {
+		return
+	}
+	player.Send(protocol.Packet{
+		Type: protocol.PacketDuelInvite,
+		Data: protocol.DuelInvitePacket{
+			ChallengerID:   challenger,
+			ChallengerName: s.displayName(challenger),
+			Timeout:        systems.DuelRequestTimeout,
+		},
+	})
+}
+
+// sendDuelUpdate sends the ring/countdown to both duelists. Assumes s.Mutex is LOCKED.
+func (s *GameServer) sendDuelUpdate(duel *systems.Duel) {
+	for _, id := range []ecs.Entity{duel.A, duel.B} {
+		player, ok := s.Players[id]
+		if !ok {
+			continue
+		}
+		player.Send(protocol.Packet{
+			Type: protocol.PacketDuelState,
+			Data: protocol.DuelStatePacket{
+				Active:       true,
+				OpponentID:   duel.Opponent(id),
+				OpponentName: s.displayName(duel.Opponent(id)),
+				CenterX:      duel.CenterX,
+				CenterY:      duel.CenterY,
+				Radius:       duel.Radius,
+				Countdown:    duel.Countdown,
+			},
+		})
+		if duel.Started() {
+			s.Notify(player, "Fight!")
+		} else {
+			s.Notify(player, fmt.Sprintf("Duel vs %s starts in %.0f... stay inside the ring", s.displayName(duel.Opponent(id)), duel.Countdown))
+		}
+	}
+}
+
+// finishDuel clears the ring, reports the result and records it on the leaderboard.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) finishDuel(duel *systems.Duel, winner, loser ecs.Entity, reason string) {
+	var msg string
+	switch reason {
+	case systems.DuelEndTimeout:
+		msg = "The duel ended in a draw"
+	case systems.DuelEndRing:
+		msg = fmt.Sprintf("%s left the ring. %s wins the duel!", s.displayName(loser), s.displayName(winner))
+	case systems.DuelEndForfeit:
+		msg = fmt.Sprintf("%s forfeited. %s wins the duel!", s.displayName(loser), s.displayName(winner))
+	default:
+		msg = fmt.Sprintf("%s wins the duel!", s.displayName(winner))
+	}
+
+	for _, id := range []ecs.Entity{duel.A, duel.B} {
+		if player, ok := s.Players[id]; ok {
+			player.Send(protocol.Packet{Type: protocol.PacketDuelState, Data: protocol.DuelStatePacket{}})
+			s.Notify(player, msg)
+		}
+	}
+
+	winnerPlayer, ok1 := s.Players[winner]
+	loserPlayer, ok2 := s.Players[loser]
+	if !ok1 || !ok2 {
+		return
+	}
+	s.Leaderboard.RecordDuel(winnerPlayer.Username, loserPlayer.Username, reason == systems.DuelEndTimeout)
+	if err := storage.SaveLeaderboard(s.Leaderboard); err != nil {
+		log.Printf("Failed to save leaderboard: %v", err)
+	}
+}
+
+// displayName is the entity's name as players see it (entityLabel is for logs)
+func (s *GameServer) displayName(id ecs.Entity) string {
+	if name, ok := ecs.GetComponent[components.NameComponent](s.World, id); ok && name.Name != "" {
+		return name.Name
+	}
+	return s.entityLabel(id)
+}
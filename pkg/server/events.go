@@ -0,0 +1,82 @@
+package server
+
+import (
+	"fmt"
+	"image/color"
+
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	protocol "henry/pkg/shared/network"
+)
+
+// broadcastEvent sends a combat event to every player on the level close enough to see
+// it (the same range entity snapshots reach). Assumes s.Mutex is LOCKED.
+func (s *GameServer) broadcastEvent(level int, event protocol.EntityEventPacket) {
+	packet := protocol.Packet{Type: protocol.PacketEntityEvent, Data: event}
+	half := float64(config.TileSize) / 2
+	for id, player := range s.Players {
+		trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if !ok || trans.Z != level {
+			continue
+		}
+		if geom.Within(trans.X+half, trans.Y+half, event.X, event.Y, systems.NetAOIRadius) {
+			player.Send(packet)
+		}
+	}
+}
+
+// broadcastHit shows a projectile impact at (x, y). Assumes s.Mutex is LOCKED.
+func (s *GameServer) broadcastHit(pid, target ecs.Entity, level int, x, y float64) {
+	c := color.RGBA{255, 255, 255, 255}
+	if sprite, ok := ecs.GetComponent[components.SpriteComponent](s.World, pid); ok {
+		c = sprite.Color
+	}
+	s.broadcastEvent(level, protocol.EntityEventPacket{Kind: protocol.EventHit, EntityID: target, X: x, Y: y, Color: c})
+}
+
+// broadcastDeath shows an entity dying where it stands. Call before despawning it.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) broadcastDeath(id ecs.Entity) {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+	if !ok {
+		return
+	}
+	c := color.RGBA{200, 200, 200, 255}
+	if sprite, ok := ecs.GetComponent[components.SpriteComponent](s.World, id); ok {
+		c = sprite.Color
+	}
+	half := float64(config.TileSize) / 2
+	s.broadcastEvent(trans.Z, protocol.EntityEventPacket{Kind: protocol.EventDeath, EntityID: id, X: trans.X + half, Y: trans.Y + half, Color: c})
+}
+
+// broadcastLevelUp shows a player's level-up to everyone around. Assumes s.Mutex is LOCKED.
+func (s *GameServer) broadcastLevelUp(id ecs.Entity) {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+	if !ok {
+		return
+	}
+	half := float64(config.TileSize) / 2
+	s.broadcastEvent(trans.Z, protocol.EntityEventPacket{Kind: protocol.EventLevelUp, EntityID: id, X: trans.X + half, Y: trans.Y + half, Color: color.RGBA{255, 215, 80, 255}})
+}
+
+// publishEventCleared tells outside listeners when a world boss or rare monster falls.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) publishEventCleared(def systems.WorldEventDef, participants int) {
+	var title string
+	switch def.Type {
+	case "world_boss":
+		title = "World boss defeated"
+	case "rare_spawn":
+		title = "Rare monster slain"
+	default:
+		return
+	}
+	message := def.EndAnnouncement
+	if message == "" {
+		message = def.ID + " was defeated"
+	}
+	s.Events.Publish(systems.EventBossKilled, title, fmt.Sprintf("%s (%d player(s) took part)", message, participants))
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"fmt"
+	"log"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/storage"
+)
+
+// handleFarm plants a seed on or harvests a farmland tile
+func (s *GameServer) handleFarm(player *Player, req protocol.FarmPacket) {
+	var err error
+	s.withLock(func() {
+		switch req.Action {
+		case "plant":
+			err = s.FarmSystem.Plant(player.EntityID, player.Username, req.SeedID, req.TileX, req.TileY)
+		case "harvest":
+			produce, qty, harvestErr := s.FarmSystem.Harvest(player.EntityID, player.Username, req.TileX, req.TileY)
+			if err = harvestErr; err == nil {
+				s.Notify(player, fmt.Sprintf("Harvested %d %s", qty, produce.Name))
+			}
+		default:
+			err = fmt.Errorf("unknown farm action %q", req.Action)
+		}
+		if err == nil {
+			// Saved right away so a crash can't hand out the same harvest twice
+			s.saveCrops()
+		}
+	})
+	if err != nil {
+		log.Printf("Player %s farm %s failed: %v", player.Username, req.Action, err)
+		s.Notify(player, "Farming: "+err.Error())
+		return
+	}
+	s.withLock(func() {
+		s.PersistenceSystem.SavePlayer(player.EntityID, player.Username)
+		s.SendInventorySync(player)
+	})
+}
+
+// broadcastObject sends an object layer change to everyone on that level.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) broadcastObject(level, tx, ty, object int) {
+	packet := protocol.Packet{
+		Type: protocol.PacketObjectUpdate,
+		Data: protocol.ObjectUpdatePacket{Level: level, TileX: tx, TileY: ty, Object: object},
+	}
+	for id, player := range s.Players {
+		if trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id); ok && trans.Z == level {
+			player.Send(packet)
+		}
+	}
+}
+
+// saveCrops writes the farm plots to disk. Assumes s.Mutex is LOCKED.
+func (s *GameServer) saveCrops() {
+	if err := storage.SaveCrops(s.FarmSystem.Save()); err != nil {
+		log.Printf("Failed to save crops: %v", err)
+	}
+}
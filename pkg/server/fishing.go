@@ -0,0 +1,80 @@
+package server
+
+import (
+	"fmt"
+	"log"
+
+	"henry/pkg/items"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+func (s *GameServer) handleFish(player *Player, req protocol.FishPacket) {
+	s.withLock(func() {
+		s.fishAction(player, req.Action)
+	})
+}
+
+// useFishingRod casts, or hooks when already fishing (hotbar rod). Assumes s.Mutex is LOCKED.
+func (s *GameServer) useFishingRod(player *Player) {
+	if state, _ := s.FishingSystem.State(player.EntityID); state == "" {
+		s.fishAction(player, "cast")
+	} else {
+		s.fishAction(player, "hook")
+	}
+}
+
+// fishAction runs one step of the fishing minigame. Assumes s.Mutex is LOCKED.
+func (s *GameServer) fishAction(player *Player, action string) {
+	var err error
+	id := player.EntityID
+	switch action {
+	case "cast":
+		if err = s.FishingSystem.Cast(id); err == nil {
+			s.TelemetrySystem.RecordItem(systems.FishingRod)
+		}
+	case "hook":
+		s.MacroDetector.React(player.Username)
+		var catch systems.FishCatch
+		var leveledUp bool
+		if catch, leveledUp, err = s.FishingSystem.Hook(id); err != nil {
+			break
+		}
+		def, _ := items.Get(catch.ItemID)
+		s.Notify(player, fmt.Sprintf("You caught %s! (+%d fishing XP)", def.Name, catch.XP))
+		if leveledUp {
+			skills, _ := ecs.GetComponent[components.SkillsComponent](s.World, id)
+			s.Notify(player, fmt.Sprintf("Fishing level %d!", systems.FishingLevel(skills.XP[systems.SkillFishing])))
+		}
+		s.PersistenceSystem.SavePlayer(id, player.Username)
+		s.SendInventorySync(player)
+	case "stop":
+		s.FishingSystem.Stop(id)
+	default:
+		err = fmt.Errorf("unknown fishing action %q", action)
+	}
+	if err != nil {
+		log.Printf("Player %s fish %s failed: %v", player.Username, action, err)
+		s.Notify(player, "Fishing: "+err.Error())
+	}
+}
+
+// sendFishState tells a player about their fishing session and skill. Assumes s.Mutex is LOCKED.
+func (s *GameServer) sendFishState(id ecs.Entity) {
+	player, ok := s.Players[id]
+	if !ok {
+		return
+	}
+	state, window := s.FishingSystem.State(id)
+	if state == "bite" {
+		s.MacroDetector.Prompt(player.Username)
+	}
+	data := protocol.FishStatePacket{State: state, Window: window, Level: 1}
+	if skills, ok := ecs.GetComponent[components.SkillsComponent](s.World, id); ok {
+		data.XP = skills.XP[systems.SkillFishing]
+		data.Level = systems.FishingLevel(data.XP)
+	}
+	player.Send(protocol.Packet{Type: protocol.PacketFishState, Data: data})
+}
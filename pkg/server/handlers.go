@@ -0,0 +1,259 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"henry/pkg/items"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/storage"
+)
+
+// Clients sending this many malformed/unknown packets are disconnected
+const maxMalformedPackets = 20
+
+// packetHandler handles one in-game packet (after login) for a player
+type packetHandler func(s *GameServer, player *Player, data any) error
+
+// typed wraps a handler for a concrete packet type, rejecting payloads of any other type
+// instead of panicking on an unchecked assertion
+func typed[T any](fn func(s *GameServer, player *Player, req T)) packetHandler {
+	return func(s *GameServer, player *Player, data any) error {
+		req, ok := data.(T)
+		if !ok {
+			var want T
+			return fmt.Errorf("expected %T, got %T", want, data)
+		}
+		fn(s, player, req)
+		return nil
+	}
+}
+
+// packetHandlers is the dispatch table for the in-game loop (TCP and WebSocket connections alike)
+var packetHandlers = map[protocol.PacketType]packetHandler{
+	protocol.PacketInput:               typed((*GameServer).handleInput),
+	protocol.PacketUpdateKeybindings:   typed((*GameServer).handleUpdateKeybindings),
+	protocol.PacketUpdateDebugSettings: typed((*GameServer).handleUpdateSettings),
+	protocol.PacketInventoryAction: typed(func(s *GameServer, player *Player, req protocol.InventoryActionPacket) {
+		s.HandleInventoryAction(player.EntityID, req, player)
+	}),
+	protocol.PacketHotbarAction: typed(func(s *GameServer, player *Player, req protocol.HotbarActionPacket) {
+		s.HandleHotbarAction(player.EntityID, req, player)
+	}),
+	protocol.PacketEquipmentAction: typed(func(s *GameServer, player *Player, req protocol.EquipmentActionPacket) {
+		s.HandleEquipmentAction(player.EntityID, req, player)
+	}),
+	protocol.PacketCastSpell:       typed((*GameServer).handleCastSpell),
+	protocol.PacketUpdateUIState:   typed((*GameServer).handleUpdateUIState),
+	protocol.PacketMoveTo:          typed((*GameServer).handleMoveTo),
+	protocol.PacketPickup:          typed((*GameServer).handlePickup),
+	protocol.PacketFollow:          typed((*GameServer).handleFollow),
+	protocol.PacketTravel:          typed((*GameServer).handleTravel),
+	protocol.PacketBugReport:       typed((*GameServer).HandleBugReport),
+	protocol.PacketLootChoice:      typed((*GameServer).handleLootChoice),
+	protocol.PacketDuel:            typed((*GameServer).handleDuel),
+	protocol.PacketArena:           typed((*GameServer).handleArena),
+	protocol.PacketSpectate:        typed((*GameServer).handleSpectate),
+	protocol.PacketBuild:           typed((*GameServer).handleBuild),
+	protocol.PacketFarm:            typed((*GameServer).handleFarm),
+	protocol.PacketFish:            typed((*GameServer).handleFish),
+	protocol.PacketMail:            typed((*GameServer).handleMail),
+	protocol.PacketHouse:           typed((*GameServer).handleHouse),
+	protocol.PacketCharacter:       typed((*GameServer).handleCharacter),
+	protocol.PacketChatMessage:     typed((*GameServer).handleChat),
+	protocol.PacketCutsceneSkip:    typed((*GameServer).handleCutsceneSkip),
+	protocol.PacketInteract:        typed((*GameServer).handleInteract),
+	protocol.PacketDialogueChoice:  typed((*GameServer).handleDialogueChoice),
+	protocol.PacketShopTransaction: typed((*GameServer).handleShopTransaction),
+	protocol.PacketWardrobeAction:  typed((*GameServer).handleWardrobeAction),
+	protocol.PacketPing:            typed((*GameServer).handlePing),
+}
+
+// spectatorPackets are the only in-game packets accepted from spectators, so watching
+// can't touch the world (no moving items, casting, picking up or travelling)
+var spectatorPackets = map[protocol.PacketType]bool{
+	protocol.PacketInput:               true,
+	protocol.PacketUpdateKeybindings:   true,
+	protocol.PacketUpdateDebugSettings: true,
+	protocol.PacketUpdateUIState:       true,
+	protocol.PacketBugReport:           true,
+	protocol.PacketSpectate:            true,
+	protocol.PacketCharacter:           true,
+	protocol.PacketChatMessage:         true,
+	protocol.PacketPing:                true,
+}
+
+// cutscenePackets are the only in-game packets accepted during a cutscene. Input is
+// dropped by ProcessInput; everything else would act behind the player's back.
+var cutscenePackets = map[protocol.PacketType]bool{
+	protocol.PacketInput:               true,
+	protocol.PacketUpdateKeybindings:   true,
+	protocol.PacketUpdateDebugSettings: true,
+	protocol.PacketUpdateUIState:       true,
+	protocol.PacketBugReport:           true,
+	protocol.PacketChatMessage:         true,
+	protocol.PacketCutsceneSkip:        true,
+	protocol.PacketPing:                true,
+}
+
+// dispatch routes a packet to its handler. Unknown types and mismatched payloads are errors.
+func (s *GameServer) dispatch(player *Player, packet protocol.Packet) error {
+	handler, ok := packetHandlers[packet.Type]
+	if !ok {
+		return fmt.Errorf("no handler for packet type %d", packet.Type)
+	}
+	if !spectatorPackets[packet.Type] {
+		s.Mutex.RLock()
+		spectating := systems.IsSpectating(s.World, player.EntityID)
+		s.Mutex.RUnlock()
+		if spectating {
+			return nil // Ignored, not malformed
+		}
+	}
+	if !cutscenePackets[packet.Type] {
+		s.Mutex.RLock()
+		watching := s.CutsceneSystem.Playing(player.EntityID)
+		s.Mutex.RUnlock()
+		if watching {
+			return nil
+		}
+	}
+	return handler(s, player, packet.Data)
+}
+
+func (s *GameServer) handleInput(player *Player, req protocol.InputPacket) {
+	s.ProcessInput(player.EntityID, req.Input)
+}
+
+// handlePing answers a client's round trip measurement right away
+func (s *GameServer) handlePing(player *Player, req protocol.PingPacket) {
+	player.Send(protocol.Packet{Type: protocol.PacketPong, Data: protocol.PongPacket{Sent: req.Sent}})
+}
+
+func (s *GameServer) handleUpdateKeybindings(player *Player, req protocol.UpdateKeybindingsPacket) {
+	s.withLock(func() {
+		currData, err := storage.LoadPlayer(player.Username)
+		if err == nil && currData != nil {
+			currData.Keybindings = req.Keybindings
+			// Update component as well
+			ecs.AddComponent(s.World, player.EntityID, components.KeybindingsComponent{Bindings: req.Keybindings})
+			storage.SavePlayer(*currData)
+			log.Printf("Updated keybindings for %s", player.Username)
+		}
+	})
+}
+
+// handleUpdateSettings saves the client's toggles (debug overlays, chat bubbles, loot
+// filter), which are handed back at the next login
+func (s *GameServer) handleUpdateSettings(player *Player, req protocol.UpdateDebugSettingsPacket) {
+	s.withLock(func() {
+		player.LootFilter = items.LootFilterFrom(req.Settings)
+		currData, err := storage.LoadPlayer(player.Username)
+		if err == nil && currData != nil {
+			currData.DebugSettings = req.Settings
+			storage.SavePlayer(*currData)
+		}
+	})
+}
+
+func (s *GameServer) handleCastSpell(player *Player, req protocol.CastSpellPacket) {
+	s.withLock(func() {
+		// Directional spells aim at the last known cursor position
+		var mx, my float64
+		if input, ok := ecs.GetComponent[components.InputComponent](s.World, player.EntityID); ok {
+			mx, my = input.MouseX, input.MouseY
+		}
+		s.handleSpellCast(player.EntityID, req.SpellID, mx, my)
+	})
+}
+
+func (s *GameServer) handleUpdateUIState(player *Player, req protocol.UpdateUIStatePacket) {
+	s.withLock(func() {
+		uiState, _ := ecs.GetComponent[components.UIStateComponent](s.World, player.EntityID)
+		if uiState == nil {
+			uiState = &components.UIStateComponent{OpenMenus: make(map[string]bool)}
+		}
+		uiState.OpenMenus = req.OpenMenus
+		ecs.AddComponent(s.World, player.EntityID, *uiState)
+
+		if err := s.PersistenceSystem.SavePlayer(player.EntityID, player.Username); err != nil {
+			log.Printf("Error saving UI state: %v", err)
+		}
+	})
+}
+
+func (s *GameServer) handleMoveTo(player *Player, req protocol.MoveToPacket) {
+	// Each request is a path search, so a flood of clicks would stall the game loop
+	now := time.Now()
+	if now.Sub(player.LastMoveTo).Seconds() < config.MoveToCooldown {
+		return
+	}
+	player.LastMoveTo = now
+
+	s.withLock(func() {
+		if !s.AutoMoveSystem.MoveTo(player.EntityID, req.X, req.Y) {
+			log.Printf("Player %s click-to-move: no path to %.1f, %.1f", player.Username, req.X, req.Y)
+		}
+	})
+}
+
+func (s *GameServer) handlePickup(player *Player, req protocol.PickupPacket) {
+	var err error
+	s.withLock(func() {
+		if req.Auto {
+			err = s.GroundItemSystem.AutoPickup(player.EntityID, req.EntityID, player.LootFilter)
+		} else {
+			err = s.GroundItemSystem.Pickup(player.EntityID, req.EntityID)
+		}
+	})
+	// The filter retries while the item is in range, no need to log each of its misses.
+	// A successful pickup is saved and synced by syncPickup.
+	if err != nil && !req.Auto {
+		log.Printf("Player %s failed to pick up Entity %d: %v", player.Username, req.EntityID, err)
+	}
+}
+
+func (s *GameServer) handleFollow(player *Player, req protocol.FollowPacket) {
+	s.withLock(func() {
+		if req.TargetID == 0 {
+			s.AutoMoveSystem.Stop(player.EntityID)
+		} else if s.AutoMoveSystem.Follow(player.EntityID, req.TargetID) {
+			log.Printf("Player %s is following Entity %d", player.Username, req.TargetID)
+		}
+	})
+}
+
+func (s *GameServer) handleTravel(player *Player, req protocol.TravelPacket) {
+	var err error
+	s.withLock(func() {
+		err = s.WaypointSystem.Travel(player.EntityID, req.WaypointID)
+		if err == nil {
+			s.AutoMoveSystem.Stop(player.EntityID)
+		}
+	})
+	if err != nil {
+		log.Printf("Player %s failed to travel to %s: %v", player.Username, req.WaypointID, err)
+		s.Notify(player, "Cannot travel: "+err.Error())
+		return
+	}
+	log.Printf("Player %s travelled to %s", player.Username, req.WaypointID)
+	s.withLock(func() {
+		s.PersistenceSystem.SavePlayer(player.EntityID, player.Username)
+		s.SendInventorySync(player)
+	})
+}
+
+func (s *GameServer) handleLootChoice(player *Player, req protocol.LootChoicePacket) {
+	var err error
+	s.withLock(func() {
+		err = s.LootSystem.Choose(player.EntityID, req.RollID, req.Choice)
+	})
+	if err != nil {
+		log.Printf("Player %s loot roll %d (%s) rejected: %v", player.Username, req.RollID, req.Choice, err)
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/shared/world"
+)
+
+func TestMoveToUnreachableTarget(t *testing.T) {
+	s := newLaneServer(t)
+	alice := connectClient(t, s, "alice")
+
+	// A ring of trees around (20, 20)
+	m := s.Maps[0]
+	for y := 18; y <= 22; y++ {
+		for x := 18; x <= 22; x++ {
+			m.Tiles[y][x].Type = world.TileGrass
+			if x == 18 || x == 22 || y == 18 || y == 22 {
+				m.Tiles[y][x].Type = world.TileTree
+			}
+			m.Objects[y][x] = 0
+		}
+	}
+	var player *Player
+	s.withLock(func() {
+		for _, p := range s.Players {
+			player = p
+		}
+	})
+
+	tile := float64(config.TileSize)
+	moveTo := func() {
+		alice.send(t, protocol.PacketMoveTo, protocol.MoveToPacket{X: 20 * tile, Y: 20 * tile})
+		waitFor(t, "the move command", func() bool { return len(s.commands) == 1 })
+		s.runCommands()
+	}
+
+	moveTo()
+	if ecs.HasComponent[components.AutoMoveComponent](s.World, player.EntityID) {
+		t.Error("started walking to a target walled off from the player")
+	}
+	first := player.LastMoveTo
+	if first.IsZero() {
+		t.Fatal("the request wasn't handled")
+	}
+
+	// Straight away again: dropped without a search
+	moveTo()
+	if !player.LastMoveTo.Equal(first) {
+		t.Error("a second request within MoveToCooldown was searched")
+	}
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/storage"
+)
+
+// handleHouse buys deeds, walks players in and out of houses and edits guest lists
+func (s *GameServer) handleHouse(player *Player, req protocol.HousePacket) {
+	var err error
+	s.withLock(func() {
+		switch req.Action {
+		case "buy":
+			if err = s.HousingSystem.BuyDeed(player.EntityID, player.Username); err == nil {
+				s.Notify(player, "You bought a house deed. Show it at the house door!")
+				s.PersistenceSystem.SavePlayer(player.EntityID, player.Username)
+				s.SendInventorySync(player)
+			}
+		case "enter":
+			err = s.enterHouse(player, player.Username, s.currentPosition(player.EntityID))
+		case "visit":
+			if !s.HousingSystem.NearSteward(player.EntityID) {
+				err = errors.New("the Housing Steward shows guests in")
+				return
+			}
+			err = s.enterHouse(player, req.Name, s.currentPosition(player.EntityID))
+		case "leave":
+			s.HousingSystem.Leave(player.EntityID)
+		case "invite":
+			var guest string
+			if guest, err = s.accountName(req.Name); err == nil {
+				if err = s.HousingSystem.Invite(player.Username, guest); err == nil {
+					s.Notify(player, guest+" may now visit your house")
+					if p := s.playerByName(guest); p != nil {
+						s.Notify(p, player.Username+" invited you to their house. Ask the Housing Steward to visit.")
+					}
+				}
+			}
+		case "uninvite":
+			if err = s.HousingSystem.Uninvite(player.Username, req.Name); err == nil {
+				s.Notify(player, req.Name+" may no longer visit your house")
+				if p := s.playerByName(req.Name); p != nil {
+					s.sendHouseState(p)
+				}
+			}
+		default:
+			err = fmt.Errorf("unknown house action %q", req.Action)
+		}
+	})
+	if err != nil {
+		log.Printf("Player %s house %s failed: %v", player.Username, req.Action, err)
+		s.Notify(player, "House: "+err.Error())
+	}
+}
+
+// enterHouse takes a player into owner's house unless they're busy elsewhere.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) enterHouse(player *Player, owner string, returnTo systems.ReturnPosition) error {
+	id := player.EntityID
+	if s.DuelSystem.DuelOf(id) != nil || s.ArenaSystem.MatchOf(id) != nil || s.ArenaSystem.IsQueued(id) {
+		return errors.New("finish your duel or arena match first")
+	}
+	if systems.IsSpectating(s.World, id) {
+		return errors.New("stop spectating first")
+	}
+	hadHouse := s.HousingSystem.HouseOf(player.Username) != nil
+	if err := s.HousingSystem.Enter(id, player.Username, owner, returnTo); err != nil {
+		return err
+	}
+	if !hadHouse && owner == player.Username {
+		s.Notify(player, "Welcome home! Place furniture from your inventory, and step on the doormat to leave.")
+		s.PersistenceSystem.SavePlayer(id, player.Username)
+		s.SendInventorySync(player)
+	}
+	return nil
+}
+
+// enterHouseDoor runs the door trigger: owners walk into their house and come back out
+// at the trigger's target. Assumes s.Mutex is LOCKED.
+func (s *GameServer) enterHouseDoor(player *Player, door *components.TriggerComponent) {
+	returnTo := systems.ReturnPosition{X: door.TargetX, Y: door.TargetY, Z: door.TargetZ}
+	if err := s.enterHouse(player, player.Username, returnTo); err != nil {
+		s.Notify(player, "House: "+err.Error())
+	}
+}
+
+// currentPosition is where an entity stands now. Assumes s.Mutex is LOCKED.
+func (s *GameServer) currentPosition(id ecs.Entity) systems.ReturnPosition {
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+	if trans == nil {
+		return systems.ReturnPosition{}
+	}
+	return systems.ReturnPosition{X: trans.X, Y: trans.Y, Z: trans.Z}
+}
+
+// accountName resolves a typed name to an existing account's username (online players
+// match regardless of case). Assumes s.Mutex is LOCKED.
+func (s *GameServer) accountName(name string) (string, error) {
+	if p := s.playerByName(name); p != nil {
+		return p.Username, nil
+	}
+	if saved, err := storage.LoadPlayer(name); err == nil && saved != nil {
+		return saved.Username, nil
+	}
+	return "", fmt.Errorf("there is no player called %s", name)
+}
+
+// houseChanged saves the houses and refreshes the owner's and guests' house state.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) houseChanged(owner string) {
+	if err := storage.SaveHouses(s.HousingSystem.Save()); err != nil {
+		log.Printf("Failed to save houses: %v", err)
+	}
+	house := s.HousingSystem.HouseOf(owner)
+	for _, player := range s.Players {
+		if player.Username == owner || (house != nil && house.MayEnter(player.Username)) {
+			s.sendHouseState(player)
+		}
+	}
+}
+
+// houseVisited refreshes the house state of a player who went in or out of a house.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) houseVisited(id ecs.Entity) {
+	if player, ok := s.Players[id]; ok {
+		s.sendHouseState(player)
+	}
+}
+
+// sendHouseState tells a player about their house and invitations. Assumes s.Mutex is LOCKED.
+func (s *GameServer) sendHouseState(player *Player) {
+	data := protocol.HouseStatePacket{InvitedTo: s.HousingSystem.InvitedTo(player.Username)}
+	if house := s.HousingSystem.HouseOf(player.Username); house != nil {
+		data.Owned = true
+		data.Guests = house.Guests
+	}
+	if house := s.HousingSystem.Inside(player.EntityID); house != nil {
+		data.Inside = house.Owner
+	}
+	player.Send(protocol.Packet{Type: protocol.PacketHouseState, Data: data})
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"log"
+	"runtime/debug"
+	"time"
+
+	"henry/pkg/shared/config"
+	protocol "henry/pkg/shared/network"
+)
+
+// Goroutines ("lanes") and what each may touch:
+//
+//   - The game loop owns the simulation. It runs the systems and every in-game packet
+//     handler (see runCommands), so handlers never race each other or a tick.
+//   - One reader per connection decodes packets and queues them as commands. After
+//     login it touches no server state.
+//   - One writer per player owns its encoder. Everything else sends with Player.Send,
+//     which never blocks.
+//   - Logins, disconnects, the console and the schedulers change state from their own
+//     goroutines, always with s.Mutex held. The game loop takes it for each tick and
+//     each handler, so those never interleave with the simulation either.
+
+// command is a packet received from a player, waiting for the game loop
+type command struct {
+	player *Player
+	packet protocol.Packet
+}
+
+// startWriter starts the goroutine that owns p.Encoder from now on. Call once, before
+// the player is shared.
+func (p *Player) startWriter() {
+	p.out = make(chan protocol.Packet, config.PlayerSendQueue)
+	p.closed = make(chan struct{})
+	go p.writeLoop()
+}
+
+// Send queues a packet for the player's writer. A player too far behind to keep up is
+// disconnected rather than let the queue grow; sends after Close are dropped.
+func (p *Player) Send(packet protocol.Packet) {
+	select {
+	case <-p.closed:
+		return
+	default:
+	}
+	select {
+	case p.out <- packet:
+	default:
+		log.Printf("Disconnecting %s: %d packets behind", p.Username, cap(p.out))
+		p.Close()
+	}
+}
+
+// Close stops the writer once what's queued has gone out (or DisconnectFlushTimeout
+// passed) and closes the connection. The reader then fails, which removes the player.
+func (p *Player) Close() {
+	p.closeOnce.Do(func() { close(p.closed) })
+}
+
+// Closing reports whether Close was called
+func (p *Player) Closing() bool {
+	select {
+	case <-p.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *Player) writeLoop() {
+	defer p.Conn.Close()
+	for {
+		select {
+		case packet := <-p.out:
+			if !p.write(packet) {
+				p.Close()
+				return
+			}
+		case <-p.closed:
+			p.Conn.SetWriteDeadline(time.Now().Add(time.Duration(config.DisconnectFlushTimeout * float64(time.Second))))
+			for {
+				select {
+				case packet := <-p.out:
+					if !p.write(packet) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *Player) write(packet protocol.Packet) bool {
+	if err := p.Encoder.Encode(packet); err != nil {
+		log.Printf("Failed to send packet %d to %s: %v", packet.Type, p.Username, err)
+		return false
+	}
+	return true
+}
+
+// queueCommand hands a received packet to the game loop, waiting while the queue is
+// full. Returns false if the player is disconnected meanwhile.
+func (s *GameServer) queueCommand(player *Player, packet protocol.Packet) bool {
+	select {
+	case s.commands <- command{player: player, packet: packet}:
+		return true
+	case <-player.closed:
+		return false
+	}
+}
+
+// runCommands handles the packets received since the last tick, in order. Called by
+// the game loop without s.Mutex held (the handlers take it).
+func (s *GameServer) runCommands() {
+	for n := len(s.commands); n > 0; n-- {
+		cmd := <-s.commands
+		if cmd.player.Closing() {
+			continue // Disconnected: the rest of its packets don't matter
+		}
+		if err := s.runCommand(cmd); err != nil {
+			cmd.player.malformed++
+			log.Printf("Player %s sent a bad packet (%d): %v", cmd.player.Username, cmd.packet.Type, err)
+			if cmd.player.malformed >= maxMalformedPackets {
+				log.Printf("Disconnecting %s after %d bad packets", cmd.player.Username, cmd.player.malformed)
+				cmd.player.Close()
+			}
+		}
+	}
+}
+
+// runCommand dispatches one packet. A handler that panics disconnects only its player.
+func (s *GameServer) runCommand(cmd command) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("PANIC in packet handler (user %q, packet %d): %v\n%s", cmd.player.Username, cmd.packet.Type, r, debug.Stack())
+			cmd.player.Close()
+			err = nil
+		}
+	}()
+	return s.dispatch(cmd.player, cmd.packet)
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"encoding/gob"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"henry/pkg/shared/components"
+	protocol "henry/pkg/shared/network"
+)
+
+// These exercise the handler paths across the reader, game loop and writer goroutines;
+// run them with -race.
+
+func newLaneServer(t *testing.T) *GameServer {
+	t.Chdir(t.TempDir()) // Accounts and saves go to data/
+	protocol.RegisterGobTypes()
+	s := NewSimServer(SimConfig{MapSize: 32, Seed: 1})
+	s.conns = newConnLimiter(0)
+	return s
+}
+
+// laneClient is a logged-in client on the other end of a pipe
+type laneClient struct {
+	conn    net.Conn
+	enc     *gob.Encoder
+	packets chan protocol.Packet // Closed when the server hangs up
+}
+
+func connectClient(t *testing.T, s *GameServer, username string) *laneClient {
+	t.Helper()
+	serverConn, clientConn := net.Pipe()
+	go s.HandleConnection(serverConn)
+	c := &laneClient{conn: clientConn, enc: gob.NewEncoder(clientConn), packets: make(chan protocol.Packet, 4096)}
+	// Registered after newLaneServer's Chdir, so it runs first: the disconnected player's
+	// save lands in the temp dir rather than the package directory
+	t.Cleanup(func() {
+		clientConn.Close()
+		waitFor(t, username+" to be removed", func() bool {
+			s.Mutex.RLock()
+			defer s.Mutex.RUnlock()
+			for _, player := range s.Players {
+				if player.Username == username {
+					return false
+				}
+			}
+			return true
+		})
+	})
+	go func() {
+		defer close(c.packets)
+		dec := gob.NewDecoder(clientConn)
+		for {
+			var packet protocol.Packet
+			if err := dec.Decode(&packet); err != nil {
+				return
+			}
+			c.packets <- packet
+		}
+	}()
+
+	c.send(t, protocol.PacketSignup, protocol.SignupPacket{Username: username, Password: "secret"})
+	if resp := c.expect(t, protocol.PacketSignupResponse).Data.(protocol.SignupResponsePacket); !resp.Success {
+		t.Fatalf("signup of %s: %s", username, resp.Error)
+	}
+	c.send(t, protocol.PacketLogin, protocol.LoginPacket{Username: username, Password: "secret", Protocol: protocol.ProtocolFingerprint()})
+	if resp := c.expect(t, protocol.PacketLoginResponse).Data.(protocol.LoginResponsePacket); !resp.Success {
+		t.Fatalf("login of %s: %s", username, resp.Error)
+	}
+	return c
+}
+
+func (c *laneClient) send(t *testing.T, packetType protocol.PacketType, data any) {
+	t.Helper()
+	if err := c.enc.Encode(protocol.Packet{Type: packetType, Data: data}); err != nil {
+		t.Fatalf("send %d: %v", packetType, err)
+	}
+}
+
+// expect skips packets until one of the type arrives
+func (c *laneClient) expect(t *testing.T, packetType protocol.PacketType) protocol.Packet {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case packet, ok := <-c.packets:
+			if !ok {
+				t.Fatalf("disconnected while waiting for packet %d", packetType)
+			}
+			if packet.Type == packetType {
+				return packet
+			}
+		case <-timeout:
+			t.Fatalf("no packet %d", packetType)
+		}
+	}
+}
+
+// waitFor polls cond until it holds
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPacketsAreHandledOnTheGameLoop(t *testing.T) {
+	s := newLaneServer(t)
+	alice := connectClient(t, s, "alice")
+	bob := connectClient(t, s, "bob")
+
+	alice.send(t, protocol.PacketChatMessage, protocol.ChatMessagePacket{Channel: protocol.ChatGlobal, Text: "hello"})
+	waitFor(t, "the chat command", func() bool { return len(s.commands) == 1 })
+
+	s.runCommands()
+	msg := bob.expect(t, protocol.PacketChatBroadcast).Data.(protocol.ChatBroadcastPacket)
+	if msg.From != "alice" || msg.Text != "hello" {
+		t.Errorf("bob got %+v", msg)
+	}
+}
+
+func TestBadPacketsDisconnectOnTheGameLoop(t *testing.T) {
+	s := newLaneServer(t)
+	mallory := connectClient(t, s, "mallory")
+
+	for i := 0; i < maxMalformedPackets; i++ {
+		mallory.send(t, protocol.PacketChatMessage, protocol.InputPacket{}) // Wrong payload
+	}
+	waitFor(t, "the bad packets", func() bool { return len(s.commands) == maxMalformedPackets })
+	s.runCommands()
+
+	for range mallory.packets {
+		// Drain until the server hangs up
+	}
+	waitFor(t, "the player to be removed", func() bool {
+		s.Mutex.RLock()
+		defer s.Mutex.RUnlock()
+		return len(s.Players) == 0
+	})
+}
+
+func TestLanesUnderLoad(t *testing.T) {
+	s := newLaneServer(t)
+	names := []string{"alice", "bob", "carol", "dave"}
+	clients := make([]*laneClient, len(names))
+	for i, name := range names {
+		clients[i] = connectClient(t, s, name)
+	}
+
+	stop := make(chan struct{})
+	var loops sync.WaitGroup
+	loops.Add(2)
+	go func() { // Game loop
+		defer loops.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			s.runCommands()
+			s.Update()
+			s.BroadcastState()
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	go func() { // Console
+		defer loops.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(2 * time.Millisecond):
+				s.withLock(func() { s.Announce("Console says hi") })
+			}
+		}
+	}()
+
+	var senders sync.WaitGroup
+	for i, c := range clients {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			for n := 0; n < 50; n++ {
+				c.enc.Encode(protocol.Packet{Type: protocol.PacketInput, Data: protocol.InputPacket{Input: components.InputComponent{Right: n%2 == 0, Down: i%2 == 0}}})
+				if n%10 == 0 {
+					c.enc.Encode(protocol.Packet{Type: protocol.PacketChatMessage, Data: protocol.ChatMessagePacket{Channel: protocol.ChatLocal, Text: "hi"}})
+				}
+			}
+		}()
+	}
+	senders.Wait()
+
+	for _, c := range clients {
+		c.expect(t, protocol.PacketStateUpdate)
+		c.expect(t, protocol.PacketAnnouncement)
+		c.conn.Close()
+	}
+	waitFor(t, "everyone to be removed", func() bool {
+		s.Mutex.RLock()
+		defer s.Mutex.RUnlock()
+		return len(s.Players) == 0
+	})
+	close(stop)
+	loops.Wait()
+}
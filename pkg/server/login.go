@@ -0,0 +1,86 @@
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"henry/pkg/auth"
+	"henry/pkg/shared/config"
+	"henry/pkg/storage"
+)
+
+// loginThrottles count failed logins per account and per address. They have their own
+// locks since logins are checked outside the game loop.
+type loginThrottles struct {
+	accounts  *auth.Throttle
+	addresses *auth.Throttle
+}
+
+func newLoginThrottles() loginThrottles {
+	window := time.Duration(config.LoginFailureWindow * float64(time.Second))
+	return loginThrottles{
+		accounts:  auth.NewThrottle(config.LoginFailuresPerAccount, window),
+		addresses: auth.NewThrottle(config.LoginFailuresPerAddress, window),
+	}
+}
+
+// checkLogin checks a login attempt from ip and returns the account's save, or nil and the
+// reason to give the client. Unknown accounts and wrong passwords get the same answer and
+// take as long, and both count toward throttling the account and the address.
+func (s *GameServer) checkLogin(ip, username, password string) (*storage.PlayerSaveData, string) {
+	now := time.Now()
+	account := strings.ToLower(username)
+	for _, limit := range []struct {
+		throttle *auth.Throttle
+		key      string
+	}{{s.logins.accounts, account}, {s.logins.addresses, ip}} {
+		if ok, wait := limit.throttle.Allow(limit.key, now); !ok {
+			log.Printf("Refusing login for %s from %s: too many failures", username, ip)
+			return nil, fmt.Sprintf("Too many failed logins, try again in %d seconds", int(math.Ceil(wait.Seconds())))
+		}
+	}
+
+	var saved *storage.PlayerSaveData
+	if auth.ValidUsername(username) {
+		var err error
+		if saved, err = storage.LoadPlayer(username); err != nil {
+			log.Printf("Failed to load %s: %v", username, err)
+		}
+	}
+	if !passwordMatches(saved, password) {
+		s.logins.accounts.Fail(account, now)
+		s.logins.addresses.Fail(ip, now)
+		log.Printf("Failed login for %q from %s", username, ip)
+		return nil, "Wrong username or password"
+	}
+	s.logins.accounts.Succeed(account)
+
+	if saved.Password != "" { // Saved by an older server: keep the hash instead
+		if err := storage.HashPassword(saved); err != nil {
+			log.Printf("Failed to hash password for %s: %v", username, err)
+		} else if err := storage.SavePlayer(*saved); err != nil {
+			log.Printf("Failed to save hashed password for %s: %v", username, err)
+		}
+	}
+	return saved, ""
+}
+
+func passwordMatches(saved *storage.PlayerSaveData, password string) bool {
+	switch {
+	case saved == nil:
+		auth.VerifyMissing(password)
+		return false
+	case saved.PasswordHash != "":
+		ok, err := auth.Verify(saved.PasswordHash, password)
+		if err != nil {
+			log.Printf("Password of %s: %v", saved.Username, err)
+		}
+		return ok
+	default: // Legacy plaintext
+		return saved.Password != "" && subtle.ConstantTimeCompare([]byte(saved.Password), []byte(password)) == 1
+	}
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"encoding/gob"
+	"log"
+	"time"
+
+	"henry/pkg/shared/config"
+	protocol "henry/pkg/shared/network"
+)
+
+// queuedLogin is a player waiting for a free slot on a full server
+type queuedLogin struct {
+	username string
+	admit    chan struct{} // Signalled (once) when a slot is reserved for this login
+}
+
+// waitForSlot blocks a login until the server has room, sending queue position updates
+// meanwhile. On success a slot is reserved: call releaseSlot once the player is spawned.
+// Admins skip the queue. Fails if the client can't be reached while waiting.
+func (s *GameServer) waitForSlot(encoder *gob.Encoder, username string, isAdmin bool) error {
+	entry := &queuedLogin{username: username, admit: make(chan struct{}, 1)}
+	var position int
+	s.withLock(func() {
+		if isAdmin || (len(s.loginQueue) == 0 && s.hasFreeSlot()) {
+			s.reservedSlots++
+			entry.admit <- struct{}{}
+			return
+		}
+		s.loginQueue = append(s.loginQueue, entry)
+		position = len(s.loginQueue)
+	})
+	if position == 0 {
+		return nil
+	}
+	log.Printf("Server full, %s queued at position %d", username, position)
+
+	ticker := time.NewTicker(time.Duration(config.LoginQueueUpdateInterval * float64(time.Second)))
+	defer ticker.Stop()
+	for {
+		var size int
+		s.withLock(func() { position, size = s.loginQueuePosition(entry) })
+		if position > 0 {
+			update := protocol.Packet{Type: protocol.PacketLoginQueue, Data: protocol.LoginQueuePacket{Position: position, Size: size}}
+			if err := encoder.Encode(update); err != nil {
+				s.withLock(func() { s.leaveLoginQueue(entry) })
+				return err
+			}
+		}
+
+		select {
+		case <-entry.admit:
+			log.Printf("%s admitted from the login queue", username)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// hasFreeSlot reports whether another player fits under MaxPlayers. Assumes s.Mutex is LOCKED.
+func (s *GameServer) hasFreeSlot() bool {
+	return s.MaxPlayers <= 0 || len(s.Players)+s.reservedSlots < s.MaxPlayers
+}
+
+// releaseSlot ends a reservation made by waitForSlot (the player is now in s.Players or
+// never made it in). Assumes s.Mutex is LOCKED.
+func (s *GameServer) releaseSlot() {
+	if s.reservedSlots > 0 {
+		s.reservedSlots--
+	}
+}
+
+// updateLoginQueue admits queued logins, oldest first, while slots are free.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) updateLoginQueue() {
+	for len(s.loginQueue) > 0 && s.hasFreeSlot() {
+		entry := s.loginQueue[0]
+		s.loginQueue = s.loginQueue[1:]
+		s.reservedSlots++
+		entry.admit <- struct{}{}
+	}
+}
+
+// loginQueuePosition returns the 1-based position of a queued login (0 once admitted) and
+// the queue length. Assumes s.Mutex is LOCKED.
+func (s *GameServer) loginQueuePosition(entry *queuedLogin) (int, int) {
+	for i, e := range s.loginQueue {
+		if e == entry {
+			return i + 1, len(s.loginQueue)
+		}
+	}
+	return 0, len(s.loginQueue)
+}
+
+// leaveLoginQueue drops a login that gave up waiting, freeing its slot if it was already
+// admitted. Assumes s.Mutex is LOCKED.
+func (s *GameServer) leaveLoginQueue(entry *queuedLogin) {
+	for i, e := range s.loginQueue {
+		if e == entry {
+			s.loginQueue = append(s.loginQueue[:i:i], s.loginQueue[i+1:]...)
+			return
+		}
+	}
+	s.releaseSlot()
+}
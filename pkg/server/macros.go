@@ -0,0 +1,52 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"henry/pkg/server/systems"
+	"henry/pkg/storage"
+)
+
+// flagMacro adds a suspicion to the admin report and lets the webhooks know. The account
+// is left alone; banning stays an admin's call. Assumes s.Mutex is LOCKED.
+func (s *GameServer) flagMacro(flag storage.MacroFlag) {
+	s.MacroFlags = append(s.MacroFlags, flag)
+	if err := storage.SaveMacroFlags(s.MacroFlags); err != nil {
+		log.Printf("Failed to save macro report: %v", err)
+	}
+	log.Printf("Flagged %s for review: %s (%s)", flag.Username, flag.Reason, flag.Detail)
+	s.Events.Publish(systems.EventMacroFlag, "Possible macro use",
+		fmt.Sprintf("%s flagged for review: %s", flag.Username, flag.Detail))
+}
+
+// macrosCommand is the "macros" console command. Assumes s.Mutex is LOCKED.
+func (s *GameServer) macrosCommand(args string) {
+	action, name, _ := strings.Cut(args, " ")
+	name = strings.TrimSpace(name)
+	switch {
+	case action == "":
+		if len(s.MacroFlags) == 0 {
+			log.Printf("No accounts flagged for macro use")
+		}
+		for _, flag := range s.MacroFlags {
+			log.Printf("%s %-16s %-18s %s", flag.FlaggedAt.Format("2006-01-02 15:04"), flag.Username, flag.Reason, flag.Detail)
+		}
+	case action == "clear" && name != "":
+		kept := s.MacroFlags[:0]
+		for _, flag := range s.MacroFlags {
+			if flag.Username != name {
+				kept = append(kept, flag)
+			}
+		}
+		cleared := len(s.MacroFlags) - len(kept)
+		s.MacroFlags = kept
+		if err := storage.SaveMacroFlags(s.MacroFlags); err != nil {
+			log.Printf("Failed to save macro report: %v", err)
+		}
+		log.Printf("Cleared %d flag(s) for %s", cleared, name)
+	default:
+		log.Printf("Usage: macros [clear <player>]")
+	}
+}
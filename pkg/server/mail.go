@@ -0,0 +1,79 @@
+package server
+
+import (
+	"fmt"
+	"log"
+
+	"henry/pkg/items"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/storage"
+)
+
+// handleMail sends the mailbox or takes a mail's attachments
+func (s *GameServer) handleMail(player *Player, req protocol.MailPacket) {
+	var err error
+	s.withLock(func() {
+		switch req.Action {
+		case "open":
+			s.sendMailbox(player)
+		case "take":
+			if err = s.MailSystem.Take(player.EntityID, player.Username, req.MailID); err != nil {
+				return
+			}
+			// Saved right away so a crash can't hand out the same attachments twice
+			s.saveMail()
+			s.sendMailbox(player)
+		default:
+			err = fmt.Errorf("unknown mail action %q", req.Action)
+		}
+	})
+	if err != nil {
+		log.Printf("Player %s mail %s failed: %v", player.Username, req.Action, err)
+		s.Notify(player, "Mail: "+err.Error())
+		return
+	}
+	if req.Action == "take" {
+		s.withLock(func() {
+			s.PersistenceSystem.SavePlayer(player.EntityID, player.Username)
+			s.SendInventorySync(player)
+		})
+	}
+}
+
+// sendMailbox lists a player's unclaimed mail. Assumes s.Mutex is LOCKED.
+func (s *GameServer) sendMailbox(player *Player) {
+	inbox := s.MailSystem.Inbox(player.Username)
+	data := protocol.MailboxPacket{Mail: make([]protocol.MailEntry, 0, len(inbox))}
+	for _, mail := range inbox {
+		entry := protocol.MailEntry{ID: mail.ID, From: mail.From, Subject: mail.Subject}
+		for _, item := range mail.Items {
+			name := item.ItemID
+			if def, ok := items.Get(item.ItemID); ok {
+				name = def.Name
+			}
+			entry.Items = append(entry.Items, fmt.Sprintf("%dx %s", item.Quantity, name))
+		}
+		data.Mail = append(data.Mail, entry)
+	}
+	player.Send(protocol.Packet{Type: protocol.PacketMailbox, Data: data})
+}
+
+// mailDelivered saves the mailboxes and tells the recipient if they're online.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) mailDelivered(to string) {
+	s.saveMail()
+	for _, player := range s.Players {
+		if player.Username == to {
+			s.Notify(player, "You have new mail")
+			s.sendMailbox(player)
+			return
+		}
+	}
+}
+
+// saveMail writes every mailbox to disk. Assumes s.Mutex is LOCKED.
+func (s *GameServer) saveMail() {
+	if err := storage.SaveMail(s.MailSystem.Store); err != nil {
+		log.Printf("Failed to save mail: %v", err)
+	}
+}
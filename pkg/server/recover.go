@@ -0,0 +1,53 @@
+package server
+
+import (
+	"log"
+	"runtime/debug"
+
+	"henry/pkg/shared/ecs"
+)
+
+// Stack traces are logged for the first panic of a system, then every Nth repeat
+const systemPanicStackEvery = 100
+
+// withLock runs fn with the server lock held, then delivers the ECS events it published.
+// The deferred unlock keeps the lock from leaking if fn panics and the panic is
+// recovered further up.
+func (s *GameServer) withLock(fn func()) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	fn()
+	s.World.DispatchEvents()
+}
+
+// runSystem calls a per-tick system update, then delivers the ECS events it published,
+// recovering (and logging) a panic so a single broken system doesn't take the whole
+// server down. Assumes s.Mutex is LOCKED.
+func (s *GameServer) runSystem(name string, update func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.systemPanics[name]++
+			count := s.systemPanics[name]
+			if count == 1 || count%systemPanicStackEvery == 0 {
+				log.Printf("PANIC in system %s (#%d): %v\n%s", name, count, r, debug.Stack())
+			} else {
+				log.Printf("PANIC in system %s (#%d): %v", name, count, r)
+			}
+		}
+	}()
+	update()
+	s.World.DispatchEvents()
+}
+
+// recoverConnection is deferred by HandleConnection. A panic while handling a client's
+// packets disconnects only that client.
+func (s *GameServer) recoverConnection(username *string, playerEntity *ecs.Entity) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	log.Printf("PANIC in connection handler (user %q, Entity %d): %v\n%s", *username, *playerEntity, r, debug.Stack())
+	if *playerEntity != 0 {
+		s.RemovePlayer(*playerEntity)
+	}
+}
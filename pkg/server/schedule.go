@@ -0,0 +1,89 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"henry/pkg/server/systems"
+	"henry/pkg/storage"
+)
+
+// ScheduleFile lists the recurring tasks of the live server (optional)
+const ScheduleFile = "data/schedule.json"
+
+// registerScheduledTasks makes the server's maintenance jobs available to data/schedule.json.
+// Tasks run from the game loop with s.Mutex LOCKED.
+func (s *GameServer) registerScheduledTasks() {
+	s.SchedulerSystem.Register("save", func(string) error {
+		s.saveAll()
+		return nil
+	})
+	s.SchedulerSystem.Register("backup", func(string) error {
+		s.saveAll()
+		dir, err := storage.Backup(time.Now())
+		if err == nil {
+			log.Printf("Backup written to %s", dir)
+		}
+		return err
+	})
+	s.SchedulerSystem.Register("leaderboard_snapshot", func(string) error {
+		s.recordPlaytime()
+		path, err := storage.SnapshotLeaderboard(s.Leaderboard, time.Now())
+		if err == nil {
+			log.Printf("Leaderboard snapshot written to %s", path)
+		}
+		return err
+	})
+	s.SchedulerSystem.Register("world_event", func(eventID string) error {
+		if eventID == "" {
+			return errors.New("world_event needs an event ID as args")
+		}
+		if s.WorldEventSystem.Roll(eventID) {
+			log.Printf("Scheduled roll started world event %s", eventID)
+		}
+		return nil
+	})
+	s.SchedulerSystem.Register("announce", func(msg string) error {
+		s.Announce(msg)
+		return nil
+	})
+}
+
+// loadSchedule reads data/schedule.json into the scheduler
+func (s *GameServer) loadSchedule() {
+	defs, err := systems.LoadSchedule(ScheduleFile)
+	if err != nil {
+		log.Printf("No scheduled tasks loaded: %v", err)
+		return
+	}
+	s.Mutex.Lock()
+	s.SchedulerSystem.Load(defs)
+	s.Mutex.Unlock()
+}
+
+// logStatus prints the population, login queue and schedule for the operator.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) logStatus() {
+	limit := "unlimited"
+	if s.MaxPlayers > 0 {
+		limit = fmt.Sprint(s.MaxPlayers)
+	}
+	log.Printf("Players online: %d (max %s), login queue: %d, AFK: %d", len(s.Players), limit, len(s.loginQueue), len(s.PlaytimeSystem.AFKPlayers()))
+
+	jobs := s.SchedulerSystem.Jobs()
+	if len(jobs) == 0 {
+		log.Printf("No scheduled tasks")
+	}
+	for _, job := range jobs {
+		last := "never"
+		if !job.LastRun.IsZero() {
+			last = job.LastRun.Format("2006-01-02 15:04:05")
+			if job.LastErr != nil {
+				last += " (failed: " + job.LastErr.Error() + ")"
+			}
+		}
+		log.Printf("  %-20s %-20s next %s, last %s, %d run(s)", job.Def.ID, job.Def.Task, job.NextRun.Format("2006-01-02 15:04:05"), last, job.Runs)
+	}
+}
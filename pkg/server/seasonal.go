@@ -0,0 +1,42 @@
+package server
+
+import (
+	"log"
+	"strings"
+)
+
+// seasonCommand is the "season" console command. Assumes s.Mutex is LOCKED.
+func (s *GameServer) seasonCommand(args string) {
+	action, id, _ := strings.Cut(args, " ")
+	id = strings.TrimSpace(id)
+	if action == "" {
+		if len(s.SeasonalSystem.Defs) == 0 {
+			log.Printf("No seasons defined (see data/events/seasonal.json)")
+		}
+		for _, def := range s.SeasonalSystem.Defs {
+			state := "off"
+			if s.SeasonalSystem.Active(def.ID) {
+				state = "on"
+			}
+			if _, forced := s.SeasonalSystem.Forced(def.ID); forced {
+				state += " (forced)"
+			}
+			log.Printf("%-20s %s to %s: %s", def.ID, def.Start, def.End, state)
+		}
+		return
+	}
+
+	ok := false
+	switch action {
+	case "on", "off":
+		ok = s.SeasonalSystem.Force(id, action == "on")
+	case "auto":
+		ok = s.SeasonalSystem.Unforce(id)
+	default:
+		log.Printf("Usage: season [on|off|auto <id>]")
+		return
+	}
+	if !ok {
+		log.Printf("Unknown season %q", id)
+	}
+}
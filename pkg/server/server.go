@@ -2,35 +2,55 @@ package server
 
 import (
 	"encoding/gob"
+	"errors"
+	"fmt"
 	"image/color"
 	"log"
 	"math"
 	"net"
 	"os"
 	"os/signal"
+	"sort"
 	"sync"
 	"syscall"
 	"time"
 
+	"henry/pkg/auth"
 	"henry/pkg/characters"
 	"henry/pkg/items"
 	"henry/pkg/network"
+	"henry/pkg/server/service"
 	"henry/pkg/server/systems"
 	"henry/pkg/shared/components"
 	"henry/pkg/shared/config"
 	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
 	protocol "henry/pkg/shared/network"
 	"henry/pkg/shared/world"
 	"henry/pkg/storage"
 )
 
+const maxBugReportLength = 1000 // Characters kept from a bug report description
+
 type Player struct {
 	Conn      net.Conn
-	Encoder   *gob.Encoder
+	Encoder   *gob.Encoder // Owned by the writer goroutine after login: use Send
 	Decoder   *gob.Decoder
 	EntityID  ecs.Entity
 	Username  string
+	IsAdmin   bool // GM: may spectate anyone and fly freely
 	PrevInput components.InputComponent
+	Kicked    bool // Connection is being closed (AFK kick), no longer counts as online
+
+	LastBugReport time.Time
+	LastMoveTo    time.Time        // Click-to-move requests are rate limited (see handleMoveTo)
+	LootFilter    items.LootFilter // From the saved settings, checked on automatic pickups
+
+	// Outgoing packets for the writer goroutine (see lanes.go)
+	out       chan protocol.Packet
+	closed    chan struct{}
+	closeOnce sync.Once
+	malformed int // Bad packets so far (game loop only)
 }
 
 type GameServer struct {
@@ -41,59 +61,446 @@ type GameServer struct {
 	NetworkSystem     *systems.NetworkSystem
 	PersistenceSystem *systems.PersistenceSystem
 	AISystem          *systems.AISystem
+	Clock             *world.Clock
+	AutoMoveSystem    *systems.AutoMoveSystem
+	GroundItemSystem  *systems.GroundItemSystem
+	WorldEventSystem  *systems.WorldEventSystem
+	SpawnRegionSystem *systems.SpawnRegionSystem
+	ItemAudit         *systems.ItemAudit // Item events for the "audit" console command
+	ZoneSystem        *systems.ZoneSystem
+	WaypointSystem    *systems.WaypointSystem
+	CombatSystem      *systems.CombatSystem
+	Events            *systems.EventBus // Notable events for outside listeners
+	WebhookSystem     *systems.WebhookSystem
+	ChatSystem        *systems.ChatSystem
+	StatusSystem      *systems.StatusEffectSystem
+	TriggerSystem     *systems.TriggerSystem
+	DummySystem       *systems.TrainingDummySystem
+	SpawnLimiter      *systems.SpawnLimiter
+	Service           *service.GameService
+	NPCStateSystem    *systems.NPCStateSystem
+	EconomySystem     *systems.EconomySystem
+	LootSystem        *systems.LootSystem
+	DeathSystem       *systems.DeathSystem
+	DuelSystem        *systems.DuelSystem
+	InstanceSystem    *systems.InstanceSystem
+	ArenaSystem       *systems.ArenaSystem
+	SpectatorSystem   *systems.SpectatorSystem
+	BuildingSystem    *systems.BuildingSystem
+	HousingSystem     *systems.HousingSystem
+	FarmSystem        *systems.FarmSystem
+	FishingSystem     *systems.FishingSystem
+	MailSystem        *systems.MailSystem
+	PlaytimeSystem    *systems.PlaytimeSystem
+	AggroSystem       *systems.AggroSystem
+	CutsceneSystem    *systems.CutsceneSystem
+	DialogueSystem    *systems.DialogueSystem
+	SeasonalSystem    *systems.SeasonalSystem
+	SchedulerSystem   *systems.SchedulerSystem
+	TelemetrySystem   *systems.TelemetrySystem
+	MacroDetector     *systems.MacroDetector
+	Diminishing       *systems.DiminishingReturns
+	Leaderboard       *storage.Leaderboard
 	Maps              map[int]*world.Map // Support multiple levels
-}
 
-func NewGameServer() *GameServer {
-	worldECS := ecs.NewWorld()
+	// PersistNPCs checkpoints spawner NPCs and resumes them on the next start (off = fresh spawns)
+	PersistNPCs bool
+	// Maintenance rejects non-admin logins
+	Maintenance bool
+	// MOTD is shown to players after login (empty = none)
+	MOTD string
+	// AFKKickAbove disconnects AFK players while more than this many are online (0 = never)
+	AFKKickAbove int
+	// MaxPlayers caps players online at once; further logins are queued (0 = unlimited)
+	MaxPlayers int
+	// MaxConnsPerIP caps simultaneous connections from one client address (0 = unlimited)
+	MaxConnsPerIP int
+	// TrustedProxies are reverse proxies in front of the WebSocket endpoint whose
+	// X-Forwarded-For header names the real client
+	TrustedProxies []*net.IPNet
+	// MacroFlags is the admin report of accounts the macro heuristics flagged (see macros.go)
+	MacroFlags []storage.MacroFlag
+	// HelpTopics are the help window's pages, sent to every player at login
+	HelpTopics []protocol.HelpTopic
+
+	conns    *connLimiter
+	logins   loginThrottles
+	commands chan command // Received packets for the game loop (see lanes.go)
+	mapDir   string       // Where the maps were loaded from (for "reload map")
+
+	// Logins waiting for a free slot, and slots promised to admitted logins not yet
+	// spawned (see loginqueue.go)
+	loginQueue    []*queuedLogin
+	reservedSlots int
+
+	autosaveTimer float64
+	systemPanics  map[string]int // Recovered panics per system (see recover.go)
+	auditFailing  bool           // The last item audit write failed (see audit.go)
+
+	// Scheduled shutdown (see admin.go)
+	shutdownScheduled bool
+	shutdownTimer     float64
+	shutdownReason    string
+	nextWarning       int
+}
 
+// NewGameServer loads the maps from mapDir (see config.MapDir). If there is no level 0
+// map a default one is generated, so the server can start without any data files.
+func NewGameServer(mapDir string) *GameServer {
 	// Load Maps
-	maps := make(map[int]*world.Map)
-	m0, err := world.LoadMap("data/maps/level_0.json")
+	maps, err := world.LoadMaps(mapDir)
+	if err != nil {
+		panic(err) // panic on startup if a map file is broken
+	}
+	if _, ok := maps[0]; !ok {
+		log.Printf("No level 0 map in %s, generating a default map", mapDir)
+		maps[0] = world.GenerateMap(config.DefaultMapSize, config.DefaultMapSize, config.DefaultMapSpawners, spawnableCharacterIDs(), config.DefaultMapSeed)
+	}
+	log.Printf("Loaded %d map(s)", len(maps))
+	checkMaps(maps)
+
+	// Spells (the built-ins are used for any spell without a file)
+	spells, err := service.LoadSpells(service.SpellDir)
 	if err != nil {
-		panic(err) // panic on startup if map missing
+		log.Printf("No spells loaded: %v", err)
+	} else {
+		components.RegisterSpells(spells)
+		log.Printf("Loaded %d spell(s)", len(spells))
+	}
+
+	// World Events (Optional data file)
+	eventDefs, err := systems.LoadWorldEvents("data/events/world_events.json")
+	if err != nil {
+		log.Printf("No world events loaded: %v", err)
+	}
+
+	gs := newGameServer(maps, eventDefs)
+	gs.mapDir = mapDir
+	gs.ItemAudit.Path = storage.ItemAuditFile
+
+	// Dialogues (optional data files)
+	if dialogues, err := systems.LoadDialogues(systems.DialogueDir); err != nil {
+		log.Printf("No dialogues loaded: %v", err)
+	} else {
+		gs.DialogueSystem = systems.NewDialogueSystem(gs.World, dialogues)
+		gs.wireDialogues()
+		log.Printf("Loaded %d dialogue(s)", len(dialogues))
 	}
-	maps[0] = m0
+
+	// Help pages (optional data files)
+	if topics, err := systems.LoadHelp(systems.HelpDir); err != nil {
+		log.Printf("No help loaded: %v", err)
+	} else {
+		gs.HelpTopics = topics
+		log.Printf("Loaded %d help topic(s)", len(topics))
+	}
+
+	// Seasonal events (optional data file)
+	if seasons, err := systems.LoadSeasons(systems.SeasonalFile); err == nil {
+		gs.SeasonalSystem.Defs = seasons
+		log.Printf("Loaded %d season(s)", len(seasons))
+	} else if !os.IsNotExist(err) {
+		log.Printf("No seasons loaded: %v", err)
+	}
+
+	// Webhooks (optional data file)
+	if hooks, err := systems.LoadWebhooks(systems.WebhookFile); err == nil {
+		gs.WebhookSystem = systems.NewWebhookSystem(hooks)
+		log.Printf("Loaded %d webhook(s)", len(hooks))
+	} else if !os.IsNotExist(err) {
+		log.Printf("No webhooks loaded: %v", err)
+	}
+
+	// Chat filter (optional data file, built-in word list without it)
+	if words, err := systems.LoadChatFilter(systems.ChatFilterFile); err == nil {
+		gs.ChatSystem = systems.NewChatSystem(gs.World, words)
+		log.Printf("Loaded %d chat filter word(s)", len(words))
+	} else if !os.IsNotExist(err) {
+		log.Printf("Using the built-in chat filter: %v", err)
+	}
+	gs.AISystem.PreparePaths()
+	return gs
+}
+
+// newGameServer wires the systems around already loaded maps and event definitions
+func newGameServer(maps map[int]*world.Map, eventDefs []systems.WorldEventDef) *GameServer {
+	worldECS := ecs.NewWorld()
 
 	// Initialize Server
 	gs := &GameServer{
 		World:   worldECS,
 		Players: make(map[ecs.Entity]*Player),
 		Maps:    maps,
+		Clock:   world.NewClock(config.StartHour, config.DayLengthSeconds),
+
+		commands:     make(chan command, config.CommandQueue),
+		systemPanics: make(map[string]int),
+		logins:       newLoginThrottles(),
 	}
 
+	gs.SpawnLimiter = systems.NewSpawnLimiter(worldECS)
+	gs.Events = systems.NewEventBus()
+	gs.WebhookSystem = systems.NewWebhookSystem(nil)
+	gs.Events.Subscribe(func(event systems.ServerEvent) { gs.WebhookSystem.Handle(event) })
+	gs.ChatSystem = systems.NewChatSystem(worldECS, systems.DefaultChatFilter)
+	gs.NPCStateSystem = systems.NewNPCStateSystem(worldECS)
 	gs.MovementSystem = systems.NewMovementSystem(worldECS, maps)
-	gs.NetworkSystem = systems.NewNetworkSystem(worldECS)
+	gs.MovementSystem.OnCorrect = gs.sendMoveCorrection
+	gs.NetworkSystem = systems.NewNetworkSystem(worldECS, gs.Clock)
 	gs.PersistenceSystem = systems.NewPersistenceSystem(worldECS)
-	gs.AISystem = systems.NewAISystem(worldECS, maps)
+	gs.AISystem = systems.NewAISystem(worldECS, maps, gs.Clock)
+	gs.AISystem.Grid = gs.MovementSystem.Grid
+	gs.AutoMoveSystem = systems.NewAutoMoveSystem(worldECS, gs.AISystem)
+	gs.ItemAudit = systems.NewItemAudit("")
+	gs.ItemAudit.Account = func(id ecs.Entity) string {
+		if player, ok := gs.Players[id]; ok {
+			return player.Username
+		}
+		return ""
+	}
+	gs.GroundItemSystem = systems.NewGroundItemSystem(worldECS)
+	gs.GroundItemSystem.Limiter = gs.SpawnLimiter
+	gs.GroundItemSystem.Audit = gs.ItemAudit
+	gs.EconomySystem = systems.NewEconomySystem("data/economy/economy.json")
+
+	gs.Service = service.NewGameService(worldECS)
+	gs.Service.GroundItems = gs.GroundItemSystem
+	gs.Service.Limiter = gs.SpawnLimiter
+	gs.Service.Economy = gs.EconomySystem
+	gs.Service.Audit = gs.ItemAudit
+	gs.CombatSystem = systems.NewCombatSystem(worldECS, maps)
+	gs.StatusSystem = systems.NewStatusEffectSystem(worldECS)
+	gs.StatusSystem.OnBurn = gs.burn
+
+	gs.WorldEventSystem = systems.NewWorldEventSystem(worldECS, maps, eventDefs)
+	gs.WorldEventSystem.Spawn = gs.SpawnCharacter
+	gs.WorldEventSystem.Announce = gs.Announce
+	gs.WorldEventSystem.AnnounceZone = gs.AnnounceZone
+	gs.WorldEventSystem.OnCleared = gs.publishEventCleared
+	gs.WorldEventSystem.OnReward = func(id ecs.Entity) {
+		if player, ok := gs.Players[id]; ok {
+			gs.PersistenceSystem.SavePlayer(id, player.Username)
+			gs.SendInventorySync(player)
+		}
+	}
+
+	gs.LootSystem = systems.NewLootSystem(worldECS, gs.GroundItemSystem)
+	gs.LootSystem.Audit = gs.ItemAudit
+	gs.LootSystem.OnRollStart = func(id ecs.Entity, roll *systems.LootRoll) {
+		if player, ok := gs.Players[id]; ok {
+			gs.SendLootRoll(player, roll)
+		}
+	}
+	gs.LootSystem.OnMessage = func(id ecs.Entity, msg string) {
+		if player, ok := gs.Players[id]; ok {
+			gs.Notify(player, msg)
+		}
+	}
+	gs.LootSystem.OnDeliver = func(id ecs.Entity) {
+		if player, ok := gs.Players[id]; ok {
+			gs.PersistenceSystem.SavePlayer(id, player.Username)
+			gs.SendInventorySync(player)
+		}
+	}
+
+	gs.DeathSystem = systems.NewDeathSystem(worldECS)
+	gs.DeathSystem.RespawnDelay = gs.respawnDelay
+	gs.DeathSystem.OnDespawn = func(id ecs.Entity) {
+		packet := protocol.Packet{Type: protocol.PacketEntityDespawn, Data: protocol.EntityDespawnPacket{EntityID: id}}
+		for _, pid := range gs.NetworkSystem.Despawned(id) {
+			if player, ok := gs.Players[pid]; ok {
+				player.Send(packet)
+			}
+		}
+	}
+
+	leaderboard, err := storage.LoadLeaderboard()
+	if err != nil {
+		log.Printf("Failed to load leaderboard, starting empty: %v", err)
+	}
+	gs.Leaderboard = leaderboard
+
+	mail, err := storage.LoadMail()
+	if err != nil {
+		log.Printf("Failed to load mail, starting empty: %v", err)
+	}
+	gs.MailSystem = systems.NewMailSystem(worldECS, mail)
+	gs.MailSystem.OnDeliver = gs.mailDelivered
+	gs.MailSystem.Audit = gs.ItemAudit
+	gs.WorldEventSystem.Mail = gs.MailSystem
+	gs.WorldEventSystem.Audit = gs.ItemAudit
+
+	gs.DuelSystem = systems.NewDuelSystem(worldECS)
+	gs.DuelSystem.OnInvite = gs.sendDuelInvite
+	gs.DuelSystem.OnUpdate = gs.sendDuelUpdate
+	gs.DuelSystem.OnEnd = gs.finishDuel
+
+	gs.InstanceSystem = systems.NewInstanceSystem(worldECS, maps)
+	gs.ArenaSystem = systems.NewArenaSystem(worldECS, gs.InstanceSystem)
+	gs.ArenaSystem.OnTeleport = func(id ecs.Entity) {
+		gs.CutsceneSystem.Stop(id) // Its camera targets are on the old level
+		if player, ok := gs.Players[id]; ok {
+			player.Send(gs.mapSyncPacket(id))
+		}
+	}
+	gs.ArenaSystem.OnMatchUpdate = func(match *systems.ArenaMatch) {
+		for _, id := range match.Players() {
+			gs.sendArenaState(id)
+		}
+	}
+	gs.ArenaSystem.OnMessage = func(id ecs.Entity, msg string) {
+		if player, ok := gs.Players[id]; ok {
+			gs.Notify(player, msg)
+		}
+	}
+	gs.ArenaSystem.OnQueueChange = gs.syncArenaQueue
+	gs.ArenaSystem.OnEnd = gs.finishArenaMatch
+
+	gs.SpectatorSystem = systems.NewSpectatorSystem(worldECS)
+	gs.SpectatorSystem.OnTeleport = gs.ArenaSystem.OnTeleport
+	gs.SpectatorSystem.OnChange = gs.sendSpectateState
+	gs.SpectatorSystem.OnMessage = gs.ArenaSystem.OnMessage
+
+	gs.BuildingSystem = systems.NewBuildingSystem(worldECS, maps)
+	gs.BuildingSystem.Audit = gs.ItemAudit
+
+	gs.HousingSystem = systems.NewHousingSystem(worldECS, gs.InstanceSystem, gs.BuildingSystem)
+	gs.HousingSystem.OnTeleport = gs.ArenaSystem.OnTeleport
+	gs.HousingSystem.OnChange = gs.houseChanged
+	gs.HousingSystem.OnVisit = gs.houseVisited
+	gs.HousingSystem.Audit = gs.ItemAudit
+
+	gs.FarmSystem = systems.NewFarmSystem(worldECS, maps)
+	gs.FarmSystem.OnObjectChange = gs.broadcastObject
+	gs.FarmSystem.Audit = gs.ItemAudit
+
+	gs.FishingSystem = systems.NewFishingSystem(worldECS, maps)
+	gs.FishingSystem.OnChange = gs.sendFishState
+	gs.FishingSystem.OnMessage = gs.ArenaSystem.OnMessage
+	gs.FishingSystem.Audit = gs.ItemAudit
+
+	gs.PlaytimeSystem = systems.NewPlaytimeSystem(worldECS)
+	gs.PlaytimeSystem.OnAFKChange = func(id ecs.Entity, afk bool) {
+		if player, ok := gs.Players[id]; ok {
+			if afk {
+				gs.Notify(player, "You are now AFK")
+			} else {
+				gs.Notify(player, "You are no longer AFK")
+			}
+		}
+	}
+
+	gs.AggroSystem = systems.NewAggroSystem(worldECS)
+	gs.AggroSystem.OnChange = func(id ecs.Entity, state systems.AggroState) {
+		if player, ok := gs.Players[id]; ok {
+			player.Send(protocol.Packet{Type: protocol.PacketCombatState, Data: protocol.CombatStatePacket{InCombat: state.InCombat, Boss: state.Boss}})
+		}
+	}
+	gs.AggroSystem.OnEngage = gs.npcEngaged
+
+	gs.CutsceneSystem = systems.NewCutsceneSystem(worldECS)
+	gs.CutsceneSystem.OnSend = gs.sendCutscene
+
+	gs.DialogueSystem = systems.NewDialogueSystem(worldECS, nil)
+	gs.wireDialogues()
+
+	gs.SpawnRegionSystem = systems.NewSpawnRegionSystem(worldECS, maps)
+	gs.SpawnRegionSystem.Spawn = gs.spawnOnLevel
+
+	gs.SeasonalSystem = systems.NewSeasonalSystem(worldECS, maps, nil)
+	gs.SeasonalSystem.Spawn = gs.spawnOnLevel
+	gs.SeasonalSystem.Announce = gs.Announce
+	gs.SeasonalSystem.OnObjectChange = gs.broadcastObject
+
+	gs.SchedulerSystem = systems.NewSchedulerSystem()
+	gs.registerScheduledTasks()
+
+	gs.TelemetrySystem = systems.NewTelemetrySystem()
+
+	gs.MacroDetector = systems.NewMacroDetector()
+	gs.MacroDetector.OnFlag = gs.flagMacro
+	gs.Diminishing = systems.NewDiminishingReturns()
+
+	gs.ZoneSystem = systems.NewZoneSystem(worldECS, maps)
+	gs.ZoneSystem.OnZoneChange = gs.SendZoneChange
+	gs.SpawnRegionSystem.Zones = gs.ZoneSystem
+
+	gs.DummySystem = systems.NewTrainingDummySystem(worldECS)
+	gs.DummySystem.OnReport = func(attacker ecs.Entity, msg string) {
+		if player, ok := gs.Players[attacker]; ok {
+			gs.Notify(player, msg)
+		}
+	}
+
+	gs.TriggerSystem = systems.NewTriggerSystem(worldECS, maps)
+	gs.TriggerSystem.OnEnter = gs.handleTriggerEnter
+
+	gs.WaypointSystem = systems.NewWaypointSystem(worldECS, maps)
+	gs.WaypointSystem.Economy = gs.EconomySystem
+	gs.WaypointSystem.OnDiscover = func(id ecs.Entity, wp *components.WaypointComponent) {
+		if player, ok := gs.Players[id]; ok {
+			gs.Notify(player, "Waypoint discovered: "+wp.Name)
+			gs.PersistenceSystem.SavePlayer(id, player.Username)
+			gs.SendWaypointSync(player)
+		}
+	}
 
+	gs.subscribeCombat()
 	return gs
 }
 
 func (s *GameServer) Run(port string) {
 	protocol.RegisterGobTypes()
+	s.conns = newConnLimiter(s.MaxConnsPerIP)
 	listener, err := net.Listen("tcp", port)
 	if err != nil {
 		log.Fatalf("Failed to listen on %s: %v", port, err)
 	}
-	log.Printf("Server listening on %s", port)
+	log.Printf("Server %s (protocol %s) listening on %s", config.BuildString(), protocol.ProtocolFingerprint(), port)
+	s.Events.Publish(systems.EventServerStart, "Server online", "")
 
 	// Start WebSocket Server
 	go func() {
 		log.Printf("WebSocket Server listening on :8081/ws")
-		network.StartWebSocketServer(":8081", s.HandleConnection)
+		network.StartWebSocketServer(":8081", s.TrustedProxies, s.HandleConnection)
 	}()
 
-	// Spawn Entities from Maps
-	for _, m := range s.Maps {
-		for _, spawner := range m.Spawners {
-			s.SpawnCharacter(spawner.X, spawner.Y, spawner.CharacterID)
-		}
+	s.populateWorld()
+
+	// Player-built structures and crops are real-world state, so only the live server restores them
+	if structures, err := storage.LoadStructures(); err != nil {
+		log.Printf("Failed to load structures: %v", err)
+	} else {
+		s.BuildingSystem.Load(structures)
+	}
+	if flags, err := storage.LoadMacroFlags(); err != nil {
+		log.Printf("Failed to load macro report: %v", err)
+	} else {
+		s.MacroFlags = flags
+	}
+	if houses, err := storage.LoadHouses(); err != nil {
+		log.Printf("Failed to load houses: %v", err)
+	} else {
+		s.HousingSystem.Load(houses)
+	}
+	if crops, err := storage.LoadCrops(); err != nil {
+		log.Printf("Failed to load crops: %v", err)
+	} else {
+		s.FarmSystem.Load(crops)
+	}
+	if chat, err := storage.LoadChatLog(); err != nil {
+		log.Printf("Failed to load chat log: %v", err)
+	} else {
+		s.ChatSystem.Log = systems.NewChatLog(chat)
 	}
+	s.loadSchedule()
 
 	// Game Loop
 	go s.GameLoop()
 
+	// Operator Console (maintenance, motd, shutdown)
+	go s.RunConsole(os.Stdin)
+
 	// Graceful Shutdown Handling
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -101,12 +508,7 @@ func (s *GameServer) Run(port string) {
 		sig := <-sigChan
 		log.Printf("Received signal %v, shutting down gracefully...", sig)
 		s.Mutex.Lock()
-		for id, player := range s.Players {
-			log.Printf("Saving player %s on shutdown...", player.Username)
-			s.PersistenceSystem.SavePlayer(id, player.Username)
-		}
-		s.Mutex.Unlock()
-		os.Exit(0)
+		s.stop("Stopped by the operator")
 	}()
 
 	for {
@@ -119,45 +521,209 @@ func (s *GameServer) Run(port string) {
 	}
 }
 
-func (s *GameServer) SpawnCharacter(x, y float64, charID string) {
+// populateWorld spawns the maps' NPCs (or resumes them from a checkpoint), waypoints and triggers
+func (s *GameServer) populateWorld() {
+	for _, m := range s.Maps {
+		for _, spawner := range m.Spawners {
+			npc := s.SpawnCharacter(spawner.X, spawner.Y, spawner.CharacterID)
+			if npc != 0 {
+				s.applySpawnerOverrides(npc, spawner)
+			}
+		}
+	}
+
+	if s.PersistNPCs {
+		if n, err := s.NPCStateSystem.Restore(); err != nil {
+			log.Printf("Failed to restore NPC state: %v", err)
+		} else if n > 0 {
+			log.Printf("Restored %d NPCs from checkpoint", n)
+		}
+	}
+
+	s.SpawnRegionSystem.Populate()
+	s.WaypointSystem.SpawnWaypoints()
+	s.TriggerSystem.SpawnTriggers()
+}
+
+func (s *GameServer) SpawnCharacter(x, y float64, charID string) ecs.Entity {
 	def, exists := characters.Get(charID)
 	if !exists {
-		return
+		return 0
+	}
+	if !s.SpawnLimiter.AllowEntity() {
+		log.Printf("Spawn of %s rejected: world entity cap (%d) reached", charID, s.SpawnLimiter.MaxEntities)
+		return 0
 	}
 
 	npc := s.World.NewEntity()
-	s.World.AddComponent(npc, components.TransformComponent{X: x, Y: y})
-	s.World.AddComponent(npc, components.PhysicsComponent{Speed: def.Speed})
-	s.World.AddComponent(npc, components.SpriteComponent{Width: def.SpriteWidth, Height: def.SpriteHeight, Color: def.Color, CharType: def.SpriteID})
-	s.World.AddComponent(npc, components.StatsComponent{MaxHealth: def.MaxHealth, CurrentHealth: def.MaxHealth})
-	s.World.AddComponent(npc, components.InputComponent{})
+	ecs.AddComponent(s.World, npc, components.TransformComponent{X: x, Y: y})
+	ecs.AddComponent(s.World, npc, components.PhysicsComponent{Speed: def.Speed, Layer: components.LayerNPC, Mask: components.MaskCharacter})
+	ecs.AddComponent(s.World, npc, components.SpriteComponent{Width: def.SpriteWidth, Height: def.SpriteHeight, Color: def.Color, CharType: def.SpriteID})
+	ecs.AddComponent(s.World, npc, components.StatsComponent{MaxHealth: def.MaxHealth, CurrentHealth: def.MaxHealth})
+	ecs.AddComponent(s.World, npc, components.InputComponent{})
+	ecs.AddComponent(s.World, npc, components.NameComponent{Name: def.Name})
+	s.World.AddTags(npc, components.TagNPC)
 
 	// AI Component
-	s.World.AddComponent(npc, components.AIComponent{
-		State:        "wander",
-		StateTimer:   0,
-		Faction:      def.Faction,
-		IsAggressive: def.IsAggressive,
-		SpawnX:       x,
-		SpawnY:       y,
-		LeashRange:   600.0, // Stop chasing after 600px
+	ecs.AddComponent(s.World, npc, components.AIComponent{
+		Type:          def.AIType,
+		State:         "wander",
+		StateTimer:    0,
+		Faction:       def.Faction,
+		IsAggressive:  def.IsAggressive,
+		SpawnX:        x,
+		SpawnY:        y,
+		LeashRange:    600.0, // Stop chasing after 600px
+		FleeThreshold: def.FleeThreshold,
+		HelpRadius:    def.HelpRadius,
+		AggroRange:    def.AggroRange,
+		Schedule:      def.Schedule,
+		Behavior:      def.Behavior,
+		Patrol:        patrolRoute(def, x, y),
 	})
 
+	if def.Attributes != (components.Attributes{}) {
+		ecs.AddComponent(s.World, npc, components.AttributesComponent{Base: def.Attributes})
+	}
+
 	// Equipment (Weapon)
 	if def.WeaponID != "" {
 		equip := components.EquipmentComponent{}
 		equip.Slots[components.SlotWeapon] = components.EquipmentSlot{ItemID: def.WeaponID}
-		s.World.AddComponent(npc, equip)
+		ecs.AddComponent(s.World, npc, equip)
+	}
+
+	// Training Dummies soak damage instead of dying
+	if def.AIType == "dummy" {
+		ecs.AddComponent(s.World, npc, components.TrainingDummyComponent{Sessions: make(map[ecs.Entity]*components.DummySession)})
+	}
+
+	// Dialogue
+	if def.Dialogue != "" {
+		ecs.AddComponent(s.World, npc, components.InteractableComponent{Dialogue: def.Dialogue})
+	}
+
+	// Vendor
+	if len(def.Shop) > 0 {
+		ecs.AddComponent(s.World, npc, components.ShopComponent{Name: def.Name, Stock: def.Shop})
+	}
+
+	// Overhead Markers (Quest/Vendor)
+	if def.Markers != 0 {
+		ecs.AddComponent(s.World, npc, components.MarkerComponent{Flags: def.Markers})
+	}
+
+	// Boss/Elite Loot Table
+	if len(def.Loot) > 0 {
+		ecs.AddComponent(s.World, npc, components.LootComponent{Table: def.Loot})
 	}
 
 	// Respawn Component
-	s.World.AddComponent(npc, components.RespawnComponent{
+	ecs.AddComponent(s.World, npc, components.RespawnComponent{
 		CharID:       charID,
 		SpawnX:       x,
 		SpawnY:       y,
 		RespawnTimer: 0,
 		IsDead:       false,
 	})
+
+	s.applyDifficulty(npc, def)
+	return npc
+}
+
+// spawnOnLevel spawns a character on any level (seasons, spawn regions). Assumes s.Mutex is LOCKED.
+func (s *GameServer) spawnOnLevel(level int, x, y float64, charID string) ecs.Entity {
+	npc := s.SpawnCharacter(x, y, charID)
+	if npc == 0 || level == 0 {
+		return npc
+	}
+	if trans, ok := ecs.GetComponent[components.TransformComponent](s.World, npc); ok {
+		trans.Z = level
+		ecs.AddComponent(s.World, npc, *trans)
+	}
+	if respawn, ok := ecs.GetComponent[components.RespawnComponent](s.World, npc); ok {
+		respawn.SpawnZ = level
+		ecs.AddComponent(s.World, npc, *respawn)
+	}
+	return npc
+}
+
+// applyDifficulty scales a freshly (re)spawned NPC to the zone it stands in and, in
+// level-scaled zones, to the players around it
+func (s *GameServer) applyDifficulty(npc ecs.Entity, def characters.CharacterDefinition) {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, npc)
+	if !ok {
+		return
+	}
+	d := systems.NPCDifficulty(s.World, s.Maps[trans.Z], trans.X, trans.Y, trans.Z, def.Level, def.XP)
+	ecs.AddComponent(s.World, npc, d)
+	if stats, ok := ecs.GetComponent[components.StatsComponent](s.World, npc); ok {
+		stats.MaxHealth = math.Round(def.MaxHealth * d.HealthScale)
+		stats.CurrentHealth = stats.MaxHealth
+		ecs.AddComponent(s.World, npc, *stats)
+	}
+}
+
+// spawnableCharacterIDs lists every registered character except training dummies and
+// town NPCs, in a stable order
+func spawnableCharacterIDs() []string {
+	var ids []string
+	for id, def := range characters.Registry {
+		if def.AIType != "dummy" && def.AIType != "static" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// checkMaps logs map reachability problems (at startup and after a reload). They don't stop
+// the server (cmd/mapcheck is the strict check), but players would get stuck or miss content.
+func checkMaps(maps map[int]*world.Map) {
+	spawn := world.MapPoint{Kind: "spawn", Name: "player", X: config.PlayerSpawnX, Y: config.PlayerSpawnY}
+	report := world.ValidateMaps(maps, spawn, config.TileSize, components.DefaultColliderSize, config.ShorePushRange)
+	for _, p := range report.Unreachable {
+		log.Printf("Map check: %s is unreachable from the player spawn", p)
+	}
+	if len(report.Pockets) > 0 {
+		log.Printf("Map check: %d walled-off walkable pocket(s), largest %d sub-cells at level %d tile %d,%d",
+			len(report.Pockets), report.Pockets[0].Cells, report.Pockets[0].Z, report.Pockets[0].TX, report.Pockets[0].TY)
+	}
+}
+
+// applySpawnerOverrides replaces a spawned NPC's schedule and patrol route with the
+// spawner's, if it sets them (kept across respawns)
+func (s *GameServer) applySpawnerOverrides(id ecs.Entity, spawner world.Spawner) {
+	if len(spawner.Schedule) == 0 && len(spawner.Patrol) == 0 && spawner.RespawnMax <= 0 {
+		return
+	}
+	if ai, ok := ecs.GetComponent[components.AIComponent](s.World, id); ok {
+		if len(spawner.Schedule) > 0 {
+			ai.Schedule = spawner.Schedule
+		}
+		if len(spawner.Patrol) > 0 {
+			ai.Patrol = spawner.Patrol
+		}
+		ecs.AddComponent(s.World, id, *ai)
+	}
+	if respawn, ok := ecs.GetComponent[components.RespawnComponent](s.World, id); ok {
+		respawn.Schedule = spawner.Schedule
+		respawn.Patrol = spawner.Patrol
+		respawn.RespawnMin, respawn.RespawnMax = spawner.RespawnMin, spawner.RespawnMax
+		ecs.AddComponent(s.World, id, *respawn)
+	}
+}
+
+// patrolRoute places a character's patrol route (relative to its spawn point) in the world
+func patrolRoute(def characters.CharacterDefinition, spawnX, spawnY float64) [][2]float64 {
+	if len(def.Patrol) == 0 {
+		return nil
+	}
+	route := make([][2]float64, len(def.Patrol))
+	for i, p := range def.Patrol {
+		route[i] = [2]float64{spawnX + p[0], spawnY + p[1]}
+	}
+	return route
 }
 
 func (s *GameServer) HandleConnection(conn net.Conn) {
@@ -165,10 +731,21 @@ func (s *GameServer) HandleConnection(conn net.Conn) {
 	decoder := gob.NewDecoder(conn)
 	encoder := gob.NewEncoder(conn)
 
+	ip := remoteIP(conn)
+	if !s.conns.Acquire(ip) {
+		log.Printf("Rejecting connection from %s: more than %d open", ip, s.MaxConnsPerIP)
+		encoder.Encode(protocol.Packet{Type: protocol.PacketLoginResponse, Data: protocol.LoginResponsePacket{Success: false, Error: "Too many connections from your address"}})
+		return
+	}
+	defer s.conns.Release(ip)
+
 	var playerEntity ecs.Entity
 	var username string
 	var player *Player
 
+	// A panic while serving this client only drops this client
+	defer s.recoverConnection(&username, &playerEntity)
+
 	for {
 		var packet protocol.Packet
 		if err := decoder.Decode(&packet); err != nil {
@@ -177,8 +754,12 @@ func (s *GameServer) HandleConnection(conn net.Conn) {
 		}
 
 		if packet.Type == protocol.PacketSignup {
-			req := packet.Data.(protocol.SignupPacket)
-			if req.Username == "" || req.Password == "" {
+			req, ok := packet.Data.(protocol.SignupPacket)
+			if !ok {
+				log.Printf("Malformed signup packet (%T)", packet.Data)
+				return
+			}
+			if !auth.ValidUsername(req.Username) || req.Password == "" {
 				encoder.Encode(protocol.Packet{Type: protocol.PacketSignupResponse, Data: protocol.SignupResponsePacket{Success: false, Error: "Invalid credentials"}})
 				continue
 			}
@@ -188,165 +769,97 @@ func (s *GameServer) HandleConnection(conn net.Conn) {
 				continue
 			}
 
-			newUser := storage.PlayerSaveData{Username: req.Username, Password: req.Password, X: 100, Y: 100, Health: 100}
+			hash, err := auth.Hash(req.Password)
+			if err != nil {
+				log.Printf("Failed to hash password for %s: %v", req.Username, err)
+				encoder.Encode(protocol.Packet{Type: protocol.PacketSignupResponse, Data: protocol.SignupResponsePacket{Success: false, Error: "Signup failed"}})
+				continue
+			}
+			newUser := storage.PlayerSaveData{Username: req.Username, PasswordHash: hash, X: config.PlayerSpawnX, Y: config.PlayerSpawnY, Health: config.PlayerBaseHealth}
 			storage.SavePlayer(newUser)
 			log.Printf("User signed up: %s", req.Username)
 			encoder.Encode(protocol.Packet{Type: protocol.PacketSignupResponse, Data: protocol.SignupResponsePacket{Success: true}})
 			continue
 
 		} else if packet.Type == protocol.PacketLogin {
-			req := packet.Data.(protocol.LoginPacket)
-			saved, err := storage.LoadPlayer(req.Username)
-
-			if err != nil || saved == nil {
-				encoder.Encode(protocol.Packet{Type: protocol.PacketLoginResponse, Data: protocol.LoginResponsePacket{Success: false, Error: "User not found"}})
-				continue
-			}
-
-			if saved.Password != req.Password {
-				encoder.Encode(protocol.Packet{Type: protocol.PacketLoginResponse, Data: protocol.LoginResponsePacket{Success: false, Error: "Wrong password"}})
-				continue
-			}
-
-			username = req.Username
-			log.Printf("Player %s logged in", username)
-
-			s.Mutex.Lock()
-			playerEntity = s.World.NewEntity()
-
-			spawnX, spawnY := saved.X, saved.Y
-			currentHealth := saved.Health
-
-			s.World.AddComponent(playerEntity, components.TransformComponent{X: spawnX, Y: spawnY})
-			s.World.AddComponent(playerEntity, components.PhysicsComponent{Speed: 3.0})
-			s.World.AddComponent(playerEntity, components.SpriteComponent{Width: 32, Height: 32, Color: color.RGBA{R: 0, G: 255, B: 0, A: 255}, CharType: "player"})
-			s.World.AddComponent(playerEntity, components.StatsComponent{MaxHealth: 100, CurrentHealth: currentHealth})
-			s.World.AddComponent(playerEntity, components.InputComponent{IsRunning: saved.IsRunning})
-
-			// Initial stats already added above
-			// Default weapon stats now fetched dynamically in HandleAttack
-
-			inv := items.NewInventory(25)
-			if len(saved.Inventory) > 0 {
-				for _, slot := range saved.Inventory {
-					if slot.Index >= 0 && slot.Index < 25 {
-						inv.Slots[slot.Index].ItemID = slot.ItemID
-						inv.Slots[slot.Index].Quantity = slot.Quantity
-					}
-				}
-			} else {
-				items.AddItem(inv, "sword_starter", 1)
-				items.AddItem(inv, "bow_starter", 1)
-				items.AddItem(inv, "potion_red", 5)
+			req, ok := packet.Data.(protocol.LoginPacket)
+			if !ok {
+				log.Printf("Malformed login packet (%T)", packet.Data)
+				return
 			}
-			s.World.AddComponent(playerEntity, *inv)
-
-			// Load Hotbar
-			var hotbar components.HotbarComponent
-			// Restore from save if present
-			for i, slot := range saved.Hotbar {
-				hotbar.Slots[i] = components.HotbarSlot{
-					Type:  slot.Type,
-					RefID: slot.RefID,
+			if req.Protocol != protocol.ProtocolFingerprint() {
+				client := req.ClientVersion
+				if client == "" {
+					client = "an older build"
 				}
+				log.Printf("Refusing login of %s: client %s speaks protocol %q, server %q", req.Username, client, req.Protocol, protocol.ProtocolFingerprint())
+				encoder.Encode(protocol.Packet{Type: protocol.PacketLoginResponse, Data: protocol.LoginResponsePacket{
+					Success:       false,
+					Error:         fmt.Sprintf("This client (%s) doesn't match the server (%s). Please update.", client, config.BuildString()),
+					ServerVersion: config.BuildString(),
+				}})
+				continue
 			}
-			s.World.AddComponent(playerEntity, hotbar)
-
-			// Load Equipment
-			var equip components.EquipmentComponent
-			for i, slot := range saved.Equipment {
-				if i < len(equip.Slots) {
-					equip.Slots[i].ItemID = slot.ItemID
-				}
+			saved, reason := s.checkLogin(ip, req.Username, req.Password)
+			if saved == nil {
+				encoder.Encode(protocol.Packet{Type: protocol.PacketLoginResponse, Data: protocol.LoginResponsePacket{Success: false, Error: reason}})
+				continue
 			}
-			s.World.AddComponent(playerEntity, equip)
 
-			spellbook := components.SpellbookComponent{
-				UnlockedSpells: saved.UnlockedSpells,
-			}
-			// Ensure it's not nil slices if possible (JSON might return nil)
-			if spellbook.UnlockedSpells == nil {
-				spellbook.UnlockedSpells = make([]string, 0)
+			if saved.Banned {
+				log.Printf("Refusing login of banned player %s", req.Username)
+				encoder.Encode(protocol.Packet{Type: protocol.PacketLoginResponse, Data: protocol.LoginResponsePacket{Success: false, Error: BanMessage(saved.BanReason)}})
+				continue
 			}
-			s.World.AddComponent(playerEntity, spellbook)
 
-			// Load UI State
-			uiState := components.UIStateComponent{
-				OpenMenus: saved.OpenMenus,
-			}
-			if uiState.OpenMenus == nil {
-				uiState.OpenMenus = make(map[string]bool)
+			s.Mutex.RLock()
+			maintenance := s.Maintenance
+			s.Mutex.RUnlock()
+			if maintenance && !saved.IsAdmin {
+				encoder.Encode(protocol.Packet{Type: protocol.PacketLoginResponse, Data: protocol.LoginResponsePacket{Success: false, Error: MaintenanceMessage}})
+				continue
 			}
-			s.World.AddComponent(playerEntity, uiState)
 
-			keybindings := saved.Keybindings
-			if keybindings == nil {
-				keybindings = make(map[string]int)
+			if err := s.waitForSlot(encoder, req.Username, saved.IsAdmin); err != nil {
+				log.Printf("Queued login of %s dropped: %v", req.Username, err)
+				return
 			}
-			s.World.AddComponent(playerEntity, components.KeybindingsComponent{Bindings: keybindings})
 
-			// Merge Defaults (Ensure new keys like "Spells" are present)
-			// KeyM = 12 (A=0, ..., I=8, ..., M=12)
-			defaults := map[string]int{
-				"Spells":         12, // M
-				config.ActionRun: 58, // Shift
-			}
-			anyMerged := false
-			for k, v := range defaults {
-				if _, exists := keybindings[k]; !exists {
-					keybindings[k] = v
-					anyMerged = true
+			username = req.Username
+			log.Printf("Player %s logged in (client %s)", username, req.ClientVersion)
+
+			player = s.spawnPlayer(conn, encoder, decoder, username, saved)
+			playerEntity = player.EntityID
+			s.withLock(func() {
+				s.releaseSlot()
+				s.SendInventorySync(player)
+				s.SendHotbarSync(player)
+				s.SendEquipmentSync(player)
+				s.SendWardrobeSync(player)
+				s.SendMapSync(player)
+				s.SendWaypointSync(player)
+				if len(s.HelpTopics) > 0 {
+					player.Send(protocol.Packet{Type: protocol.PacketHelpTopics, Data: protocol.HelpTopicsPacket{Topics: s.HelpTopics}})
 				}
-			}
 
-			if anyMerged {
-				// Update component so PersistenceSystem picks it up
-				s.World.AddComponent(playerEntity, components.KeybindingsComponent{Bindings: keybindings})
-				s.PersistenceSystem.SavePlayer(playerEntity, username)
-			}
-
-			player = &Player{
-				Conn:     conn,
-				Encoder:  encoder,
-				Decoder:  decoder,
-				EntityID: playerEntity,
-				Username: username,
-			}
-			s.Players[playerEntity] = player
-			s.Mutex.Unlock()
-
-			response := protocol.Packet{
-				Type: protocol.PacketLoginResponse,
-				Data: protocol.LoginResponsePacket{
-					Success:        true,
-					PlayerEntityID: playerEntity,
-					PlayerX:        spawnX,
-					PlayerY:        spawnY,
-					MapWidth:       s.Maps[0].Width,
-					MapHeight:      s.Maps[0].Height,
-					MapTiles:       world.FlattenTiles(s.Maps[0].Tiles),
-					MapObjects:     world.FlattenObjects(s.Maps[0].Objects),
-					UnlockedSpells: saved.UnlockedSpells,
-					Keybindings:    keybindings,
-					DebugSettings:  saved.DebugSettings,
-					OpenMenus:      saved.OpenMenus,
-					IsRunning:      saved.IsRunning,
-				},
-			}
-			if err := encoder.Encode(response); err != nil {
-				log.Printf("Failed to send login response: %v", err)
-				s.RemovePlayer(playerEntity)
-				return
-			}
-
-			s.SendInventorySync(player)
-			s.SendHotbarSync(player)
-			s.SendEquipmentSync(player)
-			s.SendMapSync(player)
+				// Message of the Day
+				if s.MOTD != "" {
+					s.Notify(player, s.MOTD)
+				}
+				if n := len(s.MailSystem.Inbox(player.Username)); n > 0 {
+					s.Notify(player, fmt.Sprintf("You have %d unclaimed mail", n))
+				}
+				s.sendMailbox(player)
+				s.sendHouseState(player)
+				if summary, ok := systems.AwaySummary(saved.LastSeen, time.Now().Unix(), s.MailSystem.Inbox(username), s.FarmSystem.Ripe(username)); ok {
+					player.Send(protocol.Packet{Type: protocol.PacketAwaySummary, Data: summary})
+				}
+			})
 			break
 		}
 	}
 
+	// From here on the game loop handles the packets (see lanes.go)
 	for {
 		var packet protocol.Packet
 		if err := decoder.Decode(&packet); err != nil {
@@ -354,196 +867,339 @@ func (s *GameServer) HandleConnection(conn net.Conn) {
 			s.RemovePlayer(playerEntity)
 			return
 		}
-		if packet.Type == protocol.PacketInput {
-			input := packet.Data.(protocol.InputPacket)
-			s.ProcessInput(playerEntity, input.Input)
-		} else if packet.Type == protocol.PacketUpdateKeybindings {
-			data := packet.Data.(protocol.UpdateKeybindingsPacket)
-			s.Mutex.Lock()
-			currData, err := storage.LoadPlayer(username)
-			if err == nil && currData != nil {
-				currData.Keybindings = data.Keybindings
-				// Update component as well
-				s.World.AddComponent(playerEntity, components.KeybindingsComponent{Bindings: data.Keybindings})
-				storage.SavePlayer(*currData)
-				log.Printf("Updated keybindings for %s", username)
-			}
-			s.Mutex.Unlock()
-		} else if packet.Type == protocol.PacketInventoryAction {
-			// Handle Inventory Actions
-			// Move this to InventorySystem later
-			action := packet.Data.(protocol.InventoryActionPacket)
-			s.HandleInventoryAction(playerEntity, action, player)
-		} else if packet.Type == protocol.PacketHotbarAction {
-			action := packet.Data.(protocol.HotbarActionPacket)
-			s.HandleHotbarAction(playerEntity, action, player)
-		} else if packet.Type == protocol.PacketEquipmentAction {
-			action := packet.Data.(protocol.EquipmentActionPacket)
-			s.HandleEquipmentAction(playerEntity, action, player)
-		} else if packet.Type == protocol.PacketCastSpell {
-			req := packet.Data.(protocol.CastSpellPacket)
-			s.Mutex.Lock()
-			// Use cursor position from last known input for target?
-			// Or just assume self/direction?
-			// Instants like Heal are self. Blink is directional.
-			// InputComponent has MouseX/Y.
-			var mx, my float64
-			if input, ok := ecs.GetComponent[components.InputComponent](s.World, playerEntity); ok {
-				mx, my = input.MouseX, input.MouseY
-			}
-			// We can pass this to handler
-			s.handleSpellCast(playerEntity, req.SpellID, mx, my)
-			s.Mutex.Unlock()
-		} else if packet.Type == protocol.PacketUpdateUIState {
-			data := packet.Data.(protocol.UpdateUIStatePacket)
-			s.Mutex.Lock()
-			uiState, _ := ecs.GetComponent[components.UIStateComponent](s.World, playerEntity)
-			if uiState == nil {
-				uiState = &components.UIStateComponent{OpenMenus: make(map[string]bool)}
-			}
-			// Update state
-			uiState.OpenMenus = data.OpenMenus
-			s.World.AddComponent(playerEntity, *uiState)
-			// Save
-			if err := s.PersistenceSystem.SavePlayer(playerEntity, username); err != nil {
-				log.Printf("Error saving UI state: %v", err)
-			}
-			s.Mutex.Unlock()
+		if !s.queueCommand(player, packet) {
+			s.RemovePlayer(playerEntity)
+			return
 		}
 	}
 }
 
-func (s *GameServer) HandleInventoryAction(id ecs.Entity, action protocol.InventoryActionPacket, player *Player) {
+// spawnPlayer creates the player entity from its save data, registers the connection
+// and sends the login response, ahead of anything the game loop sends the player.
+func (s *GameServer) spawnPlayer(conn net.Conn, encoder *gob.Encoder, decoder *gob.Decoder, username string, saved *storage.PlayerSaveData) *Player {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
-	if inv == nil {
-		return
+	playerEntity := s.World.NewEntity()
+
+	spawnX, spawnY := saved.X, saved.Y
+	currentHealth := saved.Health
+
+	// Combat XP moved out of the skills into the stats; older saves keep it under "combat"
+	xp := saved.XP
+	if combat, ok := saved.Skills[systems.SkillCombat]; ok {
+		xp = max(xp, combat)
+		delete(saved.Skills, systems.SkillCombat)
+	}
+	level := components.CombatLevel(xp)
+
+	ecs.AddComponent(s.World, playerEntity, components.TransformComponent{X: spawnX, Y: spawnY})
+	ecs.AddComponent(s.World, playerEntity, components.PhysicsComponent{Speed: 3.0, Layer: components.LayerPlayer, Mask: components.MaskCharacter})
+	ecs.AddComponent(s.World, playerEntity, components.SpriteComponent{Width: 32, Height: 32, Color: color.RGBA{R: 0, G: 255, B: 0, A: 255}, CharType: "player"})
+	ecs.AddComponent(s.World, playerEntity, components.StatsComponent{MaxHealth: systems.PlayerMaxHealth(level), CurrentHealth: currentHealth, Level: level, XP: xp})
+	ecs.AddComponent(s.World, playerEntity, components.InputComponent{Stance: saved.Stance})
+	ecs.AddComponent(s.World, playerEntity, components.StanceComponent{Stance: components.StanceWalk, Stamina: config.MaxStamina})
+	ecs.AddComponent(s.World, playerEntity, components.NameComponent{Name: username})
+	ecs.AddComponent(s.World, playerEntity, components.AttributesComponent{Base: systems.PlayerAttributes(level)})
+	s.World.AddTags(playerEntity, components.TagPlayer)
+
+	// Initial stats already added above
+	// Default weapon stats now fetched dynamically in HandleAttack
+
+	inv := items.NewInventory(25)
+	if len(saved.Inventory) > 0 {
+		for _, slot := range saved.Inventory {
+			if slot.Index >= 0 && slot.Index < 25 {
+				inv.Slots[slot.Index].ItemID = slot.ItemID
+				inv.Slots[slot.Index].Quantity = slot.Quantity
+				inv.Slots[slot.Index].Locked = slot.Locked
+				inv.Slots[slot.Index].InstanceID = slot.InstanceID
+			}
+		}
+		items.AssignInstanceIDs(inv) // Saves from before instance IDs
+	} else {
+		items.AddItem(inv, "sword_starter", 1)
+		items.AddItem(inv, "bow_starter", 1)
+		items.AddItem(inv, "potion_red", 5)
+		items.AddItem(inv, "build_banner", 1)
+		items.AddItem(inv, "build_wall_wood", 4)
+		items.AddItem(inv, "build_campfire", 1)
+		items.AddItem(inv, "seed_wheat", 5)
+		items.AddItem(inv, "rod_fishing", 1)
+		for _, slot := range inv.Slots {
+			s.ItemAudit.RecordAccount("starter_kit", username, "", slot.ItemID, slot.InstanceID, slot.Quantity)
+		}
 	}
-
-	if action.ActionType == "Swap" {
-		items.SwapItems(inv, action.SlotA, action.SlotB)
-	} else if action.ActionType == "Drop" {
-		// Remove item from slot
-		// For now, just delete. Future: Spawn drop entity.
-		if action.SlotA >= 0 && action.SlotA < len(inv.Slots) {
-			inv.Slots[action.SlotA].ItemID = ""
-			inv.Slots[action.SlotA].Quantity = 0
-			log.Printf("Player %s dropped item from slot %d", player.Username, action.SlotA)
+	ecs.AddComponent(s.World, playerEntity, *inv)
+	ecs.AddComponent(s.World, playerEntity, components.WalletComponent{Gold: saved.Gold})
+	systems.PocketGold(s.World, playerEntity) // Saves from before wallets
+	var keyring components.KeyringComponent
+	for _, slot := range saved.Keyring {
+		items.AddToKeyring(&keyring, slot.ItemID, slot.InstanceID, slot.Quantity)
+	}
+	ecs.AddComponent(s.World, playerEntity, keyring)
+	systems.PocketKeyring(s.World, playerEntity) // Saves from before keyrings
+
+	// Load Hotbar
+	var hotbar components.HotbarComponent
+	// Restore from save if present
+	for i, slot := range saved.Hotbar {
+		hotbar.Slots[i] = components.HotbarSlot{
+			Type:  slot.Type,
+			RefID: slot.RefID,
 		}
-	} else if action.ActionType == "Primary" {
-		if action.SlotA >= 0 && action.SlotA < len(inv.Slots) {
-			itemID := inv.Slots[action.SlotA].ItemID
-			if itemID != "" {
-				def, ok := items.Get(itemID)
-				if ok && def.EquipmentSlot != -1 {
-					s.equipItemInternal(id, action.SlotA, def.EquipmentSlot, player)
-					return
-				}
-				// Handle Consumables here later
-				log.Printf("Player %s used primary action on slot %d: %s", player.Username, action.SlotA, itemID)
+	}
+	ecs.AddComponent(s.World, playerEntity, hotbar)
+
+	// Load Equipment
+	var equip components.EquipmentComponent
+	for i, slot := range saved.Equipment {
+		if i < len(equip.Slots) && slot.ItemID != "" {
+			equip.Slots[i] = components.EquipmentSlot{ItemID: slot.ItemID, InstanceID: slot.InstanceID}
+			if slot.InstanceID == "" {
+				equip.Slots[i].InstanceID = items.NewInstanceID()
 			}
 		}
 	}
-	// Save changes back to World
-	s.World.AddComponent(id, *inv)
+	ecs.AddComponent(s.World, playerEntity, equip)
 
-	// Explicitly save to file
-	go s.PersistenceSystem.SavePlayer(id, player.Username)
+	ecs.AddComponent(s.World, playerEntity, components.CosmeticsComponent{Unlocked: saved.Cosmetics, Looks: saved.Looks})
+	for _, slot := range equip.Slots {
+		systems.UnlockLook(s.World, playerEntity, slot.ItemID) // Gear worn before the wardrobe existed
+	}
 
-	// Sync inventory change back to client
-	go s.SendInventorySync(player)
-}
+	spellbook := components.SpellbookComponent{
+		UnlockedSpells: saved.UnlockedSpells,
+	}
+	// Ensure it's not nil slices if possible (JSON might return nil)
+	if spellbook.UnlockedSpells == nil {
+		spellbook.UnlockedSpells = make([]string, 0)
+	}
+	ecs.AddComponent(s.World, playerEntity, spellbook)
 
-func (s *GameServer) HandleEquipmentAction(id ecs.Entity, action protocol.EquipmentActionPacket, player *Player) {
-	s.Mutex.Lock()
-	defer s.Mutex.Unlock()
+	travel := components.TravelComponent{
+		UnlockedWaypoints: saved.Waypoints,
+	}
+	if travel.UnlockedWaypoints == nil {
+		travel.UnlockedWaypoints = make([]string, 0)
+	}
+	ecs.AddComponent(s.World, playerEntity, travel)
 
-	if action.Action == "Equip" {
-		s.equipItemInternal(id, action.InvSlot, action.Slot, player)
-	} else if action.Action == "Unequip" {
-		equip, _ := ecs.GetComponent[components.EquipmentComponent](s.World, id)
-		inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	skills := components.SkillsComponent{XP: saved.Skills}
+	if skills.XP == nil {
+		skills.XP = make(map[string]int)
+	}
+	ecs.AddComponent(s.World, playerEntity, skills)
+	ecs.AddComponent(s.World, playerEntity, components.PlaytimeComponent{Seconds: saved.Playtime})
+	ecs.AddComponent(s.World, playerEntity, components.CutscenesComponent{Seen: saved.Cutscenes})
 
-		if equip == nil || inv == nil {
-			return
-		}
+	// Load UI State
+	uiState := components.UIStateComponent{
+		OpenMenus: saved.OpenMenus,
+	}
+	if uiState.OpenMenus == nil {
+		uiState.OpenMenus = make(map[string]bool)
+	}
+	ecs.AddComponent(s.World, playerEntity, uiState)
 
-		if action.Slot < 0 || action.Slot >= 9 {
-			return
-		}
-		itemID := equip.Slots[action.Slot].ItemID
-		if itemID == "" {
-			return
-		}
+	keybindings := saved.Keybindings
+	if keybindings == nil {
+		keybindings = make(map[string]int)
+	}
+	ecs.AddComponent(s.World, playerEntity, components.KeybindingsComponent{Bindings: keybindings})
+	ecs.AddComponent(s.World, playerEntity, components.ZoneComponent{})
 
-		// Try to add to Inventory
-		err := items.AddItem(inv, itemID, 1)
-		if err == nil {
-			equip.Slots[action.Slot].ItemID = ""
-			log.Printf("Player %s unequipped %s", player.Username, itemID)
-		} else {
-			log.Printf("Player %s failed to unequip %s: Inventory Full", player.Username, itemID)
+	// Merge Defaults (Ensure new keys like "Spells" are present)
+	// KeyM = 12 (A=0, ..., I=8, ..., M=12)
+	defaults := map[string]int{
+		"Spells":           12, // M
+		config.ActionRun:   58, // Shift
+		config.ActionSneak: 2,  // C
+		config.ActionDodge: 16, // Q
+	}
+	anyMerged := false
+	for k, v := range defaults {
+		if _, exists := keybindings[k]; !exists {
+			keybindings[k] = v
+			anyMerged = true
 		}
-
-		// Save components explicitly!
-		s.World.AddComponent(id, *equip)
-		s.World.AddComponent(id, *inv)
-
-		go s.SendInventorySync(player)
-		go s.SendEquipmentSync(player)
 	}
 
-	// Explicitly save to file after any equipment change
-	go s.PersistenceSystem.SavePlayer(id, player.Username)
+	if anyMerged {
+		// Update component so PersistenceSystem picks it up
+		ecs.AddComponent(s.World, playerEntity, components.KeybindingsComponent{Bindings: keybindings})
+		s.PersistenceSystem.SavePlayer(playerEntity, username)
+	}
+
+	player := &Player{
+		Conn:     conn,
+		Encoder:  encoder,
+		Decoder:  decoder,
+		EntityID: playerEntity,
+		Username: username,
+		IsAdmin:  saved.IsAdmin,
+
+		LootFilter: items.LootFilterFrom(saved.DebugSettings),
+	}
+	player.startWriter()
+	player.Send(protocol.Packet{
+		Type: protocol.PacketLoginResponse,
+		Data: protocol.LoginResponsePacket{
+			Success:        true,
+			PlayerEntityID: playerEntity,
+			PlayerX:        saved.X,
+			PlayerY:        saved.Y,
+			MapWidth:       s.Maps[0].Width,
+			MapHeight:      s.Maps[0].Height,
+			MapTiles:       world.FlattenTiles(s.Maps[0].Tiles),
+			MapObjects:     world.FlattenObjects(s.Maps[0].Objects),
+			UnlockedSpells: saved.UnlockedSpells,
+			Spells:         components.Spells(),
+			Keybindings:    keybindings,
+			DebugSettings:  saved.DebugSettings,
+			OpenMenus:      saved.OpenMenus,
+			Stance:         saved.Stance,
+			IsAdmin:        saved.IsAdmin,
+			ServerVersion:  config.BuildString(),
+		},
+	})
+	s.Players[playerEntity] = player
+	s.TelemetrySystem.RecordLogin(username, len(s.Players))
+	return player
 }
 
-func (s *GameServer) HandleHotbarAction(id ecs.Entity, action protocol.HotbarActionPacket, player *Player) {
-	s.Mutex.Lock()
-	// defer s.Mutex.Unlock() // REMOVED to avoid double unlock
+// HandleBugReport stores a playtester report with the reporting account
+func (s *GameServer) HandleBugReport(player *Player, req protocol.BugReportPacket) {
+	now := time.Now()
+	if now.Sub(player.LastBugReport).Seconds() < config.BugReportCooldown {
+		s.Notify(player, "Please wait a moment before sending another bug report.")
+		return
+	}
+	player.LastBugReport = now
 
-	hb, _ := ecs.GetComponent[components.HotbarComponent](s.World, id)
-	if hb == nil {
-		s.Mutex.Unlock()
+	if len(req.Description) > maxBugReportLength {
+		req.Description = req.Description[:maxBugReportLength]
+	}
+	if len(req.Logs) > config.BugReportLogLines {
+		req.Logs = req.Logs[len(req.Logs)-config.BugReportLogLines:]
+	}
+
+	id, err := storage.SaveBugReport(storage.BugReport{
+		Username:    player.Username,
+		ReportedAt:  now,
+		Description: req.Description,
+		X:           req.X,
+		Y:           req.Y,
+		FPS:         req.FPS,
+		Version:     req.Version,
+		Logs:        req.Logs,
+	})
+	if err != nil {
+		log.Printf("Failed to save bug report from %s: %v", player.Username, err)
+		s.Notify(player, "Bug report could not be saved, sorry!")
 		return
 	}
 
-	if action.ActionType == "Bind" {
-		if action.SlotIndex >= 0 && action.SlotIndex < 10 {
-			hb.Slots[action.SlotIndex].Type = action.TargetType
-			hb.Slots[action.SlotIndex].RefID = action.TargetRefID
-			log.Printf("Player %s bound %s:%s to slot %d", player.Username, action.TargetType, action.TargetRefID, action.SlotIndex)
-		}
-	} else if action.ActionType == "Swap" {
-		if action.SlotIndex >= 0 && action.SlotIndex < 10 && action.SlotIndexB >= 0 && action.SlotIndexB < 10 {
-			hb.Slots[action.SlotIndex], hb.Slots[action.SlotIndexB] = hb.Slots[action.SlotIndexB], hb.Slots[action.SlotIndex]
+	log.Printf("Bug report %s from %s: %s", id, player.Username, req.Description)
+	s.Notify(player, "Thanks! Bug report received.")
+}
+
+func (s *GameServer) HandleInventoryAction(id ecs.Entity, action protocol.InventoryActionPacket, player *Player) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	used := ""
+	if inv, ok := ecs.GetComponent[components.InventoryComponent](s.World, id); ok && action.ActionType == "Primary" {
+		if slot := items.FindInstance(inv, action.InstanceID); slot >= 0 {
+			used = inv.Slots[slot].ItemID
 		}
 	}
+	changes, err := s.Service.InventoryAction(id, action)
+	if err != nil {
+		log.Printf("Player %s inventory action %s (item %s) failed: %v", player.Username, action.ActionType, action.InstanceID, err)
+		s.notifyItemUse(player, used, err)
+	} else if used != "" {
+		s.TelemetrySystem.RecordItem(used)
+	}
+	s.applyChanges(player, changes)
+}
 
-	// Save back to world
-	s.World.AddComponent(id, *hb)
+func (s *GameServer) HandleEquipmentAction(id ecs.Entity, action protocol.EquipmentActionPacket, player *Player) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
 
-	// Explicitly save to file
-	go s.PersistenceSystem.SavePlayer(id, player.Username)
+	changes, err := s.Service.EquipmentAction(id, action)
+	if err != nil {
+		log.Printf("Player %s equipment action %s (slot %d) failed: %v", player.Username, action.Action, action.Slot, err)
+	}
+	s.applyChanges(player, changes)
+}
 
-	s.Mutex.Unlock()
+func (s *GameServer) HandleHotbarAction(id ecs.Entity, action protocol.HotbarActionPacket, player *Player) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
 
-	// Sync back to client
-	s.SendHotbarSync(player)
+	changes, err := s.Service.HotbarAction(id, action)
+	if err != nil {
+		log.Printf("Player %s hotbar action %s (slot %d) failed: %v", player.Username, action.ActionType, action.SlotIndex, err)
+	}
+	s.applyChanges(player, changes)
+}
+
+// applyChanges saves the player and syncs whatever the service changed. Assumes s.Mutex is LOCKED.
+func (s *GameServer) applyChanges(player *Player, changes service.Changes) {
+	if !changes.Any() {
+		return
+	}
+	s.PersistenceSystem.SavePlayer(player.EntityID, player.Username)
+	if changes.Inventory {
+		s.SendInventorySync(player)
+	}
+	if changes.Equipment {
+		s.SendEquipmentSync(player)
+	}
+	if changes.Hotbar {
+		s.SendHotbarSync(player)
+	}
+	if changes.Spellbook || changes.Equipment { // Gear can change cooldown reduction
+		s.SendSpellbookSync(player)
+	}
+	if changes.Cosmetics {
+		s.SendWardrobeSync(player)
+	}
 }
 
 func (s *GameServer) RemovePlayer(id ecs.Entity) {
 	s.Mutex.Lock()
 
+	// Leaving mid-duel counts as a forfeit; arena players are returned before the save
+	s.DuelSystem.Forfeit(id)
+	s.ArenaSystem.Leave(id)
+	s.HousingSystem.Leave(id)
+	s.SpectatorSystem.Stop(id)
+	s.FishingSystem.Stop(id)
+
 	if player, ok := s.Players[id]; ok {
 		// Use Persistence System
 		if err := s.PersistenceSystem.SavePlayer(id, player.Username); err != nil {
 			log.Printf("Failed to save player %s: %v", player.Username, err)
 		}
+		s.savePlaytimeLeaderboard()
 	}
 
+	if player, ok := s.Players[id]; ok {
+		player.Close()
+	}
 	delete(s.Players, id)
+	s.NetworkSystem.ForgetPlayer(id)
+	s.PersistenceSystem.ForgetPlayer(id)
+	s.LootSystem.ForgetPlayer(id)
+	s.PlaytimeSystem.ForgetPlayer(id)
+	s.AggroSystem.ForgetPlayer(id)
+	s.CutsceneSystem.ForgetPlayer(id)
+	s.DialogueSystem.ForgetPlayer(id)
+	s.MovementSystem.ForgetPlayer(id)
+	s.ChatSystem.ForgetPlayer(id)
 	s.World.RemoveEntity(id)
 	s.Mutex.Unlock()
 }
@@ -557,8 +1213,39 @@ func (s *GameServer) ProcessInput(id ecs.Entity, input components.InputComponent
 		return
 	}
 
-	if input.Attack {
-		// Log attack?
+	// The camera isn't theirs during a cutscene (CutsceneSystem already let go of their keys)
+	if s.CutsceneSystem.Playing(id) {
+		return
+	}
+
+	// Spectators only steer their camera
+	if systems.IsSpectating(s.World, id) {
+		input.Attack = false
+		input.ActiveSpell = ""
+		input.HotbarTriggers = [10]bool{}
+		input.Dodge = false
+	}
+
+	// Manual movement cancels click-to-move / follow
+	moving := input.Up || input.Down || input.Left || input.Right
+	if moving {
+		s.AutoMoveSystem.Stop(id)
+	}
+
+	// Any key, click or mouse movement counts as activity for AFK detection
+	if moving || input.Attack || input != player.PrevInput {
+		s.PlaytimeSystem.Activity(id)
+	}
+
+	// Presses (not holds) feed the macro heuristics
+	if input.Attack && !player.PrevInput.Attack {
+		s.MacroDetector.Press(player.Username, "attack")
+	}
+
+	// Dodge on the key press; without stamina the press is just ignored
+	if input.Dodge && !player.PrevInput.Dodge {
+		s.MacroDetector.Press(player.Username, "dodge")
+		s.MovementSystem.Dodge(id, input)
 	}
 
 	// Handle Hotbar Triggers
@@ -566,8 +1253,14 @@ func (s *GameServer) ProcessInput(id ecs.Entity, input components.InputComponent
 	if hb != nil {
 		for i := 0; i < 10; i++ {
 			if input.HotbarTriggers[i] && !player.PrevInput.HotbarTriggers[i] {
+				s.MacroDetector.Press(player.Username, fmt.Sprintf("hotbar %d", i+1))
 				slot := hb.Slots[i]
-				if slot.Type == "Item" && slot.RefID != "" {
+				if slot.Type == "Item" && slot.RefID == systems.FishingRod {
+					s.useFishingRod(player)
+				} else if slot.Type == "Item" && service.IsConsumable(slot.RefID) {
+					s.useItem(player, slot.RefID)
+				} else if slot.Type == "Item" && slot.RefID != "" {
+					s.TelemetrySystem.RecordItem(slot.RefID)
 					s.toggleEquipItem(id, slot.RefID, player)
 				} else if slot.Type == "Spell" && slot.RefID != "" {
 					// Toggle Active Spell if Combat, or Cast if Instant
@@ -613,16 +1306,31 @@ func (s *GameServer) ProcessInput(id ecs.Entity, input components.InputComponent
 		}
 	}
 
-	s.World.AddComponent(id, input)
+	ecs.AddComponent(s.World, id, input)
 }
 
 func (s *GameServer) GameLoop() {
-	ticker := time.NewTicker(time.Millisecond * 33) // ~30 TPS
+	ticker := time.NewTicker(time.Second / config.ServerTickRate)
 	defer ticker.Stop()
 
+	// Broadcast every Nth tick
+	broadcastEvery := config.ServerTickRate / config.SnapshotRate
+	if broadcastEvery < 1 {
+		broadcastEvery = 1
+	}
+
+	start := time.Now()
+	tick := 0
 	for range ticker.C {
+		s.runCommands()
 		s.Update()
-		s.BroadcastState()
+		tick++
+		if tick%broadcastEvery == 0 {
+			// Numbered by the clock rather than counted, so broadcasts lost to ticks the
+			// ticker dropped (an overrunning Update) show up as gaps on the client
+			s.NetworkSystem.Seq = uint32(math.Round(time.Since(start).Seconds() * config.SnapshotRate))
+			s.BroadcastState()
+		}
 	}
 }
 
@@ -634,92 +1342,263 @@ func (s *GameServer) UpdateRespawn(dt float64) {
 			continue
 		}
 
+		// Hunting players hurry it along, an empty zone holds it back
+		if delay := s.respawnDelay(respawn); respawn.RespawnDelay <= 0 {
+			respawn.RespawnDelay = delay // Resumed from a checkpoint: the timer is already what's left
+		} else if delay != respawn.RespawnDelay {
+			respawn.RespawnTimer = systems.RetimeRespawn(respawn.RespawnTimer, respawn.RespawnDelay, delay)
+			respawn.RespawnDelay = delay
+		}
+
 		respawn.RespawnTimer -= dt
 		if respawn.RespawnTimer <= 0 {
 			// RESPAWN!
 			respawn.IsDead = false
-			s.World.AddComponent(id, *respawn)
+			ecs.AddComponent(s.World, id, *respawn)
 
 			// Get Character Definition for restoration
 			def, exists := characters.Get(respawn.CharID)
 			if !exists {
 				// Fallback to basic guard if somehow missing, but this shouldn't happen
 				log.Printf("Warning: Missing character definition %s during respawn of entity %d", respawn.CharID, id)
-				s.World.AddComponent(id, components.TransformComponent{X: respawn.SpawnX, Y: respawn.SpawnY})
-				s.World.AddComponent(id, components.PhysicsComponent{Speed: 3.0})
-				s.World.AddComponent(id, components.SpriteComponent{Width: 32, Height: 32, Color: color.RGBA{R: 255, G: 255, B: 0, A: 255}})
-				s.World.AddComponent(id, components.StatsComponent{MaxHealth: 50, CurrentHealth: 50})
+				ecs.AddComponent(s.World, id, components.TransformComponent{X: respawn.SpawnX, Y: respawn.SpawnY, Z: respawn.SpawnZ})
+				ecs.AddComponent(s.World, id, components.PhysicsComponent{Speed: 3.0, Layer: components.LayerNPC, Mask: components.MaskCharacter})
+				ecs.AddComponent(s.World, id, components.SpriteComponent{Width: 32, Height: 32, Color: color.RGBA{R: 255, G: 255, B: 0, A: 255}})
+				ecs.AddComponent(s.World, id, components.StatsComponent{MaxHealth: 50, CurrentHealth: 50})
 			} else {
+				schedule := def.Schedule
+				if len(respawn.Schedule) > 0 {
+					schedule = respawn.Schedule
+				}
+				patrol := patrolRoute(def, respawn.SpawnX, respawn.SpawnY)
+				if len(respawn.Patrol) > 0 {
+					patrol = respawn.Patrol
+				}
+
 				// Restore Components using Definition
-				s.World.AddComponent(id, components.TransformComponent{X: respawn.SpawnX, Y: respawn.SpawnY})
-				s.World.AddComponent(id, components.PhysicsComponent{Speed: def.Speed})
-				s.World.AddComponent(id, components.SpriteComponent{
+				ecs.AddComponent(s.World, id, components.TransformComponent{X: respawn.SpawnX, Y: respawn.SpawnY, Z: respawn.SpawnZ})
+				ecs.AddComponent(s.World, id, components.PhysicsComponent{Speed: def.Speed, Layer: components.LayerNPC, Mask: components.MaskCharacter})
+				ecs.AddComponent(s.World, id, components.SpriteComponent{
 					Width:    def.SpriteWidth,
 					Height:   def.SpriteHeight,
 					Color:    def.Color,
 					CharType: def.SpriteID,
 				})
-				s.World.AddComponent(id, components.StatsComponent{MaxHealth: def.MaxHealth, CurrentHealth: def.MaxHealth})
+				ecs.AddComponent(s.World, id, components.StatsComponent{MaxHealth: def.MaxHealth, CurrentHealth: def.MaxHealth})
 
 				// AI Component (Restore original definition settings)
-				s.World.AddComponent(id, components.AIComponent{
-					Type:         def.AIType,
-					State:        "wander",
-					StateTimer:   1.0,
-					IsAggressive: def.IsAggressive,
-					Faction:      def.Faction,
-					SpawnX:       respawn.SpawnX,
-					SpawnY:       respawn.SpawnY,
-					LeashRange:   600.0,
+				ecs.AddComponent(s.World, id, components.AIComponent{
+					Type:          def.AIType,
+					State:         "wander",
+					StateTimer:    1.0,
+					IsAggressive:  def.IsAggressive,
+					Faction:       def.Faction,
+					SpawnX:        respawn.SpawnX,
+					SpawnY:        respawn.SpawnY,
+					LeashRange:    600.0,
+					FleeThreshold: def.FleeThreshold,
+					HelpRadius:    def.HelpRadius,
+					AggroRange:    def.AggroRange,
+					Schedule:      schedule,
+					Behavior:      def.Behavior,
+					Patrol:        patrol,
 				})
 
 				// Equipment (Restore original weapon if any)
 				if def.WeaponID != "" {
 					equip := components.EquipmentComponent{}
 					equip.Slots[components.SlotWeapon] = components.EquipmentSlot{ItemID: def.WeaponID}
-					s.World.AddComponent(id, equip)
+					ecs.AddComponent(s.World, id, equip)
 				}
+
+				// Re-scaled for whoever is around now
+				s.applyDifficulty(id, def)
 			}
 
-			s.World.AddComponent(id, components.InputComponent{})
+			ecs.AddComponent(s.World, id, components.InputComponent{})
 			log.Printf("Entity %d respawned at %.1f, %.1f", id, respawn.SpawnX, respawn.SpawnY)
 		} else {
-			s.World.AddComponent(id, *respawn)
+			ecs.AddComponent(s.World, id, *respawn)
+		}
+	}
+}
+
+// respawnDelay is how long a spawner NPC stays dead, given how many players are hunting
+// the zone around its spawn point right now
+func (s *GameServer) respawnDelay(respawn *components.RespawnComponent) float64 {
+	fastest, slowest := respawn.RespawnMin, respawn.RespawnMax
+	if slowest <= 0 {
+		base := config.NPCRespawnSeconds
+		if def, ok := characters.Get(respawn.CharID); ok && def.RespawnSeconds > 0 {
+			base = def.RespawnSeconds
 		}
+		fastest, slowest = systems.RespawnRange(base)
+	} else if fastest <= 0 || fastest > slowest {
+		fastest = slowest
 	}
+	half := float64(config.TileSize) / 2
+	players := s.ZoneSystem.PlayersAt(respawn.SpawnZ, respawn.SpawnX+half, respawn.SpawnY+half)
+	return systems.RespawnDelay(fastest, slowest, players)
 }
 
 func (s *GameServer) Update() {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
+	dt := 1.0 / config.ServerTickRate
+
+	// Each system runs under runSystem so a panic in one is logged and skipped for this tick
+
+	// Recount entities / reset projectile budget
+	s.runSystem("SpawnLimiter", func() { s.SpawnLimiter.Update(dt) })
+
+	// Advance Day/Night Clock
+	s.Clock.Advance(dt)
+
 	// Update AI
-	s.AISystem.Update(0.033)
+	s.runSystem("AI", func() { s.AISystem.Update(dt) })
+
+	// Despawn Corpses / Respawn
+	s.runSystem("Death", func() { s.DeathSystem.Update(dt) })
+	s.runSystem("Respawn", func() { s.UpdateRespawn(dt) })
+
+	// Crop Growth
+	s.runSystem("Farming", func() { s.FarmSystem.Update(dt) })
+
+	// Fishing Bites / Escapes
+	s.runSystem("Fishing", func() { s.FishingSystem.Update(dt) })
+
+	// Ground Item Timers (Ownership/Despawn)
+	s.runSystem("GroundItems", func() { s.GroundItemSystem.Update(dt) })
 
-	// Update Deads/Respawn
-	s.UpdateRespawn(0.033)
+	// Spawn Region Top-ups
+	s.runSystem("SpawnRegions", func() { s.SpawnRegionSystem.Update(dt) })
+
+	// Invasions / Rare Spawns
+	s.runSystem("WorldEvents", func() { s.WorldEventSystem.Update(dt) })
+
+	// Holiday Content (by date or admin override)
+	s.runSystem("Seasons", func() { s.SeasonalSystem.Update(dt) })
+
+	// Duel Countdowns / Ring / Time Limit
+	s.runSystem("Duels", func() { s.DuelSystem.Update(dt) })
+
+	// Arena Rounds
+	s.runSystem("Arena", func() { s.ArenaSystem.Update(dt) })
+
+	// House Doormats
+	s.runSystem("Housing", s.HousingSystem.Update)
+
+	// Spectator Cameras (after everything that moves entities)
+	s.runSystem("Spectators", func() { s.SpectatorSystem.Update() })
+
+	// Combat Flags (music)
+	s.runSystem("Aggro", func() { s.AggroSystem.Update(dt) })
+
+	// Cutscene Clocks
+	s.runSystem("Cutscenes", func() { s.CutsceneSystem.Update(dt) })
+
+	// Conversations (NPC died or player walked off)
+	s.runSystem("Dialogue", func() { s.DialogueSystem.Update() })
+
+	// Boss Loot Roll Timers
+	s.runSystem("Loot", func() { s.LootSystem.Update(dt) })
+
+	// Economy Config Hot-Reload
+	s.runSystem("Economy", func() { s.EconomySystem.Update(dt) })
+
+	// Click-to-move / Follow (Overrides player movement inputs)
+	s.runSystem("AutoMove", func() { s.AutoMoveSystem.Update(dt) })
 
 	// Move Players/NPCs via System
-	s.MovementSystem.Update(0.033)
+	s.runSystem("Movement", func() { s.MovementSystem.Update(dt) })
 
-	// Handle Attacks for ALL entities with Input (Players AND NPCs)
-	inputs := ecs.Query[components.InputComponent](s.World)
-	for _, id := range inputs {
-		s.HandleAttack(id)
+	// Zone Transitions ("Now entering ...")
+	s.runSystem("Zones", s.ZoneSystem.Update)
+
+	// Waypoint Discovery
+	s.runSystem("Waypoints", s.WaypointSystem.Update)
+
+	// Trigger Volumes (Popups, Traps, Teleports)
+	s.runSystem("Triggers", s.TriggerSystem.Update)
+
+	// Playtime / AFK Detection (and AFK kicks on a busy server)
+	s.runSystem("Playtime", func() { s.PlaytimeSystem.Update(dt) })
+	s.runSystem("AFK", s.kickAFK)
+	s.runSystem("Macros", func() {
+		for id, player := range s.Players {
+			if !s.PlaytimeSystem.IsAFK(id) {
+				s.MacroDetector.Active(player.Username, dt)
+			}
+		}
+	})
+
+	// Admit Queued Logins
+	s.runSystem("LoginQueue", s.updateLoginQueue)
+
+	// Scheduled Tasks (Backups, Snapshots, Event Rolls)
+	s.runSystem("Scheduler", s.SchedulerSystem.Update)
+
+	// Telemetry Export (opt-in)
+	s.runSystem("Telemetry", func() { s.TelemetrySystem.Update(dt) })
+
+	// Autosave (Only players whose components changed)
+	s.autosaveTimer += dt
+	if s.autosaveTimer >= config.AutosaveInterval {
+		s.autosaveTimer = 0
+		s.runSystem("Autosave", func() {
+			for id, player := range s.Players {
+				if _, err := s.PersistenceSystem.SavePlayerIfChanged(id, player.Username); err != nil {
+					log.Printf("Autosave failed for %s: %v", player.Username, err)
+				}
+			}
+			if s.FarmSystem.Dirty() {
+				s.saveCrops()
+			}
+			s.savePlaytimeLeaderboard()
+		})
+	}
+
+	// Scheduled Shutdown Warnings
+	s.updateShutdown(dt)
+
+	// NPC Checkpoints (Optional)
+	if s.PersistNPCs {
+		s.runSystem("NPCState", func() { s.NPCStateSystem.Update(dt) })
 	}
 
+	// Handle Attacks for ALL entities with Input (Players AND NPCs)
+	s.runSystem("Attacks", func() {
+		inputs := ecs.Query[components.InputComponent](s.World)
+		for _, id := range inputs {
+			s.HandleAttack(id)
+		}
+	})
+
 	for id, player := range s.Players {
 		if input, ok := ecs.GetComponent[components.InputComponent](s.World, id); ok {
 			player.PrevInput = *input
 		}
 	}
 
-	projectiles := ecs.Query[components.ProjectileComponent](s.World)
-	for _, pid := range projectiles {
-		s.UpdateProjectile(pid)
-	}
+	// Post-hit Immunity
+	s.runSystem("Combat", func() { s.CombatSystem.Update(dt) })
+	s.runSystem("Status Effects", func() { s.StatusSystem.Update(dt) })
+
+	// Training Dummy DPS Reports
+	s.runSystem("Dummies", func() { s.DummySystem.Update(dt) })
+
+	s.runSystem("Projectiles", func() {
+		projectiles := ecs.Query[components.ProjectileComponent](s.World)
+		for _, pid := range projectiles {
+			s.UpdateProjectile(pid)
+		}
+	})
+
+	// Item Audit Trail
+	s.runSystem("Audit", s.flushItemAudit)
 
-	s.World.Update(0.033)
+	s.World.Update(dt)
 }
 
 func (s *GameServer) HandleAttack(id ecs.Entity) {
@@ -772,6 +1651,9 @@ func (s *GameServer) HandleAttack(id ecs.Entity) {
 	if !weaponFound {
 		return
 	}
+	stats := systems.Stats(s.World, id)
+	damage = stats.AttackDamage(attackType, damage) * systems.DamageScale(s.World, id) // Attributes, then zone/level scaled NPCs
+	cooldown = stats.AttackCooldown(cooldown)
 
 	// 3. Use AttackComponent ONLY for LastAttackTime tracking
 	attackComp, _ := ecs.GetComponent[components.AttackComponent](s.World, id)
@@ -792,7 +1674,8 @@ func (s *GameServer) HandleAttack(id ecs.Entity) {
 
 	// Update Cooldown State
 	attackComp.LastAttackTime = now
-	s.World.AddComponent(id, *attackComp)
+	ecs.AddComponent(s.World, id, *attackComp)
+	systems.Reveal(s.World, id)
 
 	// 3. Spawn Projectile from Dynamic Center (Calculate once for all types)
 	// Default Size
@@ -805,10 +1688,15 @@ func (s *GameServer) HandleAttack(id ecs.Entity) {
 	startX := transform.X + width/2
 	startY := transform.Y + height/2
 
+	// Flood protection (cooldown is already spent, so macroed attacks just fizzle)
+	if !s.SpawnLimiter.AllowProjectile(id) {
+		return
+	}
+
 	if attackType == components.AttackTypeRanged {
 		proj := s.World.NewEntity()
 		// Direction from CENTER to Mouse
-		dirX, dirY := components.Direction(startX, startY, input.MouseX, input.MouseY)
+		dirX, dirY := geom.Direction(startX, startY, input.MouseX, input.MouseY)
 
 		speed := 10.0
 		lifetime := attackRange / speed
@@ -817,22 +1705,39 @@ func (s *GameServer) HandleAttack(id ecs.Entity) {
 		spawnX := startX + dirX*spawnDist
 		spawnY := startY + dirY*spawnDist
 
-		rot := math.Atan2(dirY, dirX) + math.Pi/4
-		s.World.AddComponent(proj, components.TransformComponent{X: spawnX, Y: spawnY, Rotation: rot})
-		s.World.AddComponent(proj, components.PhysicsComponent{VelX: dirX * speed, VelY: dirY * speed, Speed: speed})
-		s.World.AddComponent(proj, components.SpriteComponent{Width: 8, Height: 8, Color: color.RGBA{R: 255, G: 255, B: 0, A: 255}, Texture: "arrow"})
-		s.World.AddComponent(proj, components.ProjectileComponent{OwnerID: id, Damage: damage, Lifetime: lifetime})
+		rot := components.TextureRotation(components.TextureArrow, dirX, dirY)
+		ecs.AddComponent(s.World, proj, components.TransformComponent{X: spawnX, Y: spawnY, Z: transform.Z, Rotation: rot})
+		ecs.AddComponent(s.World, proj, components.PhysicsComponent{
+			VelX:  dirX * speed,
+			VelY:  dirY * speed,
+			Speed: speed,
+			Layer: components.LayerProjectile,
+			Mask:  components.MaskProjectile,
+			Shape: components.ShapeCircle,
+			Size:  10,
+		})
+		ecs.AddComponent(s.World, proj, components.SpriteComponent{Width: 8, Height: 8, Color: color.RGBA{R: 255, G: 255, B: 0, A: 255}, Texture: components.TextureArrow})
+		ecs.AddComponent(s.World, proj, components.ProjectileComponent{OwnerID: id, Faction: systems.FactionOf(s.World, id), Damage: damage, Lifetime: lifetime})
+		s.World.AddTags(proj, components.TagProjectile)
 
 	} else if attackType == components.AttackTypeMelee {
 		slash := s.World.NewEntity()
-		dirX, dirY := components.Direction(transform.X, transform.Y, input.MouseX, input.MouseY)
+		dirX, dirY := geom.Direction(transform.X, transform.Y, input.MouseX, input.MouseY)
 		offsetX := dirX * 30
 		offsetY := dirY * 30
 
-		rot := math.Atan2(dirY, dirX)
-		s.World.AddComponent(slash, components.TransformComponent{X: transform.X + offsetX, Y: transform.Y + offsetY, Rotation: rot})
-		s.World.AddComponent(slash, components.SpriteComponent{Width: 40, Height: 40, Color: color.RGBA{R: 255, G: 0, B: 0, A: 255}})
-		s.World.AddComponent(slash, components.ProjectileComponent{OwnerID: id, Damage: damage, Lifetime: 15}) // Melee slash duration in ticks
+		rot := components.TextureRotation(components.TextureSlash, dirX, dirY)
+		ecs.AddComponent(s.World, slash, components.TransformComponent{X: transform.X + offsetX, Y: transform.Y + offsetY, Z: transform.Z, Rotation: rot})
+		ecs.AddComponent(s.World, slash, components.SpriteComponent{Width: 40, Height: 40, Color: color.RGBA{R: 255, G: 0, B: 0, A: 255}, Texture: components.TextureSlash})
+		ecs.AddComponent(s.World, slash, components.ProjectileComponent{
+			OwnerID:  id,
+			Faction:  systems.FactionOf(s.World, id),
+			Damage:   damage,
+			Lifetime: 15, // Melee slash duration in ticks
+			Pierce:   true,
+			HitList:  make(map[ecs.Entity]bool),
+		})
+		s.World.AddTags(slash, components.TagProjectile)
 	}
 }
 
@@ -856,89 +1761,86 @@ func (s *GameServer) UpdateProjectile(pid ecs.Entity) {
 		return
 	}
 
-	s.World.AddComponent(pid, *transform)
-	s.World.AddComponent(pid, *proj)
+	ecs.AddComponent(s.World, pid, *transform)
+	ecs.AddComponent(s.World, pid, *proj)
 
 	// terrain Collision (Projectiles)
 	// Check center of projectile
 	cx := transform.X + 4
 	cy := transform.Y + 4
-	tx := int(cx / 32.0)
-	ty := int(cy / 32.0)
+	tx := int(math.Floor(cx / float64(config.TileSize)))
+	ty := int(math.Floor(cy / float64(config.TileSize)))
 
 	// Projectile Z
 	z := transform.Z
 	if m, ok := s.Maps[z]; ok {
 		if tx >= 0 && tx < m.Width && ty >= 0 && ty < m.Height {
 			tile := m.Tiles[ty][tx]
-			if tile.Type == world.TileTree || m.Objects[ty][tx] > 0 {
+			if tile.Type.BlocksProjectiles() || world.IsSolidObject(m.Objects[ty][tx]) {
 				// Tree/Object is solid -> Block
+				s.broadcastHit(pid, 0, z, cx, cy)
 				s.World.RemoveEntity(pid)
 				return
 			}
-			// If Water, we DO NOT destroy.
 		}
 	}
 
-	// Collision Detection
-	// Simple O(N) check against all entities with Stats (Health)
-	targets := ecs.Query[components.StatsComponent](s.World)
-	projRect := struct{ X, Y, W, H float64 }{transform.X, transform.Y, 10, 10}
-	// Assuming projectile size for collision
+	// Projectile collider (melee slashes have no physics body)
+	projShape, projSize, projMask := components.ShapeAABB, 10.0, components.MaskProjectile
+	if phys != nil && phys.Size > 0 {
+		projShape, projSize, projMask = phys.Shape, phys.Size, phys.Mask
+	}
+
+	// Collision Detection against the bodies around the projectile
+	targets := systems.Nearby[components.StatsComponent](s.World, s.MovementSystem.Grid, z, transform.X, transform.Y, projSize, projSize)
 
 	for _, tid := range targets {
 		if tid == proj.OwnerID {
 			continue // Don't hit yourself
 		}
+		if systems.IsSpectating(s.World, tid) {
+			continue // Spectators can't be hit
+		}
+		if !s.CombatSystem.CanDamage(proj.Faction, tid) && !s.DuelSystem.IsDueling(proj.OwnerID, tid) && !s.ArenaSystem.AreEnemies(proj.OwnerID, tid) {
+			continue // Allies (friendly fire off / not a PvP zone / not dueling / arena teammates)
+		}
+		if proj.HitList[tid] {
+			continue // Already hit by this sweep
+		}
 
 		targetStats, _ := ecs.GetComponent[components.StatsComponent](s.World, tid)
 		targetTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, tid)
-		targetSprite, _ := ecs.GetComponent[components.SpriteComponent](s.World, tid)
+		targetPhys, _ := ecs.GetComponent[components.PhysicsComponent](s.World, tid)
 
-		if targetTrans == nil || targetSprite == nil {
+		if targetStats == nil || targetTrans == nil || targetPhys == nil || targetTrans.Z != transform.Z || projMask&targetPhys.Layer == 0 {
 			continue
 		}
 
-		// AABB Check
-		if s.rectOverlap(projRect.X, projRect.Y, projRect.W, projRect.H,
-			targetTrans.X, targetTrans.Y, targetSprite.Width, targetSprite.Height) {
+		tx, ty, tSize := components.ColliderBounds(targetTrans.X, targetTrans.Y, targetPhys, config.TileSize)
+		if components.CollidersOverlap(transform.X, transform.Y, projSize, projShape, tx, ty, tSize, targetPhys.Shape) {
 
-			// HIT!
-			targetStats.CurrentHealth -= proj.Damage
-			if targetStats.CurrentHealth < 0 {
-				targetStats.CurrentHealth = 0 // Clamp Health
+			// Immunity frames: pass through
+			if targetStats.InvulnTimer > 0 {
+				continue
 			}
-			s.World.AddComponent(tid, *targetStats)
-
-			log.Printf("Entity %d hit Entity %d for %.1f damage (HP: %.1f)", proj.OwnerID, tid, proj.Damage, targetStats.CurrentHealth)
-
-			// Check Death
-			if targetStats.CurrentHealth <= 0 {
-				if respawn, ok := ecs.GetComponent[components.RespawnComponent](s.World, tid); ok {
-					respawn.IsDead = true
-					respawn.RespawnTimer = 30.0
-					s.World.AddComponent(tid, *respawn)
-
-					// Despawn (Remove components)
-					s.World.RemoveComponent(tid, components.SpriteComponent{})
-					s.World.RemoveComponent(tid, components.PhysicsComponent{})
-					s.World.RemoveComponent(tid, components.AIComponent{})
-					s.World.RemoveComponent(tid, components.InputComponent{})
-					s.World.RemoveComponent(tid, components.StatsComponent{})
-					s.World.RemoveComponent(tid, components.TransformComponent{})
-
-					log.Printf("Entity %d died. Respawning in 30s.", tid)
-				}
+
+			s.broadcastHit(pid, tid, transform.Z, transform.X+projSize/2, transform.Y+projSize/2)
+			if s.DummySystem.RecordHit(tid, proj.OwnerID, proj.Damage) {
+				// Training Dummy: record instead of taking damage
+				targetStats.InvulnTimer = systems.HitInvulnTime
+				ecs.AddComponent(s.World, tid, *targetStats)
 			} else {
-				// Aggro Logic: If victim is alive and NPC, set target to attacker
-				if ai, ok := ecs.GetComponent[components.AIComponent](s.World, tid); ok {
-					if ai.TargetID == 0 {
-						ai.TargetID = proj.OwnerID
-						ai.State = "chase"
-						s.World.AddComponent(tid, *ai)
-						log.Printf("Entity %d is now chasing Entity %d", tid, proj.OwnerID)
-					}
+				s.resolveHit(tid, targetStats, proj)
+			}
+
+			if proj.Pierce {
+				// Sweeps carry on but hit each target once
+				if proj.HitList == nil {
+					proj.HitList = make(map[ecs.Entity]bool)
 				}
+				proj.HitList[tid] = true
+				ecs.AddComponent(s.World, pid, *proj)
+				continue
 			}
 
 			// Destroy Projectile
@@ -948,71 +1850,292 @@ func (s *GameServer) UpdateProjectile(pid ecs.Entity) {
 	}
 }
 
-func (s *GameServer) rectOverlap(x1, y1, w1, h1, x2, y2, w2, h2 float64) bool {
-	return x1 < x2+w2 && x1+w1 > x2 && y1 < y2+h2 && y1+h1 > y2
+// resolveHit applies projectile damage and on-hit statuses to a target
+func (s *GameServer) resolveHit(tid ecs.Entity, targetStats *components.StatsComponent, proj *components.ProjectileComponent) {
+	for _, status := range proj.OnHit {
+		systems.AddStatusEffect(s.World, tid, status)
+	}
+	s.dealDamage(proj.OwnerID, tid, targetStats, proj.Damage, systems.HitInvulnTime)
+}
+
+// burn deals a tick of burn damage (StatusEffectSystem.OnBurn). Burns don't grant
+// immunity to the hits in between.
+func (s *GameServer) burn(caster, target ecs.Entity, damage float64) {
+	stats, _ := ecs.GetComponent[components.StatsComponent](s.World, target)
+	if stats == nil || stats.CurrentHealth <= 0 || s.DummySystem.RecordHit(target, caster, damage) {
+		return
+	}
+	s.dealDamage(caster, target, stats, damage, stats.InvulnTimer)
+}
+
+// dealDamage hurts a target on behalf of an attacker and publishes the DamageEvent (and
+// DeathEvent) the rest of combat reacts to. The target is then immune to hits for
+// invuln seconds.
+func (s *GameServer) dealDamage(attacker, tid ecs.Entity, targetStats *components.StatsComponent, amount, invuln float64) {
+	if targetStats.CurrentHealth <= 0 {
+		return // Already dead, waiting for its DeathEvent to be handled
+	}
+	damage := systems.IncomingDamage(s.World, tid, amount)
+	dealt := math.Min(damage, targetStats.CurrentHealth)
+	targetStats.CurrentHealth -= damage
+	if targetStats.CurrentHealth < 0 {
+		targetStats.CurrentHealth = 0 // Clamp Health
+	}
+	targetStats.InvulnTimer = invuln
+
+	// Duels and arena rounds end at 1 HP instead of killing
+	duelDefeat := targetStats.CurrentHealth < 1 && s.DuelSystem.IsDueling(attacker, tid)
+	arenaKnockOut := targetStats.CurrentHealth < 1 && s.ArenaSystem.AreEnemies(attacker, tid)
+	if duelDefeat || arenaKnockOut {
+		targetStats.CurrentHealth = 1
+	}
+	ecs.AddComponent(s.World, tid, *targetStats)
+	if duelDefeat {
+		s.DuelSystem.Defeat(tid)
+	}
+	if arenaKnockOut {
+		s.ArenaSystem.KnockOut(tid)
+	}
+
+	log.Printf("%s hit %s for %.1f damage (HP: %.1f)", s.entityLabel(attacker), s.entityLabel(tid), damage, targetStats.CurrentHealth)
+
+	// Aggro, drops, XP and respawns react to these (see combat.go)
+	killed := targetStats.CurrentHealth <= 0
+	ecs.Publish(s.World, components.DamageEvent{Attacker: attacker, Target: tid, Amount: damage, Dealt: dealt, Killed: killed})
+	if killed {
+		ecs.Publish(s.World, components.DeathEvent{Killer: attacker, Victim: tid})
+	}
+}
+
+// dropGold rolls the economy's gold drop for a dying NPC and leaves it on the ground for the killer
+func (s *GameServer) dropGold(tid, killer ecs.Entity) {
+	charID := s.spawnedCharID(tid)
+	if charID == "" {
+		return
+	}
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, tid)
+	if !ok {
+		return
+	}
+	amount := int(math.Round(float64(s.EconomySystem.RollGoldDrop(charID)) * s.rewardScale(killer, tid)))
+	if amount <= 0 {
+		return
+	}
+	if _, err := s.GroundItemSystem.Spawn(trans.X, trans.Y, trans.Z, "coin_gold", amount, killer); err != nil {
+		log.Printf("Gold drop for Entity %d failed: %v", tid, err)
+	}
+}
+
+// dropItems rolls a dying NPC's common drop table and leaves the drops for the killer
+func (s *GameServer) dropItems(tid, killer ecs.Entity) {
+	charID := s.spawnedCharID(tid)
+	if charID == "" {
+		return
+	}
+	def, _ := characters.Get(charID)
+	drops := append(def.Drops[:len(def.Drops):len(def.Drops)], s.SeasonalSystem.Drops(charID)...)
+	if len(drops) == 0 {
+		return
+	}
+	if trans, ok := ecs.GetComponent[components.TransformComponent](s.World, tid); ok {
+		drops = systems.ThinDrops(drops, s.rewardScale(killer, tid))
+		s.LootSystem.DropTable(drops, trans.X+systems.GroundItemSize, trans.Y, trans.Z, killer)
+	}
+}
+
+// spawnedCharID returns the character an NPC was spawned as by a spawner or spawn
+// region ("" for anything else, e.g. world event spawns, which drop nothing)
+func (s *GameServer) spawnedCharID(id ecs.Entity) string {
+	if respawn, ok := ecs.GetComponent[components.RespawnComponent](s.World, id); ok {
+		return respawn.CharID
+	}
+	if region, ok := ecs.GetComponent[components.SpawnRegionComponent](s.World, id); ok {
+		return region.CharID
+	}
+	return ""
+}
+
+// entityLabel formats an entity for logs, e.g. "Guard#12"
+func (s *GameServer) entityLabel(id ecs.Entity) string {
+	if name, ok := ecs.GetComponent[components.NameComponent](s.World, id); ok && name.Name != "" {
+		return fmt.Sprintf("%s#%d", name.Name, id)
+	}
+	return fmt.Sprintf("Entity#%d", id)
 }
 
+// BroadcastState sends every player their updates. It takes the write lock: preparing
+// them records what each player has been sent (see NetworkSystem.PrepareUpdatesFor).
 func (s *GameServer) BroadcastState() {
-	s.Mutex.RLock()
-	defer s.Mutex.RUnlock()
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
 
-	packet := s.NetworkSystem.PrepareStateUpdate()
+	for id, p := range s.Players {
+		for _, packet := range s.NetworkSystem.PrepareUpdatesFor(id) {
+			p.Send(packet)
+		}
+	}
+}
 
-	for _, p := range s.Players {
-		go func(player *Player) {
-			if err := player.Encoder.Encode(packet); err != nil {
-				// handled
-			}
-		}(p)
+// Announce sends a server-wide banner message to all players. Assumes s.Mutex is LOCKED.
+func (s *GameServer) Announce(msg string) {
+	log.Printf("Announcement: %s", msg)
+	packet := protocol.Packet{
+		Type: protocol.PacketAnnouncement,
+		Data: protocol.AnnouncementPacket{Message: msg},
+	}
+	for _, player := range s.Players {
+		player.Send(packet)
+	}
+}
+
+// AnnounceZone sends a banner to players in the zone around a point, or to everyone on
+// the level when the point lies in unnamed wilderness. Assumes s.Mutex is LOCKED.
+func (s *GameServer) AnnounceZone(level int, x, y float64, msg string) {
+	m, ok := s.Maps[level]
+	if !ok {
+		return
+	}
+	zoneID := ""
+	if zone := m.ZoneAt(x, y); zone != nil {
+		zoneID = zone.ID
+	}
+	log.Printf("Zone announcement (%d/%s): %s", level, zoneID, msg)
+	for id, player := range s.Players {
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if trans == nil || trans.Z != level {
+			continue
+		}
+		if zc, ok := ecs.GetComponent[components.ZoneComponent](s.World, id); zoneID != "" && (!ok || zc.ZoneID != zoneID) {
+			continue
+		}
+		s.Notify(player, msg)
+	}
+}
+
+// handleTriggerEnter runs a trigger's action for a player. Assumes s.Mutex is LOCKED.
+func (s *GameServer) handleTriggerEnter(id ecs.Entity, trigger *components.TriggerComponent) {
+	player, ok := s.Players[id]
+	if !ok {
+		return
+	}
+
+	switch trigger.Action {
+	case "damage":
+		if !s.CombatSystem.ApplyDamage(id, trigger.Damage) {
+			return
+		}
+		if stats, ok := ecs.GetComponent[components.StatsComponent](s.World, id); ok && stats.CurrentHealth <= 0 {
+			ecs.Publish(s.World, components.DeathEvent{Victim: id})
+		}
+	case "teleport":
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if trans == nil {
+			return
+		}
+		trans.X, trans.Y, trans.Z = trigger.TargetX, trigger.TargetY, trigger.TargetZ
+		ecs.AddComponent(s.World, id, *trans)
+		s.AutoMoveSystem.Stop(id)
+	case "house":
+		s.AutoMoveSystem.Stop(id)
+		s.enterHouseDoor(player, trigger)
+	case "cutscene":
+		if err := s.CutsceneSystem.Play(id, trigger.Cutscene, 0); err != nil {
+			log.Printf("Entity %d cutscene %s: %v", id, trigger.Cutscene, err)
+		}
+	}
+
+	if trigger.Message != "" {
+		s.Notify(player, trigger.Message)
+	}
+}
+
+// Notify shows a banner message to a single player
+func (s *GameServer) Notify(player *Player, msg string) {
+	packet := protocol.Packet{
+		Type: protocol.PacketAnnouncement,
+		Data: protocol.AnnouncementPacket{Message: msg},
+	}
+	player.Send(packet)
+}
+
+// SendZoneChange notifies a player that they crossed into another zone (nil = wilderness).
+// Assumes the caller holds s.Mutex.
+func (s *GameServer) SendZoneChange(id ecs.Entity, zone *world.Zone) {
+	player, ok := s.Players[id]
+	if !ok {
+		return
+	}
+
+	data := protocol.ZoneChangePacket{}
+	if zone != nil {
+		data = protocol.ZoneChangePacket{
+			ZoneID:   zone.ID,
+			Name:     zone.Name,
+			MinLevel: zone.MinLevel,
+			MaxLevel: zone.MaxLevel,
+			PvP:      zone.PvP,
+			Music:    zone.Music,
+		}
 	}
+	packet := protocol.Packet{Type: protocol.PacketZoneChange, Data: data}
+	player.Send(packet)
 }
 
+// SendInventorySync sends the player's inventory. Assumes s.Mutex is LOCKED.
 func (s *GameServer) SendInventorySync(player *Player) {
-	s.Mutex.RLock()
 	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, player.EntityID)
-	s.Mutex.RUnlock()
-
 	if inv == nil {
 		return
 	}
 
 	syncSlots := make([]struct {
-		Index    int
-		ItemID   string
-		Quantity int
+		Index      int
+		ItemID     string
+		Quantity   int
+		Locked     bool
+		InstanceID string
 	}, 0)
 	for i, slot := range inv.Slots {
 		if slot.ItemID != "" && slot.Quantity > 0 {
 			syncSlots = append(syncSlots, struct {
-				Index    int
-				ItemID   string
-				Quantity int
+				Index      int
+				ItemID     string
+				Quantity   int
+				Locked     bool
+				InstanceID string
 			}{
-				Index:    i,
-				ItemID:   slot.ItemID,
-				Quantity: slot.Quantity,
+				Index:      i,
+				ItemID:     slot.ItemID,
+				Quantity:   slot.Quantity,
+				Locked:     slot.Locked,
+				InstanceID: slot.InstanceID,
 			})
 		}
 	}
 
+	var keyring []protocol.KeyringSlot
+	if kr, ok := ecs.GetComponent[components.KeyringComponent](s.World, player.EntityID); ok {
+		for _, slot := range kr.Items {
+			keyring = append(keyring, protocol.KeyringSlot{ItemID: slot.ItemID, Quantity: slot.Quantity, InstanceID: slot.InstanceID})
+		}
+	}
+
 	packet := protocol.Packet{
 		Type: protocol.PacketInventorySync,
 		Data: protocol.InventorySyncPacket{
 			Slots:    syncSlots,
 			Capacity: inv.Capacity,
+			Gold:     systems.Gold(s.World, player.EntityID),
+			Keyring:  keyring,
 		},
 	}
 
-	if err := player.Encoder.Encode(packet); err != nil {
-		log.Printf("Failed to send inventory sync: %v", err)
-	}
+	player.Send(packet)
 }
 
+// SendHotbarSync sends the player's hotbar. Assumes s.Mutex is LOCKED.
 func (s *GameServer) SendHotbarSync(player *Player) {
-	s.Mutex.RLock()
 	hb, _ := ecs.GetComponent[components.HotbarComponent](s.World, player.EntityID)
-	s.Mutex.RUnlock()
-
 	if hb == nil {
 		return
 	}
@@ -1033,16 +2156,12 @@ func (s *GameServer) SendHotbarSync(player *Player) {
 		Data: syncPacket,
 	}
 
-	if err := player.Encoder.Encode(packet); err != nil {
-		log.Printf("Failed to send hotbar sync: %v", err)
-	}
+	player.Send(packet)
 }
 
+// SendEquipmentSync sends the player's equipment. Assumes s.Mutex is LOCKED.
 func (s *GameServer) SendEquipmentSync(player *Player) {
-	s.Mutex.RLock()
 	equip, _ := ecs.GetComponent[components.EquipmentComponent](s.World, player.EntityID)
-	s.Mutex.RUnlock()
-
 	if equip == nil {
 		return
 	}
@@ -1050,6 +2169,7 @@ func (s *GameServer) SendEquipmentSync(player *Player) {
 	var syncPacket protocol.EquipmentSyncPacket
 	for i, slot := range equip.Slots {
 		syncPacket.Slots[i].ItemID = slot.ItemID
+		syncPacket.Slots[i].InstanceID = slot.InstanceID
 	}
 
 	packet := protocol.Packet{
@@ -1057,142 +2177,60 @@ func (s *GameServer) SendEquipmentSync(player *Player) {
 		Data: syncPacket,
 	}
 
-	if err := player.Encoder.Encode(packet); err != nil {
-		log.Printf("Failed to send equipment sync: %v", err)
-	}
+	player.Send(packet)
 }
 
 // equipItemInternal performs the actual equip logic. Assumes s.Mutex is LOCKED.
-func (s *GameServer) equipItemInternal(id ecs.Entity, invSlot int, equipSlot int, player *Player) {
-	equip, _ := ecs.GetComponent[components.EquipmentComponent](s.World, id)
-	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
-
-	if equip == nil || inv == nil {
-		return
-	}
-
-	// Verify Inventory Slot
-	if invSlot < 0 || invSlot >= len(inv.Slots) {
-		return
-	}
-	itemID := inv.Slots[invSlot].ItemID
-	if itemID == "" {
-		return
-	}
-
-	// Verify Item Type and Target Slot
-	def, ok := items.Get(itemID)
-	if !ok || def.EquipmentSlot == -1 {
-		log.Printf("Player %s tried to equip non-equippable item %s", player.Username, itemID)
-		return
-	}
-	if def.EquipmentSlot != equipSlot {
-		log.Printf("Player %s tried to equip %s to wrong slot %d (expected %d)", player.Username, itemID, equipSlot, def.EquipmentSlot)
-		return
-	}
-
-	// Perform Swap
-	// 1. Take from Inventory (assuming equipment items stack to 1 generally, but handle quantity)
-	inv.Slots[invSlot].Quantity--
-	if inv.Slots[invSlot].Quantity <= 0 {
-		inv.Slots[invSlot].ItemID = ""
-		inv.Slots[invSlot].Quantity = 0
-	}
-
-	// 2. Check if Equipment Slot has item (Swap)
-	oldItem := equip.Slots[equipSlot].ItemID
-	equip.Slots[equipSlot].ItemID = itemID
-
-	// 3. Return old item to inventory
-	if oldItem != "" {
-		if inv.Slots[invSlot].ItemID == "" {
-			inv.Slots[invSlot].ItemID = oldItem
-			inv.Slots[invSlot].Quantity = 1
-		} else {
-			err := items.AddItem(inv, oldItem, 1)
-			if err != nil {
-				// Revert
-				equip.Slots[equipSlot].ItemID = oldItem
-				items.AddItem(inv, itemID, 1)
-				log.Printf("Inventory full, could not unequip old item %s", oldItem)
-				return
-			}
-		}
-	}
-
-	log.Printf("Player %s equipped %s to slot %d", player.Username, itemID, equipSlot)
-
-	// Save components explicitly!
-	s.World.AddComponent(id, *equip)
-	s.World.AddComponent(id, *inv)
-
-	go s.SendInventorySync(player)
-	go s.SendEquipmentSync(player)
-}
-
 // toggleEquipItem toggles an item between equipped and inventory states. Assumes s.Mutex is LOCKED.
 func (s *GameServer) toggleEquipItem(id ecs.Entity, itemID string, player *Player) {
-	equip, _ := ecs.GetComponent[components.EquipmentComponent](s.World, id)
-	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
-
-	if equip == nil || inv == nil {
-		return
-	}
-
-	// 1. Check if already equipped
-	foundSlot := -1
-	for i, slot := range equip.Slots {
-		if slot.ItemID == itemID {
-			foundSlot = i
-			break
-		}
+	changes, err := s.Service.ToggleEquip(id, itemID)
+	if err != nil {
+		log.Printf("Player %s failed to toggle %s via hotbar: %v", player.Username, itemID, err)
 	}
+	s.applyChanges(player, changes)
+}
 
-	if foundSlot != -1 {
-		// ALREADY EQUIPPED -> UNEQUIP
-		// Try to add back to inventory
-		err := items.AddItem(inv, itemID, 1)
-		if err == nil {
-			equip.Slots[foundSlot].ItemID = ""
-			log.Printf("Player %s unequipped %s via hotbar", player.Username, itemID)
-		} else {
-			log.Printf("Player %s failed to unequip %s via hotbar: Inventory full", player.Username, itemID)
-		}
+// useItem uses a consumable from the hotbar. Assumes s.Mutex is LOCKED.
+func (s *GameServer) useItem(player *Player, itemID string) {
+	changes, err := s.Service.UseItemByID(player.EntityID, itemID)
+	if err != nil {
+		log.Printf("Player %s failed to use %s via hotbar: %v", player.Username, itemID, err)
+		s.notifyItemUse(player, itemID, err)
 	} else {
-		// NOT EQUIPPED -> EQUIP
-		// Find in inventory
-		invSlot := -1
-		for i, slot := range inv.Slots {
-			if slot.ItemID == itemID {
-				invSlot = i
-				break
-			}
-		}
-
-		if invSlot != -1 {
-			def, ok := items.Get(itemID)
-			if ok && def.EquipmentSlot != -1 {
-				s.equipItemInternal(id, invSlot, def.EquipmentSlot, player)
-			}
-		} else {
-			log.Printf("Player %s tried to hotbar equip %s but it's not in inventory", player.Username, itemID)
-		}
+		s.TelemetrySystem.RecordItem(itemID)
 	}
+	s.applyChanges(player, changes)
+}
 
-	// Sync happens inside unequip logic or equipItemInternal
-	// But for unequip we need to sync manually here if we changed it.
-	if foundSlot != -1 {
-		s.World.AddComponent(id, *equip)
-		s.World.AddComponent(id, *inv)
-		go s.SendInventorySync(player)
-		go s.SendEquipmentSync(player)
+// notifyItemUse tells the player why a consumable didn't work, for the reasons they can act on
+func (s *GameServer) notifyItemUse(player *Player, itemID string, err error) {
+	name := itemID
+	if def, ok := items.Get(itemID); ok {
+		name = def.Name
+	}
+	switch {
+	case errors.Is(err, service.ErrItemOnCooldown):
+		left := s.Service.ItemCooldownLeft(player.EntityID, itemID)
+		s.Notify(player, fmt.Sprintf("%s is not ready yet (%.0fs)", name, math.Ceil(left)))
+	case errors.Is(err, service.ErrFullHealth):
+		s.Notify(player, "You are already at full health")
+	case errors.Is(err, service.ErrNotInInventory):
+		s.Notify(player, "You have no "+name+" left")
 	}
 }
 
+// SendMapSync sends the map of the player's level. Assumes s.Mutex is LOCKED.
 func (s *GameServer) SendMapSync(player *Player) {
-	// Determine which map to send
-	// For now, assume player is on Level 0 if not set, or fetch from Transform
-	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, player.EntityID)
+	packet := s.mapSyncPacket(player.EntityID)
+	if packet.Data == nil {
+		return // No map to sync?
+	}
+	player.Send(packet)
+}
+
+// mapSyncPacket flattens the map of the level the entity is on (Data is nil if there is none)
+func (s *GameServer) mapSyncPacket(id ecs.Entity) protocol.Packet {
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
 	z := 0
 	if trans != nil {
 		z = trans.Z
@@ -1200,7 +2238,7 @@ func (s *GameServer) SendMapSync(player *Player) {
 
 	gameMap, ok := s.Maps[z]
 	if !ok {
-		return // No map to sync?
+		return protocol.Packet{Type: protocol.PacketMapSync}
 	}
 
 	// Flatten Tiles and Objects
@@ -1213,7 +2251,7 @@ func (s *GameServer) SendMapSync(player *Player) {
 		}
 	}
 
-	packet := protocol.Packet{
+	return protocol.Packet{
 		Type: protocol.PacketMapSync,
 		Data: protocol.MapSyncPacket{
 			Level:   z,
@@ -1223,104 +2261,76 @@ func (s *GameServer) SendMapSync(player *Player) {
 			Objects: objects,
 		},
 	}
-	player.Encoder.Encode(packet)
 }
 
+// handleSpellCast casts a spell for any caster (players and NPCs). Assumes s.Mutex is LOCKED.
 func (s *GameServer) handleSpellCast(id ecs.Entity, spellID string, targetX, targetY float64) {
-	// Verify Unlock
-	spellbook, _ := ecs.GetComponent[components.SpellbookComponent](s.World, id)
-	if spellbook == nil {
-		return
-	}
-
-	unlocked := false
-	if spellbook.UnlockedSpells != nil {
-		for _, s := range spellbook.UnlockedSpells {
-			if s == spellID {
-				unlocked = true
-				break
-			}
+	changes, err := s.Service.CastSpell(id, spellID, targetX, targetY)
+	if err != nil {
+		if err != service.ErrOnCooldown {
+			log.Printf("%s failed to cast %s: %v", s.entityLabel(id), spellID, err)
 		}
-	}
-	if !unlocked {
 		return
 	}
-
-	// Verify Cooldown
-	if spellbook.Cooldowns == nil {
-		spellbook.Cooldowns = make(map[string]float64)
+	if player, ok := s.Players[id]; ok {
+		s.TelemetrySystem.RecordSpell(spellID)
+		s.applyChanges(player, changes)
 	}
+}
 
-	now := float64(time.Now().UnixMilli()) / 1000.0
-	lastCast := spellbook.Cooldowns[spellID]
+// SendLootRoll offers a player a need/greed roll. Assumes s.Mutex is LOCKED.
+func (s *GameServer) SendLootRoll(player *Player, roll *systems.LootRoll) {
+	packet := protocol.Packet{
+		Type: protocol.PacketLootRoll,
+		Data: protocol.LootRollPacket{
+			RollID:   roll.ID,
+			ItemID:   roll.ItemID,
+			ItemName: roll.ItemName(),
+			Quantity: roll.Quantity,
+			Duration: roll.TimeLeft,
+		},
+	}
+	player.Send(packet)
+}
 
-	spellDef, exists := components.SpellRegistry[spellID]
-	if !exists {
+// SendWaypointSync sends the waypoints the player discovered. Assumes s.Mutex is LOCKED.
+func (s *GameServer) SendWaypointSync(player *Player) {
+	travel, _ := ecs.GetComponent[components.TravelComponent](s.World, player.EntityID)
+	if travel == nil {
 		return
 	}
 
-	if now-lastCast < spellDef.Cooldown {
-		return // On Cooldown
+	data := protocol.WaypointSyncPacket{Fee: s.WaypointSystem.Fee()}
+	for _, m := range s.Maps {
+		for _, wp := range m.Waypoints {
+			for _, id := range travel.UnlockedWaypoints {
+				if id == wp.ID {
+					data.Waypoints = append(data.Waypoints, struct {
+						ID   string
+						Name string
+					}{wp.ID, wp.Name})
+				}
+			}
+		}
 	}
 
-	// Cast Spell
-	spellbook.Cooldowns[spellID] = now
-	s.World.AddComponent(id, *spellbook)
-
-	// Notify Client of Cooldown (Sync)
-	if player, ok := s.Players[id]; ok {
-		go s.SendSpellbookSync(player)
+	packet := protocol.Packet{
+		Type: protocol.PacketWaypointSync,
+		Data: data,
 	}
+	player.Send(packet)
+}
 
-	// Logic
-	transform, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
-	if transform == nil {
-		return
+// sendMoveCorrection tells a player's client where the server really has them after
+// rejecting part of their movement. Assumes s.Mutex is LOCKED.
+func (s *GameServer) sendMoveCorrection(id ecs.Entity, correction protocol.MoveCorrectionPacket) {
+	if player, ok := s.Players[id]; ok {
+		player.Send(protocol.Packet{Type: protocol.PacketMoveCorrection, Data: correction})
 	}
-
-	if spellID == "fireball" {
-		// Projectile
-		proj := s.World.NewEntity()
-		dirX, dirY := components.Direction(transform.X, transform.Y, targetX, targetY)
-		speed := 12.0
-		damage := 25.0
-		lifetime := 60.0 // 2 seconds (30 TPS)
-
-		spawnDist := 20.0
-		spawnX := transform.X + dirX*spawnDist
-		spawnY := transform.Y + dirY*spawnDist
-
-		rot := math.Atan2(dirY, dirX) + math.Pi/4
-		s.World.AddComponent(proj, components.TransformComponent{X: spawnX, Y: spawnY, Rotation: rot})
-		s.World.AddComponent(proj, components.PhysicsComponent{VelX: dirX * speed, VelY: dirY * speed, Speed: speed})
-		s.World.AddComponent(proj, components.SpriteComponent{Width: 12, Height: 12, Color: spellDef.Color, Texture: "fireball"})
-		s.World.AddComponent(proj, components.ProjectileComponent{OwnerID: id, Damage: damage, Lifetime: lifetime})
-
-	} else if spellID == "heal" {
-		stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id)
-		if stats != nil {
-			stats.CurrentHealth += 20
-			if stats.CurrentHealth > stats.MaxHealth {
-				stats.CurrentHealth = stats.MaxHealth
-			}
-			s.World.AddComponent(id, *stats)
-			log.Printf("Entity %d healed. HP: %.1f", id, stats.CurrentHealth)
-		}
-	} else if spellID == "blink" {
-		dirX, dirY := components.Direction(transform.X, transform.Y, targetX, targetY)
-		dist := 100.0
-		// Should check collision?
-		transform.X += dirX * dist
-		transform.Y += dirY * dist
-		s.World.AddComponent(id, *transform)
-	}
-	// Add other spells...
 }
 
+// SendSpellbookSync sends the player's spells and cooldowns. Assumes s.Mutex is LOCKED.
 func (s *GameServer) SendSpellbookSync(player *Player) {
-	s.Mutex.RLock()
-	defer s.Mutex.RUnlock()
-
 	sb, _ := ecs.GetComponent[components.SpellbookComponent](s.World, player.EntityID)
 	if sb == nil {
 		return
@@ -1329,9 +2339,10 @@ func (s *GameServer) SendSpellbookSync(player *Player) {
 	packet := protocol.Packet{
 		Type: protocol.PacketSpellbookSync,
 		Data: protocol.SpellbookSyncPacket{
-			UnlockedSpells: sb.UnlockedSpells,
-			Cooldowns:      sb.Cooldowns,
+			UnlockedSpells:    sb.UnlockedSpells,
+			Cooldowns:         sb.Cooldowns,
+			CooldownReduction: systems.Stats(s.World, player.EntityID).CooldownReduction,
 		},
 	}
-	player.Encoder.Encode(packet)
+	player.Send(packet)
 }
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"henry/pkg/items"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"math"
+)
+
+// UseItem uses one consumable from an inventory slot: heals, applies its buff and starts
+// its cooldown. Health and buffs reach the client in snapshots; the changes cover the
+// inventory.
+func (s *GameService) UseItem(id ecs.Entity, slotIndex int) (Changes, error) {
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id)
+	if inv == nil || stats == nil {
+		return Changes{}, ErrNoComponent
+	}
+	if slotIndex < 0 || slotIndex >= len(inv.Slots) {
+		return Changes{}, ErrInvalidSlot
+	}
+	slot := inv.Slots[slotIndex]
+	if slot.ItemID == "" || slot.Quantity <= 0 {
+		return Changes{}, ErrEmptySlot
+	}
+	def, ok := items.Get(slot.ItemID)
+	if !ok || def.Consumable == nil {
+		return Changes{}, ErrNotConsumable
+	}
+	effect := def.Consumable
+
+	cooldowns, _ := ecs.GetComponent[components.ItemCooldownComponent](s.World, id)
+	if cooldowns == nil {
+		cooldowns = &components.ItemCooldownComponent{}
+	}
+	if cooldowns.LastUse == nil {
+		cooldowns.LastUse = make(map[string]float64)
+	}
+	now := s.Now()
+	if lastUse, used := cooldowns.LastUse[slot.ItemID]; used && now-lastUse < effect.Cooldown {
+		return Changes{}, ErrItemOnCooldown
+	}
+	if effect.Heal > 0 && effect.Buff == "" && stats.CurrentHealth >= stats.MaxHealth {
+		return Changes{}, ErrFullHealth // Don't waste a potion
+	}
+
+	if effect.Heal > 0 {
+		stats.CurrentHealth = math.Min(stats.CurrentHealth+effect.Heal, stats.MaxHealth)
+		ecs.AddComponent(s.World, id, *stats)
+	}
+	if effect.Buff != "" {
+		systems.AddStatusEffect(s.World, id, components.StatusEffect{Kind: effect.Buff, Source: slot.ItemID, Caster: id, Amount: effect.BuffAmount, TimeLeft: effect.Duration})
+	}
+	if err := items.RemoveItem(inv, slotIndex, 1); err != nil {
+		return Changes{}, err
+	}
+	ecs.AddComponent(s.World, id, *inv)
+	s.Audit.Record("used", id, "", slot.ItemID, slot.InstanceID, 1)
+	cooldowns.LastUse[slot.ItemID] = now
+	ecs.AddComponent(s.World, id, *cooldowns)
+	return Changes{Inventory: true}, nil
+}
+
+// UseItemByID uses a consumable from wherever it is in the inventory (hotbar keys)
+func (s *GameService) UseItemByID(id ecs.Entity, itemID string) (Changes, error) {
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	if inv == nil {
+		return Changes{}, ErrNoComponent
+	}
+	for i, slot := range inv.Slots {
+		if slot.ItemID == itemID && slot.Quantity > 0 {
+			return s.UseItem(id, i)
+		}
+	}
+	return Changes{}, ErrNotInInventory
+}
+
+// ItemCooldownLeft is how many seconds are left before a player can use an item again
+func (s *GameService) ItemCooldownLeft(id ecs.Entity, itemID string) float64 {
+	def, ok := items.Get(itemID)
+	cooldowns, _ := ecs.GetComponent[components.ItemCooldownComponent](s.World, id)
+	if !ok || def.Consumable == nil || cooldowns == nil {
+		return 0
+	}
+	lastUse, used := cooldowns.LastUse[itemID]
+	if !used {
+		return 0
+	}
+	return math.Max(0, def.Consumable.Cooldown-(s.Now()-lastUse))
+}
+
+// IsConsumable reports whether an item is used up by using it
+func IsConsumable(itemID string) bool {
+	def, ok := items.Get(itemID)
+	return ok && def.Consumable != nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+func TestUsePotionHealsAndUsesItUp(t *testing.T) {
+	svc, now := newTestService(t)
+	id := newTestPlayer(t, svc)
+	inv := inventoryOf(t, svc, id)
+	items.AddItem(inv, "potion_health_small", 2)
+	ecs.AddComponent(svc.World, id, *inv)
+
+	changes, err := svc.InventoryAction(id, protocol.InventoryActionPacket{ActionType: "Primary", InstanceID: instanceAt(t, svc, id, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changes.Inventory {
+		t.Error("drinking should report an inventory change")
+	}
+	stats, _ := ecs.GetComponent[components.StatsComponent](svc.World, id)
+	if stats.CurrentHealth != 80 {
+		t.Errorf("health = %.0f, want 80", stats.CurrentHealth)
+	}
+	if got := inventoryOf(t, svc, id).Slots[0].Quantity; got != 1 {
+		t.Errorf("potions left = %d, want 1", got)
+	}
+
+	// Cooldown, then capped at max health, then gone
+	_, err = svc.UseItemByID(id, "potion_health_small")
+	expectErr(t, err, ErrItemOnCooldown)
+	if left := svc.ItemCooldownLeft(id, "potion_health_small"); left != 10 {
+		t.Errorf("cooldown left = %.1f, want 10", left)
+	}
+	*now += 10
+	if _, err := svc.UseItemByID(id, "potion_health_small"); err != nil {
+		t.Fatal(err)
+	}
+	if stats, _ := ecs.GetComponent[components.StatsComponent](svc.World, id); stats.CurrentHealth != 100 {
+		t.Errorf("health = %.0f, want capped at 100", stats.CurrentHealth)
+	}
+	if slot := inventoryOf(t, svc, id).Slots[0]; slot.ItemID != "" {
+		t.Errorf("slot = %+v after the last potion", slot)
+	}
+	*now += 10
+	_, err = svc.UseItemByID(id, "potion_health_small")
+	expectErr(t, err, ErrNotInInventory)
+}
+
+func TestUsePotionAtFullHealthIsRefused(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "potion_health_small")
+	ecs.AddComponent(svc.World, id, components.StatsComponent{MaxHealth: 100, CurrentHealth: 100})
+
+	_, err := svc.UseItem(id, 0)
+	expectErr(t, err, ErrFullHealth)
+	if inventoryOf(t, svc, id).Slots[0].Quantity != 1 {
+		t.Error("the potion was wasted")
+	}
+}
+
+func TestUseElixirBuffs(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "elixir_might")
+
+	if _, err := svc.UseItem(id, 0); err != nil {
+		t.Fatal(err)
+	}
+	if m := systems.BuffMultiplier(svc.World, id, components.BuffDamage); m != 1.25 {
+		t.Errorf("damage x%.2f, want x1.25", m)
+	}
+	systems.NewStatusEffectSystem(svc.World).Update(61)
+	if m := systems.BuffMultiplier(svc.World, id, components.BuffDamage); m != 1 {
+		t.Errorf("damage x%.2f after the elixir wore off", m)
+	}
+}
+
+func TestUseNonConsumable(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "sword_starter")
+
+	_, err := svc.UseItem(id, 0)
+	expectErr(t, err, ErrNotConsumable)
+}
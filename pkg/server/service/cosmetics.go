@@ -0,0 +1,50 @@
+package service
+
+import (
+	"henry/pkg/items"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+// UseCosmetic uses up a cosmetic item from an inventory slot and adds its look (and any
+// collection rewards) to the player's wardrobe. A look already collected keeps the item.
+func (s *GameService) UseCosmetic(id ecs.Entity, slotIndex int) (Changes, error) {
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	if inv == nil {
+		return Changes{}, ErrNoComponent
+	}
+	if slotIndex < 0 || slotIndex >= len(inv.Slots) {
+		return Changes{}, ErrInvalidSlot
+	}
+	itemID := inv.Slots[slotIndex].ItemID
+	if itemID == "" || inv.Slots[slotIndex].Quantity <= 0 {
+		return Changes{}, ErrEmptySlot
+	}
+	if def, ok := items.Get(itemID); !ok || def.Type != items.ItemTypeCosmetic {
+		return Changes{}, ErrNotCosmetic
+	}
+	if items.IsLocked(inv, slotIndex) {
+		return Changes{}, ErrSlotLocked
+	}
+
+	if len(systems.UnlockLook(s.World, id, itemID)) == 0 {
+		return Changes{}, nil
+	}
+	instanceID := inv.Slots[slotIndex].InstanceID
+	if err := items.RemoveItem(inv, slotIndex, 1); err != nil {
+		return Changes{}, err
+	}
+	ecs.AddComponent(s.World, id, *inv)
+	s.Audit.Record("used", id, "", itemID, instanceID, 1)
+	return Changes{Inventory: true, Cosmetics: true}, nil
+}
+
+// SetLook shows a collected look in an equipment slot instead of the worn item ("" shows
+// the worn item again)
+func (s *GameService) SetLook(id ecs.Entity, slot int, itemID string) (Changes, error) {
+	if err := systems.SetLook(s.World, id, slot, itemID); err != nil {
+		return Changes{Cosmetics: true}, err
+	}
+	return Changes{Cosmetics: true}, nil
+}
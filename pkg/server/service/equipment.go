@@ -0,0 +1,151 @@
+package service
+
+import (
+	"henry/pkg/items"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+// EquipmentAction applies a client equipment action: "Equip" moves the named instance from
+// the inventory into Slot, "Unequip" takes the named instance off
+func (s *GameService) EquipmentAction(id ecs.Entity, action protocol.EquipmentActionPacket) (Changes, error) {
+	switch action.Action {
+	case "Equip":
+		inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+		if inv == nil {
+			return Changes{}, ErrNoComponent
+		}
+		invSlot := items.FindInstance(inv, action.InstanceID)
+		if invSlot < 0 {
+			return Changes{Inventory: true}, ErrNotInInventory
+		}
+		return s.Equip(id, invSlot, action.Slot)
+	case "Unequip":
+		equip, _ := ecs.GetComponent[components.EquipmentComponent](s.World, id)
+		if equip == nil {
+			return Changes{}, ErrNoComponent
+		}
+		for i, slot := range equip.Slots {
+			if slot.ItemID != "" && slot.InstanceID == action.InstanceID {
+				return s.Unequip(id, i)
+			}
+		}
+		return Changes{Equipment: true}, ErrNotEquipped
+	}
+	return Changes{}, ErrUnknownAction
+}
+
+// Equip moves one item from an inventory slot into an equipment slot, swapping out
+// whatever was equipped there
+func (s *GameService) Equip(id ecs.Entity, invSlot int, equipSlot int) (Changes, error) {
+	equip, _ := ecs.GetComponent[components.EquipmentComponent](s.World, id)
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	if equip == nil || inv == nil {
+		return Changes{}, ErrNoComponent
+	}
+
+	if invSlot < 0 || invSlot >= len(inv.Slots) || equipSlot < 0 || equipSlot >= len(equip.Slots) {
+		return Changes{}, ErrInvalidSlot
+	}
+	itemID := inv.Slots[invSlot].ItemID
+	if itemID == "" {
+		return Changes{}, ErrEmptySlot
+	}
+
+	def, ok := items.Get(itemID)
+	if !ok || def.EquipmentSlot == -1 || def.Type == items.ItemTypeCosmetic {
+		return Changes{}, ErrNotEquippable
+	}
+	if def.EquipmentSlot != equipSlot {
+		return Changes{}, ErrWrongSlot
+	}
+
+	// 1. Take from Inventory. Equipment doesn't stack, but a stack from an old save
+	// keeps its instance ID for the rest and the equipped one becomes a new instance.
+	taken := inv.Slots[invSlot]
+	instanceID := taken.InstanceID
+	inv.Slots[invSlot].Quantity--
+	if inv.Slots[invSlot].Quantity <= 0 {
+		inv.Slots[invSlot] = components.InventorySlot{}
+	} else {
+		instanceID = items.NewInstanceID()
+	}
+
+	// 2. Swap into the equipment slot
+	old := equip.Slots[equipSlot]
+	equip.Slots[equipSlot] = components.EquipmentSlot{ItemID: itemID, InstanceID: instanceID}
+
+	// 3. Return the old item to the freed slot (or anywhere)
+	if old.ItemID != "" {
+		if inv.Slots[invSlot].ItemID == "" {
+			inv.Slots[invSlot] = components.InventorySlot{ItemID: old.ItemID, Quantity: 1, InstanceID: old.InstanceID}
+		} else if _, err := items.AddInstance(inv, old.ItemID, old.InstanceID, 1); err != nil {
+			// inv.Slots shares its backing array with the world's component, so the
+			// stack taken from has to be put back (equip's Slots is an array, a copy)
+			inv.Slots[invSlot] = taken
+			return Changes{}, ErrInventoryFull
+		}
+	}
+
+	ecs.AddComponent(s.World, id, *equip)
+	ecs.AddComponent(s.World, id, *inv)
+	// Wearing gear collects its look
+	unlocked := systems.UnlockLook(s.World, id, itemID)
+	return Changes{Inventory: true, Equipment: true, Cosmetics: len(unlocked) > 0}, nil
+}
+
+// Unequip moves the item in an equipment slot back to the inventory
+func (s *GameService) Unequip(id ecs.Entity, equipSlot int) (Changes, error) {
+	equip, _ := ecs.GetComponent[components.EquipmentComponent](s.World, id)
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	if equip == nil || inv == nil {
+		return Changes{}, ErrNoComponent
+	}
+
+	if equipSlot < 0 || equipSlot >= len(equip.Slots) {
+		return Changes{}, ErrInvalidSlot
+	}
+	slot := equip.Slots[equipSlot]
+	if slot.ItemID == "" {
+		return Changes{}, ErrEmptySlot
+	}
+
+	if _, err := items.AddInstance(inv, slot.ItemID, slot.InstanceID, 1); err != nil {
+		return Changes{}, ErrInventoryFull
+	}
+	equip.Slots[equipSlot] = components.EquipmentSlot{}
+
+	ecs.AddComponent(s.World, id, *equip)
+	ecs.AddComponent(s.World, id, *inv)
+	return Changes{Inventory: true, Equipment: true}, nil
+}
+
+// ToggleEquip unequips the item if it is equipped, otherwise equips it from the inventory
+// (hotbar item slots)
+func (s *GameService) ToggleEquip(id ecs.Entity, itemID string) (Changes, error) {
+	equip, _ := ecs.GetComponent[components.EquipmentComponent](s.World, id)
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	if equip == nil || inv == nil {
+		return Changes{}, ErrNoComponent
+	}
+
+	for i, slot := range equip.Slots {
+		if slot.ItemID == itemID {
+			return s.Unequip(id, i)
+		}
+	}
+
+	for i, slot := range inv.Slots {
+		if slot.ItemID != itemID {
+			continue
+		}
+		def, ok := items.Get(itemID)
+		if !ok || def.EquipmentSlot == -1 {
+			return Changes{}, ErrNotEquippable
+		}
+		return s.Equip(id, i, def.EquipmentSlot)
+	}
+	return Changes{}, ErrNotInInventory
+}
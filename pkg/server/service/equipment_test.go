@@ -0,0 +1,158 @@
+package service
+
+import (
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+func TestEquipSwapsOldItemBack(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "sword_starter", "bow_starter")
+
+	if _, err := svc.Equip(id, 0, components.SlotWeapon); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Equip(id, 1, components.SlotWeapon); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := equipmentOf(t, svc, id).Slots[components.SlotWeapon].ItemID; got != "bow_starter" {
+		t.Errorf("weapon slot = %q, want bow_starter", got)
+	}
+	// The sword goes back into the bow's freed slot
+	if got := inventoryOf(t, svc, id).Slots[1].ItemID; got != "sword_starter" {
+		t.Errorf("inventory slot 1 = %q, want sword_starter", got)
+	}
+}
+
+func TestEquipWrongSlot(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "sword_starter")
+
+	_, err := svc.EquipmentAction(id, protocol.EquipmentActionPacket{Action: "Equip", InstanceID: instanceAt(t, svc, id, 0), Slot: components.SlotHead})
+	expectErr(t, err, ErrWrongSlot)
+	if got := inventoryOf(t, svc, id).Slots[0].ItemID; got != "sword_starter" {
+		t.Error("sword should stay in the inventory")
+	}
+}
+
+func TestEquipNotEquippable(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "coin_gold")
+
+	_, err := svc.Equip(id, 0, components.SlotHead)
+	expectErr(t, err, ErrNotEquippable)
+}
+
+func TestUnequip(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "sword_starter")
+	sword := instanceAt(t, svc, id, 0)
+
+	if _, err := svc.Equip(id, 0, components.SlotWeapon); err != nil {
+		t.Fatal(err)
+	}
+	if got := equipmentOf(t, svc, id).Slots[components.SlotWeapon].InstanceID; got != sword {
+		t.Errorf("equipped instance = %q, want %q", got, sword)
+	}
+	changes, err := svc.EquipmentAction(id, protocol.EquipmentActionPacket{Action: "Unequip", InstanceID: sword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changes.Inventory || !changes.Equipment {
+		t.Errorf("changes = %+v, want inventory and equipment", changes)
+	}
+	if got := equipmentOf(t, svc, id).Slots[components.SlotWeapon].ItemID; got != "" {
+		t.Errorf("weapon slot = %q after unequip", got)
+	}
+	if items.FindInstance(inventoryOf(t, svc, id), sword) < 0 {
+		t.Error("sword should be back in the inventory as the same instance")
+	}
+}
+
+func TestEquippableItemsDontStack(t *testing.T) {
+	inv := items.NewInventory(4)
+	if err := items.AddItem(inv, "sword_starter", 2); err != nil {
+		t.Fatal(err)
+	}
+	if inv.Slots[0].Quantity != 1 || inv.Slots[1].ItemID != "sword_starter" {
+		t.Errorf("slots = %+v, want one sword each", inv.Slots[:2])
+	}
+	if inv.Slots[0].InstanceID == "" || inv.Slots[0].InstanceID == inv.Slots[1].InstanceID {
+		t.Errorf("instance IDs %q and %q should be set and differ", inv.Slots[0].InstanceID, inv.Slots[1].InstanceID)
+	}
+	if err := items.AddItem(inv, "sword_starter", 3); err == nil {
+		t.Error("adding more swords than free slots should fail")
+	}
+}
+
+func TestUnequipInventoryFull(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "sword_starter")
+
+	if _, err := svc.Equip(id, 0, components.SlotWeapon); err != nil {
+		t.Fatal(err)
+	}
+	inv := inventoryOf(t, svc, id)
+	for i := range inv.Slots {
+		inv.Slots[i] = components.InventorySlot{ItemID: "coin_gold", Quantity: 1}
+	}
+	// Distinct stacks can't merge with the sword
+	inv.Slots[0].ItemID = "bow_starter"
+	inv.Slots[1].ItemID = "potion_health_small"
+	ecs.AddComponent(svc.World, id, *inv)
+
+	_, err := svc.Unequip(id, components.SlotWeapon)
+	expectErr(t, err, ErrInventoryFull)
+	if got := equipmentOf(t, svc, id).Slots[components.SlotWeapon].ItemID; got != "sword_starter" {
+		t.Error("sword should stay equipped when the inventory is full")
+	}
+}
+
+func TestEquipFromOldStackWithFullInventory(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "sword_starter")
+	if _, err := svc.Equip(id, 0, components.SlotWeapon); err != nil {
+		t.Fatal(err)
+	}
+	// An old save's stack of the same sword fills a one-slot bag
+	inv := inventoryOf(t, svc, id)
+	inv.Slots = []components.InventorySlot{{ItemID: "sword_starter", Quantity: 2, InstanceID: items.NewInstanceID()}}
+	ecs.AddComponent(svc.World, id, *inv)
+	equipped := equipmentOf(t, svc, id).Slots[components.SlotWeapon].InstanceID
+
+	_, err := svc.Equip(id, 0, components.SlotWeapon)
+	expectErr(t, err, ErrInventoryFull)
+	if got := inventoryOf(t, svc, id).Slots[0].Quantity; got != 2 {
+		t.Errorf("stack quantity = %d after the failed swap, want 2", got)
+	}
+	if got := equipmentOf(t, svc, id).Slots[components.SlotWeapon].InstanceID; got != equipped {
+		t.Error("equipped sword should stay when the swap fails")
+	}
+}
+
+func TestToggleEquip(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "bow_starter")
+
+	if _, err := svc.ToggleEquip(id, "bow_starter"); err != nil {
+		t.Fatal(err)
+	}
+	if got := equipmentOf(t, svc, id).Slots[components.SlotWeapon].ItemID; got != "bow_starter" {
+		t.Fatalf("weapon slot = %q after first toggle", got)
+	}
+
+	if _, err := svc.ToggleEquip(id, "bow_starter"); err != nil {
+		t.Fatal(err)
+	}
+	if got := equipmentOf(t, svc, id).Slots[components.SlotWeapon].ItemID; got != "" {
+		t.Errorf("weapon slot = %q after second toggle", got)
+	}
+
+	_, err := svc.ToggleEquip(id, "sword_starter")
+	expectErr(t, err, ErrNotInInventory)
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+// HotbarAction applies a client hotbar action ("Bind", "Swap", "Clear")
+func (s *GameService) HotbarAction(id ecs.Entity, action protocol.HotbarActionPacket) (Changes, error) {
+	hb, _ := ecs.GetComponent[components.HotbarComponent](s.World, id)
+	if hb == nil {
+		return Changes{}, ErrNoComponent
+	}
+
+	valid := func(i int) bool { return i >= 0 && i < len(hb.Slots) }
+	if !valid(action.SlotIndex) {
+		return Changes{}, ErrInvalidSlot
+	}
+
+	switch action.ActionType {
+	case "Bind":
+		hb.Slots[action.SlotIndex] = components.HotbarSlot{Type: action.TargetType, RefID: action.TargetRefID}
+	case "Swap":
+		if !valid(action.SlotIndexB) {
+			return Changes{}, ErrInvalidSlot
+		}
+		hb.Slots[action.SlotIndex], hb.Slots[action.SlotIndexB] = hb.Slots[action.SlotIndexB], hb.Slots[action.SlotIndex]
+	case "Clear":
+		hb.Slots[action.SlotIndex] = components.HotbarSlot{}
+	default:
+		return Changes{}, ErrUnknownAction
+	}
+
+	ecs.AddComponent(s.World, id, *hb)
+	return Changes{Hotbar: true}, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+func hotbarOf(t *testing.T, svc *GameService, id ecs.Entity) *components.HotbarComponent {
+	t.Helper()
+	hb, ok := ecs.GetComponent[components.HotbarComponent](svc.World, id)
+	if !ok {
+		t.Fatal("player has no hotbar")
+	}
+	return hb
+}
+
+func TestHotbarBindSwapClear(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc)
+
+	bind := protocol.HotbarActionPacket{ActionType: "Bind", SlotIndex: 2, TargetType: "Spell", TargetRefID: "fireball"}
+	changes, err := svc.HotbarAction(id, bind)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changes.Hotbar {
+		t.Error("bind should report a hotbar change")
+	}
+	if got := hotbarOf(t, svc, id).Slots[2]; got.Type != "Spell" || got.RefID != "fireball" {
+		t.Fatalf("slot 2 = %+v", got)
+	}
+
+	if _, err := svc.HotbarAction(id, protocol.HotbarActionPacket{ActionType: "Swap", SlotIndex: 2, SlotIndexB: 7}); err != nil {
+		t.Fatal(err)
+	}
+	hb := hotbarOf(t, svc, id)
+	if hb.Slots[2].RefID != "" || hb.Slots[7].RefID != "fireball" {
+		t.Fatalf("after swap: slot 2 = %+v, slot 7 = %+v", hb.Slots[2], hb.Slots[7])
+	}
+
+	if _, err := svc.HotbarAction(id, protocol.HotbarActionPacket{ActionType: "Clear", SlotIndex: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if got := hotbarOf(t, svc, id).Slots[7]; got != (components.HotbarSlot{}) {
+		t.Errorf("slot 7 = %+v after clear", got)
+	}
+}
+
+func TestHotbarInvalidSlots(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc)
+
+	_, err := svc.HotbarAction(id, protocol.HotbarActionPacket{ActionType: "Bind", SlotIndex: 10})
+	expectErr(t, err, ErrInvalidSlot)
+
+	_, err = svc.HotbarAction(id, protocol.HotbarActionPacket{ActionType: "Swap", SlotIndex: 0, SlotIndexB: -1})
+	expectErr(t, err, ErrInvalidSlot)
+}
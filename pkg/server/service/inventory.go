@@ -0,0 +1,99 @@
+package service
+
+import (
+	"henry/pkg/items"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+// InventoryAction applies a client inventory action ("Swap", "Drop", "ToggleLock", "Primary")
+// to the item instance it names
+func (s *GameService) InventoryAction(id ecs.Entity, action protocol.InventoryActionPacket) (Changes, error) {
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	if inv == nil {
+		return Changes{}, ErrNoComponent
+	}
+	slot := items.FindInstance(inv, action.InstanceID)
+	if slot < 0 {
+		// Stale client view (item moved or gone): resync
+		return Changes{Inventory: true}, ErrNotInInventory
+	}
+
+	switch action.ActionType {
+	case "Swap":
+		if err := items.SwapItems(inv, slot, action.ToSlot); err != nil {
+			return Changes{}, ErrInvalidSlot
+		}
+	case "Drop":
+		if err := s.drop(id, inv, slot); err != nil {
+			// Locked: resync so the client puts the item back
+			return Changes{Inventory: err == ErrSlotLocked}, err
+		}
+	case "ToggleLock":
+		if err := items.ToggleLock(inv, slot); err != nil {
+			return Changes{}, err
+		}
+	case "Primary":
+		return s.usePrimary(id, inv, slot)
+	default:
+		return Changes{}, ErrUnknownAction
+	}
+
+	ecs.AddComponent(s.World, id, *inv)
+	return Changes{Inventory: true}, nil
+}
+
+// drop moves a whole stack to the ground at the player's feet
+func (s *GameService) drop(id ecs.Entity, inv *components.InventoryComponent, slotIndex int) error {
+	if slotIndex < 0 || slotIndex >= len(inv.Slots) {
+		return ErrInvalidSlot
+	}
+	slot := inv.Slots[slotIndex]
+	if slot.ItemID == "" || slot.Quantity <= 0 {
+		return ErrEmptySlot
+	}
+	if items.IsLocked(inv, slotIndex) {
+		return ErrSlotLocked
+	}
+
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+	if trans == nil || s.GroundItems == nil {
+		return ErrNoComponent
+	}
+
+	offset := (float64(config.TileSize) - systems.GroundItemSize) / 2
+	ground, err := s.GroundItems.SpawnInstance(trans.X+offset, trans.Y+offset, trans.Z, slot.ItemID, slot.InstanceID, slot.Quantity, id)
+	if err != nil {
+		return err
+	}
+	inv.Slots[slotIndex] = components.InventorySlot{}
+	item, _ := ecs.GetComponent[components.GroundItemComponent](s.World, ground)
+	s.Audit.Record("dropped", id, "", item.ItemID, item.InstanceID, item.Quantity)
+	return nil
+}
+
+// usePrimary equips equippable items, uses consumables and collects cosmetics
+func (s *GameService) usePrimary(id ecs.Entity, inv *components.InventoryComponent, slotIndex int) (Changes, error) {
+	if slotIndex < 0 || slotIndex >= len(inv.Slots) {
+		return Changes{}, ErrInvalidSlot
+	}
+	itemID := inv.Slots[slotIndex].ItemID
+	if itemID == "" {
+		return Changes{}, ErrEmptySlot
+	}
+
+	def, ok := items.Get(itemID)
+	if ok && def.Consumable != nil {
+		return s.UseItem(id, slotIndex)
+	}
+	if ok && def.Type == items.ItemTypeCosmetic {
+		return s.UseCosmetic(id, slotIndex)
+	}
+	if ok && def.EquipmentSlot != -1 {
+		return s.Equip(id, slotIndex, def.EquipmentSlot)
+	}
+	return Changes{}, nil
+}
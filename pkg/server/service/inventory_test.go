@@ -0,0 +1,146 @@
+package service
+
+import (
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+func TestInventorySwap(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "sword_starter", "bow_starter")
+
+	changes, err := svc.InventoryAction(id, protocol.InventoryActionPacket{ActionType: "Swap", InstanceID: instanceAt(t, svc, id, 0), ToSlot: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changes.Inventory {
+		t.Error("swap should report an inventory change")
+	}
+
+	inv := inventoryOf(t, svc, id)
+	if inv.Slots[0].ItemID != "" || inv.Slots[3].ItemID != "sword_starter" {
+		t.Errorf("slots after swap = %q, %q", inv.Slots[0].ItemID, inv.Slots[3].ItemID)
+	}
+}
+
+func TestInventorySwapInvalidSlot(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "sword_starter")
+
+	_, err := svc.InventoryAction(id, protocol.InventoryActionPacket{ActionType: "Swap", InstanceID: instanceAt(t, svc, id, 0), ToSlot: 99})
+	expectErr(t, err, ErrInvalidSlot)
+}
+
+func TestInventoryDrop(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "bow_starter")
+
+	if _, err := svc.InventoryAction(id, protocol.InventoryActionPacket{ActionType: "Drop", InstanceID: instanceAt(t, svc, id, 0)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if inv := inventoryOf(t, svc, id); inv.Slots[0].ItemID != "" {
+		t.Errorf("slot 0 = %q after drop, want empty", inv.Slots[0].ItemID)
+	}
+	ground := ecs.Query[components.GroundItemComponent](svc.World)
+	if len(ground) != 1 {
+		t.Fatalf("ground items = %d, want 1", len(ground))
+	}
+	item, _ := ecs.GetComponent[components.GroundItemComponent](svc.World, ground[0])
+	if item.ItemID != "bow_starter" || item.OwnerID != id {
+		t.Errorf("ground item = %+v", item)
+	}
+}
+
+func TestInventoryDropLocked(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "bow_starter")
+
+	if _, err := svc.InventoryAction(id, protocol.InventoryActionPacket{ActionType: "ToggleLock", InstanceID: instanceAt(t, svc, id, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := svc.InventoryAction(id, protocol.InventoryActionPacket{ActionType: "Drop", InstanceID: instanceAt(t, svc, id, 0)})
+	expectErr(t, err, ErrSlotLocked)
+	if !changes.Inventory {
+		t.Error("locked drop should still resync the inventory")
+	}
+	if inv := inventoryOf(t, svc, id); inv.Slots[0].ItemID != "bow_starter" {
+		t.Error("locked item was dropped")
+	}
+}
+
+func TestInventoryPrimaryEquips(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "sword_starter")
+
+	changes, err := svc.InventoryAction(id, protocol.InventoryActionPacket{ActionType: "Primary", InstanceID: instanceAt(t, svc, id, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changes.Equipment {
+		t.Error("primary on a weapon should equip it")
+	}
+	if got := equipmentOf(t, svc, id).Slots[components.SlotWeapon].ItemID; got != "sword_starter" {
+		t.Errorf("weapon slot = %q", got)
+	}
+}
+
+func TestInventoryPrimaryNonEquippable(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "coin_gold")
+
+	changes, err := svc.InventoryAction(id, protocol.InventoryActionPacket{ActionType: "Primary", InstanceID: instanceAt(t, svc, id, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes.Any() {
+		t.Errorf("changes = %+v, want none", changes)
+	}
+	if inv := inventoryOf(t, svc, id); inv.Slots[0].ItemID != "coin_gold" {
+		t.Error("coin should stay in the inventory")
+	}
+}
+
+func TestInventoryUnknownAction(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "coin_gold")
+
+	_, err := svc.InventoryAction(id, protocol.InventoryActionPacket{ActionType: "Juggle", InstanceID: instanceAt(t, svc, id, 0)})
+	expectErr(t, err, ErrUnknownAction)
+}
+
+func TestInventoryActionStaleInstance(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "bow_starter")
+
+	changes, err := svc.InventoryAction(id, protocol.InventoryActionPacket{ActionType: "Drop", InstanceID: "gone"})
+	expectErr(t, err, ErrNotInInventory)
+	if !changes.Inventory {
+		t.Error("an unknown instance should resync the inventory")
+	}
+}
+
+func TestInstanceSurvivesDropAndPickup(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc, "coin_gold", "bow_starter")
+	bow := instanceAt(t, svc, id, 1)
+	ecs.AddComponent(svc.World, id, components.TransformComponent{})
+
+	if _, err := svc.InventoryAction(id, protocol.InventoryActionPacket{ActionType: "Drop", InstanceID: bow}); err != nil {
+		t.Fatal(err)
+	}
+	ground := ecs.Query[components.GroundItemComponent](svc.World)
+	if item, _ := ecs.GetComponent[components.GroundItemComponent](svc.World, ground[0]); item.InstanceID != bow {
+		t.Fatalf("ground instance = %q, want %q", item.InstanceID, bow)
+	}
+	if err := svc.GroundItems.Pickup(id, ground[0]); err != nil {
+		t.Fatal(err)
+	}
+	if slot := items.FindInstance(inventoryOf(t, svc, id), bow); slot < 0 {
+		t.Error("picked up bow lost its instance ID")
+	}
+}
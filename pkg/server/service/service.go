@@ -0,0 +1,86 @@
+// Package service holds the player-facing game rules (inventory, equipment, hotbar, spells)
+// as plain functions over the ECS world. The GameServer wraps them with locking, logging and
+// network syncs; tests drive them directly.
+package service
+
+import (
+	"errors"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/ecs"
+	"time"
+)
+
+var (
+	ErrNoComponent    = errors.New("entity is missing a required component")
+	ErrInvalidSlot    = errors.New("invalid slot")
+	ErrEmptySlot      = errors.New("empty slot")
+	ErrSlotLocked     = errors.New("slot is locked")
+	ErrNotEquippable  = errors.New("item is not equippable")
+	ErrWrongSlot      = errors.New("item does not fit that slot")
+	ErrInventoryFull  = errors.New("inventory full")
+	ErrNotInInventory = errors.New("item is not in the inventory")
+	ErrNotEquipped    = errors.New("item is not equipped")
+	ErrUnknownAction  = errors.New("unknown action")
+	ErrUnknownSpell   = errors.New("unknown spell")
+	ErrSpellLocked    = errors.New("spell not unlocked")
+	ErrOnCooldown     = errors.New("spell on cooldown")
+	ErrNotConsumable  = errors.New("item can't be used up")
+	ErrItemOnCooldown = errors.New("item on cooldown")
+	ErrFullHealth     = errors.New("already at full health")
+	ErrSpawnLimit     = errors.New("spawn limit reached")
+	ErrNoShop         = errors.New("no vendor nearby")
+	ErrShopClosed     = errors.New("the shop is closed until morning")
+	ErrNotForSale     = errors.New("the vendor doesn't trade that")
+	ErrBadQuantity    = errors.New("invalid quantity")
+	ErrNotEnoughGold  = systems.ErrNotEnoughGold
+	ErrNotCosmetic    = errors.New("item is not a cosmetic")
+	ErrLookLocked     = systems.ErrLookLocked
+	ErrLookSlot       = systems.ErrLookSlot
+)
+
+// Changes reports which parts of the player's state were modified, so the caller knows
+// what to sync to the client and whether to save. It can be set alongside an error when
+// the client's (optimistic) view needs to be refreshed.
+type Changes struct {
+	Inventory bool
+	Equipment bool
+	Hotbar    bool
+	Spellbook bool
+	Cosmetics bool
+}
+
+func (c Changes) Any() bool {
+	return c.Inventory || c.Equipment || c.Hotbar || c.Spellbook || c.Cosmetics
+}
+
+func (c Changes) merge(o Changes) Changes {
+	return Changes{
+		Inventory: c.Inventory || o.Inventory,
+		Equipment: c.Equipment || o.Equipment,
+		Hotbar:    c.Hotbar || o.Hotbar,
+		Spellbook: c.Spellbook || o.Spellbook,
+		Cosmetics: c.Cosmetics || o.Cosmetics,
+	}
+}
+
+type GameService struct {
+	World *ecs.World
+
+	// Optional collaborators (nil disables the feature they back)
+	GroundItems *systems.GroundItemSystem // Dropping items
+	Limiter     *systems.SpawnLimiter     // Spell projectiles
+	Economy     *systems.EconomySystem    // Shop prices
+	Audit       *systems.ItemAudit        // Item audit trail
+
+	// Now returns the current time in seconds (cooldowns). Tests replace it.
+	Now func() float64
+}
+
+func NewGameService(world *ecs.World) *GameService {
+	return &GameService{
+		World: world,
+		Now: func() float64 {
+			return float64(time.Now().UnixMilli()) / 1000.0
+		},
+	}
+}
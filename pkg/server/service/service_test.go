@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+// newTestService returns a service over an empty world with a fixed clock
+func newTestService(t *testing.T) (*GameService, *float64) {
+	t.Helper()
+	w := ecs.NewWorld()
+	svc := NewGameService(w)
+	svc.GroundItems = systems.NewGroundItemSystem(w)
+	now := 1000.0
+	svc.Now = func() float64 { return now }
+	return svc, &now
+}
+
+// instanceAt returns the instance ID of the item in a player's inventory slot
+func instanceAt(t *testing.T, svc *GameService, id ecs.Entity, slot int) string {
+	t.Helper()
+	return inventoryOf(t, svc, id).Slots[slot].InstanceID
+}
+
+// newTestPlayer spawns a player with a small inventory holding the given items (one slot each)
+func newTestPlayer(t *testing.T, svc *GameService, itemIDs ...string) ecs.Entity {
+	t.Helper()
+	id := svc.World.NewEntity()
+	inv := items.NewInventory(4)
+	for _, itemID := range itemIDs {
+		if err := items.AddItem(inv, itemID, 1); err != nil {
+			t.Fatalf("AddItem(%s): %v", itemID, err)
+		}
+	}
+	ecs.AddComponent(svc.World, id, *inv)
+	ecs.AddComponent(svc.World, id, components.EquipmentComponent{})
+	ecs.AddComponent(svc.World, id, components.HotbarComponent{})
+	ecs.AddComponent(svc.World, id, components.TransformComponent{X: 100, Y: 100})
+	ecs.AddComponent(svc.World, id, components.StatsComponent{MaxHealth: 100, CurrentHealth: 50})
+	ecs.AddComponent(svc.World, id, components.SpellbookComponent{UnlockedSpells: []string{"fireball", "heal", "blink"}})
+	svc.World.AddTags(id, components.TagPlayer)
+	return id
+}
+
+func inventoryOf(t *testing.T, svc *GameService, id ecs.Entity) *components.InventoryComponent {
+	t.Helper()
+	inv, ok := ecs.GetComponent[components.InventoryComponent](svc.World, id)
+	if !ok {
+		t.Fatal("player has no inventory")
+	}
+	return inv
+}
+
+func equipmentOf(t *testing.T, svc *GameService, id ecs.Entity) *components.EquipmentComponent {
+	t.Helper()
+	equip, ok := ecs.GetComponent[components.EquipmentComponent](svc.World, id)
+	if !ok {
+		t.Fatal("player has no equipment")
+	}
+	return equip
+}
+
+func expectErr(t *testing.T, got, want error) {
+	t.Helper()
+	if !errors.Is(got, want) {
+		t.Fatalf("error = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"henry/pkg/items"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	protocol "henry/pkg/shared/network"
+)
+
+// ShopRange is how far (px) a player can stand from a vendor and still trade: the shop
+// opens from its dialogue, so it stays usable as far as the conversation would have
+const ShopRange = systems.DialogueLeave
+
+// ShopMaxQuantity caps a single purchase or sale
+const ShopMaxQuantity = 100
+
+// ShopOpen lists what a vendor sells and what it pays, for the player's shop window
+func (s *GameService) ShopOpen(npc ecs.Entity) (protocol.ShopOpenPacket, error) {
+	shop, ok := ecs.GetComponent[components.ShopComponent](s.World, npc)
+	if !ok {
+		return protocol.ShopOpenPacket{}, ErrNoShop
+	}
+	if !s.trading(npc) {
+		return protocol.ShopOpenPacket{}, ErrShopClosed
+	}
+	packet := protocol.ShopOpenPacket{NPC: npc, Name: shop.Name, SellPrices: map[string]int{}}
+	for _, entry := range shop.Stock {
+		if price, ok := s.buyPrice(shop, entry.ItemID); ok && price > 0 {
+			packet.Stock = append(packet.Stock, protocol.ShopEntry{ItemID: entry.ItemID, Price: price})
+		}
+	}
+	if s.Economy != nil {
+		for itemID := range s.Economy.Config().ItemValues {
+			if price, ok := s.Economy.SellPrice(itemID); ok && price > 0 && itemID != items.Gold {
+				packet.SellPrices[itemID] = price
+			}
+		}
+	}
+	return packet, nil
+}
+
+// ShopBuy buys items from a vendor. The gold and the bag space are both checked before
+// either changes, so a purchase goes through whole or not at all.
+func (s *GameService) ShopBuy(id, npc ecs.Entity, itemID string, quantity int) (Changes, error) {
+	if quantity < 1 || quantity > ShopMaxQuantity {
+		return Changes{}, ErrBadQuantity
+	}
+	shop, err := s.shopNear(id, npc)
+	if err != nil {
+		return Changes{}, err
+	}
+	price, ok := s.buyPrice(shop, itemID)
+	if !ok || price <= 0 {
+		return Changes{}, ErrNotForSale
+	}
+	cost := price * quantity
+	if systems.Gold(s.World, id) < cost {
+		return Changes{}, ErrNotEnoughGold
+	}
+
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	if inv == nil {
+		return Changes{}, ErrNoComponent
+	}
+	if err := items.AddItem(inv, itemID, quantity); err != nil {
+		return Changes{}, ErrInventoryFull
+	}
+	if err := systems.SpendGold(s.World, id, cost); err != nil {
+		return Changes{}, err
+	}
+	ecs.AddComponent(s.World, id, *inv)
+	s.Audit.Record("bought", id, shop.Name, itemID, "", quantity)
+	s.Audit.Record("spent", id, shop.Name, items.Gold, "", cost)
+	return Changes{Inventory: true}, nil
+}
+
+// ShopSell sells items from one of the player's stacks to a vendor
+func (s *GameService) ShopSell(id, npc ecs.Entity, instanceID string, quantity int) (Changes, error) {
+	if quantity < 1 || quantity > ShopMaxQuantity {
+		return Changes{}, ErrBadQuantity
+	}
+	shop, err := s.shopNear(id, npc)
+	if err != nil {
+		return Changes{}, err
+	}
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	if inv == nil {
+		return Changes{}, ErrNoComponent
+	}
+	slot := items.FindInstance(inv, instanceID)
+	if slot < 0 {
+		return Changes{Inventory: true}, ErrNotInInventory
+	}
+	if items.IsLocked(inv, slot) {
+		return Changes{}, ErrSlotLocked
+	}
+	if inv.Slots[slot].Quantity < quantity {
+		return Changes{Inventory: true}, ErrBadQuantity
+	}
+	if s.Economy == nil {
+		return Changes{}, ErrNotForSale
+	}
+	sold := inv.Slots[slot]
+	price, ok := s.Economy.SellPrice(sold.ItemID)
+	if !ok || price <= 0 {
+		return Changes{}, ErrNotForSale
+	}
+
+	if err := items.RemoveItem(inv, slot, quantity); err != nil {
+		return Changes{Inventory: true}, ErrBadQuantity
+	}
+	ecs.AddComponent(s.World, id, *inv)
+	systems.AddGold(s.World, id, price*quantity)
+	s.Audit.Record("sold", id, shop.Name, sold.ItemID, sold.InstanceID, quantity)
+	s.Audit.Record("earned", id, shop.Name, items.Gold, "", price*quantity)
+	return Changes{Inventory: true}, nil
+}
+
+// shopNear returns the shop of a vendor the player is close enough to trade with
+func (s *GameService) shopNear(id, npc ecs.Entity) (*components.ShopComponent, error) {
+	shop, ok := ecs.GetComponent[components.ShopComponent](s.World, npc)
+	if !ok {
+		return nil, ErrNoShop
+	}
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+	npcTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, npc)
+	if trans == nil || npcTrans == nil || trans.Z != npcTrans.Z ||
+		geom.Dist(trans.X, trans.Y, npcTrans.X, npcTrans.Y) > ShopRange {
+		return nil, ErrNoShop
+	}
+	if !s.trading(npc) {
+		return nil, ErrShopClosed
+	}
+	return shop, nil
+}
+
+// trading reports whether a vendor is open: one with a schedule only trades during its
+// "trade" hours, while one without (a festival stall) never closes
+func (s *GameService) trading(npc ecs.Entity) bool {
+	ai, ok := ecs.GetComponent[components.AIComponent](s.World, npc)
+	return !ok || len(ai.Schedule) == 0 || ai.Activity == "trade"
+}
+
+// buyPrice is what a vendor charges for one item: its own price, or the economy's
+func (s *GameService) buyPrice(shop *components.ShopComponent, itemID string) (int, bool) {
+	for _, entry := range shop.Stock {
+		if entry.ItemID != itemID {
+			continue
+		}
+		if entry.Price > 0 {
+			return entry.Price, true
+		}
+		if s.Economy != nil {
+			return s.Economy.BuyPrice(itemID)
+		}
+		return 0, false
+	}
+	return 0, false
+}
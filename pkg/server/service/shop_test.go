@@ -0,0 +1,115 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+
+	"henry/pkg/characters"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+// newTestShop adds the economy and a vendor next to the test player
+func newTestShop(t *testing.T, svc *GameService) ecs.Entity {
+	t.Helper()
+	svc.Economy = systems.NewEconomySystem(filepath.Join("..", "..", "..", "data", "economy", "economy.json"))
+	npc := svc.World.NewEntity()
+	ecs.AddComponent(svc.World, npc, components.TransformComponent{X: 150, Y: 100})
+	ecs.AddComponent(svc.World, npc, components.ShopComponent{Name: "Merchant", Stock: []components.ShopItem{
+		{ItemID: "potion_health_small"},
+		{ItemID: "sword_starter", Price: 50},
+	}})
+	return npc
+}
+
+func TestShopBuyIsAtomic(t *testing.T) {
+	svc, _ := newTestService(t)
+	npc := newTestShop(t, svc)
+	id := newTestPlayer(t, svc, "bow_starter", "bow_starter", "bow_starter")
+	systems.AddGold(svc.World, id, 60)
+
+	packet, err := svc.ShopOpen(npc)
+	if err != nil || len(packet.Stock) != 2 || packet.Stock[1].Price != 50 {
+		t.Fatalf("ShopOpen = %+v, %v", packet, err)
+	}
+
+	_, err = svc.ShopBuy(id, npc, "bow_starter", 1)
+	expectErr(t, err, ErrNotForSale)
+	_, err = svc.ShopBuy(id, npc, "sword_starter", 2)
+	expectErr(t, err, ErrNotEnoughGold)
+
+	// One slot left: a sword fits, a second one doesn't and costs nothing
+	if _, err := svc.ShopBuy(id, npc, "sword_starter", 1); err != nil {
+		t.Fatal(err)
+	}
+	if gold := systems.Gold(svc.World, id); gold != 10 {
+		t.Fatalf("gold after buying = %d, want 10", gold)
+	}
+	systems.AddGold(svc.World, id, 100)
+	_, err = svc.ShopBuy(id, npc, "sword_starter", 1)
+	expectErr(t, err, ErrInventoryFull)
+	if gold := systems.Gold(svc.World, id); gold != 110 {
+		t.Fatalf("failed purchase took gold: %d left, want 110", gold)
+	}
+
+	// Out of range
+	ecs.AddComponent(svc.World, id, components.TransformComponent{X: 100 + ShopRange + 100, Y: 100})
+	_, err = svc.ShopBuy(id, npc, "potion_health_small", 1)
+	expectErr(t, err, ErrNoShop)
+}
+
+func TestShopSell(t *testing.T) {
+	svc, _ := newTestService(t)
+	npc := newTestShop(t, svc)
+	id := newTestPlayer(t, svc, "sword_starter", "bow_starter")
+	price, _ := svc.Economy.SellPrice("sword_starter")
+
+	if _, err := svc.ShopSell(id, npc, instanceAt(t, svc, id, 0), 1); err != nil {
+		t.Fatal(err)
+	}
+	if gold := systems.Gold(svc.World, id); gold != price {
+		t.Fatalf("gold after selling = %d, want %d", gold, price)
+	}
+	if inv := inventoryOf(t, svc, id); inv.Slots[0].ItemID != "" {
+		t.Fatalf("sold sword still in slot 0: %+v", inv.Slots[0])
+	}
+
+	_, err := svc.ShopSell(id, npc, instanceAt(t, svc, id, 1), 2)
+	expectErr(t, err, ErrBadQuantity)
+
+	inv := inventoryOf(t, svc, id)
+	inv.Slots[1].Locked = true
+	ecs.AddComponent(svc.World, id, *inv)
+	_, err = svc.ShopSell(id, npc, instanceAt(t, svc, id, 1), 1)
+	expectErr(t, err, ErrSlotLocked)
+}
+
+func TestShopClosedAtNight(t *testing.T) {
+	svc, _ := newTestService(t)
+	npc := newTestShop(t, svc)
+	id := newTestPlayer(t, svc, "bow_starter")
+	systems.AddGold(svc.World, id, 100)
+
+	// The merchant's hours, kept by the AI system like a spawned merchant's
+	merchant, _ := characters.Get("merchant")
+	ecs.AddComponent(svc.World, npc, components.AIComponent{Type: "static", Schedule: merchant.Schedule})
+	ecs.AddComponent(svc.World, npc, components.InputComponent{})
+	clock := world.NewClock(22, 1200)
+	ai := systems.NewAISystem(svc.World, nil, clock)
+
+	ai.Update(0)
+	_, err := svc.ShopOpen(npc)
+	expectErr(t, err, ErrShopClosed)
+	_, err = svc.ShopBuy(id, npc, "potion_health_small", 1)
+	expectErr(t, err, ErrShopClosed)
+	_, err = svc.ShopSell(id, npc, instanceAt(t, svc, id, 0), 1)
+	expectErr(t, err, ErrShopClosed)
+
+	clock.Hour = 9
+	ai.Update(0)
+	if _, err := svc.ShopBuy(id, npc, "potion_health_small", 1); err != nil {
+		t.Fatalf("buying in the morning: %v", err)
+	}
+}
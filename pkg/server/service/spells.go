@@ -0,0 +1,210 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// SpellDir holds one JSON spell definition per file (see LoadSpells)
+const SpellDir = "data/spells"
+
+// CastSpell casts an unlocked spell toward a target position, starting its cooldown
+func (s *GameService) CastSpell(id ecs.Entity, spellID string, targetX, targetY float64) (Changes, error) {
+	spellbook, _ := ecs.GetComponent[components.SpellbookComponent](s.World, id)
+	if spellbook == nil {
+		return Changes{}, ErrNoComponent
+	}
+	if !slices.Contains(spellbook.UnlockedSpells, spellID) {
+		return Changes{}, ErrSpellLocked
+	}
+
+	spellDef, exists := components.SpellRegistry[spellID]
+	if !exists {
+		return Changes{}, ErrUnknownSpell
+	}
+
+	now := s.Now()
+	if spellbook.Cooldowns == nil {
+		spellbook.Cooldowns = make(map[string]float64)
+	}
+	stats := systems.Stats(s.World, id)
+	if lastCast, cast := spellbook.Cooldowns[spellID]; cast && now-lastCast < stats.SpellCooldown(spellDef.Cooldown) {
+		return Changes{}, ErrOnCooldown
+	}
+
+	transform, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+	if transform == nil {
+		return Changes{}, ErrNoComponent
+	}
+
+	attacking := hasEffect(spellDef, components.EffectProjectile)
+	if s.Limiter != nil && attacking && !s.Limiter.AllowProjectile(id) {
+		return Changes{}, ErrSpawnLimit
+	}
+	if attacking {
+		systems.Reveal(s.World, id)
+	}
+	for _, effect := range spellDef.Effects {
+		s.applySpellEffect(id, transform, stats, spellDef, effect, targetX, targetY)
+	}
+
+	spellbook.Cooldowns[spellID] = now
+	ecs.AddComponent(s.World, id, *spellbook)
+	return Changes{Spellbook: true}, nil
+}
+
+// applySpellEffect carries out one effect of a spell cast by id
+func (s *GameService) applySpellEffect(id ecs.Entity, transform *components.TransformComponent, stats systems.CombatStats, def components.Spell, effect components.SpellEffect, targetX, targetY float64) {
+	switch effect.Type {
+	case components.EffectProjectile:
+		s.spawnSpellProjectile(id, transform, effect.Amount*stats.SpellPower, def, effect, targetX, targetY)
+
+	case components.EffectHeal:
+		if health, _ := ecs.GetComponent[components.StatsComponent](s.World, id); health != nil {
+			health.CurrentHealth = math.Min(health.CurrentHealth+effect.Amount*stats.SpellPower, health.MaxHealth)
+			ecs.AddComponent(s.World, id, *health)
+		}
+
+	case components.EffectTeleport:
+		dirX, dirY := geom.Direction(transform.X, transform.Y, targetX, targetY)
+		transform.X += dirX * effect.Amount
+		transform.Y += dirY * effect.Amount
+		ecs.AddComponent(s.World, id, *transform)
+
+	case components.EffectBuff, components.EffectStatus:
+		if !effect.OnHit {
+			systems.AddStatusEffect(s.World, id, components.StatusEffect{Kind: effect.Stat, Source: def.ID, Caster: id, Amount: effect.Amount, TimeLeft: effect.Duration})
+		}
+	}
+}
+
+// onHitStatuses are the statuses a spell's projectiles put on what they hit
+func onHitStatuses(owner ecs.Entity, def components.Spell) []components.StatusEffect {
+	var statuses []components.StatusEffect
+	for _, effect := range def.Effects {
+		if effect.Type == components.EffectStatus && effect.OnHit {
+			statuses = append(statuses, components.StatusEffect{Kind: effect.Stat, Source: def.ID, Caster: owner, Amount: effect.Amount, TimeLeft: effect.Duration})
+		}
+	}
+	return statuses
+}
+
+func hasEffect(def components.Spell, effectType string) bool {
+	for _, effect := range def.Effects {
+		if effect.Type == effectType {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *GameService) spawnSpellProjectile(owner ecs.Entity, from *components.TransformComponent, damage float64, def components.Spell, effect components.SpellEffect, targetX, targetY float64) {
+	dirX, dirY := geom.Direction(from.X, from.Y, targetX, targetY)
+
+	spawnDist := 20.0
+	spawnX := from.X + dirX*spawnDist
+	spawnY := from.Y + dirY*spawnDist
+	rot := components.TextureRotation(effect.Texture, dirX, dirY)
+
+	proj := s.World.NewEntity()
+	ecs.AddComponent(s.World, proj, components.TransformComponent{X: spawnX, Y: spawnY, Z: from.Z, Rotation: rot})
+	ecs.AddComponent(s.World, proj, components.PhysicsComponent{
+		VelX:  dirX * effect.Speed,
+		VelY:  dirY * effect.Speed,
+		Speed: effect.Speed,
+		Layer: components.LayerProjectile,
+		Mask:  components.MaskProjectile,
+		Shape: components.ShapeCircle,
+		Size:  effect.Size,
+	})
+	ecs.AddComponent(s.World, proj, components.SpriteComponent{Width: effect.Size + 2, Height: effect.Size + 2, Color: def.Color, Texture: effect.Texture})
+	ecs.AddComponent(s.World, proj, components.ProjectileComponent{
+		OwnerID:  owner,
+		Faction:  systems.FactionOf(s.World, owner),
+		Damage:   damage * systems.DamageScale(s.World, owner),
+		Lifetime: effect.Lifetime,
+		OnHit:    onHitStatuses(owner, def),
+	})
+	s.World.AddTags(proj, components.TagProjectile)
+}
+
+// LoadSpells reads every *.json spell definition in dir. Any bad file fails the whole
+// load, so a typo can't leave half the spells changed.
+func LoadSpells(dir string) ([]components.Spell, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var spells []components.Spell
+	seen := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var spell components.Spell
+		if err := json.Unmarshal(data, &spell); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if err := validateSpell(spell); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if other, dup := seen[spell.ID]; dup {
+			return nil, fmt.Errorf("%s: spell %q is already defined in %s", file, spell.ID, other)
+		}
+		seen[spell.ID] = file
+		spells = append(spells, spell)
+	}
+	return spells, nil
+}
+
+func validateSpell(spell components.Spell) error {
+	if spell.ID == "" {
+		return errors.New("spell has no id")
+	}
+	for i, effect := range spell.Effects {
+		switch effect.Type {
+		case components.EffectProjectile:
+			if effect.Speed <= 0 || effect.Lifetime <= 0 || effect.Size <= 0 {
+				return fmt.Errorf("effect %d: a projectile needs a speed, lifetime and size", i+1)
+			}
+		case components.EffectHeal, components.EffectTeleport:
+		case components.EffectBuff:
+			switch effect.Stat {
+			case components.BuffDamage, components.BuffDamageTaken, components.BuffSpeed:
+			default:
+				return fmt.Errorf("effect %d: unknown buff stat %q", i+1, effect.Stat)
+			}
+			if effect.Duration <= 0 {
+				return fmt.Errorf("effect %d: a buff needs a duration", i+1)
+			}
+		case components.EffectStatus:
+			switch effect.Stat {
+			case components.StatusSlow, components.StatusBurn, components.StatusShield, components.StatusInvisible:
+			default:
+				return fmt.Errorf("effect %d: unknown status %q", i+1, effect.Stat)
+			}
+			if effect.Duration <= 0 {
+				return fmt.Errorf("effect %d: a status needs a duration", i+1)
+			}
+			if effect.OnHit && !hasEffect(spell, components.EffectProjectile) {
+				return fmt.Errorf("effect %d: an on-hit status needs a projectile to carry it", i+1)
+			}
+		default:
+			return fmt.Errorf("effect %d: unknown type %q", i+1, effect.Type)
+		}
+	}
+	return nil
+}
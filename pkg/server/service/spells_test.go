@@ -0,0 +1,161 @@
+package service
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+// effectOf returns the first effect of a built-in spell
+func effectOf(spellID string) components.SpellEffect {
+	return components.SpellRegistry[spellID].Effects[0]
+}
+
+func TestCastHealClampsAndStartsCooldown(t *testing.T) {
+	svc, now := newTestService(t)
+	id := newTestPlayer(t, svc)
+
+	changes, err := svc.CastSpell(id, "heal", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changes.Spellbook {
+		t.Error("cast should report a spellbook (cooldown) change")
+	}
+	stats, _ := ecs.GetComponent[components.StatsComponent](svc.World, id)
+	if stats.CurrentHealth != 50+effectOf("heal").Amount {
+		t.Errorf("health = %.1f, want %.1f", stats.CurrentHealth, 50+effectOf("heal").Amount)
+	}
+
+	_, err = svc.CastSpell(id, "heal", 0, 0)
+	expectErr(t, err, ErrOnCooldown)
+
+	// After the cooldown, healing caps at max health
+	*now += components.SpellRegistry["heal"].Cooldown
+	for i := 0; i < 3; i++ {
+		if _, err := svc.CastSpell(id, "heal", 0, 0); err != nil {
+			t.Fatal(err)
+		}
+		*now += components.SpellRegistry["heal"].Cooldown
+	}
+	stats, _ = ecs.GetComponent[components.StatsComponent](svc.World, id)
+	if stats.CurrentHealth != stats.MaxHealth {
+		t.Errorf("health = %.1f, want capped at %.1f", stats.CurrentHealth, stats.MaxHealth)
+	}
+}
+
+func TestCastLockedSpell(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc)
+
+	_, err := svc.CastSpell(id, "shield", 0, 0)
+	expectErr(t, err, ErrSpellLocked)
+}
+
+func TestCastFireballSpawnsProjectile(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc)
+
+	if _, err := svc.CastSpell(id, "fireball", 400, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	projectiles := ecs.Query[components.ProjectileComponent](svc.World)
+	if len(projectiles) != 1 {
+		t.Fatalf("projectiles = %d, want 1", len(projectiles))
+	}
+	proj, _ := ecs.GetComponent[components.ProjectileComponent](svc.World, projectiles[0])
+	if proj.OwnerID != id || proj.Damage != effectOf("fireball").Amount {
+		t.Errorf("projectile = %+v", proj)
+	}
+	phys, _ := ecs.GetComponent[components.PhysicsComponent](svc.World, projectiles[0])
+	if phys.VelX <= 0 || phys.VelY != 0 {
+		t.Errorf("fireball velocity = (%.1f, %.1f), want straight right", phys.VelX, phys.VelY)
+	}
+	// The art flies down-right, so heading right turns it back an eighth of a turn
+	trans, _ := ecs.GetComponent[components.TransformComponent](svc.World, projectiles[0])
+	sprite, _ := ecs.GetComponent[components.SpriteComponent](svc.World, projectiles[0])
+	if sprite.Texture != components.TextureFireball || math.Abs(trans.Rotation+math.Pi/4) > 1e-9 {
+		t.Errorf("fireball texture %q rotated %.3f, want %q at %.3f", sprite.Texture, trans.Rotation, components.TextureFireball, -math.Pi/4)
+	}
+}
+
+func TestCastBlinkMovesTowardTarget(t *testing.T) {
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc)
+
+	if _, err := svc.CastSpell(id, "blink", 100, 500); err != nil {
+		t.Fatal(err)
+	}
+	trans, _ := ecs.GetComponent[components.TransformComponent](svc.World, id)
+	if trans.X != 100 || trans.Y != 100+effectOf("blink").Amount {
+		t.Errorf("position after blink = (%.1f, %.1f)", trans.X, trans.Y)
+	}
+}
+
+func TestShippedSpellsMatchBuiltins(t *testing.T) {
+	spells, err := LoadSpells("../../../" + SpellDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spells) != len(components.SpellRegistry) {
+		t.Errorf("%d spell files, %d built-in spells", len(spells), len(components.SpellRegistry))
+	}
+	for _, spell := range spells {
+		if !reflect.DeepEqual(spell, components.SpellRegistry[spell.ID]) {
+			t.Errorf("%s.json differs from the built-in:\n%+v\n%+v", spell.ID, spell, components.SpellRegistry[spell.ID])
+		}
+	}
+}
+
+func TestSpellFromDataComposesEffects(t *testing.T) {
+	dir := t.TempDir()
+	writeSpell := func(name, body string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSpell("dash.json", `{"id": "dash", "name": "Dash", "cooldown": 4, "type": "instant", "effects": [
+		{"type": "teleport", "amount": 50},
+		{"type": "buff", "stat": "speed", "amount": 1.5, "duration": 3}
+	]}`)
+	spells, err := LoadSpells(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	components.RegisterSpells(spells)
+	t.Cleanup(func() {
+		delete(components.SpellRegistry, "dash")
+		components.SpellList = components.SpellList[:len(components.SpellList)-1]
+	})
+
+	svc, _ := newTestService(t)
+	id := newTestPlayer(t, svc)
+	ecs.AddComponent(svc.World, id, components.SpellbookComponent{UnlockedSpells: []string{"dash"}})
+	if _, err := svc.CastSpell(id, "dash", 500, 100); err != nil {
+		t.Fatal(err)
+	}
+	trans, _ := ecs.GetComponent[components.TransformComponent](svc.World, id)
+	if trans.X != 150 || systems.BuffMultiplier(svc.World, id, components.BuffSpeed) != 1.5 {
+		t.Errorf("after dash: x = %.1f, speed x%.2f; want 150, x1.50", trans.X, systems.BuffMultiplier(svc.World, id, components.BuffSpeed))
+	}
+
+	// Buffs run out
+	buffs := systems.NewStatusEffectSystem(svc.World)
+	buffs.Update(3)
+	if m := systems.BuffMultiplier(svc.World, id, components.BuffSpeed); m != 1 {
+		t.Errorf("speed x%.2f after the buff ended", m)
+	}
+
+	// A bad file fails the whole load
+	writeSpell("zap.json", `{"id": "zap", "effects": [{"type": "lightning"}]}`)
+	if _, err := LoadSpells(dir); err == nil {
+		t.Error("loaded a spell with an unknown effect")
+	}
+}
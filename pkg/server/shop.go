@@ -0,0 +1,49 @@
+package server
+
+import (
+	"log"
+
+	"henry/pkg/server/service"
+	"henry/pkg/server/systems"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+// openShop is the dialogue "shop" action: it shows the player the NPC's shop window.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) openShop(id, npc ecs.Entity, _ systems.DialogueAction) error {
+	packet, err := s.Service.ShopOpen(npc)
+	if err != nil {
+		return err
+	}
+	if player, ok := s.Players[id]; ok {
+		player.Send(protocol.Packet{Type: protocol.PacketShopOpen, Data: packet})
+	}
+	s.DialogueSystem.Close(id)
+	return nil
+}
+
+func (s *GameServer) handleShopTransaction(player *Player, req protocol.ShopTransactionPacket) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	var changes service.Changes
+	var err error
+	switch req.Action {
+	case "buy":
+		changes, err = s.Service.ShopBuy(player.EntityID, req.NPC, req.ItemID, req.Quantity)
+	case "sell":
+		changes, err = s.Service.ShopSell(player.EntityID, req.NPC, req.InstanceID, req.Quantity)
+	default:
+		err = service.ErrUnknownAction
+	}
+	if err != nil {
+		log.Printf("Player %s shop %s (%s%s x%d) failed: %v", player.Username, req.Action, req.ItemID, req.InstanceID, req.Quantity, err)
+		s.Notify(player, "Cannot "+req.Action+": "+err.Error())
+		if err == service.ErrNoShop || err == service.ErrShopClosed {
+			// Walked away, the vendor is gone or night fell: close the window
+			player.Send(protocol.Packet{Type: protocol.PacketShopOpen, Data: protocol.ShopOpenPacket{}})
+		}
+	}
+	s.applyChanges(player, changes)
+}
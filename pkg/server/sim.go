@@ -0,0 +1,234 @@
+package server
+
+import (
+	"image/color"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"time"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"henry/pkg/shared/world"
+)
+
+// SimConfig describes a headless simulation run (see RunHeadlessSim)
+type SimConfig struct {
+	Ticks    int   // Server ticks to simulate
+	MapSize  int   // Width and height of the generated map in tiles
+	Spawners int   // NPC spawners placed on the map
+	Bots     int   // Scripted player stand-ins
+	Seed     int64 // Map layout and bot behaviour
+	Verbose  bool  // Keep per-event server logs (hits, deaths, respawns)
+}
+
+// SimStats is the summary printed at the end of a simulation
+type SimStats struct {
+	Ticks         int
+	Wall          time.Duration
+	AvgTick       time.Duration
+	MaxTick       time.Duration
+	AvgBroadcast  time.Duration
+	Entities      int
+	PeakEntities  int
+	AliveNPCs     int
+	DeadNPCs      int
+	BotDeaths     int
+	SpawnRejected int
+	Panics        int
+}
+
+// Bots re-pick a destination after this many seconds (or on arrival)
+const simBotRetarget = 8.0
+
+// Bots swing at NPCs closer than this (px)
+const simBotAttackRange = 250.0
+
+// simBot is a scripted stand-in for a connected player
+type simBot struct {
+	id           ecs.Entity
+	goalX, goalY float64
+	timer        float64
+}
+
+// NewSimServer builds a server around a generated map. Nothing is read from data/.
+func NewSimServer(cfg SimConfig) *GameServer {
+	m := world.GenerateMap(cfg.MapSize, cfg.MapSize, cfg.Spawners, spawnableCharacterIDs(), cfg.Seed)
+	return newGameServer(map[int]*world.Map{0: m}, nil)
+}
+
+// RunHeadlessSim runs the game loop for cfg.Ticks ticks as fast as possible with no
+// network listeners, then logs and returns the stats. Meant for profiling and CI smoke runs.
+func RunHeadlessSim(cfg SimConfig) SimStats {
+	s := NewSimServer(cfg)
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
+	s.populateWorld()
+	bots := make([]*simBot, 0, cfg.Bots)
+	for i := 0; i < cfg.Bots; i++ {
+		bots = append(bots, s.spawnSimBot(i, rng))
+	}
+	log.Printf("Headless sim: %dx%d map, %d spawners, %d bots, %d ticks (seed %d)",
+		cfg.MapSize, cfg.MapSize, len(s.Maps[0].Spawners), len(bots), cfg.Ticks, cfg.Seed)
+
+	if !cfg.Verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	broadcastEvery := config.ServerTickRate / config.SnapshotRate
+	if broadcastEvery < 1 {
+		broadcastEvery = 1
+	}
+	dt := 1.0 / config.ServerTickRate
+
+	var stats SimStats
+	var broadcastTotal time.Duration
+	broadcasts := 0
+	start := time.Now()
+	for tick := 1; tick <= cfg.Ticks; tick++ {
+		s.withLock(func() {
+			for _, bot := range bots {
+				if s.driveSimBot(bot, dt, rng) {
+					stats.BotDeaths++
+				}
+			}
+		})
+
+		tickStart := time.Now()
+		s.Update()
+		elapsed := time.Since(tickStart)
+		stats.MaxTick = max(stats.MaxTick, elapsed)
+		stats.PeakEntities = max(stats.PeakEntities, s.SpawnLimiter.EntityCount())
+
+		// Build (but don't send) each bot's updates, as BroadcastState would
+		if tick%broadcastEvery == 0 {
+			broadcastStart := time.Now()
+			s.Mutex.Lock()
+			for _, bot := range bots {
+				s.NetworkSystem.PrepareUpdatesFor(bot.id)
+			}
+			s.Mutex.Unlock()
+			broadcastTotal += time.Since(broadcastStart)
+			broadcasts++
+		}
+	}
+	stats.Wall = time.Since(start)
+
+	log.SetOutput(os.Stderr)
+
+	stats.Ticks = cfg.Ticks
+	if cfg.Ticks > 0 {
+		stats.AvgTick = stats.Wall / time.Duration(cfg.Ticks)
+	}
+	if broadcasts > 0 {
+		stats.AvgBroadcast = broadcastTotal / time.Duration(broadcasts)
+	}
+	stats.Entities = s.SpawnLimiter.EntityCount()
+	for _, id := range ecs.Query[components.RespawnComponent](s.World) {
+		if respawn, _ := ecs.GetComponent[components.RespawnComponent](s.World, id); respawn.IsDead || ecs.HasComponent[components.DeadComponent](s.World, id) {
+			stats.DeadNPCs++
+		} else {
+			stats.AliveNPCs++
+		}
+	}
+	stats.AliveNPCs += len(ecs.Query[components.SpawnRegionComponent](s.World))
+	for _, n := range s.SpawnLimiter.Rejected {
+		stats.SpawnRejected += n
+	}
+	for name, n := range s.systemPanics {
+		stats.Panics += n
+		log.Printf("Headless sim: system %s panicked %d times", name, n)
+	}
+
+	simulated := time.Duration(float64(cfg.Ticks) * dt * float64(time.Second))
+	log.Printf("Headless sim done: %d ticks (%s simulated) in %s", stats.Ticks, simulated, stats.Wall.Round(time.Millisecond))
+	log.Printf("  tick avg %s, max %s (budget %s); snapshot avg %s for %d bots",
+		stats.AvgTick, stats.MaxTick, time.Second/config.ServerTickRate, stats.AvgBroadcast, len(bots))
+	log.Printf("  entities %d (peak %d), NPCs alive %d / dead %d, bot deaths %d, spawns rejected %d, panics %d",
+		stats.Entities, stats.PeakEntities, stats.AliveNPCs, stats.DeadNPCs, stats.BotDeaths, stats.SpawnRejected, stats.Panics)
+	return stats
+}
+
+// spawnSimBot adds a player-tagged entity with a starter weapon (alternating sword and bow)
+func (s *GameServer) spawnSimBot(n int, rng *rand.Rand) *simBot {
+	m := s.Maps[0]
+	tile := float64(config.TileSize)
+	tx, ty := m.RandomWalkable(rng)
+
+	id := s.World.NewEntity()
+	ecs.AddComponent(s.World, id, components.TransformComponent{X: float64(tx) * tile, Y: float64(ty) * tile})
+	ecs.AddComponent(s.World, id, components.PhysicsComponent{Speed: 3.0, Layer: components.LayerPlayer, Mask: components.MaskCharacter})
+	ecs.AddComponent(s.World, id, components.SpriteComponent{Width: 32, Height: 32, Color: color.RGBA{R: 0, G: 255, B: 0, A: 255}, CharType: "player"})
+	ecs.AddComponent(s.World, id, components.StatsComponent{MaxHealth: config.PlayerBaseHealth, CurrentHealth: config.PlayerBaseHealth})
+	ecs.AddComponent(s.World, id, components.InputComponent{})
+	ecs.AddComponent(s.World, id, components.StanceComponent{Stance: components.StanceWalk, Stamina: config.MaxStamina})
+	ecs.AddComponent(s.World, id, components.NameComponent{Name: "Bot"})
+	s.World.AddTags(id, components.TagPlayer)
+
+	weapon := "sword_starter"
+	if n%2 == 1 {
+		weapon = "bow_starter"
+	}
+	equip := components.EquipmentComponent{}
+	equip.Slots[components.SlotWeapon] = components.EquipmentSlot{ItemID: weapon}
+	ecs.AddComponent(s.World, id, equip)
+
+	return &simBot{id: id}
+}
+
+// driveSimBot sets the bot's input for this tick: wander between random points and
+// attack the nearest NPC in range. Dead bots are healed and moved to a fresh spot;
+// returns true when that happens. Assumes s.Mutex is LOCKED.
+func (s *GameServer) driveSimBot(bot *simBot, dt float64, rng *rand.Rand) bool {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, bot.id)
+	stats, _ := ecs.GetComponent[components.StatsComponent](s.World, bot.id)
+	if !ok || stats == nil {
+		return false
+	}
+
+	m := s.Maps[0]
+	tile := float64(config.TileSize)
+	died := false
+	if stats.CurrentHealth <= 0 {
+		tx, ty := m.RandomWalkable(rng)
+		trans.X, trans.Y = float64(tx)*tile, float64(ty)*tile
+		ecs.AddComponent(s.World, bot.id, *trans)
+		stats.CurrentHealth = stats.MaxHealth
+		ecs.AddComponent(s.World, bot.id, *stats)
+		bot.timer = 0
+		died = true
+	}
+
+	bot.timer -= dt
+	if bot.timer <= 0 || geom.Dist(trans.X, trans.Y, bot.goalX, bot.goalY) < tile {
+		tx, ty := m.RandomWalkable(rng)
+		bot.goalX, bot.goalY = float64(tx)*tile, float64(ty)*tile
+		bot.timer = simBotRetarget
+	}
+
+	input := components.InputComponent{
+		Up:    bot.goalY < trans.Y-4,
+		Down:  bot.goalY > trans.Y+4,
+		Left:  bot.goalX < trans.X-4,
+		Right: bot.goalX > trans.X+4,
+	}
+
+	nearest := simBotAttackRange
+	for _, npc := range ecs.QueryTagged(s.World, components.TagNPC) {
+		t, ok := ecs.GetComponent[components.TransformComponent](s.World, npc)
+		if !ok {
+			continue
+		}
+		if d := geom.Dist(trans.X, trans.Y, t.X, t.Y); d < nearest {
+			nearest = d
+			input.Attack = true
+			input.MouseX, input.MouseY = t.X+16, t.Y+16
+		}
+	}
+
+	ecs.AddComponent(s.World, bot.id, input)
+	return died
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+// handleSpectate starts, switches or stops spectating. GMs may watch anyone or fly freely;
+// everyone else may only watch arena matches, starting from an Arena Master.
+func (s *GameServer) handleSpectate(player *Player, req protocol.SpectatePacket) {
+	var err error
+	s.withLock(func() {
+		id := player.EntityID
+		if req.Action != "stop" && (s.ArenaSystem.MatchOf(id) != nil || s.ArenaSystem.IsQueued(id) || s.DuelSystem.DuelOf(id) != nil) {
+			err = errors.New("not while dueling or in the arena")
+			return
+		}
+		spec, spectating := ecs.GetComponent[components.SpectatorComponent](s.World, id)
+
+		switch req.Action {
+		case "follow":
+			if !player.IsAdmin && !(spectating && spec.ArenaOnly && s.inWatchedMatch(spec, req.TargetID)) {
+				err = errors.New("you can only watch arena matches")
+				return
+			}
+			err = s.SpectatorSystem.Start(id, req.TargetID, !player.IsAdmin)
+		case "free":
+			if !player.IsAdmin {
+				err = errors.New("free camera is for GMs")
+				return
+			}
+			err = s.SpectatorSystem.Start(id, 0, false)
+		case "arena":
+			if !spectating && !s.ArenaSystem.NearArenaMaster(id) {
+				err = errors.New("talk to an Arena Master to watch")
+				return
+			}
+			matches := s.ArenaSystem.Matches()
+			if len(matches) == 0 {
+				err = errors.New("no arena match is running")
+				return
+			}
+			err = s.SpectatorSystem.Start(id, matches[0].Players()[0], !player.IsAdmin)
+		case "stop":
+			s.SpectatorSystem.Stop(id)
+		default:
+			err = fmt.Errorf("unknown spectate action %q", req.Action)
+		}
+	})
+	if err != nil {
+		log.Printf("Player %s spectate %s failed: %v", player.Username, req.Action, err)
+		s.Notify(player, "Spectate: "+err.Error())
+	}
+}
+
+// inWatchedMatch reports whether target fights in the arena match the viewer is watching.
+// Assumes s.Mutex is LOCKED.
+func (s *GameServer) inWatchedMatch(spec *components.SpectatorComponent, target ecs.Entity) bool {
+	match := s.ArenaSystem.MatchOf(spec.TargetID)
+	return match != nil && match == s.ArenaSystem.MatchOf(target)
+}
+
+// sendSpectateState tells the player whom they are watching. Assumes s.Mutex is LOCKED.
+func (s *GameServer) sendSpectateState(id ecs.Entity) {
+	player, ok := s.Players[id]
+	if !ok {
+		return
+	}
+	data := protocol.SpectateStatePacket{}
+	if spec, ok := ecs.GetComponent[components.SpectatorComponent](s.World, id); ok {
+		data.Active = true
+		data.TargetID = spec.TargetID
+		if spec.TargetID != 0 {
+			data.TargetName = s.displayName(spec.TargetID)
+		}
+	}
+	player.Send(protocol.Packet{Type: protocol.PacketSpectateState, Data: data})
+}
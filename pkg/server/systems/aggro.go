@@ -0,0 +1,118 @@
+package systems
+
+import (
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+)
+
+// AggroState is what a player is fighting, for the client's music
+type AggroState struct {
+	InCombat bool // A hostile NPC is chasing or attacking the player
+	Boss     bool // One of them is a boss (has a loot table)
+}
+
+// AggroSystem raises a combat flag for each player hostile NPCs are after. The flag
+// stays up for config.CombatLingerTime after the last one gives up, so the music
+// doesn't flip between every swing.
+type AggroSystem struct {
+	World *ecs.World
+
+	// Called when a player's state changes
+	OnChange func(id ecs.Entity, state AggroState)
+	// Called when a hostile NPC starts chasing or attacking a player (boss intros)
+	OnEngage func(player, npc ecs.Entity)
+
+	states  map[ecs.Entity]AggroState
+	linger  map[ecs.Entity]float64 // Seconds left before the flag drops
+	engaged map[aggroPair]bool     // NPCs after players as of the last update
+}
+
+type aggroPair struct{ player, npc ecs.Entity }
+
+func NewAggroSystem(world *ecs.World) *AggroSystem {
+	return &AggroSystem{
+		World:   world,
+		states:  make(map[ecs.Entity]AggroState),
+		linger:  make(map[ecs.Entity]float64),
+		engaged: make(map[aggroPair]bool),
+	}
+}
+
+// State returns a player's current combat flag
+func (s *AggroSystem) State(id ecs.Entity) AggroState {
+	return s.states[id]
+}
+
+// Update collects who each hostile NPC is after and updates the flags
+func (s *AggroSystem) Update(dt float64) {
+	hunted := make(map[ecs.Entity]AggroState)
+	engaged := make(map[aggroPair]bool)
+	for _, id := range ecs.Query[components.AIComponent](s.World) {
+		ai, _ := ecs.GetComponent[components.AIComponent](s.World, id)
+		if ai.TargetID == 0 || (ai.State != "chase" && ai.State != "attack" && ai.State != "kite") {
+			continue
+		}
+		if !ecs.HasTag(s.World, ai.TargetID, components.TagPlayer) || !components.IsHostile(ai.Faction, components.FactionPlayer) {
+			continue
+		}
+		state := hunted[ai.TargetID]
+		state.InCombat = true
+		if _, boss := ecs.GetComponent[components.LootComponent](s.World, id); boss {
+			state.Boss = true
+		}
+		hunted[ai.TargetID] = state
+		engaged[aggroPair{ai.TargetID, id}] = true
+	}
+
+	previous := s.engaged
+	s.engaged = engaged
+	if s.OnEngage != nil {
+		for pair := range engaged {
+			if !previous[pair] {
+				s.OnEngage(pair.player, pair.npc)
+			}
+		}
+	}
+
+	for id, state := range hunted {
+		s.linger[id] = config.CombatLingerTime
+		s.set(id, state)
+	}
+	for id, left := range s.linger {
+		if _, ok := hunted[id]; ok {
+			continue
+		}
+		if left -= dt; left > 0 {
+			s.linger[id] = left
+			continue
+		}
+		delete(s.linger, id)
+		s.set(id, AggroState{})
+	}
+}
+
+// ForgetPlayer drops the flag of a disconnected player
+func (s *AggroSystem) ForgetPlayer(id ecs.Entity) {
+	delete(s.states, id)
+	delete(s.linger, id)
+	for pair := range s.engaged {
+		if pair.player == id {
+			delete(s.engaged, pair)
+		}
+	}
+}
+
+func (s *AggroSystem) set(id ecs.Entity, state AggroState) {
+	if s.states[id] == state {
+		return
+	}
+	if state == (AggroState{}) {
+		delete(s.states, id)
+	} else {
+		s.states[id] = state
+	}
+	if s.OnChange != nil {
+		s.OnChange(id, state)
+	}
+}
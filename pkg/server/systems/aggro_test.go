@@ -0,0 +1,63 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+)
+
+func TestAggroFlagLingersAndSpotsBosses(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewAggroSystem(w)
+	var changes []AggroState
+	s.OnChange = func(id ecs.Entity, state AggroState) { changes = append(changes, state) }
+	engaged := 0
+	s.OnEngage = func(player, npc ecs.Entity) { engaged++ }
+
+	player := w.NewEntity()
+	w.AddTags(player, components.TagPlayer)
+	goblin := w.NewEntity()
+	ecs.AddComponent(w, goblin, components.AIComponent{Faction: components.FactionMonsters, State: "chase", TargetID: player})
+	guard := w.NewEntity()
+	ecs.AddComponent(w, guard, components.AIComponent{Faction: components.FactionGuards, State: "chase", TargetID: player})
+
+	s.Update(0.1)
+	if got := s.State(player); !got.InCombat || got.Boss {
+		t.Fatalf("chased by a goblin: %+v", got)
+	}
+
+	warlord := w.NewEntity()
+	ecs.AddComponent(w, warlord, components.AIComponent{Faction: components.FactionMonsters, State: "attack", TargetID: player})
+	ecs.AddComponent(w, warlord, components.LootComponent{Table: []components.LootEntry{{ItemID: "coin_gold", Quantity: 1, Chance: 1}}})
+	s.Update(0.1)
+	if !s.State(player).Boss {
+		t.Fatal("boss not spotted")
+	}
+	if engaged != 2 {
+		t.Errorf("%d engagements, want one each for the goblin and the warlord", engaged)
+	}
+
+	// Everyone gives up: the flag holds for a while, then drops once
+	w.RemoveEntity(goblin)
+	w.RemoveEntity(warlord)
+	s.Update(config.CombatLingerTime / 2)
+	if !s.State(player).InCombat {
+		t.Fatal("combat flag dropped straight away")
+	}
+	s.Update(config.CombatLingerTime)
+	s.Update(config.CombatLingerTime)
+	if s.State(player).InCombat {
+		t.Fatal("combat flag never dropped")
+	}
+	want := []AggroState{{InCombat: true}, {InCombat: true, Boss: true}, {}}
+	if len(changes) != len(want) {
+		t.Fatalf("changes %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+}
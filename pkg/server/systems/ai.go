@@ -1,9 +1,12 @@
 package systems
 
 import (
-	"henry/pkg/items"
+	"container/heap"
+	"henry/pkg/behavior"
 	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
 	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
 	"henry/pkg/shared/world"
 	"math"
 	"math/rand"
@@ -12,30 +15,39 @@ import (
 type AISystem struct {
 	World *ecs.World
 	Maps  map[int]*world.Map
+	Clock *world.Clock // Drives NPC schedules
+	Grid  *SpatialGrid // Finds the entities near an NPC (nil scans the world)
+
+	graphs map[*world.Map]*clusterGraph // Cached pathfinding graphs of big maps (see hpa.go)
 }
 
-func NewAISystem(world *ecs.World, maps map[int]*world.Map) *AISystem {
+func NewAISystem(world *ecs.World, maps map[int]*world.Map, clock *world.Clock) *AISystem {
 	return &AISystem{
 		World: world,
 		Maps:  maps,
+		Clock: clock,
 	}
 }
 
+// Update ticks every NPC's behavior tree (behavior.Default unless its character has
+// one), which steers it through its InputComponent like a player's keys would
 func (s *AISystem) Update(dt float64) {
-	entities := ecs.Query[components.AIComponent](s.World)
-
-	for _, id := range entities {
-		ai, _ := ecs.GetComponent[components.AIComponent](s.World, id)
-		input, _ := ecs.GetComponent[components.InputComponent](s.World, id)
-		transform, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
-
-		if ai == nil || input == nil || transform == nil {
-			continue
+	ecs.Each3(s.World, func(id ecs.Entity, ai *components.AIComponent, input *components.InputComponent, transform *components.TransformComponent) {
+		activity := ai.Activity
+		if s.Clock != nil {
+			ai.Activity = components.ActivityAt(ai.Schedule, s.Clock.Hour)
+		}
+		if ai.Type == "dummy" || ai.Type == "static" {
+			// Training dummies and town NPCs stand still, but keep their hours (shops)
+			if ai.Activity != activity {
+				ecs.AddComponent(s.World, id, *ai)
+			}
+			return
 		}
 
 		currentMap, ok := s.Maps[transform.Z]
 		if !ok {
-			continue // No map for this entity?
+			return // No map for this entity?
 		}
 
 		// Reset Inputs Frame
@@ -45,250 +57,178 @@ func (s *AISystem) Update(dt float64) {
 		input.Right = false
 		input.Attack = false
 
-		// Check Target Validity
-		if ai.TargetID != 0 {
-			targetTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, ai.TargetID)
-			if targetTrans == nil || targetTrans.Z != transform.Z { // Verify Target is on same Z
-				// Target dead or gone or different level
-				ai.TargetID = 0
-				ai.State = "wander"
-			} else {
-				// Use Dynamic Center
-				selfX, selfY := s.getEntityCenter(id)
-				targetX, targetY := s.getEntityCenter(ai.TargetID)
-
-				// Dist Logic (Center to Center)
-				dx := targetX - selfX
-				dy := targetY - selfY
-				dist := math.Sqrt(dx*dx + dy*dy)
-
-				// Face Target (Input uses Transform, maybe update to Center too? Client handles offsets usually)
-				// Keeping input raw for now, or use target center.
-				input.MouseX = targetX
-				input.MouseY = targetY
-
-				// Use Multi-Ray LOS (Function adds offsets internally)
-				hasLOS := s.HasLineOfSight(currentMap, selfX, selfY, targetX, targetY)
-
-				// Determine Attack Range from Equipment
-				attackRange := 50.0 // Default Melee
-				weaponType := "melee"
-				if equip, ok := ecs.GetComponent[components.EquipmentComponent](s.World, id); ok {
-					if weaponID := equip.Slots[components.SlotWeapon].ItemID; weaponID != "" {
-						if item, exists := items.Get(weaponID); exists && item.WeaponStats != nil {
-							attackRange = item.WeaponStats.Range
-							if attackRange > 60 {
-								weaponType = "ranged"
-							}
-							attackRange *= 0.8
-						}
-					}
-				}
+		tree := ai.Behavior
+		if tree.Empty() {
+			tree = behavior.Default
+		}
+		agent := &aiAgent{id: id, ai: ai, input: input, transform: transform, m: currentMap, dt: dt}
+		tree.Tick(func(leaf string) behavior.Status { return s.runLeaf(agent, leaf) })
 
-				// Attack Logic
-				// Ranged: Needs Range AND LOS
-				// Melee: Needs Range (LOS implied by close range usually, but strictly required for corners)
-				canAttack := dist <= attackRange
-				if weaponType == "ranged" && !hasLOS {
-					canAttack = false // Can't shoot through walls
-				}
+		// Save components back
+		ecs.AddComponent(s.World, id, *ai)
+		ecs.AddComponent(s.World, id, *input)
+	}, ecs.Without[components.DeadComponent]())
+}
 
-				// LEASH CHECK (Global for TargetID != 0)
-				// Must override attack logic to prevent infinite kiting/stuck rangers
-				dxSpawn := transform.X - ai.SpawnX
-				dySpawn := transform.Y - ai.SpawnY
-				if dxSpawn*dxSpawn+dySpawn*dySpawn > ai.LeashRange*ai.LeashRange {
-					// Too far! Go home.
-					ai.State = "return"
-					ai.TargetID = 0
-					ai.Path = nil // Reset path
-					// log.Printf("Entity %d Leashed! Pos: %.1f,%.1f Spawn: %.1f,%.1f DistSq: %.1f",
-					// 	id, transform.X, transform.Y, ai.SpawnX, ai.SpawnY, dxSpawn*dxSpawn+dySpawn*dySpawn)
-
-					// Critical: Save state before returning!
-					s.World.AddComponent(id, *ai)
-					s.World.AddComponent(id, *input)
-					return // Skip rest of frame
-				}
+func (s *AISystem) factionOf(id ecs.Entity) int {
+	return FactionOf(s.World, id)
+}
 
-				if canAttack {
-					// ATTACK
-					ai.State = "attack"
-					input.Attack = true
-				} else {
-					// CHASE
-					ai.State = "chase"
-					ai.PathTimer -= dt
-
-					var moveTargetX, moveTargetY float64
-
-					if hasLOS {
-						// Direct Chase - Clear path data
-						ai.Path = nil
-						moveTargetX = targetTrans.X
-						moveTargetY = targetTrans.Y
-					} else {
-						// Blocked! Pathfind
-
-						// Recalculate path if timer expired or no path
-						if ai.PathTimer <= 0 || len(ai.Path) == 0 {
-							// Calculate new path
-							ai.Path = s.FindPath(currentMap, selfX, selfY, targetX, targetY)
-							ai.PathTimer = 0.5 // Refresh path every 0.5s to track moving target
-						}
-
-						// Follow Path
-						if len(ai.Path) > 0 {
-							moveTargetX = ai.Path[0][0]
-							moveTargetY = ai.Path[0][1]
-
-							// Check if reached node (within 10px)
-							dx := moveTargetX - transform.X
-							dy := moveTargetY - transform.Y
-							if dx*dx+dy*dy < 100.0 {
-								// Node reached, advance
-								ai.Path = ai.Path[1:]
-								if len(ai.Path) > 0 {
-									moveTargetX = ai.Path[0][0]
-									moveTargetY = ai.Path[0][1]
-								}
-							}
-						} else {
-							// No path found? Direct chase as failover
-							moveTargetX = targetTrans.X
-							moveTargetY = targetTrans.Y
-						}
-					}
-
-					// Calculate Vector to MoveTarget
-					dx = moveTargetX - transform.X
-					dy = moveTargetY - transform.Y
-					distToNode := math.Sqrt(dx*dx + dy*dy)
-
-					if distToNode > 0 {
-						dx /= distToNode
-						dy /= distToNode
-					}
-
-					// Apply Movement Inputs
-					if math.Abs(dx) > math.Abs(dy) {
-						if dx > 0 {
-							input.Right = true
-						} else {
-							input.Left = true
-						}
-						// Smoothing
-						if dy > 0.5 {
-							input.Down = true
-						} else if dy < -0.5 {
-							input.Up = true
-						}
-					} else {
-						if dy > 0 {
-							input.Down = true
-						} else {
-							input.Up = true
-						}
-						if dx > 0.5 {
-							input.Right = true
-						} else if dx < -0.5 {
-							input.Left = true
-						}
-					}
-				}
-			}
-		} else if ai.State == "return" {
-			// RETURNING HOME
-			dx := ai.SpawnX - transform.X
-			dy := ai.SpawnY - transform.Y
-			distSq := dx*dx + dy*dy
-
-			// Safely back within range? (e.g. within 50px of spawn)
-			// This prevents them from walking ALL the way back to the exact pixel
-			if distSq < 50*50 {
-				// Home reached (enough)
-				ai.State = "wander"
-				ai.StateTimer = 2.0 // Chill for a bit
-			} else {
-				// Move towards home
-				// Simple direct movement for now, improve with pathfinding if needed
-				// Actually, should reuse pathfinding to avoid getting stuck on return
-				ai.PathTimer -= dt
-				if ai.PathTimer <= 0 || len(ai.Path) == 0 {
-					ai.Path = s.FindPath(currentMap, transform.X, transform.Y, ai.SpawnX, ai.SpawnY)
-					ai.PathTimer = 1.0
-					// log.Printf("NPC %d Returning. Pos: %.1f,%.1f -> Spawn: %.1f,%.1f. DistSq: %.1f, PathLen: %d",
-					// 	id, transform.X, transform.Y, ai.SpawnX, ai.SpawnY, distSq, pathLen)
-				}
+// findHostileTarget returns the closest living hostile within AggroRange (0 if none).
+// Sneaking players are only noticed within a fraction of the range.
+func (s *AISystem) findHostileTarget(id ecs.Entity, ai *components.AIComponent, transform *components.TransformComponent) ecs.Entity {
+	var best ecs.Entity
+	var bestDistSq float64
+	for _, otherID := range NearbyRadius[components.StatsComponent](s.World, s.Grid, transform.Z, transform.X, transform.Y, ai.AggroRange) {
+		if otherID == id || !components.IsHostile(ai.Faction, s.factionOf(otherID)) || IsSpectating(s.World, otherID) || IsInvisible(s.World, otherID) {
+			continue
+		}
+		otherTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, otherID)
+		otherStats, _ := ecs.GetComponent[components.StatsComponent](s.World, otherID)
+		if otherTrans == nil || otherStats == nil || otherStats.CurrentHealth <= 0 || otherTrans.Z != transform.Z {
+			continue
+		}
+		aggroRange := ai.AggroRange
+		if stance, ok := ecs.GetComponent[components.StanceComponent](s.World, otherID); ok && stance.Stance == components.StanceSneak {
+			aggroRange *= config.SneakAggroMultiplier
+		}
+		dx := otherTrans.X - transform.X
+		dy := otherTrans.Y - transform.Y
+		distSq := dx*dx + dy*dy
+		if distSq < aggroRange*aggroRange && (best == 0 || distSq < bestDistSq) {
+			bestDistSq = distSq
+			best = otherID
+		}
+	}
+	return best
+}
 
-				var moveTargetX, moveTargetY float64
-				if len(ai.Path) > 0 {
-					moveTargetX = ai.Path[0][0]
-					moveTargetY = ai.Path[0][1]
-
-					mdx := moveTargetX - transform.X
-					mdy := moveTargetY - transform.Y
-					// Increase tolerance to avoid orbiting (Speed is ~6, so < 16 was too tight)
-					if mdx*mdx+mdy*mdy < 100.0 { // < 10px distance
-						ai.Path = ai.Path[1:]
-						if len(ai.Path) > 0 {
-							moveTargetX = ai.Path[0][0]
-							moveTargetY = ai.Path[0][1]
-						}
-					}
-				} else {
-					// Fallback: Direct line
-					moveTargetX = ai.SpawnX
-					moveTargetY = ai.SpawnY
-				}
+// shouldFlee reports whether the NPC's health dropped below its flee threshold
+func (s *AISystem) shouldFlee(id ecs.Entity, ai *components.AIComponent) bool {
+	if ai.FleeThreshold <= 0 {
+		return false
+	}
+	stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id)
+	if stats == nil || stats.MaxHealth <= 0 {
+		return false
+	}
+	return stats.CurrentHealth/stats.MaxHealth < ai.FleeThreshold
+}
 
-				// Move Logic
-				finalDx := moveTargetX - transform.X
-				finalDy := moveTargetY - transform.Y
-				distFinal := math.Sqrt(finalDx*finalDx + finalDy*finalDy)
-				if distFinal > 0 {
-					finalDx /= distFinal
-					finalDy /= distFinal
-				}
+// startFlee picks the nearest same-faction ally (or the spawn point) and calls for help
+func (s *AISystem) startFlee(id ecs.Entity, ai *components.AIComponent, transform *components.TransformComponent) {
+	ai.State = "flee"
+	ai.HasFled = true
+	ai.Path = nil
+	ai.FleeX, ai.FleeY = ai.SpawnX, ai.SpawnY
+
+	const rallyRange = 400.0 // Only run to allies that are reasonably close
+	bestDistSq := rallyRange * rallyRange
+	for _, otherID := range NearbyRadius[components.AIComponent](s.World, s.Grid, transform.Z, transform.X, transform.Y, rallyRange) {
+		if otherID == id {
+			continue
+		}
+		other, _ := ecs.GetComponent[components.AIComponent](s.World, otherID)
+		otherTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, otherID)
+		if other == nil || otherTrans == nil || other.Faction != ai.Faction || other.State == "flee" || otherTrans.Z != transform.Z {
+			continue
+		}
+		dx := otherTrans.X - transform.X
+		dy := otherTrans.Y - transform.Y
+		if distSq := dx*dx + dy*dy; distSq < bestDistSq {
+			bestDistSq = distSq
+			ai.FleeX, ai.FleeY = otherTrans.X, otherTrans.Y
+		}
+	}
 
-				if math.Abs(finalDx) > math.Abs(finalDy) {
-					if finalDx > 0 {
-						input.Right = true
-					} else {
-						input.Left = true
-					}
-				} else {
-					if finalDy > 0 {
-						input.Down = true
-					} else {
-						input.Up = true
-					}
-				}
-			}
+	// Save state before rallying so CallForHelp sees us as fleeing
+	ecs.AddComponent(s.World, id, *ai)
+	s.CallForHelp(id, ai.TargetID)
+}
 
-		} else {
-			// Wander Logic
-
-			// LEASH CHECK (Wander)
-			dxSpawn := transform.X - ai.SpawnX
-			dySpawn := transform.Y - ai.SpawnY
-			if dxSpawn*dxSpawn+dySpawn*dySpawn > ai.LeashRange*ai.LeashRange {
-				ai.State = "return"
-				ai.TargetID = 0
-				ai.Path = nil
-			} else {
-				ai.StateTimer -= dt
-				if ai.StateTimer <= 0 {
-					s.pickNewState(ai)
-				}
-				s.applyWanderState(ai, input, transform)
+// CallForHelp makes idle same-faction NPCs within the victim's HelpRadius target the attacker
+func (s *AISystem) CallForHelp(victimID, attackerID ecs.Entity) {
+	victimAI, _ := ecs.GetComponent[components.AIComponent](s.World, victimID)
+	victimTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, victimID)
+	if victimAI == nil || victimTrans == nil || victimAI.HelpRadius <= 0 || attackerID == 0 {
+		return
+	}
+
+	// Never rally against our own faction (Players are faction 0 and have no AI)
+	attackerFaction := 0
+	if attackerAI, ok := ecs.GetComponent[components.AIComponent](s.World, attackerID); ok {
+		attackerFaction = attackerAI.Faction
+	}
+	if attackerFaction == victimAI.Faction {
+		return
+	}
+
+	for _, allyID := range NearbyRadius[components.AIComponent](s.World, s.Grid, victimTrans.Z, victimTrans.X, victimTrans.Y, victimAI.HelpRadius) {
+		if allyID == victimID || allyID == attackerID {
+			continue
+		}
+		ally, _ := ecs.GetComponent[components.AIComponent](s.World, allyID)
+		allyTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, allyID)
+		if ally == nil || allyTrans == nil || ally.Faction != victimAI.Faction || ally.TargetID != 0 || ally.State == "flee" {
+			continue
+		}
+		if allyTrans.Z != victimTrans.Z {
+			continue
+		}
+		if !geom.Within(allyTrans.X, allyTrans.Y, victimTrans.X, victimTrans.Y, victimAI.HelpRadius) {
+			continue
+		}
+
+		ally.TargetID = attackerID
+		ally.State = "chase"
+		ally.Path = nil
+		ecs.AddComponent(s.World, allyID, *ally)
+	}
+}
+
+// moveTowards steers along an A* path to a destination (used by flee)
+func (s *AISystem) moveTowards(ai *components.AIComponent, input *components.InputComponent, transform *components.TransformComponent, m *world.Map, destX, destY, dt float64) {
+	ai.PathTimer -= dt
+	if ai.PathTimer <= 0 || len(ai.Path) == 0 {
+		ai.Path = s.FindPath(m, transform.X, transform.Y, destX, destY)
+		ai.PathTimer = 1.0
+	}
+
+	moveTargetX, moveTargetY := destX, destY
+	if len(ai.Path) > 0 {
+		moveTargetX = ai.Path[0][0]
+		moveTargetY = ai.Path[0][1]
+
+		mdx := moveTargetX - transform.X
+		mdy := moveTargetY - transform.Y
+		if mdx*mdx+mdy*mdy < 100.0 { // < 10px distance
+			ai.Path = ai.Path[1:]
+			if len(ai.Path) > 0 {
+				moveTargetX = ai.Path[0][0]
+				moveTargetY = ai.Path[0][1]
 			}
 		}
+	}
 
-		// Save components back
-		s.World.AddComponent(id, *ai)
-		s.World.AddComponent(id, *input)
+	dx := moveTargetX - transform.X
+	dy := moveTargetY - transform.Y
+	if math.Abs(dx) > math.Abs(dy) {
+		if dx > 0 {
+			input.Right = true
+		} else {
+			input.Left = true
+		}
+	} else {
+		if dy > 0 {
+			input.Down = true
+		} else {
+			input.Up = true
+		}
 	}
+
+	// Face where we're running
+	input.MouseX = moveTargetX
+	input.MouseY = moveTargetY
 }
 
 func (s *AISystem) pickNewState(ai *components.AIComponent) {
@@ -340,11 +280,15 @@ func (s *AISystem) getEntityCenter(id ecs.Entity) (float64, float64) {
 	return trans.X + w/2, trans.Y + h/2
 }
 
-// HasLineOfSight checks if a straight line between start and end is clear of obstacles
+// HasLineOfSight checks if a straight line between two entity positions (transforms, like
+// path nodes) is clear of obstacles.
 // Checks multiple rays to ensure the entity's width allows passage
 func (s *AISystem) HasLineOfSight(m *world.Map, x1, y1, x2, y2 float64) bool {
-	// Offsets for approx 32x32 entity
-	// We check the Center and the 4 corners (shrunk slightly to 24x24 box to avoid grazing)
+	// Rays run between body centers, in the middle of the entity's tile cell
+	half := float64(config.TileSize) / 2
+	x1, y1, x2, y2 = x1+half, y1+half, x2+half, y2+half
+
+	// We check the Center and the 4 corners (of the 24x24 collider)
 	offsets := [][2]float64{
 		{0, 0},     // Center
 		{-12, -12}, // TL
@@ -365,49 +309,30 @@ func (s *AISystem) HasLineOfSight(m *world.Map, x1, y1, x2, y2 float64) bool {
 }
 
 func (s *AISystem) castRay(m *world.Map, x1, y1, x2, y2 float64) bool {
-	dist := math.Sqrt((x2-x1)*(x2-x1) + (y2-y1)*(y2-y1))
-	steps := int(dist / 8.0) // Check every 8 pixels
-
-	if steps == 0 {
-		return true
-	}
-
-	dx := (x2 - x1) / float64(steps)
-	dy := (y2 - y1) / float64(steps)
-
-	cx, cy := x1, y1
-	for i := 0; i < steps; i++ {
-		cx += dx
-		cy += dy
-
-		tx := int(cx / 32.0)
-		ty := int(cy / 32.0)
+	// Check every 8 pixels
+	return geom.March(x1, y1, x2, y2, 8, func(cx, cy float64) bool {
+		tx := int(math.Floor(cx / float64(config.TileSize)))
+		ty := int(math.Floor(cy / float64(config.TileSize)))
 		if tx >= 0 && tx < m.Width && ty >= 0 && ty < m.Height {
-			tile := m.Tiles[ty][tx]
-			if tile.Type.IsSolid() {
-				return false
-			}
-			if m.Objects[ty][tx] > 0 {
+			if m.Tiles[ty][tx].Type.IsSolid() || world.IsSolidObject(m.Objects[ty][tx]) {
 				return false
 			}
 		}
-	}
-	return true
-}
-
-type Node struct {
-	X, Y    int
-	G, H, F float64
-	Parent  *Node
+		return true
+	})
 }
 
-// FindPath finds a path from start to end using A* Algorithm
+// FindPath finds a path from start to end with A* over map tiles. Positions and the
+// returned nodes are transforms: a node puts the entity's body in the middle of a tile.
+// Long trips on big maps go through the cluster graph (see hpa.go) instead of the
+// whole grid.
 func (s *AISystem) FindPath(m *world.Map, startX, startY, endX, endY float64) [][]float64 {
-	// Grid Coordinates
-	startTX := int((startX + 16) / 32.0)
-	startTY := int((startY + 16) / 32.0)
-	endTX := int((endX + 16) / 32.0)
-	endTY := int((endY + 16) / 32.0)
+	// Grid Coordinates (the tile under the body center)
+	tileSize := float64(config.TileSize)
+	startTX := int(math.Floor(startX/tileSize + 0.5))
+	startTY := int(math.Floor(startY/tileSize + 0.5))
+	endTX := int(math.Floor(endX/tileSize + 0.5))
+	endTY := int(math.Floor(endY/tileSize + 0.5))
 
 	if startTX == endTX && startTY == endTY {
 		return nil
@@ -418,150 +343,233 @@ func (s *AISystem) FindPath(m *world.Map, startX, startY, endX, endY float64) []
 		return nil
 	}
 	// Target blockage check (Basic)
-	if m.Tiles[endTY][endTX].Type.IsSolid() || m.Objects[endTY][endTX] > 0 {
+	if !walkable(m, endTX, endTY) {
 		return nil
 	}
 
-	openList := make(map[int]*Node)
-	closedList := make(map[int]bool)
+	// Walled off (an island, a sealed room): no search would get there
+	if hasClusters(m) && !s.pathGraph(m).connected(m, startTX, startTY, endTX, endTY) {
+		return nil
+	}
 
-	startNode := &Node{X: startTX, Y: startTY, G: 0, H: 0, F: 0}
-	openList[startTY*m.Width+startTX] = startNode
+	var tiles [][2]int
+	if useClusters(m, startTX, startTY, endTX, endTY) {
+		tiles = s.pathGraph(m).findPath(m, startTX, startTY, endTX, endTY)
+	}
+	if tiles == nil {
+		tiles, _ = searchTiles(m, startTX, startTY, endTX, endTY, tileRect{0, 0, m.Width, m.Height}, MaxFlatSearch)
+	}
+	if tiles == nil {
+		return nil
+	}
 
-	var finalNode *Node
+	// Body centered on each tile
+	rawPath := make([][]float64, len(tiles))
+	for i, t := range tiles {
+		rawPath[i] = []float64{float64(t[0]) * tileSize, float64(t[1]) * tileSize}
+	}
 
-	// Directions: Cardinal + Diagonal
-	// Up, Down, Left, Right, TL, TR, BL, BR
-	dirs := [][2]int{
-		{0, -1}, {0, 1}, {-1, 0}, {1, 0},
-		{-1, -1}, {1, -1}, {-1, 1}, {1, 1},
+	// String Pulling (Smoothing)
+	if len(rawPath) > 2 {
+		return s.stringPull(m, rawPath)
+	}
+	if len(rawPath) > 1 {
+		return rawPath[1:] // Skip start node
 	}
+	return rawPath
+}
 
-	for len(openList) > 0 {
-		// Get node with lowest F
-		var curr *Node
-		var currIdx int
-		minF := math.MaxFloat64
+// walkable reports whether pathfinding may step on a tile
+func walkable(m *world.Map, tx, ty int) bool {
+	return tx >= 0 && tx < m.Width && ty >= 0 && ty < m.Height &&
+		!m.Tiles[ty][tx].Type.IsSolid() && !world.IsSolidObject(m.Objects[ty][tx])
+}
 
-		for idx, node := range openList {
-			if node.F < minF {
-				minF = node.F
-				curr = node
-				currIdx = idx
-			}
-		}
+// tileRect bounds a grid search: tiles from (minX, minY) up to but excluding (maxX, maxY)
+type tileRect struct {
+	minX, minY, maxX, maxY int
+}
 
-		delete(openList, currIdx)
-		closedList[currIdx] = true
+func (r tileRect) contains(tx, ty int) bool {
+	return tx >= r.minX && tx < r.maxX && ty >= r.minY && ty < r.maxY
+}
 
-		// Found Target?
-		if curr.X == endTX && curr.Y == endTY {
-			finalNode = curr
-			break
-		}
+// Up, Down, Left, Right, then the diagonals TL, TR, BL, BR
+var pathDirs = [8][2]int{
+	{0, -1}, {0, 1}, {-1, 0}, {1, 0},
+	{-1, -1}, {1, -1}, {-1, 1}, {1, 1},
+}
 
-		// Neighbors
-		for i, d := range dirs {
-			nx, ny := curr.X+d[0], curr.Y+d[1]
+// searchTiles runs A* between two tiles without leaving bounds, expanding at most limit
+// tiles (0 = no limit). It returns the tiles walked (start first) and their cost, or nil
+// if there is no way through.
+func searchTiles(m *world.Map, startX, startY, endX, endY int, bounds tileRect, limit int) ([][2]int, float64) {
+	if !bounds.contains(endX, endY) {
+		return nil, 0
+	}
+	search := exploreTiles(m, startX, startY, bounds, &[2]int{endX, endY}, limit)
+	if search == nil {
+		return nil, 0
+	}
+	return search.pathTo(endX, endY)
+}
 
-			// Bounds
-			if nx < 0 || nx >= m.Width || ny < 0 || ny >= m.Height {
-				continue
-			}
+// tileSearch holds the costs and parents of a finished grid search
+type tileSearch struct {
+	bounds tileRect
+	cost   []float64
+	parent []int
+	closed []bool
+}
 
-			idx := ny*m.Width + nx
-			if closedList[idx] {
-				continue
-			}
+// exploreTiles searches outward from a tile without leaving bounds: A* towards goal, or
+// Dijkstra to every reachable tile if goal is nil. It stops after expanding limit tiles
+// (0 = no limit). Returns nil if start is out of bounds.
+func exploreTiles(m *world.Map, startX, startY int, bounds tileRect, goal *[2]int, limit int) *tileSearch {
+	if !bounds.contains(startX, startY) {
+		return nil
+	}
+	size := (bounds.maxX - bounds.minX) * (bounds.maxY - bounds.minY)
+	t := &tileSearch{
+		bounds: bounds,
+		cost:   make([]float64, size),
+		parent: make([]int, size),
+		closed: make([]bool, size),
+	}
+	for i := range t.cost {
+		t.cost[i] = math.MaxFloat64
+		t.parent[i] = -1
+	}
+	heuristic := func(tx, ty int) float64 {
+		if goal == nil {
+			return 0
+		}
+		return math.Hypot(float64(tx-goal[0]), float64(ty-goal[1])) * world.MinMoveCost // Euclidean, all road
+	}
+	goalIdx := -1
+	if goal != nil {
+		goalIdx = t.index(goal[0], goal[1])
+	}
+
+	start := t.index(startX, startY)
+	t.cost[start] = 0
+	open := &pathHeap{{index: start, f: heuristic(startX, startY)}}
+	expanded := 0
+	for open.Len() > 0 {
+		curr := heap.Pop(open).(pathItem).index
+		if t.closed[curr] {
+			continue // Stale entry, already expanded with a lower cost
+		}
+		if limit > 0 && expanded == limit {
+			break // Gave up: the goal stays unreached
+		}
+		expanded++
+		t.closed[curr] = true
+		if curr == goalIdx {
+			break
+		}
 
-			// Collision Check
-			if m.Tiles[ny][nx].Type.IsSolid() || m.Objects[ny][nx] > 0 {
+		cx, cy := t.tile(curr)
+		for i, d := range pathDirs {
+			nx, ny := cx+d[0], cy+d[1]
+			if !bounds.contains(nx, ny) || !walkable(m, nx, ny) {
 				continue
 			}
-
-			// Diagonal Safety: Check adjacent cardinals
-			// If moving diagonally, both cardinals must be free to avoid cutting corners
-			if i >= 4 { // Diagonals are indices 4-7
-				// e.g., TL (-1, -1) needs (-1, 0) and (0, -1) free
-				c1x, c1y := curr.X+d[0], curr.Y
-				c2x, c2y := curr.X, curr.Y+d[1]
-
-				// Using simple existence checks - improve if strict validation needed
-				blocked := false
-				if c1x >= 0 && c1x < m.Width && c1y >= 0 && c1y < m.Height {
-					if m.Tiles[c1y][c1x].Type.IsSolid() || m.Objects[c1y][c1x] > 0 {
-						blocked = true
-					}
-				}
-				if c2x >= 0 && c2x < m.Width && c2y >= 0 && c2y < m.Height {
-					if m.Tiles[c2y][c2x].Type.IsSolid() || m.Objects[c2y][c2x] > 0 {
-						blocked = true
-					}
-				}
-				if blocked {
-					continue
-				}
+			next := t.index(nx, ny)
+			if t.closed[next] {
+				continue
 			}
 
-			// Costs
+			// Diagonals need both cardinals free so they don't cut corners
 			moveCost := 1.0
 			if i >= 4 {
+				if !walkable(m, nx, cy) || !walkable(m, cx, ny) {
+					continue
+				}
 				moveCost = 1.414 // Sqrt(2) for diagonals
 			}
+			// Distance weighted by the terrain of both tiles (roads cheap, sand dear)
+			moveCost *= (m.MoveCostAt(cx, cy) + m.MoveCostAt(nx, ny)) / 2
 
-			gScore := curr.G + moveCost
-			hScore := math.Sqrt(float64((nx-endTX)*(nx-endTX) + (ny-endTY)*(ny-endTY))) // Euclidean
-			fScore := gScore + hScore
-
-			if existing, exists := openList[idx]; exists {
-				if gScore < existing.G {
-					existing.G = gScore
-					existing.F = fScore
-					existing.Parent = curr
-				}
-			} else {
-				node := &Node{X: nx, Y: ny, G: gScore, H: hScore, F: fScore, Parent: curr}
-				openList[idx] = node
+			if cost := t.cost[curr] + moveCost; cost < t.cost[next] {
+				t.cost[next] = cost
+				t.parent[next] = curr
+				heap.Push(open, pathItem{index: next, f: cost + heuristic(nx, ny)})
 			}
 		}
 	}
+	return t
+}
 
-	if finalNode != nil {
-		// Reconstruct Path
-		var rawPath [][]float64
-		curr := finalNode
-		for curr != nil {
-			// Center of tile
-			rawPath = append([][]float64{{float64(curr.X)*32 + 16, float64(curr.Y)*32 + 16}}, rawPath...)
-			curr = curr.Parent
-		}
+func (t *tileSearch) index(tx, ty int) int {
+	return (ty-t.bounds.minY)*(t.bounds.maxX-t.bounds.minX) + (tx - t.bounds.minX)
+}
 
-		// String Pulling (Smoothing)
-		if len(rawPath) > 2 {
-			return s.stringPull(m, rawPath)
-		}
-		if len(rawPath) > 1 {
-			return rawPath[1:] // Skip start node
-		}
-		return rawPath
+func (t *tileSearch) tile(i int) (int, int) {
+	width := t.bounds.maxX - t.bounds.minX
+	return t.bounds.minX + i%width, t.bounds.minY + i/width
+}
+
+// pathTo returns the tiles from the search's start to a reached tile (start first) and
+// their cost, or nil if the search never got there
+func (t *tileSearch) pathTo(tx, ty int) ([][2]int, float64) {
+	if !t.bounds.contains(tx, ty) || !t.closed[t.index(tx, ty)] {
+		return nil, 0
+	}
+	end := t.index(tx, ty)
+	var tiles [][2]int
+	for i := end; i != -1; i = t.parent[i] {
+		x, y := t.tile(i)
+		tiles = append(tiles, [2]int{x, y})
 	}
+	return reversed(tiles), t.cost[end]
+}
+
+// pathHeap is the A* open list, cheapest estimate first
+type pathItem struct {
+	index int
+	f     float64
+}
 
-	return nil
+type pathHeap []pathItem
+
+func (h pathHeap) Len() int           { return len(h) }
+func (h pathHeap) Less(i, j int) bool { return h[i].f < h[j].f }
+func (h pathHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *pathHeap) Push(x any)        { *h = append(*h, x.(pathItem)) }
+func (h *pathHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
 }
 
+// Furthest node (in path steps) stringPull tries to shortcut to, so long paths across big
+// maps stay cheap to smooth
+const pullLookahead = 2 * ClusterSize
+
 // stringPull optimizes the path by removing unnecessary nodes
 func (s *AISystem) stringPull(m *world.Map, path [][]float64) [][]float64 {
 	if len(path) < 3 {
 		return path
 	}
 
+	// Cost of walking the path up to each node, so shortcuts can't trade a road for sand
+	costTo := make([]float64, len(path))
+	for i := 1; i < len(path); i++ {
+		costTo[i] = costTo[i-1] + segmentCost(m, path[i-1][0], path[i-1][1], path[i][0], path[i][1])
+	}
+
 	smoothPath := [][]float64{path[0]}
 	currIdx := 0
 
 	for currIdx < len(path)-1 {
 		// Look ahead as far as possible
 		nextIdx := currIdx + 1
-		for i := len(path) - 1; i > currIdx+1; i-- {
+		for i := min(len(path)-1, currIdx+pullLookahead); i > currIdx+1; i-- {
+			if segmentCost(m, path[currIdx][0], path[currIdx][1], path[i][0], path[i][1]) > costTo[i]-costTo[currIdx]+1e-6 {
+				continue
+			}
 			if s.HasLineOfSight(m, path[currIdx][0], path[currIdx][1], path[i][0], path[i][1]) {
 				nextIdx = i
 				break
@@ -576,3 +584,22 @@ func (s *AISystem) stringPull(m *world.Map, path [][]float64) [][]float64 {
 	}
 	return smoothPath
 }
+
+// segmentCost is the terrain-weighted length (in tiles) of walking straight between two
+// transforms, sampling the tile under the body center every 8px
+func segmentCost(m *world.Map, x1, y1, x2, y2 float64) float64 {
+	tileSize := float64(config.TileSize)
+	dist := geom.Dist(x1, y1, x2, y2)
+	steps := int(math.Ceil(dist / 8.0))
+	if steps == 0 {
+		return 0
+	}
+	cost := 0.0
+	for i := 0; i < steps; i++ {
+		t := (float64(i) + 0.5) / float64(steps)
+		tx := int(math.Floor((x1+(x2-x1)*t)/tileSize + 0.5))
+		ty := int(math.Floor((y1+(y2-y1)*t)/tileSize + 0.5))
+		cost += m.MoveCostAt(tx, ty)
+	}
+	return cost * dist / float64(steps) / tileSize
+}
@@ -0,0 +1,327 @@
+package systems
+
+import (
+	"math"
+
+	"henry/pkg/behavior"
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"henry/pkg/shared/world"
+)
+
+// aiAgent is the NPC a behavior tree is being ticked for: its components (saved after
+// the tick) and what has_target found out about its target this tick
+type aiAgent struct {
+	id        ecs.Entity
+	ai        *components.AIComponent
+	input     *components.InputComponent
+	transform *components.TransformComponent
+	m         *world.Map
+	dt        float64
+
+	target           *components.TransformComponent
+	targetX, targetY float64 // Target's center
+	dist             float64 // Center to center
+	hasLOS           bool
+	attackRange      float64
+	ranged           bool
+}
+
+// aiLeaves implements the leaves named in pkg/behavior
+var aiLeaves = map[string]func(s *AISystem, a *aiAgent) behavior.Status{
+	behavior.Fleeing:    func(s *AISystem, a *aiAgent) behavior.Status { return status(a.ai.State == "flee") },
+	behavior.Returning:  func(s *AISystem, a *aiAgent) behavior.Status { return status(a.ai.State == "return") },
+	behavior.Leashed:    (*AISystem).leashed,
+	behavior.HasTarget:  (*AISystem).hasTarget,
+	behavior.Hurt:       func(s *AISystem, a *aiAgent) behavior.Status { return status(s.shouldFlee(a.id, a.ai)) },
+	behavior.HasFled:    func(s *AISystem, a *aiAgent) behavior.Status { return status(a.ai.HasFled) },
+	behavior.InRange:    (*AISystem).inRange,
+	behavior.TooClose:   func(s *AISystem, a *aiAgent) behavior.Status { return status(a.dist < a.attackRange/2) },
+	behavior.FindTarget: (*AISystem).findTarget,
+	behavior.OnPost: func(s *AISystem, a *aiAgent) behavior.Status {
+		return status(a.ai.Activity == "post" || a.ai.Activity == "trade")
+	},
+	behavior.OnPatrol: func(s *AISystem, a *aiAgent) behavior.Status { return status(len(a.ai.Patrol) > 0) },
+
+	behavior.Flee:     (*AISystem).flee,
+	behavior.Return:   (*AISystem).returnHome,
+	behavior.Attack:   (*AISystem).attack,
+	behavior.Chase:    (*AISystem).chase,
+	behavior.Kite:     (*AISystem).kite,
+	behavior.HoldPost: (*AISystem).holdPost,
+	behavior.Patrol:   (*AISystem).patrol,
+	behavior.Wander:   (*AISystem).wander,
+	behavior.Idle: func(s *AISystem, a *aiAgent) behavior.Status {
+		a.ai.State = "idle"
+		a.ai.Path = nil
+		return behavior.Success
+	},
+}
+
+// runLeaf runs one leaf for an agent. Leaves the AI doesn't know fail.
+func (s *AISystem) runLeaf(a *aiAgent, leaf string) behavior.Status {
+	fn, ok := aiLeaves[leaf]
+	if !ok {
+		return behavior.Failure
+	}
+	return fn(s, a)
+}
+
+// UnknownLeaves lists the leaves of a tree the AI can't run
+func UnknownLeaves(tree behavior.Node) []string {
+	var unknown []string
+	for _, leaf := range tree.Leaves() {
+		if _, ok := aiLeaves[leaf]; !ok {
+			unknown = append(unknown, leaf)
+		}
+	}
+	return unknown
+}
+
+func status(ok bool) behavior.Status {
+	if ok {
+		return behavior.Success
+	}
+	return behavior.Failure
+}
+
+func (s *AISystem) leashed(a *aiAgent) behavior.Status {
+	homeX, homeY := a.ai.Home()
+	return status(!geom.Within(a.transform.X, a.transform.Y, homeX, homeY, a.ai.LeashRange))
+}
+
+// hasTarget checks the target is still there to fight, drops it if not, and sizes up
+// the fight: distance, line of sight and the reach of the NPC's weapon
+func (s *AISystem) hasTarget(a *aiAgent) behavior.Status {
+	ai := a.ai
+	if ai.TargetID == 0 {
+		return behavior.Failure
+	}
+	targetTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, ai.TargetID)
+	if targetTrans == nil || targetTrans.Z != a.transform.Z || IsSpectating(s.World, ai.TargetID) || IsInvisible(s.World, ai.TargetID) {
+		// Target dead or gone, on a different level, now spectating or invisible
+		ai.TargetID = 0
+		ai.State = "wander"
+		ai.HasFled = false
+		return behavior.Failure
+	}
+	a.target = targetTrans
+
+	selfX, selfY := s.getEntityCenter(a.id)
+	a.targetX, a.targetY = s.getEntityCenter(ai.TargetID)
+	a.dist = geom.Dist(selfX, selfY, a.targetX, a.targetY)
+
+	// Face the target
+	a.input.MouseX = a.targetX
+	a.input.MouseY = a.targetY
+
+	// Use Multi-Ray LOS (Function adds offsets internally)
+	a.hasLOS = s.HasLineOfSight(a.m, a.transform.X, a.transform.Y, targetTrans.X, targetTrans.Y)
+
+	// Determine Attack Range from Equipment
+	a.attackRange = 50.0 // Default Melee
+	a.ranged = false
+	if equip, ok := ecs.GetComponent[components.EquipmentComponent](s.World, a.id); ok {
+		if weaponID := equip.Slots[components.SlotWeapon].ItemID; weaponID != "" {
+			if item, exists := items.Get(weaponID); exists && item.WeaponStats != nil {
+				a.attackRange = item.WeaponStats.Range
+				a.ranged = a.attackRange > 60
+				a.attackRange *= 0.8
+			}
+		}
+	}
+	return behavior.Success
+}
+
+// inRange: melee needs range, ranged also needs line of sight (can't shoot through walls)
+func (s *AISystem) inRange(a *aiAgent) behavior.Status {
+	return status(a.dist <= a.attackRange && (!a.ranged || a.hasLOS))
+}
+
+// findTarget engages hostile factions on sight (aggressive NPCs only)
+func (s *AISystem) findTarget(a *aiAgent) behavior.Status {
+	ai := a.ai
+	if !ai.IsAggressive || ai.AggroRange <= 0 {
+		return behavior.Failure
+	}
+	targetID := s.findHostileTarget(a.id, ai, a.transform)
+	if targetID == 0 {
+		return behavior.Failure
+	}
+	ai.TargetID = targetID
+	ai.State = "chase" // Start chasing next frame
+	ai.Path = nil
+	return behavior.Success
+}
+
+// flee runs to the nearest ally (or home), calling for help on the way out. Once there
+// it turns to fight again, or goes back to wandering if the attacker is gone.
+func (s *AISystem) flee(a *aiAgent) behavior.Status {
+	ai, transform := a.ai, a.transform
+	if ai.State != "flee" {
+		s.startFlee(a.id, ai, transform)
+	}
+	if geom.Within(transform.X, transform.Y, ai.FleeX, ai.FleeY, 50) {
+		ai.Path = nil
+		if ai.TargetID != 0 {
+			ai.State = "chase"
+		} else {
+			ai.State = "wander"
+			ai.StateTimer = 2.0
+		}
+		return behavior.Success
+	}
+	s.moveTowards(ai, a.input, transform, a.m, ai.FleeX, ai.FleeY, a.dt)
+	return behavior.Running
+}
+
+// returnHome gives up the fight and walks back to (near enough) the spawn point, or for
+// a patrolling NPC to the patrol point it was heading for
+func (s *AISystem) returnHome(a *aiAgent) behavior.Status {
+	ai, transform := a.ai, a.transform
+	ai.TargetID = 0
+	if ai.State != "return" {
+		ai.State = "return"
+		ai.Path = nil
+	}
+	homeX, homeY := ai.Home()
+	// Within 50px is home enough; walking to the exact pixel only makes them orbit
+	if geom.Within(transform.X, transform.Y, homeX, homeY, 50) {
+		ai.State = "wander"
+		ai.StateTimer = 2.0 // Chill for a bit
+		ai.HasFled = false
+		ai.Path = nil
+		return behavior.Success
+	}
+	s.moveTowards(ai, a.input, transform, a.m, homeX, homeY, a.dt)
+	return behavior.Running
+}
+
+func (s *AISystem) attack(a *aiAgent) behavior.Status {
+	a.ai.State = "attack"
+	a.input.Attack = true
+	return behavior.Success
+}
+
+// chase heads straight for a target in sight, and paths around whatever is in the way
+func (s *AISystem) chase(a *aiAgent) behavior.Status {
+	ai, transform := a.ai, a.transform
+	ai.State = "chase"
+	ai.PathTimer -= a.dt
+
+	var moveTargetX, moveTargetY float64
+	if a.hasLOS {
+		// Direct Chase - Clear path data
+		ai.Path = nil
+		moveTargetX = a.target.X
+		moveTargetY = a.target.Y
+	} else {
+		// Blocked! Pathfind, refreshing every 0.5s to track the moving target
+		if ai.PathTimer <= 0 || len(ai.Path) == 0 {
+			ai.Path = s.FindPath(a.m, transform.X, transform.Y, a.target.X, a.target.Y)
+			ai.PathTimer = 0.5
+		}
+
+		if len(ai.Path) > 0 {
+			moveTargetX = ai.Path[0][0]
+			moveTargetY = ai.Path[0][1]
+
+			// Check if reached node (within 10px)
+			if geom.Within(transform.X, transform.Y, moveTargetX, moveTargetY, 10) {
+				ai.Path = ai.Path[1:]
+				if len(ai.Path) > 0 {
+					moveTargetX = ai.Path[0][0]
+					moveTargetY = ai.Path[0][1]
+				}
+			}
+		} else {
+			// No path found? Direct chase as failover
+			moveTargetX = a.target.X
+			moveTargetY = a.target.Y
+		}
+	}
+
+	dx, dy := geom.Direction(transform.X, transform.Y, moveTargetX, moveTargetY)
+	steer(a.input, dx, dy)
+	return behavior.Running
+}
+
+// kite backs straight away from the target, still facing it and shooting while it's in
+// range. Walls simply stop the retreat.
+func (s *AISystem) kite(a *aiAgent) behavior.Status {
+	a.ai.State = "kite"
+	a.ai.Path = nil
+	dx, dy := geom.Direction(a.targetX, a.targetY, a.transform.X, a.transform.Y)
+	steer(a.input, dx, dy)
+	a.input.Attack = s.inRange(a) == behavior.Success
+	return behavior.Running
+}
+
+// holdPost keeps the NPC at its spawn point (guard post / shop counter)
+func (s *AISystem) holdPost(a *aiAgent) behavior.Status {
+	ai, transform := a.ai, a.transform
+	ai.State = "idle"
+	if !geom.Within(transform.X, transform.Y, ai.SpawnX, ai.SpawnY, 16) {
+		s.moveTowards(ai, a.input, transform, a.m, ai.SpawnX, ai.SpawnY, a.dt)
+		return behavior.Running
+	}
+	ai.Path = nil
+	return behavior.Success
+}
+
+// patrol walks to each point of the route in turn (pathing around whatever is in the
+// way), then starts over. A fight only interrupts it: the point it was heading for is
+// where it returns to and carries on from.
+func (s *AISystem) patrol(a *aiAgent) behavior.Status {
+	ai, transform := a.ai, a.transform
+	ai.State = "patrol"
+	x, y := ai.Home()
+	if geom.Within(transform.X, transform.Y, x, y, 16) {
+		ai.PatrolIndex = (ai.PatrolIndex + 1) % len(ai.Patrol)
+		ai.Path = nil
+		ai.PathTimer = 0
+		x, y = ai.Home()
+	}
+	s.moveTowards(ai, a.input, transform, a.m, x, y, a.dt)
+	return behavior.Running
+}
+
+// wander alternates between standing around and strolling in a random direction
+func (s *AISystem) wander(a *aiAgent) behavior.Status {
+	a.ai.StateTimer -= a.dt
+	if a.ai.StateTimer <= 0 {
+		s.pickNewState(a.ai)
+	}
+	s.applyWanderState(a.ai, a.input, a.transform)
+	return behavior.Running
+}
+
+// steer presses the keys for a direction: the main axis, plus the other one when the
+// direction leans far enough that way
+func steer(input *components.InputComponent, dx, dy float64) {
+	if math.Abs(dx) > math.Abs(dy) {
+		if dx > 0 {
+			input.Right = true
+		} else {
+			input.Left = true
+		}
+		if dy > 0.5 {
+			input.Down = true
+		} else if dy < -0.5 {
+			input.Up = true
+		}
+	} else {
+		if dy > 0 {
+			input.Down = true
+		} else {
+			input.Up = true
+		}
+		if dx > 0.5 {
+			input.Right = true
+		} else if dx < -0.5 {
+			input.Left = true
+		}
+	}
+}
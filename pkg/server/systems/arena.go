@@ -0,0 +1,402 @@
+package systems
+
+import (
+	"errors"
+	"fmt"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"henry/pkg/shared/world"
+	"log"
+	"sort"
+)
+
+const (
+	ArenaTeamSize       = 2     // Default players per side; the queue pops 2*TeamSize at once
+	ArenaRoundsToWin    = 2     // Best of three
+	ArenaMaxRounds      = 5     // Drawn rounds don't score; the match stops after this many
+	ArenaRoundCountdown = 5.0   // Seconds between teleport and the first allowed hit
+	ArenaRoundTime      = 90.0  // Rounds still going after this are a draw
+	ArenaQueueRange     = 200.0 // Max distance (px) from an arena master to join the queue
+	ArenaWidth          = 24    // Arena map size in tiles
+	ArenaHeight         = 14
+	ArenaWinGold        = 50 // Reward per player on the winning team
+	ArenaLossGold       = 10 // Consolation per player on the losing team (and on draws)
+)
+
+// ArenaMatch is one instanced team fight, played as rounds on a private arena map.
+// A player knocked down to 1 HP sits out the rest of the round; the last team standing scores.
+type ArenaMatch struct {
+	ID        int
+	Level     int // Instance level the arena map lives on
+	Teams     [2][]ecs.Entity
+	Score     [2]int
+	Round     int
+	Countdown float64 // Seconds until this round's fight starts (0 = fighting)
+	TimeLeft  float64
+
+	out      map[ecs.Entity]bool // Knocked out this round
+	returnTo map[ecs.Entity]ReturnPosition
+}
+
+// Started reports whether the current round's countdown is over
+func (m *ArenaMatch) Started() bool {
+	return m.Countdown <= 0
+}
+
+// TeamOf returns the player's team (0 or 1), or -1
+func (m *ArenaMatch) TeamOf(id ecs.Entity) int {
+	for team, members := range m.Teams {
+		for _, member := range members {
+			if member == id {
+				return team
+			}
+		}
+	}
+	return -1
+}
+
+// Players returns both teams' members
+func (m *ArenaMatch) Players() []ecs.Entity {
+	return append(append([]ecs.Entity{}, m.Teams[0]...), m.Teams[1]...)
+}
+
+// Standing counts the team's players not yet knocked out this round
+func (m *ArenaMatch) Standing(team int) int {
+	n := 0
+	for _, id := range m.Teams[team] {
+		if !m.out[id] {
+			n++
+		}
+	}
+	return n
+}
+
+// ArenaSystem runs the arena queue and matches. Each match gets its own instance map;
+// players are teleported in, fight best-of-three rounds and are returned where they were.
+type ArenaSystem struct {
+	World     *ecs.World
+	Instances *InstanceSystem
+	TeamSize  int
+
+	// Hooks provided by the GameServer
+	OnTeleport    func(player ecs.Entity) // Moved to another level (resync map)
+	OnMatchUpdate func(match *ArenaMatch) // Round started, fight began or score changed
+	OnMessage     func(player ecs.Entity, msg string)
+	OnEnd         func(match *ArenaMatch, winner int)        // Players already returned; -1 = draw
+	OnQueueChange func(queued []ecs.Entity, left ecs.Entity) // left is 0 unless someone dropped out
+
+	queue   []ecs.Entity
+	matches map[ecs.Entity]*ArenaMatch // Every participant -> their match
+	nextID  int
+}
+
+func NewArenaSystem(world *ecs.World, instances *InstanceSystem) *ArenaSystem {
+	return &ArenaSystem{
+		World:     world,
+		Instances: instances,
+		TeamSize:  ArenaTeamSize,
+		matches:   make(map[ecs.Entity]*ArenaMatch),
+	}
+}
+
+// Join queues a player standing near an arena master. A match starts as soon as
+// enough players are queued.
+func (s *ArenaSystem) Join(player ecs.Entity) error {
+	if s.matches[player] != nil {
+		return errors.New("already in an arena match")
+	}
+	if s.IsQueued(player) {
+		return errors.New("already queued")
+	}
+	if !s.NearArenaMaster(player) {
+		return errors.New("talk to an Arena Master to queue")
+	}
+
+	s.queue = append(s.queue, player)
+	log.Printf("Arena: Entity %d queued (%d/%d)", player, len(s.queue), s.QueueNeeded())
+	if len(s.queue) >= s.QueueNeeded() {
+		players := s.queue[:s.QueueNeeded()]
+		s.queue = append([]ecs.Entity{}, s.queue[s.QueueNeeded():]...)
+		s.startMatch(players)
+	}
+	if s.OnQueueChange != nil {
+		s.OnQueueChange(s.queue, 0)
+	}
+	return nil
+}
+
+// Leave drops the player from the queue, or forfeits their match (also used on disconnect).
+// A team with nobody left loses the match.
+func (s *ArenaSystem) Leave(player ecs.Entity) {
+	for i, id := range s.queue {
+		if id == player {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			if s.OnQueueChange != nil {
+				s.OnQueueChange(s.queue, player)
+			}
+			return
+		}
+	}
+
+	match := s.matches[player]
+	if match == nil {
+		return
+	}
+	team := match.TeamOf(player)
+	for i, id := range match.Teams[team] {
+		if id == player {
+			match.Teams[team] = append(match.Teams[team][:i], match.Teams[team][i+1:]...)
+			break
+		}
+	}
+	delete(match.out, player)
+	s.returnPlayer(match, player)
+	delete(s.matches, player)
+	log.Printf("Arena match %d: Entity %d left", match.ID, player)
+
+	if len(match.Teams[team]) == 0 {
+		s.finish(match, 1-team)
+		return
+	}
+	s.broadcast(match, fmt.Sprintf("%s left the arena", s.playerName(player)))
+	if match.Started() && match.Standing(team) == 0 {
+		s.endRound(match, 1-team)
+		return
+	}
+	if s.OnMatchUpdate != nil {
+		s.OnMatchUpdate(match)
+	}
+}
+
+// IsQueued reports whether the player is waiting for a match
+func (s *ArenaSystem) IsQueued(player ecs.Entity) bool {
+	for _, id := range s.queue {
+		if id == player {
+			return true
+		}
+	}
+	return false
+}
+
+// QueueNeeded is how many queued players start a match
+func (s *ArenaSystem) QueueNeeded() int {
+	return 2 * max(s.TeamSize, 1)
+}
+
+// MatchOf returns the player's match, or nil
+func (s *ArenaSystem) MatchOf(player ecs.Entity) *ArenaMatch {
+	return s.matches[player]
+}
+
+// Matches returns the running matches, oldest first
+func (s *ArenaSystem) Matches() []*ArenaMatch {
+	var matches []*ArenaMatch
+	seen := make(map[*ArenaMatch]bool)
+	for _, match := range s.matches {
+		if !seen[match] {
+			seen[match] = true
+			matches = append(matches, match)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	return matches
+}
+
+// AreEnemies reports whether a and b are on opposing teams of a live round, both still standing
+func (s *ArenaSystem) AreEnemies(a, b ecs.Entity) bool {
+	match := s.matches[a]
+	if match == nil || match != s.matches[b] || !match.Started() || match.out[a] || match.out[b] {
+		return false
+	}
+	return match.TeamOf(a) != match.TeamOf(b)
+}
+
+// KnockOut takes a player out of the current round. Call when an arena hit would drop
+// them below 1 HP.
+func (s *ArenaSystem) KnockOut(player ecs.Entity) {
+	match := s.matches[player]
+	if match == nil || match.out[player] {
+		return
+	}
+	match.out[player] = true
+	team := match.TeamOf(player)
+	s.broadcast(match, fmt.Sprintf("%s is out!", s.playerName(player)))
+	if match.Standing(team) == 0 {
+		s.endRound(match, 1-team)
+	}
+}
+
+// Update runs round countdowns and time limits
+func (s *ArenaSystem) Update(dt float64) {
+	seen := make(map[*ArenaMatch]bool)
+	for _, match := range s.matches {
+		if seen[match] {
+			continue
+		}
+		seen[match] = true
+
+		if !match.Started() {
+			match.Countdown -= dt
+			if match.Started() {
+				match.Countdown = 0
+				s.broadcast(match, fmt.Sprintf("Round %d: Fight!", match.Round))
+				if s.OnMatchUpdate != nil {
+					s.OnMatchUpdate(match)
+				}
+			}
+			continue
+		}
+
+		match.TimeLeft -= dt
+		if match.TimeLeft <= 0 {
+			s.broadcast(match, "Time! The round is a draw")
+			s.endRound(match, -1)
+		}
+	}
+}
+
+func (s *ArenaSystem) startMatch(players []ecs.Entity) {
+	s.nextID++
+	match := &ArenaMatch{
+		ID:       s.nextID,
+		Level:    s.Instances.Create(world.GenerateArena(ArenaWidth, ArenaHeight)),
+		out:      make(map[ecs.Entity]bool),
+		returnTo: make(map[ecs.Entity]ReturnPosition),
+	}
+	for i, id := range players {
+		match.Teams[i%2] = append(match.Teams[i%2], id)
+		if trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id); ok {
+			match.returnTo[id] = ReturnPosition{X: trans.X, Y: trans.Y, Z: trans.Z}
+		}
+		s.matches[id] = match
+	}
+	log.Printf("Arena match %d started on level %d: %v vs %v", match.ID, match.Level, match.Teams[0], match.Teams[1])
+	s.startRound(match)
+}
+
+// startRound heals everyone and lines the teams up on opposite walls
+func (s *ArenaSystem) startRound(match *ArenaMatch) {
+	match.Round++
+	match.Countdown = ArenaRoundCountdown
+	match.TimeLeft = ArenaRoundTime
+	match.out = make(map[ecs.Entity]bool)
+
+	tile := float64(config.TileSize)
+	for team, members := range match.Teams {
+		x := 2 * tile
+		if team == 1 {
+			x = float64(ArenaWidth-3) * tile
+		}
+		for i, id := range members {
+			y := (float64(ArenaHeight)/2 + float64(i*2) - float64(len(members)-1)) * tile
+			s.place(id, x, y, match.Level)
+			s.heal(id)
+		}
+	}
+
+	s.broadcast(match, fmt.Sprintf("Round %d begins in %.0f... (%d - %d)", match.Round, ArenaRoundCountdown, match.Score[0], match.Score[1]))
+	if s.OnMatchUpdate != nil {
+		s.OnMatchUpdate(match)
+	}
+}
+
+// endRound scores the round (-1 = draw) and starts the next one or finishes the match
+func (s *ArenaSystem) endRound(match *ArenaMatch, winner int) {
+	if winner >= 0 {
+		match.Score[winner]++
+		if match.Score[winner] >= ArenaRoundsToWin {
+			s.finish(match, winner)
+			return
+		}
+	}
+	if match.Round >= ArenaMaxRounds {
+		switch {
+		case match.Score[0] > match.Score[1]:
+			s.finish(match, 0)
+		case match.Score[1] > match.Score[0]:
+			s.finish(match, 1)
+		default:
+			s.finish(match, -1)
+		}
+		return
+	}
+	s.startRound(match)
+}
+
+// finish returns everyone to where they queued from and tears down the instance
+func (s *ArenaSystem) finish(match *ArenaMatch, winner int) {
+	for _, id := range match.Players() {
+		s.returnPlayer(match, id)
+		delete(s.matches, id)
+	}
+	s.Instances.Destroy(match.Level)
+	log.Printf("Arena match %d over: team %d won (%d - %d)", match.ID, winner, match.Score[0], match.Score[1])
+	if s.OnEnd != nil {
+		s.OnEnd(match, winner)
+	}
+}
+
+func (s *ArenaSystem) returnPlayer(match *ArenaMatch, id ecs.Entity) {
+	if pos, ok := match.returnTo[id]; ok {
+		s.place(id, pos.X, pos.Y, pos.Z)
+	}
+	s.heal(id)
+}
+
+func (s *ArenaSystem) place(id ecs.Entity, x, y float64, z int) {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+	if !ok {
+		return
+	}
+	changedLevel := trans.Z != z
+	trans.X, trans.Y, trans.Z = x, y, z
+	ecs.AddComponent(s.World, id, *trans)
+	ecs.RemoveComponent[components.AutoMoveComponent](s.World, id)
+	if changedLevel && s.OnTeleport != nil {
+		s.OnTeleport(id)
+	}
+}
+
+func (s *ArenaSystem) heal(id ecs.Entity) {
+	if stats, ok := ecs.GetComponent[components.StatsComponent](s.World, id); ok {
+		stats.CurrentHealth = stats.MaxHealth
+		ecs.AddComponent(s.World, id, *stats)
+	}
+}
+
+// NearArenaMaster reports whether the player stands close enough to an arena master to queue or watch
+func (s *ArenaSystem) NearArenaMaster(player ecs.Entity) bool {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, player)
+	if !ok {
+		return false
+	}
+	for _, id := range ecs.Query[components.MarkerComponent](s.World) {
+		marker, _ := ecs.GetComponent[components.MarkerComponent](s.World, id)
+		if marker.Flags&components.MarkerArena == 0 {
+			continue
+		}
+		t, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if ok && t.Z == trans.Z && geom.Within(t.X, t.Y, trans.X, trans.Y, ArenaQueueRange) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ArenaSystem) broadcast(match *ArenaMatch, msg string) {
+	if s.OnMessage == nil {
+		return
+	}
+	for _, id := range match.Players() {
+		s.OnMessage(id, msg)
+	}
+}
+
+func (s *ArenaSystem) playerName(id ecs.Entity) string {
+	if name, ok := ecs.GetComponent[components.NameComponent](s.World, id); ok && name.Name != "" {
+		return name.Name
+	}
+	return fmt.Sprintf("Entity %d", id)
+}
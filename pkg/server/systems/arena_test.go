@@ -0,0 +1,98 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+// arenaWorld places an arena master and n players next to it on level 0
+func arenaWorld(t *testing.T, n int) (*ArenaSystem, []ecs.Entity) {
+	t.Helper()
+	w := ecs.NewWorld()
+	maps := map[int]*world.Map{0: world.NewMap(32, 32)}
+	s := NewArenaSystem(w, NewInstanceSystem(w, maps))
+	s.TeamSize = 1
+
+	master := w.NewEntity()
+	ecs.AddComponent(w, master, components.TransformComponent{X: 100, Y: 100})
+	ecs.AddComponent(w, master, components.MarkerComponent{Flags: components.MarkerArena})
+
+	players := spawnTestPlayers(w, n, 150, 100)
+	for _, id := range players {
+		ecs.AddComponent(w, id, components.StatsComponent{MaxHealth: 100, CurrentHealth: 40})
+	}
+	return s, players
+}
+
+func TestArenaQueuePopsIntoInstance(t *testing.T) {
+	s, players := arenaWorld(t, 2)
+	if err := s.Join(players[0]); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if s.MatchOf(players[0]) != nil {
+		t.Fatal("match started with half a queue")
+	}
+	if err := s.Join(players[1]); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+
+	match := s.MatchOf(players[0])
+	if match == nil || match != s.MatchOf(players[1]) {
+		t.Fatal("both players should share a match")
+	}
+	if _, ok := s.Instances.Maps[match.Level]; !ok || !IsInstanceLevel(match.Level) {
+		t.Fatalf("arena map missing on instance level %d", match.Level)
+	}
+	for _, id := range players {
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+		stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id)
+		if trans.Z != match.Level || stats.CurrentHealth != stats.MaxHealth {
+			t.Errorf("Entity %d not moved in and healed (Z %d, HP %.0f)", id, trans.Z, stats.CurrentHealth)
+		}
+	}
+	if s.AreEnemies(players[0], players[1]) {
+		t.Error("damage allowed during the round countdown")
+	}
+}
+
+func TestArenaBestOfThreeReturnsPlayers(t *testing.T) {
+	s, players := arenaWorld(t, 2)
+	winner := -1
+	s.OnEnd = func(match *ArenaMatch, w int) { winner = w }
+	s.Join(players[0])
+	s.Join(players[1])
+	match := s.MatchOf(players[0])
+	loser := match.Teams[1][0]
+
+	for round := 0; round < ArenaRoundsToWin; round++ {
+		s.Update(ArenaRoundCountdown + 0.1)
+		if !s.AreEnemies(players[0], players[1]) {
+			t.Fatalf("round %d: players should be able to fight", match.Round)
+		}
+		s.KnockOut(loser)
+	}
+
+	if winner != 0 {
+		t.Fatalf("expected team 0 to win, got %d", winner)
+	}
+	if _, ok := s.Instances.Maps[match.Level]; ok {
+		t.Error("instance map should be destroyed")
+	}
+	for _, id := range players {
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if trans.Z != 0 || trans.X != 150 || s.MatchOf(id) != nil {
+			t.Errorf("Entity %d not returned (Z %d, X %.0f)", id, trans.Z, trans.X)
+		}
+	}
+}
+
+func TestArenaJoinNeedsArenaMaster(t *testing.T) {
+	s, players := arenaWorld(t, 1)
+	ecs.AddComponent(s.World, players[0], components.TransformComponent{X: 1000, Y: 1000})
+	if err := s.Join(players[0]); err == nil {
+		t.Error("joined the queue far from any arena master")
+	}
+}
@@ -0,0 +1,101 @@
+package systems
+
+import (
+	"henry/pkg/shared/ecs"
+	"henry/pkg/storage"
+	"time"
+)
+
+// ItemAudit collects item events (creation, deletion, changing hands) for the audit
+// trail and appends them to Path on Flush. Systems that move items hold an optional
+// *ItemAudit; a nil one records nothing.
+type ItemAudit struct {
+	Path string // Where Flush appends ("" discards the entries, e.g. in the headless sim)
+
+	// Account names the player behind an entity ("" for NPCs and the world). Set by
+	// the GameServer.
+	Account func(id ecs.Entity) string
+
+	// Now stamps entries. Tests replace it.
+	Now func() time.Time
+
+	pending []storage.ItemAuditEntry
+}
+
+func NewItemAudit(path string) *ItemAudit {
+	return &ItemAudit{Path: path, Now: time.Now}
+}
+
+// Record notes that the player entity id gained or lost items (action says which)
+func (a *ItemAudit) Record(action string, id ecs.Entity, other, itemID, instanceID string, quantity int) {
+	if a == nil {
+		return
+	}
+	a.RecordAccount(action, a.accountOf(id), other, itemID, instanceID, quantity)
+}
+
+// RecordAccount is Record for an account that may be offline
+func (a *ItemAudit) RecordAccount(action, account, other, itemID, instanceID string, quantity int) {
+	if a == nil || itemID == "" || quantity <= 0 {
+		return
+	}
+	a.pending = append(a.pending, storage.ItemAuditEntry{
+		Time:       a.Now(),
+		Action:     action,
+		Account:    account,
+		Other:      other,
+		ItemID:     itemID,
+		InstanceID: instanceID,
+		Quantity:   quantity,
+	})
+}
+
+// RecordMerge notes that items joined a stack the player already held (kept, from
+// AddInstance or AddToKeyring), so their own instance ID ends here. The trail can still
+// follow them: the entry's Other is the stack they went into.
+func (a *ItemAudit) RecordMerge(id ecs.Entity, itemID, instanceID, kept string, quantity int) {
+	if instanceID == "" || instanceID == kept {
+		return
+	}
+	a.Record("merged", id, kept, itemID, instanceID, quantity)
+}
+
+// AccountOf names the player behind an entity for the Other side of an entry
+func (a *ItemAudit) AccountOf(id ecs.Entity) string {
+	if a == nil {
+		return ""
+	}
+	return a.accountOf(id)
+}
+
+func (a *ItemAudit) accountOf(id ecs.Entity) string {
+	if a.Account == nil || id == 0 {
+		return ""
+	}
+	return a.Account(id)
+}
+
+// Pending returns the entries not yet flushed
+func (a *ItemAudit) Pending() []storage.ItemAuditEntry {
+	if a == nil {
+		return nil
+	}
+	return a.pending
+}
+
+// Flush appends the pending entries to the audit file. They're kept for the next try
+// if that fails.
+func (a *ItemAudit) Flush() error {
+	if a == nil || len(a.pending) == 0 {
+		return nil
+	}
+	if a.Path == "" {
+		a.pending = nil
+		return nil
+	}
+	if err := storage.AppendItemAudit(a.Path, a.pending); err != nil {
+		return err
+	}
+	a.pending = nil
+	return nil
+}
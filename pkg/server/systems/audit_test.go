@@ -0,0 +1,141 @@
+package systems
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/storage"
+)
+
+func TestItemAuditTrailsGroundItems(t *testing.T) {
+	w := ecs.NewWorld()
+	audit := NewItemAudit(filepath.Join(t.TempDir(), "audit", "items.jsonl"))
+	players := map[ecs.Entity]string{}
+	audit.Account = func(id ecs.Entity) string { return players[id] }
+
+	ground := NewGroundItemSystem(w)
+	ground.Audit = audit
+	newPlayer := func(name string) ecs.Entity {
+		id := w.NewEntity()
+		players[id] = name
+		ecs.AddComponent(w, id, components.TransformComponent{X: 100, Y: 100})
+		ecs.AddComponent(w, id, *items.NewInventory(5))
+		return id
+	}
+	alice, bob := newPlayer("alice"), newPlayer("bob")
+
+	// Loot for alice that bob picks up once it's free for all, and a stack nobody takes
+	loot, _ := ground.Spawn(100, 100, 0, "potion_health_small", 2, alice)
+	ground.Spawn(100, 100, 0, "slime_gel", 1, 0)
+	item, _ := ecs.GetComponent[components.GroundItemComponent](w, loot)
+	item.OwnerTimer = 0
+	ecs.AddComponent(w, loot, *item)
+	if err := ground.Pickup(bob, loot); err != nil {
+		t.Fatal(err)
+	}
+	ground.Update(GroundItemLifetime)
+
+	if err := audit.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	audit.RecordAccount("mailed", "alice", "Support", "potion_health_small", "", 1)
+	if err := audit.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := storage.ReadItemAudit(audit.Path, func(storage.ItemAuditEntry) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []storage.ItemAuditEntry{
+		{Action: "spawned", Account: "alice", ItemID: "potion_health_small", InstanceID: item.InstanceID, Quantity: 2},
+		{Action: "spawned", ItemID: "slime_gel", Quantity: 1},
+		{Action: "picked_up", Account: "bob", Other: "alice", ItemID: "potion_health_small", InstanceID: item.InstanceID, Quantity: 2},
+		{Action: "despawned", ItemID: "slime_gel", Quantity: 1},
+		{Action: "mailed", Account: "alice", Other: "Support", ItemID: "potion_health_small", Quantity: 1},
+	}
+	if len(all) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(all), len(want), all)
+	}
+	for i, got := range all {
+		if got.Time.IsZero() {
+			t.Errorf("entry %d has no time", i)
+		}
+		got.Time = want[i].Time
+		if want[i].InstanceID == "" {
+			got.InstanceID = "" // The unclaimed stack's fresh ID
+		}
+		if got != want[i] {
+			t.Errorf("entry %d: got %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	// Appended, not rewritten: a torn last line is skipped, not fatal
+	f, _ := os.OpenFile(audit.Path, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString(`{"Action":"sol`)
+	f.Close()
+	bobs, err := storage.ReadItemAudit(audit.Path, func(e storage.ItemAuditEntry) bool { return e.Account == "bob" })
+	if err != nil || len(bobs) != 1 {
+		t.Errorf("bob's events: %v, %v; want the one pickup", bobs, err)
+	}
+}
+
+func TestItemAuditFollowsMail(t *testing.T) {
+	w := ecs.NewWorld()
+	audit := NewItemAudit("")
+	audit.Account = func(ecs.Entity) string { return "alice" }
+	mail := NewMailSystem(w, nil)
+	mail.Audit = audit
+
+	alice := w.NewEntity()
+	inv := items.NewInventory(3)
+	items.AddItem(inv, "potion_health_small", 1)
+	held := inv.Slots[0].InstanceID
+	ecs.AddComponent(w, alice, *inv)
+
+	mail.Send("alice", "Support", "Restored items", []storage.MailItem{
+		{ItemID: "potion_health_small", Quantity: 2},
+		{ItemID: "sword_starter", Quantity: 2},
+	})
+	sent := mail.Inbox("alice")[0].Items
+	if len(sent) != 3 || sent[1].InstanceID == "" || sent[1].InstanceID == sent[2].InstanceID {
+		t.Fatalf("attachments = %+v, want the potions and one sword each, all with their own ID", sent)
+	}
+	if err := mail.Take(alice, "alice", mail.Inbox("alice")[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	inv, _ = ecs.GetComponent[components.InventoryComponent](w, alice)
+	if inv.Slots[0].Quantity != 3 || inv.Slots[1].InstanceID != sent[1].InstanceID || inv.Slots[2].InstanceID != sent[2].InstanceID {
+		t.Errorf("bag = %+v, want the potions stacked and the swords under their mailed IDs", inv.Slots)
+	}
+	var merged []storage.ItemAuditEntry
+	for _, e := range audit.Pending() {
+		if e.InstanceID == "" {
+			t.Errorf("%s entry without an instance ID", e.Action)
+		}
+		if e.Action == "merged" {
+			merged = append(merged, e)
+		}
+	}
+	if len(merged) != 1 || merged[0].InstanceID != sent[0].InstanceID || merged[0].Other != held {
+		t.Errorf("merged entries = %+v, want the mailed potions into stack %s", merged, held)
+	}
+
+	// The bag is full now: the potion would stack, the sword doesn't fit, so neither moves
+	mail.Send("alice", "Support", "More", []storage.MailItem{
+		{ItemID: "potion_health_small", Quantity: 1},
+		{ItemID: "sword_starter", Quantity: 1},
+	})
+	if err := mail.Take(alice, "alice", mail.Inbox("alice")[0].ID); err == nil {
+		t.Fatal("took a mail that doesn't fit")
+	}
+	inv, _ = ecs.GetComponent[components.InventoryComponent](w, alice)
+	if inv.Slots[0].Quantity != 3 {
+		t.Errorf("potions = %d after the failed take, want 3", inv.Slots[0].Quantity)
+	}
+}
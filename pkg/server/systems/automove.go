@@ -0,0 +1,140 @@
+package systems
+
+import (
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+)
+
+// Distance (px) at which a follower stops closing in on its target
+const followDistance = 64.0
+
+type AutoMoveSystem struct {
+	World *ecs.World
+	AI    *AISystem // Shared A* pathfinder
+}
+
+func NewAutoMoveSystem(world *ecs.World, ai *AISystem) *AutoMoveSystem {
+	return &AutoMoveSystem{
+		World: world,
+		AI:    ai,
+	}
+}
+
+// MoveTo paths the entity to a world position. Returns false if no path exists.
+func (s *AutoMoveSystem) MoveTo(id ecs.Entity, x, y float64) bool {
+	transform, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+	if transform == nil {
+		return false
+	}
+	m, ok := s.AI.Maps[transform.Z]
+	if !ok {
+		return false
+	}
+
+	path := s.AI.FindPath(m, transform.X, transform.Y, x, y)
+	if len(path) == 0 {
+		return false
+	}
+
+	ecs.AddComponent(s.World, id, components.AutoMoveComponent{Path: path})
+	return true
+}
+
+// Follow makes the entity trail the target until cancelled
+func (s *AutoMoveSystem) Follow(id, targetID ecs.Entity) bool {
+	if id == targetID {
+		return false
+	}
+	if _, ok := ecs.GetComponent[components.TransformComponent](s.World, targetID); !ok {
+		return false
+	}
+
+	ecs.AddComponent(s.World, id, components.AutoMoveComponent{FollowID: targetID})
+	return true
+}
+
+// Stop cancels any click-to-move or follow in progress
+func (s *AutoMoveSystem) Stop(id ecs.Entity) {
+	ecs.RemoveComponent[components.AutoMoveComponent](s.World, id)
+}
+
+func (s *AutoMoveSystem) Update(dt float64) {
+	entities := ecs.Query[components.AutoMoveComponent](s.World)
+	for _, id := range entities {
+		move, _ := ecs.GetComponent[components.AutoMoveComponent](s.World, id)
+		input, _ := ecs.GetComponent[components.InputComponent](s.World, id)
+		transform, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+
+		if move == nil || input == nil || transform == nil {
+			s.Stop(id)
+			continue
+		}
+
+		if move.FollowID != 0 {
+			targetTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, move.FollowID)
+			if targetTrans == nil || targetTrans.Z != transform.Z {
+				// Target dead, gone, or on another level
+				s.Stop(id)
+				continue
+			}
+
+			if geom.Within(transform.X, transform.Y, targetTrans.X, targetTrans.Y, followDistance) {
+				// Close enough, wait for target to move again
+				move.Path = nil
+				ecs.AddComponent(s.World, id, *move)
+				continue
+			}
+
+			move.PathTimer -= dt
+			if move.PathTimer <= 0 || len(move.Path) == 0 {
+				if m, ok := s.AI.Maps[transform.Z]; ok {
+					move.Path = s.AI.FindPath(m, transform.X, transform.Y, targetTrans.X, targetTrans.Y)
+				}
+				if len(move.Path) == 0 {
+					// No path (open ground or same tile), head straight for target
+					move.Path = [][]float64{{targetTrans.X, targetTrans.Y}}
+				}
+				move.PathTimer = 0.5
+			}
+		}
+
+		// Advance past reached nodes (within 10px)
+		for len(move.Path) > 0 {
+			ndx := move.Path[0][0] - transform.X
+			ndy := move.Path[0][1] - transform.Y
+			if ndx*ndx+ndy*ndy >= 100.0 {
+				break
+			}
+			move.Path = move.Path[1:]
+		}
+
+		if len(move.Path) == 0 {
+			if move.FollowID == 0 {
+				// Destination reached
+				s.Stop(id)
+			} else {
+				ecs.AddComponent(s.World, id, *move)
+			}
+			continue
+		}
+
+		// Steer towards next node
+		dx, dy := geom.Direction(transform.X, transform.Y, move.Path[0][0], move.Path[0][1])
+
+		input.Up, input.Down, input.Left, input.Right = false, false, false, false
+		if dx > 0.38 {
+			input.Right = true
+		} else if dx < -0.38 {
+			input.Left = true
+		}
+		if dy > 0.38 {
+			input.Down = true
+		} else if dy < -0.38 {
+			input.Up = true
+		}
+
+		ecs.AddComponent(s.World, id, *move)
+		ecs.AddComponent(s.World, id, *input)
+	}
+}
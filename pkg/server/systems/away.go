@@ -0,0 +1,35 @@
+package systems
+
+import (
+	"henry/pkg/items"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/storage"
+)
+
+// AwayMinSeconds is how long a player must have been offline to be told what happened
+const AwayMinSeconds = 10 * 60
+
+// AwaySummary sums up what happened to a player's things while they were offline, from
+// their last save (lastSeen, Unix seconds) until now: the mail in their inbox sent since
+// and what it carries, and their crops ready to harvest. ok is false when there's
+// nothing worth telling: a first login, a quick relog, or nothing new.
+func AwaySummary(lastSeen, now int64, inbox []storage.Mail, ripeCrops int) (protocol.AwaySummaryPacket, bool) {
+	summary := protocol.AwaySummaryPacket{AwaySeconds: now - lastSeen, RipeCrops: ripeCrops}
+	if lastSeen <= 0 || summary.AwaySeconds < AwayMinSeconds {
+		return summary, false
+	}
+	for _, mail := range inbox {
+		if mail.Sent < lastSeen {
+			continue
+		}
+		summary.Mail++
+		for _, item := range mail.Items {
+			if item.ItemID == items.Gold {
+				summary.MailGold += item.Quantity
+			} else {
+				summary.MailItems += item.Quantity
+			}
+		}
+	}
+	return summary, summary.Mail > 0 || summary.RipeCrops > 0
+}
@@ -0,0 +1,32 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/storage"
+)
+
+func TestAwaySummaryCountsWhatArrivedSinceLogout(t *testing.T) {
+	const lastSeen, now = 1_000_000, 1_000_000 + 3*3600
+	inbox := []storage.Mail{
+		{ID: 1, Sent: lastSeen - 60, Items: []storage.MailItem{{ItemID: "potion_red", Quantity: 2}}}, // Already seen
+		{ID: 2, Sent: lastSeen + 60, Items: []storage.MailItem{{ItemID: items.Gold, Quantity: 250}, {ItemID: "wolf_pelt", Quantity: 3}}},
+		{ID: 3, Sent: now - 60},
+	}
+
+	summary, ok := AwaySummary(lastSeen, now, inbox, 2)
+	if !ok || summary.AwaySeconds != 3*3600 || summary.Mail != 2 || summary.MailGold != 250 || summary.MailItems != 3 || summary.RipeCrops != 2 {
+		t.Errorf("summary %+v (ok %v)", summary, ok)
+	}
+
+	if _, ok := AwaySummary(0, now, inbox, 2); ok {
+		t.Error("summary for a player never seen before")
+	}
+	if _, ok := AwaySummary(now-60, now, inbox, 2); ok {
+		t.Error("summary after a quick relog")
+	}
+	if _, ok := AwaySummary(lastSeen, now, inbox[:1], 0); ok {
+		t.Error("summary with nothing new")
+	}
+}
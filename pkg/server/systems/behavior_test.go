@@ -0,0 +1,133 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/behavior"
+	"henry/pkg/characters"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+func TestBehaviorTreesUseKnownLeaves(t *testing.T) {
+	trees := map[string]behavior.Node{"Default": behavior.Default, "Archer": behavior.Archer, "Coward": behavior.Coward}
+	for id, def := range characters.Registry {
+		if !def.Behavior.Empty() {
+			trees[id] = def.Behavior
+		}
+	}
+	for name, tree := range trees {
+		if unknown := UnknownLeaves(tree); len(unknown) > 0 {
+			t.Errorf("%s uses leaves the AI doesn't know: %v", name, unknown)
+		}
+	}
+}
+
+// newFightingNPC puts an NPC with a weapon and a target 100px to its right on an open map
+func newFightingNPC(tree behavior.Node, weaponID string, health float64) (*AISystem, ecs.Entity) {
+	w := ecs.NewWorld()
+	s := NewAISystem(w, map[int]*world.Map{0: world.NewMap(32, 32)}, nil)
+
+	target := w.NewEntity()
+	ecs.AddComponent(w, target, components.TransformComponent{X: 500, Y: 500})
+	ecs.AddComponent(w, target, components.StatsComponent{MaxHealth: 100, CurrentHealth: 100})
+
+	npc := w.NewEntity()
+	ecs.AddComponent(w, npc, components.TransformComponent{X: 400, Y: 500})
+	ecs.AddComponent(w, npc, components.StatsComponent{MaxHealth: 100, CurrentHealth: health})
+	ecs.AddComponent(w, npc, components.InputComponent{})
+	var equip components.EquipmentComponent
+	equip.Slots[components.SlotWeapon] = components.EquipmentSlot{ItemID: weaponID}
+	ecs.AddComponent(w, npc, equip)
+	ecs.AddComponent(w, npc, components.AIComponent{
+		Faction: components.FactionGuards, TargetID: target, State: "chase", Behavior: tree,
+		SpawnX: 400, SpawnY: 500, LeashRange: 600, FleeThreshold: 0.3,
+	})
+	return s, npc
+}
+
+func TestArcherKitesWhileMeleeAttacks(t *testing.T) {
+	s, archer := newFightingNPC(behavior.Archer, "bow_starter", 100)
+	s.Update(0.1)
+	ai, _ := ecs.GetComponent[components.AIComponent](s.World, archer)
+	input, _ := ecs.GetComponent[components.InputComponent](s.World, archer)
+	if ai.State != "kite" || !input.Left || input.Right {
+		t.Errorf("archer with a target 100px away: state %q, left %v right %v; want backing off", ai.State, input.Left, input.Right)
+	}
+	if !input.Attack {
+		t.Error("archer stopped shooting while backing off")
+	}
+
+	// The default tree with the same bow just shoots
+	s, npc := newFightingNPC(behavior.Default, "bow_starter", 100)
+	s.Update(0.1)
+	ai, _ = ecs.GetComponent[components.AIComponent](s.World, npc)
+	if ai.State != "attack" {
+		t.Errorf("default tree with a bow: state %q, want attack", ai.State)
+	}
+}
+
+func TestCowardStaysOutOfTheFightWhenHurt(t *testing.T) {
+	// Hurt at home with no allies around, so running for help ends right away
+	s, coward := newFightingNPC(behavior.Coward, "sword_starter", 20)
+	s.Update(0.1)
+	if ai, _ := ecs.GetComponent[components.AIComponent](s.World, coward); !ai.HasFled {
+		t.Fatalf("hurt coward didn't run for help (state %q)", ai.State)
+	}
+	// Then it cowers instead of going back in like the default tree does
+	s.Update(0.1)
+	ai, _ := ecs.GetComponent[components.AIComponent](s.World, coward)
+	input, _ := ecs.GetComponent[components.InputComponent](s.World, coward)
+	if ai.State != "idle" || input.Attack || input.Right {
+		t.Errorf("hurt coward after fleeing: state %q, attacking %v; want idle", ai.State, input.Attack)
+	}
+
+	s, npc := newFightingNPC(behavior.Default, "sword_starter", 20)
+	s.Update(0.1)
+	s.Update(0.1)
+	ai, _ = ecs.GetComponent[components.AIComponent](s.World, npc)
+	if ai.State != "chase" {
+		t.Errorf("default tree after fleeing: state %q, want back to chasing", ai.State)
+	}
+}
+
+func TestPatrolResumesAfterLeash(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewAISystem(w, map[int]*world.Map{0: world.NewMap(32, 32)}, nil)
+	target := w.NewEntity()
+	ecs.AddComponent(w, target, components.TransformComponent{X: 1050, Y: 100})
+
+	npc := w.NewEntity()
+	ecs.AddComponent(w, npc, components.TransformComponent{X: 100, Y: 100})
+	ecs.AddComponent(w, npc, components.InputComponent{})
+	ecs.AddComponent(w, npc, components.AIComponent{
+		Faction: components.FactionGuards, State: "wander", LeashRange: 600,
+		SpawnX: 900, SpawnY: 100, Patrol: [][2]float64{{100, 100}, {300, 100}},
+	})
+	place := func(x float64, targetID ecs.Entity) (*components.AIComponent, *components.InputComponent) {
+		ecs.AddComponent(w, npc, components.TransformComponent{X: x, Y: 100})
+		ai, _ := ecs.GetComponent[components.AIComponent](w, npc)
+		ai.TargetID = targetID
+		ecs.AddComponent(w, npc, *ai)
+		s.Update(0.1)
+		ai, _ = ecs.GetComponent[components.AIComponent](w, npc)
+		input, _ := ecs.GetComponent[components.InputComponent](w, npc)
+		return ai, input
+	}
+
+	// At the first point it sets off for the second
+	if ai, input := place(100, 0); ai.State != "patrol" || ai.PatrolIndex != 1 || !input.Right {
+		t.Fatalf("at the first patrol point: state %q, heading for %d; want off to point 1", ai.State, ai.PatrolIndex)
+	}
+
+	// Chased a target too far from the route: it heads back to the route, not to its
+	// spawn point (which is still within the leash)
+	if ai, input := place(1000, target); ai.State != "return" || ai.TargetID != 0 || !input.Left {
+		t.Fatalf("leashed off the route: state %q, target %d, left %v; want returning to the route", ai.State, ai.TargetID, input.Left)
+	}
+	place(310, 0) // Back on the route
+	if ai, input := place(310, 0); ai.State != "patrol" || ai.PatrolIndex != 0 || !input.Left {
+		t.Errorf("back on the route: state %q, heading for %d; want patrolling on to point 0", ai.State, ai.PatrolIndex)
+	}
+}
@@ -0,0 +1,126 @@
+package systems
+
+import (
+	"math/rand"
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+// benchMap builds a size x size grass map with deterministic tree clumps (~10% solid)
+func benchMap(size int) *world.Map {
+	m := world.NewMap(size, size)
+	rng := rand.New(rand.NewSource(1))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if rng.Float64() < 0.1 {
+				m.Tiles[y][x].Type = world.TileTree
+			}
+		}
+	}
+	// Keep the corners walkable for pathfinding
+	m.Tiles[0][0].Type = world.TileGrass
+	m.Tiles[size-1][size-1].Type = world.TileGrass
+	return m
+}
+
+// benchWorld spawns npcs aggressive NPCs and a handful of players spread over the map
+func benchWorld(m *world.Map, npcs, players int) *ecs.World {
+	w := ecs.NewWorld()
+	rng := rand.New(rand.NewSource(2))
+	extent := float64(m.Width * 32)
+
+	spawn := func() ecs.Entity {
+		id := w.NewEntity()
+		ecs.AddComponent(w, id, components.TransformComponent{X: rng.Float64() * extent, Y: rng.Float64() * extent})
+		ecs.AddComponent(w, id, components.PhysicsComponent{Speed: 2, Layer: components.LayerNPC, Mask: components.MaskCharacter})
+		ecs.AddComponent(w, id, components.SpriteComponent{Width: 32, Height: 32})
+		ecs.AddComponent(w, id, components.StatsComponent{MaxHealth: 50, CurrentHealth: 50})
+		ecs.AddComponent(w, id, components.InputComponent{})
+		return id
+	}
+
+	for i := 0; i < players; i++ {
+		id := spawn()
+		w.AddTags(id, components.TagPlayer)
+	}
+	for i := 0; i < npcs; i++ {
+		id := spawn()
+		trans, _ := ecs.GetComponent[components.TransformComponent](w, id)
+		ecs.AddComponent(w, id, components.AIComponent{
+			Type:         "monster",
+			State:        "wander",
+			IsAggressive: true,
+			Faction:      components.FactionMonsters,
+			SpawnX:       trans.X,
+			SpawnY:       trans.Y,
+			LeashRange:   600,
+			AggroRange:   300,
+		})
+		w.AddTags(id, components.TagNPC)
+	}
+	return w
+}
+
+func BenchmarkAISystemUpdate500(b *testing.B) {
+	m := benchMap(128)
+	w := benchWorld(m, 500, 20)
+	s := NewAISystem(w, map[int]*world.Map{0: m}, world.NewClock(12, 1200))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Update(1.0 / 30)
+	}
+}
+
+func BenchmarkFindPath256(b *testing.B) {
+	m := benchMap(256)
+	s := NewAISystem(ecs.NewWorld(), map[int]*world.Map{0: m}, nil)
+	end := float64(255 * config.TileSize)
+	s.FindPath(m, 0, 0, end, end) // Builds the cluster graph
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if path := s.FindPath(m, 0, 0, end, end); path == nil {
+			b.Fatal("no path across the map")
+		}
+	}
+}
+
+func BenchmarkBuildClusterGraph256(b *testing.B) {
+	m := benchMap(256)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buildClusterGraph(m)
+	}
+}
+
+func BenchmarkPrepareUpdatesFor1000(b *testing.B) {
+	m := benchMap(128)
+	w := benchWorld(m, 1000, 1)
+	s := NewNetworkSystem(w, world.NewClock(12, 1200))
+	viewer := ecs.QueryTagged(w, components.TagPlayer)[0]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.PrepareUpdatesFor(viewer)
+	}
+}
+
+func BenchmarkMovementSystemUpdate1000(b *testing.B) {
+	m := benchMap(128)
+	w := benchWorld(m, 1000, 20)
+	for _, id := range ecs.Query[components.InputComponent](w) {
+		ecs.AddComponent(w, id, components.InputComponent{Right: true})
+	}
+	s := NewMovementSystem(w, map[int]*world.Map{0: m})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Update(1.0 / 30)
+	}
+}
@@ -0,0 +1,255 @@
+package systems
+
+import (
+	"errors"
+	"fmt"
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"henry/pkg/shared/world"
+	"henry/pkg/storage"
+	"log"
+)
+
+// BuildingSystem places player-built structures from inventory items onto free tiles
+// and enforces land claims. Structures belong to a username rather than an entity so
+// they outlive sessions and restarts (see Save/Load).
+type BuildingSystem struct {
+	World *ecs.World
+	Maps  map[int]*world.Map
+	Audit *ItemAudit // Optional item audit trail
+}
+
+func NewBuildingSystem(world *ecs.World, maps map[int]*world.Map) *BuildingSystem {
+	return &BuildingSystem{
+		World: world,
+		Maps:  maps,
+	}
+}
+
+// Place builds one of the builder's placeable items on tile (tx, ty) of their level
+func (s *BuildingSystem) Place(builder ecs.Entity, owner, itemID string, tx, ty int) (ecs.Entity, error) {
+	def, ok := items.Get(itemID)
+	if !ok || def.Structure == nil {
+		return 0, errors.New("that can't be built")
+	}
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, builder)
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, builder)
+	if trans == nil || inv == nil {
+		return 0, errors.New("invalid builder")
+	}
+	if IsInstanceLevel(trans.Z) {
+		return 0, errors.New("you can't build here")
+	}
+	if def.Structure.Furniture {
+		return 0, errors.New("furniture goes inside your house")
+	}
+
+	tileSize := float64(config.TileSize)
+	x, y := float64(tx)*tileSize, float64(ty)*tileSize
+	if !geom.Within(trans.X, trans.Y, x, y, config.BuildRange) {
+		return 0, errors.New("too far away")
+	}
+	if !s.tileFree(trans.Z, tx, ty, def.Structure.Solid) {
+		return 0, errors.New("that spot is not free")
+	}
+
+	cx, cy := x+tileSize/2, y+tileSize/2
+	if claimant := s.ClaimOwnerAt(trans.Z, cx, cy); claimant != "" && claimant != owner {
+		return 0, fmt.Errorf("this land is claimed by %s", claimant)
+	}
+	if def.Structure.Claim {
+		if err := s.checkNewClaim(owner, trans.Z, cx, cy); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := items.RemoveItemByID(inv, itemID, 1); err != nil {
+		return 0, err
+	}
+	ecs.AddComponent(s.World, builder, *inv)
+	s.Audit.Record("placed", builder, "", itemID, "", 1)
+
+	return s.Spawn(itemID, owner, x, y, trans.Z)
+}
+
+// Spawn creates the structure entity for a placeable item at a tile's top-left corner
+func (s *BuildingSystem) Spawn(itemID, owner string, x, y float64, z int) (ecs.Entity, error) {
+	def, ok := items.Get(itemID)
+	if !ok || def.Structure == nil {
+		return 0, errors.New("item is not placeable: " + itemID)
+	}
+
+	tileSize := float64(config.TileSize)
+	id := s.World.NewEntity()
+	ecs.AddComponent(s.World, id, components.TransformComponent{X: x, Y: y, Z: z})
+	ecs.AddComponent(s.World, id, components.SpriteComponent{
+		Width:   tileSize,
+		Height:  tileSize,
+		Color:   def.Structure.Color,
+		Texture: itemID,
+	})
+	ecs.AddComponent(s.World, id, components.StructureComponent{
+		ItemID: itemID,
+		Owner:  owner,
+		Solid:  def.Structure.Solid,
+		Claim:  def.Structure.Claim,
+	})
+	if def.Structure.Solid {
+		ecs.AddComponent(s.World, id, components.PhysicsComponent{Layer: components.LayerWall, Size: tileSize})
+	}
+	ecs.AddComponent(s.World, id, components.NameComponent{Name: def.Name})
+	s.World.AddTags(id, components.TagStructure)
+	return id, nil
+}
+
+// Demolish tears a structure down. Owners get the item back; anyone else may only
+// demolish structures standing outside every claim.
+func (s *BuildingSystem) Demolish(actor ecs.Entity, owner string, target ecs.Entity) error {
+	st, _ := ecs.GetComponent[components.StructureComponent](s.World, target)
+	stTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, target)
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, actor)
+	if st == nil || stTrans == nil || trans == nil {
+		return errors.New("nothing to demolish")
+	}
+	if stTrans.Z != trans.Z || !geom.Within(trans.X, trans.Y, stTrans.X, stTrans.Y, config.BuildRange) {
+		return errors.New("too far away")
+	}
+	if IsInstanceLevel(stTrans.Z) {
+		return errors.New("that can't be demolished") // House furniture goes through HousingSystem
+	}
+
+	if st.Owner == owner {
+		inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, actor)
+		if inv == nil {
+			return errors.New("invalid builder")
+		}
+		if err := items.AddItem(inv, st.ItemID, 1); err != nil {
+			return err
+		}
+		ecs.AddComponent(s.World, actor, *inv)
+		s.Audit.Record("demolished", actor, "", st.ItemID, "", 1)
+	} else {
+		half := float64(config.TileSize) / 2
+		if claimant := s.ClaimOwnerAt(stTrans.Z, stTrans.X+half, stTrans.Y+half); claimant != "" && claimant != owner {
+			return fmt.Errorf("this land is claimed by %s", claimant)
+		}
+	}
+
+	s.World.RemoveEntity(target)
+	return nil
+}
+
+// ClaimOwnerAt returns the username whose claim covers a position ("" = unclaimed)
+func (s *BuildingSystem) ClaimOwnerAt(z int, x, y float64) string {
+	half := float64(config.TileSize) / 2
+	for _, id := range ecs.Query[components.StructureComponent](s.World) {
+		st, _ := ecs.GetComponent[components.StructureComponent](s.World, id)
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if st == nil || trans == nil || !st.Claim || trans.Z != z {
+			continue
+		}
+		if geom.Within(trans.X+half, trans.Y+half, x, y, config.ClaimRadius) {
+			return st.Owner
+		}
+	}
+	return ""
+}
+
+// checkNewClaim limits claims per player and keeps claims from overlapping
+func (s *BuildingSystem) checkNewClaim(owner string, z int, x, y float64) error {
+	half := float64(config.TileSize) / 2
+	claims := 0
+	for _, id := range ecs.Query[components.StructureComponent](s.World) {
+		st, _ := ecs.GetComponent[components.StructureComponent](s.World, id)
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if st == nil || trans == nil || !st.Claim {
+			continue
+		}
+		if st.Owner == owner {
+			claims++
+			continue
+		}
+		if trans.Z == z && geom.Within(trans.X+half, trans.Y+half, x, y, 2*config.ClaimRadius) {
+			return fmt.Errorf("too close to the claim of %s", st.Owner)
+		}
+	}
+	if claims >= config.MaxClaimsPerPlayer {
+		return errors.New("you already have a claim banner")
+	}
+	return nil
+}
+
+// tileFree reports whether nothing stands on a tile: no solid terrain, map object,
+// structure or waypoint, and for solid structures no character either
+func (s *BuildingSystem) tileFree(z, tx, ty int, solid bool) bool {
+	m, ok := s.Maps[z]
+	if !ok || tx < 0 || ty < 0 || tx >= m.Width || ty >= m.Height {
+		return false
+	}
+	// Farmland is kept free for crops
+	if m.Tiles[ty][tx].Type.IsSolid() || m.Tiles[ty][tx].Type == world.TileFarmland || m.Objects[ty][tx] > 0 {
+		return false
+	}
+
+	tileSize := float64(config.TileSize)
+	x, y := float64(tx)*tileSize, float64(ty)*tileSize
+	for _, id := range ecs.Query[components.TransformComponent](s.World) {
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if trans.Z != z {
+			continue
+		}
+		if ecs.HasTag(s.World, id, components.TagStructure) || ecs.HasTag(s.World, id, components.TagWaypoint) {
+			if trans.X == x && trans.Y == y {
+				return false
+			}
+			continue
+		}
+		if !solid || IsSpectating(s.World, id) {
+			continue
+		}
+		phys, _ := ecs.GetComponent[components.PhysicsComponent](s.World, id)
+		if phys == nil || phys.Layer&(components.LayerPlayer|components.LayerNPC) == 0 {
+			continue
+		}
+		ox, oy, size := components.ColliderBounds(trans.X, trans.Y, phys, tileSize)
+		if components.CollidersOverlap(x, y, tileSize, components.ShapeAABB, ox, oy, size, phys.Shape) {
+			return false
+		}
+	}
+	return true
+}
+
+// Save lists every structure in the world for storage.SaveStructures. Furniture inside
+// houses is saved with the house (see HousingSystem).
+func (s *BuildingSystem) Save() []storage.StructureSave {
+	var saved []storage.StructureSave
+	for _, id := range ecs.Query[components.StructureComponent](s.World) {
+		st, _ := ecs.GetComponent[components.StructureComponent](s.World, id)
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if st == nil || trans == nil || IsInstanceLevel(trans.Z) {
+			continue
+		}
+		saved = append(saved, storage.StructureSave{ItemID: st.ItemID, Owner: st.Owner, X: trans.X, Y: trans.Y, Z: trans.Z})
+	}
+	return saved
+}
+
+// Load spawns saved structures, skipping any whose item or level no longer exists
+func (s *BuildingSystem) Load(saved []storage.StructureSave) {
+	loaded := 0
+	for _, st := range saved {
+		if _, ok := s.Maps[st.Z]; !ok {
+			log.Printf("Skipping structure %s of %s: level %d not loaded", st.ItemID, st.Owner, st.Z)
+			continue
+		}
+		if _, err := s.Spawn(st.ItemID, st.Owner, st.X, st.Y, st.Z); err != nil {
+			log.Printf("Skipping structure of %s: %v", st.Owner, err)
+			continue
+		}
+		loaded++
+	}
+	log.Printf("Loaded %d structures", loaded)
+}
@@ -0,0 +1,81 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+func newBuilder(w *ecs.World, tx, ty int, kit ...string) ecs.Entity {
+	id := w.NewEntity()
+	ecs.AddComponent(w, id, components.TransformComponent{X: float64(tx * 64), Y: float64(ty * 64)})
+	inv := items.NewInventory(10)
+	for _, itemID := range kit {
+		items.AddItem(inv, itemID, 2)
+	}
+	ecs.AddComponent(w, id, *inv)
+	return id
+}
+
+func TestClaimBlocksOtherBuilders(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewBuildingSystem(w, map[int]*world.Map{0: world.NewMap(30, 30)})
+
+	alice := newBuilder(w, 5, 5, "build_banner", "build_wall_wood")
+	bob := newBuilder(w, 7, 5, "build_wall_wood")
+
+	if _, err := s.Place(alice, "alice", "build_banner", 5, 6); err != nil {
+		t.Fatalf("banner: %v", err)
+	}
+	if _, err := s.Place(alice, "alice", "build_banner", 4, 6); err == nil {
+		t.Error("second claim banner allowed")
+	}
+	if _, err := s.Place(bob, "bob", "build_wall_wood", 7, 6); err == nil {
+		t.Error("bob built inside alice's claim")
+	}
+	wall, err := s.Place(alice, "alice", "build_wall_wood", 6, 6)
+	if err != nil {
+		t.Fatalf("wall: %v", err)
+	}
+	if _, err := s.Place(alice, "alice", "build_wall_wood", 6, 6); err == nil {
+		t.Error("built on an occupied tile")
+	}
+	if err := s.Demolish(bob, "bob", wall); err == nil {
+		t.Error("bob demolished inside alice's claim")
+	}
+
+	inv, _ := ecs.GetComponent[components.InventoryComponent](w, alice)
+	before := items.CountItem(inv, "build_wall_wood")
+	if err := s.Demolish(alice, "alice", wall); err != nil {
+		t.Fatalf("demolish: %v", err)
+	}
+	inv, _ = ecs.GetComponent[components.InventoryComponent](w, alice)
+	if got := items.CountItem(inv, "build_wall_wood"); got != before+1 {
+		t.Errorf("owner refund: have %d walls, want %d", got, before+1)
+	}
+}
+
+func TestStructuresSaveAndLoad(t *testing.T) {
+	w := ecs.NewWorld()
+	maps := map[int]*world.Map{0: world.NewMap(30, 30)}
+	s := NewBuildingSystem(w, maps)
+	builder := newBuilder(w, 5, 5, "build_wall_wood")
+	if _, err := s.Place(builder, "alice", "build_wall_wood", 6, 5); err != nil {
+		t.Fatalf("wall: %v", err)
+	}
+
+	restored := NewBuildingSystem(ecs.NewWorld(), maps)
+	restored.Load(s.Save())
+	saved := restored.Save()
+	if len(saved) != 1 || saved[0].Owner != "alice" || saved[0].X != 6*64 {
+		t.Fatalf("restored %+v", saved)
+	}
+	for _, id := range ecs.Query[components.StructureComponent](restored.World) {
+		if phys, ok := ecs.GetComponent[components.PhysicsComponent](restored.World, id); !ok || phys.Layer != components.LayerWall {
+			t.Error("restored wall is not solid")
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package systems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	protocol "henry/pkg/shared/network"
+)
+
+// ChatFilterFile lists the words masked in chat (optional; DefaultChatFilter without it)
+const ChatFilterFile = "data/chat_filter.json"
+
+// DefaultChatFilter is masked when there's no ChatFilterFile
+var DefaultChatFilter = []string{"fuck", "fucking", "shit", "bitch", "cunt", "asshole", "bastard", "dick", "whore", "slut"}
+
+// ChatFilterConfig is the chat filter file: word lists per locale ("en", "de", ...) and
+// the locales whose lists are masked, every one of them when Locales is empty. A plain
+// JSON list of words also works, as a single list that's always on.
+type ChatFilterConfig struct {
+	Locales []string
+	Words   map[string][]string
+}
+
+// LoadChatFilter reads the masked words of the enabled locales
+func LoadChatFilter(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var words []string
+	if json.Unmarshal(data, &words) == nil {
+		return words, nil
+	}
+	var cfg ChatFilterConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse chat filter json: %w", err)
+	}
+	locales := cfg.Locales
+	if len(locales) == 0 {
+		for locale := range cfg.Words {
+			locales = append(locales, locale)
+		}
+	}
+	for _, locale := range locales {
+		list, ok := cfg.Words[locale]
+		if !ok {
+			return nil, fmt.Errorf("chat filter: no word list for locale %q", locale)
+		}
+		words = append(words, list...)
+	}
+	return words, nil
+}
+
+// ChatSystem checks messages before they're sent on: it masks filtered words and mutes
+// players who send too fast or keep swearing. Routing is up to the server, which knows
+// who is online. What was said is kept in Log.
+type ChatSystem struct {
+	World *ecs.World
+	Log   *ChatLog
+
+	// SlowMode is how many seconds a player waits between global messages (0 = off)
+	SlowMode float64
+
+	words   map[string]bool
+	senders map[ecs.Entity]*chatSender
+}
+
+type chatSender struct {
+	sent       []time.Time // Messages within config.ChatRateWindow
+	strikes    []time.Time // Filtered messages within config.ChatStrikeWindow
+	mutedUntil time.Time
+	lastGlobal time.Time // For SlowMode
+}
+
+func NewChatSystem(world *ecs.World, filter []string) *ChatSystem {
+	s := &ChatSystem{
+		World:    world,
+		Log:      NewChatLog(nil),
+		SlowMode: config.ChatGlobalSlowMode,
+		senders:  make(map[ecs.Entity]*chatSender),
+	}
+	s.SetFilter(filter)
+	return s
+}
+
+// SetFilter replaces the masked words
+func (s *ChatSystem) SetFilter(filter []string) {
+	s.words = make(map[string]bool, len(filter))
+	for _, word := range filter {
+		s.words[strings.ToLower(word)] = true
+	}
+}
+
+// Check cleans up a message from id on a channel and returns the text to send on. The
+// error is meant for the sender (empty message, muted, slow mode).
+func (s *ChatSystem) Check(id ecs.Entity, channel, text string, now time.Time) (string, error) {
+	text = CleanChat(text)
+	if text == "" {
+		return "", errors.New("empty message")
+	}
+
+	sender := s.senders[id]
+	if sender == nil {
+		sender = &chatSender{}
+		s.senders[id] = sender
+	}
+	if now.Before(sender.mutedUntil) {
+		return "", fmt.Errorf("you are muted for %ds", int(math.Ceil(sender.mutedUntil.Sub(now).Seconds())))
+	}
+	if channel == protocol.ChatGlobal && s.SlowMode > 0 {
+		if wait := sender.lastGlobal.Add(time.Duration(s.SlowMode * float64(time.Second))).Sub(now); wait > 0 {
+			return "", fmt.Errorf("global chat is in slow mode: wait %ds", int(math.Ceil(wait.Seconds())))
+		}
+		sender.lastGlobal = now
+	}
+
+	sender.sent = within(sender.sent, now, config.ChatRateWindow)
+	if len(sender.sent) >= config.ChatMessagesPerWindow {
+		sender.mutedUntil = now.Add(config.ChatMuteSeconds * time.Second)
+		sender.sent = nil
+		return "", fmt.Errorf("slow down: you are muted for %ds", int(config.ChatMuteSeconds))
+	}
+	sender.sent = append(sender.sent, now)
+
+	filtered := s.Filter(text)
+	if filtered != text {
+		sender.strikes = append(within(sender.strikes, now, config.ChatStrikeWindow), now)
+		if len(sender.strikes) >= config.ChatStrikesToMute {
+			// This message still goes out (masked), the next ones don't
+			sender.mutedUntil = now.Add(config.ChatMuteSeconds * time.Second)
+			sender.strikes = nil
+		}
+	}
+	return filtered, nil
+}
+
+// CleanChat drops control characters and surrounding space from a message and cuts it
+// to config.ChatMaxLength: what Check sends on before masking words
+func CleanChat(text string) string {
+	text = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text))
+	if runes := []rune(text); len(runes) > config.ChatMaxLength {
+		text = string(runes[:config.ChatMaxLength])
+	}
+	return text
+}
+
+// Filter masks filtered words (whole words, any case) with asterisks
+func (s *ChatSystem) Filter(text string) string {
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		if !unicode.IsLetter(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && unicode.IsLetter(runes[end]) {
+			end++
+		}
+		if s.words[strings.ToLower(string(runes[start:end]))] {
+			for i := start; i < end; i++ {
+				runes[i] = '*'
+			}
+		}
+		start = end
+	}
+	return string(runes)
+}
+
+// InRange reports whether to hears local chat from from
+func (s *ChatSystem) InRange(from, to ecs.Entity) bool {
+	a, ok := ecs.GetComponent[components.TransformComponent](s.World, from)
+	if !ok {
+		return false
+	}
+	b, ok := ecs.GetComponent[components.TransformComponent](s.World, to)
+	if !ok || a.Z != b.Z {
+		return false
+	}
+	return geom.Within(a.X, a.Y, b.X, b.Y, config.ChatLocalRange)
+}
+
+// ForgetPlayer drops a player's rate limit and mute (they logged out)
+func (s *ChatSystem) ForgetPlayer(id ecs.Entity) {
+	delete(s.senders, id)
+}
+
+// within keeps the times no older than window seconds
+func within(times []time.Time, now time.Time, window float64) []time.Time {
+	cutoff := now.Add(-time.Duration(window * float64(time.Second)))
+	kept := times[:0]
+	for _, t := range times {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,130 @@
+package systems
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/storage"
+)
+
+func TestChatFilterMasksWholeWords(t *testing.T) {
+	s := NewChatSystem(ecs.NewWorld(), []string{"darn"})
+	if got := s.Filter("Darn it, DARN! darning is fine"); got != "**** it, ****! darning is fine" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestChatThrottleMutesFastSenders(t *testing.T) {
+	s := NewChatSystem(ecs.NewWorld(), nil)
+	now := time.Unix(1000, 0)
+	for i := 0; i < config.ChatMessagesPerWindow; i++ {
+		if _, err := s.Check(1, protocol.ChatLocal, "hi", now); err != nil {
+			t.Fatalf("message %d: %v", i+1, err)
+		}
+	}
+	if _, err := s.Check(1, protocol.ChatLocal, "hi", now); err == nil {
+		t.Fatal("one message too many was sent")
+	}
+	if _, err := s.Check(2, protocol.ChatLocal, "hi", now); err != nil {
+		t.Errorf("another player is throttled too: %v", err)
+	}
+	if _, err := s.Check(1, protocol.ChatLocal, "hi", now.Add((config.ChatMuteSeconds-1)*time.Second)); err == nil || !strings.Contains(err.Error(), "muted for 1s") {
+		t.Errorf("err = %v, want muted for another second", err)
+	}
+	if _, err := s.Check(1, protocol.ChatLocal, "hi", now.Add(config.ChatMuteSeconds*time.Second)); err != nil {
+		t.Errorf("still muted after %ds: %v", config.ChatMuteSeconds, err)
+	}
+}
+
+func TestChatSwearingMutes(t *testing.T) {
+	s := NewChatSystem(ecs.NewWorld(), []string{"darn"})
+	now := time.Unix(1000, 0)
+	for i := 0; i < config.ChatStrikesToMute; i++ {
+		now = now.Add(time.Duration(config.ChatRateWindow) * time.Second) // Slow enough for the rate limit
+		text, err := s.Check(1, protocol.ChatLocal, "darn", now)
+		if err != nil || text != "****" {
+			t.Fatalf("strike %d: got %q, %v; want it masked and sent", i+1, text, err)
+		}
+	}
+	if _, err := s.Check(1, protocol.ChatLocal, "sorry", now.Add(time.Second)); err == nil {
+		t.Error("not muted after repeated swearing")
+	}
+}
+
+func TestChatCheckCleansText(t *testing.T) {
+	s := NewChatSystem(ecs.NewWorld(), nil)
+	now := time.Unix(1000, 0)
+	if _, err := s.Check(1, protocol.ChatLocal, " \t\n ", now); err == nil {
+		t.Error("blank message accepted")
+	}
+	text, _ := s.Check(1, protocol.ChatLocal, "  a\x07b  ", now)
+	if text != "ab" {
+		t.Errorf("got %q, want control characters and padding removed", text)
+	}
+	text, _ = s.Check(1, protocol.ChatLocal, strings.Repeat("é", config.ChatMaxLength+10), now)
+	if n := len([]rune(text)); n != config.ChatMaxLength {
+		t.Errorf("long message kept %d runes, want %d", n, config.ChatMaxLength)
+	}
+}
+
+func TestChatSlowModeOnlyHoldsBackGlobal(t *testing.T) {
+	s := NewChatSystem(ecs.NewWorld(), nil)
+	s.SlowMode = 5
+	now := time.Unix(1000, 0)
+	if _, err := s.Check(1, protocol.ChatGlobal, "hi", now); err != nil {
+		t.Fatalf("first global message: %v", err)
+	}
+	if _, err := s.Check(1, protocol.ChatGlobal, "hi", now.Add(2*time.Second)); err == nil || !strings.Contains(err.Error(), "wait 3s") {
+		t.Errorf("err = %v, want a 3s wait", err)
+	}
+	if _, err := s.Check(1, protocol.ChatLocal, "hi", now.Add(2*time.Second)); err != nil {
+		t.Errorf("local chat held back by slow mode: %v", err)
+	}
+	if _, err := s.Check(1, protocol.ChatGlobal, "hi", now.Add(5*time.Second)); err != nil {
+		t.Errorf("still held back after the slow mode delay: %v", err)
+	}
+}
+
+func TestChatFilterLocales(t *testing.T) {
+	path := t.TempDir() + "/chat_filter.json"
+	os.WriteFile(path, []byte(`{"Locales": ["de"], "Words": {"en": ["darn"], "de": ["mist"]}}`), 0o644)
+	words, err := LoadChatFilter(path)
+	if err != nil || !slices.Equal(words, []string{"mist"}) {
+		t.Errorf("got %v, %v; want only the enabled locale's words", words, err)
+	}
+	os.WriteFile(path, []byte(`["darn"]`), 0o644)
+	if words, err := LoadChatFilter(path); err != nil || !slices.Equal(words, []string{"darn"}) {
+		t.Errorf("plain list: got %v, %v", words, err)
+	}
+}
+
+func TestChatLogKeepsTheLatestLinesPerChannel(t *testing.T) {
+	l := NewChatLog(nil)
+	now := time.Unix(1000, 0)
+	for i := range config.ChatLogLines + 3 {
+		l.Add(storage.ChatLine{Time: now.Add(time.Duration(i) * time.Second), Channel: protocol.ChatGlobal, From: "bob", Text: strconv.Itoa(i)})
+	}
+	l.Add(storage.ChatLine{Time: now.Add(time.Hour), Channel: protocol.ChatLocal, From: "Bob", Text: "last"})
+
+	lines := l.Lines(protocol.ChatGlobal)
+	if len(lines) != config.ChatLogLines || lines[0].Text != "3" || lines[len(lines)-1].Text != strconv.Itoa(config.ChatLogLines+2) {
+		t.Fatalf("kept %d lines from %q, want the latest %d", len(lines), lines[0].Text, config.ChatLogLines)
+	}
+	from := l.From("bob", 2)
+	if len(from) != 2 || from[1].Text != "last" {
+		t.Errorf("From = %v, want bob's latest two lines across channels", from)
+	}
+
+	// Saved and reloaded in the same order
+	reloaded := NewChatLog(l.Saved())
+	if !slices.Equal(reloaded.Lines(protocol.ChatGlobal), lines) || reloaded.Dirty() {
+		t.Error("chat log not restored as saved")
+	}
+}
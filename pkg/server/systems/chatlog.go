@@ -0,0 +1,92 @@
+package systems
+
+import (
+	"slices"
+	"strings"
+
+	"henry/pkg/shared/config"
+	"henry/pkg/storage"
+)
+
+// ChatLog keeps the latest config.ChatLogLines lines of each channel, overwriting the
+// oldest, for moderators to look back through (console "chat")
+type ChatLog struct {
+	channels map[string]*chatRing
+	dirty    bool
+}
+
+// chatRing is a fixed-size ring of lines: next is where the following line goes once
+// the ring is full
+type chatRing struct {
+	lines []storage.ChatLine
+	next  int
+}
+
+// NewChatLog starts from saved lines (channel -> lines, oldest first), which may be nil
+func NewChatLog(saved map[string][]storage.ChatLine) *ChatLog {
+	l := &ChatLog{channels: make(map[string]*chatRing)}
+	for _, lines := range saved {
+		for _, line := range lines {
+			l.Add(line)
+		}
+	}
+	l.dirty = false
+	return l
+}
+
+// Add records a line on its channel
+func (l *ChatLog) Add(line storage.ChatLine) {
+	ring := l.channels[line.Channel]
+	if ring == nil {
+		ring = &chatRing{lines: make([]storage.ChatLine, 0, config.ChatLogLines)}
+		l.channels[line.Channel] = ring
+	}
+	if len(ring.lines) < cap(ring.lines) {
+		ring.lines = append(ring.lines, line)
+	} else {
+		ring.lines[ring.next] = line
+		ring.next = (ring.next + 1) % len(ring.lines)
+	}
+	l.dirty = true
+}
+
+// Lines returns a channel's lines, oldest first
+func (l *ChatLog) Lines(channel string) []storage.ChatLine {
+	ring := l.channels[channel]
+	if ring == nil {
+		return nil
+	}
+	return append(slices.Clone(ring.lines[ring.next:]), ring.lines[:ring.next]...)
+}
+
+// From returns the latest n lines a player sent on any channel, oldest first
+func (l *ChatLog) From(username string, n int) []storage.ChatLine {
+	var lines []storage.ChatLine
+	for channel := range l.channels {
+		for _, line := range l.Lines(channel) {
+			if strings.EqualFold(line.From, username) {
+				lines = append(lines, line)
+			}
+		}
+	}
+	slices.SortStableFunc(lines, func(a, b storage.ChatLine) int { return a.Time.Compare(b.Time) })
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// Dirty reports whether lines were added since the last Saved
+func (l *ChatLog) Dirty() bool {
+	return l.dirty
+}
+
+// Saved returns every channel's lines for storage.SaveChatLog and marks them saved
+func (l *ChatLog) Saved() map[string][]storage.ChatLine {
+	channels := make(map[string][]storage.ChatLine, len(l.channels))
+	for channel := range l.channels {
+		channels[channel] = l.Lines(channel)
+	}
+	l.dirty = false
+	return channels
+}
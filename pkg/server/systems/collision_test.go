@@ -0,0 +1,117 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/shared/world"
+)
+
+// shoreWorld is a 3x3 grass map whose middle tile is the top edge of a lake (water in its
+// bottom half), with one walker standing at (x, y)
+func shoreWorld(x, y float64, input components.InputComponent) (*ecs.World, *MovementSystem, ecs.Entity) {
+	m := world.NewMap(3, 3)
+	m.Tiles[1][1].Type = world.TileWaterEdgeTop
+	w := ecs.NewWorld()
+	s := NewMovementSystem(w, map[int]*world.Map{0: m})
+	id := w.NewEntity()
+	ecs.AddComponent(w, id, components.TransformComponent{X: x, Y: y})
+	ecs.AddComponent(w, id, components.PhysicsComponent{Speed: 10, Mask: components.LayerWall})
+	ecs.AddComponent(w, id, input)
+	return w, s, id
+}
+
+func TestWaterEdgeBlocksOnlyTheWaterHalf(t *testing.T) {
+	w, s, id := shoreWorld(config.TileSize, 30, components.InputComponent{Down: true})
+	for i := 0; i < 10; i++ {
+		s.Update(0.05)
+	}
+	trans, _ := ecs.GetComponent[components.TransformComponent](w, id)
+	// The collider's bottom edge (Y + 20 offset + 24 size) stops at the waterline (96)
+	if bottom := trans.Y + 44; bottom > 96 || bottom < 86 {
+		t.Errorf("walker stopped with its feet at %.0f, want just above the waterline at 96", bottom)
+	}
+}
+
+func TestEntityInWaterIsPushedBackToShore(t *testing.T) {
+	w, s, id := shoreWorld(config.TileSize, 78, components.InputComponent{})
+	s.Update(0.05)
+	trans, _ := ecs.GetComponent[components.TransformComponent](w, id)
+	if trans.X != config.TileSize || trans.Y != 52 {
+		t.Errorf("walker at %.0f,%.0f, want pushed straight up onto the shore at %d,52", trans.X, trans.Y, config.TileSize)
+	}
+}
+
+func TestBlockedPlayerGetsRateLimitedCorrections(t *testing.T) {
+	w, s, id := shoreWorld(config.TileSize, 30, components.InputComponent{Down: true})
+	w.AddTags(id, components.TagPlayer)
+	var got []protocol.MoveCorrectionPacket
+	s.OnCorrect = func(player ecs.Entity, c protocol.MoveCorrectionPacket) {
+		got = append(got, c)
+	}
+
+	// Walking into the water for a second: blocked once it reaches the shore, but at
+	// most one correction per interval
+	for i := 0; i < 20; i++ {
+		s.Update(0.05)
+	}
+	if len(got) == 0 {
+		t.Fatal("no correction for walking into the water")
+	}
+	if limit := int(1/config.MoveCorrectionInterval) + 1; len(got) > limit {
+		t.Errorf("%d corrections in a second, want at most %d", len(got), limit)
+	}
+	trans, _ := ecs.GetComponent[components.TransformComponent](w, id)
+	if last := got[len(got)-1]; last.Reason != protocol.CorrectionBlocked || last.Y != trans.Y {
+		t.Errorf("correction = %+v, want blocked at y %.0f", last, trans.Y)
+	}
+}
+
+func TestPathCrossesRiverOnBridge(t *testing.T) {
+	// A river of deep water down column 2, bridged at row 4
+	m := world.NewMap(5, 6)
+	for y := 0; y < 6; y++ {
+		m.Tiles[y][2].Type = world.TileWaterDeep
+	}
+	m.Tiles[4][2].Type = world.TileBridge
+	s := NewAISystem(ecs.NewWorld(), map[int]*world.Map{0: m}, nil)
+
+	tile := float64(config.TileSize)
+	path := s.FindPath(m, 0, 0, 4*tile, 0)
+	if len(path) == 0 {
+		t.Fatal("no path across the bridge")
+	}
+	prev := []float64{0, 0}
+	for _, node := range path {
+		// Straight legs between nodes (string pulling) must stay on walkable ground
+		if !s.HasLineOfSight(m, prev[0], prev[1], node[0], node[1]) {
+			t.Errorf("path leg %v -> %v cuts through water", prev, node)
+		}
+		prev = node
+	}
+	if prev[0] != 4*tile || prev[1] != 0 {
+		t.Errorf("path %v ends at %v, want the far bank", path, prev)
+	}
+}
+
+func TestRelocateStuckLeavesFloodedGround(t *testing.T) {
+	w, s, id := shoreWorld(config.TileSize, config.TileSize, components.InputComponent{})
+	// A reload floods everything but the right column, far past the shore push range
+	m := world.NewMap(10, 3)
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 9; x++ {
+			m.Tiles[y][x].Type = world.TileWater
+		}
+	}
+	*s.Maps[0] = *m
+	if moved := s.RelocateStuck(0); moved != 1 {
+		t.Fatalf("moved %d entities, want 1", moved)
+	}
+	trans, _ := ecs.GetComponent[components.TransformComponent](w, id)
+	if bx := trans.X + 20; bx < 9*config.TileSize {
+		t.Errorf("walker left at x=%.0f, want on the dry column from %d", trans.X, 9*config.TileSize)
+	}
+}
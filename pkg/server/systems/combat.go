@@ -0,0 +1,91 @@
+package systems
+
+import (
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+// HitInvulnTime is the immunity window (seconds) after taking a hit
+const HitInvulnTime = 0.25
+
+type CombatSystem struct {
+	World *ecs.World
+	Maps  map[int]*world.Map
+}
+
+func NewCombatSystem(world *ecs.World, maps map[int]*world.Map) *CombatSystem {
+	return &CombatSystem{
+		World: world,
+		Maps:  maps,
+	}
+}
+
+// Update counts down post-hit immunity
+func (s *CombatSystem) Update(dt float64) {
+	for _, id := range ecs.Query[components.StatsComponent](s.World) {
+		stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id)
+		if stats == nil || stats.InvulnTimer <= 0 {
+			continue
+		}
+		stats.InvulnTimer -= dt
+		if stats.InvulnTimer < 0 {
+			stats.InvulnTimer = 0
+		}
+		ecs.AddComponent(s.World, id, *stats)
+	}
+}
+
+// ApplyDamage hurts an entity unless it is immune, starting its immunity window.
+// Returns false if no damage was dealt.
+func (s *CombatSystem) ApplyDamage(target ecs.Entity, amount float64) bool {
+	stats, _ := ecs.GetComponent[components.StatsComponent](s.World, target)
+	if stats == nil || stats.CurrentHealth <= 0 || stats.InvulnTimer > 0 {
+		return false
+	}
+	stats.CurrentHealth -= IncomingDamage(s.World, target, amount)
+	if stats.CurrentHealth < 0 {
+		stats.CurrentHealth = 0 // Clamp Health
+	}
+	stats.InvulnTimer = HitInvulnTime
+	ecs.AddComponent(s.World, target, *stats)
+	return true
+}
+
+// FactionOf returns the entity's faction (untagged entities count as players)
+func FactionOf(w *ecs.World, id ecs.Entity) int {
+	if ecs.HasTag(w, id, components.TagNPC) {
+		if ai, ok := ecs.GetComponent[components.AIComponent](w, id); ok {
+			return ai.Faction
+		}
+	}
+	return components.FactionPlayer
+}
+
+// CanDamage reports whether an attack from the given faction may hurt the target.
+// Different factions always can. Same-faction hits need the map's FriendlyFire flag,
+// or a PvP zone when both sides are players.
+func (s *CombatSystem) CanDamage(attackerFaction int, target ecs.Entity) bool {
+	targetFaction := FactionOf(s.World, target)
+	if attackerFaction != targetFaction {
+		return true
+	}
+
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, target)
+	if trans == nil {
+		return false
+	}
+	m, ok := s.Maps[trans.Z]
+	if !ok {
+		return false
+	}
+	if m.FriendlyFire {
+		return true
+	}
+
+	if targetFaction == components.FactionPlayer {
+		zone := m.ZoneAt(trans.X+32, trans.Y+32)
+		return zone != nil && zone.PvP
+	}
+	return false
+}
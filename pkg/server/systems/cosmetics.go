@@ -0,0 +1,92 @@
+package systems
+
+import (
+	"errors"
+	"slices"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+var (
+	ErrLookLocked = errors.New("look is not in the wardrobe")
+	ErrLookSlot   = errors.New("look does not fit that slot")
+)
+
+// Appearance is what an entity is seen wearing: the wardrobe look chosen for each slot,
+// else the worn item. Nil for entities with neither equipment nor a wardrobe.
+func Appearance(w *ecs.World, id ecs.Entity) *components.AppearanceComponent {
+	equip, hasEquip := ecs.GetComponent[components.EquipmentComponent](w, id)
+	cosmetics, hasCosmetics := ecs.GetComponent[components.CosmeticsComponent](w, id)
+	if !hasEquip && !hasCosmetics {
+		return nil
+	}
+	var look components.AppearanceComponent
+	for slot := range look.Slots {
+		if hasCosmetics && cosmetics.Looks[slot] != "" {
+			look.Slots[slot] = cosmetics.Looks[slot]
+		} else if hasEquip {
+			look.Slots[slot] = equip.Slots[slot].ItemID
+		}
+	}
+	return &look
+}
+
+// HasLook reports whether a look is in an entity's wardrobe
+func HasLook(w *ecs.World, id ecs.Entity, itemID string) bool {
+	cosmetics, ok := ecs.GetComponent[components.CosmeticsComponent](w, id)
+	return ok && slices.Contains(cosmetics.Unlocked, itemID)
+}
+
+// UnlockLook adds an item's look to an entity's wardrobe, along with the reward of every
+// collection it completes. Items without a look aren't collected. Returns the looks
+// newly unlocked.
+func UnlockLook(w *ecs.World, id ecs.Entity, itemID string) []string {
+	def, ok := items.Get(itemID)
+	if !ok || def.Look.A == 0 || def.EquipmentSlot < 0 || HasLook(w, id, itemID) {
+		return nil
+	}
+	cosmetics, _ := ecs.GetComponent[components.CosmeticsComponent](w, id)
+	if cosmetics == nil {
+		cosmetics = &components.CosmeticsComponent{}
+	}
+	cosmetics.Unlocked = append(cosmetics.Unlocked, itemID)
+	unlocked := []string{itemID}
+
+	for _, c := range items.CollectionsWith(itemID) {
+		complete := true
+		for _, member := range c.Items {
+			complete = complete && slices.Contains(cosmetics.Unlocked, member)
+		}
+		if complete && c.Reward != "" && !slices.Contains(cosmetics.Unlocked, c.Reward) {
+			cosmetics.Unlocked = append(cosmetics.Unlocked, c.Reward)
+			unlocked = append(unlocked, c.Reward)
+		}
+	}
+	ecs.AddComponent(w, id, *cosmetics)
+	return unlocked
+}
+
+// SetLook shows a look from the wardrobe in an equipment slot. An empty itemID shows the
+// worn item again.
+func SetLook(w *ecs.World, id ecs.Entity, slot int, itemID string) error {
+	cosmetics, _ := ecs.GetComponent[components.CosmeticsComponent](w, id)
+	if cosmetics == nil {
+		cosmetics = &components.CosmeticsComponent{}
+	}
+	if slot < 0 || slot >= len(cosmetics.Looks) {
+		return ErrLookSlot
+	}
+	if itemID != "" {
+		if !slices.Contains(cosmetics.Unlocked, itemID) {
+			return ErrLookLocked
+		}
+		if def, ok := items.Get(itemID); !ok || def.EquipmentSlot != slot {
+			return ErrLookSlot
+		}
+	}
+	cosmetics.Looks[slot] = itemID
+	ecs.AddComponent(w, id, *cosmetics)
+	return nil
+}
@@ -0,0 +1,58 @@
+package systems
+
+import (
+	"slices"
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+func TestUnlockLookCompletesCollection(t *testing.T) {
+	w := ecs.NewWorld()
+	id := w.NewEntity()
+
+	if got := UnlockLook(w, id, "hat_pumpkin"); !slices.Equal(got, []string{"hat_pumpkin"}) {
+		t.Fatalf("first look unlocked %v", got)
+	}
+	if got := UnlockLook(w, id, "hat_pumpkin"); got != nil {
+		t.Errorf("collecting a look twice unlocked %v", got)
+	}
+	if got := UnlockLook(w, id, "potion_red"); got != nil {
+		t.Errorf("an item without a look unlocked %v", got)
+	}
+	got := UnlockLook(w, id, "cloak_harvest")
+	if !slices.Equal(got, []string{"cloak_harvest", "crown_harvest"}) {
+		t.Errorf("completing the collection unlocked %v, want the cloak and the crown", got)
+	}
+}
+
+func TestSetLookOverridesAppearance(t *testing.T) {
+	w := ecs.NewWorld()
+	id := w.NewEntity()
+	var equip components.EquipmentComponent
+	equip.Slots[components.SlotWeapon] = components.EquipmentSlot{ItemID: "sword_starter"}
+	ecs.AddComponent(w, id, equip)
+
+	if err := SetLook(w, id, components.SlotHead, "hat_pumpkin"); err != ErrLookLocked {
+		t.Errorf("showing an uncollected look: got %v, want %v", err, ErrLookLocked)
+	}
+	UnlockLook(w, id, "hat_pumpkin")
+	if err := SetLook(w, id, components.SlotBack, "hat_pumpkin"); err != ErrLookSlot {
+		t.Errorf("showing a hat on the back: got %v, want %v", err, ErrLookSlot)
+	}
+	if err := SetLook(w, id, components.SlotHead, "hat_pumpkin"); err != nil {
+		t.Fatal(err)
+	}
+
+	look := Appearance(w, id)
+	if look.Slots[components.SlotHead] != "hat_pumpkin" || look.Slots[components.SlotWeapon] != "sword_starter" {
+		t.Errorf("appearance = %v, want the hat over the worn sword", look.Slots)
+	}
+
+	// Clearing the look shows the worn item (here: nothing) again
+	SetLook(w, id, components.SlotHead, "")
+	if look := Appearance(w, id); look.Slots[components.SlotHead] != "" {
+		t.Errorf("cleared head shows %q", look.Slots[components.SlotHead])
+	}
+}
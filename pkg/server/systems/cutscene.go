@@ -0,0 +1,159 @@
+package systems
+
+import (
+	"errors"
+	"fmt"
+	"henry/pkg/cutscenes"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"log"
+	"math"
+	"slices"
+)
+
+// CutsceneSystem plays cutscenes (pkg/cutscenes) to players. While one plays, the
+// player's input is ignored and nothing can hurt them, so a boss intro can't get them
+// killed. It ends when its time is up (the client keeps time too), when skipped, or
+// on Stop.
+type CutsceneSystem struct {
+	World *ecs.World
+
+	// Sends the cutscene to the player's client; no shots ends it early
+	OnSend func(player ecs.Entity, packet protocol.CutscenePacket)
+
+	playing map[ecs.Entity]*playingCutscene
+}
+
+type playingCutscene struct {
+	id        string
+	left      float64 // Seconds
+	skippable bool
+}
+
+func NewCutsceneSystem(world *ecs.World) *CutsceneSystem {
+	return &CutsceneSystem{
+		World:   world,
+		playing: make(map[ecs.Entity]*playingCutscene),
+	}
+}
+
+// Playing reports whether a player is watching a cutscene
+func (s *CutsceneSystem) Playing(player ecs.Entity) bool {
+	return s.playing[player] != nil
+}
+
+// Play starts a cutscene for a player. subject is the entity it's about (the boss of a
+// boss intro), 0 for none. Once cutscenes the player has seen are skipped silently.
+func (s *CutsceneSystem) Play(player ecs.Entity, id string, subject ecs.Entity) error {
+	def, ok := cutscenes.Get(id)
+	if !ok {
+		return fmt.Errorf("unknown cutscene %q", id)
+	}
+	if s.playing[player] != nil {
+		return errors.New("already watching a cutscene")
+	}
+	if IsSpectating(s.World, player) {
+		return errors.New("spectating")
+	}
+	seen, _ := ecs.GetComponent[components.CutscenesComponent](s.World, player)
+	if def.Once {
+		if seen == nil {
+			seen = &components.CutscenesComponent{}
+		}
+		if slices.Contains(seen.Seen, id) {
+			return nil
+		}
+		seen.Seen = append(seen.Seen, id)
+		ecs.AddComponent(s.World, player, *seen)
+	}
+
+	packet := protocol.CutscenePacket{ID: id, Letterbox: def.Letterbox, Skippable: def.Skippable}
+	for _, shot := range def.Shots {
+		out := protocol.CutsceneShot{Duration: shot.Duration, Pan: shot.Pan, X: shot.X, Y: shot.Y, Speaker: shot.Speaker, Subtitle: shot.Subtitle}
+		switch shot.Target {
+		case cutscenes.TargetSubject:
+			out.Focus = subject
+			if subject == 0 {
+				out.Focus = player
+			}
+		case cutscenes.TargetPlayer:
+			out.Focus = player
+		}
+		packet.Shots = append(packet.Shots, out)
+	}
+
+	// Let go of held keys so the player doesn't walk on while the camera is away
+	if input, ok := ecs.GetComponent[components.InputComponent](s.World, player); ok {
+		ecs.AddComponent(s.World, player, components.InputComponent{Stance: input.Stance, MouseX: input.MouseX, MouseY: input.MouseY})
+	}
+	ecs.RemoveComponent[components.AutoMoveComponent](s.World, player)
+
+	left := math.Min(def.Length(), config.CutsceneMaxDuration)
+	s.playing[player] = &playingCutscene{id: id, left: left, skippable: def.Skippable}
+	s.protect(player, left)
+	log.Printf("Entity %d watches cutscene %s", player, id)
+	if s.OnSend != nil {
+		s.OnSend(player, packet)
+	}
+	return nil
+}
+
+// Skip ends the player's cutscene at their request (their client already stopped it)
+func (s *CutsceneSystem) Skip(player ecs.Entity, id string) error {
+	cs := s.playing[player]
+	if cs == nil || cs.id != id {
+		return errors.New("no such cutscene playing")
+	}
+	if !cs.skippable {
+		return errors.New("this cutscene can't be skipped")
+	}
+	s.end(player)
+	return nil
+}
+
+// Stop ends the player's cutscene early, e.g. when they're moved to another level
+func (s *CutsceneSystem) Stop(player ecs.Entity) {
+	cs := s.playing[player]
+	if cs == nil {
+		return
+	}
+	s.end(player)
+	if s.OnSend != nil {
+		s.OnSend(player, protocol.CutscenePacket{ID: cs.id})
+	}
+}
+
+// ForgetPlayer drops a disconnected player's cutscene
+func (s *CutsceneSystem) ForgetPlayer(player ecs.Entity) {
+	delete(s.playing, player)
+}
+
+// Update runs the cutscenes' clocks and keeps their watchers safe
+func (s *CutsceneSystem) Update(dt float64) {
+	for player, cs := range s.playing {
+		if cs.left -= dt; cs.left <= 0 {
+			delete(s.playing, player)
+			continue
+		}
+		s.protect(player, cs.left)
+	}
+}
+
+// protect keeps the player invulnerable for the rest of the cutscene
+func (s *CutsceneSystem) protect(player ecs.Entity, left float64) {
+	if stats, ok := ecs.GetComponent[components.StatsComponent](s.World, player); ok && stats.InvulnTimer < left {
+		stats.InvulnTimer = left
+		ecs.AddComponent(s.World, player, *stats)
+	}
+}
+
+// end stops the cutscene before its time, taking back the invulnerability it gave
+func (s *CutsceneSystem) end(player ecs.Entity) {
+	delete(s.playing, player)
+	if stats, ok := ecs.GetComponent[components.StatsComponent](s.World, player); ok {
+		stats.InvulnTimer = 0
+		ecs.AddComponent(s.World, player, *stats)
+	}
+}
@@ -0,0 +1,83 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+func TestCutscenePlaysOnceAndLocksThePlayer(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewCutsceneSystem(w)
+	var sent []protocol.CutscenePacket
+	s.OnSend = func(id ecs.Entity, packet protocol.CutscenePacket) { sent = append(sent, packet) }
+
+	player := w.NewEntity()
+	w.AddTags(player, components.TagPlayer)
+	ecs.AddComponent(w, player, components.StatsComponent{CurrentHealth: 100, MaxHealth: 100})
+	ecs.AddComponent(w, player, components.InputComponent{Up: true, Attack: true, Stance: components.StanceRun})
+	ecs.AddComponent(w, player, components.AutoMoveComponent{Path: [][]float64{{10, 10}}})
+	boss := w.NewEntity()
+
+	if err := s.Play(player, "goblin_warlord_intro", boss); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Playing(player) || len(sent) != 1 {
+		t.Fatalf("not playing: %v, sent %d", s.Playing(player), len(sent))
+	}
+	if focus := sent[0].Shots[0].Focus; focus != boss {
+		t.Errorf("first shot focuses %d, want the boss %d", focus, boss)
+	}
+	if focus := sent[0].Shots[len(sent[0].Shots)-1].Focus; focus != player {
+		t.Errorf("last shot focuses %d, want the player %d", focus, player)
+	}
+	input, _ := ecs.GetComponent[components.InputComponent](w, player)
+	if input.Up || input.Attack || input.Stance != components.StanceRun {
+		t.Errorf("input not released: %+v", input)
+	}
+	if _, ok := ecs.GetComponent[components.AutoMoveComponent](w, player); ok {
+		t.Error("still auto-moving")
+	}
+	if stats, _ := ecs.GetComponent[components.StatsComponent](w, player); stats.InvulnTimer <= 0 {
+		t.Error("not invulnerable")
+	}
+	if err := s.Play(player, "goblin_behemoth_intro", boss); err == nil {
+		t.Error("started a second cutscene")
+	}
+
+	// Skipping ends it and the protection with it
+	if err := s.Skip(player, "goblin_behemoth_intro"); err == nil {
+		t.Error("skipped a cutscene that isn't playing")
+	}
+	if err := s.Skip(player, "goblin_warlord_intro"); err != nil {
+		t.Fatal(err)
+	}
+	if s.Playing(player) {
+		t.Fatal("still playing after skip")
+	}
+	if stats, _ := ecs.GetComponent[components.StatsComponent](w, player); stats.InvulnTimer != 0 {
+		t.Errorf("still invulnerable for %.1fs", stats.InvulnTimer)
+	}
+
+	// Seen once, never again
+	if err := s.Play(player, "goblin_warlord_intro", boss); err != nil || s.Playing(player) {
+		t.Fatalf("replayed a once cutscene: %v", err)
+	}
+	seen, _ := ecs.GetComponent[components.CutscenesComponent](w, player)
+	if len(seen.Seen) != 1 {
+		t.Errorf("seen %v", seen.Seen)
+	}
+
+	// Left alone, a cutscene runs out
+	if err := s.Play(player, "goblin_behemoth_intro", boss); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100 && s.Playing(player); i++ {
+		s.Update(0.1)
+	}
+	if s.Playing(player) {
+		t.Fatal("cutscene never ended")
+	}
+}
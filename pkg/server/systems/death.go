@@ -0,0 +1,86 @@
+package systems
+
+import (
+	"log"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+)
+
+// DeathSystem takes slain NPCs out of the world. Kill lays the victim down as a corpse
+// (a DeadComponent), which AI, movement and collisions pass by; config.CorpseSeconds
+// later it's despawned. A spawner NPC is stripped down to its RespawnComponent to wait
+// for its respawn, anything else is removed for good. Players aren't handled here: they
+// stay where they fell.
+type DeathSystem struct {
+	World *ecs.World
+
+	// RespawnDelay picks how long a spawner NPC stays dead (config.NPCRespawnSeconds
+	// without it)
+	RespawnDelay func(respawn *components.RespawnComponent) float64
+
+	// OnDespawn is told about every corpse despawned
+	OnDespawn func(id ecs.Entity)
+}
+
+func NewDeathSystem(world *ecs.World) *DeathSystem {
+	return &DeathSystem{World: world}
+}
+
+// Kill turns a slain NPC into a corpse. Its respawn delay is settled now, by who's
+// hunting around it as it fell.
+func (s *DeathSystem) Kill(victim, killer ecs.Entity) {
+	if ecs.HasTag(s.World, victim, components.TagPlayer) || ecs.HasComponent[components.DeadComponent](s.World, victim) {
+		return
+	}
+	ecs.AddComponent(s.World, victim, components.DeadComponent{Killer: killer, CorpseTimer: config.CorpseSeconds})
+	if ecs.HasComponent[components.InputComponent](s.World, victim) {
+		ecs.AddComponent(s.World, victim, components.InputComponent{}) // Let go of every key
+	}
+	if respawn, ok := ecs.GetComponent[components.RespawnComponent](s.World, victim); ok {
+		respawn.RespawnDelay = config.NPCRespawnSeconds
+		if s.RespawnDelay != nil {
+			respawn.RespawnDelay = s.RespawnDelay(respawn)
+		}
+		ecs.AddComponent(s.World, victim, *respawn)
+	}
+}
+
+// Update despawns the corpses that have lain long enough
+func (s *DeathSystem) Update(dt float64) {
+	for _, id := range ecs.Query[components.DeadComponent](s.World) {
+		dead, _ := ecs.GetComponent[components.DeadComponent](s.World, id)
+		if dead.CorpseTimer -= dt; dead.CorpseTimer > 0 {
+			ecs.AddComponent(s.World, id, *dead)
+			continue
+		}
+		s.Despawn(id)
+	}
+}
+
+// Despawn takes a dead NPC out of the world now, until it respawns or for good
+func (s *DeathSystem) Despawn(id ecs.Entity) {
+	if respawn, ok := ecs.GetComponent[components.RespawnComponent](s.World, id); ok {
+		DespawnForRespawn(s.World, id, respawn.RespawnDelay)
+		log.Printf("Entity %d despawned. Respawning in %.0fs.", id, respawn.RespawnDelay)
+	} else {
+		s.World.RemoveEntity(id)
+	}
+	if s.OnDespawn != nil {
+		s.OnDespawn(id)
+	}
+}
+
+// DespawnForRespawn empties a spawner NPC's entity but for its RespawnComponent, which
+// counts timer seconds down to its respawn
+func DespawnForRespawn(w *ecs.World, id ecs.Entity, timer float64) {
+	respawn, ok := ecs.GetComponent[components.RespawnComponent](w, id)
+	if !ok {
+		return
+	}
+	respawn.IsDead = true
+	respawn.RespawnTimer = timer
+	w.RemoveEntity(id)
+	ecs.AddComponent(w, id, *respawn)
+}
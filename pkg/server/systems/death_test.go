@@ -0,0 +1,57 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+)
+
+func TestSlainNPCLiesAsCorpseThenDespawns(t *testing.T) {
+	w := ecs.NewWorld()
+	deaths := NewDeathSystem(w)
+	deaths.RespawnDelay = func(*components.RespawnComponent) float64 { return 12 }
+	var despawned []ecs.Entity
+	deaths.OnDespawn = func(id ecs.Entity) { despawned = append(despawned, id) }
+
+	guard := w.NewEntity()
+	ecs.AddComponent(w, guard, components.TransformComponent{X: 10, Y: 10})
+	ecs.AddComponent(w, guard, components.InputComponent{Left: true})
+	ecs.AddComponent(w, guard, components.StatsComponent{MaxHealth: 50})
+	ecs.AddComponent(w, guard, components.RespawnComponent{CharID: "guard"})
+	slime := w.NewEntity()
+	ecs.AddComponent(w, slime, components.TransformComponent{X: 20, Y: 20})
+	player := w.NewEntity()
+	w.AddTags(player, components.TagPlayer)
+
+	deaths.Kill(guard, player)
+	deaths.Kill(slime, player)
+	deaths.Kill(player, guard)
+	if ecs.HasComponent[components.DeadComponent](w, player) {
+		t.Error("a player was laid down as a corpse")
+	}
+	if input, _ := ecs.GetComponent[components.InputComponent](w, guard); input.Left {
+		t.Error("corpse still holds its keys")
+	}
+
+	deaths.Update(config.CorpseSeconds / 2)
+	if !ecs.HasComponent[components.TransformComponent](w, guard) || len(despawned) != 0 {
+		t.Fatal("corpse despawned before its time")
+	}
+
+	deaths.Update(config.CorpseSeconds)
+	if len(despawned) != 2 {
+		t.Fatalf("%d corpses despawned, want 2", len(despawned))
+	}
+	respawn, ok := ecs.GetComponent[components.RespawnComponent](w, guard)
+	if !ok || !respawn.IsDead || respawn.RespawnTimer != 12 {
+		t.Errorf("spawner NPC not waiting out its respawn: %+v", respawn)
+	}
+	if ecs.HasComponent[components.TransformComponent](w, guard) || ecs.HasComponent[components.DeadComponent](w, guard) {
+		t.Error("despawned spawner NPC kept more than its RespawnComponent")
+	}
+	if ecs.HasComponent[components.TransformComponent](w, slime) {
+		t.Error("non-respawning NPC not removed")
+	}
+}
@@ -0,0 +1,318 @@
+package systems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	protocol "henry/pkg/shared/network"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	DialogueDir   = "data/dialogues" // One JSON dialogue tree per file (see LoadDialogues)
+	DialogueRange = 128.0            // Max distance (px) to start talking to an NPC
+	DialogueLeave = 256.0            // Walking further away than this ends the conversation
+)
+
+// What a dialogue option does besides moving the conversation on
+const (
+	DialogueQuest    = "quest"    // Offer a quest (Target = quest ID)
+	DialogueShop     = "shop"     // Open the NPC's shop
+	DialogueTeleport = "teleport" // Move the player to X, Y on level Z
+)
+
+// Dialogue is a conversation tree, attached to NPCs by CharacterDefinition.Dialogue
+type Dialogue struct {
+	ID    string                  `json:"id"`
+	Start string                  `json:"start"` // Node the conversation opens on
+	Nodes map[string]DialogueNode `json:"nodes"`
+}
+
+// DialogueNode is one thing the NPC says and the replies the player can pick
+type DialogueNode struct {
+	Speaker string           `json:"speaker"` // "" = the NPC's name
+	Text    string           `json:"text"`
+	Options []DialogueOption `json:"options"` // The client always adds a goodbye
+}
+
+type DialogueOption struct {
+	Text   string          `json:"text"`
+	Next   string          `json:"next"` // Node it leads to ("" ends the conversation)
+	Action *DialogueAction `json:"action,omitempty"`
+}
+
+type DialogueAction struct {
+	Type   string  `json:"type"`   // Dialogue* constant
+	Target string  `json:"target"` // Quest or shop ID
+	X      float64 `json:"x"`      // Teleport destination (world px)
+	Y      float64 `json:"y"`
+	Z      int     `json:"z"`
+}
+
+// DialogueActionFunc carries out an option's action for a player talking to npc. An
+// error keeps the conversation on the same node and is shown to the player.
+type DialogueActionFunc func(player, npc ecs.Entity, action DialogueAction) error
+
+// DialogueSystem runs conversations between players and NPCs with an
+// InteractableComponent. The server only shows the player the node they're on and
+// takes their choice by index, so a client can't skip to a node or trigger an action
+// the tree doesn't offer there.
+type DialogueSystem struct {
+	World *ecs.World
+
+	// Option actions by type. Teleport is built in; the systems behind quests and
+	// shops register theirs.
+	Actions map[string]DialogueActionFunc
+
+	OnShow     func(player ecs.Entity, packet protocol.DialoguePacket) // NPC 0 closes the window
+	OnTeleport func(player ecs.Entity)                                 // Moved by a teleport option
+
+	dialogues map[string]Dialogue
+	talking   map[ecs.Entity]*conversation
+}
+
+type conversation struct {
+	npc      ecs.Entity
+	dialogue string
+	node     string
+}
+
+func NewDialogueSystem(world *ecs.World, dialogues []Dialogue) *DialogueSystem {
+	s := &DialogueSystem{
+		World:     world,
+		Actions:   make(map[string]DialogueActionFunc),
+		dialogues: make(map[string]Dialogue),
+		talking:   make(map[ecs.Entity]*conversation),
+	}
+	for _, d := range dialogues {
+		s.dialogues[d.ID] = d
+	}
+	s.Actions[DialogueTeleport] = s.teleport
+	return s
+}
+
+// LoadDialogues reads every *.json dialogue tree in dir. Any bad file fails the whole
+// load, like LoadSpells.
+func LoadDialogues(dir string) ([]Dialogue, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var dialogues []Dialogue
+	seen := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var d Dialogue
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if err := validateDialogue(d); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if other, dup := seen[d.ID]; dup {
+			return nil, fmt.Errorf("%s: dialogue %q is already defined in %s", file, d.ID, other)
+		}
+		seen[d.ID] = file
+		dialogues = append(dialogues, d)
+	}
+	return dialogues, nil
+}
+
+func validateDialogue(d Dialogue) error {
+	if d.ID == "" {
+		return errors.New("dialogue has no id")
+	}
+	if _, ok := d.Nodes[d.Start]; !ok {
+		return fmt.Errorf("start node %q doesn't exist", d.Start)
+	}
+	for name, node := range d.Nodes {
+		for i, option := range node.Options {
+			if option.Next != "" {
+				if _, ok := d.Nodes[option.Next]; !ok {
+					return fmt.Errorf("node %s option %d: next node %q doesn't exist", name, i+1, option.Next)
+				}
+			}
+			if option.Action == nil {
+				continue
+			}
+			switch option.Action.Type {
+			case DialogueQuest:
+				if option.Action.Target == "" {
+					return fmt.Errorf("node %s option %d: a %s action needs a target", name, i+1, option.Action.Type)
+				}
+			case DialogueShop, DialogueTeleport:
+			default:
+				return fmt.Errorf("node %s option %d: unknown action %q", name, i+1, option.Action.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// Talking reports whether a player is in a conversation
+func (s *DialogueSystem) Talking(player ecs.Entity) bool {
+	return s.talking[player] != nil
+}
+
+// Interact starts a conversation with an NPC in range. target 0 picks the nearest one
+// that has something to say.
+func (s *DialogueSystem) Interact(player, target ecs.Entity) error {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, player)
+	if !ok {
+		return errors.New("invalid player")
+	}
+
+	npc := target
+	if npc == 0 {
+		best := DialogueRange
+		for _, id := range ecs.Query[components.InteractableComponent](s.World) {
+			t, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+			if !ok || t.Z != trans.Z || !s.available(id) {
+				continue
+			}
+			if d := geom.Dist(t.X, t.Y, trans.X, trans.Y); d <= best {
+				npc, best = id, d
+			}
+		}
+		if npc == 0 {
+			return errors.New("no one to talk to nearby")
+		}
+	} else if !s.available(npc) || !s.inRange(player, npc, DialogueRange) {
+		return errors.New("too far away to talk")
+	}
+
+	interactable, _ := ecs.GetComponent[components.InteractableComponent](s.World, npc)
+	d, ok := s.dialogues[interactable.Dialogue]
+	if !ok {
+		return fmt.Errorf("unknown dialogue %q", interactable.Dialogue)
+	}
+	s.talking[player] = &conversation{npc: npc, dialogue: d.ID, node: d.Start}
+	s.show(player)
+	return nil
+}
+
+// Choose picks an option on the player's current node by index. A negative index says
+// goodbye.
+func (s *DialogueSystem) Choose(player ecs.Entity, option int) error {
+	conv := s.talking[player]
+	if conv == nil {
+		return errors.New("not talking to anyone")
+	}
+	if option < 0 {
+		delete(s.talking, player)
+		return nil
+	}
+	node := s.dialogues[conv.dialogue].Nodes[conv.node]
+	if option >= len(node.Options) {
+		return fmt.Errorf("no option %d", option)
+	}
+	if !s.available(conv.npc) || !s.inRange(player, conv.npc, DialogueLeave) {
+		s.Close(player)
+		return errors.New("too far away to talk")
+	}
+
+	chosen := node.Options[option]
+	if chosen.Action != nil {
+		act, ok := s.Actions[chosen.Action.Type]
+		if !ok {
+			return errors.New("that isn't available yet")
+		}
+		if err := act(player, conv.npc, *chosen.Action); err != nil {
+			return err
+		}
+	}
+	// The action may have ended the conversation (e.g. a teleport)
+	if s.talking[player] != conv {
+		return nil
+	}
+	if chosen.Next == "" {
+		s.Close(player)
+		return nil
+	}
+	conv.node = chosen.Next
+	s.show(player)
+	return nil
+}
+
+// Close ends the player's conversation and closes their dialogue window
+func (s *DialogueSystem) Close(player ecs.Entity) {
+	if s.talking[player] == nil {
+		return
+	}
+	delete(s.talking, player)
+	if s.OnShow != nil {
+		s.OnShow(player, protocol.DialoguePacket{})
+	}
+}
+
+// ForgetPlayer drops a disconnected player's conversation
+func (s *DialogueSystem) ForgetPlayer(player ecs.Entity) {
+	delete(s.talking, player)
+}
+
+// Update ends conversations whose NPC died or despawned or that the player walked away from
+func (s *DialogueSystem) Update() {
+	for player, conv := range s.talking {
+		if !s.available(conv.npc) || !s.inRange(player, conv.npc, DialogueLeave) {
+			s.Close(player)
+		}
+	}
+}
+
+// show sends the player the node they're on
+func (s *DialogueSystem) show(player ecs.Entity) {
+	conv := s.talking[player]
+	if conv == nil || s.OnShow == nil {
+		return
+	}
+	node := s.dialogues[conv.dialogue].Nodes[conv.node]
+	packet := protocol.DialoguePacket{NPC: conv.npc, Speaker: node.Speaker, Text: node.Text}
+	if packet.Speaker == "" {
+		if name, ok := ecs.GetComponent[components.NameComponent](s.World, conv.npc); ok {
+			packet.Speaker = name.Name
+		}
+	}
+	for _, option := range node.Options {
+		packet.Options = append(packet.Options, option.Text)
+	}
+	s.OnShow(player, packet)
+}
+
+// teleport is the built-in DialogueTeleport action
+func (s *DialogueSystem) teleport(player, _ ecs.Entity, action DialogueAction) error {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, player)
+	if !ok {
+		return errors.New("invalid player")
+	}
+	s.Close(player)
+	trans.X, trans.Y, trans.Z = action.X, action.Y, action.Z
+	ecs.AddComponent(s.World, player, *trans)
+	if s.OnTeleport != nil {
+		s.OnTeleport(player)
+	}
+	return nil
+}
+
+// available reports whether an NPC can be talked to (it exists and isn't dead)
+func (s *DialogueSystem) available(npc ecs.Entity) bool {
+	if _, ok := ecs.GetComponent[components.InteractableComponent](s.World, npc); !ok {
+		return false
+	}
+	return !ecs.HasComponent[components.DeadComponent](s.World, npc)
+}
+
+func (s *DialogueSystem) inRange(player, npc ecs.Entity, dist float64) bool {
+	a, ok := ecs.GetComponent[components.TransformComponent](s.World, player)
+	b, ok2 := ecs.GetComponent[components.TransformComponent](s.World, npc)
+	return ok && ok2 && a.Z == b.Z && geom.Within(a.X, a.Y, b.X, b.Y, dist)
+}
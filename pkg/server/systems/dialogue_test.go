@@ -0,0 +1,123 @@
+package systems
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+var testDialogue = Dialogue{
+	ID:    "guard",
+	Start: "greet",
+	Nodes: map[string]DialogueNode{
+		"greet": {Text: "Hello.", Options: []DialogueOption{
+			{Text: "Rumours?", Next: "rumours"},
+			{Text: "Take me home.", Action: &DialogueAction{Type: DialogueTeleport, X: 500, Y: 600, Z: 1}},
+			{Text: "Buy something.", Next: "greet", Action: &DialogueAction{Type: DialogueShop}},
+		}},
+		"rumours": {Speaker: "Old Guard", Text: "Nothing new.", Options: []DialogueOption{
+			{Text: "Bye.", Next: ""},
+		}},
+	},
+}
+
+func TestDialogueBranchesAndActions(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewDialogueSystem(w, []Dialogue{testDialogue})
+	var shown []protocol.DialoguePacket
+	s.OnShow = func(id ecs.Entity, packet protocol.DialoguePacket) { shown = append(shown, packet) }
+	teleported := 0
+	s.OnTeleport = func(id ecs.Entity) { teleported++ }
+
+	player := w.NewEntity()
+	ecs.AddComponent(w, player, components.TransformComponent{X: 100, Y: 100})
+	far := w.NewEntity()
+	ecs.AddComponent(w, far, components.TransformComponent{X: 400, Y: 100})
+	ecs.AddComponent(w, far, components.InteractableComponent{Dialogue: "guard"})
+	npc := w.NewEntity()
+	ecs.AddComponent(w, npc, components.TransformComponent{X: 150, Y: 100})
+	ecs.AddComponent(w, npc, components.NameComponent{Name: "City Guard"})
+	ecs.AddComponent(w, npc, components.InteractableComponent{Dialogue: "guard"})
+
+	if err := s.Interact(player, far); err == nil {
+		t.Error("talked to an NPC out of range")
+	}
+	if err := s.Interact(player, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(shown) != 1 || shown[0].NPC != npc || shown[0].Speaker != "City Guard" || len(shown[0].Options) != 3 {
+		t.Fatalf("opening node: %+v", shown)
+	}
+
+	// Unregistered actions fail without moving on
+	if err := s.Choose(player, 2); err == nil {
+		t.Error("shop option worked without a shop handler")
+	}
+	if err := s.Choose(player, 3); err == nil {
+		t.Error("picked an option that doesn't exist")
+	}
+	if err := s.Choose(player, 0); err != nil {
+		t.Fatal(err)
+	}
+	if last := shown[len(shown)-1]; last.Speaker != "Old Guard" || last.Text != "Nothing new." {
+		t.Fatalf("rumours node: %+v", last)
+	}
+	// An option with no next node ends the conversation
+	if err := s.Choose(player, 0); err != nil {
+		t.Fatal(err)
+	}
+	if s.Talking(player) || shown[len(shown)-1].NPC != 0 {
+		t.Fatal("conversation didn't end")
+	}
+
+	// Teleport moves the player and ends the conversation
+	s.Interact(player, npc)
+	if err := s.Choose(player, 1); err != nil {
+		t.Fatal(err)
+	}
+	trans, _ := ecs.GetComponent[components.TransformComponent](w, player)
+	if trans.X != 500 || trans.Y != 600 || trans.Z != 1 || teleported != 1 {
+		t.Errorf("teleported to %+v (%d times)", trans, teleported)
+	}
+	if s.Talking(player) {
+		t.Error("still talking after the teleport")
+	}
+
+	// Walking away closes the window
+	ecs.AddComponent(w, player, components.TransformComponent{X: 100, Y: 100})
+	s.Interact(player, npc)
+	ecs.AddComponent(w, player, components.TransformComponent{X: 100 + DialogueLeave + 100, Y: 100})
+	s.Update()
+	if s.Talking(player) || shown[len(shown)-1].NPC != 0 {
+		t.Error("conversation survived walking away")
+	}
+}
+
+func TestLoadDialogues(t *testing.T) {
+	dialogues, err := LoadDialogues(filepath.Join("..", "..", "..", DialogueDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dialogues) == 0 {
+		t.Fatal("no dialogues shipped")
+	}
+
+	bad := map[string]string{
+		"start":  `{"id": "a", "start": "missing", "nodes": {"x": {}}}`,
+		"next":   `{"id": "a", "start": "x", "nodes": {"x": {"options": [{"next": "missing"}]}}}`,
+		"action": `{"id": "a", "start": "x", "nodes": {"x": {"options": [{"action": {"type": "dance"}}]}}}`,
+		"target": `{"id": "a", "start": "x", "nodes": {"x": {"options": [{"action": {"type": "quest"}}]}}}`,
+	}
+	for name, data := range bad {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "bad.json"), []byte(data), 0o644)
+		if _, err := LoadDialogues(dir); err == nil || !strings.Contains(err.Error(), "bad.json") {
+			t.Errorf("%s: got %v, want an error naming the file", name, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package systems
+
+import (
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"henry/pkg/shared/world"
+	"math"
+)
+
+// Players earn combat XP by killing NPCs. It's kept in their StatsComponent; the skill
+// ID names the combat level on the character sheet (and in saves from before levels).
+const SkillCombat = "combat"
+
+// NPCDifficulty works out how tough an NPC spawning at (x, y) on level z is. The zone there
+// scales its health, damage and kill XP; in a level-scaled zone its level also follows the
+// average combat level of the players nearby, each level above or below its base level
+// (baseLevel, 0 = 1) adding or taking config.NPCLevelStep off all three.
+func NPCDifficulty(w *ecs.World, m *world.Map, x, y float64, z, baseLevel, baseXP int) components.DifficultyComponent {
+	baseLevel = max(baseLevel, 1)
+	d := components.DifficultyComponent{Level: baseLevel, HealthScale: 1, DamageScale: 1}
+	xpScale := 1.0
+
+	var zone *world.Zone
+	if m != nil {
+		zone = m.ZoneAt(x+float64(config.TileSize)/2, y+float64(config.TileSize)/2)
+	}
+	if zone != nil {
+		d.HealthScale, d.DamageScale, xpScale = scaleOr1(zone.HealthScale), scaleOr1(zone.DamageScale), scaleOr1(zone.XPScale)
+		if zone.LevelScaling {
+			if level, ok := nearbyCombatLevel(w, x, y, z); ok {
+				d.Level = level
+			}
+			if zone.MinLevel > 0 {
+				d.Level = max(d.Level, zone.MinLevel)
+			}
+			if zone.MaxLevel > 0 {
+				d.Level = min(d.Level, zone.MaxLevel)
+			}
+		}
+	}
+
+	level := math.Max(0.5, 1+config.NPCLevelStep*float64(d.Level-baseLevel))
+	d.HealthScale *= level
+	d.DamageScale *= level
+	d.XP = int(math.Round(float64(baseXP) * xpScale * level))
+	return d
+}
+
+func scaleOr1(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	return v
+}
+
+// nearbyCombatLevel averages the combat level of the players within
+// config.NPCLevelScaleRadius of (x, y) on level z (spectators don't count)
+func nearbyCombatLevel(w *ecs.World, x, y float64, z int) (int, bool) {
+	total, count := 0, 0
+	for _, id := range ecs.Query[components.StatsComponent](w) {
+		if !ecs.HasTag(w, id, components.TagPlayer) || IsSpectating(w, id) {
+			continue
+		}
+		trans, ok := ecs.GetComponent[components.TransformComponent](w, id)
+		if !ok || trans.Z != z || !geom.Within(trans.X, trans.Y, x, y, config.NPCLevelScaleRadius) {
+			continue
+		}
+		stats, _ := ecs.GetComponent[components.StatsComponent](w, id)
+		total += max(stats.Level, 1)
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return int(math.Round(float64(total) / float64(count))), true
+}
+
+// DamageScale is the multiplier on damage dealt by an entity: its NPC scaling and its
+// damage buffs (1 for neither)
+func DamageScale(w *ecs.World, id ecs.Entity) float64 {
+	scale := BuffMultiplier(w, id, components.BuffDamage)
+	if d, ok := ecs.GetComponent[components.DifficultyComponent](w, id); ok && d.DamageScale > 0 {
+		scale *= d.DamageScale
+	}
+	return scale
+}
+
+// AwardCombatXP gives a player the kill XP of an NPC, times scale (see
+// DiminishingReturns), and reports the XP gained and whether it raised their combat
+// level (see ApplyLevel)
+func AwardCombatXP(w *ecs.World, player, npc ecs.Entity, scale float64) (int, bool) {
+	d, ok := ecs.GetComponent[components.DifficultyComponent](w, npc)
+	stats, hasStats := ecs.GetComponent[components.StatsComponent](w, player)
+	if !ok || !hasStats || !ecs.HasTag(w, player, components.TagPlayer) || d.XP <= 0 {
+		return 0, false
+	}
+	xp := int(math.Round(float64(d.XP) * scale))
+	if xp <= 0 {
+		return 0, false
+	}
+	stats.XP += xp
+	ecs.AddComponent(w, player, *stats)
+	return xp, ApplyLevel(w, player)
+}
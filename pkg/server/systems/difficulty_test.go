@@ -0,0 +1,105 @@
+package systems
+
+import (
+	"math"
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+func difficultyMap() *world.Map {
+	return &world.Map{Zones: []world.Zone{
+		{ID: "plain", X: 0, Y: 0, Width: 1000, Height: 1000, HealthScale: 2, XPScale: 1.5},
+		{ID: "scaled", X: 5000, Y: 0, Width: 1000, Height: 1000, MinLevel: 5, MaxLevel: 10, LevelScaling: true},
+	}}
+}
+
+func addFighter(w *ecs.World, x, y float64, combatXP int) ecs.Entity {
+	id := w.NewEntity()
+	ecs.AddComponent(w, id, components.TransformComponent{X: x, Y: y})
+	level := components.CombatLevel(combatXP)
+	ecs.AddComponent(w, id, components.StatsComponent{MaxHealth: PlayerMaxHealth(level), CurrentHealth: 50, Level: level, XP: combatXP})
+	ecs.AddComponent(w, id, components.AttributesComponent{Base: PlayerAttributes(level)})
+	w.AddTags(id, components.TagPlayer)
+	return id
+}
+
+func TestNPCDifficultyZoneMultipliers(t *testing.T) {
+	w := ecs.NewWorld()
+	m := difficultyMap()
+	addFighter(w, 100, 100, 5000) // Level scaling is off here, so ignored
+
+	d := NPCDifficulty(w, m, 100, 100, 0, 3, 20)
+	if d.Level != 3 || d.HealthScale != 2 || d.DamageScale != 1 || d.XP != 30 {
+		t.Errorf("got %+v, want level 3, health x2, damage x1, 30 XP", d)
+	}
+
+	// Wilderness: untouched
+	d = NPCDifficulty(w, m, 3000, 100, 0, 3, 20)
+	if d.Level != 3 || d.HealthScale != 1 || d.DamageScale != 1 || d.XP != 20 {
+		t.Errorf("wilderness got %+v", d)
+	}
+}
+
+func TestNPCDifficultyLevelScaling(t *testing.T) {
+	w := ecs.NewWorld()
+	m := difficultyMap()
+
+	// Nobody around: the base level, raised to the zone minimum
+	d := NPCDifficulty(w, m, 5100, 100, 0, 3, 20)
+	if d.Level != 5 {
+		t.Errorf("empty zone level = %d, want the zone minimum 5", d.Level)
+	}
+
+	// Two fighters at combat levels 7 and 9 average to 8: five levels above base 3
+	addFighter(w, 5200, 100, 1800)       // Level 7
+	addFighter(w, 5300, 100, 3200)       // Level 9
+	other := addFighter(w, 5200, 100, 0) // Another level: doesn't count
+	ecs.AddComponent(w, other, components.TransformComponent{X: 5200, Y: 100, Z: 1})
+	addFighter(w, 5100+2000, 100, 0) // Too far
+	d = NPCDifficulty(w, m, 5100, 100, 0, 3, 20)
+	if d.Level != 8 {
+		t.Fatalf("level = %d, want 8", d.Level)
+	}
+	if want := 1.5; math.Abs(d.HealthScale-want) > 1e-9 || math.Abs(d.DamageScale-want) > 1e-9 || d.XP != 30 {
+		t.Errorf("got %+v, want x%.1f health and damage, 30 XP", d, want)
+	}
+
+	// Capped at the zone maximum
+	addFighter(w, 5200, 200, 50000)
+	addFighter(w, 5200, 300, 50000)
+	if d := NPCDifficulty(w, m, 5100, 100, 0, 3, 20); d.Level != 10 {
+		t.Errorf("level = %d, want the zone maximum 10", d.Level)
+	}
+}
+
+func TestAwardCombatXP(t *testing.T) {
+	w := ecs.NewWorld()
+	player := addFighter(w, 0, 0, 40)
+	npc := w.NewEntity()
+	ecs.AddComponent(w, npc, components.DifficultyComponent{Level: 1, HealthScale: 1, DamageScale: 1, XP: 15})
+
+	xp, leveledUp := AwardCombatXP(w, player, npc, 1)
+	stats, _ := ecs.GetComponent[components.StatsComponent](w, player)
+	if xp != 15 || !leveledUp || stats.XP != 55 || stats.Level != 2 {
+		t.Errorf("xp %d, leveled up %v, total %d, level %d; want 15, true, 55, 2", xp, leveledUp, stats.XP, stats.Level)
+	}
+	// The new level's health is healed on top of what the player had
+	if stats.MaxHealth != 110 || stats.CurrentHealth != 60 {
+		t.Errorf("health %.0f/%.0f, want 60/110", stats.CurrentHealth, stats.MaxHealth)
+	}
+
+	// Attributes grow every few levels
+	stats.XP = components.XPForLevel(4)
+	ecs.AddComponent(w, player, *stats)
+	ApplyLevel(w, player)
+	attrs, _ := ecs.GetComponent[components.AttributesComponent](w, player)
+	if attrs.Base.Str != 6 || attrs.Base.Dex != 6 || attrs.Base.Int != 6 {
+		t.Errorf("level 4 attributes %+v, want 6 each", attrs.Base)
+	}
+	if components.CombatLevel(components.XPForLevel(7)) != 7 || components.CombatLevel(components.XPForLevel(7)-1) != 6 {
+		t.Error("XPForLevel isn't the inverse of CombatLevel")
+	}
+}
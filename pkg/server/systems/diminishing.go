@@ -0,0 +1,100 @@
+package systems
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+const (
+	DiminishWindow    = 10 * time.Minute // Kills of one spawner's NPCs older than this are forgotten
+	DiminishFreeKills = 8                // Kills in the window at full XP and loot
+	DiminishStep      = 0.15             // Reward taken off for each kill past those
+	DiminishFloor     = 0.1              // Least of the usual reward a kill is still worth
+)
+
+// DiminishingReturns thins out the XP and loot a player gets for killing the same
+// spawner's NPCs over and over, so camping one spot pays less than roaming. Kills are
+// counted per player per spawner over a sliding DiminishWindow. Players are tracked by
+// username so relogging doesn't reset them.
+type DiminishingReturns struct {
+	// Clock (replaced in tests)
+	Now func() time.Time
+
+	kills map[string]map[string][]time.Time // Username -> spawner -> kill times, oldest first
+}
+
+func NewDiminishingReturns() *DiminishingReturns {
+	return &DiminishingReturns{Now: time.Now, kills: make(map[string]map[string][]time.Time)}
+}
+
+// Kill records a player's kill of a spawner's NPC and returns how many of its NPCs they
+// have killed within the window, this one included
+func (d *DiminishingReturns) Kill(username, spawner string) int {
+	now := d.Now()
+	spawners := d.kills[username]
+	if spawners == nil {
+		spawners = make(map[string][]time.Time)
+		d.kills[username] = spawners
+	}
+	// Forget whatever has aged out of the window, at every spawner the player hunted
+	for key, times := range spawners {
+		if times = recentKills(times, now); len(times) == 0 {
+			delete(spawners, key)
+		} else {
+			spawners[key] = times
+		}
+	}
+	spawners[spawner] = append(spawners[spawner], now)
+	return len(spawners[spawner])
+}
+
+// Scale is how much of the usual XP and loot a player's latest kill of a spawner's NPC
+// is worth, from 1 down to DiminishFloor
+func (d *DiminishingReturns) Scale(username, spawner string) float64 {
+	return DiminishScale(len(recentKills(d.kills[username][spawner], d.Now())))
+}
+
+// DiminishScale is the reward scale of a spawner's kills-th kill within the window
+func DiminishScale(kills int) float64 {
+	if kills <= DiminishFreeKills {
+		return 1
+	}
+	return math.Max(DiminishFloor, 1-DiminishStep*float64(kills-DiminishFreeKills))
+}
+
+func recentKills(times []time.Time, now time.Time) []time.Time {
+	i := 0
+	for i < len(times) && now.Sub(times[i]) > DiminishWindow {
+		i++
+	}
+	return times[i:]
+}
+
+// SpawnerOf names the spawner or spawn region an NPC came from ("" for anything else,
+// e.g. world event spawns)
+func SpawnerOf(w *ecs.World, id ecs.Entity) string {
+	if respawn, ok := ecs.GetComponent[components.RespawnComponent](w, id); ok {
+		return fmt.Sprintf("%d/%s", respawn.SpawnZ, spawnerKey(respawn.CharID, respawn.SpawnX, respawn.SpawnY))
+	}
+	if region, ok := ecs.GetComponent[components.SpawnRegionComponent](w, id); ok {
+		return region.Region
+	}
+	return ""
+}
+
+// ThinDrops scales the chance of every drop in a table (a copy; the table is untouched)
+func ThinDrops(table []components.LootEntry, scale float64) []components.LootEntry {
+	if scale >= 1 {
+		return table
+	}
+	thinned := make([]components.LootEntry, len(table))
+	for i, entry := range table {
+		entry.Chance *= scale
+		thinned[i] = entry
+	}
+	return thinned
+}
@@ -0,0 +1,57 @@
+package systems
+
+import (
+	"testing"
+	"time"
+
+	"henry/pkg/shared/components"
+)
+
+func TestFarmingOneSpawnerDiminishesRewards(t *testing.T) {
+	d := NewDiminishingReturns()
+	now := time.Unix(1_000_000, 0)
+	d.Now = func() time.Time { return now }
+
+	for range DiminishFreeKills {
+		d.Kill("alice", "0/wolf@100,100")
+		now = now.Add(time.Second)
+	}
+	if s := d.Scale("alice", "0/wolf@100,100"); s != 1 {
+		t.Fatalf("scale %.2f within the free kills, want 1", s)
+	}
+	if n := d.Kill("alice", "0/wolf@100,100"); n != DiminishFreeKills+1 {
+		t.Fatalf("%d kills counted, want %d", n, DiminishFreeKills+1)
+	}
+	if s := d.Scale("alice", "0/wolf@100,100"); s != 1-DiminishStep {
+		t.Errorf("scale %.2f past the free kills, want %.2f", s, 1-DiminishStep)
+	}
+
+	// Other spawners and other players aren't affected
+	if s := d.Scale("alice", "0/slime@300,300"); s != 1 {
+		t.Errorf("another spawner scaled %.2f", s)
+	}
+	if s := d.Scale("bob", "0/wolf@100,100"); s != 1 {
+		t.Errorf("another player scaled %.2f", s)
+	}
+
+	for range 50 {
+		d.Kill("alice", "0/wolf@100,100")
+	}
+	if s := d.Scale("alice", "0/wolf@100,100"); s != DiminishFloor {
+		t.Errorf("scale %.2f after heavy farming, want the floor %.2f", s, DiminishFloor)
+	}
+
+	// Roaming elsewhere for a while restores it
+	now = now.Add(DiminishWindow + time.Second)
+	if s := d.Scale("alice", "0/wolf@100,100"); s != 1 {
+		t.Errorf("scale %.2f once the window passed, want 1", s)
+	}
+}
+
+func TestThinDropsLeavesTheTableAlone(t *testing.T) {
+	table := []components.LootEntry{{ItemID: "wolf_pelt", Quantity: 1, Chance: 0.5}}
+	thinned := ThinDrops(table, 0.5)
+	if thinned[0].Chance != 0.25 || table[0].Chance != 0.5 {
+		t.Errorf("chances %.2f (thinned) and %.2f (table), want 0.25 and 0.5", thinned[0].Chance, table[0].Chance)
+	}
+}
@@ -0,0 +1,240 @@
+package systems
+
+import (
+	"errors"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"log"
+)
+
+const (
+	DuelRequestRange   = 320.0 // Max distance (px) to challenge / accept
+	DuelRequestTimeout = 30.0  // Seconds an invite stays open
+	DuelRingRadius     = 400.0 // Leaving the ring (from its center) forfeits
+	DuelCountdown      = 3.0   // Seconds between accept and the first allowed hit
+	DuelTimeLimit      = 180.0 // Fights still going after this end in a draw
+)
+
+// Ways a duel can end
+const (
+	DuelEndDefeat  = "defeat"  // Loser dropped to 1 HP
+	DuelEndForfeit = "forfeit" // Loser gave up or disconnected
+	DuelEndRing    = "ring"    // Loser left the ring
+	DuelEndTimeout = "timeout" // Draw
+)
+
+// Duel is a consensual 1v1 between two players. While it runs they can hurt each other
+// anywhere; nobody dies, the fight ends when one of them drops to 1 HP.
+type Duel struct {
+	A, B             ecs.Entity // A challenged B
+	CenterX, CenterY float64
+	Radius           float64
+	Countdown        float64
+	TimeLeft         float64
+}
+
+// Started reports whether the countdown is over
+func (d *Duel) Started() bool {
+	return d.Countdown <= 0
+}
+
+// Opponent returns the other duelist
+func (d *Duel) Opponent(id ecs.Entity) ecs.Entity {
+	if id == d.A {
+		return d.B
+	}
+	return d.A
+}
+
+type duelInvite struct {
+	Challenger ecs.Entity
+	TimeLeft   float64
+}
+
+type DuelSystem struct {
+	World *ecs.World
+
+	// Hooks provided by the GameServer
+	OnInvite func(target, challenger ecs.Entity)
+	OnUpdate func(duel *Duel) // Duel started or countdown finished
+	// OnEnd reports the result; for DuelEndTimeout neither side won
+	OnEnd func(duel *Duel, winner, loser ecs.Entity, reason string)
+
+	invites map[ecs.Entity]duelInvite // Target -> pending invite
+	duels   map[ecs.Entity]*Duel      // Both duelists -> their duel
+}
+
+func NewDuelSystem(world *ecs.World) *DuelSystem {
+	return &DuelSystem{
+		World:   world,
+		invites: make(map[ecs.Entity]duelInvite),
+		duels:   make(map[ecs.Entity]*Duel),
+	}
+}
+
+// Request challenges target. It replaces any older invite the target had.
+func (s *DuelSystem) Request(challenger, target ecs.Entity) error {
+	if challenger == target {
+		return errors.New("you can't duel yourself")
+	}
+	if !ecs.HasTag(s.World, target, components.TagPlayer) || IsSpectating(s.World, target) {
+		return errors.New("you can only duel players")
+	}
+	if s.duels[challenger] != nil || s.duels[target] != nil {
+		return errors.New("already in a duel")
+	}
+	if !s.inRange(challenger, target) {
+		return errors.New("too far away")
+	}
+
+	s.invites[target] = duelInvite{Challenger: challenger, TimeLeft: DuelRequestTimeout}
+	if s.OnInvite != nil {
+		s.OnInvite(target, challenger)
+	}
+	return nil
+}
+
+// Accept starts the duel the challenger offered, with the ring centered between both players
+func (s *DuelSystem) Accept(target, challenger ecs.Entity) (*Duel, error) {
+	invite, ok := s.invites[target]
+	if !ok || invite.Challenger != challenger {
+		return nil, errors.New("no pending challenge")
+	}
+	delete(s.invites, target)
+	if s.duels[challenger] != nil || s.duels[target] != nil {
+		return nil, errors.New("already in a duel")
+	}
+	if !s.inRange(challenger, target) {
+		return nil, errors.New("too far away")
+	}
+
+	a, _ := ecs.GetComponent[components.TransformComponent](s.World, challenger)
+	b, _ := ecs.GetComponent[components.TransformComponent](s.World, target)
+	duel := &Duel{
+		A:         challenger,
+		B:         target,
+		CenterX:   (a.X + b.X) / 2,
+		CenterY:   (a.Y + b.Y) / 2,
+		Radius:    DuelRingRadius,
+		Countdown: DuelCountdown,
+		TimeLeft:  DuelTimeLimit,
+	}
+	s.duels[challenger] = duel
+	s.duels[target] = duel
+	log.Printf("Duel started: Entity %d vs Entity %d", challenger, target)
+	if s.OnUpdate != nil {
+		s.OnUpdate(duel)
+	}
+	return duel, nil
+}
+
+// Decline drops a pending invite
+func (s *DuelSystem) Decline(target, challenger ecs.Entity) {
+	if invite, ok := s.invites[target]; ok && invite.Challenger == challenger {
+		delete(s.invites, target)
+	}
+}
+
+// Forfeit ends the player's duel as a loss (also used on disconnect)
+func (s *DuelSystem) Forfeit(player ecs.Entity) {
+	delete(s.invites, player)
+	for target, invite := range s.invites {
+		if invite.Challenger == player {
+			delete(s.invites, target)
+		}
+	}
+	if duel := s.duels[player]; duel != nil {
+		s.end(duel, duel.Opponent(player), player, DuelEndForfeit)
+	}
+}
+
+// DuelOf returns the player's duel, or nil
+func (s *DuelSystem) DuelOf(player ecs.Entity) *Duel {
+	return s.duels[player]
+}
+
+// IsDueling reports whether a and b are fighting each other right now (countdown over)
+func (s *DuelSystem) IsDueling(a, b ecs.Entity) bool {
+	duel := s.duels[a]
+	return duel != nil && duel.Started() && duel.Opponent(a) == b
+}
+
+// Defeat ends the duel with the loser at 1 HP. Call when a duel hit would drop them below it.
+func (s *DuelSystem) Defeat(loser ecs.Entity) {
+	if duel := s.duels[loser]; duel != nil {
+		s.end(duel, duel.Opponent(loser), loser, DuelEndDefeat)
+	}
+}
+
+// Update expires invites, runs countdowns and enforces the ring and time limit
+func (s *DuelSystem) Update(dt float64) {
+	for target, invite := range s.invites {
+		invite.TimeLeft -= dt
+		if invite.TimeLeft <= 0 {
+			delete(s.invites, target)
+			continue
+		}
+		s.invites[target] = invite
+	}
+
+	seen := make(map[*Duel]bool)
+	for _, duel := range s.duels {
+		if seen[duel] {
+			continue
+		}
+		seen[duel] = true
+
+		if !duel.Started() {
+			duel.Countdown -= dt
+			if duel.Started() {
+				duel.Countdown = 0
+				if s.OnUpdate != nil {
+					s.OnUpdate(duel)
+				}
+			}
+			continue
+		}
+
+		duel.TimeLeft -= dt
+		if duel.TimeLeft <= 0 {
+			s.end(duel, duel.A, duel.B, DuelEndTimeout)
+			continue
+		}
+		for _, id := range []ecs.Entity{duel.A, duel.B} {
+			if !s.insideRing(duel, id) {
+				s.end(duel, duel.Opponent(id), id, DuelEndRing)
+				break
+			}
+		}
+	}
+}
+
+func (s *DuelSystem) end(duel *Duel, winner, loser ecs.Entity, reason string) {
+	delete(s.duels, duel.A)
+	delete(s.duels, duel.B)
+	log.Printf("Duel ended (%s): Entity %d beat Entity %d", reason, winner, loser)
+	if s.OnEnd != nil {
+		s.OnEnd(duel, winner, loser, reason)
+	}
+}
+
+func (s *DuelSystem) insideRing(duel *Duel, id ecs.Entity) bool {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+	if !ok {
+		return false
+	}
+	return geom.Within(trans.X, trans.Y, duel.CenterX, duel.CenterY, duel.Radius)
+}
+
+func (s *DuelSystem) inRange(a, b ecs.Entity) bool {
+	ta, ok := ecs.GetComponent[components.TransformComponent](s.World, a)
+	if !ok {
+		return false
+	}
+	tb, ok := ecs.GetComponent[components.TransformComponent](s.World, b)
+	if !ok {
+		return false
+	}
+	return ta.Z == tb.Z && geom.Within(ta.X, ta.Y, tb.X, tb.Y, DuelRequestRange)
+}
@@ -0,0 +1,62 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+// duelWorld spawns two players next to each other and starts a duel between them
+func duelWorld(t *testing.T) (*DuelSystem, ecs.Entity, ecs.Entity, *string) {
+	t.Helper()
+	w := ecs.NewWorld()
+	s := NewDuelSystem(w)
+
+	a := spawnTestPlayers(w, 1, 100, 100)[0]
+	b := spawnTestPlayers(w, 1, 200, 100)[0]
+
+	reason := new(string)
+	s.OnEnd = func(duel *Duel, winner, loser ecs.Entity, r string) { *reason = r }
+
+	if err := s.Request(a, b); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if _, err := s.Accept(b, a); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	return s, a, b, reason
+}
+
+func TestDuelCountdownGatesDamage(t *testing.T) {
+	s, a, b, _ := duelWorld(t)
+	if s.IsDueling(a, b) {
+		t.Fatal("duelists can hit each other during the countdown")
+	}
+	s.Update(DuelCountdown + 0.1)
+	if !s.IsDueling(a, b) || !s.IsDueling(b, a) {
+		t.Fatal("duel should be live after the countdown")
+	}
+}
+
+func TestDuelLeavingRingLoses(t *testing.T) {
+	s, a, b, reason := duelWorld(t)
+	s.Update(DuelCountdown + 0.1)
+
+	ecs.AddComponent(s.World, b, components.TransformComponent{X: 150 + DuelRingRadius + 10, Y: 100})
+	s.Update(0.1)
+	if *reason != DuelEndRing {
+		t.Fatalf("expected ring loss, got %q", *reason)
+	}
+	if s.IsDueling(a, b) || s.DuelOf(a) != nil {
+		t.Error("duel should be over")
+	}
+}
+
+func TestDuelAcceptNeedsInvite(t *testing.T) {
+	s, a, b, _ := duelWorld(t)
+	s.Forfeit(a)
+	if _, err := s.Accept(b, a); err == nil {
+		t.Error("accepting without a pending invite should fail")
+	}
+}
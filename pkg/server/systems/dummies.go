@@ -0,0 +1,81 @@
+package systems
+
+import (
+	"fmt"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+const (
+	DummyIdleReport = 3.0  // Seconds without hits before a session is reported
+	DummyMaxSession = 60.0 // Long runs are reported (and restarted) after this many seconds
+)
+
+type TrainingDummySystem struct {
+	World *ecs.World
+
+	// Called with a finished session summary for the attacker
+	OnReport func(attacker ecs.Entity, msg string)
+
+	elapsed float64
+}
+
+func NewTrainingDummySystem(world *ecs.World) *TrainingDummySystem {
+	return &TrainingDummySystem{
+		World: world,
+	}
+}
+
+// RecordHit adds damage to the attacker's running session. Returns false if target isn't a dummy.
+func (s *TrainingDummySystem) RecordHit(dummy, attacker ecs.Entity, damage float64) bool {
+	td, _ := ecs.GetComponent[components.TrainingDummyComponent](s.World, dummy)
+	if td == nil {
+		return false
+	}
+	if td.Sessions == nil {
+		td.Sessions = make(map[ecs.Entity]*components.DummySession)
+		ecs.AddComponent(s.World, dummy, *td)
+	}
+
+	session, ok := td.Sessions[attacker]
+	if !ok {
+		session = &components.DummySession{Start: s.elapsed}
+		td.Sessions[attacker] = session
+	}
+	session.Damage += damage
+	session.Hits++
+	session.LastHit = s.elapsed
+	return true
+}
+
+// Update reports sessions that went idle or ran past DummyMaxSession
+func (s *TrainingDummySystem) Update(dt float64) {
+	s.elapsed += dt
+
+	for _, id := range ecs.Query[components.TrainingDummyComponent](s.World) {
+		td, _ := ecs.GetComponent[components.TrainingDummyComponent](s.World, id)
+		if td == nil {
+			continue
+		}
+
+		for attacker, session := range td.Sessions {
+			idle := s.elapsed-session.LastHit >= DummyIdleReport
+			long := s.elapsed-session.Start >= DummyMaxSession
+			if !idle && !long {
+				continue
+			}
+			delete(td.Sessions, attacker)
+
+			// Measure up to the last hit so idle time doesn't drag DPS down
+			duration := session.LastHit - session.Start
+			if duration < 1 {
+				duration = 1
+			}
+			msg := fmt.Sprintf("Training Dummy: %.0f damage in %d hits over %.1fs (%.1f DPS)",
+				session.Damage, session.Hits, duration, session.Damage/duration)
+			if s.OnReport != nil {
+				s.OnReport(attacker, msg)
+			}
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package systems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"henry/pkg/items"
+	"henry/pkg/shared/config"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+)
+
+// Seconds between checks of the economy file for edits
+const EconomyReloadInterval = 5.0
+
+// EconomyConfig holds the designer-tunable economy numbers, loaded from data/economy/economy.json
+type EconomyConfig struct {
+	GoldDrops   map[string]GoldDrop `json:"gold_drops"`  // Character ID -> gold dropped on death
+	ItemValues  map[string]int      `json:"item_values"` // Base value in gold (unlisted items can't be traded)
+	Vendor      VendorMargins       `json:"vendor"`
+	Repair      RepairCosts         `json:"repair"`
+	WaypointFee int                 `json:"waypoint_fee"` // Gold per fast travel
+}
+
+// GoldDrop is a gold pile rolled when a character dies
+type GoldDrop struct {
+	Min    int     `json:"min"`
+	Max    int     `json:"max"`
+	Chance float64 `json:"chance"` // Probability (0-1) of dropping anything
+}
+
+// VendorMargins scale an item's base value into shop prices
+type VendorMargins struct {
+	BuyMarkup float64 `json:"buy_markup"` // Players pay value * BuyMarkup
+	SellRatio float64 `json:"sell_ratio"` // Players receive value * SellRatio
+}
+
+// RepairCosts prices restoring a worn item
+type RepairCosts struct {
+	PercentOfValue float64 `json:"percent_of_value"` // Full repair cost as a fraction of base value
+	MinCost        int     `json:"min_cost"`
+}
+
+// DefaultEconomy is used when no economy file exists
+func DefaultEconomy() EconomyConfig {
+	return EconomyConfig{
+		GoldDrops:   map[string]GoldDrop{},
+		ItemValues:  map[string]int{},
+		Vendor:      VendorMargins{BuyMarkup: 1.0, SellRatio: 0.25},
+		Repair:      RepairCosts{PercentOfValue: 0.1, MinCost: 1},
+		WaypointFee: config.WaypointTravelFee,
+	}
+}
+
+// Validate rejects configs that would break the economy (e.g. selling for more than buying)
+func (c *EconomyConfig) Validate() error {
+	for charID, drop := range c.GoldDrops {
+		if drop.Min < 0 || drop.Max < drop.Min {
+			return fmt.Errorf("gold drop %s: invalid range %d-%d", charID, drop.Min, drop.Max)
+		}
+		if drop.Chance < 0 || drop.Chance > 1 {
+			return fmt.Errorf("gold drop %s: chance %.2f out of range", charID, drop.Chance)
+		}
+	}
+	for itemID, value := range c.ItemValues {
+		if _, ok := items.Get(itemID); !ok {
+			return fmt.Errorf("item value %s: unknown item", itemID)
+		}
+		if value < 0 {
+			return fmt.Errorf("item value %s: negative", itemID)
+		}
+	}
+	if c.Vendor.BuyMarkup <= 0 || c.Vendor.SellRatio < 0 {
+		return errors.New("vendor margins must be positive")
+	}
+	if c.Vendor.SellRatio > c.Vendor.BuyMarkup {
+		return errors.New("vendor sell_ratio above buy_markup lets players farm gold")
+	}
+	if c.Repair.PercentOfValue < 0 || c.Repair.MinCost < 0 {
+		return errors.New("repair costs must not be negative")
+	}
+	if c.WaypointFee < 0 {
+		return errors.New("waypoint fee must not be negative")
+	}
+	return nil
+}
+
+// LoadEconomy reads and validates an economy config. Omitted sections keep their defaults.
+func LoadEconomy(path string) (EconomyConfig, error) {
+	cfg := DefaultEconomy()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse economy json: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// EconomySystem serves the current economy config and reloads it when the file changes
+type EconomySystem struct {
+	Path string
+
+	cfg        EconomyConfig
+	modTime    time.Time
+	checkTimer float64
+	rng        *rand.Rand
+}
+
+func NewEconomySystem(path string) *EconomySystem {
+	s := &EconomySystem{
+		Path: path,
+		cfg:  DefaultEconomy(),
+		rng:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	if err := s.Reload(); err != nil {
+		log.Printf("Using default economy: %v", err)
+	}
+	return s
+}
+
+// Reload re-reads the economy file. A broken file keeps the previous config.
+func (s *EconomySystem) Reload() error {
+	info, err := os.Stat(s.Path)
+	if err != nil {
+		return err
+	}
+	s.modTime = info.ModTime()
+
+	cfg, err := LoadEconomy(s.Path)
+	if err != nil {
+		return err
+	}
+	s.cfg = cfg
+	log.Printf("Economy loaded from %s (%d gold drops, %d item values)", s.Path, len(cfg.GoldDrops), len(cfg.ItemValues))
+	return nil
+}
+
+// Update polls the file's modification time and hot-reloads edits
+func (s *EconomySystem) Update(dt float64) {
+	s.checkTimer += dt
+	if s.checkTimer < EconomyReloadInterval {
+		return
+	}
+	s.checkTimer = 0
+
+	info, err := os.Stat(s.Path)
+	if err != nil || info.ModTime().Equal(s.modTime) {
+		return
+	}
+	if err := s.Reload(); err != nil {
+		log.Printf("Economy reload failed, keeping previous config: %v", err)
+	}
+}
+
+// Config returns the active config (treat as read-only)
+func (s *EconomySystem) Config() *EconomyConfig {
+	return &s.cfg
+}
+
+// RollGoldDrop returns the gold a dying character drops (0 = nothing)
+func (s *EconomySystem) RollGoldDrop(charID string) int {
+	drop, ok := s.cfg.GoldDrops[charID]
+	if !ok || drop.Max <= 0 || s.rng.Float64() >= drop.Chance {
+		return 0
+	}
+	return drop.Min + s.rng.Intn(drop.Max-drop.Min+1)
+}
+
+// BuyPrice is what a vendor charges for one item (false = not for trade)
+func (s *EconomySystem) BuyPrice(itemID string) (int, bool) {
+	value, ok := s.cfg.ItemValues[itemID]
+	if !ok {
+		return 0, false
+	}
+	return int(math.Ceil(float64(value) * s.cfg.Vendor.BuyMarkup)), true
+}
+
+// SellPrice is what a vendor pays for one item (false = not for trade)
+func (s *EconomySystem) SellPrice(itemID string) (int, bool) {
+	value, ok := s.cfg.ItemValues[itemID]
+	if !ok {
+		return 0, false
+	}
+	return int(math.Floor(float64(value) * s.cfg.Vendor.SellRatio)), true
+}
+
+// RepairCost prices repairing wear (0-1, fraction of durability lost) on an item
+func (s *EconomySystem) RepairCost(itemID string, wear float64) int {
+	if wear <= 0 {
+		return 0
+	}
+	value := s.cfg.ItemValues[itemID]
+	cost := int(math.Ceil(float64(value) * s.cfg.Repair.PercentOfValue * math.Min(wear, 1)))
+	return max(cost, s.cfg.Repair.MinCost)
+}
+
+// WaypointFee is the gold charged per fast travel
+func (s *EconomySystem) WaypointFee() int {
+	return s.cfg.WaypointFee
+}
@@ -0,0 +1,51 @@
+package systems
+
+import (
+	"sync"
+	"time"
+)
+
+// Notable server events, for listeners outside the game (webhooks)
+const (
+	EventServerStart = "server_start"
+	EventServerStop  = "server_stop"
+	EventBossKilled  = "boss_killed"
+	EventMaxLevel    = "max_level"
+	EventBan         = "ban"
+	EventMacroFlag   = "macro_flag"
+)
+
+type ServerEvent struct {
+	Kind    string
+	Title   string
+	Message string
+	Time    time.Time
+}
+
+// EventBus hands server events to every subscriber, in the order they were published.
+// Handlers run on the publisher's goroutine (often with the server lock held), so they
+// must not block.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers []func(ServerEvent)
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+func (b *EventBus) Subscribe(handler func(ServerEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish sends an event to the subscribers, stamping it with the current time
+func (b *EventBus) Publish(kind, title, message string) {
+	event := ServerEvent{Kind: kind, Title: title, Message: message, Time: time.Now()}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+}
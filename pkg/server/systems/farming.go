@@ -0,0 +1,239 @@
+package systems
+
+import (
+	"errors"
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"henry/pkg/shared/world"
+	"henry/pkg/storage"
+	"log"
+)
+
+const FarmRange = 128.0 // Max distance (px) between the farmer and the plot
+
+type plotKey struct {
+	Level, X, Y int
+}
+
+// cropPlot is a seed growing on a farmland tile
+type cropPlot struct {
+	SeedID string
+	Owner  string // Planter's username, the only one who may harvest
+	Growth float64
+	Stage  int
+}
+
+// FarmSystem grows crops planted on farmland tiles. Crops live in the map's object
+// layer (see world.CropObject) rather than as entities, so clients render growth from
+// object layer updates.
+type FarmSystem struct {
+	World *ecs.World
+	Maps  map[int]*world.Map
+	Audit *ItemAudit // Optional item audit trail
+
+	// Called whenever a plot's object changes (planted, grew, harvested)
+	OnObjectChange func(level, tx, ty, object int)
+
+	plots map[plotKey]*cropPlot
+	dirty bool // Changed since the last Save
+}
+
+func NewFarmSystem(world *ecs.World, maps map[int]*world.Map) *FarmSystem {
+	return &FarmSystem{
+		World: world,
+		Maps:  maps,
+		plots: make(map[plotKey]*cropPlot),
+	}
+}
+
+// Plant sows one of the farmer's seeds on an empty farmland tile of their level
+func (s *FarmSystem) Plant(farmer ecs.Entity, owner, seedID string, tx, ty int) error {
+	def, ok := items.Get(seedID)
+	if !ok || def.Crop == nil {
+		return errors.New("that can't be planted")
+	}
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, farmer)
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, farmer)
+	if trans == nil || inv == nil {
+		return errors.New("invalid farmer")
+	}
+	m, ok := s.Maps[trans.Z]
+	if !ok || tx < 0 || ty < 0 || tx >= m.Width || ty >= m.Height || m.Tiles[ty][tx].Type != world.TileFarmland {
+		return errors.New("seeds only grow on farmland")
+	}
+	if !s.inRange(trans, tx, ty) {
+		return errors.New("too far away")
+	}
+	if m.Objects[ty][tx] != 0 {
+		return errors.New("something is already growing there")
+	}
+
+	if err := items.RemoveItemByID(inv, seedID, 1); err != nil {
+		return err
+	}
+	ecs.AddComponent(s.World, farmer, *inv)
+	s.Audit.Record("planted", farmer, "", seedID, "", 1)
+
+	s.plant(plotKey{trans.Z, tx, ty}, &cropPlot{SeedID: seedID, Owner: owner})
+	return nil
+}
+
+// Harvest picks a ripe crop, giving its produce and the seed back
+func (s *FarmSystem) Harvest(farmer ecs.Entity, owner string, tx, ty int) (items.ItemDefinition, int, error) {
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, farmer)
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, farmer)
+	if trans == nil || inv == nil {
+		return items.ItemDefinition{}, 0, errors.New("invalid farmer")
+	}
+	key := plotKey{trans.Z, tx, ty}
+	plot, ok := s.plots[key]
+	if !ok {
+		return items.ItemDefinition{}, 0, errors.New("nothing is growing there")
+	}
+	if !s.inRange(trans, tx, ty) {
+		return items.ItemDefinition{}, 0, errors.New("too far away")
+	}
+	if plot.Owner != owner {
+		return items.ItemDefinition{}, 0, errors.New("this crop was planted by " + plot.Owner)
+	}
+	if plot.Stage < world.CropRipe {
+		return items.ItemDefinition{}, 0, errors.New("not ripe yet")
+	}
+
+	seed, _ := items.Get(plot.SeedID)
+	produce, _ := items.Get(seed.Crop.Produce)
+	if err := items.AddItem(inv, produce.ID, seed.Crop.Yield); err != nil {
+		return items.ItemDefinition{}, 0, err
+	}
+	if err := items.AddItem(inv, plot.SeedID, 1); err != nil {
+		items.RemoveItemByID(inv, produce.ID, seed.Crop.Yield)
+		return items.ItemDefinition{}, 0, err
+	}
+	ecs.AddComponent(s.World, farmer, *inv)
+	s.Audit.Record("harvested", farmer, "", produce.ID, "", seed.Crop.Yield)
+	s.Audit.Record("harvested", farmer, "", plot.SeedID, "", 1)
+
+	delete(s.plots, key)
+	s.setObject(key, 0)
+	return produce, seed.Crop.Yield, nil
+}
+
+// Update grows every planted crop, advancing its object when it reaches a new stage
+func (s *FarmSystem) Update(dt float64) {
+	for key, plot := range s.plots {
+		if plot.Stage >= world.CropRipe {
+			continue
+		}
+		plot.Growth += dt
+		s.dirty = true
+		if stage := cropStage(plot); stage != plot.Stage {
+			plot.Stage = stage
+			s.setObject(key, cropObjectOf(plot))
+		}
+	}
+}
+
+// Ripe counts a player's crops ready to harvest
+func (s *FarmSystem) Ripe(owner string) int {
+	n := 0
+	for _, plot := range s.plots {
+		if plot.Owner == owner && plot.Stage >= world.CropRipe {
+			n++
+		}
+	}
+	return n
+}
+
+// Dirty reports whether crops changed since the last Save
+func (s *FarmSystem) Dirty() bool {
+	return s.dirty
+}
+
+// Save lists every planted crop for storage.SaveCrops
+func (s *FarmSystem) Save() []storage.CropSave {
+	saved := make([]storage.CropSave, 0, len(s.plots))
+	for key, plot := range s.plots {
+		saved = append(saved, storage.CropSave{
+			SeedID: plot.SeedID,
+			Owner:  plot.Owner,
+			Level:  key.Level,
+			TileX:  key.X,
+			TileY:  key.Y,
+			Growth: plot.Growth,
+		})
+	}
+	s.dirty = false
+	return saved
+}
+
+// Load replants saved crops, skipping any whose seed or farmland no longer exists
+func (s *FarmSystem) Load(saved []storage.CropSave) {
+	loaded := 0
+	for _, c := range saved {
+		def, ok := items.Get(c.SeedID)
+		m, mapOK := s.Maps[c.Level]
+		if !ok || def.Crop == nil || !mapOK || c.TileX < 0 || c.TileY < 0 || c.TileX >= m.Width || c.TileY >= m.Height ||
+			m.Tiles[c.TileY][c.TileX].Type != world.TileFarmland {
+			log.Printf("Skipping crop %s of %s at %d,%d (level %d)", c.SeedID, c.Owner, c.TileX, c.TileY, c.Level)
+			continue
+		}
+		s.plant(plotKey{c.Level, c.TileX, c.TileY}, &cropPlot{SeedID: c.SeedID, Owner: c.Owner, Growth: c.Growth})
+		loaded++
+	}
+	s.dirty = false
+	log.Printf("Loaded %d crops", loaded)
+}
+
+// ReloadLevel puts a level's crops back into its freshly loaded map, dropping any whose
+// tile is no longer farmland
+func (s *FarmSystem) ReloadLevel(level int) {
+	m, ok := s.Maps[level]
+	for key, plot := range s.plots {
+		if key.Level != level {
+			continue
+		}
+		if !ok || key.X >= m.Width || key.Y >= m.Height || m.Tiles[key.Y][key.X].Type != world.TileFarmland {
+			log.Printf("Dropping crop %s of %s at %d,%d (level %d): no farmland after map reload", plot.SeedID, plot.Owner, key.X, key.Y, level)
+			delete(s.plots, key)
+			s.dirty = true
+			continue
+		}
+		s.setObject(key, cropObjectOf(plot))
+	}
+}
+
+func (s *FarmSystem) plant(key plotKey, plot *cropPlot) {
+	plot.Stage = cropStage(plot)
+	s.plots[key] = plot
+	s.setObject(key, cropObjectOf(plot))
+}
+
+func (s *FarmSystem) setObject(key plotKey, object int) {
+	s.Maps[key.Level].Objects[key.Y][key.X] = object
+	s.dirty = true
+	if s.OnObjectChange != nil {
+		s.OnObjectChange(key.Level, key.X, key.Y, object)
+	}
+}
+
+func (s *FarmSystem) inRange(trans *components.TransformComponent, tx, ty int) bool {
+	tileSize := float64(config.TileSize)
+	return geom.Within(trans.X, trans.Y, float64(tx)*tileSize, float64(ty)*tileSize, FarmRange)
+}
+
+// cropStage spreads the growing stages evenly over the seed's grow time
+func cropStage(plot *cropPlot) int {
+	def, _ := items.Get(plot.SeedID)
+	if plot.Growth >= def.Crop.GrowSeconds {
+		return world.CropRipe
+	}
+	return int(plot.Growth / def.Crop.GrowSeconds * world.CropRipe)
+}
+
+func cropObjectOf(plot *cropPlot) int {
+	def, _ := items.Get(plot.SeedID)
+	return world.CropObject(def.Crop.Kind, plot.Stage)
+}
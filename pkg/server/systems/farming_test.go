@@ -0,0 +1,83 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+func newFarm() map[int]*world.Map {
+	m := world.NewMap(10, 10)
+	m.Tiles[2][2] = world.Tile{Type: world.TileFarmland}
+	return map[int]*world.Map{0: m}
+}
+
+func TestCropGrowsAndHarvests(t *testing.T) {
+	w := ecs.NewWorld()
+	maps := newFarm()
+	s := NewFarmSystem(w, maps)
+	var updates []int
+	s.OnObjectChange = func(level, tx, ty, object int) { updates = append(updates, object) }
+
+	farmer := newBuilder(w, 2, 3, "seed_wheat")
+	if err := s.Plant(farmer, "alice", "seed_wheat", 3, 3); err == nil {
+		t.Error("planted on grass")
+	}
+	if err := s.Plant(farmer, "alice", "seed_wheat", 2, 2); err != nil {
+		t.Fatalf("plant: %v", err)
+	}
+	if _, _, err := s.Harvest(farmer, "alice", 2, 2); err == nil {
+		t.Error("harvested an unripe crop")
+	}
+
+	for i := 0; i < 301; i++ {
+		s.Update(1)
+	}
+	if _, stage, _ := world.CropFromObject(maps[0].Objects[2][2]); stage != world.CropRipe {
+		t.Fatalf("stage %d after grow time, want ripe", stage)
+	}
+	if len(updates) != world.CropStages {
+		t.Errorf("%d object updates, want one per stage", len(updates))
+	}
+	if _, _, err := s.Harvest(farmer, "bob", 2, 2); err == nil {
+		t.Error("someone else harvested the crop")
+	}
+
+	produce, qty, err := s.Harvest(farmer, "alice", 2, 2)
+	if err != nil {
+		t.Fatalf("harvest: %v", err)
+	}
+	inv, _ := ecs.GetComponent[components.InventoryComponent](w, farmer)
+	if produce.ID != "wheat" || items.CountItem(inv, "wheat") != qty || items.CountItem(inv, "seed_wheat") != 2 {
+		t.Errorf("harvest gave %d %s, inventory %+v", qty, produce.ID, inv.Slots)
+	}
+	if maps[0].Objects[2][2] != 0 {
+		t.Error("harvested plot not cleared")
+	}
+}
+
+func TestCropGrowthSurvivesSave(t *testing.T) {
+	w := ecs.NewWorld()
+	maps := newFarm()
+	s := NewFarmSystem(w, maps)
+	farmer := newBuilder(w, 2, 2, "seed_wheat")
+	if err := s.Plant(farmer, "alice", "seed_wheat", 2, 2); err != nil {
+		t.Fatalf("plant: %v", err)
+	}
+	s.Update(150)
+	if !s.Dirty() {
+		t.Error("growth not marked for saving")
+	}
+
+	restored := NewFarmSystem(ecs.NewWorld(), newFarm())
+	restored.Load(s.Save())
+	if s.Dirty() {
+		t.Error("still dirty after save")
+	}
+	if _, stage, _ := world.CropFromObject(restored.Maps[0].Objects[2][2]); stage != 1 {
+		t.Errorf("restored crop at stage %d, want 1", stage)
+	}
+}
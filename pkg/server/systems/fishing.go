@@ -0,0 +1,244 @@
+package systems
+
+import (
+	"errors"
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"henry/pkg/shared/world"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	SkillFishing = "fishing"
+	FishingRod   = "rod_fishing"
+
+	FishingBiteMin    = 3.0 // Seconds after the cast before a fish can bite
+	FishingBiteMax    = 8.0
+	FishingHookWindow = 1.0  // Seconds to hook a biting fish (grows with skill level)
+	FishingWindowStep = 0.1  // Extra hook window per skill level
+	FishingMoveLimit  = 16.0 // Moving further than this (px) from the cast spot reels in
+)
+
+// FishCatch is one entry of the fishing loot table
+type FishCatch struct {
+	ItemID   string
+	Weight   int // Relative chance among the catches the angler's level allows
+	MinLevel int
+	XP       int
+}
+
+var FishTable = []FishCatch{
+	{ItemID: "boot_old", Weight: 15, MinLevel: 1, XP: 2},
+	{ItemID: "fish_minnow", Weight: 50, MinLevel: 1, XP: 5},
+	{ItemID: "fish_trout", Weight: 30, MinLevel: 3, XP: 12},
+	{ItemID: "fish_salmon", Weight: 10, MinLevel: 6, XP: 25},
+}
+
+type fishingSession struct {
+	Bite  bool    // A fish is on the line, hook it before Timer runs out
+	Timer float64 // Seconds until the bite (or until the fish escapes)
+	X, Y  float64 // Where the angler stood when casting
+}
+
+// FishingSystem runs the cast/bite/hook minigame: a cast waits a random time for a bite,
+// then the angler has a short window to hook the fish. Catches give fishing XP.
+type FishingSystem struct {
+	World *ecs.World
+	Maps  map[int]*world.Map
+	Audit *ItemAudit // Optional item audit trail
+
+	// Called when a session starts, gets a bite or ends
+	OnChange func(id ecs.Entity)
+	// Called with a message for the angler (fish escaped, stopped fishing)
+	OnMessage func(id ecs.Entity, msg string)
+
+	sessions map[ecs.Entity]*fishingSession
+	rng      *rand.Rand
+}
+
+func NewFishingSystem(world *ecs.World, maps map[int]*world.Map) *FishingSystem {
+	return &FishingSystem{
+		World:    world,
+		Maps:     maps,
+		sessions: make(map[ecs.Entity]*fishingSession),
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Cast starts fishing if the player holds a rod and faces shallow water
+func (s *FishingSystem) Cast(id ecs.Entity) error {
+	if _, fishing := s.sessions[id]; fishing {
+		return errors.New("already fishing")
+	}
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	if trans == nil || inv == nil {
+		return errors.New("invalid angler")
+	}
+	if items.CountItem(inv, FishingRod) == 0 {
+		return errors.New("you need a fishing rod")
+	}
+	if !s.facingShallowWater(trans) {
+		return errors.New("face shallow water to cast")
+	}
+
+	s.sessions[id] = &fishingSession{
+		Timer: FishingBiteMin + s.rng.Float64()*(FishingBiteMax-FishingBiteMin),
+		X:     trans.X,
+		Y:     trans.Y,
+	}
+	s.changed(id)
+	return nil
+}
+
+// Hook tries to land the fish on the line. Hooking before a bite scares the fish away.
+// leveledUp reports whether the catch raised the fishing level.
+func (s *FishingSystem) Hook(id ecs.Entity) (catch FishCatch, leveledUp bool, err error) {
+	session, ok := s.sessions[id]
+	if !ok {
+		return FishCatch{}, false, errors.New("not fishing")
+	}
+	// Ended either way, listeners hear about it after the XP is in
+	delete(s.sessions, id)
+	defer s.changed(id)
+	if !session.Bite {
+		return FishCatch{}, false, errors.New("too early, the fish swam away")
+	}
+
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	skills, _ := ecs.GetComponent[components.SkillsComponent](s.World, id)
+	if inv == nil || skills == nil {
+		return FishCatch{}, false, errors.New("invalid angler")
+	}
+
+	level := FishingLevel(skills.XP[SkillFishing])
+	catch = s.rollCatch(level)
+	if err := items.AddItem(inv, catch.ItemID, 1); err != nil {
+		return FishCatch{}, false, err
+	}
+	ecs.AddComponent(s.World, id, *inv)
+	s.Audit.Record("caught", id, "", catch.ItemID, "", 1)
+
+	if skills.XP == nil {
+		skills.XP = make(map[string]int)
+	}
+	skills.XP[SkillFishing] += catch.XP
+	ecs.AddComponent(s.World, id, *skills)
+	return catch, FishingLevel(skills.XP[SkillFishing]) > level, nil
+}
+
+// Stop reels in without catching anything
+func (s *FishingSystem) Stop(id ecs.Entity) {
+	if _, ok := s.sessions[id]; ok {
+		s.end(id)
+	}
+}
+
+// State reports the angler's session: "" (not fishing), "waiting" or "bite", and the
+// seconds left to hook when biting
+func (s *FishingSystem) State(id ecs.Entity) (string, float64) {
+	session, ok := s.sessions[id]
+	switch {
+	case !ok:
+		return "", 0
+	case session.Bite:
+		return "bite", session.Timer
+	default:
+		return "waiting", 0
+	}
+}
+
+func (s *FishingSystem) Update(dt float64) {
+	for id, session := range s.sessions {
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if trans == nil {
+			delete(s.sessions, id)
+			continue
+		}
+		if !geom.Within(trans.X, trans.Y, session.X, session.Y, FishingMoveLimit) {
+			s.end(id)
+			s.message(id, "You reel in your line")
+			continue
+		}
+
+		session.Timer -= dt
+		if session.Timer > 0 {
+			continue
+		}
+		if session.Bite {
+			s.end(id)
+			s.message(id, "The fish got away")
+			continue
+		}
+		session.Bite = true
+		session.Timer = FishingHookWindow
+		if skills, ok := ecs.GetComponent[components.SkillsComponent](s.World, id); ok {
+			session.Timer += FishingWindowStep * float64(FishingLevel(skills.XP[SkillFishing])-1)
+		}
+		s.changed(id)
+	}
+}
+
+// FishingLevel converts fishing XP to a level (1 at 0 XP, 3 at 80, 6 at 500)
+func FishingLevel(xp int) int {
+	return 1 + int(math.Sqrt(float64(xp)/20))
+}
+
+// rollCatch picks a weighted catch among those the level allows
+func (s *FishingSystem) rollCatch(level int) FishCatch {
+	total := 0
+	for _, c := range FishTable {
+		if c.MinLevel <= level {
+			total += c.Weight
+		}
+	}
+	r := s.rng.Intn(total)
+	for _, c := range FishTable {
+		if c.MinLevel > level {
+			continue
+		}
+		if r < c.Weight {
+			return c
+		}
+		r -= c.Weight
+	}
+	return FishTable[0]
+}
+
+// facingShallowWater checks the tile one step ahead of the player's center
+func (s *FishingSystem) facingShallowWater(trans *components.TransformComponent) bool {
+	m, ok := s.Maps[trans.Z]
+	if !ok {
+		return false
+	}
+	tileSize := float64(config.TileSize)
+	x := trans.X + tileSize/2 + math.Cos(trans.Rotation)*tileSize
+	y := trans.Y + tileSize/2 + math.Sin(trans.Rotation)*tileSize
+	tx, ty := int(math.Floor(x/tileSize)), int(math.Floor(y/tileSize))
+	if tx < 0 || ty < 0 || tx >= m.Width || ty >= m.Height {
+		return false
+	}
+	return m.Tiles[ty][tx].Type == world.TileWaterShallow
+}
+
+func (s *FishingSystem) end(id ecs.Entity) {
+	delete(s.sessions, id)
+	s.changed(id)
+}
+
+func (s *FishingSystem) changed(id ecs.Entity) {
+	if s.OnChange != nil {
+		s.OnChange(id)
+	}
+}
+
+func (s *FishingSystem) message(id ecs.Entity, msg string) {
+	if s.OnMessage != nil {
+		s.OnMessage(id, msg)
+	}
+}
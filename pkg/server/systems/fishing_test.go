@@ -0,0 +1,83 @@
+package systems
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+// newAngler stands a player with a rod on tile (2,2), facing east onto shallow water at (3,2)
+func newAngler(t *testing.T) (*FishingSystem, ecs.Entity) {
+	t.Helper()
+	w := ecs.NewWorld()
+	m := world.NewMap(6, 6)
+	m.Tiles[2][3] = world.Tile{Type: world.TileWaterShallow}
+	s := NewFishingSystem(w, map[int]*world.Map{0: m})
+	s.rng = rand.New(rand.NewSource(1))
+
+	id := newBuilder(w, 2, 2, FishingRod)
+	ecs.AddComponent(w, id, components.SkillsComponent{XP: map[string]int{}})
+	return s, id
+}
+
+func TestFishingCastNeedsWater(t *testing.T) {
+	s, id := newAngler(t)
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+	trans.Rotation = math.Pi // West, onto grass
+	ecs.AddComponent(s.World, id, *trans)
+	if err := s.Cast(id); err == nil {
+		t.Fatal("cast facing grass")
+	}
+}
+
+func TestFishingHookTiming(t *testing.T) {
+	s, id := newAngler(t)
+	if err := s.Cast(id); err != nil {
+		t.Fatalf("cast: %v", err)
+	}
+	if _, _, err := s.Hook(id); err == nil {
+		t.Fatal("hooked before a bite")
+	}
+
+	if err := s.Cast(id); err != nil {
+		t.Fatalf("recast: %v", err)
+	}
+	s.Update(FishingBiteMax)
+	if state, window := s.State(id); state != "bite" || window <= 0 {
+		t.Fatalf("state %q window %.1f after waiting, want a bite", state, window)
+	}
+	catch, _, err := s.Hook(id)
+	if err != nil {
+		t.Fatalf("hook: %v", err)
+	}
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	skills, _ := ecs.GetComponent[components.SkillsComponent](s.World, id)
+	if items.CountItem(inv, catch.ItemID) != 1 || skills.XP[SkillFishing] != catch.XP {
+		t.Errorf("catch %s not given (xp %d)", catch.ItemID, skills.XP[SkillFishing])
+	}
+
+	// A bite left alone escapes
+	s.Cast(id)
+	s.Update(FishingBiteMax)
+	s.Update(FishingHookWindow + 1)
+	if state, _ := s.State(id); state != "" {
+		t.Errorf("still %q after the hook window", state)
+	}
+}
+
+func TestFishingLevelGatesCatches(t *testing.T) {
+	s, _ := newAngler(t)
+	for i := 0; i < 200; i++ {
+		if c := s.rollCatch(1); c.MinLevel > 1 {
+			t.Fatalf("level 1 caught %s", c.ItemID)
+		}
+	}
+	if FishingLevel(0) != 1 || FishingLevel(80) != 3 || FishingLevel(500) != 6 {
+		t.Error("fishing level curve changed")
+	}
+}
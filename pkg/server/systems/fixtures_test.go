@@ -0,0 +1,21 @@
+package systems
+
+import (
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+// spawnTestPlayers adds n players standing at (x, y) on level 0, each with an empty
+// four-slot bag. Tests add whatever else their system needs.
+func spawnTestPlayers(w *ecs.World, n int, x, y float64) []ecs.Entity {
+	players := make([]ecs.Entity, 0, n)
+	for range n {
+		id := w.NewEntity()
+		ecs.AddComponent(w, id, components.TransformComponent{X: x, Y: y})
+		ecs.AddComponent(w, id, *items.NewInventory(4))
+		w.AddTags(id, components.TagPlayer)
+		players = append(players, id)
+	}
+	return players
+}
@@ -0,0 +1,159 @@
+package systems
+
+import (
+	"errors"
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"image/color"
+)
+
+const (
+	GroundItemSize       = 16.0
+	GroundItemOwnerTime  = 60.0  // Seconds the dropper has exclusive pickup rights
+	GroundItemLifetime   = 300.0 // Seconds before a ground item despawns
+	GroundItemPickupDist = config.GroundItemPickupDist
+)
+
+type GroundItemSystem struct {
+	World   *ecs.World
+	Limiter *SpawnLimiter // Optional global entity cap
+	Audit   *ItemAudit    // Optional item audit trail
+}
+
+func NewGroundItemSystem(world *ecs.World) *GroundItemSystem {
+	return &GroundItemSystem{
+		World: world,
+	}
+}
+
+// Spawn places a new item stack in the world centered on a 64x64 tile position
+func (s *GroundItemSystem) Spawn(x, y float64, z int, itemID string, quantity int, owner ecs.Entity) (ecs.Entity, error) {
+	id, err := s.SpawnInstance(x, y, z, itemID, "", quantity, owner)
+	if err != nil {
+		return 0, err
+	}
+	item, _ := ecs.GetComponent[components.GroundItemComponent](s.World, id)
+	s.Audit.Record("spawned", owner, "", itemID, item.InstanceID, quantity)
+	return id, nil
+}
+
+// SpawnInstance places an existing item instance (dropped from an inventory) in the
+// world, keeping its ID; an empty instanceID gets a new one
+func (s *GroundItemSystem) SpawnInstance(x, y float64, z int, itemID, instanceID string, quantity int, owner ecs.Entity) (ecs.Entity, error) {
+	def, ok := items.Get(itemID)
+	if !ok {
+		return 0, errors.New("item not defined: " + itemID)
+	}
+	if quantity <= 0 {
+		return 0, errors.New("invalid quantity")
+	}
+	if s.Limiter != nil && !s.Limiter.AllowEntity() {
+		return 0, ErrEntityCap
+	}
+
+	if instanceID == "" {
+		instanceID = items.NewInstanceID()
+	}
+
+	id := s.World.NewEntity()
+	ecs.AddComponent(s.World, id, components.TransformComponent{X: x, Y: y, Z: z})
+	ecs.AddComponent(s.World, id, components.SpriteComponent{
+		Width:   GroundItemSize,
+		Height:  GroundItemSize,
+		Color:   color.RGBA{R: 200, G: 100, B: 100, A: 255},
+		Texture: itemID,
+	})
+	ecs.AddComponent(s.World, id, components.GroundItemComponent{
+		ItemID:     itemID,
+		InstanceID: instanceID,
+		Quantity:   quantity,
+		OwnerID:    owner,
+		OwnerTimer: GroundItemOwnerTime,
+		Lifetime:   GroundItemLifetime,
+	})
+	ecs.AddComponent(s.World, id, components.NameComponent{Name: def.Name})
+	s.World.AddTags(id, components.TagGroundItem)
+	return id, nil
+}
+
+// Pickup moves a ground item into the player's inventory
+func (s *GroundItemSystem) Pickup(playerID, itemEntity ecs.Entity) error {
+	item, _ := ecs.GetComponent[components.GroundItemComponent](s.World, itemEntity)
+	itemTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, itemEntity)
+	playerTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, playerID)
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, playerID)
+
+	if item == nil || itemTrans == nil || playerTrans == nil || inv == nil {
+		return errors.New("invalid pickup")
+	}
+
+	if item.OwnerTimer > 0 && item.OwnerID != 0 && item.OwnerID != playerID {
+		return errors.New("item belongs to another player")
+	}
+
+	// Range Check (Player tile center to item)
+	itemCenter := geom.Square(itemTrans.X, itemTrans.Y, GroundItemSize).Center()
+	if itemTrans.Z != playerTrans.Z || !geom.Within(itemCenter.X, itemCenter.Y, playerTrans.X+32, playerTrans.Y+32, GroundItemPickupDist) {
+		return errors.New("too far away")
+	}
+
+	kept := item.InstanceID // Another stack's ID if it merges into one
+	var err error
+	if item.ItemID == items.Gold {
+		AddGold(s.World, playerID, item.Quantity)
+	} else if items.OnKeyring(item.ItemID) {
+		kept = AddToKeyring(s.World, playerID, item.ItemID, item.InstanceID, item.Quantity)
+	} else if kept, err = items.AddInstance(inv, item.ItemID, item.InstanceID, item.Quantity); err != nil {
+		return err
+	} else {
+		ecs.AddComponent(s.World, playerID, *inv)
+	}
+	if s.Audit != nil {
+		other := ""
+		if item.OwnerID != playerID {
+			other = s.Audit.AccountOf(item.OwnerID)
+		}
+		s.Audit.Record("picked_up", playerID, other, item.ItemID, item.InstanceID, item.Quantity)
+		s.Audit.RecordMerge(playerID, item.ItemID, item.InstanceID, kept, item.Quantity)
+	}
+	ecs.Publish(s.World, components.PickupEvent{Player: playerID, ItemID: item.ItemID, InstanceID: item.InstanceID, Quantity: item.Quantity})
+	s.World.RemoveEntity(itemEntity)
+	return nil
+}
+
+// AutoPickup is Pickup on behalf of a player's loot filter, for the items it takes
+// without a click
+func (s *GroundItemSystem) AutoPickup(playerID, itemEntity ecs.Entity, filter items.LootFilter) error {
+	item, _ := ecs.GetComponent[components.GroundItemComponent](s.World, itemEntity)
+	if item == nil {
+		return errors.New("invalid pickup")
+	}
+	if def, ok := items.Get(item.ItemID); !ok || !filter.AutoPickup(def) {
+		return errors.New("not taken by the loot filter")
+	}
+	return s.Pickup(playerID, itemEntity)
+}
+
+func (s *GroundItemSystem) Update(dt float64) {
+	entities := ecs.Query[components.GroundItemComponent](s.World)
+	for _, id := range entities {
+		item, _ := ecs.GetComponent[components.GroundItemComponent](s.World, id)
+		if item == nil {
+			continue
+		}
+
+		item.Lifetime -= dt
+		if item.Lifetime <= 0 {
+			s.Audit.RecordAccount("despawned", "", "", item.ItemID, item.InstanceID, item.Quantity)
+			s.World.RemoveEntity(id)
+			continue
+		}
+		if item.OwnerTimer > 0 {
+			item.OwnerTimer -= dt
+		}
+		ecs.AddComponent(s.World, id, *item)
+	}
+}
@@ -0,0 +1,62 @@
+package systems
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	protocol "henry/pkg/shared/network"
+)
+
+// HelpDir holds the in-game help, one topic per *.md file (see LoadHelp)
+const HelpDir = "data/help"
+
+// LoadHelp reads every *.md help topic in dir, sorted by file name. A topic's ID is
+// its file name; the first line is its "# Title", an optional "Keywords: a, b" line
+// right after adds search terms, and the rest is the body shown in the help window.
+func LoadHelp(dir string) ([]protocol.HelpTopic, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var topics []protocol.HelpTopic
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		topic, err := parseHelp(strings.TrimSuffix(filepath.Base(file), ".md"), string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		topics = append(topics, topic)
+	}
+	return topics, nil
+}
+
+func parseHelp(id, text string) (protocol.HelpTopic, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	title, ok := strings.CutPrefix(lines[0], "# ")
+	if !ok || strings.TrimSpace(title) == "" {
+		return protocol.HelpTopic{}, fmt.Errorf("first line must be the \"# Title\"")
+	}
+	topic := protocol.HelpTopic{ID: id, Title: strings.TrimSpace(title)}
+	lines = lines[1:]
+
+	if len(lines) > 0 {
+		if keywords, ok := strings.CutPrefix(lines[0], "Keywords:"); ok {
+			for _, k := range strings.Split(keywords, ",") {
+				if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+					topic.Keywords = append(topic.Keywords, k)
+				}
+			}
+			lines = lines[1:]
+		}
+	}
+	topic.Body = strings.TrimSpace(strings.Join(lines, "\n"))
+	return topic, nil
+}
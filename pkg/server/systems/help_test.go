@@ -0,0 +1,40 @@
+package systems
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadHelp(t *testing.T) {
+	topics, err := LoadHelp(filepath.Join("..", "..", "..", HelpDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) == 0 {
+		t.Fatal("no help shipped")
+	}
+	for _, topic := range topics {
+		if topic.Body == "" {
+			t.Errorf("help topic %q is empty", topic.ID)
+		}
+	}
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "fishing.md"), []byte("# Fishing\r\nKeywords: Rod, , bite\r\n\r\nWait for a bite.\r\n"), 0o644)
+	topics, err = LoadHelp(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := topics[0]
+	if got.ID != "fishing" || got.Title != "Fishing" || got.Body != "Wait for a bite." || !slices.Equal(got.Keywords, []string{"rod", "bite"}) {
+		t.Errorf("parsed %+v", got)
+	}
+
+	os.WriteFile(filepath.Join(dir, "untitled.md"), []byte("Just text"), 0o644)
+	if _, err := LoadHelp(dir); err == nil || !strings.Contains(err.Error(), "untitled.md") {
+		t.Errorf("got %v, want an error naming the untitled file", err)
+	}
+}
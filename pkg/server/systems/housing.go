@@ -0,0 +1,399 @@
+package systems
+
+import (
+	"errors"
+	"fmt"
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"henry/pkg/shared/world"
+	"henry/pkg/storage"
+	"log"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Furniture a new house comes with, so it isn't bare before the owner rearranges it
+var starterFurniture = []storage.FurnitureSave{
+	{ItemID: "build_bed", TileX: 1, TileY: 1},
+	{ItemID: "build_table", TileX: config.HouseWidth / 2, TileY: config.HouseHeight/2 - 1},
+	{ItemID: "build_chair", TileX: config.HouseWidth/2 - 1, TileY: config.HouseHeight/2 - 1},
+	{ItemID: "build_rug", TileX: config.HouseWidth / 2, TileY: config.HouseHeight - 3},
+}
+
+// House is a player's home: who may come in and where the furniture stands. Its
+// interior is an instance that only exists while someone is inside.
+type House struct {
+	Owner     string
+	Guests    []string
+	Furniture []storage.FurnitureSave
+	Level     int // Instance level while open (0 = closed)
+}
+
+// MayEnter reports whether a player is the owner or on the guest list
+func (h *House) MayEnter(username string) bool {
+	return username == h.Owner || slices.Contains(h.Guests, username)
+}
+
+type houseVisit struct {
+	house    *House
+	username string
+	returnTo ReturnPosition // Where the visitor stood outside
+}
+
+// HousingSystem runs player houses. A house deed bought from the Housing Steward turns
+// into a house at the door in town; owners and their guests walk into a private interior
+// where the owner places furniture from their inventory. Stepping on the doormat leaves.
+type HousingSystem struct {
+	World     *ecs.World
+	Instances *InstanceSystem
+	Building  *BuildingSystem // Spawns the furniture
+	Audit     *ItemAudit      // Optional item audit trail
+
+	// Hooks provided by the GameServer
+	OnTeleport func(player ecs.Entity) // Moved to another level (resync map)
+	OnChange   func(owner string)      // A house was bought, furnished or its guest list changed
+	OnVisit    func(player ecs.Entity) // Went into or out of a house
+
+	houses map[string]*House // By owner
+	visits map[ecs.Entity]*houseVisit
+}
+
+func NewHousingSystem(world *ecs.World, instances *InstanceSystem, building *BuildingSystem) *HousingSystem {
+	return &HousingSystem{
+		World:     world,
+		Instances: instances,
+		Building:  building,
+		houses:    make(map[string]*House),
+		visits:    make(map[ecs.Entity]*houseVisit),
+	}
+}
+
+// HouseOf returns the owner's house, or nil
+func (s *HousingSystem) HouseOf(owner string) *House {
+	return s.houses[owner]
+}
+
+// Inside returns the house a player is in, or nil
+func (s *HousingSystem) Inside(player ecs.Entity) *House {
+	if visit := s.visits[player]; visit != nil {
+		return visit.house
+	}
+	return nil
+}
+
+// InvitedTo lists the owners whose guest list has the player, sorted
+func (s *HousingSystem) InvitedTo(username string) []string {
+	var owners []string
+	for owner, house := range s.houses {
+		if owner != username && slices.Contains(house.Guests, username) {
+			owners = append(owners, owner)
+		}
+	}
+	sort.Strings(owners)
+	return owners
+}
+
+// BuyDeed sells a house deed to a player standing near the Housing Steward
+func (s *HousingSystem) BuyDeed(player ecs.Entity, username string) error {
+	if !s.NearSteward(player) {
+		return errors.New("talk to the Housing Steward to buy a deed")
+	}
+	if s.houses[username] != nil {
+		return errors.New("you already own a house")
+	}
+	if KeyringCount(s.World, player, "house_deed") > 0 {
+		return errors.New("you already have a deed: show it at the house door")
+	}
+	if Gold(s.World, player) < config.HouseDeedPrice {
+		return fmt.Errorf("a deed costs %d gold", config.HouseDeedPrice)
+	}
+	if err := SpendGold(s.World, player, config.HouseDeedPrice); err != nil {
+		return err
+	}
+	AddToKeyring(s.World, player, "house_deed", "", 1)
+	s.Audit.Record("bought", player, "Housing Steward", "house_deed", "", 1)
+	s.Audit.Record("spent", player, "Housing Steward", items.Gold, "", config.HouseDeedPrice)
+	return nil
+}
+
+// Enter takes a player into the owner's house, to return to returnTo when they leave.
+// Owners without a house move in with a deed from their keyring.
+func (s *HousingSystem) Enter(player ecs.Entity, username, owner string, returnTo ReturnPosition) error {
+	if s.visits[player] != nil {
+		return errors.New("you are already inside a house")
+	}
+	if IsInstanceLevel(returnTo.Z) {
+		return errors.New("you can't do that from here")
+	}
+	house := s.houses[owner]
+	if house == nil {
+		if owner != username {
+			return fmt.Errorf("%s has no house", owner)
+		}
+		var err error
+		if house, err = s.moveIn(player, username); err != nil {
+			return err
+		}
+	}
+	if !house.MayEnter(username) {
+		return fmt.Errorf("%s hasn't invited you", owner)
+	}
+
+	if house.Level == 0 {
+		s.open(house)
+	}
+	s.visits[player] = &houseVisit{house: house, username: username, returnTo: returnTo}
+	tile := float64(config.TileSize)
+	doorX, doorY := world.HouseDoor(config.HouseWidth, config.HouseHeight)
+	s.place(player, float64(doorX)*tile, float64(doorY-1)*tile, house.Level)
+	log.Printf("%s entered the house of %s", username, owner)
+	s.visited(player)
+	return nil
+}
+
+// moveIn turns the deed on the player's keyring into a furnished house
+func (s *HousingSystem) moveIn(player ecs.Entity, username string) (*House, error) {
+	if err := TakeFromKeyring(s.World, player, "house_deed", 1); err != nil {
+		return nil, errors.New("you don't own a house: buy a deed from the Housing Steward")
+	}
+	s.Audit.Record("used", player, "", "house_deed", "", 1)
+
+	house := &House{Owner: username, Furniture: slices.Clone(starterFurniture)}
+	s.houses[username] = house
+	log.Printf("%s moved into a new house", username)
+	s.changed(username)
+	return house, nil
+}
+
+// open creates the house's interior with its furniture
+func (s *HousingSystem) open(house *House) {
+	house.Level = s.Instances.Create(world.GenerateHouse(config.HouseWidth, config.HouseHeight, house.Owner+"'s House"))
+	tile := float64(config.TileSize)
+	for _, f := range house.Furniture {
+		if _, err := s.Building.Spawn(f.ItemID, house.Owner, float64(f.TileX)*tile, float64(f.TileY)*tile, house.Level); err != nil {
+			log.Printf("Skipping furniture in the house of %s: %v", house.Owner, err)
+		}
+	}
+}
+
+// Leave returns a player to where they entered from. The interior is torn down once
+// the last visitor is out.
+func (s *HousingSystem) Leave(player ecs.Entity) {
+	visit := s.visits[player]
+	if visit == nil {
+		return
+	}
+	delete(s.visits, player)
+	s.place(player, visit.returnTo.X, visit.returnTo.Y, visit.returnTo.Z)
+	s.visited(player)
+
+	for _, other := range s.visits {
+		if other.house == visit.house {
+			return
+		}
+	}
+	s.Instances.Destroy(visit.house.Level)
+	visit.house.Level = 0
+}
+
+// Place puts a piece of furniture from the owner's inventory on a tile of their house
+func (s *HousingSystem) Place(builder ecs.Entity, username, itemID string, tx, ty int) (ecs.Entity, error) {
+	house := s.Inside(builder)
+	if house == nil || house.Owner != username {
+		return 0, errors.New("you can only furnish your own house")
+	}
+	def, ok := items.Get(itemID)
+	if !ok || def.Structure == nil || !def.Structure.Furniture {
+		return 0, errors.New("that isn't furniture")
+	}
+	if len(house.Furniture) >= config.HouseMaxFurniture {
+		return 0, fmt.Errorf("your house holds %d pieces at most", config.HouseMaxFurniture)
+	}
+	if doorX, doorY := world.HouseDoor(config.HouseWidth, config.HouseHeight); tx == doorX && ty == doorY {
+		return 0, errors.New("keep the door free")
+	}
+	tile := float64(config.TileSize)
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, builder)
+	if trans == nil || !geom.Within(trans.X, trans.Y, float64(tx)*tile, float64(ty)*tile, config.BuildRange) {
+		return 0, errors.New("too far away")
+	}
+	if !s.Building.tileFree(house.Level, tx, ty, def.Structure.Solid) {
+		return 0, errors.New("that spot is not free")
+	}
+	inv, ok := ecs.GetComponent[components.InventoryComponent](s.World, builder)
+	if !ok {
+		return 0, errors.New("invalid builder")
+	}
+	if err := items.RemoveItemByID(inv, itemID, 1); err != nil {
+		return 0, err
+	}
+	ecs.AddComponent(s.World, builder, *inv)
+	s.Audit.Record("placed", builder, "", itemID, "", 1)
+
+	id, err := s.Building.Spawn(itemID, username, float64(tx)*tile, float64(ty)*tile, house.Level)
+	if err != nil {
+		return 0, err
+	}
+	house.Furniture = append(house.Furniture, storage.FurnitureSave{ItemID: itemID, TileX: tx, TileY: ty})
+	s.changed(username)
+	return id, nil
+}
+
+// Remove picks a piece of furniture up into the owner's inventory
+func (s *HousingSystem) Remove(actor ecs.Entity, username string, target ecs.Entity) error {
+	house := s.Inside(actor)
+	if house == nil || house.Owner != username {
+		return errors.New("you can only rearrange your own house")
+	}
+	st, _ := ecs.GetComponent[components.StructureComponent](s.World, target)
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, target)
+	if st == nil || trans == nil || trans.Z != house.Level {
+		return errors.New("nothing to pick up")
+	}
+	tile := float64(config.TileSize)
+	tx, ty := int(math.Floor(trans.X/tile)), int(math.Floor(trans.Y/tile))
+	index := slices.IndexFunc(house.Furniture, func(f storage.FurnitureSave) bool {
+		return f.ItemID == st.ItemID && f.TileX == tx && f.TileY == ty
+	})
+	if index < 0 {
+		return errors.New("nothing to pick up")
+	}
+
+	inv, ok := ecs.GetComponent[components.InventoryComponent](s.World, actor)
+	if !ok {
+		return errors.New("invalid player")
+	}
+	if err := items.AddItem(inv, st.ItemID, 1); err != nil {
+		return err
+	}
+	ecs.AddComponent(s.World, actor, *inv)
+	s.Audit.Record("picked_up", actor, "", st.ItemID, "", 1)
+	s.World.RemoveEntity(target)
+	house.Furniture = slices.Delete(house.Furniture, index, index+1)
+	s.changed(username)
+	return nil
+}
+
+// Invite adds a player to the owner's guest list
+func (s *HousingSystem) Invite(owner, guest string) error {
+	house := s.houses[owner]
+	if house == nil {
+		return errors.New("you don't own a house")
+	}
+	if guest == owner {
+		return errors.New("it's your own house")
+	}
+	if slices.Contains(house.Guests, guest) {
+		return fmt.Errorf("%s is already invited", guest)
+	}
+	if len(house.Guests) >= config.HouseMaxGuests {
+		return fmt.Errorf("your guest list is full (%d)", config.HouseMaxGuests)
+	}
+	house.Guests = append(house.Guests, guest)
+	s.changed(owner)
+	return nil
+}
+
+// Uninvite takes a player off the owner's guest list, showing them out if they're inside
+func (s *HousingSystem) Uninvite(owner, guest string) error {
+	house := s.houses[owner]
+	if house == nil {
+		return errors.New("you don't own a house")
+	}
+	index := slices.Index(house.Guests, guest)
+	if index < 0 {
+		return fmt.Errorf("%s isn't invited", guest)
+	}
+	house.Guests = slices.Delete(house.Guests, index, index+1)
+	for id, visit := range s.visits {
+		if visit.house == house && visit.username == guest {
+			s.Leave(id)
+		}
+	}
+	s.changed(owner)
+	return nil
+}
+
+// Update shows out visitors standing on the doormat
+func (s *HousingSystem) Update() {
+	tile := float64(config.TileSize)
+	doorX, doorY := world.HouseDoor(config.HouseWidth, config.HouseHeight)
+	for id, visit := range s.visits {
+		trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if !ok || trans.Z != visit.house.Level {
+			continue
+		}
+		// Feet (bottom middle of the sprite) on the mat
+		if int(math.Floor((trans.X+tile/2)/tile)) == doorX && int(math.Floor((trans.Y+tile-1)/tile)) == doorY {
+			s.Leave(id)
+		}
+	}
+}
+
+// NearSteward reports whether the player stands close enough to a Housing Steward
+func (s *HousingSystem) NearSteward(player ecs.Entity) bool {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, player)
+	if !ok {
+		return false
+	}
+	for _, id := range ecs.Query[components.MarkerComponent](s.World) {
+		marker, _ := ecs.GetComponent[components.MarkerComponent](s.World, id)
+		if marker.Flags&components.MarkerHouse == 0 {
+			continue
+		}
+		t, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if ok && t.Z == trans.Z && geom.Within(t.X, t.Y, trans.X, trans.Y, config.HouseStewardRange) {
+			return true
+		}
+	}
+	return false
+}
+
+// Save lists every house for storage.SaveHouses, by owner
+func (s *HousingSystem) Save() []storage.HouseSave {
+	saved := make([]storage.HouseSave, 0, len(s.houses))
+	for _, house := range s.houses {
+		saved = append(saved, storage.HouseSave{Owner: house.Owner, Guests: house.Guests, Furniture: house.Furniture})
+	}
+	sort.Slice(saved, func(i, j int) bool { return saved[i].Owner < saved[j].Owner })
+	return saved
+}
+
+// Load replaces the houses with saved ones. Call before anyone is inside.
+func (s *HousingSystem) Load(saved []storage.HouseSave) {
+	s.houses = make(map[string]*House, len(saved))
+	for _, h := range saved {
+		s.houses[h.Owner] = &House{Owner: h.Owner, Guests: h.Guests, Furniture: h.Furniture}
+	}
+	log.Printf("Loaded %d houses", len(s.houses))
+}
+
+func (s *HousingSystem) place(id ecs.Entity, x, y float64, z int) {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+	if !ok {
+		return
+	}
+	changedLevel := trans.Z != z
+	trans.X, trans.Y, trans.Z = x, y, z
+	ecs.AddComponent(s.World, id, *trans)
+	ecs.RemoveComponent[components.AutoMoveComponent](s.World, id)
+	if changedLevel && s.OnTeleport != nil {
+		s.OnTeleport(id)
+	}
+}
+
+func (s *HousingSystem) changed(owner string) {
+	if s.OnChange != nil {
+		s.OnChange(owner)
+	}
+}
+
+func (s *HousingSystem) visited(player ecs.Entity) {
+	if s.OnVisit != nil {
+		s.OnVisit(player)
+	}
+}
@@ -0,0 +1,137 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+// housingWorld places a Housing Steward and players next to it, each with a bag of gold
+func housingWorld(t *testing.T, n int) (*HousingSystem, []ecs.Entity) {
+	t.Helper()
+	w := ecs.NewWorld()
+	maps := map[int]*world.Map{0: world.NewMap(32, 32)}
+	s := NewHousingSystem(w, NewInstanceSystem(w, maps), NewBuildingSystem(w, maps))
+
+	steward := w.NewEntity()
+	ecs.AddComponent(w, steward, components.TransformComponent{X: 100, Y: 100})
+	ecs.AddComponent(w, steward, components.MarkerComponent{Flags: components.MarkerHouse})
+
+	players := spawnTestPlayers(w, n, 150, 100)
+	for _, id := range players {
+		inv := items.NewInventory(10)
+		items.AddItem(inv, "build_chair", 1)
+		ecs.AddComponent(w, id, *inv)
+		ecs.AddComponent(w, id, components.WalletComponent{Gold: config.HouseDeedPrice})
+	}
+	return s, players
+}
+
+func levelOf(w *ecs.World, id ecs.Entity) int {
+	trans, _ := ecs.GetComponent[components.TransformComponent](w, id)
+	return trans.Z
+}
+
+func TestHouseDeedMovesInAndInstanceCloses(t *testing.T) {
+	s, players := housingWorld(t, 1)
+	alice := players[0]
+	if err := s.Enter(alice, "alice", "alice", ReturnPosition{X: 150, Y: 100}); err == nil {
+		t.Fatal("entered a house without a deed")
+	}
+	if err := s.BuyDeed(alice, "alice"); err != nil {
+		t.Fatalf("buy: %v", err)
+	}
+	if err := s.Enter(alice, "alice", "alice", ReturnPosition{X: 150, Y: 100}); err != nil {
+		t.Fatalf("enter: %v", err)
+	}
+
+	house := s.HouseOf("alice")
+	if house == nil || s.Inside(alice) != house || levelOf(s.World, alice) != house.Level || !IsInstanceLevel(house.Level) {
+		t.Fatalf("alice not moved into her house (%+v)", house)
+	}
+	if KeyringCount(s.World, alice, "house_deed") != 0 || Gold(s.World, alice) != 0 {
+		t.Error("deed or gold not spent")
+	}
+	furniture := 0
+	for _, id := range ecs.Query[components.StructureComponent](s.World) {
+		if levelOf(s.World, id) == house.Level {
+			furniture++
+		}
+	}
+	if furniture != len(starterFurniture) {
+		t.Errorf("%d pieces of furniture spawned, want %d", furniture, len(starterFurniture))
+	}
+
+	level := house.Level
+	s.Leave(alice)
+	if levelOf(s.World, alice) != 0 || house.Level != 0 {
+		t.Error("leaving didn't return alice and close the house")
+	}
+	if _, ok := s.Instances.Maps[level]; ok {
+		t.Error("empty house instance not destroyed")
+	}
+}
+
+func TestHouseGuestsAndFurniture(t *testing.T) {
+	s, players := housingWorld(t, 2)
+	alice, bob := players[0], players[1]
+	s.BuyDeed(alice, "alice")
+	s.Enter(alice, "alice", "alice", ReturnPosition{X: 150, Y: 100})
+
+	if err := s.Enter(bob, "bob", "alice", ReturnPosition{X: 150, Y: 100}); err == nil {
+		t.Fatal("bob entered without an invitation")
+	}
+	if err := s.Invite("alice", "bob"); err != nil {
+		t.Fatalf("invite: %v", err)
+	}
+	if got := s.InvitedTo("bob"); len(got) != 1 || got[0] != "alice" {
+		t.Errorf("bob invited to %v", got)
+	}
+	if err := s.Enter(bob, "bob", "alice", ReturnPosition{X: 150, Y: 100}); err != nil {
+		t.Fatalf("guest enter: %v", err)
+	}
+
+	doorX, doorY := world.HouseDoor(config.HouseWidth, config.HouseHeight)
+	if _, err := s.Place(bob, "bob", "build_chair", doorX+1, doorY-1); err == nil {
+		t.Error("a guest furnished the house")
+	}
+	if _, err := s.Place(alice, "alice", "build_chair", doorX, doorY); err == nil {
+		t.Error("furniture blocked the door")
+	}
+	if _, err := s.Place(alice, "alice", "build_chair", doorX+1, doorY-1); err != nil {
+		t.Fatalf("place: %v", err)
+	}
+
+	if err := s.Uninvite("alice", "bob"); err != nil {
+		t.Fatalf("uninvite: %v", err)
+	}
+	if s.Inside(bob) != nil || levelOf(s.World, bob) != 0 {
+		t.Error("uninvited guest still inside")
+	}
+
+	// The chair is saved, and comes back when the house reopens
+	saved := s.Save()
+	s.Leave(alice)
+	s.Load(saved)
+	if len(s.HouseOf("alice").Furniture) != len(starterFurniture)+1 {
+		t.Fatalf("furniture not saved: %+v", s.HouseOf("alice").Furniture)
+	}
+	s.Enter(alice, "alice", "alice", ReturnPosition{X: 150, Y: 100})
+	var chairs []ecs.Entity
+	for _, id := range ecs.Query[components.StructureComponent](s.World) {
+		st, _ := ecs.GetComponent[components.StructureComponent](s.World, id)
+		if st.ItemID == "build_chair" && levelOf(s.World, id) == s.HouseOf("alice").Level {
+			chairs = append(chairs, id)
+		}
+	}
+	if len(chairs) != 2 {
+		t.Fatalf("%d chairs in the reopened house, want the starter one and alice's", len(chairs))
+	}
+	if err := s.Remove(alice, "alice", chairs[0]); err != nil {
+		t.Fatalf("pick up: %v", err)
+	}
+}
@@ -0,0 +1,351 @@
+package systems
+
+import (
+	"container/heap"
+	"henry/pkg/shared/world"
+	"math"
+)
+
+// Hierarchical pathfinding (HPA*): big maps are cut into square clusters, and the walkable
+// openings between neighbouring clusters become the nodes of a small graph. Routes
+// between the nodes of each cluster are searched once and cached, so a long trip only
+// searches the graph plus the start and end clusters instead of the whole grid.
+const (
+	ClusterSize    = 16 // Cluster side in tiles
+	ClusterMinMap  = 64 // Maps at least this wide or tall (in tiles) get a cluster graph
+	entranceSplits = 6  // Openings at least this long get a node at each end instead of the middle
+
+	// MaxFlatSearch caps the tiles a search over the whole grid may expand. Short trips
+	// and small maps stay well under it; past it the target is treated as unreachable
+	// rather than flooding a big map (e.g. a click into a maze).
+	MaxFlatSearch = 8192
+)
+
+// clusterGraph is the abstract graph of one map
+type clusterGraph struct {
+	width, height int             // In clusters
+	nodes         [][2]int        // Tile of each node
+	edges         [][]clusterEdge // Outgoing edges by node
+	byCluster     map[int][]int   // Nodes of each cluster
+	byTile        map[[2]int]int  // Node on a tile
+	areas         []int           // Connected area of each tile by y*width+x (-1 = blocked)
+}
+
+// clusterEdge leads to another node; tiles is the way there (excluding the node it
+// starts from)
+type clusterEdge struct {
+	to    int
+	cost  float64
+	tiles [][2]int
+}
+
+// hasClusters reports whether a map is big enough to get a cluster graph
+func hasClusters(m *world.Map) bool {
+	return m.Width >= ClusterMinMap || m.Height >= ClusterMinMap
+}
+
+// useClusters reports whether a search is long enough, on a map big enough, to go
+// through the cluster graph. Short ones stay on the flat grid.
+func useClusters(m *world.Map, startX, startY, endX, endY int) bool {
+	if !hasClusters(m) {
+		return false
+	}
+	if startX < 0 || startX >= m.Width || startY < 0 || startY >= m.Height {
+		return false // Off the map: only the flat search copes (by failing)
+	}
+	return max(abs(startX-endX), abs(startY-endY)) > ClusterSize
+}
+
+// pathGraph returns the cluster graph of a map, building it on first use
+func (s *AISystem) pathGraph(m *world.Map) *clusterGraph {
+	if g, ok := s.graphs[m]; ok {
+		return g
+	}
+	if s.graphs == nil {
+		s.graphs = make(map[*world.Map]*clusterGraph)
+	}
+	// Drop graphs of maps that are gone (finished instances)
+	for old := range s.graphs {
+		if s.Maps[old.Level] != old {
+			delete(s.graphs, old)
+		}
+	}
+	g := buildClusterGraph(m)
+	s.graphs[m] = g
+	return g
+}
+
+// PreparePaths builds the cluster graphs of all big maps up front, so the first long
+// path on each doesn't stall a tick
+func (s *AISystem) PreparePaths() {
+	for _, m := range s.Maps {
+		if hasClusters(m) {
+			s.pathGraph(m)
+		}
+	}
+}
+
+// InvalidatePaths drops a map's cached cluster graph after its tiles or objects changed
+func (s *AISystem) InvalidatePaths(m *world.Map) {
+	delete(s.graphs, m)
+}
+
+func buildClusterGraph(m *world.Map) *clusterGraph {
+	g := &clusterGraph{
+		width:     (m.Width + ClusterSize - 1) / ClusterSize,
+		height:    (m.Height + ClusterSize - 1) / ClusterSize,
+		byCluster: make(map[int][]int),
+		byTile:    make(map[[2]int]int),
+	}
+
+	// Openings between each cluster and its right and bottom neighbours
+	for cy := 0; cy < g.height; cy++ {
+		for cx := 0; cx < g.width; cx++ {
+			bounds := g.bounds(m, cx, cy)
+			if bounds.maxX < m.Width {
+				g.addEntrances(m, bounds.minY, bounds.maxY, func(i int) ([2]int, [2]int) {
+					return [2]int{bounds.maxX - 1, i}, [2]int{bounds.maxX, i}
+				})
+			}
+			if bounds.maxY < m.Height {
+				g.addEntrances(m, bounds.minX, bounds.maxX, func(i int) ([2]int, [2]int) {
+					return [2]int{i, bounds.maxY - 1}, [2]int{i, bounds.maxY}
+				})
+			}
+		}
+	}
+
+	// Routes between the nodes of each cluster, staying inside it (one search per node)
+	for cluster, nodes := range g.byCluster {
+		bounds := g.bounds(m, cluster%g.width, cluster/g.width)
+		for _, a := range nodes {
+			from := g.nodes[a]
+			search := exploreTiles(m, from[0], from[1], bounds, nil, 0)
+			for _, b := range nodes {
+				to := g.nodes[b]
+				if tiles, cost := search.pathTo(to[0], to[1]); b != a && tiles != nil {
+					g.link(a, b, cost, tiles[1:])
+				}
+			}
+		}
+	}
+
+	g.areas = connectedAreas(m)
+	return g
+}
+
+// connectedAreas flood fills the walkable tiles, numbering each group that can reach
+// each other. Straight steps are enough: a diagonal step needs both tiles beside it free.
+func connectedAreas(m *world.Map) []int {
+	areas := make([]int, m.Width*m.Height)
+	for i := range areas {
+		areas[i] = -1
+	}
+	next := 0
+	var stack [][2]int
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			if areas[y*m.Width+x] != -1 || !walkable(m, x, y) {
+				continue
+			}
+			areas[y*m.Width+x] = next
+			stack = append(stack[:0], [2]int{x, y})
+			for len(stack) > 0 {
+				t := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				for _, d := range pathDirs[:4] {
+					nx, ny := t[0]+d[0], t[1]+d[1]
+					if walkable(m, nx, ny) && areas[ny*m.Width+nx] == -1 {
+						areas[ny*m.Width+nx] = next
+						stack = append(stack, [2]int{nx, ny})
+					}
+				}
+			}
+			next++
+		}
+	}
+	return areas
+}
+
+// connected reports whether the end tile can be reached from the start tile. A start
+// off the map or on a blocked tile (a body overlapping a wall) gets the benefit of the
+// doubt and is left to the search.
+func (g *clusterGraph) connected(m *world.Map, startX, startY, endX, endY int) bool {
+	if startX < 0 || startX >= m.Width || startY < 0 || startY >= m.Height {
+		return true
+	}
+	from := g.areas[startY*m.Width+startX]
+	return from == -1 || from == g.areas[endY*m.Width+endX]
+}
+
+// addEntrances scans a border between two clusters: side(i) gives the pair of facing
+// tiles at position i along it. Every run of open pairs becomes one crossing (in its
+// middle) or, if long, two (at its ends).
+func (g *clusterGraph) addEntrances(m *world.Map, from, to int, side func(i int) ([2]int, [2]int)) {
+	open := func(i int) bool {
+		a, b := side(i)
+		return walkable(m, a[0], a[1]) && walkable(m, b[0], b[1])
+	}
+	cross := func(i int) {
+		a, b := side(i)
+		g.addCrossing(m, a, b)
+	}
+	for i := from; i < to; i++ {
+		if !open(i) {
+			continue
+		}
+		start := i
+		for i+1 < to && open(i+1) {
+			i++
+		}
+		if i-start+1 >= entranceSplits {
+			cross(start)
+			cross(i)
+		} else {
+			cross((start + i) / 2)
+		}
+	}
+}
+
+// addCrossing links two facing tiles of neighbouring clusters
+func (g *clusterGraph) addCrossing(m *world.Map, a, b [2]int) {
+	na, nb := g.node(a), g.node(b)
+	cost := (m.MoveCostAt(a[0], a[1]) + m.MoveCostAt(b[0], b[1])) / 2
+	g.link(na, nb, cost, [][2]int{b})
+	g.link(nb, na, cost, [][2]int{a})
+}
+
+// node returns the node on a tile, adding it to its cluster if new
+func (g *clusterGraph) node(tile [2]int) int {
+	if id, ok := g.byTile[tile]; ok {
+		return id
+	}
+	id := len(g.nodes)
+	g.nodes = append(g.nodes, tile)
+	g.edges = append(g.edges, nil)
+	g.byTile[tile] = id
+	cluster := g.clusterOf(tile[0], tile[1])
+	g.byCluster[cluster] = append(g.byCluster[cluster], id)
+	return id
+}
+
+func (g *clusterGraph) link(from, to int, cost float64, tiles [][2]int) {
+	g.edges[from] = append(g.edges[from], clusterEdge{to: to, cost: cost, tiles: tiles})
+}
+
+func (g *clusterGraph) clusterOf(tx, ty int) int {
+	return (ty/ClusterSize)*g.width + tx/ClusterSize
+}
+
+func (g *clusterGraph) bounds(m *world.Map, cx, cy int) tileRect {
+	return tileRect{
+		minX: cx * ClusterSize,
+		minY: cy * ClusterSize,
+		maxX: min((cx+1)*ClusterSize, m.Width),
+		maxY: min((cy+1)*ClusterSize, m.Height),
+	}
+}
+
+// findPath connects the start and end tiles to the nodes of their clusters, searches the
+// graph, and returns the tiles walked (start first), or nil if the graph finds no way
+func (g *clusterGraph) findPath(m *world.Map, startX, startY, endX, endY int) [][2]int {
+	startBounds := g.bounds(m, startX/ClusterSize, startY/ClusterSize)
+	endBounds := g.bounds(m, endX/ClusterSize, endY/ClusterSize)
+
+	// Virtual edges: start -> its cluster's nodes, and its cluster's nodes -> end
+	goal := len(g.nodes)
+	var startEdges []clusterEdge
+	fromStart := exploreTiles(m, startX, startY, startBounds, nil, 0)
+	for _, n := range g.byCluster[g.clusterOf(startX, startY)] {
+		tile := g.nodes[n]
+		if tiles, cost := fromStart.pathTo(tile[0], tile[1]); tiles != nil {
+			startEdges = append(startEdges, clusterEdge{to: n, cost: cost, tiles: tiles[1:]})
+		}
+	}
+	endEdges := make(map[int]clusterEdge)
+	fromEnd := exploreTiles(m, endX, endY, endBounds, nil, 0)
+	for _, n := range g.byCluster[g.clusterOf(endX, endY)] {
+		tile := g.nodes[n]
+		if tiles, cost := fromEnd.pathTo(tile[0], tile[1]); tiles != nil {
+			endEdges[n] = clusterEdge{to: goal, cost: cost, tiles: reversed(tiles)[1:]}
+		}
+	}
+	if len(startEdges) == 0 || len(endEdges) == 0 {
+		return nil
+	}
+
+	// A* over the nodes, with the end as one more node
+	heuristic := func(n int) float64 {
+		if n == goal {
+			return 0
+		}
+		tile := g.nodes[n]
+		return math.Hypot(float64(tile[0]-endX), float64(tile[1]-endY)) * world.MinMoveCost
+	}
+	cost := make([]float64, goal+1)
+	via := make([]*clusterEdge, goal+1) // Edge that reached each node
+	from := make([]int, goal+1)
+	closed := make([]bool, goal+1)
+	for i := range cost {
+		cost[i] = math.MaxFloat64
+	}
+	open := &pathHeap{}
+	relax := func(prev int, e clusterEdge, base float64) {
+		if c := base + e.cost; c < cost[e.to] {
+			cost[e.to] = c
+			via[e.to] = &e
+			from[e.to] = prev
+			heap.Push(open, pathItem{index: e.to, f: c + heuristic(e.to)})
+		}
+	}
+	for _, e := range startEdges {
+		relax(-1, e, 0)
+	}
+	for open.Len() > 0 {
+		curr := heap.Pop(open).(pathItem).index
+		if closed[curr] {
+			continue
+		}
+		closed[curr] = true
+		if curr == goal {
+			break
+		}
+		for _, e := range g.edges[curr] {
+			if !closed[e.to] {
+				relax(curr, e, cost[curr])
+			}
+		}
+		if e, ok := endEdges[curr]; ok {
+			relax(curr, e, cost[curr])
+		}
+	}
+	if !closed[goal] {
+		return nil
+	}
+
+	// Stitch the edges' tiles together, end to start
+	var legs [][][2]int
+	for n := goal; n != -1; n = from[n] {
+		legs = append(legs, via[n].tiles)
+	}
+	tiles := [][2]int{{startX, startY}}
+	for i := len(legs) - 1; i >= 0; i-- {
+		tiles = append(tiles, legs[i]...)
+	}
+	return tiles
+}
+
+func reversed(tiles [][2]int) [][2]int {
+	out := make([][2]int, len(tiles))
+	for i, t := range tiles {
+		out[len(tiles)-1-i] = t
+	}
+	return out
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,86 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+func TestClusterPathMatchesFlatSearch(t *testing.T) {
+	// Walls across the map with a single gap each, alternating sides
+	m := world.NewMap(96, 96)
+	for _, wall := range []struct{ y, gap int }{{20, 90}, {45, 5}, {70, 60}} {
+		for x := 0; x < 96; x++ {
+			if x != wall.gap {
+				m.Tiles[wall.y][x].Type = world.TileTree
+			}
+		}
+	}
+	s := NewAISystem(ecs.NewWorld(), map[int]*world.Map{0: m}, nil)
+
+	tile := float64(config.TileSize)
+	path := s.FindPath(m, 2*tile, 2*tile, 90*tile, 90*tile)
+	if s.graphs[m] == nil {
+		t.Fatal("long path on a big map didn't use the cluster graph")
+	}
+	if len(path) == 0 {
+		t.Fatal("no path through the gaps")
+	}
+	length := 0.0
+	prev := []float64{2 * tile, 2 * tile}
+	for _, node := range path {
+		if !s.HasLineOfSight(m, prev[0], prev[1], node[0], node[1]) {
+			t.Errorf("path leg %v -> %v cuts through a wall", prev, node)
+		}
+		length += segmentCost(m, prev[0], prev[1], node[0], node[1])
+		prev = node
+	}
+	if prev[0] != 90*tile || prev[1] != 90*tile {
+		t.Errorf("path ends at %v, want the far corner", prev)
+	}
+
+	_, best := searchTiles(m, 2, 2, 90, 90, tileRect{0, 0, m.Width, m.Height}, 0)
+	if length > best*1.2 {
+		t.Errorf("cluster path is %.1f tiles long, flat search found %.1f", length, best)
+	}
+}
+
+func TestFindPathIntoSealedRoom(t *testing.T) {
+	// A ring of trees around (80, 80) on an open map
+	m := world.NewMap(96, 96)
+	for y := 78; y <= 82; y++ {
+		for x := 78; x <= 82; x++ {
+			if x == 78 || x == 82 || y == 78 || y == 82 {
+				m.Tiles[y][x].Type = world.TileTree
+			}
+		}
+	}
+	s := NewAISystem(ecs.NewWorld(), map[int]*world.Map{0: m}, nil)
+
+	tile := float64(config.TileSize)
+	if path := s.FindPath(m, 2*tile, 2*tile, 80*tile, 80*tile); path != nil {
+		t.Errorf("long path into the room = %v, want none", path)
+	}
+	if path := s.FindPath(m, 76*tile, 80*tile, 80*tile, 80*tile); path != nil {
+		t.Errorf("short path into the room = %v, want none", path)
+	}
+	if s.graphs[m].connected(m, 76, 80, 80, 80) {
+		t.Error("the room should be its own area, rejected before any search")
+	}
+	if !s.graphs[m].connected(m, 2, 2, 76, 80) {
+		t.Error("open ground should be one area")
+	}
+}
+
+func TestFlatSearchGivesUpPastItsLimit(t *testing.T) {
+	m := world.NewMap(96, 96)
+	bounds := tileRect{0, 0, m.Width, m.Height}
+	if tiles, _ := searchTiles(m, 0, 0, 95, 95, bounds, 100); tiles != nil {
+		t.Error("a search limited to 100 tiles crossed the map")
+	}
+	if tiles, _ := searchTiles(m, 0, 0, 95, 95, bounds, MaxFlatSearch); tiles == nil {
+		t.Error("no path across an open map within MaxFlatSearch")
+	}
+}
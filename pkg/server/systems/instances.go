@@ -0,0 +1,74 @@
+package systems
+
+import (
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+	"log"
+)
+
+// InstanceLevelBase is the first level handed out to instanced maps. Static maps
+// loaded from data/maps stay below it.
+const InstanceLevelBase = 1000
+
+// ReturnPosition is where a player stood before being taken into an instance
+type ReturnPosition struct {
+	X, Y float64
+	Z    int
+}
+
+// InstanceSystem creates short-lived private maps (arena matches, dungeon runs) on their
+// own level. Entities only see and collide with entities on the same level, so an
+// instance is isolated from the world and from other instances.
+type InstanceSystem struct {
+	World *ecs.World
+	Maps  map[int]*world.Map // Shared with the other systems
+
+	next int
+}
+
+func NewInstanceSystem(world *ecs.World, maps map[int]*world.Map) *InstanceSystem {
+	return &InstanceSystem{
+		World: world,
+		Maps:  maps,
+		next:  InstanceLevelBase,
+	}
+}
+
+// Create registers the map on an unused level and returns that level
+func (s *InstanceSystem) Create(m *world.Map) int {
+	for {
+		level := s.next
+		s.next++
+		if _, taken := s.Maps[level]; !taken {
+			m.Level = level
+			s.Maps[level] = m
+			log.Printf("Instance created on level %d (%dx%d)", level, m.Width, m.Height)
+			return level
+		}
+	}
+}
+
+// Destroy removes the instance's map and everything left on its level except players,
+// who must be moved out first
+func (s *InstanceSystem) Destroy(level int) {
+	if !IsInstanceLevel(level) {
+		return
+	}
+	delete(s.Maps, level)
+	removed := 0
+	for _, id := range ecs.Query[components.TransformComponent](s.World) {
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if trans.Z != level || ecs.HasTag(s.World, id, components.TagPlayer) {
+			continue
+		}
+		s.World.RemoveEntity(id)
+		removed++
+	}
+	log.Printf("Instance on level %d destroyed (%d entities removed)", level, removed)
+}
+
+// IsInstanceLevel reports whether a level belongs to an instance rather than a static map
+func IsInstanceLevel(level int) bool {
+	return level >= InstanceLevelBase
+}
@@ -0,0 +1,65 @@
+package systems
+
+import (
+	"slices"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+// keyring returns a copy of an entity's keyring to change and put back (empty without one)
+func keyring(w *ecs.World, id ecs.Entity) components.KeyringComponent {
+	kr, ok := ecs.GetComponent[components.KeyringComponent](w, id)
+	if !ok {
+		return components.KeyringComponent{}
+	}
+	return components.KeyringComponent{Items: slices.Clone(kr.Items)}
+}
+
+// KeyringCount is how many of an item are on an entity's keyring
+func KeyringCount(w *ecs.World, id ecs.Entity, itemID string) int {
+	kr := keyring(w, id)
+	return items.KeyringCount(&kr, itemID)
+}
+
+// AddToKeyring puts keys or quest items on an entity's keyring (see items.OnKeyring).
+// Returns the instance ID they're held under.
+func AddToKeyring(w *ecs.World, id ecs.Entity, itemID, instanceID string, quantity int) string {
+	kr := keyring(w, id)
+	kept := items.AddToKeyring(&kr, itemID, instanceID, quantity)
+	ecs.AddComponent(w, id, kr)
+	return kept
+}
+
+// TakeFromKeyring takes items off an entity's keyring, all or nothing
+func TakeFromKeyring(w *ecs.World, id ecs.Entity, itemID string, quantity int) error {
+	kr := keyring(w, id)
+	if err := items.RemoveFromKeyring(&kr, itemID, quantity); err != nil {
+		return err
+	}
+	ecs.AddComponent(w, id, kr)
+	return nil
+}
+
+// PocketKeyring moves keys and quest items from an entity's bag onto its keyring (saves
+// from before keyrings). Reports whether there were any.
+func PocketKeyring(w *ecs.World, id ecs.Entity) bool {
+	inv, ok := ecs.GetComponent[components.InventoryComponent](w, id)
+	if !ok {
+		return false
+	}
+	moved := false
+	for i, slot := range inv.Slots {
+		if slot.ItemID == "" || !items.OnKeyring(slot.ItemID) {
+			continue
+		}
+		AddToKeyring(w, id, slot.ItemID, slot.InstanceID, slot.Quantity)
+		inv.Slots[i] = components.InventorySlot{}
+		moved = true
+	}
+	if moved {
+		ecs.AddComponent(w, id, *inv)
+	}
+	return moved
+}
@@ -0,0 +1,59 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+func TestQuestItemsGoOnTheKeyringNotTheBag(t *testing.T) {
+	w := ecs.NewWorld()
+	ground := NewGroundItemSystem(w)
+	player := w.NewEntity()
+	ecs.AddComponent(w, player, components.TransformComponent{X: 100, Y: 100})
+	inv := items.NewInventory(1)
+	items.AddItem(inv, "slime_gel", 1) // Bag full
+	ecs.AddComponent(w, player, *inv)
+
+	deed, _ := ground.Spawn(110, 110, 0, "house_deed", 1, 0)
+	if err := ground.Pickup(player, deed); err != nil {
+		t.Fatalf("quest item not picked up with a full bag: %v", err)
+	}
+	if err := GiveItem(w, player, "house_deed", 2); err != nil {
+		t.Fatalf("give: %v", err)
+	}
+	if n := KeyringCount(w, player, "house_deed"); n != 3 {
+		t.Errorf("keyring holds %d deeds, want 3", n)
+	}
+	if err := TakeFromKeyring(w, player, "house_deed", 4); err == nil {
+		t.Error("took more deeds than the keyring holds")
+	}
+	if err := TakeFromKeyring(w, player, "house_deed", 3); err != nil || KeyringCount(w, player, "house_deed") != 0 {
+		t.Errorf("deeds not taken off the keyring (%v)", err)
+	}
+}
+
+func TestPocketKeyringMovesQuestItemsOutOfOldBags(t *testing.T) {
+	w := ecs.NewWorld()
+	player := w.NewEntity()
+	inv := items.NewInventory(5)
+	items.AddItem(inv, "house_deed", 1)
+	items.AddItem(inv, "slime_gel", 2)
+	ecs.AddComponent(w, player, *inv)
+
+	if !PocketKeyring(w, player) {
+		t.Fatal("nothing moved to the keyring")
+	}
+	inv, _ = ecs.GetComponent[components.InventoryComponent](w, player)
+	if items.CountItem(inv, "house_deed") != 0 || items.CountItem(inv, "slime_gel") != 2 {
+		t.Error("bag not left with only its other items")
+	}
+	if KeyringCount(w, player, "house_deed") != 1 {
+		t.Error("deed not on the keyring")
+	}
+	if PocketKeyring(w, player) {
+		t.Error("moved quest items twice")
+	}
+}
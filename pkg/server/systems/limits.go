@@ -0,0 +1,144 @@
+package systems
+
+import (
+	"errors"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"log"
+)
+
+// Reasons a spawn was rejected (used as metric keys)
+const (
+	LimitEntityCap   = "entity_cap"
+	LimitOwnerCap    = "owner_cap"
+	LimitTickBudget  = "tick_budget"
+	LimitReportEvery = 60.0 // Seconds between rejection summaries in the log
+)
+
+var ErrEntityCap = errors.New("world entity cap reached")
+
+// SpawnLimiter guards the world against entity floods (macroed attacks, runaway spawners).
+// Counts are rebuilt from the world at the start of every tick, then bumped as spawns are allowed.
+type SpawnLimiter struct {
+	World *ecs.World
+
+	MaxEntities            int
+	MaxProjectilesPerOwner int
+	MaxProjectilesPerTick  int
+
+	// Metrics: rejections per reason since startup
+	Rejected map[string]int
+
+	entities        int
+	tickProjectiles int
+	owned           map[ecs.Entity]int
+
+	// Rejections since the last log summary
+	pending       map[string]int
+	pendingOwners map[ecs.Entity]int
+	reportTimer   float64
+}
+
+func NewSpawnLimiter(world *ecs.World) *SpawnLimiter {
+	return &SpawnLimiter{
+		World:                  world,
+		MaxEntities:            config.MaxWorldEntities,
+		MaxProjectilesPerOwner: config.MaxProjectilesPerOwner,
+		MaxProjectilesPerTick:  config.MaxProjectilesPerTick,
+		Rejected:               make(map[string]int),
+		owned:                  make(map[ecs.Entity]int),
+		pending:                make(map[string]int),
+		pendingOwners:          make(map[ecs.Entity]int),
+	}
+}
+
+// Update recounts live entities and resets the per-tick budget. Call once at the start of a tick.
+func (l *SpawnLimiter) Update(dt float64) {
+	l.entities = len(ecs.Query[components.TransformComponent](l.World))
+	l.tickProjectiles = 0
+
+	for owner := range l.owned {
+		delete(l.owned, owner)
+	}
+	for _, pid := range ecs.Query[components.ProjectileComponent](l.World) {
+		if proj, _ := ecs.GetComponent[components.ProjectileComponent](l.World, pid); proj != nil {
+			l.owned[proj.OwnerID]++
+		}
+	}
+
+	l.reportTimer += dt
+	if l.reportTimer >= LimitReportEvery {
+		l.reportTimer = 0
+		l.report()
+	}
+}
+
+// AllowEntity reserves room for one more entity under the global cap
+func (l *SpawnLimiter) AllowEntity() bool {
+	if l.MaxEntities > 0 && l.entities >= l.MaxEntities {
+		l.reject(LimitEntityCap, 0)
+		return false
+	}
+	l.entities++
+	return true
+}
+
+// AllowProjectile reserves a projectile for the owner, checking the per-owner cap,
+// the per-tick budget and the global cap (in that order)
+func (l *SpawnLimiter) AllowProjectile(owner ecs.Entity) bool {
+	if l.MaxProjectilesPerOwner > 0 && l.owned[owner] >= l.MaxProjectilesPerOwner {
+		l.reject(LimitOwnerCap, owner)
+		return false
+	}
+	if l.MaxProjectilesPerTick > 0 && l.tickProjectiles >= l.MaxProjectilesPerTick {
+		l.reject(LimitTickBudget, owner)
+		return false
+	}
+	if !l.AllowEntity() {
+		return false
+	}
+	l.tickProjectiles++
+	l.owned[owner]++
+	return true
+}
+
+// EntityCount returns the live entity count as of the last Update (plus spawns allowed since)
+func (l *SpawnLimiter) EntityCount() int {
+	return l.entities
+}
+
+func (l *SpawnLimiter) reject(reason string, owner ecs.Entity) {
+	l.Rejected[reason]++
+	l.pending[reason]++
+	if owner != 0 {
+		l.pendingOwners[owner]++
+	}
+}
+
+// report logs a summary of recent rejections and the worst offender (likely macroing)
+func (l *SpawnLimiter) report() {
+	total := 0
+	for _, n := range l.pending {
+		total += n
+	}
+	if total == 0 {
+		return
+	}
+
+	var worst ecs.Entity
+	for owner, n := range l.pendingOwners {
+		if worst == 0 || n > l.pendingOwners[worst] {
+			worst = owner
+		}
+	}
+
+	log.Printf("Spawn limits: %d rejected in the last %.0fs (entity_cap=%d owner_cap=%d tick_budget=%d), %d live entities",
+		total, LimitReportEvery, l.pending[LimitEntityCap], l.pending[LimitOwnerCap], l.pending[LimitTickBudget], l.entities)
+	if worst != 0 {
+		log.Printf("Spawn limits: top offender Entity %d (%d rejections)", worst, l.pendingOwners[worst])
+	}
+
+	l.pending = make(map[string]int)
+	l.pendingOwners = make(map[ecs.Entity]int)
+}
@@ -0,0 +1,317 @@
+package systems
+
+import (
+	"errors"
+	"fmt"
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"log"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const LootRollDuration = 30.0 // Seconds players have to answer a roll
+
+// Roll choices, best first
+const (
+	LootNeed  = "need"
+	LootGreed = "greed"
+	LootPass  = "pass"
+)
+
+// LootRoll is one boss drop being rolled for by the players who fought it
+type LootRoll struct {
+	ID       int
+	ItemID   string
+	Quantity int
+	X, Y     float64 // Where the boss died (all-pass drops land here)
+	Z        int
+	Eligible map[ecs.Entity]bool
+	Choices  map[ecs.Entity]string
+	TimeLeft float64
+}
+
+// ItemName is the display name of the rolled item
+func (r *LootRoll) ItemName() string {
+	return itemName(r.ItemID)
+}
+
+// LootSystem hands out boss/elite loot tables. A drop with a single eligible player goes
+// straight to their inventory; with several, it's rolled need > greed, highest d100 wins.
+type LootSystem struct {
+	World       *ecs.World
+	GroundItems *GroundItemSystem // Overflow and all-pass drops
+	Audit       *ItemAudit        // Optional item audit trail
+
+	// Hooks provided by the GameServer
+	OnRollStart func(player ecs.Entity, roll *LootRoll)
+	OnMessage   func(player ecs.Entity, msg string)
+	OnDeliver   func(player ecs.Entity) // Inventory changed
+
+	attackers map[ecs.Entity]map[ecs.Entity]bool // Boss -> players who damaged it
+	rolls     map[int]*LootRoll
+	nextID    int
+	rng       *rand.Rand
+}
+
+func NewLootSystem(world *ecs.World, groundItems *GroundItemSystem) *LootSystem {
+	return &LootSystem{
+		World:       world,
+		GroundItems: groundItems,
+		attackers:   make(map[ecs.Entity]map[ecs.Entity]bool),
+		rolls:       make(map[int]*LootRoll),
+		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// RecordDamage remembers players hitting an entity that has a loot table
+func (s *LootSystem) RecordDamage(attacker, victim ecs.Entity) {
+	if !ecs.HasTag(s.World, attacker, components.TagPlayer) {
+		return
+	}
+	if _, ok := ecs.GetComponent[components.LootComponent](s.World, victim); !ok {
+		return
+	}
+	if s.attackers[victim] == nil {
+		s.attackers[victim] = make(map[ecs.Entity]bool)
+	}
+	s.attackers[victim][attacker] = true
+}
+
+// DropLoot rolls the victim's loot table on death. Call before the victim is despawned.
+func (s *LootSystem) DropLoot(victim ecs.Entity) {
+	eligible := s.attackers[victim]
+	delete(s.attackers, victim)
+
+	loot, ok := ecs.GetComponent[components.LootComponent](s.World, victim)
+	if !ok || len(eligible) == 0 {
+		return
+	}
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, victim)
+	if !ok {
+		return
+	}
+
+	for _, entry := range loot.Table {
+		if s.rng.Float64() >= entry.Chance {
+			continue
+		}
+		roll := &LootRoll{
+			ItemID:   entry.ItemID,
+			Quantity: max(entry.Quantity, 1),
+			X:        trans.X,
+			Y:        trans.Y,
+			Z:        trans.Z,
+			Eligible: make(map[ecs.Entity]bool),
+			Choices:  make(map[ecs.Entity]string),
+			TimeLeft: LootRollDuration,
+		}
+		for id := range eligible {
+			if _, ok := ecs.GetComponent[components.InventoryComponent](s.World, id); ok {
+				roll.Eligible[id] = true
+			}
+		}
+
+		switch len(roll.Eligible) {
+		case 0:
+			s.dropOnGround(roll, 0)
+		case 1:
+			for id := range roll.Eligible {
+				s.deliver(roll, id)
+			}
+		default:
+			s.nextID++
+			roll.ID = s.nextID
+			s.rolls[roll.ID] = roll
+			log.Printf("Loot roll %d started: %dx %s among %d players", roll.ID, roll.Quantity, roll.ItemID, len(roll.Eligible))
+			if s.OnRollStart != nil {
+				for id := range roll.Eligible {
+					s.OnRollStart(id, roll)
+				}
+			}
+		}
+	}
+}
+
+// Choose records a player's answer. The roll resolves as soon as everyone has answered.
+func (s *LootSystem) Choose(player ecs.Entity, rollID int, choice string) error {
+	roll, ok := s.rolls[rollID]
+	if !ok {
+		return errors.New("roll has ended")
+	}
+	if !roll.Eligible[player] {
+		return errors.New("not eligible for this roll")
+	}
+	if _, answered := roll.Choices[player]; answered {
+		return errors.New("already answered")
+	}
+	if choice != LootNeed && choice != LootGreed && choice != LootPass {
+		return fmt.Errorf("invalid choice %q", choice)
+	}
+	roll.Choices[player] = choice
+	if len(roll.Choices) == len(roll.Eligible) {
+		s.resolve(roll)
+	}
+	return nil
+}
+
+// ForgetPlayer removes a disconnecting player from pending rolls and attacker lists
+func (s *LootSystem) ForgetPlayer(player ecs.Entity) {
+	for _, set := range s.attackers {
+		delete(set, player)
+	}
+	for _, roll := range s.rolls {
+		if !roll.Eligible[player] {
+			continue
+		}
+		delete(roll.Eligible, player)
+		delete(roll.Choices, player)
+		if len(roll.Choices) == len(roll.Eligible) {
+			s.resolve(roll)
+		}
+	}
+}
+
+// Update times out rolls (unanswered = pass) and forgets attackers of removed bosses
+func (s *LootSystem) Update(dt float64) {
+	for _, roll := range s.rolls {
+		roll.TimeLeft -= dt
+		if roll.TimeLeft <= 0 {
+			s.resolve(roll)
+		}
+	}
+	for victim := range s.attackers {
+		if _, ok := ecs.GetComponent[components.StatsComponent](s.World, victim); !ok {
+			delete(s.attackers, victim)
+		}
+	}
+}
+
+// resolve picks the winner: best choice (need > greed) first, then the highest d100,
+// re-rolling ties. If everyone passed the item drops on the ground for anyone.
+func (s *LootSystem) resolve(roll *LootRoll) {
+	delete(s.rolls, roll.ID)
+
+	choice := LootGreed
+	var candidates []ecs.Entity
+	for id, c := range roll.Choices {
+		if c == LootNeed {
+			candidates = append(candidates, id)
+		}
+	}
+	if len(candidates) > 0 {
+		choice = LootNeed
+	} else {
+		for id, c := range roll.Choices {
+			if c == LootGreed {
+				candidates = append(candidates, id)
+			}
+		}
+	}
+
+	name := itemName(roll.ItemID)
+	if len(candidates) == 0 {
+		s.broadcast(roll, "Everyone passed on "+name+". It was left on the ground.")
+		s.dropOnGround(roll, 0)
+		return
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] }) // Stable rng order
+
+	var winner ecs.Entity
+	var best int
+	for winner == 0 {
+		best = 0
+		tied := 0
+		for _, id := range candidates {
+			r := 1 + s.rng.Intn(100)
+			if r > best {
+				best, winner, tied = r, id, 1
+			} else if r == best {
+				tied++
+			}
+		}
+		if tied > 1 {
+			winner = 0
+		}
+	}
+
+	log.Printf("Loot roll %d: Entity %d won %s (%s %d)", roll.ID, winner, roll.ItemID, choice, best)
+	s.broadcast(roll, fmt.Sprintf("%s won %s (%s %d)", s.playerName(winner), name, choice, best))
+	s.deliver(roll, winner)
+}
+
+// deliver puts the item straight into the winner's inventory (gold into their wallet), or
+// at their feet if it's full
+func (s *LootSystem) deliver(roll *LootRoll, winner ecs.Entity) {
+	if GiveItem(s.World, winner, roll.ItemID, roll.Quantity) == nil {
+		s.Audit.Record("won", winner, "", roll.ItemID, "", roll.Quantity)
+		if s.OnDeliver != nil {
+			s.OnDeliver(winner)
+		}
+		if s.OnMessage != nil {
+			s.OnMessage(winner, fmt.Sprintf("You received %dx %s", roll.Quantity, itemName(roll.ItemID)))
+		}
+		return
+	}
+
+	if trans, ok := ecs.GetComponent[components.TransformComponent](s.World, winner); ok {
+		roll.X, roll.Y, roll.Z = trans.X, trans.Y, trans.Z
+	}
+	if s.OnMessage != nil {
+		s.OnMessage(winner, "Inventory full: "+itemName(roll.ItemID)+" was dropped at your feet")
+	}
+	s.dropOnGround(roll, winner)
+}
+
+// DropTable rolls a common drop table (characters.CharacterDefinition.Drops) and leaves
+// what drops in a row at x, y for the owner (the killer)
+func (s *LootSystem) DropTable(table []components.LootEntry, x, y float64, z int, owner ecs.Entity) {
+	if s.GroundItems == nil {
+		return
+	}
+	dropped := 0
+	for _, entry := range table {
+		if s.rng.Float64() >= entry.Chance {
+			continue
+		}
+		if _, err := s.GroundItems.Spawn(x+float64(dropped)*GroundItemSize, y, z, entry.ItemID, max(entry.Quantity, 1), owner); err != nil {
+			log.Printf("Drop of %s failed: %v", entry.ItemID, err)
+			continue
+		}
+		dropped++
+	}
+}
+
+func (s *LootSystem) dropOnGround(roll *LootRoll, owner ecs.Entity) {
+	if s.GroundItems == nil {
+		return
+	}
+	if _, err := s.GroundItems.Spawn(roll.X, roll.Y, roll.Z, roll.ItemID, roll.Quantity, owner); err != nil {
+		log.Printf("Loot drop of %s failed: %v", roll.ItemID, err)
+	}
+}
+
+func (s *LootSystem) broadcast(roll *LootRoll, msg string) {
+	if s.OnMessage == nil {
+		return
+	}
+	for id := range roll.Eligible {
+		s.OnMessage(id, msg)
+	}
+}
+
+func (s *LootSystem) playerName(id ecs.Entity) string {
+	if name, ok := ecs.GetComponent[components.NameComponent](s.World, id); ok && name.Name != "" {
+		return name.Name
+	}
+	return fmt.Sprintf("Entity %d", id)
+}
+
+func itemName(itemID string) string {
+	if def, ok := items.Get(itemID); ok {
+		return def.Name
+	}
+	return itemID
+}
@@ -0,0 +1,130 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+// lootWorld spawns a boss that always drops one bow and n players who all hit it
+func lootWorld(t *testing.T, n int) (*LootSystem, ecs.Entity, []ecs.Entity) {
+	t.Helper()
+	w := ecs.NewWorld()
+	s := NewLootSystem(w, NewGroundItemSystem(w))
+
+	boss := w.NewEntity()
+	ecs.AddComponent(w, boss, components.TransformComponent{X: 100, Y: 100})
+	ecs.AddComponent(w, boss, components.StatsComponent{MaxHealth: 10})
+	ecs.AddComponent(w, boss, components.LootComponent{Table: []components.LootEntry{{ItemID: "bow_starter", Quantity: 1, Chance: 1}}})
+
+	players := spawnTestPlayers(w, n, 120, 100)
+	for _, id := range players {
+		s.RecordDamage(id, boss)
+	}
+	return s, boss, players
+}
+
+func hasBow(s *LootSystem, id ecs.Entity) bool {
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	return items.CountItem(inv, "bow_starter") == 1
+}
+
+func TestLootSoloGoesStraightToInventory(t *testing.T) {
+	s, boss, players := lootWorld(t, 1)
+	s.DropLoot(boss)
+
+	if len(s.rolls) != 0 {
+		t.Fatalf("solo kill started %d rolls", len(s.rolls))
+	}
+	if !hasBow(s, players[0]) {
+		t.Error("solo killer should receive the bow")
+	}
+}
+
+func TestLootNeedBeatsGreed(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		s, boss, players := lootWorld(t, 3)
+		s.DropLoot(boss)
+		if len(s.rolls) != 1 {
+			t.Fatalf("rolls = %d, want 1", len(s.rolls))
+		}
+		rollID := s.nextID
+
+		if err := s.Choose(players[0], rollID, LootGreed); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Choose(players[0], rollID, LootNeed); err == nil {
+			t.Error("answering twice should fail")
+		}
+		s.Choose(players[1], rollID, LootNeed)
+		s.Choose(players[2], rollID, LootPass)
+
+		if len(s.rolls) != 0 {
+			t.Fatal("roll should resolve once everyone answered")
+		}
+		if !hasBow(s, players[1]) || hasBow(s, players[0]) || hasBow(s, players[2]) {
+			t.Fatal("the only need roll should win")
+		}
+	}
+}
+
+func TestLootTimeoutAllPassDropsOnGround(t *testing.T) {
+	s, boss, players := lootWorld(t, 2)
+	s.DropLoot(boss)
+	s.Choose(players[0], s.nextID, LootPass)
+
+	s.Update(LootRollDuration + 1)
+
+	if len(s.rolls) != 0 {
+		t.Fatal("roll should time out")
+	}
+	for _, id := range players {
+		if hasBow(s, id) {
+			t.Error("nobody rolled, nobody should get the bow")
+		}
+	}
+	if len(ecs.QueryTagged(s.World, components.TagGroundItem)) != 1 {
+		t.Error("passed loot should be left on the ground")
+	}
+}
+
+func TestLootChooseValidation(t *testing.T) {
+	s, boss, players := lootWorld(t, 2)
+	s.DropLoot(boss)
+
+	if err := s.Choose(players[0], s.nextID+1, LootNeed); err == nil {
+		t.Error("unknown roll should be rejected")
+	}
+	if err := s.Choose(boss, s.nextID, LootNeed); err == nil {
+		t.Error("non-participants should be rejected")
+	}
+	if err := s.Choose(players[0], s.nextID, "mine"); err == nil {
+		t.Error("invalid choice should be rejected")
+	}
+}
+
+func TestLootDropTableLeavesDropsForTheKiller(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewLootSystem(w, NewGroundItemSystem(w))
+	killer := w.NewEntity()
+
+	s.DropTable([]components.LootEntry{
+		{ItemID: "potion_health_small", Quantity: 2, Chance: 1},
+		{ItemID: "bow_starter", Chance: 1},
+		{ItemID: "sword_starter", Quantity: 1, Chance: 0},
+	}, 100, 100, 0, killer)
+
+	dropped := make(map[string]int)
+	for _, id := range ecs.Query[components.GroundItemComponent](w) {
+		item, _ := ecs.GetComponent[components.GroundItemComponent](w, id)
+		if item.OwnerID != killer {
+			t.Errorf("%s belongs to %d, want the killer", item.ItemID, item.OwnerID)
+		}
+		dropped[item.ItemID] += item.Quantity
+	}
+	if len(dropped) != 2 || dropped["potion_health_small"] != 2 || dropped["bow_starter"] != 1 {
+		t.Errorf("dropped %v", dropped)
+	}
+}
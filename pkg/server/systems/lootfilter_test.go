@@ -0,0 +1,45 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+func TestAutoPickupOnlyTakesWhatTheFilterAllows(t *testing.T) {
+	w := ecs.NewWorld()
+	ground := NewGroundItemSystem(w)
+	player := w.NewEntity()
+	ecs.AddComponent(w, player, components.TransformComponent{X: 100, Y: 100})
+	ecs.AddComponent(w, player, *items.NewInventory(5))
+
+	coins, _ := ground.Spawn(110, 110, 0, items.Gold, 7, 0)
+	deed, _ := ground.Spawn(110, 110, 0, "house_deed", 1, 0)
+	gel, _ := ground.Spawn(110, 110, 0, "slime_gel", 1, 0)
+
+	filter := items.LootFilter{AutoQuest: true, IgnoreJunk: true}
+	if err := ground.AutoPickup(player, coins, filter); err == nil {
+		t.Error("picked up gold with gold auto-pickup off")
+	}
+	if err := ground.AutoPickup(player, gel, filter); err == nil {
+		t.Error("auto-picked up junk")
+	}
+	if err := ground.AutoPickup(player, deed, filter); err != nil {
+		t.Errorf("quest item not auto-picked up: %v", err)
+	}
+
+	filter.AutoGold = true
+	if err := ground.AutoPickup(player, coins, filter); err != nil {
+		t.Errorf("gold not auto-picked up: %v", err)
+	}
+	if gold := Gold(w, player); gold != 7 {
+		t.Errorf("wallet holds %d gold, want 7", gold)
+	}
+
+	// A click still takes anything in reach
+	if err := ground.Pickup(player, gel); err != nil {
+		t.Errorf("manual pickup of junk failed: %v", err)
+	}
+}
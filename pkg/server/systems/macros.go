@@ -0,0 +1,157 @@
+package systems
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"henry/pkg/storage"
+)
+
+// Reasons an account gets flagged for macro use
+const (
+	MacroPeriodic = "periodic_input"
+	MacroReaction = "inhuman_reaction"
+	MacroNonstop  = "nonstop_activity"
+)
+
+const (
+	MacroSamples       = 20            // Presses of one action compared for periodicity
+	MacroMaxInterval   = 10.0          // Seconds; slower presses aren't considered clicking
+	MacroMaxJitter     = 0.02          // Spread of the intervals relative to their mean (humans: well over 0.1)
+	MacroMinReaction   = 0.12          // Seconds from prompt to answer, network latency included
+	MacroFastReactions = 5             // Reactions below MacroMinReaction in a row
+	MacroHourActive    = 45 * 60.0     // Seconds of activity that make an hour count as active
+	MacroActiveHours   = 20            // Active hours out of the last 24
+	MacroFlagCooldown  = 6 * time.Hour // An account isn't flagged again for the same reason sooner
+)
+
+// macroTrack is what is known about one account's input
+type macroTrack struct {
+	presses map[string][]time.Time // Action -> recent presses, oldest first
+	prompt  time.Time              // When the pending reaction prompt was shown (zero = none)
+	fast    int                    // Too-fast reactions in a row
+	active  [24]float64            // Active seconds per hour of the day, for the last 24 hours
+	hour    int64                  // Unix hour the active buckets were last written
+	flagged map[string]time.Time   // Reason -> when last flagged
+}
+
+// MacroDetector watches input streams for signs of macros and auto-clickers: presses
+// that repeat too evenly, reactions faster than a person can manage, and activity around
+// the clock. It only flags the account (through OnFlag) for an admin to review; it never
+// punishes anyone itself. Accounts are tracked by username so relogging doesn't reset them.
+type MacroDetector struct {
+	// OnFlag receives each new suspicion
+	OnFlag func(flag storage.MacroFlag)
+
+	// Clock (replaced in tests)
+	Now func() time.Time
+
+	tracks map[string]*macroTrack
+}
+
+func NewMacroDetector() *MacroDetector {
+	return &MacroDetector{Now: time.Now, tracks: make(map[string]*macroTrack)}
+}
+
+func (d *MacroDetector) track(username string) *macroTrack {
+	t, ok := d.tracks[username]
+	if !ok {
+		t = &macroTrack{presses: make(map[string][]time.Time), flagged: make(map[string]time.Time)}
+		d.tracks[username] = t
+	}
+	return t
+}
+
+// Press records a key press or click (the rising edge, not the hold) of an action
+func (d *MacroDetector) Press(username, action string) {
+	t := d.track(username)
+	presses := append(t.presses[action], d.Now())
+	if len(presses) > MacroSamples {
+		presses = presses[len(presses)-MacroSamples:]
+	}
+	t.presses[action] = presses
+	if len(presses) < MacroSamples {
+		return
+	}
+
+	var sum float64
+	intervals := make([]float64, len(presses)-1)
+	for i := range intervals {
+		intervals[i] = presses[i+1].Sub(presses[i]).Seconds()
+		sum += intervals[i]
+	}
+	mean := sum / float64(len(intervals))
+	if mean <= 0 || mean > MacroMaxInterval {
+		return
+	}
+	var variance float64
+	for _, interval := range intervals {
+		variance += (interval - mean) * (interval - mean)
+	}
+	spread := math.Sqrt(variance / float64(len(intervals)))
+	if spread/mean < MacroMaxJitter {
+		d.flag(username, t, MacroPeriodic, fmt.Sprintf("%d %s presses %.3fs apart (±%.1fms)",
+			len(presses), action, mean, spread*1000))
+		t.presses[action] = nil
+	}
+}
+
+// Prompt records that the player was just shown something to react to (a fish biting)
+func (d *MacroDetector) Prompt(username string) {
+	d.track(username).prompt = d.Now()
+}
+
+// React records the player answering the last prompt. Answers without a prompt are ignored.
+func (d *MacroDetector) React(username string) {
+	t := d.track(username)
+	if t.prompt.IsZero() {
+		return
+	}
+	reaction := d.Now().Sub(t.prompt).Seconds()
+	t.prompt = time.Time{}
+	if reaction >= MacroMinReaction {
+		t.fast = 0
+		return
+	}
+	t.fast++
+	if t.fast >= MacroFastReactions {
+		d.flag(username, t, MacroReaction, fmt.Sprintf("%d reactions in a row under %.0fms (last %.0fms)",
+			t.fast, MacroMinReaction*1000, reaction*1000))
+		t.fast = 0
+	}
+}
+
+// Active adds dt seconds in which the player was online and not AFK
+func (d *MacroDetector) Active(username string, dt float64) {
+	t := d.track(username)
+	hour := d.Now().Unix() / 3600
+	// Hours nobody was active in since the last write start from zero
+	for h := max(t.hour+1, hour-23); h <= hour; h++ {
+		t.active[h%24] = 0
+	}
+	t.hour = max(t.hour, hour)
+	t.active[hour%24] += dt
+
+	hours := 0
+	for _, seconds := range t.active {
+		if seconds >= MacroHourActive {
+			hours++
+		}
+	}
+	if hours >= MacroActiveHours {
+		d.flag(username, t, MacroNonstop, fmt.Sprintf("active in %d of the last 24 hours", hours))
+	}
+}
+
+// flag hands a suspicion to OnFlag, unless the account was flagged for it recently
+func (d *MacroDetector) flag(username string, t *macroTrack, reason, detail string) {
+	now := d.Now()
+	if last, ok := t.flagged[reason]; ok && now.Sub(last) < MacroFlagCooldown {
+		return
+	}
+	t.flagged[reason] = now
+	if d.OnFlag != nil {
+		d.OnFlag(storage.MacroFlag{Username: username, Reason: reason, Detail: detail, FlaggedAt: now})
+	}
+}
@@ -0,0 +1,108 @@
+package systems
+
+import (
+	"testing"
+	"time"
+
+	"henry/pkg/storage"
+)
+
+// newTestMacroDetector runs on a clock the test moves by hand and collects the flags
+func newTestMacroDetector(now *time.Time, flags *[]storage.MacroFlag) *MacroDetector {
+	d := NewMacroDetector()
+	d.Now = func() time.Time { return *now }
+	d.OnFlag = func(flag storage.MacroFlag) { *flags = append(*flags, flag) }
+	return d
+}
+
+func TestMacroPeriodicPresses(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var flags []storage.MacroFlag
+	d := newTestMacroDetector(&now, &flags)
+
+	// A person clicking roughly every half second
+	human := []int{480, 530, 610, 450, 505, 570, 420, 495}
+	for i := range MacroSamples * 2 {
+		d.Press("alice", "attack")
+		now = now.Add(time.Duration(human[i%len(human)]) * time.Millisecond)
+	}
+	if len(flags) != 0 {
+		t.Fatalf("human clicking was flagged: %v", flags)
+	}
+
+	// An auto-clicker, a millisecond of network jitter at most
+	for i := range MacroSamples {
+		d.Press("bot", "attack")
+		now = now.Add(time.Duration(500+i%2) * time.Millisecond)
+	}
+	if len(flags) != 1 || flags[0].Username != "bot" || flags[0].Reason != MacroPeriodic {
+		t.Fatalf("flags = %v, want the bot flagged once for periodic input", flags)
+	}
+
+	// The same suspicion isn't reported again right away
+	for range MacroSamples {
+		d.Press("bot", "attack")
+		now = now.Add(500 * time.Millisecond)
+	}
+	if len(flags) != 1 {
+		t.Errorf("bot flagged %d times within the cooldown", len(flags))
+	}
+}
+
+func TestMacroReactionTimes(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var flags []storage.MacroFlag
+	d := newTestMacroDetector(&now, &flags)
+
+	react := func(username string, after time.Duration) {
+		d.Prompt(username)
+		now = now.Add(after)
+		d.React(username)
+		now = now.Add(10 * time.Second)
+	}
+	// One slow reaction breaks the streak
+	for i := range MacroFastReactions*2 - 1 {
+		after := 80 * time.Millisecond
+		if i == MacroFastReactions-1 {
+			after = 300 * time.Millisecond
+		}
+		react("lucky", after)
+	}
+	d.React("lucky") // No prompt pending
+	if len(flags) != 0 {
+		t.Fatalf("flags = %v, want none without %d fast reactions in a row", flags, MacroFastReactions)
+	}
+
+	for range MacroFastReactions {
+		react("bot", 40*time.Millisecond)
+	}
+	if len(flags) != 1 || flags[0].Reason != MacroReaction {
+		t.Errorf("flags = %v, want one inhuman reaction flag", flags)
+	}
+}
+
+func TestMacroNonstopActivity(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var flags []storage.MacroFlag
+	d := newTestMacroDetector(&now, &flags)
+
+	// Active every hour, except that a few-hour gap two days ago was forgotten since
+	play := func(hours int) {
+		for range hours {
+			for range 60 {
+				d.Active("bot", 60)
+				now = now.Add(time.Minute)
+			}
+		}
+	}
+	play(MacroActiveHours - 1)
+	now = now.Add(24 * time.Hour)
+	play(MacroActiveHours - 1)
+	if len(flags) != 0 {
+		t.Fatalf("flags = %v after %d active hours", flags, MacroActiveHours-1)
+	}
+	play(1)
+	if len(flags) != 1 || flags[0].Reason != MacroNonstop {
+		t.Errorf("flags = %v, want one nonstop activity flag", flags)
+	}
+}
@@ -0,0 +1,141 @@
+package systems
+
+import (
+	"errors"
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/storage"
+	"slices"
+	"time"
+)
+
+// MailSystem keeps item mail addressed to usernames, so rewards reach players who are
+// dead or offline when they're earned. Recipients take the attachments into their bags.
+type MailSystem struct {
+	World *ecs.World
+	Store *storage.MailStore
+	Audit *ItemAudit // Optional item audit trail
+
+	// Called after mail is added to a mailbox
+	OnDeliver func(to string)
+}
+
+func NewMailSystem(world *ecs.World, store *storage.MailStore) *MailSystem {
+	if store == nil {
+		store = &storage.MailStore{NextID: 1, Boxes: make(map[string][]storage.Mail)}
+	}
+	return &MailSystem{
+		World: world,
+		Store: store,
+	}
+}
+
+// Send puts a mail with item attachments in a player's mailbox. Attachments get
+// instance IDs (see withInstanceIDs), so they can be followed from sender to bag.
+func (s *MailSystem) Send(to, from, subject string, attachments []storage.MailItem) {
+	attachments = withInstanceIDs(attachments)
+	mail := storage.Mail{
+		ID:      s.Store.NextID,
+		From:    from,
+		Subject: subject,
+		Items:   attachments,
+		Sent:    time.Now().Unix(),
+	}
+	s.Store.NextID++
+	s.Store.Boxes[to] = append(s.Store.Boxes[to], mail)
+	for _, item := range attachments {
+		s.Audit.RecordAccount("mailed", to, from, item.ItemID, item.InstanceID, item.Quantity)
+	}
+	if s.OnDeliver != nil {
+		s.OnDeliver(to)
+	}
+}
+
+// withInstanceIDs returns a copy of attachments where every item but gold has an
+// instance ID, minting the missing ones. Copies of an item that doesn't stack are split
+// up, one per attachment, as they would be in a bag.
+func withInstanceIDs(attachments []storage.MailItem) []storage.MailItem {
+	out := make([]storage.MailItem, 0, len(attachments))
+	for _, item := range attachments {
+		if item.ItemID == items.Gold || item.InstanceID != "" {
+			out = append(out, item)
+			continue
+		}
+		if def, ok := items.Registry[item.ItemID]; ok && !def.Stackable() {
+			for range item.Quantity {
+				out = append(out, storage.MailItem{ItemID: item.ItemID, Quantity: 1, InstanceID: items.NewInstanceID()})
+			}
+			continue
+		}
+		item.InstanceID = items.NewInstanceID()
+		out = append(out, item)
+	}
+	return out
+}
+
+// Inbox lists a player's unclaimed mail, oldest first
+func (s *MailSystem) Inbox(username string) []storage.Mail {
+	return s.Store.Boxes[username]
+}
+
+// Take moves a mail's attachments into the recipient's inventory and deletes the mail.
+// Nothing is taken unless everything fits.
+func (s *MailSystem) Take(id ecs.Entity, username string, mailID int) error {
+	box := s.Store.Boxes[username]
+	index := -1
+	for i, mail := range box {
+		if mail.ID == mailID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return errors.New("no such mail")
+	}
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, id)
+	if inv == nil {
+		return errors.New("invalid recipient")
+	}
+
+	// inv is a copy, but its Slots share the world's array: add to a copy of those too,
+	// so nothing is taken unless everything fits
+	mail := box[index]
+	attachments := withInstanceIDs(mail.Items) // Mail from before instance IDs
+	inv.Slots = slices.Clone(inv.Slots)
+	kept := make([]string, len(attachments)) // The ID each attachment ends up held under
+	gold := 0
+	var keys []int
+	for i, item := range attachments {
+		if item.ItemID == items.Gold {
+			gold += item.Quantity
+			continue
+		}
+		if items.OnKeyring(item.ItemID) {
+			keys = append(keys, i)
+			continue
+		}
+		var err error
+		if kept[i], err = items.AddInstance(inv, item.ItemID, item.InstanceID, item.Quantity); err != nil {
+			return errors.New("not enough room in your bag")
+		}
+	}
+	ecs.AddComponent(s.World, id, *inv)
+	if gold > 0 {
+		AddGold(s.World, id, gold)
+	}
+	for _, i := range keys {
+		item := attachments[i]
+		kept[i] = AddToKeyring(s.World, id, item.ItemID, item.InstanceID, item.Quantity)
+	}
+	for i, item := range attachments {
+		s.Audit.RecordAccount("claimed", username, mail.From, item.ItemID, item.InstanceID, item.Quantity)
+		s.Audit.RecordMerge(id, item.ItemID, item.InstanceID, kept[i], item.Quantity)
+	}
+
+	s.Store.Boxes[username] = append(box[:index:index], box[index+1:]...)
+	if len(s.Store.Boxes[username]) == 0 {
+		delete(s.Store.Boxes, username)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/world"
+)
+
+// walledMaps is a 10x5 level 0 split by a water column at x=5, with a spawner on the far side
+func walledMaps() map[int]*world.Map {
+	m := world.NewMap(10, 5)
+	for y := 0; y < 5; y++ {
+		m.Tiles[y][5].Type = world.TileWater
+	}
+	m.Spawners = []world.Spawner{{X: 8 * config.TileSize, Y: 2 * config.TileSize, CharacterID: "guard_melee"}}
+	return map[int]*world.Map{0: m}
+}
+
+func checkMaps(maps map[int]*world.Map) *world.MapReport {
+	spawn := world.MapPoint{Kind: "spawn", Name: "player", X: config.TileSize, Y: config.TileSize}
+	return world.ValidateMaps(maps, spawn, config.TileSize, components.DefaultColliderSize, config.ShorePushRange)
+}
+
+func TestMapCheckReportsWalledOffSpawner(t *testing.T) {
+	report := checkMaps(walledMaps())
+	if len(report.Unreachable) != 1 || report.Unreachable[0].Kind != "spawner" {
+		t.Fatalf("unreachable = %v, want the spawner behind the water", report.Unreachable)
+	}
+	if len(report.Pockets) != 1 || report.Pockets[0].TX != 6 {
+		t.Errorf("pockets = %+v, want the far bank starting at tile 6", report.Pockets)
+	}
+}
+
+func TestMapCheckFollowsTeleports(t *testing.T) {
+	maps := walledMaps()
+	maps[0].Triggers = []world.Trigger{{
+		ID: "ferry", X: 3 * config.TileSize, Y: 2 * config.TileSize, Width: config.TileSize, Height: config.TileSize,
+		Action: "teleport", TargetX: 7 * config.TileSize, TargetY: 2 * config.TileSize,
+	}}
+	if report := checkMaps(maps); !report.OK() || len(report.Pockets) != 0 {
+		t.Errorf("report = %+v, want everything reachable through the ferry", report)
+	}
+}
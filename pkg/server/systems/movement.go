@@ -1,9 +1,12 @@
 package systems
 
 import (
+	"errors"
 	"henry/pkg/shared/components"
 	"henry/pkg/shared/config"
 	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	protocol "henry/pkg/shared/network"
 	"henry/pkg/shared/world"
 	"math"
 )
@@ -12,6 +15,23 @@ type MovementSystem struct {
 	World        *ecs.World
 	Maps         map[int]*world.Map
 	CombatTimers map[ecs.Entity]float64
+
+	// Tells a player's client the server didn't move them as their input asked (at most
+	// every config.MoveCorrectionInterval)
+	OnCorrect func(player ecs.Entity, correction protocol.MoveCorrectionPacket)
+
+	// Grid indexes every entity's position for collision checks; shared with the AI and
+	// projectiles
+	Grid *SpatialGrid
+
+	dodges    map[ecs.Entity]*dodgeRoll
+	corrected map[ecs.Entity]float64 // Seconds until a player can be sent another correction
+}
+
+// dodgeRoll is a dodge in progress
+type dodgeRoll struct {
+	dx, dy float64 // Unit direction
+	left   float64 // Seconds
 }
 
 func NewMovementSystem(world *ecs.World, atlas map[int]*world.Map) *MovementSystem {
@@ -19,26 +39,92 @@ func NewMovementSystem(world *ecs.World, atlas map[int]*world.Map) *MovementSyst
 		World:        world,
 		Maps:         atlas,
 		CombatTimers: make(map[ecs.Entity]float64),
+		Grid:         NewSpatialGrid(),
+		dodges:       make(map[ecs.Entity]*dodgeRoll),
+		corrected:    make(map[ecs.Entity]float64),
 	}
 }
 
 func (s *MovementSystem) Update(dt float64) {
-	// Query all entities with Input, Transform, and Physics components
-	entities := ecs.Query[components.InputComponent](s.World)
-	for _, id := range entities {
-		s.UpdateEntityMovement(id, dt)
+	s.Grid.Rebuild(s.World)
+
+	// Everything steered by an Input (players and NPCs) that has a body to move, corpses aside
+	ecs.Each3(s.World, func(id ecs.Entity, input *components.InputComponent, transform *components.TransformComponent, phys *components.PhysicsComponent) {
+		s.UpdateEntityMovement(id, input, transform, phys, dt)
+	}, ecs.Without[components.DeadComponent]())
+
+	for id, roll := range s.dodges {
+		roll.left -= dt
+		if roll.left <= 0 {
+			delete(s.dodges, id)
+		}
+	}
+	for id, left := range s.corrected {
+		if left -= dt; left <= 0 {
+			delete(s.corrected, id)
+		} else {
+			s.corrected[id] = left
+		}
 	}
 }
 
-func (s *MovementSystem) UpdateEntityMovement(id ecs.Entity, dt float64) {
-	input, _ := ecs.GetComponent[components.InputComponent](s.World, id)
+// ForgetPlayer drops a disconnected player's correction cooldown
+func (s *MovementSystem) ForgetPlayer(id ecs.Entity) {
+	delete(s.corrected, id)
+}
+
+// Dodge starts a dodge roll: a short speed burst in the input direction (or facing, when
+// standing still) during which the entity can't be hurt. Costs config.DodgeStaminaCost.
+func (s *MovementSystem) Dodge(id ecs.Entity, input components.InputComponent) error {
+	stance, _ := ecs.GetComponent[components.StanceComponent](s.World, id)
 	transform, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
-	phys, _ := ecs.GetComponent[components.PhysicsComponent](s.World, id)
+	stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id)
+	if stance == nil || transform == nil || stats == nil || stats.CurrentHealth <= 0 {
+		return errors.New("can't dodge")
+	}
+	if s.dodges[id] != nil {
+		return errors.New("already dodging")
+	}
+	if stance.Stamina < config.DodgeStaminaCost {
+		return errors.New("not enough stamina")
+	}
 
-	if input == nil || transform == nil || phys == nil {
-		return
+	dx, dy := 0.0, 0.0
+	if input.Up {
+		dy = -1
+	}
+	if input.Down {
+		dy = 1
+	}
+	if input.Left {
+		dx = -1
 	}
+	if input.Right {
+		dx = 1
+	}
+	if dx == 0 && dy == 0 {
+		dx, dy = math.Cos(transform.Rotation), math.Sin(transform.Rotation)
+	} else {
+		dx, dy = geom.Direction(0, 0, dx, dy)
+	}
+	s.dodges[id] = &dodgeRoll{dx: dx, dy: dy, left: config.DodgeDuration}
+
+	stance.Stamina -= config.DodgeStaminaCost
+	stance.Stance = components.StanceDodge
+	ecs.AddComponent(s.World, id, *stance)
+	stats.InvulnTimer = math.Max(stats.InvulnTimer, config.DodgeDuration)
+	ecs.AddComponent(s.World, id, *stats)
+	return nil
+}
 
+// IsDodging reports whether an entity is mid dodge roll
+func (s *MovementSystem) IsDodging(id ecs.Entity) bool {
+	return s.dodges[id] != nil
+}
+
+// UpdateEntityMovement moves an entity for a tick as its input steers it. transform is
+// a copy, written back once moved.
+func (s *MovementSystem) UpdateEntityMovement(id ecs.Entity, input *components.InputComponent, transform *components.TransformComponent, phys *components.PhysicsComponent, dt float64) {
 	dx, dy := 0.0, 0.0
 	if input.Up {
 		dy = -1
@@ -59,30 +145,49 @@ func (s *MovementSystem) UpdateEntityMovement(id ecs.Entity, dt float64) {
 		dy *= 0.7071
 	}
 
-	speed := phys.Speed
-	if input.IsRunning {
-		speed *= 2.0
+	// A dodge roll overrides steering
+	if roll := s.dodges[id]; roll != nil {
+		dx, dy = roll.dx, roll.dy
+	}
+
+	// Spectators fly through everything, at the same speed over any terrain
+	spectating := IsSpectating(s.World, id)
+
+	stanceScale, tired := s.updateStance(id, input, dx != 0 || dy != 0, dt)
+	speed := phys.Speed * stanceScale * BuffMultiplier(s.World, id, components.BuffSpeed)
+	if config.TerrainSpeed && !spectating {
+		speed /= s.terrainCost(transform)
 	}
 
 	moveX := dx * speed
 	moveY := dy * speed
 
-	// Collision box (centered in TileSize sprite)
-	boxSize := 24.0 // Adjusted for 64x64 (was 14 for 32x32)
-	offset := (float64(config.TileSize) - boxSize) / 2.0
-
+	// Collider (centered in TileSize sprite)
+	tileSize := float64(config.TileSize)
 	z := transform.Z
 
+	reason := ""
+	if tired {
+		reason = protocol.CorrectionSpeed
+	}
+	if !spectating && s.pushOutOfWalls(phys, transform) {
+		reason = protocol.CorrectionPushed
+	}
+
 	// Try move X
-	if !s.collidesAt(z, transform.X+moveX+offset, transform.Y+offset, boxSize, boxSize) &&
-		!s.collidesWithEntities(id, z, transform.X+moveX+offset, transform.Y+offset, boxSize, boxSize) {
+	bx, by, size := components.ColliderBounds(transform.X+moveX, transform.Y, phys, tileSize)
+	if spectating || !s.blockedAt(id, phys, z, bx, by, size) {
 		transform.X += moveX
+	} else if moveX != 0 {
+		reason = protocol.CorrectionBlocked
 	}
 
 	// Try move Y
-	if !s.collidesAt(z, transform.X+offset, transform.Y+moveY+offset, boxSize, boxSize) &&
-		!s.collidesWithEntities(id, z, transform.X+offset, transform.Y+moveY+offset, boxSize, boxSize) {
+	bx, by, size = components.ColliderBounds(transform.X, transform.Y+moveY, phys, tileSize)
+	if spectating || !s.blockedAt(id, phys, z, bx, by, size) {
 		transform.Y += moveY
+	} else if moveY != 0 {
+		reason = protocol.CorrectionBlocked
 	}
 
 	// Update Rotation
@@ -103,34 +208,102 @@ func (s *MovementSystem) UpdateEntityMovement(id ecs.Entity, dt float64) {
 		transform.Rotation = math.Atan2(input.MouseY-transform.Y, input.MouseX-transform.X)
 	}
 
-	s.World.AddComponent(id, *transform)
+	ecs.AddComponent(s.World, id, *transform)
+	s.Grid.Move(id, z, transform.X, transform.Y, phys.Size)
+	if reason != "" {
+		s.correct(id, transform, reason)
+	}
+}
+
+// correct reports a rejected move of a player to their client, unless one went out recently
+func (s *MovementSystem) correct(id ecs.Entity, transform *components.TransformComponent, reason string) {
+	if s.OnCorrect == nil || s.corrected[id] > 0 || !ecs.HasTag(s.World, id, components.TagPlayer) {
+		return
+	}
+	s.corrected[id] = config.MoveCorrectionInterval
+	s.OnCorrect(id, protocol.MoveCorrectionPacket{X: transform.X, Y: transform.Y, Z: transform.Z, Reason: reason})
+}
+
+// updateStance settles the stance an entity moves in this tick, spending or recovering
+// stamina, and returns its speed multiplier and whether it asked to run but is too tired
+// to. Entities without a StanceComponent always walk.
+func (s *MovementSystem) updateStance(id ecs.Entity, input *components.InputComponent, moving bool, dt float64) (float64, bool) {
+	stance, _ := ecs.GetComponent[components.StanceComponent](s.World, id)
+	if stance == nil {
+		return 1, false
+	}
+	prev := *stance
+
+	if s.dodges[id] != nil {
+		stance.Stance = components.StanceDodge
+		if *stance != prev {
+			ecs.AddComponent(s.World, id, *stance)
+		}
+		return config.DodgeSpeedMultiplier, false
+	}
+
+	stance.Stance = input.Stance
+	switch input.Stance {
+	case components.StanceSneak:
+	case components.StanceRun:
+		// Keep running until out of stamina, but catch some breath before starting again
+		if stance.Stamina <= 0 || (prev.Stance != components.StanceRun && stance.Stamina < config.StaminaToRun) {
+			stance.Stance = components.StanceWalk
+		}
+	default:
+		stance.Stance = components.StanceWalk
+	}
+
+	if stance.Stance == components.StanceRun && moving {
+		stance.Stamina = math.Max(0, stance.Stamina-config.StaminaDrain*dt)
+	} else {
+		stance.Stamina = math.Min(config.MaxStamina, stance.Stamina+config.StaminaRegen*dt)
+	}
+	// Unchanged stances aren't written back, so idle players don't look changed to snapshots
+	if *stance != prev {
+		ecs.AddComponent(s.World, id, *stance)
+	}
+
+	tired := input.Stance == components.StanceRun && stance.Stance != components.StanceRun && moving
+	switch stance.Stance {
+	case components.StanceRun:
+		return config.RunSpeedMultiplier, false
+	case components.StanceSneak:
+		return config.SneakSpeedMultiplier, false
+	}
+	return 1, tired
+}
+
+// blockedAt checks a body's collider against walls and other bodies, honoring its layer mask
+func (s *MovementSystem) blockedAt(selfID ecs.Entity, phys *components.PhysicsComponent, z int, x, y, size float64) bool {
+	// Tiles use the bounding box for every shape
+	if phys.Mask&components.LayerWall != 0 && s.collidesAt(z, x, y, size, size) {
+		return true
+	}
+	return s.collidesWithEntities(selfID, phys, z, x, y, size)
 }
 
-func (s *MovementSystem) collidesWithEntities(selfID ecs.Entity, z int, x, y, w, h float64) bool {
-	others := ecs.Query[components.PhysicsComponent](s.World)
-	for _, otherID := range others {
+func (s *MovementSystem) collidesWithEntities(selfID ecs.Entity, phys *components.PhysicsComponent, z int, x, y, size float64) bool {
+	tileSize := float64(config.TileSize)
+	for _, otherID := range Nearby[components.PhysicsComponent](s.World, s.Grid, z, x, y, size, size) {
 		if otherID == selfID {
 			continue
 		}
 
-		proj, _ := ecs.GetComponent[components.ProjectileComponent](s.World, otherID)
-		if proj != nil {
-			continue // Don't collide with projectiles physically
+		otherPhys, _ := ecs.GetComponent[components.PhysicsComponent](s.World, otherID)
+		if otherPhys == nil || phys.Mask&otherPhys.Layer == 0 || IsSpectating(s.World, otherID) || ecs.HasComponent[components.DeadComponent](s.World, otherID) {
+			continue
 		}
 
 		otherTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, otherID)
 
 		// Check Z Match
-		if otherTrans.Z != z {
+		if otherTrans == nil || otherTrans.Z != z {
 			continue
 		}
 
-		boxSize := 24.0
-		offset := (float64(config.TileSize) - boxSize) / 2.0
-		otherX := otherTrans.X + offset
-		otherY := otherTrans.Y + offset
-
-		if s.rectOverlap(x, y, w, h, otherX, otherY, boxSize, boxSize) {
+		ox, oy, oSize := components.ColliderBounds(otherTrans.X, otherTrans.Y, otherPhys, tileSize)
+		if components.CollidersOverlap(x, y, size, phys.Shape, ox, oy, oSize, otherPhys.Shape) {
 			return true
 		}
 	}
@@ -142,92 +315,59 @@ func (s *MovementSystem) collidesAt(z int, x, y, w, h float64) bool {
 	if !ok {
 		return true // No map at this Z = Solid Void? Or empty? Better block.
 	}
+	return gameMap.Collides(x, y, w, h, float64(config.TileSize))
+}
 
-	tileSize := float64(config.TileSize)
-	// Check all tiles the box might overlap
-	startTX := int(math.Floor(x / tileSize))
-	startTY := int(math.Floor(y / tileSize))
-	endTX := int(math.Floor((x + w) / tileSize))
-	endTY := int(math.Floor((y + h) / tileSize))
-
-	for ty := startTY; ty <= endTY; ty++ {
-		for tx := startTX; tx <= endTX; tx++ {
-			if tx < 0 || tx >= gameMap.Width || ty < 0 || ty >= gameMap.Height {
-				return true // Out of bounds is a collision
-			}
-
-			tile := gameMap.Tiles[ty][tx]
-			if s.isTileSolid(tile, tx, ty, x, y, w, h) {
-				return true
-			}
-
-			// Check Objects Layer (Trees)
-			objID := gameMap.Objects[ty][tx]
-			if objID > 0 { // Any object > 0 is solid for now (Trees mostly)
-				// Treat as Tree
-				// Assuming all objects are trees for now or centered obstructions
-				treeSize := tileSize / 2.0 // Scale tree roughly
-				offset := (tileSize - treeSize) / 2.0
-				obsX := float64(tx)*tileSize + offset
-				obsY := float64(ty)*tileSize + offset
-				if s.rectOverlap(x, y, w, h, obsX, obsY, treeSize, treeSize) {
-					return true
-				}
-			}
-		}
+// terrainCost is the move cost of the tile under an entity's body center
+func (s *MovementSystem) terrainCost(transform *components.TransformComponent) float64 {
+	gameMap, ok := s.Maps[transform.Z]
+	if !ok {
+		return 1
 	}
-
-	return false
+	tileSize := float64(config.TileSize)
+	return gameMap.MoveCostAt(int(math.Floor(transform.X/tileSize+0.5)), int(math.Floor(transform.Y/tileSize+0.5)))
 }
 
-func (s *MovementSystem) isTileSolid(tile world.Tile, tx, ty int, x, y, w, h float64) bool {
+// pushOutOfWalls moves an entity whose collider overlaps solid terrain (spawned, teleported
+// or built over) back onto the nearest open ground, so it can't stay wedged in water edges.
+// Reports whether it moved.
+func (s *MovementSystem) pushOutOfWalls(phys *components.PhysicsComponent, transform *components.TransformComponent) bool {
+	gameMap, ok := s.Maps[transform.Z]
+	if !ok || phys.Mask&components.LayerWall == 0 {
+		return false
+	}
 	tileSize := float64(config.TileSize)
-	tileX := float64(tx) * tileSize
-	tileY := float64(ty) * tileSize
-
-	localX := x - tileX
-	localY := y - tileY
-
-	// General Solid Check
-	if tile.Type.IsSolid() {
-		// Special handling for partial solids (Edges/Corners)
-		// For now, let's simplify: if it claims to be solid, treat full tile as solid
-		// UNLESS we want to keep the sub-tile precision for edges.
-		// NOTE: Hardcoded 16 offset logic for water edges needs scaling too if we want it perfect.
-		// For 64x64, 16 -> 32? Or keep 16px edge?
-		// Let's assume we want substantial edge, say 1/4 or 1/2.
-		// 16 was half of 32. So let's use tileSize / 2.
-		halfTile := tileSize / 2.0
-
-		switch tile.Type {
-		case world.TileWaterEdgeTop:
-			return localY+h > halfTile
-		case world.TileWaterEdgeBottom:
-			return localY < halfTile
-		case world.TileWaterEdgeLeft:
-			return localX+w > halfTile
-		case world.TileWaterEdgeRight:
-			return localX < halfTile
-		case world.TileWaterCornerTL:
-			return localX+w > halfTile && localY+h > halfTile
-		case world.TileWaterCornerTR:
-			return localX < halfTile && localY+h > halfTile
-		case world.TileWaterCornerBL:
-			return localX+w > halfTile && localY < halfTile
-		case world.TileWaterCornerBR:
-			return localX < halfTile && localY < halfTile
-		case world.TileTree:
-			treeSize := tileSize / 2.0
-			treeOffset := (tileSize - treeSize) / 2.0
-			return s.rectOverlap(localX, localY, w, h, treeOffset, treeOffset, treeSize, treeSize)
-		default:
-			return true // Full solid (Deep Water, Lava, etc)
-		}
+	bx, by, size := components.ColliderBounds(transform.X, transform.Y, phys, tileSize)
+	dx, dy, ok := gameMap.Depenetrate(bx, by, size, size, tileSize, config.ShorePushRange)
+	if ok {
+		transform.X += dx
+		transform.Y += dy
 	}
-
-	return false
+	return ok
 }
 
-func (s *MovementSystem) rectOverlap(x1, y1, w1, h1, x2, y2, w2, h2 float64) bool {
-	return x1 < x2+w2 && x1+w1 > x2 && y1 < y2+h2 && y1+h1 > y2
+// RelocateStuck moves every entity on a level whose collider overlaps solid terrain to the
+// nearest open ground, however far (after the map changed under it). Returns how many moved.
+func (s *MovementSystem) RelocateStuck(z int) int {
+	gameMap, ok := s.Maps[z]
+	if !ok {
+		return 0
+	}
+	tileSize := float64(config.TileSize)
+	maxDist := float64(gameMap.Width+gameMap.Height) * tileSize
+	moved := 0
+	ecs.Each2(s.World, func(id ecs.Entity, phys *components.PhysicsComponent, transform *components.TransformComponent) {
+		if transform.Z != z || phys.Mask&components.LayerWall == 0 {
+			return
+		}
+		bx, by, size := components.ColliderBounds(transform.X, transform.Y, phys, tileSize)
+		if dx, dy, ok := gameMap.Depenetrate(bx, by, size, size, tileSize, maxDist); ok {
+			transform.X += dx
+			transform.Y += dy
+			ecs.AddComponent(s.World, id, *transform)
+			s.Grid.Move(id, z, transform.X, transform.Y, phys.Size)
+			moved++
+		}
+	}, ecs.Without[components.SpectatorComponent]())
+	return moved
 }
@@ -3,44 +3,212 @@ package systems
 import (
 	"henry/pkg/shared/components"
 	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
 	protocol "henry/pkg/shared/network"
+	"henry/pkg/shared/world"
+)
+
+// Area of interest tiers (distance in px from the viewing player)
+const (
+	NetNearDist    = 640.0  // Full rate
+	NetMidDist     = 1100.0 // Every NetMidInterval broadcasts
+	NetAOIRadius   = 1600.0 // Every NetFarInterval broadcasts, nothing beyond
+	NetMidInterval = 3
+	NetFarInterval = 5
 )
 
 type NetworkSystem struct {
 	World *ecs.World
+	Clock *world.Clock
+
+	// Broadcast slot stamped on the snapshots being prepared (set by the game loop)
+	Seq uint32
+
+	// Broadcast counter per viewing player
+	playerTicks map[ecs.Entity]int
+	// World tick at which each entity was last sent, per viewing player
+	playerSent map[ecs.Entity]map[ecs.Entity]uint64
+	// Static data each viewing player was last sent (EntitySpawnPacket), per entity. The
+	// entities a player has been introduced to, and not yet told are gone.
+	playerSpawned map[ecs.Entity]map[ecs.Entity]protocol.EntitySpawnPacket
 }
 
-func NewNetworkSystem(world *ecs.World) *NetworkSystem {
+func NewNetworkSystem(world *ecs.World, clock *world.Clock) *NetworkSystem {
 	return &NetworkSystem{
-		World: world,
+		World:       world,
+		Clock:       clock,
+		playerTicks: make(map[ecs.Entity]int),
+		playerSent:  make(map[ecs.Entity]map[ecs.Entity]uint64),
+
+		playerSpawned: make(map[ecs.Entity]map[ecs.Entity]protocol.EntitySpawnPacket),
 	}
 }
 
-func (s *NetworkSystem) PrepareStateUpdate() protocol.Packet {
-	snapshot := protocol.StateUpdatePacket{
-		Entities: make([]protocol.EntitySnapshot, 0),
-	}
+// PrepareUpdatesFor builds what one player is sent this broadcast: an EntitySpawnPacket
+// for each entity new to them (or whose static data changed), an EntityDespawnPacket for
+// each they no longer see, then their snapshot. Entities that haven't changed since they
+// were last sent are only listed in the snapshot's Retained. Distant entities are
+// refreshed every few broadcasts and entities outside the AOI are left out.
+func (s *NetworkSystem) PrepareUpdatesFor(playerID ecs.Entity) []protocol.Packet {
+	viewer, _ := ecs.GetComponent[components.TransformComponent](s.World, playerID)
+
+	tick := s.playerTicks[playerID]
+	s.playerTicks[playerID] = tick + 1
+
+	sent := s.playerSent[playerID]
+	nextSent := make(map[ecs.Entity]uint64, len(sent))
+	spawned := s.playerSpawned[playerID]
+	nextSpawned := make(map[ecs.Entity]protocol.EntitySpawnPacket, len(spawned))
+	worldTick := s.World.Tick()
+
+	var packets []protocol.Packet
+	snapshot := s.newSnapshot()
 
 	entities := ecs.Query[components.TransformComponent](s.World)
 	for _, id := range entities {
 		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
-		sprite, _ := ecs.GetComponent[components.SpriteComponent](s.World, id)
-		stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id)
-		physics, _ := ecs.GetComponent[components.PhysicsComponent](s.World, id)
-
-		if sprite != nil {
-			snapshot.Entities = append(snapshot.Entities, protocol.EntitySnapshot{
-				ID:        id,
-				Transform: trans,
-				Physics:   physics,
-				Sprite:    sprite,
-				Stats:     stats,
-			})
+		if trans == nil || !ecs.HasComponent[components.SpriteComponent](s.World, id) {
+			continue
+		}
+		if id != playerID && (IsSpectating(s.World, id) || IsInvisible(s.World, id)) {
+			continue // Spectators and invisible entities are only seen by themselves
 		}
+
+		interval := 1
+		if viewer != nil { // Not in the world yet: sees everything
+			if trans.Z != viewer.Z {
+				continue
+			}
+			dist := geom.Dist(viewer.X, viewer.Y, trans.X, trans.Y)
+			switch {
+			case id == playerID || dist <= NetNearDist:
+			case dist <= NetMidDist:
+				interval = NetMidInterval
+			case dist <= NetAOIRadius:
+				interval = NetFarInterval
+			default:
+				continue
+			}
+		}
+
+		// Stagger by entity ID so distant updates are spread across broadcasts.
+		// Entities the client doesn't have yet are always sent.
+		if sentTick, known := sent[id]; known {
+			skip := interval > 1 && (tick+int(id))%interval != 0
+			if skip || !s.World.EntityChangedSince(id, sentTick) {
+				snapshot.Retained = append(snapshot.Retained, id)
+				nextSent[id] = sentTick
+				nextSpawned[id] = spawned[id]
+				continue
+			}
+		}
+
+		spawn := s.spawnOf(id)
+		if last, known := spawned[id]; !known || !sameSpawn(last, spawn) {
+			packets = append(packets, protocol.Packet{Type: protocol.PacketEntitySpawn, Data: spawn})
+		}
+		nextSpawned[id] = spawn
+		snapshot.Entities = append(snapshot.Entities, s.snapshotEntity(id))
+		nextSent[id] = worldTick
 	}
+	for id := range spawned {
+		if _, seen := nextSpawned[id]; !seen {
+			packets = append(packets, protocol.Packet{Type: protocol.PacketEntityDespawn, Data: protocol.EntityDespawnPacket{EntityID: id}})
+		}
+	}
+	s.playerSent[playerID] = nextSent
+	s.playerSpawned[playerID] = nextSpawned
 
-	return protocol.Packet{
+	return append(packets, protocol.Packet{
 		Type: protocol.PacketStateUpdate,
 		Data: snapshot,
+	})
+}
+
+// Despawned forgets an entity that left the world and returns the players who had been
+// introduced to it, to be sent an EntityDespawnPacket now rather than at their next
+// broadcast
+func (s *NetworkSystem) Despawned(id ecs.Entity) []ecs.Entity {
+	var viewers []ecs.Entity
+	for playerID, spawned := range s.playerSpawned {
+		if _, ok := spawned[id]; ok {
+			delete(spawned, id)
+			delete(s.playerSent[playerID], id)
+			viewers = append(viewers, playerID)
+		}
+	}
+	return viewers
+}
+
+// ForgetPlayer drops per-player tracking (on disconnect)
+func (s *NetworkSystem) ForgetPlayer(playerID ecs.Entity) {
+	delete(s.playerTicks, playerID)
+	delete(s.playerSent, playerID)
+	delete(s.playerSpawned, playerID)
+}
+
+func (s *NetworkSystem) newSnapshot() protocol.StateUpdatePacket {
+	snapshot := protocol.StateUpdatePacket{
+		Seq:      s.Seq,
+		Entities: make([]protocol.EntitySnapshot, 0),
+	}
+	if s.Clock != nil {
+		snapshot.WorldHour = s.Clock.Hour
+	}
+	return snapshot
+}
+
+// spawnOf gathers an entity's static data
+func (s *NetworkSystem) spawnOf(id ecs.Entity) protocol.EntitySpawnPacket {
+	spawn := protocol.EntitySpawnPacket{ID: id}
+	if sprite, ok := ecs.GetComponent[components.SpriteComponent](s.World, id); ok {
+		spawn.Sprite = *sprite
+	}
+	if name, ok := ecs.GetComponent[components.NameComponent](s.World, id); ok {
+		spawn.Name = name.Name
+	}
+	if stats, ok := ecs.GetComponent[components.StatsComponent](s.World, id); ok {
+		spawn.MaxHealth = stats.MaxHealth
+	}
+	spawn.Marker, _ = ecs.GetComponent[components.MarkerComponent](s.World, id)
+	spawn.Waypoint, _ = ecs.GetComponent[components.WaypointComponent](s.World, id)
+	spawn.Structure, _ = ecs.GetComponent[components.StructureComponent](s.World, id)
+	return spawn
+}
+
+func sameSpawn(a, b protocol.EntitySpawnPacket) bool {
+	return a.ID == b.ID && a.Name == b.Name && a.Sprite == b.Sprite && a.MaxHealth == b.MaxHealth &&
+		samePtr(a.Marker, b.Marker) && samePtr(a.Waypoint, b.Waypoint) && samePtr(a.Structure, b.Structure)
+}
+
+// samePtr compares two optional components by value
+func samePtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// snapshotEntity gathers an entity's dynamic data
+func (s *NetworkSystem) snapshotEntity(id ecs.Entity) protocol.EntitySnapshot {
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+	stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id)
+	physics, _ := ecs.GetComponent[components.PhysicsComponent](s.World, id)
+	item, _ := ecs.GetComponent[components.GroundItemComponent](s.World, id)
+	stance, _ := ecs.GetComponent[components.StanceComponent](s.World, id)
+	status, _ := ecs.GetComponent[components.StatusEffectComponent](s.World, id)
+	if stats != nil {
+		stats.MaxHealth = 0 // Sent with the EntitySpawnPacket
+	}
+
+	return protocol.EntitySnapshot{
+		ID:         id,
+		Transform:  trans,
+		Physics:    physics,
+		Stats:      stats,
+		Item:       item,
+		Stance:     stance,
+		Appearance: Appearance(s.World, id),
+		Status:     status,
 	}
 }
@@ -0,0 +1,75 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/shared/world"
+)
+
+// updateKinds sorts one player's updates into spawns, despawns and their snapshot
+func updateKinds(t *testing.T, updates []protocol.Packet) (spawns map[ecs.Entity]protocol.EntitySpawnPacket, despawns []ecs.Entity, state protocol.StateUpdatePacket) {
+	t.Helper()
+	spawns = make(map[ecs.Entity]protocol.EntitySpawnPacket)
+	for _, p := range updates[:len(updates)-1] {
+		switch data := p.Data.(type) {
+		case protocol.EntitySpawnPacket:
+			spawns[data.ID] = data
+		case protocol.EntityDespawnPacket:
+			despawns = append(despawns, data.EntityID)
+		default:
+			t.Fatalf("unexpected %T before the snapshot", p.Data)
+		}
+	}
+	return spawns, despawns, updates[len(updates)-1].Data.(protocol.StateUpdatePacket)
+}
+
+func TestEntitiesSpawnOnceAndDespawnOutOfView(t *testing.T) {
+	w := ecs.NewWorld()
+	net := NewNetworkSystem(w, world.NewClock(12, 1200))
+	viewer, wolf := w.NewEntity(), w.NewEntity()
+	for _, id := range []ecs.Entity{viewer, wolf} {
+		ecs.AddComponent(w, id, components.TransformComponent{X: 100, Y: 100})
+		ecs.AddComponent(w, id, components.SpriteComponent{Width: 32, Height: 32, CharType: "wolf"})
+		ecs.AddComponent(w, id, components.StatsComponent{MaxHealth: 40, CurrentHealth: 40})
+	}
+	ecs.AddComponent(w, wolf, components.NameComponent{Name: "Wolf"})
+
+	spawns, _, state := updateKinds(t, net.PrepareUpdatesFor(viewer))
+	if spawn, ok := spawns[wolf]; !ok || spawn.Name != "Wolf" || spawn.MaxHealth != 40 || spawn.Sprite.CharType != "wolf" {
+		t.Fatalf("wolf introduced as %+v", spawn)
+	}
+	for _, e := range state.Entities {
+		if e.Sprite != nil || e.Stats.MaxHealth != 0 {
+			t.Errorf("static data in entity %d's snapshot", e.ID)
+		}
+	}
+
+	// Moving isn't news, a tougher wolf is
+	ecs.AddComponent(w, wolf, components.TransformComponent{X: 120, Y: 100})
+	if spawns, _, _ := updateKinds(t, net.PrepareUpdatesFor(viewer)); len(spawns) != 0 {
+		t.Errorf("%d entities introduced again without changing", len(spawns))
+	}
+	ecs.AddComponent(w, wolf, components.StatsComponent{MaxHealth: 60, CurrentHealth: 60})
+	if spawns, _, _ := updateKinds(t, net.PrepareUpdatesFor(viewer)); spawns[wolf].MaxHealth != 60 {
+		t.Error("wolf's new max health not sent")
+	}
+
+	ecs.AddComponent(w, wolf, components.TransformComponent{X: 100 + NetAOIRadius*2, Y: 100})
+	if _, despawns, _ := updateKinds(t, net.PrepareUpdatesFor(viewer)); len(despawns) != 1 || despawns[0] != wolf {
+		t.Errorf("despawns %v after the wolf left view, want [%d]", despawns, wolf)
+	}
+
+	// Removed from the world: the viewers who knew it are told at once
+	ecs.AddComponent(w, wolf, components.TransformComponent{X: 100, Y: 100})
+	net.PrepareUpdatesFor(viewer)
+	w.RemoveEntity(wolf)
+	if viewers := net.Despawned(wolf); len(viewers) != 1 || viewers[0] != viewer {
+		t.Errorf("viewers %v of the removed wolf, want [%d]", viewers, viewer)
+	}
+	if _, despawns, _ := updateKinds(t, net.PrepareUpdatesFor(viewer)); len(despawns) != 0 {
+		t.Errorf("removed wolf despawned twice")
+	}
+}
@@ -0,0 +1,129 @@
+package systems
+
+import (
+	"fmt"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/storage"
+	"log"
+	"time"
+)
+
+// NPCStateSystem periodically checkpoints spawner NPCs (position, health, respawn timers)
+// so a quick restart resumes the world instead of fresh-spawning everything.
+// Only NPCs with a RespawnComponent are tracked; world event spawns are transient.
+type NPCStateSystem struct {
+	World *ecs.World
+
+	timer float64
+}
+
+func NewNPCStateSystem(world *ecs.World) *NPCStateSystem {
+	return &NPCStateSystem{
+		World: world,
+	}
+}
+
+func (s *NPCStateSystem) Update(dt float64) {
+	s.timer += dt
+	if s.timer < config.NPCCheckpointInterval {
+		return
+	}
+	s.timer = 0
+	if err := s.Checkpoint(); err != nil {
+		log.Printf("NPC checkpoint failed: %v", err)
+	}
+}
+
+// Checkpoint writes the current state of all spawner NPCs to disk
+func (s *NPCStateSystem) Checkpoint() error {
+	data := storage.WorldSaveData{SavedAt: time.Now().Unix()}
+
+	for _, id := range ecs.Query[components.RespawnComponent](s.World) {
+		respawn, _ := ecs.GetComponent[components.RespawnComponent](s.World, id)
+		if respawn == nil {
+			continue
+		}
+
+		npc := storage.NPCSave{
+			CharID:       respawn.CharID,
+			SpawnX:       respawn.SpawnX,
+			SpawnY:       respawn.SpawnY,
+			IsDead:       respawn.IsDead,
+			RespawnTimer: respawn.RespawnTimer,
+		}
+		if ecs.HasComponent[components.DeadComponent](s.World, id) {
+			// A corpse comes back as waiting out its whole respawn
+			npc.IsDead, npc.RespawnTimer = true, respawn.RespawnDelay
+		} else if !respawn.IsDead {
+			trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+			stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id)
+			if trans == nil || stats == nil {
+				continue
+			}
+			npc.X, npc.Y, npc.Z = trans.X, trans.Y, trans.Z
+			npc.Health = stats.CurrentHealth
+		}
+		data.NPCs = append(data.NPCs, npc)
+	}
+
+	return storage.SaveWorldState(data)
+}
+
+// Restore applies the last checkpoint to freshly spawned NPCs, matching them by spawner.
+// Checkpoints older than config.NPCStateMaxAge are ignored. Returns the number of NPCs restored.
+func (s *NPCStateSystem) Restore() (int, error) {
+	data, err := storage.LoadWorldState()
+	if err != nil || data == nil {
+		return 0, err
+	}
+
+	age := time.Since(time.Unix(data.SavedAt, 0)).Seconds()
+	if age > config.NPCStateMaxAge {
+		log.Printf("NPC checkpoint is %.0fs old, spawning fresh", age)
+		return 0, nil
+	}
+
+	saved := make(map[string][]storage.NPCSave)
+	for _, npc := range data.NPCs {
+		key := spawnerKey(npc.CharID, npc.SpawnX, npc.SpawnY)
+		saved[key] = append(saved[key], npc)
+	}
+
+	restored := 0
+	for _, id := range ecs.Query[components.RespawnComponent](s.World) {
+		respawn, _ := ecs.GetComponent[components.RespawnComponent](s.World, id)
+		if respawn == nil {
+			continue
+		}
+		key := spawnerKey(respawn.CharID, respawn.SpawnX, respawn.SpawnY)
+		queue := saved[key]
+		if len(queue) == 0 {
+			continue // Spawner added since the checkpoint
+		}
+		npc := queue[0]
+		saved[key] = queue[1:]
+
+		if npc.IsDead {
+			DespawnForRespawn(s.World, id, npc.RespawnTimer)
+			restored++
+			continue
+		}
+
+		if trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id); trans != nil {
+			trans.X, trans.Y, trans.Z = npc.X, npc.Y, npc.Z
+			ecs.AddComponent(s.World, id, *trans)
+		}
+		if stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id); stats != nil {
+			stats.CurrentHealth = min(npc.Health, stats.MaxHealth)
+			ecs.AddComponent(s.World, id, *stats)
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+func spawnerKey(charID string, x, y float64) string {
+	return fmt.Sprintf("%s@%.0f,%.0f", charID, x, y)
+}
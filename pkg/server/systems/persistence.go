@@ -5,16 +5,36 @@ import (
 	"henry/pkg/shared/ecs"
 	"henry/pkg/storage"
 	"log"
+	"time"
 )
 
 type PersistenceSystem struct {
 	World *ecs.World
+
+	// World tick of the last SavePlayerIfChanged per player
+	lastSaved map[ecs.Entity]uint64
 }
 
 func NewPersistenceSystem(world *ecs.World) *PersistenceSystem {
 	return &PersistenceSystem{
-		World: world,
+		World:     world,
+		lastSaved: make(map[ecs.Entity]uint64),
+	}
+}
+
+// SavePlayerIfChanged saves only if the player's components changed since the last call.
+// Not safe for concurrent use, call with the server lock held.
+func (s *PersistenceSystem) SavePlayerIfChanged(id ecs.Entity, username string) (bool, error) {
+	if last, ok := s.lastSaved[id]; ok && !s.World.EntityChangedSince(id, last) {
+		return false, nil
 	}
+	s.lastSaved[id] = s.World.Tick()
+	return true, s.SavePlayer(id, username)
+}
+
+// ForgetPlayer drops change tracking for a disconnected player
+func (s *PersistenceSystem) ForgetPlayer(id ecs.Entity) {
+	delete(s.lastSaved, id)
 }
 
 func (s *PersistenceSystem) SavePlayer(id ecs.Entity, username string) error {
@@ -32,14 +52,20 @@ func (s *PersistenceSystem) SavePlayer(id ecs.Entity, username string) error {
 	}
 
 	data := storage.PlayerSaveData{
-		Username:    username,
-		Password:    existing.Password,
-		X:           trans.X,
-		Y:           trans.Y,
-		Health:      stats.CurrentHealth,
-		Keybindings: existing.Keybindings,
-		OpenMenus:   existing.OpenMenus,
-		IsRunning:   existing.IsRunning,
+		Username:      username,
+		PasswordHash:  existing.PasswordHash,
+		Password:      existing.Password,
+		IsAdmin:       existing.IsAdmin,
+		Banned:        existing.Banned,
+		BanReason:     existing.BanReason,
+		X:             trans.X,
+		Y:             trans.Y,
+		Health:        stats.CurrentHealth,
+		Keybindings:   existing.Keybindings,
+		OpenMenus:     existing.OpenMenus,
+		Stance:        existing.Stance,
+		DebugSettings: existing.DebugSettings,
+		LastSeen:      time.Now().Unix(),
 	}
 
 	// Update Keybindings from world component if present
@@ -48,10 +74,10 @@ func (s *PersistenceSystem) SavePlayer(id ecs.Entity, username string) error {
 		data.Keybindings = kb.Bindings
 	}
 
-	// Update IsRunning from world component if present
+	// Update Stance from world component if present
 	input, _ := ecs.GetComponent[components.InputComponent](s.World, id)
 	if input != nil {
-		data.IsRunning = input.IsRunning
+		data.Stance = input.Stance
 	}
 
 	// Save Inventory
@@ -61,9 +87,11 @@ func (s *PersistenceSystem) SavePlayer(id ecs.Entity, username string) error {
 		for i, slot := range inv.Slots {
 			if slot.ItemID != "" && slot.Quantity > 0 {
 				saveSlots = append(saveSlots, storage.InventorySlotSave{
-					Index:    i,
-					ItemID:   slot.ItemID,
-					Quantity: slot.Quantity,
+					Index:      i,
+					ItemID:     slot.ItemID,
+					Quantity:   slot.Quantity,
+					Locked:     slot.Locked,
+					InstanceID: slot.InstanceID,
 				})
 			}
 		}
@@ -89,7 +117,8 @@ func (s *PersistenceSystem) SavePlayer(id ecs.Entity, username string) error {
 		var saveEquip [9]storage.EquipmentSlotSave
 		for i, slot := range equip.Slots {
 			saveEquip[i] = storage.EquipmentSlotSave{
-				ItemID: slot.ItemID,
+				ItemID:     slot.ItemID,
+				InstanceID: slot.InstanceID,
 			}
 		}
 		data.Equipment = saveEquip
@@ -98,6 +127,15 @@ func (s *PersistenceSystem) SavePlayer(id ecs.Entity, username string) error {
 		log.Printf("PersistenceSystem: No EquipmentComponent found for %s", username)
 	}
 
+	// Save Wardrobe
+	if cosmetics, ok := ecs.GetComponent[components.CosmeticsComponent](s.World, id); ok {
+		data.Cosmetics = cosmetics.Unlocked
+		data.Looks = cosmetics.Looks
+	} else {
+		data.Cosmetics = existing.Cosmetics
+		data.Looks = existing.Looks
+	}
+
 	// Save Spellbook
 	spellbook, _ := ecs.GetComponent[components.SpellbookComponent](s.World, id)
 	if spellbook != nil {
@@ -108,6 +146,65 @@ func (s *PersistenceSystem) SavePlayer(id ecs.Entity, username string) error {
 		}
 	}
 
+	// Save Discovered Waypoints
+	travel, _ := ecs.GetComponent[components.TravelComponent](s.World, id)
+	if travel != nil {
+		data.Waypoints = travel.UnlockedWaypoints
+	} else {
+		data.Waypoints = existing.Waypoints
+	}
+
+	// Save Gold
+	if wallet, ok := ecs.GetComponent[components.WalletComponent](s.World, id); ok {
+		data.Gold = wallet.Gold
+	} else {
+		data.Gold = existing.Gold
+	}
+
+	// Save Keyring
+	if kr, ok := ecs.GetComponent[components.KeyringComponent](s.World, id); ok {
+		for _, slot := range kr.Items {
+			data.Keyring = append(data.Keyring, storage.InventorySlotSave{
+				ItemID:     slot.ItemID,
+				Quantity:   slot.Quantity,
+				InstanceID: slot.InstanceID,
+			})
+		}
+	} else {
+		data.Keyring = existing.Keyring
+	}
+
+	// Save Combat XP
+	if stats, ok := ecs.GetComponent[components.StatsComponent](s.World, id); ok {
+		data.XP = stats.XP
+	} else {
+		data.XP = existing.XP
+	}
+
+	// Save Skill XP
+	skills, _ := ecs.GetComponent[components.SkillsComponent](s.World, id)
+	if skills != nil {
+		data.Skills = skills.XP
+	} else {
+		data.Skills = existing.Skills
+	}
+
+	// Save Playtime
+	playtime, _ := ecs.GetComponent[components.PlaytimeComponent](s.World, id)
+	if playtime != nil {
+		data.Playtime = playtime.Seconds
+	} else {
+		data.Playtime = existing.Playtime
+	}
+
+	// Save Seen Cutscenes
+	cutscenes, _ := ecs.GetComponent[components.CutscenesComponent](s.World, id)
+	if cutscenes != nil {
+		data.Cutscenes = cutscenes.Seen
+	} else {
+		data.Cutscenes = existing.Cutscenes
+	}
+
 	// Save UI State
 	uiState, _ := ecs.GetComponent[components.UIStateComponent](s.World, id)
 	if uiState != nil {
@@ -0,0 +1,79 @@
+package systems
+
+import (
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"sort"
+)
+
+// PlaytimeSystem counts each player's active playtime. A player who sends no input for
+// config.AFKTimeout seconds is AFK: their playtime pauses until they act again.
+type PlaytimeSystem struct {
+	World *ecs.World
+
+	// Called when a player goes AFK or comes back
+	OnAFKChange func(id ecs.Entity, afk bool)
+
+	idle map[ecs.Entity]float64 // Seconds since the player's last input
+}
+
+func NewPlaytimeSystem(world *ecs.World) *PlaytimeSystem {
+	return &PlaytimeSystem{
+		World: world,
+		idle:  make(map[ecs.Entity]float64),
+	}
+}
+
+// Activity marks a player as active, ending AFK
+func (s *PlaytimeSystem) Activity(id ecs.Entity) {
+	wasAFK := s.IsAFK(id)
+	s.idle[id] = 0
+	if wasAFK && s.OnAFKChange != nil {
+		s.OnAFKChange(id, false)
+	}
+}
+
+// IsAFK reports whether a player has been idle for at least config.AFKTimeout
+func (s *PlaytimeSystem) IsAFK(id ecs.Entity) bool {
+	return s.idle[id] >= config.AFKTimeout
+}
+
+// AFKPlayers lists AFK players, longest idle first
+func (s *PlaytimeSystem) AFKPlayers() []ecs.Entity {
+	var afk []ecs.Entity
+	for id := range s.idle {
+		if s.IsAFK(id) {
+			afk = append(afk, id)
+		}
+	}
+	sort.Slice(afk, func(i, j int) bool {
+		if s.idle[afk[i]] != s.idle[afk[j]] {
+			return s.idle[afk[i]] > s.idle[afk[j]]
+		}
+		return afk[i] < afk[j]
+	})
+	return afk
+}
+
+// ForgetPlayer drops idle tracking for a disconnected player
+func (s *PlaytimeSystem) ForgetPlayer(id ecs.Entity) {
+	delete(s.idle, id)
+}
+
+// Update adds dt to the playtime of every active player and ages idle timers
+func (s *PlaytimeSystem) Update(dt float64) {
+	for _, id := range ecs.Query[components.PlaytimeComponent](s.World) {
+		wasAFK := s.IsAFK(id)
+		s.idle[id] += dt
+		if s.IsAFK(id) {
+			if !wasAFK && s.OnAFKChange != nil {
+				s.OnAFKChange(id, true)
+			}
+			continue // AFK players aren't written back, so they don't look changed to autosave
+		}
+		playtime, _ := ecs.GetComponent[components.PlaytimeComponent](s.World, id)
+		playtime.Seconds += dt
+		ecs.AddComponent(s.World, id, *playtime)
+	}
+}
@@ -0,0 +1,60 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+)
+
+func TestPlaytimePausesWhileAFK(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewPlaytimeSystem(w)
+	var changes []bool
+	s.OnAFKChange = func(id ecs.Entity, afk bool) { changes = append(changes, afk) }
+
+	id := w.NewEntity()
+	ecs.AddComponent(w, id, components.PlaytimeComponent{Seconds: 100})
+
+	// Idle past the timeout, then another minute
+	s.Update(config.AFKTimeout - 1)
+	s.Update(1)
+	s.Update(60)
+	if !s.IsAFK(id) {
+		t.Fatal("player should be AFK")
+	}
+	playtime, _ := ecs.GetComponent[components.PlaytimeComponent](w, id)
+	if want := 100 + config.AFKTimeout - 1; playtime.Seconds != want {
+		t.Errorf("playtime = %v, want %v (AFK time excluded)", playtime.Seconds, want)
+	}
+
+	s.Activity(id)
+	s.Update(10)
+	playtime, _ = ecs.GetComponent[components.PlaytimeComponent](w, id)
+	if want := 110 + config.AFKTimeout - 1; playtime.Seconds != want {
+		t.Errorf("playtime = %v, want %v after coming back", playtime.Seconds, want)
+	}
+	if len(changes) != 2 || !changes[0] || changes[1] {
+		t.Errorf("AFK changes = %v, want [true false]", changes)
+	}
+}
+
+func TestAFKPlayersLongestIdleFirst(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewPlaytimeSystem(w)
+	early, late, active := w.NewEntity(), w.NewEntity(), w.NewEntity()
+	for _, id := range []ecs.Entity{early, late, active} {
+		ecs.AddComponent(w, id, components.PlaytimeComponent{})
+	}
+
+	s.Update(60)
+	s.Activity(late)
+	s.Update(config.AFKTimeout)
+	s.Activity(active)
+
+	afk := s.AFKPlayers()
+	if len(afk) != 2 || afk[0] != early || afk[1] != late {
+		t.Errorf("AFK players = %v, want [%d %d]", afk, early, late)
+	}
+}
@@ -0,0 +1,44 @@
+package systems
+
+import (
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+)
+
+// PlayerMaxHealth is a player's max health at a combat level
+func PlayerMaxHealth(level int) float64 {
+	return config.PlayerBaseHealth + config.HealthPerLevel*float64(max(level, 1)-1)
+}
+
+// PlayerAttributes are a player's own STR, DEX and INT at a combat level (gear comes on top)
+func PlayerAttributes(level int) components.Attributes {
+	a := config.PlayerBaseAttribute + (max(level, 1)-1)/config.LevelsPerAttribute
+	return components.Attributes{Str: a, Dex: a, Int: a}
+}
+
+// ApplyLevel sets a player's combat level from their XP and grows their max health and
+// attributes to match. Health a new level adds is healed too. Reports whether the level
+// went up.
+func ApplyLevel(w *ecs.World, id ecs.Entity) bool {
+	stats, ok := ecs.GetComponent[components.StatsComponent](w, id)
+	if !ok {
+		return false
+	}
+	level := components.CombatLevel(stats.XP)
+	leveledUp := level > stats.Level
+	stats.Level = level
+	maxHealth := PlayerMaxHealth(level)
+	if maxHealth > stats.MaxHealth {
+		stats.CurrentHealth += maxHealth - stats.MaxHealth
+	}
+	stats.MaxHealth = maxHealth
+	stats.CurrentHealth = min(stats.CurrentHealth, maxHealth)
+	ecs.AddComponent(w, id, *stats)
+
+	if attrs, ok := ecs.GetComponent[components.AttributesComponent](w, id); ok {
+		attrs.Base = PlayerAttributes(level)
+		ecs.AddComponent(w, id, *attrs)
+	}
+	return leveledUp
+}
@@ -0,0 +1,32 @@
+package systems
+
+import "henry/pkg/shared/config"
+
+// RespawnRange is the default respawn range around a base respawn time
+func RespawnRange(base float64) (fastest, slowest float64) {
+	return base * config.RespawnMinScale, base * config.RespawnMaxScale
+}
+
+// RespawnDelay picks a respawn time in [fastest, slowest] by how many players are
+// hunting the zone: slowest when it's empty, halfway with one player, down to fastest
+// at config.RespawnBusyPlayers
+func RespawnDelay(fastest, slowest float64, players int) float64 {
+	if players <= 0 {
+		return slowest
+	}
+	mid := (fastest + slowest) / 2
+	if config.RespawnBusyPlayers <= 1 {
+		return fastest
+	}
+	busy := float64(min(players, config.RespawnBusyPlayers)-1) / float64(config.RespawnBusyPlayers-1)
+	return mid - (mid-fastest)*busy
+}
+
+// RetimeRespawn carries a running respawn timer over to a new delay, keeping the share
+// already waited: half of a 60s wait left becomes half of the new delay left
+func RetimeRespawn(timer, delay, newDelay float64) float64 {
+	if delay <= 0 {
+		return newDelay
+	}
+	return timer * newDelay / delay
+}
@@ -0,0 +1,45 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+func TestRespawnDelayFollowsHunters(t *testing.T) {
+	w := ecs.NewWorld()
+	m := world.NewMap(32, 32)
+	m.Zones = []world.Zone{{ID: "woods", X: 0, Y: 0, Width: 1024, Height: 1024}}
+	zones := NewZoneSystem(w, map[int]*world.Map{0: m})
+	hunters := func(n int) int {
+		for range n {
+			id := w.NewEntity()
+			ecs.AddComponent(w, id, components.TransformComponent{X: 100, Y: 100})
+			ecs.AddComponent(w, id, components.ZoneComponent{})
+		}
+		zones.Update()
+		return zones.PlayersAt(0, 500, 500)
+	}
+
+	fastest, slowest := RespawnRange(60)
+	if got := RespawnDelay(fastest, slowest, hunters(0)); got != slowest {
+		t.Errorf("empty zone: %gs, want the slowest %gs", got, slowest)
+	}
+	if got := RespawnDelay(fastest, slowest, hunters(1)); got != 60 {
+		t.Errorf("one hunter: %gs, want the base 60s", got)
+	}
+	if zones.PlayersAt(0, 1500, 1500) != 0 {
+		t.Error("the hunter in the woods counted in the wilderness")
+	}
+	if got := RespawnDelay(fastest, slowest, hunters(config.RespawnBusyPlayers+2)); got != fastest {
+		t.Errorf("crowded zone: %gs, want the fastest %gs", got, fastest)
+	}
+
+	// Halfway through a 60s wait when a crowd arrives: halfway through the fast wait
+	if got := RetimeRespawn(30, 60, fastest); got != fastest/2 {
+		t.Errorf("retimed to %gs left, want %gs", got, fastest/2)
+	}
+}
@@ -0,0 +1,153 @@
+package systems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+)
+
+// ScheduleDef is a recurring task loaded from data/schedule.json. Exactly one of Every or
+// Daily says when it runs.
+type ScheduleDef struct {
+	ID    string  `json:"id"`
+	Task  string  `json:"task"`  // Registered task name, e.g. "backup"
+	Args  string  `json:"args"`  // Passed to the task (e.g. a world event ID)
+	Every float64 `json:"every"` // Seconds between runs
+	Daily string  `json:"daily"` // "HH:MM" server local time
+}
+
+// ScheduledJob is a loaded schedule and its run history
+type ScheduledJob struct {
+	Def     ScheduleDef
+	NextRun time.Time
+	LastRun time.Time // Zero until the first run
+	LastErr error
+	Runs    int
+}
+
+// SchedulerSystem runs registered server tasks on wall clock schedules (daily resets,
+// backups, leaderboard snapshots...). Tasks run inside the game loop with the server lock held.
+type SchedulerSystem struct {
+	Now func() time.Time // Wall clock, replaceable in tests
+
+	tasks map[string]func(args string) error
+	jobs  []*ScheduledJob
+}
+
+func NewSchedulerSystem() *SchedulerSystem {
+	return &SchedulerSystem{
+		Now:   time.Now,
+		tasks: make(map[string]func(args string) error),
+	}
+}
+
+// LoadSchedule reads schedule definitions from a JSON file
+func LoadSchedule(path string) ([]ScheduleDef, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var defs []ScheduleDef
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule json: %w", err)
+	}
+	return defs, nil
+}
+
+// Register makes a task available to schedules under a name
+func (s *SchedulerSystem) Register(task string, fn func(args string) error) {
+	s.tasks[task] = fn
+}
+
+// Load replaces the schedule, skipping entries with an unknown task or a bad time.
+// Register tasks first.
+func (s *SchedulerSystem) Load(defs []ScheduleDef) {
+	s.jobs = nil
+	now := s.Now()
+	for _, def := range defs {
+		if _, ok := s.tasks[def.Task]; !ok {
+			log.Printf("Schedule %s: unknown task %q, skipping", def.ID, def.Task)
+			continue
+		}
+		job := &ScheduledJob{Def: def}
+		next, err := nextRun(def, now)
+		if err != nil {
+			log.Printf("Schedule %s: %v, skipping", def.ID, err)
+			continue
+		}
+		job.NextRun = next
+		s.jobs = append(s.jobs, job)
+	}
+	log.Printf("Loaded %d scheduled task(s)", len(s.jobs))
+}
+
+// Update runs every job that is due and schedules its next run
+func (s *SchedulerSystem) Update() {
+	now := s.Now()
+	for _, job := range s.jobs {
+		if now.Before(job.NextRun) {
+			continue
+		}
+		s.run(job, now)
+	}
+}
+
+// RunNow runs a scheduled job immediately (its regular schedule is unchanged)
+func (s *SchedulerSystem) RunNow(id string) error {
+	for _, job := range s.jobs {
+		if job.Def.ID == id {
+			next := job.NextRun
+			s.run(job, s.Now())
+			job.NextRun = next
+			return job.LastErr
+		}
+	}
+	return errors.New("no scheduled task " + id)
+}
+
+// Jobs lists the schedule, soonest first
+func (s *SchedulerSystem) Jobs() []ScheduledJob {
+	jobs := make([]ScheduledJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].NextRun.Before(jobs[j].NextRun) })
+	return jobs
+}
+
+func (s *SchedulerSystem) run(job *ScheduledJob, now time.Time) {
+	job.LastRun = now
+	job.Runs++
+	job.LastErr = s.tasks[job.Def.Task](job.Def.Args)
+	if job.LastErr != nil {
+		log.Printf("Scheduled task %s (%s) failed: %v", job.Def.ID, job.Def.Task, job.LastErr)
+	} else {
+		log.Printf("Scheduled task %s (%s) done", job.Def.ID, job.Def.Task)
+	}
+	// A definition that parsed at load time always parses again
+	job.NextRun, _ = nextRun(job.Def, now)
+}
+
+// nextRun returns the first run time strictly after now
+func nextRun(def ScheduleDef, now time.Time) (time.Time, error) {
+	switch {
+	case def.Every > 0 && def.Daily == "":
+		return now.Add(time.Duration(def.Every * float64(time.Second))), nil
+	case def.Daily != "" && def.Every == 0:
+		at, err := time.Parse("15:04", def.Daily)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("bad daily time %q (want HH:MM)", def.Daily)
+		}
+		next := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next, nil
+	default:
+		return time.Time{}, errors.New("set exactly one of every or daily")
+	}
+}
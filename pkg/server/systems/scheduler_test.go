@@ -0,0 +1,40 @@
+package systems
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedulerRunsDailyAndIntervalJobs(t *testing.T) {
+	now := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	s := NewSchedulerSystem()
+	s.Now = func() time.Time { return now }
+	runs := map[string]int{}
+	s.Register("count", func(args string) error {
+		runs[args]++
+		return nil
+	})
+
+	s.Load([]ScheduleDef{
+		{ID: "midnight", Task: "count", Args: "daily", Daily: "00:00"},
+		{ID: "half_hour", Task: "count", Args: "every", Every: 1800},
+		{ID: "typo", Task: "cuont", Every: 60},
+		{ID: "both", Task: "count", Every: 60, Daily: "01:00"},
+	})
+	if jobs := s.Jobs(); len(jobs) != 2 || jobs[0].Def.ID != "half_hour" {
+		t.Fatalf("jobs = %+v, want half_hour then midnight (bad entries skipped)", jobs)
+	}
+
+	for i := 0; i < 4; i++ { // 23:00 -> 01:00 in 30 minute steps
+		now = now.Add(30 * time.Minute)
+		s.Update()
+	}
+	if runs["every"] != 4 || runs["daily"] != 1 {
+		t.Errorf("runs = %v, want 4 interval and 1 daily", runs)
+	}
+	for _, job := range s.Jobs() {
+		if job.Def.ID == "midnight" && !job.NextRun.Equal(time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("next midnight run = %v", job.NextRun)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package systems
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+// SeasonalFile lists the holiday events (optional)
+const SeasonalFile = "data/events/seasonal.json"
+
+// SeasonCheckInterval is how often (seconds) the calendar is checked for seasons starting
+// or ending
+const SeasonCheckInterval = 60.0
+
+// SeasonDef is a time-boxed event: while it runs, its spawners are populated, its drops
+// are added to kills and its decorations are placed on the map
+type SeasonDef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Dates it runs, inclusive: "MM-DD" repeats every year (and may wrap over New Year),
+	// "YYYY-MM-DD" runs once
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	Announcement    string `json:"announcement"`
+	EndAnnouncement string `json:"end_announcement"`
+
+	Spawners    []SeasonSpawner    `json:"spawners"`
+	Drops       []SeasonDrop       `json:"drops"`
+	Decorations []SeasonDecoration `json:"decorations"`
+}
+
+// SeasonSpawner places an NPC for the season. Vendors can be given their own stock.
+type SeasonSpawner struct {
+	Level       int              `json:"level"`
+	X           float64          `json:"x"`
+	Y           float64          `json:"y"`
+	CharacterID string           `json:"character_id"`
+	Name        string           `json:"name"` // Overrides the character's name
+	Shop        []SeasonShopItem `json:"shop"`
+}
+
+type SeasonShopItem struct {
+	ItemID string `json:"item_id"`
+	Price  int    `json:"price"` // 0 = the economy's buy price
+}
+
+// SeasonDrop is an extra drop for NPCs killed during the season
+type SeasonDrop struct {
+	CharacterID string  `json:"character_id"` // Empty = every spawner NPC
+	ItemID      string  `json:"item_id"`
+	Quantity    int     `json:"quantity"`
+	Chance      float64 `json:"chance"`
+}
+
+// SeasonDecoration puts a map object (see world.Object*) on a tile. Tiles that already
+// hold an object are left alone.
+type SeasonDecoration struct {
+	Level  int `json:"level"`
+	X      int `json:"x"` // Tile
+	Y      int `json:"y"`
+	Object int `json:"object"`
+}
+
+type activeSeason struct {
+	entities []ecs.Entity
+	placed   []SeasonDecoration // Decorations actually placed, to clear at the end
+}
+
+// SeasonalSystem runs the seasons whose dates include today, plus any an admin forced on
+// (and minus any forced off)
+type SeasonalSystem struct {
+	World *ecs.World
+	Maps  map[int]*world.Map
+	Defs  []SeasonDef
+
+	// Hooks provided by the GameServer
+	Spawn          func(level int, x, y float64, charID string) ecs.Entity
+	Announce       func(msg string)
+	OnObjectChange func(level, tx, ty, object int)
+
+	// Now returns the current time (tests replace it)
+	Now func() time.Time
+
+	forced map[string]bool // Season ID -> forced on/off (absent = by date)
+	active map[string]*activeSeason
+	timer  float64
+}
+
+func NewSeasonalSystem(w *ecs.World, maps map[int]*world.Map, defs []SeasonDef) *SeasonalSystem {
+	return &SeasonalSystem{
+		World:  w,
+		Maps:   maps,
+		Defs:   defs,
+		Now:    time.Now,
+		forced: make(map[string]bool),
+		active: make(map[string]*activeSeason),
+		timer:  SeasonCheckInterval, // Check on the first update
+	}
+}
+
+// LoadSeasons reads season definitions from a JSON file and checks their dates
+func LoadSeasons(path string) ([]SeasonDef, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var defs []SeasonDef
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("failed to parse seasons json: %w", err)
+	}
+	for _, def := range defs {
+		if _, _, err := parseSeasonDate(def.Start); err != nil {
+			return nil, fmt.Errorf("season %s start: %w", def.ID, err)
+		}
+		if _, _, err := parseSeasonDate(def.End); err != nil {
+			return nil, fmt.Errorf("season %s end: %w", def.ID, err)
+		}
+	}
+	return defs, nil
+}
+
+// parseSeasonDate parses "MM-DD" (yearly) or "YYYY-MM-DD" (once)
+func parseSeasonDate(s string) (time.Time, bool, error) {
+	if t, err := time.Parse("01-02", s); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%q is not MM-DD or YYYY-MM-DD", s)
+	}
+	return t, false, nil
+}
+
+// InSeason reports whether a season's dates include the day of now
+func (def SeasonDef) InSeason(now time.Time) bool {
+	start, yearly, err1 := parseSeasonDate(def.Start)
+	end, _, err2 := parseSeasonDate(def.End)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	if !yearly {
+		day := now.Format("2006-01-02")
+		return day >= start.Format("2006-01-02") && day <= end.Format("2006-01-02")
+	}
+	day, from, to := now.Format("01-02"), start.Format("01-02"), end.Format("01-02")
+	if from <= to {
+		return day >= from && day <= to
+	}
+	return day >= from || day <= to // Over New Year
+}
+
+func (s *SeasonalSystem) Update(dt float64) {
+	s.timer += dt
+	if s.timer < SeasonCheckInterval {
+		return
+	}
+	s.timer = 0
+	s.sync()
+}
+
+// Force starts (on) or ends (off) a season regardless of its dates, until Unforce.
+// Returns false for an unknown season.
+func (s *SeasonalSystem) Force(id string, on bool) bool {
+	if _, ok := s.def(id); !ok {
+		return false
+	}
+	s.forced[id] = on
+	s.sync()
+	return true
+}
+
+// Unforce hands a season back to its dates
+func (s *SeasonalSystem) Unforce(id string) bool {
+	if _, ok := s.def(id); !ok {
+		return false
+	}
+	delete(s.forced, id)
+	s.sync()
+	return true
+}
+
+// Active reports whether a season is running
+func (s *SeasonalSystem) Active(id string) bool {
+	return s.active[id] != nil
+}
+
+// Forced reports whether an admin forced a season on or off
+func (s *SeasonalSystem) Forced(id string) (on, forced bool) {
+	on, forced = s.forced[id]
+	return on, forced
+}
+
+// Drops returns the extra drops the running seasons add to a character
+func (s *SeasonalSystem) Drops(charID string) []components.LootEntry {
+	var drops []components.LootEntry
+	for _, def := range s.Defs {
+		if s.active[def.ID] == nil {
+			continue
+		}
+		for _, drop := range def.Drops {
+			if drop.CharacterID == "" || drop.CharacterID == charID {
+				drops = append(drops, components.LootEntry{ItemID: drop.ItemID, Quantity: max(drop.Quantity, 1), Chance: drop.Chance})
+			}
+		}
+	}
+	return drops
+}
+
+// ReloadLevel puts the running seasons' decorations back after a level's map was re-read
+func (s *SeasonalSystem) ReloadLevel(level int) {
+	for _, def := range s.Defs {
+		season := s.active[def.ID]
+		if season == nil {
+			continue
+		}
+		kept := season.placed[:0]
+		for _, d := range season.placed {
+			if d.Level != level {
+				kept = append(kept, d)
+			}
+		}
+		season.placed = kept
+		s.decorate(def, season, level)
+	}
+}
+
+// sync starts and ends seasons to match the calendar and the admin's overrides
+func (s *SeasonalSystem) sync() {
+	now := s.Now()
+	for _, def := range s.Defs {
+		want := def.InSeason(now)
+		if on, ok := s.forced[def.ID]; ok {
+			want = on
+		}
+		switch {
+		case want && s.active[def.ID] == nil:
+			s.start(def)
+		case !want && s.active[def.ID] != nil:
+			s.end(def)
+		}
+	}
+}
+
+func (s *SeasonalSystem) start(def SeasonDef) {
+	season := &activeSeason{}
+	s.active[def.ID] = season
+	for _, sp := range def.Spawners {
+		id := s.Spawn(sp.Level, sp.X, sp.Y, sp.CharacterID)
+		if id == 0 {
+			log.Printf("Season %s: couldn't spawn %s at (%.0f, %.0f)", def.ID, sp.CharacterID, sp.X, sp.Y)
+			continue
+		}
+		if sp.Name != "" {
+			ecs.AddComponent(s.World, id, components.NameComponent{Name: sp.Name})
+		}
+		if len(sp.Shop) > 0 {
+			shop := components.ShopComponent{Name: sp.Name}
+			if name, ok := ecs.GetComponent[components.NameComponent](s.World, id); ok {
+				shop.Name = name.Name
+			}
+			for _, item := range sp.Shop {
+				shop.Stock = append(shop.Stock, components.ShopItem{ItemID: item.ItemID, Price: item.Price})
+			}
+			ecs.AddComponent(s.World, id, shop)
+		}
+		season.entities = append(season.entities, id)
+	}
+	for level := range s.Maps {
+		s.decorate(def, season, level)
+	}
+	log.Printf("Season %s started (%d spawns, %d decorations)", def.ID, len(season.entities), len(season.placed))
+	if def.Announcement != "" && s.Announce != nil {
+		s.Announce(def.Announcement)
+	}
+}
+
+func (s *SeasonalSystem) end(def SeasonDef) {
+	season := s.active[def.ID]
+	delete(s.active, def.ID)
+	for _, id := range season.entities {
+		s.World.RemoveEntity(id)
+	}
+	for _, d := range season.placed {
+		if m := s.Maps[d.Level]; m != nil && m.Objects[d.Y][d.X] == d.Object {
+			s.setObject(d.Level, d.X, d.Y, 0)
+		}
+	}
+	log.Printf("Season %s ended", def.ID)
+	if def.EndAnnouncement != "" && s.Announce != nil {
+		s.Announce(def.EndAnnouncement)
+	}
+}
+
+// decorate places a season's decorations on one level, on tiles with no object
+func (s *SeasonalSystem) decorate(def SeasonDef, season *activeSeason, level int) {
+	m := s.Maps[level]
+	if m == nil {
+		return
+	}
+	for _, d := range def.Decorations {
+		if d.Level != level || d.X < 0 || d.Y < 0 || d.X >= m.Width || d.Y >= m.Height {
+			continue
+		}
+		if m.Objects[d.Y][d.X] != 0 || m.Tiles[d.Y][d.X].Type.IsSolid() {
+			continue
+		}
+		s.setObject(level, d.X, d.Y, d.Object)
+		season.placed = append(season.placed, d)
+	}
+}
+
+func (s *SeasonalSystem) setObject(level, tx, ty, object int) {
+	s.Maps[level].Objects[ty][tx] = object
+	if s.OnObjectChange != nil {
+		s.OnObjectChange(level, tx, ty, object)
+	}
+}
+
+func (s *SeasonalSystem) def(id string) (SeasonDef, bool) {
+	for _, def := range s.Defs {
+		if def.ID == id {
+			return def, true
+		}
+	}
+	return SeasonDef{}, false
+}
@@ -0,0 +1,113 @@
+package systems
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+func TestSeasonDates(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	cases := []struct {
+		start, end, today string
+		want              bool
+	}{
+		{"10-15", "11-05", "2026-10-15", true},
+		{"10-15", "11-05", "2026-11-05", true},
+		{"10-15", "11-05", "2026-11-06", false},
+		{"12-20", "01-03", "2026-12-31", true}, // Over New Year
+		{"12-20", "01-03", "2027-01-02", true},
+		{"12-20", "01-03", "2026-06-01", false},
+		{"2026-07-01", "2026-07-04", "2026-07-02", true}, // Once
+		{"2026-07-01", "2026-07-04", "2027-07-02", false},
+	}
+	for _, c := range cases {
+		def := SeasonDef{Start: c.start, End: c.end}
+		if got := def.InSeason(day(c.today)); got != c.want {
+			t.Errorf("%s to %s on %s = %v, want %v", c.start, c.end, c.today, got, c.want)
+		}
+	}
+}
+
+func TestSeasonalStartAndEnd(t *testing.T) {
+	w := ecs.NewWorld()
+	m := world.NewMap(10, 10)
+	m.Objects[2][2] = world.ObjectTree
+	def := SeasonDef{
+		ID: "fest", Start: "10-15", End: "11-05",
+		Spawners:    []SeasonSpawner{{X: 64, Y: 64, CharacterID: "vendor", Name: "Fest Vendor", Shop: []SeasonShopItem{{ItemID: "potion", Price: 3}}}},
+		Drops:       []SeasonDrop{{ItemID: "pumpkin", Chance: 0.5}, {CharacterID: "goblin", ItemID: "hat", Chance: 1}},
+		Decorations: []SeasonDecoration{{X: 1, Y: 1, Object: world.ObjectPumpkin}, {X: 2, Y: 2, Object: world.ObjectLantern}},
+	}
+	s := NewSeasonalSystem(w, map[int]*world.Map{0: m}, []SeasonDef{def})
+	s.Spawn = func(level int, x, y float64, charID string) ecs.Entity {
+		id := w.NewEntity()
+		ecs.AddComponent(w, id, components.TransformComponent{X: x, Y: y, Z: level})
+		return id
+	}
+	changed := 0
+	s.OnObjectChange = func(level, tx, ty, object int) { changed++ }
+	s.Now = func() time.Time { return time.Date(2026, 6, 1, 12, 0, 0, 0, time.Local) }
+
+	s.Update(0)
+	if s.Active("fest") || len(s.Drops("goblin")) != 0 {
+		t.Fatal("season running out of its dates")
+	}
+
+	// Forced on out of season
+	if !s.Force("fest", true) || !s.Active("fest") {
+		t.Fatal("forcing the season on didn't start it")
+	}
+	vendors := ecs.Query[components.ShopComponent](w)
+	if len(vendors) != 1 {
+		t.Fatalf("%d vendors spawned, want 1", len(vendors))
+	}
+	shop, _ := ecs.GetComponent[components.ShopComponent](w, vendors[0])
+	if shop.Name != "Fest Vendor" || len(shop.Stock) != 1 || shop.Stock[0].Price != 3 {
+		t.Errorf("vendor shop %+v", shop)
+	}
+	if len(s.Drops("goblin")) != 2 || len(s.Drops("wolf")) != 1 {
+		t.Errorf("drops: goblin %v, wolf %v", s.Drops("goblin"), s.Drops("wolf"))
+	}
+	// The tree stays; only the free tile is decorated
+	if m.Objects[1][1] != world.ObjectPumpkin || m.Objects[2][2] != world.ObjectTree || changed != 1 {
+		t.Errorf("objects %d and %d after %d change(s)", m.Objects[1][1], m.Objects[2][2], changed)
+	}
+
+	// Back to the calendar ends it and clears everything it added
+	s.Unforce("fest")
+	if s.Active("fest") || len(ecs.Query[components.ShopComponent](w)) != 0 || m.Objects[1][1] != 0 || m.Objects[2][2] != world.ObjectTree {
+		t.Error("ending the season left its content behind")
+	}
+	if s.Force("nope", true) {
+		t.Error("forced an unknown season")
+	}
+
+	// In season by date, unless forced off
+	s.Now = func() time.Time { return time.Date(2026, 10, 20, 12, 0, 0, 0, time.Local) }
+	s.Update(SeasonCheckInterval)
+	if !s.Active("fest") {
+		t.Fatal("season didn't start on its dates")
+	}
+	s.Force("fest", false)
+	if s.Active("fest") {
+		t.Error("forcing the season off didn't end it")
+	}
+}
+
+func TestLoadSeasons(t *testing.T) {
+	defs, err := LoadSeasons(filepath.Join("..", "..", "..", SeasonalFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defs) == 0 {
+		t.Fatal("no seasons shipped")
+	}
+}
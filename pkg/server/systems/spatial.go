@@ -0,0 +1,121 @@
+package systems
+
+import (
+	"math"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+)
+
+// SpatialCellSize is the side of a SpatialGrid cell in pixels (two tiles)
+const SpatialCellSize = 2 * config.TileSize
+
+// SpatialGrid buckets entities by the cell their position falls in, per level, so
+// collision and perception checks only look at their neighborhood instead of scanning
+// the whole world. The MovementSystem rebuilds it every tick and keeps it current as it
+// moves bodies; whatever else moves an entity is picked up by the next rebuild.
+type SpatialGrid struct {
+	cells map[gridCell][]ecs.Entity
+	where map[ecs.Entity]gridCell
+
+	// pad widens queries so bodies whose position is outside a rect but whose collider
+	// reaches into it are still found: the biggest collider (or sprite tile) indexed
+	pad   float64
+	built bool
+}
+
+type gridCell struct{ z, x, y int }
+
+func NewSpatialGrid() *SpatialGrid {
+	return &SpatialGrid{
+		cells: make(map[gridCell][]ecs.Entity),
+		where: make(map[ecs.Entity]gridCell),
+		pad:   config.TileSize,
+	}
+}
+
+func cellAt(z int, x, y float64) gridCell {
+	return gridCell{z, int(math.Floor(x / SpatialCellSize)), int(math.Floor(y / SpatialCellSize))}
+}
+
+// Rebuild indexes every entity with a position from scratch
+func (g *SpatialGrid) Rebuild(w *ecs.World) {
+	for cell, ids := range g.cells {
+		g.cells[cell] = ids[:0] // Keep the buckets' memory across ticks
+	}
+	clear(g.where)
+	g.pad = config.TileSize
+	for _, id := range ecs.Query[components.TransformComponent](w) {
+		trans, _ := ecs.GetComponent[components.TransformComponent](w, id)
+		size := 0.0
+		if phys, ok := ecs.GetComponent[components.PhysicsComponent](w, id); ok {
+			size = phys.Size
+		}
+		g.Move(id, trans.Z, trans.X, trans.Y, size)
+	}
+	g.built = true
+}
+
+// Move puts an entity at its new position (adding it if the grid hasn't seen it).
+// size is its collider size (0 for the default).
+func (g *SpatialGrid) Move(id ecs.Entity, z int, x, y, size float64) {
+	g.pad = max(g.pad, size)
+	cell := cellAt(z, x, y)
+	if old, ok := g.where[id]; ok {
+		if old == cell {
+			return
+		}
+		g.unlink(id, old)
+	}
+	g.where[id] = cell
+	g.cells[cell] = append(g.cells[cell], id)
+}
+
+// Remove drops an entity from the grid
+func (g *SpatialGrid) Remove(id ecs.Entity) {
+	if cell, ok := g.where[id]; ok {
+		g.unlink(id, cell)
+		delete(g.where, id)
+	}
+}
+
+func (g *SpatialGrid) unlink(id ecs.Entity, cell gridCell) {
+	ids := g.cells[cell]
+	for i, other := range ids {
+		if other == id {
+			g.cells[cell] = append(ids[:i], ids[i+1:]...)
+			return
+		}
+	}
+}
+
+// InRect returns the entities on level z that may touch the rect (x, y, w, h): every body
+// whose collider overlaps it, plus some close by. Callers still check the exact shapes.
+// The entities may have been removed since the grid was updated.
+func (g *SpatialGrid) InRect(z int, x, y, w, h float64) []ecs.Entity {
+	lo := cellAt(z, x-g.pad, y-g.pad)
+	hi := cellAt(z, x+w+g.pad, y+h+g.pad)
+	var found []ecs.Entity
+	for cy := lo.y; cy <= hi.y; cy++ {
+		for cx := lo.x; cx <= hi.x; cx++ {
+			found = append(found, g.cells[gridCell{z, cx, cy}]...)
+		}
+	}
+	return found
+}
+
+// Nearby returns the candidates for a check around the rect (x, y, w, h) on level z:
+// the grid's neighbors when there is a built grid, otherwise every entity with a T.
+// Either way callers must look up T and the position themselves.
+func Nearby[T any](w *ecs.World, g *SpatialGrid, z int, x, y, width, height float64) []ecs.Entity {
+	if g == nil || !g.built {
+		return ecs.Query[T](w)
+	}
+	return g.InRect(z, x, y, width, height)
+}
+
+// NearbyRadius is Nearby for a circle of radius r around (x, y)
+func NearbyRadius[T any](w *ecs.World, g *SpatialGrid, z int, x, y, r float64) []ecs.Entity {
+	return Nearby[T](w, g, z, x-r, y-r, 2*r, 2*r)
+}
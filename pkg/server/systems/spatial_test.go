@@ -0,0 +1,68 @@
+package systems
+
+import (
+	"slices"
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+func TestSpatialGridFindsOnlyNeighbors(t *testing.T) {
+	w := ecs.NewWorld()
+	at := func(z int, x, y, size float64) ecs.Entity {
+		id := w.NewEntity()
+		ecs.AddComponent(w, id, components.TransformComponent{X: x, Y: y, Z: z})
+		ecs.AddComponent(w, id, components.PhysicsComponent{Size: size})
+		return id
+	}
+	near := at(0, 100, 100, 0)
+	far := at(0, 2000, 2000, 0)
+	below := at(1, 100, 100, 0)
+	giant := at(0, 500, 100, 200) // Its collider reaches back toward the query
+
+	g := NewSpatialGrid()
+	g.Rebuild(w)
+	found := g.InRect(0, 90, 90, 20, 20)
+	if !slices.Contains(found, near) || slices.Contains(found, far) || slices.Contains(found, below) {
+		t.Errorf("InRect around (100,100) found %v, want %d but not %d or %d", found, near, far, below)
+	}
+	if !slices.Contains(g.InRect(0, 360, 100, 10, 10), giant) {
+		t.Error("InRect missed a big body whose collider overlaps the rect")
+	}
+
+	g.Move(near, 0, 1990, 1990, 0)
+	if found := g.InRect(0, 90, 90, 20, 20); slices.Contains(found, near) {
+		t.Error("a moved entity is still found at its old cell")
+	}
+	if found := g.InRect(0, 1990, 1990, 20, 20); !slices.Contains(found, near) || !slices.Contains(found, far) {
+		t.Errorf("found %v at the new cell, want both %d and %d", found, near, far)
+	}
+	g.Remove(far)
+	if slices.Contains(g.InRect(0, 1990, 1990, 20, 20), far) {
+		t.Error("a removed entity is still found")
+	}
+}
+
+func TestMovementCollidesThroughTheGrid(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewMovementSystem(w, map[int]*world.Map{0: world.NewMap(64, 64)})
+	body := func(x, y float64, input components.InputComponent) ecs.Entity {
+		id := w.NewEntity()
+		ecs.AddComponent(w, id, components.TransformComponent{X: x, Y: y})
+		ecs.AddComponent(w, id, components.PhysicsComponent{Speed: 10, Layer: components.LayerNPC, Mask: components.MaskCharacter})
+		ecs.AddComponent(w, id, input)
+		return id
+	}
+	// A walker heading right into a post a few cells away, across a cell boundary
+	walker := body(200, 300, components.InputComponent{Right: true})
+	body(400, 300, components.InputComponent{})
+	for i := 0; i < 40; i++ {
+		s.Update(0.05)
+	}
+	trans, _ := ecs.GetComponent[components.TransformComponent](w, walker)
+	if trans.X < 350 || trans.X >= 400 {
+		t.Errorf("walker stopped at x=%.0f, want blocked just short of the post at 400", trans.X)
+	}
+}
@@ -0,0 +1,154 @@
+package systems
+
+import (
+	"fmt"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+	"log"
+	"math/rand"
+	"sort"
+)
+
+type spawnRegionState struct {
+	Level   int
+	Region  world.SpawnRegion
+	Members map[ecs.Entity]bool
+	Timer   float64 // Seconds until the next spawn while short
+	Delay   float64 // The full wait Timer counts down from at the current player count
+}
+
+// SpawnRegionSystem keeps the maps' spawn regions stocked: it fills them at startup,
+// then brings back one NPC per region every RespawnSeconds while it's below its cap,
+// sooner with players hunting its zone and later with nobody there. Region NPCs don't
+// respawn on their own (their RespawnComponent is removed).
+type SpawnRegionSystem struct {
+	World *ecs.World
+	Maps  map[int]*world.Map
+	Zones *ZoneSystem // Player counts (nil = always RespawnSeconds)
+
+	// Hook provided by the GameServer
+	Spawn func(level int, x, y float64, charID string) ecs.Entity
+
+	regions []*spawnRegionState
+}
+
+func NewSpawnRegionSystem(w *ecs.World, maps map[int]*world.Map) *SpawnRegionSystem {
+	s := &SpawnRegionSystem{World: w, Maps: maps}
+	levels := make([]int, 0, len(maps))
+	for z := range maps {
+		levels = append(levels, z)
+	}
+	sort.Ints(levels)
+	for _, z := range levels {
+		for _, r := range maps[z].SpawnRegions {
+			if len(r.CharacterIDs) == 0 || r.MaxPopulation <= 0 {
+				log.Printf("Spawn region %s on level %d has nothing to spawn, skipping", r.ID, z)
+				continue
+			}
+			s.regions = append(s.regions, &spawnRegionState{Level: z, Region: r, Members: make(map[ecs.Entity]bool)})
+		}
+	}
+	return s
+}
+
+// Populate fills every region to its cap
+func (s *SpawnRegionSystem) Populate() {
+	for _, st := range s.regions {
+		for len(st.Members) < st.Region.MaxPopulation {
+			if !s.spawn(st) {
+				break
+			}
+		}
+		st.Timer, st.Delay = 0, 0
+	}
+}
+
+func (s *SpawnRegionSystem) Update(dt float64) {
+	for _, st := range s.regions {
+		// Drop dead/removed members
+		for id := range st.Members {
+			stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id)
+			if stats == nil || stats.CurrentHealth <= 0 {
+				delete(st.Members, id)
+			}
+		}
+
+		if len(st.Members) >= st.Region.MaxPopulation {
+			st.Timer, st.Delay = 0, 0
+			continue
+		}
+
+		// The wait starts when the region falls short, and follows the player count
+		delay := s.respawnDelay(st)
+		if st.Delay <= 0 {
+			st.Timer = delay
+		} else if delay != st.Delay {
+			st.Timer = RetimeRespawn(st.Timer, st.Delay, delay)
+		}
+		st.Delay = delay
+
+		st.Timer -= dt
+		if st.Timer > 0 {
+			continue
+		}
+		st.Delay = 0
+		s.spawn(st)
+	}
+}
+
+// respawnDelay is the wait between spawns in a region that's short, given the players
+// hunting the zone around its center
+func (s *SpawnRegionSystem) respawnDelay(st *spawnRegionState) float64 {
+	base := st.Region.RespawnSeconds
+	if base <= 0 {
+		base = config.NPCRespawnSeconds
+	}
+	if s.Zones == nil {
+		return base
+	}
+	r := st.Region
+	fastest, slowest := RespawnRange(base)
+	return RespawnDelay(fastest, slowest, s.Zones.PlayersAt(st.Level, r.X+r.Width/2, r.Y+r.Height/2))
+}
+
+// spawn adds one random NPC from the region's list at a random open spot in it
+func (s *SpawnRegionSystem) spawn(st *spawnRegionState) bool {
+	if s.Spawn == nil {
+		return false
+	}
+	charID := st.Region.CharacterIDs[rand.Intn(len(st.Region.CharacterIDs))]
+	x, y, ok := s.pickSpawnPoint(st)
+	if !ok {
+		return false
+	}
+	id := s.Spawn(st.Level, x, y, charID)
+	if id == 0 {
+		return false
+	}
+	ecs.RemoveComponent[components.RespawnComponent](s.World, id)
+	ecs.AddComponent(s.World, id, components.SpawnRegionComponent{Region: fmt.Sprintf("%d/%s", st.Level, st.Region.ID), CharID: charID})
+	st.Members[id] = true
+	return true
+}
+
+// pickSpawnPoint finds a random spot in the region that isn't a solid tile or object
+func (s *SpawnRegionSystem) pickSpawnPoint(st *spawnRegionState) (float64, float64, bool) {
+	m, r := s.Maps[st.Level], st.Region
+	tileSize := float64(config.TileSize)
+	for attempt := 0; attempt < 10; attempt++ {
+		x := r.X + rand.Float64()*r.Width
+		y := r.Y + rand.Float64()*r.Height
+		tx := int((x + tileSize/2) / tileSize)
+		ty := int((y + tileSize/2) / tileSize)
+		if tx < 0 || tx >= m.Width || ty < 0 || ty >= m.Height {
+			continue
+		}
+		if m.Tiles[ty][tx].Type.IsSolid() || m.Objects[ty][tx] > 0 {
+			continue
+		}
+		return x, y, true
+	}
+	return 0, 0, false
+}
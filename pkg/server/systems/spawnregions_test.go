@@ -0,0 +1,54 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+func TestSpawnRegionKeepsPopulation(t *testing.T) {
+	w := ecs.NewWorld()
+	m := world.NewMap(32, 32)
+	m.SpawnRegions = []world.SpawnRegion{{
+		ID: "den", X: 256, Y: 256, Width: 512, Height: 512,
+		CharacterIDs: []string{"wolf"}, MaxPopulation: 3, RespawnSeconds: 10,
+	}}
+	s := NewSpawnRegionSystem(w, map[int]*world.Map{0: m})
+	s.Spawn = func(level int, x, y float64, charID string) ecs.Entity {
+		if x < 256 || x > 768 || y < 256 || y > 768 {
+			t.Errorf("spawned at %.0f,%.0f, outside the region", x, y)
+		}
+		id := w.NewEntity()
+		ecs.AddComponent(w, id, components.StatsComponent{MaxHealth: 10, CurrentHealth: 10})
+		ecs.AddComponent(w, id, components.RespawnComponent{CharID: charID})
+		return id
+	}
+	members := func() []ecs.Entity { return ecs.Query[components.SpawnRegionComponent](w) }
+
+	s.Populate()
+	if n := len(members()); n != 3 {
+		t.Fatalf("populated with %d, want the cap of 3", n)
+	}
+	if len(ecs.Query[components.RespawnComponent](w)) != 0 {
+		t.Error("region spawns would also respawn on their own")
+	}
+
+	// Kill two: one comes back per RespawnSeconds, never past the cap
+	w.RemoveEntity(members()[0])
+	w.RemoveEntity(members()[0])
+	s.Update(9)
+	if n := len(members()); n != 1 {
+		t.Fatalf("%d alive before the respawn time, want 1", n)
+	}
+	s.Update(1)
+	if n := len(members()); n != 2 {
+		t.Fatalf("%d alive after one respawn time, want 2", n)
+	}
+	s.Update(10)
+	s.Update(10)
+	if n := len(members()); n != 3 {
+		t.Errorf("%d alive once refilled, want the cap of 3", n)
+	}
+}
@@ -0,0 +1,136 @@
+package systems
+
+import (
+	"errors"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"log"
+)
+
+// IsSpectating reports whether the entity is a spectator (skip it for collision, targeting and snapshots)
+func IsSpectating(world *ecs.World, id ecs.Entity) bool {
+	_, ok := ecs.GetComponent[components.SpectatorComponent](world, id)
+	return ok
+}
+
+// SpectatorSystem moves spectators' cameras. A spectator's own entity is parked on top of
+// whatever it follows, so the usual per-player snapshots cover that area.
+type SpectatorSystem struct {
+	World *ecs.World
+
+	// Hooks provided by the GameServer
+	OnTeleport func(id ecs.Entity) // Moved to another level (resync map)
+	OnChange   func(id ecs.Entity) // Started, stopped or switched target
+	OnMessage  func(id ecs.Entity, msg string)
+}
+
+func NewSpectatorSystem(world *ecs.World) *SpectatorSystem {
+	return &SpectatorSystem{World: world}
+}
+
+// Start turns the player into a spectator following target (0 = free camera where they stand).
+// Calling it again while spectating just switches target.
+func (s *SpectatorSystem) Start(id, target ecs.Entity, arenaOnly bool) error {
+	if target == id {
+		return errors.New("you can't spectate yourself")
+	}
+	if target != 0 {
+		if _, ok := ecs.GetComponent[components.TransformComponent](s.World, target); !ok {
+			return errors.New("nothing to spectate")
+		}
+		if IsSpectating(s.World, target) {
+			return errors.New("that player is spectating")
+		}
+	}
+
+	spec, ok := ecs.GetComponent[components.SpectatorComponent](s.World, id)
+	if !ok {
+		trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if !ok {
+			return errors.New("nothing to spectate from")
+		}
+		spec = &components.SpectatorComponent{ArenaOnly: arenaOnly, ReturnX: trans.X, ReturnY: trans.Y, ReturnZ: trans.Z}
+		log.Printf("Entity %d started spectating", id)
+	}
+	spec.TargetID = target
+	ecs.AddComponent(s.World, id, *spec)
+	ecs.RemoveComponent[components.AutoMoveComponent](s.World, id)
+	s.follow(id, spec)
+	if s.OnChange != nil {
+		s.OnChange(id)
+	}
+	return nil
+}
+
+// Stop puts the spectator back where they started watching from
+func (s *SpectatorSystem) Stop(id ecs.Entity) {
+	spec, ok := ecs.GetComponent[components.SpectatorComponent](s.World, id)
+	if !ok {
+		return
+	}
+	ecs.RemoveComponent[components.SpectatorComponent](s.World, id)
+	s.place(id, spec.ReturnX, spec.ReturnY, spec.ReturnZ)
+	log.Printf("Entity %d stopped spectating", id)
+	if s.OnChange != nil {
+		s.OnChange(id)
+	}
+}
+
+// Update keeps every spectator on its target. Arena viewers are sent back once the
+// match (instance) they watch is over; GMs fall back to a free camera.
+func (s *SpectatorSystem) Update() {
+	for _, id := range ecs.Query[components.SpectatorComponent](s.World) {
+		spec, _ := ecs.GetComponent[components.SpectatorComponent](s.World, id)
+		if spec.TargetID == 0 {
+			continue
+		}
+		if !s.follow(id, spec) {
+			if spec.ArenaOnly {
+				s.message(id, "The match is over")
+				s.Stop(id)
+				continue
+			}
+			s.message(id, "Target gone, free camera")
+			spec.TargetID = 0
+			ecs.AddComponent(s.World, id, *spec)
+			if s.OnChange != nil {
+				s.OnChange(id)
+			}
+		}
+	}
+}
+
+// follow moves the spectator onto its target; false if the target can't be watched anymore
+func (s *SpectatorSystem) follow(id ecs.Entity, spec *components.SpectatorComponent) bool {
+	if spec.TargetID == 0 {
+		return true
+	}
+	target, ok := ecs.GetComponent[components.TransformComponent](s.World, spec.TargetID)
+	if !ok || (spec.ArenaOnly && !IsInstanceLevel(target.Z)) {
+		return false
+	}
+	s.place(id, target.X, target.Y, target.Z)
+	return true
+}
+
+func (s *SpectatorSystem) place(id ecs.Entity, x, y float64, z int) {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+	if !ok {
+		return
+	}
+	changedLevel := trans.Z != z
+	if trans.X == x && trans.Y == y && !changedLevel {
+		return // Don't mark the entity changed every tick
+	}
+	trans.X, trans.Y, trans.Z = x, y, z
+	ecs.AddComponent(s.World, id, *trans)
+	if changedLevel && s.OnTeleport != nil {
+		s.OnTeleport(id)
+	}
+}
+
+func (s *SpectatorSystem) message(id ecs.Entity, msg string) {
+	if s.OnMessage != nil {
+		s.OnMessage(id, msg)
+	}
+}
@@ -0,0 +1,64 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+	"henry/pkg/shared/world"
+)
+
+func TestSpectatorFollowsInvisiblyAndReturns(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewSpectatorSystem(w)
+
+	gm := w.NewEntity()
+	ecs.AddComponent(w, gm, components.TransformComponent{X: 10, Y: 20})
+	w.AddTags(gm, components.TagPlayer)
+	target := w.NewEntity()
+	ecs.AddComponent(w, target, components.TransformComponent{X: 500, Y: 600})
+
+	if err := s.Start(gm, target, false); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	ecs.AddComponent(w, target, components.TransformComponent{X: 550, Y: 600})
+	s.Update()
+	trans, _ := ecs.GetComponent[components.TransformComponent](w, gm)
+	if trans.X != 550 || trans.Y != 600 {
+		t.Fatalf("spectator at %.0f,%.0f, want on target", trans.X, trans.Y)
+	}
+
+	net := NewNetworkSystem(w, world.NewClock(12, 1200))
+	updates := net.PrepareUpdatesFor(target)
+	for _, e := range updates[len(updates)-1].Data.(protocol.StateUpdatePacket).Entities {
+		if e.ID == gm {
+			t.Error("spectator visible to another entity")
+		}
+	}
+
+	s.Stop(gm)
+	trans, _ = ecs.GetComponent[components.TransformComponent](w, gm)
+	if IsSpectating(w, gm) || trans.X != 10 || trans.Y != 20 {
+		t.Errorf("stop should return the spectator (at %.0f,%.0f)", trans.X, trans.Y)
+	}
+}
+
+func TestArenaViewerLeavesWhenMatchEnds(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewSpectatorSystem(w)
+
+	viewer := w.NewEntity()
+	ecs.AddComponent(w, viewer, components.TransformComponent{X: 10, Y: 20})
+	fighter := w.NewEntity()
+	ecs.AddComponent(w, fighter, components.TransformComponent{X: 100, Y: 100, Z: InstanceLevelBase})
+
+	if err := s.Start(viewer, fighter, true); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	ecs.AddComponent(w, fighter, components.TransformComponent{X: 300, Y: 300}) // Returned to level 0
+	s.Update()
+	if IsSpectating(w, viewer) {
+		t.Error("arena viewer kept watching after the fighter left the instance")
+	}
+}
@@ -0,0 +1,93 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+func TestRunningDrainsStaminaAndFallsBackToWalk(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewMovementSystem(w, map[int]*world.Map{})
+	id := w.NewEntity()
+	ecs.AddComponent(w, id, components.TransformComponent{})
+	ecs.AddComponent(w, id, components.PhysicsComponent{Speed: 3})
+	ecs.AddComponent(w, id, components.InputComponent{Right: true, Stance: components.StanceRun})
+	ecs.AddComponent(w, id, components.StanceComponent{Stance: components.StanceWalk, Stamina: config.MaxStamina})
+
+	stance := func() components.StanceComponent {
+		c, _ := ecs.GetComponent[components.StanceComponent](w, id)
+		return *c
+	}
+
+	s.Update(1)
+	if got := stance(); got.Stance != components.StanceRun || got.Stamina != config.MaxStamina-config.StaminaDrain {
+		t.Fatalf("after 1s running: %+v", got)
+	}
+	for i := 0; i < int(config.MaxStamina/config.StaminaDrain); i++ {
+		s.Update(1)
+	}
+	if got := stance(); got.Stance != components.StanceWalk || got.Stamina != config.StaminaRegen {
+		t.Fatalf("out of stamina: %+v, want walking and recovering", got)
+	}
+
+	// Not enough breath to start running again yet
+	s.Update(1)
+	if got := stance(); got.Stance != components.StanceWalk {
+		t.Errorf("ran again at %.0f stamina", got.Stamina)
+	}
+}
+
+func TestSneakingShrinksAggroRange(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewAISystem(w, map[int]*world.Map{}, nil)
+	npc := w.NewEntity()
+	ai := &components.AIComponent{Faction: components.FactionMonsters, IsAggressive: true, AggroRange: 300}
+	npcTrans := &components.TransformComponent{}
+
+	player := w.NewEntity()
+	ecs.AddComponent(w, player, components.TransformComponent{X: 200})
+	ecs.AddComponent(w, player, components.StatsComponent{MaxHealth: 100, CurrentHealth: 100})
+	ecs.AddComponent(w, player, components.StanceComponent{Stance: components.StanceWalk})
+	w.AddTags(player, components.TagPlayer)
+
+	if got := s.findHostileTarget(npc, ai, npcTrans); got != player {
+		t.Fatalf("walking player at 200px not noticed (got %d)", got)
+	}
+	ecs.AddComponent(w, player, components.StanceComponent{Stance: components.StanceSneak})
+	if got := s.findHostileTarget(npc, ai, npcTrans); got != 0 {
+		t.Errorf("sneaking player at 200px noticed with a 300px aggro range")
+	}
+}
+
+func TestDodgeCostsStaminaAndGrantsInvulnerability(t *testing.T) {
+	w := ecs.NewWorld()
+	s := NewMovementSystem(w, map[int]*world.Map{})
+	id := w.NewEntity()
+	ecs.AddComponent(w, id, components.TransformComponent{})
+	ecs.AddComponent(w, id, components.StatsComponent{MaxHealth: 100, CurrentHealth: 100})
+	ecs.AddComponent(w, id, components.StanceComponent{Stance: components.StanceWalk, Stamina: config.DodgeStaminaCost})
+
+	input := components.InputComponent{Right: true, Dodge: true}
+	if err := s.Dodge(id, input); err != nil {
+		t.Fatalf("dodge: %v", err)
+	}
+	if err := s.Dodge(id, input); err == nil {
+		t.Error("dodged again mid roll")
+	}
+	stats, _ := ecs.GetComponent[components.StatsComponent](w, id)
+	if stats.InvulnTimer < config.DodgeDuration {
+		t.Errorf("invulnerable for %.2fs, want the whole roll", stats.InvulnTimer)
+	}
+
+	s.Update(config.DodgeDuration)
+	if s.IsDodging(id) {
+		t.Error("still dodging after the roll")
+	}
+	if err := s.Dodge(id, input); err == nil {
+		t.Error("dodged without stamina")
+	}
+}
@@ -0,0 +1,76 @@
+package systems
+
+import (
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"math"
+)
+
+// CombatStats are the numbers combat runs on, derived from an entity's attributes. Melee
+// and ranged attacks, spells and the character sheet all read them from Stats, so an
+// attribute means the same thing everywhere.
+type CombatStats struct {
+	Attributes components.Attributes // Own plus gear
+
+	MeleeDamage       float64 // Multiplier (STR)
+	RangedDamage      float64 // Multiplier (DEX)
+	SpellPower        float64 // Multiplier on spell damage and healing (INT)
+	CooldownReduction float64 // Fraction taken off spell cooldowns (INT)
+	AttackSpeed       float64 // Multiplier on attacks per second (haste)
+}
+
+// Stats totals an entity's own attributes and its equipped gear's and derives its combat
+// stats. Entities with neither get the neutral stats (all multipliers 1).
+func Stats(w *ecs.World, id ecs.Entity) CombatStats {
+	var attrs components.Attributes
+	if own, ok := ecs.GetComponent[components.AttributesComponent](w, id); ok {
+		attrs = own.Base
+	}
+	if equip, ok := ecs.GetComponent[components.EquipmentComponent](w, id); ok {
+		for _, slot := range equip.Slots {
+			if def, ok := items.Get(slot.ItemID); ok {
+				attrs = attrs.Add(def.Attributes)
+			}
+		}
+	}
+	return DeriveStats(attrs)
+}
+
+// DeriveStats turns attribute totals into combat stats
+func DeriveStats(attrs components.Attributes) CombatStats {
+	haste := math.Min(float64(max(attrs.Haste, 0)), config.MaxHaste)
+	return CombatStats{
+		Attributes:        attrs,
+		MeleeDamage:       attributeScale(attrs.Str),
+		RangedDamage:      attributeScale(attrs.Dex),
+		SpellPower:        attributeScale(attrs.Int),
+		CooldownReduction: math.Min(float64(max(attrs.Int, 0))*config.CDRPerInt, config.MaxCooldownReduction),
+		AttackSpeed:       1 + haste/100,
+	}
+}
+
+// AttackDamage scales a weapon's base damage by the stat its attack type uses
+func (c CombatStats) AttackDamage(attackType components.AttackType, base float64) float64 {
+	if attackType == components.AttackTypeRanged {
+		return base * c.RangedDamage
+	}
+	return base * c.MeleeDamage
+}
+
+// AttackCooldown shortens a weapon's cooldown by haste
+func (c CombatStats) AttackCooldown(base float64) float64 {
+	return base / c.AttackSpeed
+}
+
+// SpellCooldown shortens a spell's cooldown by cooldown reduction
+func (c CombatStats) SpellCooldown(base float64) float64 {
+	return base * (1 - c.CooldownReduction)
+}
+
+// attributeScale is the damage multiplier from points of a primary attribute; negative
+// totals (from cursed gear) can't take it below zero
+func attributeScale(points int) float64 {
+	return math.Max(1+float64(points)*config.DamagePerAttribute, 0)
+}
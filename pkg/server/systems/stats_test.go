@@ -0,0 +1,63 @@
+package systems
+
+import (
+	"math"
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestStatsAddGearToOwnAttributes(t *testing.T) {
+	w := ecs.NewWorld()
+	id := w.NewEntity()
+	ecs.AddComponent(w, id, components.AttributesComponent{Base: components.Attributes{Str: 5, Dex: 5, Int: 5}})
+	equip := components.EquipmentComponent{}
+	equip.Slots[components.SlotWeapon] = components.EquipmentSlot{ItemID: "sword_starter"}
+	equip.Slots[components.SlotNeck] = components.EquipmentSlot{ItemID: "amulet_sage"}
+	equip.Slots[components.SlotHands] = components.EquipmentSlot{ItemID: "gloves_swift"}
+	ecs.AddComponent(w, id, equip)
+
+	stats := Stats(w, id)
+	if want := (components.Attributes{Str: 7, Dex: 6, Int: 9, Haste: 15}); stats.Attributes != want {
+		t.Fatalf("attributes = %+v, want %+v", stats.Attributes, want)
+	}
+	if !near(stats.AttackDamage(components.AttackTypeMelee, 10), 10*(1+7*config.DamagePerAttribute)) {
+		t.Errorf("melee damage = %v", stats.AttackDamage(components.AttackTypeMelee, 10))
+	}
+	if !near(stats.AttackDamage(components.AttackTypeRanged, 10), 10*(1+6*config.DamagePerAttribute)) {
+		t.Errorf("ranged damage = %v", stats.AttackDamage(components.AttackTypeRanged, 10))
+	}
+	if !near(stats.AttackCooldown(1.15), 1) {
+		t.Errorf("15%% haste cooldown = %v, want 1", stats.AttackCooldown(1.15))
+	}
+	if !near(stats.SpellCooldown(10), 10*(1-9*config.CDRPerInt)) {
+		t.Errorf("spell cooldown = %v", stats.SpellCooldown(10))
+	}
+}
+
+func TestStatsNeutralWithoutAttributes(t *testing.T) {
+	w := ecs.NewWorld()
+	stats := Stats(w, w.NewEntity())
+	if stats.MeleeDamage != 1 || stats.RangedDamage != 1 || stats.SpellPower != 1 || stats.AttackSpeed != 1 || stats.CooldownReduction != 0 {
+		t.Errorf("stats = %+v, want neutral", stats)
+	}
+}
+
+func TestDeriveStatsCaps(t *testing.T) {
+	stats := DeriveStats(components.Attributes{Str: -100, Int: 1000, Haste: 1000})
+	if stats.MeleeDamage != 0 {
+		t.Errorf("melee damage = %v, want 0", stats.MeleeDamage)
+	}
+	if stats.CooldownReduction != config.MaxCooldownReduction {
+		t.Errorf("cooldown reduction = %v, want %v", stats.CooldownReduction, config.MaxCooldownReduction)
+	}
+	if !near(stats.AttackSpeed, 1+config.MaxHaste/100.0) {
+		t.Errorf("attack speed = %v", stats.AttackSpeed)
+	}
+}
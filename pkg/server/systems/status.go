@@ -0,0 +1,190 @@
+package systems
+
+import (
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+// MaxBurnStacks caps how many times a burn from one source stacks
+const MaxBurnStacks = 5
+
+// BurnInterval is how often (seconds) a burn deals its damage
+const BurnInterval = 1.0
+
+// StatusEffectSystem counts down status effects, removes them when they run out and deals
+// burn damage
+type StatusEffectSystem struct {
+	World *ecs.World
+
+	// OnBurn deals burn damage to target on behalf of caster, going through the same
+	// shields, deaths and kill credit as a hit (provided by the GameServer)
+	OnBurn func(caster, target ecs.Entity, damage float64)
+}
+
+func NewStatusEffectSystem(world *ecs.World) *StatusEffectSystem {
+	return &StatusEffectSystem{World: world}
+}
+
+func (s *StatusEffectSystem) Update(dt float64) {
+	type burn struct {
+		caster, target ecs.Entity
+		damage         float64
+	}
+	var burns []burn
+
+	for _, id := range ecs.Query[components.StatusEffectComponent](s.World) {
+		status, _ := ecs.GetComponent[components.StatusEffectComponent](s.World, id)
+		kept := status.Effects[:0]
+		for _, effect := range status.Effects {
+			if effect.Kind == components.StatusBurn {
+				effect.Timer -= dt
+				if effect.Timer <= 0 {
+					effect.Timer += BurnInterval
+					burns = append(burns, burn{effect.Caster, id, effect.Amount * float64(max(effect.Stacks, 1))})
+				}
+			}
+			effect.TimeLeft -= dt
+			if effect.TimeLeft > 0 {
+				kept = append(kept, effect)
+			}
+		}
+		if len(kept) == 0 {
+			ecs.RemoveComponent[components.StatusEffectComponent](s.World, id)
+			continue
+		}
+		status.Effects = kept
+		ecs.AddComponent(s.World, id, *status)
+	}
+
+	// Dealt after the sweep, as a burn can kill (and remove) its target
+	for _, b := range burns {
+		if s.OnBurn != nil {
+			s.OnBurn(b.caster, b.target, b.damage)
+		}
+	}
+}
+
+// AddStatusEffect puts an effect on an entity. Reapplying an effect refreshes its
+// duration; beyond that it depends on the kind:
+//   - burns from the same source add a stack (up to MaxBurnStacks)
+//   - shields replace any shield, keeping the larger amount left to soak up
+//   - anything else from the same source replaces the old one (no stacking)
+func AddStatusEffect(w *ecs.World, id ecs.Entity, effect components.StatusEffect) {
+	status, _ := ecs.GetComponent[components.StatusEffectComponent](w, id)
+	if status == nil {
+		status = &components.StatusEffectComponent{}
+	}
+	effect.Stacks = max(effect.Stacks, 1)
+	if effect.Duration == 0 {
+		effect.Duration = effect.TimeLeft
+	}
+	if effect.Kind == components.StatusBurn {
+		effect.Timer = BurnInterval
+	}
+
+	for i, old := range status.Effects {
+		if old.Kind != effect.Kind {
+			continue
+		}
+		switch {
+		case effect.Kind == components.StatusShield:
+			effect.Amount = max(effect.Amount, old.Amount)
+		case old.Source != effect.Source:
+			continue
+		case effect.Kind == components.StatusBurn:
+			effect.Stacks = min(old.Stacks+1, MaxBurnStacks)
+			effect.Timer = old.Timer
+		}
+		status.Effects[i] = effect
+		ecs.AddComponent(w, id, *status)
+		return
+	}
+	status.Effects = append(status.Effects, effect)
+	ecs.AddComponent(w, id, *status)
+}
+
+// RemoveStatus takes every effect of a kind off an entity
+func RemoveStatus(w *ecs.World, id ecs.Entity, kind string) {
+	status, ok := ecs.GetComponent[components.StatusEffectComponent](w, id)
+	if !ok || !HasStatus(w, id, kind) {
+		return
+	}
+	kept := status.Effects[:0]
+	for _, effect := range status.Effects {
+		if effect.Kind != kind {
+			kept = append(kept, effect)
+		}
+	}
+	if len(kept) == 0 {
+		ecs.RemoveComponent[components.StatusEffectComponent](w, id)
+		return
+	}
+	status.Effects = kept
+	ecs.AddComponent(w, id, *status)
+}
+
+// HasStatus reports whether an entity has an effect of a kind
+func HasStatus(w *ecs.World, id ecs.Entity, kind string) bool {
+	status, ok := ecs.GetComponent[components.StatusEffectComponent](w, id)
+	if !ok {
+		return false
+	}
+	for _, effect := range status.Effects {
+		if effect.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInvisible reports whether an entity is hidden from other players and NPCs
+func IsInvisible(w *ecs.World, id ecs.Entity) bool {
+	return HasStatus(w, id, components.StatusInvisible)
+}
+
+// Reveal ends an entity's invisibility (it attacked)
+func Reveal(w *ecs.World, id ecs.Entity) {
+	RemoveStatus(w, id, components.StatusInvisible)
+}
+
+// BuffMultiplier is the product of an entity's buffs on a stat (1 without any). Speed
+// also counts the strongest slow.
+func BuffMultiplier(w *ecs.World, id ecs.Entity, stat string) float64 {
+	status, ok := ecs.GetComponent[components.StatusEffectComponent](w, id)
+	if !ok {
+		return 1
+	}
+	m, slow := 1.0, 1.0
+	for _, effect := range status.Effects {
+		switch {
+		case effect.Kind == stat:
+			m *= effect.Amount
+		case effect.Kind == components.StatusSlow && stat == components.BuffSpeed:
+			slow = min(slow, effect.Amount)
+		}
+	}
+	return m * slow
+}
+
+// IncomingDamage is how much of a hit gets through to an entity: its damage-taken buffs
+// scale it, then its shield soaks up what it can (and is used up by as much)
+func IncomingDamage(w *ecs.World, id ecs.Entity, amount float64) float64 {
+	amount *= BuffMultiplier(w, id, components.BuffDamageTaken)
+	status, ok := ecs.GetComponent[components.StatusEffectComponent](w, id)
+	if !ok {
+		return amount
+	}
+	for i, effect := range status.Effects {
+		if effect.Kind != components.StatusShield || amount <= 0 {
+			continue
+		}
+		soaked := min(effect.Amount, amount)
+		amount -= soaked
+		status.Effects[i].Amount -= soaked
+		if status.Effects[i].Amount <= 0 {
+			status.Effects[i].TimeLeft = 0 // Broken, removed on the next update
+		}
+		ecs.AddComponent(w, id, *status)
+	}
+	return amount
+}
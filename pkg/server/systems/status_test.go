@@ -0,0 +1,112 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+func TestStatusStacking(t *testing.T) {
+	w := ecs.NewWorld()
+	id := w.NewEntity()
+
+	for range MaxBurnStacks + 2 {
+		AddStatusEffect(w, id, components.StatusEffect{Kind: components.StatusBurn, Source: "fireball", Amount: 3, TimeLeft: 4})
+	}
+	AddStatusEffect(w, id, components.StatusEffect{Kind: components.StatusShield, Source: "shield", Amount: 40, TimeLeft: 8})
+	AddStatusEffect(w, id, components.StatusEffect{Kind: components.StatusShield, Source: "potion", Amount: 10, TimeLeft: 8})
+	AddStatusEffect(w, id, components.StatusEffect{Kind: components.StatusSlow, Source: "frost", Amount: 0.5, TimeLeft: 3})
+	AddStatusEffect(w, id, components.StatusEffect{Kind: components.StatusSlow, Source: "mud", Amount: 0.8, TimeLeft: 3})
+	AddStatusEffect(w, id, components.StatusEffect{Kind: components.BuffSpeed, Source: "elixir", Amount: 1.2, TimeLeft: 30})
+
+	status, _ := ecs.GetComponent[components.StatusEffectComponent](w, id)
+	if len(status.Effects) != 5 {
+		t.Fatalf("%d effects, want burn, one shield, two slows and a buff", len(status.Effects))
+	}
+	if burn := status.Effects[0]; burn.Stacks != MaxBurnStacks {
+		t.Errorf("burn has %d stacks, want %d", burn.Stacks, MaxBurnStacks)
+	}
+	if shield := status.Effects[1]; shield.Amount != 40 {
+		t.Errorf("shield soaks %.0f, want the larger 40 kept", shield.Amount)
+	}
+	// Only the strongest slow counts, buffs still multiply
+	if m := BuffMultiplier(w, id, components.BuffSpeed); m != 0.5*1.2 {
+		t.Errorf("speed x%.2f, want x%.2f", m, 0.5*1.2)
+	}
+}
+
+func TestShieldSoaksDamage(t *testing.T) {
+	w := ecs.NewWorld()
+	id := w.NewEntity()
+	AddStatusEffect(w, id, components.StatusEffect{Kind: components.StatusShield, Source: "shield", Amount: 40, TimeLeft: 8})
+
+	if got := IncomingDamage(w, id, 25); got != 0 {
+		t.Errorf("first hit got %.0f through the shield", got)
+	}
+	if got := IncomingDamage(w, id, 25); got != 10 {
+		t.Errorf("second hit got %.0f through, want the 10 the shield couldn't soak", got)
+	}
+	NewStatusEffectSystem(w).Update(0.1)
+	if HasStatus(w, id, components.StatusShield) {
+		t.Error("a broken shield stayed up")
+	}
+}
+
+func TestBurnTicksAndExpires(t *testing.T) {
+	w := ecs.NewWorld()
+	caster, id := w.NewEntity(), w.NewEntity()
+	AddStatusEffect(w, id, components.StatusEffect{Kind: components.StatusBurn, Source: "fireball", Caster: caster, Amount: 3, TimeLeft: 2.5})
+	AddStatusEffect(w, id, components.StatusEffect{Kind: components.StatusBurn, Source: "fireball", Caster: caster, Amount: 3, TimeLeft: 2.5})
+
+	s := NewStatusEffectSystem(w)
+	var total float64
+	s.OnBurn = func(from, target ecs.Entity, damage float64) {
+		if from != caster || target != id {
+			t.Errorf("burn from %d on %d, want %d on %d", from, target, caster, id)
+		}
+		total += damage
+	}
+	for range 30 {
+		s.Update(0.1)
+	}
+	if total != 12 {
+		t.Errorf("burned for %.0f, want two ticks of two stacks (12)", total)
+	}
+	if HasStatus(w, id, components.StatusBurn) {
+		t.Error("burn outlasted its duration")
+	}
+}
+
+func TestInvisibleHiddenFromOthers(t *testing.T) {
+	w := ecs.NewWorld()
+	net := NewNetworkSystem(w, nil)
+	viewer, sneak := w.NewEntity(), w.NewEntity()
+	for _, id := range []ecs.Entity{viewer, sneak} {
+		ecs.AddComponent(w, id, components.TransformComponent{X: 100, Y: 100})
+		ecs.AddComponent(w, id, components.SpriteComponent{Width: 32, Height: 32})
+	}
+	AddStatusEffect(w, sneak, components.StatusEffect{Kind: components.StatusInvisible, Source: "void", TimeLeft: 6})
+
+	seen := func(by, target ecs.Entity) bool {
+		updates := net.PrepareUpdatesFor(by)
+		snapshot := updates[len(updates)-1].Data.(protocol.StateUpdatePacket)
+		for _, e := range snapshot.Entities {
+			if e.ID == target {
+				return true
+			}
+		}
+		return false
+	}
+	if seen(viewer, sneak) {
+		t.Error("an invisible entity was sent to another player")
+	}
+	if !seen(sneak, sneak) {
+		t.Error("an invisible entity wasn't sent to itself")
+	}
+	Reveal(w, sneak)
+	if !seen(viewer, sneak) {
+		t.Error("a revealed entity wasn't sent")
+	}
+}
@@ -0,0 +1,125 @@
+package systems
+
+import (
+	"henry/pkg/storage"
+	"log"
+	"time"
+)
+
+// Zone name used for deaths and kills outside every zone (and on zoneless instance maps)
+const TelemetryWilderness = "wilderness"
+
+// TelemetrySystem counts what players actually do (logins, deaths and kills per zone,
+// item and spell use) and exports the counts every Interval seconds, then starts over.
+// It is opt-in: with Interval 0 nothing is counted or written.
+type TelemetrySystem struct {
+	Interval float64 // Seconds between exports (0 = off)
+	Dir      string  // Where exports are written
+
+	// Clock (replaced in tests)
+	Now func() time.Time
+
+	report  storage.TelemetryReport
+	players map[string]bool // Usernames seen this interval
+	timer   float64
+}
+
+func NewTelemetrySystem() *TelemetrySystem {
+	s := &TelemetrySystem{Dir: storage.TelemetryDir, Now: time.Now}
+	s.reset()
+	return s
+}
+
+// Enabled reports whether telemetry is being collected
+func (s *TelemetrySystem) Enabled() bool {
+	return s.Interval > 0
+}
+
+func (s *TelemetrySystem) reset() {
+	s.report = storage.TelemetryReport{
+		Start:  s.Now(),
+		Deaths: make(map[string]int),
+		Kills:  make(map[string]int),
+		Items:  make(map[string]int),
+		Spells: make(map[string]int),
+	}
+	s.players = make(map[string]bool)
+	s.timer = 0
+}
+
+// RecordLogin counts a login; online is the player count including them
+func (s *TelemetrySystem) RecordLogin(username string, online int) {
+	if !s.Enabled() {
+		return
+	}
+	s.report.Logins++
+	s.players[username] = true
+	s.report.UniquePlayers = len(s.players)
+	s.report.PeakOnline = max(s.report.PeakOnline, online)
+}
+
+// RecordDeath counts a player dying in a zone ("" = wilderness)
+func (s *TelemetrySystem) RecordDeath(zone string) {
+	s.count(s.report.Deaths, zoneKey(zone))
+}
+
+// RecordKill counts a player killing an NPC in a zone ("" = wilderness)
+func (s *TelemetrySystem) RecordKill(zone string) {
+	s.count(s.report.Kills, zoneKey(zone))
+}
+
+// RecordItem counts a player using (equipping, fishing with, ...) an item
+func (s *TelemetrySystem) RecordItem(itemID string) {
+	s.count(s.report.Items, itemID)
+}
+
+// RecordSpell counts a successful cast by a player
+func (s *TelemetrySystem) RecordSpell(spellID string) {
+	s.count(s.report.Spells, spellID)
+}
+
+func (s *TelemetrySystem) count(counts map[string]int, key string) {
+	if s.Enabled() && key != "" {
+		counts[key]++
+	}
+}
+
+func zoneKey(zone string) string {
+	if zone == "" {
+		return TelemetryWilderness
+	}
+	return zone
+}
+
+// Current returns the counts of the interval so far
+func (s *TelemetrySystem) Current() storage.TelemetryReport {
+	report := s.report
+	report.End = s.Now()
+	return report
+}
+
+// Update exports the counts once the interval is up
+func (s *TelemetrySystem) Update(dt float64) {
+	if !s.Enabled() {
+		return
+	}
+	s.timer += dt
+	if s.timer >= s.Interval {
+		s.Flush()
+	}
+}
+
+// Flush exports the counts so far and starts a new interval. Returns the JSON file
+// written ("" when telemetry is off).
+func (s *TelemetrySystem) Flush() string {
+	if !s.Enabled() {
+		return ""
+	}
+	path, err := storage.SaveTelemetry(s.Dir, s.Current())
+	if err != nil {
+		log.Printf("Telemetry export failed: %v", err)
+		path = ""
+	}
+	s.reset()
+	return path
+}
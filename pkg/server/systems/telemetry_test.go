@@ -0,0 +1,81 @@
+package systems
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"henry/pkg/storage"
+)
+
+func TestTelemetryOffRecordsNothing(t *testing.T) {
+	s := NewTelemetrySystem()
+	s.Dir = t.TempDir()
+	s.RecordLogin("alice", 1)
+	s.RecordDeath("Goblin Fields")
+	s.RecordSpell("fireball")
+	s.Update(1e6)
+
+	if report := s.Current(); report.Logins != 0 || len(report.Deaths) != 0 || len(report.Spells) != 0 {
+		t.Errorf("disabled telemetry counted %+v", report)
+	}
+	if files, _ := os.ReadDir(s.Dir); len(files) != 0 {
+		t.Errorf("disabled telemetry wrote %d file(s)", len(files))
+	}
+}
+
+func TestTelemetryExportsAndResets(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	s := NewTelemetrySystem()
+	s.Now = func() time.Time { return now }
+	s.Dir = t.TempDir()
+	s.Interval = 60
+
+	s.RecordLogin("alice", 1)
+	s.RecordLogin("bob", 2)
+	s.RecordLogin("alice", 2)
+	s.RecordDeath("")
+	s.RecordKill("Goblin Fields")
+	s.RecordKill("Goblin Fields")
+	s.RecordItem("iron_sword")
+	s.RecordSpell("fireball")
+
+	s.Update(30)
+	if files, _ := os.ReadDir(s.Dir); len(files) != 0 {
+		t.Fatal("exported before the interval was up")
+	}
+	now = now.Add(time.Minute)
+	s.Update(30)
+
+	data, err := os.ReadFile(filepath.Join(s.Dir, "telemetry-20261017-120100.json"))
+	if err != nil {
+		t.Fatalf("no JSON export: %v", err)
+	}
+	var report storage.TelemetryReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Logins != 3 || report.UniquePlayers != 2 || report.PeakOnline != 2 {
+		t.Errorf("logins %d / unique %d / peak %d, want 3 / 2 / 2", report.Logins, report.UniquePlayers, report.PeakOnline)
+	}
+	if report.Deaths[TelemetryWilderness] != 1 || report.Kills["Goblin Fields"] != 2 {
+		t.Errorf("deaths %v, kills %v", report.Deaths, report.Kills)
+	}
+
+	csv, err := os.ReadFile(filepath.Join(s.Dir, "telemetry-20261017-120100.csv"))
+	if err != nil {
+		t.Fatalf("no CSV export: %v", err)
+	}
+	for _, row := range []string{"logins,,3", "kills,Goblin Fields,2", "items,iron_sword,1", "spells,fireball,1"} {
+		if !strings.Contains(string(csv), row+"\n") {
+			t.Errorf("CSV is missing row %q:\n%s", row, csv)
+		}
+	}
+
+	if after := s.Current(); after.Logins != 0 || len(after.Kills) != 0 || !after.Start.Equal(now) {
+		t.Errorf("counts not reset after export: %+v", after)
+	}
+}
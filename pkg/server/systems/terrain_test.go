@@ -0,0 +1,52 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+func TestPathPrefersRoadOverSand(t *testing.T) {
+	// Row 1 is sand between the endpoints, row 0 a cobbled road around it
+	m := world.NewMap(7, 3)
+	for x := 0; x < 7; x++ {
+		m.Tiles[0][x].Type = world.TileCobblePath
+		m.Tiles[1][x].Type = world.TileSand
+		m.Tiles[2][x].Type = world.TileSand
+	}
+	s := NewAISystem(ecs.NewWorld(), map[int]*world.Map{0: m}, nil)
+
+	tile := float64(config.TileSize)
+	path := s.FindPath(m, 0, tile, 6*tile, tile)
+	if len(path) < 2 {
+		t.Fatalf("path %v goes straight over the sand, want a detour by road", path)
+	}
+	for _, node := range path[:len(path)-1] {
+		if node[1] != 0 {
+			t.Errorf("path %v leaves the road at %v", path, node)
+		}
+	}
+}
+
+func TestRoadsAreFasterThanSand(t *testing.T) {
+	walk := func(ground world.TileType) float64 {
+		m := world.NewMap(3, 1)
+		m.Tiles[0][0].Type = ground
+		w := ecs.NewWorld()
+		s := NewMovementSystem(w, map[int]*world.Map{0: m})
+		id := w.NewEntity()
+		ecs.AddComponent(w, id, components.TransformComponent{})
+		ecs.AddComponent(w, id, components.PhysicsComponent{Speed: 4})
+		ecs.AddComponent(w, id, components.InputComponent{Right: true})
+		s.Update(0.05)
+		trans, _ := ecs.GetComponent[components.TransformComponent](w, id)
+		return trans.X
+	}
+	road, grass, sand := walk(world.TileCobblePath), walk(world.TileGrass), walk(world.TileSand)
+	if !(road > grass && grass > sand) {
+		t.Errorf("one step covers %.2f on road, %.2f on grass, %.2f on sand; want road > grass > sand", road, grass, sand)
+	}
+}
@@ -0,0 +1,124 @@
+package systems
+
+import (
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"henry/pkg/shared/world"
+)
+
+type TriggerSystem struct {
+	World *ecs.World
+	Maps  map[int]*world.Map
+
+	// Hooks provided by the GameServer
+	OnEnter func(id ecs.Entity, trigger *components.TriggerComponent)
+	OnExit  func(id ecs.Entity, trigger *components.TriggerComponent)
+}
+
+func NewTriggerSystem(world *ecs.World, maps map[int]*world.Map) *TriggerSystem {
+	return &TriggerSystem{
+		World: world,
+		Maps:  maps,
+	}
+}
+
+// SpawnTriggers creates a trigger entity for every trigger defined in the maps
+func (s *TriggerSystem) SpawnTriggers() {
+	for level, m := range s.Maps {
+		s.spawnLevel(level, m)
+	}
+}
+
+// ReloadLevel replaces a level's trigger entities with the ones its map defines now
+func (s *TriggerSystem) ReloadLevel(level int) {
+	for _, id := range ecs.Query[components.TriggerComponent](s.World) {
+		if trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id); ok && trans.Z == level {
+			s.World.RemoveEntity(id)
+		}
+	}
+	if m, ok := s.Maps[level]; ok {
+		s.spawnLevel(level, m)
+	}
+}
+
+func (s *TriggerSystem) spawnLevel(level int, m *world.Map) {
+	for _, t := range m.Triggers {
+		id := s.World.NewEntity()
+		ecs.AddComponent(s.World, id, components.TransformComponent{X: t.X, Y: t.Y, Z: level})
+		ecs.AddComponent(s.World, id, components.TriggerComponent{
+			TriggerID: t.ID,
+			Width:     t.Width,
+			Height:    t.Height,
+			Action:    t.Action,
+			Message:   t.Message,
+			Damage:    t.Damage,
+			TargetX:   t.TargetX,
+			TargetY:   t.TargetY,
+			TargetZ:   t.TargetZ,
+			Cutscene:  t.Cutscene,
+			Once:      t.Once,
+			Occupants: make(map[ecs.Entity]bool),
+			Fired:     make(map[ecs.Entity]bool),
+		})
+		ecs.AddComponent(s.World, id, components.NameComponent{Name: t.ID})
+	}
+}
+
+// Update fires enter/exit events for players crossing trigger bounds
+func (s *TriggerSystem) Update() {
+	players := ecs.QueryTagged(s.World, components.TagPlayer)
+	tileSize := float64(config.TileSize)
+
+	for _, tid := range ecs.Query[components.TriggerComponent](s.World) {
+		trigger, _ := ecs.GetComponent[components.TriggerComponent](s.World, tid)
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, tid)
+		if trigger == nil || trans == nil {
+			continue
+		}
+
+		for _, pid := range players {
+			inside := false
+			pTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, pid)
+			pPhys, _ := ecs.GetComponent[components.PhysicsComponent](s.World, pid)
+			if pTrans != nil && pPhys != nil && pTrans.Z == trans.Z && !IsSpectating(s.World, pid) {
+				bx, by, size := components.ColliderBounds(pTrans.X, pTrans.Y, pPhys, tileSize)
+				inside = geom.Square(bx, by, size).Overlaps(geom.AABB{X: trans.X, Y: trans.Y, W: trigger.Width, H: trigger.Height})
+			}
+
+			wasInside := trigger.Occupants[pid]
+			if inside == wasInside {
+				continue
+			}
+
+			if inside {
+				trigger.Occupants[pid] = true
+				if trigger.Once && trigger.Fired[pid] {
+					continue
+				}
+				trigger.Fired[pid] = true
+				if s.OnEnter != nil {
+					s.OnEnter(pid, trigger)
+				}
+			} else {
+				delete(trigger.Occupants, pid)
+				if s.OnExit != nil {
+					s.OnExit(pid, trigger)
+				}
+			}
+		}
+
+		// Forget players that logged out
+		for pid := range trigger.Occupants {
+			if !ecs.HasTag(s.World, pid, components.TagPlayer) {
+				delete(trigger.Occupants, pid)
+			}
+		}
+		for pid := range trigger.Fired {
+			if !ecs.HasTag(s.World, pid, components.TagPlayer) {
+				delete(trigger.Fired, pid)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package systems
+
+import (
+	"errors"
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+var ErrNotEnoughGold = errors.New("not enough gold")
+
+// Gold is how much gold an entity holds (0 without a wallet)
+func Gold(w *ecs.World, id ecs.Entity) int {
+	if wallet, ok := ecs.GetComponent[components.WalletComponent](w, id); ok {
+		return wallet.Gold
+	}
+	return 0
+}
+
+// AddGold puts gold in an entity's wallet
+func AddGold(w *ecs.World, id ecs.Entity, amount int) {
+	ecs.AddComponent(w, id, components.WalletComponent{Gold: Gold(w, id) + amount})
+}
+
+// SpendGold takes gold from an entity's wallet, all or nothing
+func SpendGold(w *ecs.World, id ecs.Entity, amount int) error {
+	gold := Gold(w, id)
+	if amount > gold {
+		return ErrNotEnoughGold
+	}
+	ecs.AddComponent(w, id, components.WalletComponent{Gold: gold - amount})
+	return nil
+}
+
+// GiveItem gives an entity items: gold goes to its wallet, keys and quest items to its
+// keyring, anything else to its bag
+func GiveItem(w *ecs.World, id ecs.Entity, itemID string, quantity int) error {
+	if itemID == items.Gold {
+		AddGold(w, id, quantity)
+		return nil
+	}
+	if items.OnKeyring(itemID) {
+		AddToKeyring(w, id, itemID, "", quantity)
+		return nil
+	}
+	inv, ok := ecs.GetComponent[components.InventoryComponent](w, id)
+	if !ok {
+		return errors.New("no inventory")
+	}
+	if err := items.AddItem(inv, itemID, quantity); err != nil {
+		return err
+	}
+	ecs.AddComponent(w, id, *inv)
+	return nil
+}
+
+// PocketGold moves gold coins from an entity's bag into its wallet (saves from before
+// wallets). Reports whether there were any.
+func PocketGold(w *ecs.World, id ecs.Entity) bool {
+	inv, ok := ecs.GetComponent[components.InventoryComponent](w, id)
+	if !ok {
+		return false
+	}
+	coins := items.CountItem(inv, items.Gold)
+	if coins == 0 {
+		return false
+	}
+	items.RemoveItemByID(inv, items.Gold, coins)
+	ecs.AddComponent(w, id, *inv)
+	AddGold(w, id, coins)
+	return true
+}
@@ -0,0 +1,180 @@
+package systems
+
+import (
+	"errors"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/geom"
+	"henry/pkg/shared/world"
+	"image/color"
+)
+
+const (
+	WaypointDiscoverDist = 128.0 // Max distance (px) to unlock a waypoint
+	WaypointUseDist      = 128.0 // Max distance (px) to an unlocked waypoint to start travelling
+)
+
+type WaypointSystem struct {
+	World *ecs.World
+	Maps  map[int]*world.Map
+
+	// Called when a player unlocks a new waypoint
+	OnDiscover func(id ecs.Entity, wp *components.WaypointComponent)
+
+	Economy *EconomySystem // Optional travel fee override
+}
+
+func NewWaypointSystem(world *ecs.World, maps map[int]*world.Map) *WaypointSystem {
+	return &WaypointSystem{
+		World: world,
+		Maps:  maps,
+	}
+}
+
+// SpawnWaypoints creates a waypoint entity for every waypoint defined in the maps
+func (s *WaypointSystem) SpawnWaypoints() {
+	for level, m := range s.Maps {
+		s.spawnLevel(level, m)
+	}
+}
+
+// ReloadLevel replaces a level's waypoint entities with the ones its map defines now.
+// Players keep the waypoints they unlocked (by ID).
+func (s *WaypointSystem) ReloadLevel(level int) {
+	for _, id := range ecs.Query[components.WaypointComponent](s.World) {
+		if trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id); ok && trans.Z == level {
+			s.World.RemoveEntity(id)
+		}
+	}
+	if m, ok := s.Maps[level]; ok {
+		s.spawnLevel(level, m)
+	}
+}
+
+func (s *WaypointSystem) spawnLevel(level int, m *world.Map) {
+	for _, wp := range m.Waypoints {
+		id := s.World.NewEntity()
+		ecs.AddComponent(s.World, id, components.TransformComponent{X: wp.X, Y: wp.Y, Z: level})
+		ecs.AddComponent(s.World, id, components.SpriteComponent{
+			Width:  float64(config.TileSize),
+			Height: float64(config.TileSize),
+			Color:  color.RGBA{R: 80, G: 200, B: 255, A: 160}, // Translucent Cyan
+		})
+		ecs.AddComponent(s.World, id, components.WaypointComponent{WaypointID: wp.ID, Name: wp.Name})
+		ecs.AddComponent(s.World, id, components.NameComponent{Name: wp.Name})
+		s.World.AddTags(id, components.TagWaypoint)
+	}
+}
+
+// Update unlocks waypoints for players standing near them
+func (s *WaypointSystem) Update() {
+	waypoints := ecs.Query[components.WaypointComponent](s.World)
+	players := ecs.Query[components.TravelComponent](s.World)
+
+	for _, pid := range players {
+		travel, _ := ecs.GetComponent[components.TravelComponent](s.World, pid)
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, pid)
+		if travel == nil || trans == nil {
+			continue
+		}
+
+		changed := false
+		for _, wid := range waypoints {
+			wp, _ := ecs.GetComponent[components.WaypointComponent](s.World, wid)
+			if wp == nil || hasWaypoint(travel, wp.WaypointID) {
+				continue
+			}
+			if !s.inRange(trans, wid, WaypointDiscoverDist) {
+				continue
+			}
+
+			travel.UnlockedWaypoints = append(travel.UnlockedWaypoints, wp.WaypointID)
+			changed = true
+			if s.OnDiscover != nil {
+				s.OnDiscover(pid, wp)
+			}
+		}
+
+		if changed {
+			ecs.AddComponent(s.World, pid, *travel)
+		}
+	}
+}
+
+// Travel teleports a player to an unlocked waypoint, charging the gold fee.
+// The player must be standing at one of their unlocked waypoints.
+func (s *WaypointSystem) Travel(playerID ecs.Entity, waypointID string) error {
+	travel, _ := ecs.GetComponent[components.TravelComponent](s.World, playerID)
+	trans, _ := ecs.GetComponent[components.TransformComponent](s.World, playerID)
+	if travel == nil || trans == nil {
+		return errors.New("invalid traveller")
+	}
+
+	if !hasWaypoint(travel, waypointID) {
+		return errors.New("waypoint not discovered")
+	}
+
+	var origin, dest ecs.Entity
+	for _, wid := range ecs.Query[components.WaypointComponent](s.World) {
+		wp, _ := ecs.GetComponent[components.WaypointComponent](s.World, wid)
+		if wp == nil {
+			continue
+		}
+		if wp.WaypointID == waypointID {
+			dest = wid
+		}
+		if origin == 0 && hasWaypoint(travel, wp.WaypointID) && s.inRange(trans, wid, WaypointUseDist) {
+			origin = wid
+		}
+	}
+
+	if dest == 0 {
+		return errors.New("unknown waypoint")
+	}
+	if origin == 0 {
+		return errors.New("not at a waypoint")
+	}
+	if origin == dest {
+		return errors.New("already at this waypoint")
+	}
+
+	destTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, dest)
+	if destTrans == nil {
+		return errors.New("unknown waypoint")
+	}
+	if err := SpendGold(s.World, playerID, s.Fee()); err != nil {
+		return err
+	}
+	trans.X = destTrans.X
+	trans.Y = destTrans.Y
+	trans.Z = destTrans.Z
+
+	ecs.AddComponent(s.World, playerID, *trans)
+	return nil
+}
+
+func (s *WaypointSystem) inRange(trans *components.TransformComponent, waypoint ecs.Entity, dist float64) bool {
+	wpTrans, _ := ecs.GetComponent[components.TransformComponent](s.World, waypoint)
+	if wpTrans == nil || wpTrans.Z != trans.Z {
+		return false
+	}
+	return geom.Within(wpTrans.X, wpTrans.Y, trans.X, trans.Y, dist)
+}
+
+func hasWaypoint(travel *components.TravelComponent, waypointID string) bool {
+	for _, id := range travel.UnlockedWaypoints {
+		if id == waypointID {
+			return true
+		}
+	}
+	return false
+}
+
+// Fee is the gold charged per teleport
+func (s *WaypointSystem) Fee() int {
+	if s.Economy != nil {
+		return s.Economy.WaypointFee()
+	}
+	return config.WaypointTravelFee
+}
@@ -0,0 +1,182 @@
+package systems
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WebhookFile lists the outgoing webhooks (optional; without it none are sent)
+const WebhookFile = "data/webhooks.json"
+
+// WebhookConfig is one webhook URL (a Discord channel webhook) and the events it gets
+type WebhookConfig struct {
+	URL      string   `json:"url"`
+	Events   []string `json:"events"`   // Event kinds to send (empty = all)
+	Username string   `json:"username"` // Name the messages are posted under
+}
+
+// LoadWebhooks reads webhook definitions from a JSON file
+func LoadWebhooks(path string) ([]WebhookConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var hooks []WebhookConfig
+	if err := json.Unmarshal(data, &hooks); err != nil {
+		return nil, fmt.Errorf("failed to parse webhooks json: %w", err)
+	}
+	for i, hook := range hooks {
+		if hook.URL == "" {
+			return nil, fmt.Errorf("webhook %d has no url", i+1)
+		}
+	}
+	return hooks, nil
+}
+
+// Embed colors per event kind
+var webhookColors = map[string]int{
+	EventServerStart: 0x2ECC71, // Green
+	EventServerStop:  0x95A5A6, // Grey
+	EventBossKilled:  0xF1C40F, // Gold
+	EventMaxLevel:    0x9B59B6, // Purple
+	EventBan:         0xE74C3C, // Red
+	EventMacroFlag:   0xE67E22, // Orange
+}
+
+// Discord webhook body
+type discordMessage struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Color       int    `json:"color"`
+	Timestamp   string `json:"timestamp"`
+}
+
+type webhookPost struct {
+	URL  string
+	Body []byte
+}
+
+// WebhookSystem posts server events to the configured webhooks. Posts go out one at a
+// time from a background goroutine, so a slow endpoint never holds up the game; when
+// more than webhookQueueSize are waiting, new ones are dropped.
+type WebhookSystem struct {
+	Hooks  []WebhookConfig
+	Client *http.Client
+
+	mu     sync.Mutex
+	closed bool
+	queue  chan webhookPost
+	done   chan struct{}
+}
+
+const webhookQueueSize = 100
+
+// NewWebhookSystem starts the sender when there are hooks to send to
+func NewWebhookSystem(hooks []WebhookConfig) *WebhookSystem {
+	s := &WebhookSystem{
+		Hooks:  hooks,
+		Client: &http.Client{Timeout: 10 * time.Second},
+		queue:  make(chan webhookPost, webhookQueueSize),
+		done:   make(chan struct{}),
+	}
+	if len(hooks) > 0 {
+		go s.run()
+	} else {
+		close(s.done)
+	}
+	return s
+}
+
+// Handle queues an event for every webhook that wants it (subscribe it to the EventBus)
+func (s *WebhookSystem) Handle(event ServerEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	for _, hook := range s.Hooks {
+		if len(hook.Events) > 0 && !slices.Contains(hook.Events, event.Kind) {
+			continue
+		}
+		body, err := json.Marshal(discordMessageFor(hook, event))
+		if err != nil {
+			log.Printf("Webhook: %v", err)
+			continue
+		}
+		select {
+		case s.queue <- webhookPost{URL: hook.URL, Body: body}:
+		default:
+			log.Printf("Webhook queue full, dropping %s event", event.Kind)
+		}
+	}
+}
+
+// discordMessageFor formats an event as a Discord embed
+func discordMessageFor(hook WebhookConfig, event ServerEvent) discordMessage {
+	return discordMessage{
+		Username: hook.Username,
+		Embeds: []discordEmbed{{
+			Title:       event.Title,
+			Description: event.Message,
+			Color:       webhookColors[event.Kind],
+			Timestamp:   event.Time.UTC().Format(time.RFC3339),
+		}},
+	}
+}
+
+// Close sends what's queued, giving up after timeout, and ignores later events. Call
+// before the server exits so the stop event gets out.
+func (s *WebhookSystem) Close(timeout time.Duration) {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	select {
+	case <-s.done:
+	case <-time.After(timeout):
+		log.Printf("Webhook: gave up on %d unsent post(s)", len(s.queue))
+	}
+}
+
+func (s *WebhookSystem) run() {
+	defer close(s.done)
+	for post := range s.queue {
+		s.send(post)
+	}
+}
+
+// send posts once, and once more after the wait Discord asks for when rate limited
+func (s *WebhookSystem) send(post webhookPost) {
+	for attempt := 0; attempt < 2; attempt++ {
+		resp, err := s.Client.Post(post.URL, "application/json", bytes.NewReader(post.Body))
+		if err != nil {
+			log.Printf("Webhook post failed: %v", err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusTooManyRequests {
+			wait, _ := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64)
+			time.Sleep(time.Duration(min(max(wait, 1), 30) * float64(time.Second)))
+			continue
+		}
+		if resp.StatusCode >= 300 {
+			log.Printf("Webhook post failed: %s", resp.Status)
+		}
+		return
+	}
+}
@@ -0,0 +1,56 @@
+package systems
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebhooksPostDiscordEmbeds(t *testing.T) {
+	var mu sync.Mutex
+	var got []discordMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg discordMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("bad body: %v", err)
+		}
+		mu.Lock()
+		got = append(got, msg)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	hooks := NewWebhookSystem([]WebhookConfig{{URL: server.URL, Events: []string{EventBossKilled, EventBan}, Username: "Realm"}})
+	bus := NewEventBus()
+	bus.Subscribe(hooks.Handle)
+	bus.Publish(EventServerStart, "Server started", "") // Not wanted by the hook
+	bus.Publish(EventBossKilled, "World boss defeated", "The dragon falls")
+	bus.Publish(EventBan, "Player banned", "griefer was banned")
+	hooks.Close(5 * time.Second) // Sends what's queued
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 2 {
+		t.Fatalf("got %d posts, want 2 (the start event is filtered out)", len(got))
+	}
+	msg := got[0]
+	if msg.Username != "Realm" || len(msg.Embeds) != 1 {
+		t.Fatalf("got %+v, want one embed posted as Realm", msg)
+	}
+	embed := msg.Embeds[0]
+	if embed.Title != "World boss defeated" || embed.Description != "The dragon falls" || embed.Color != webhookColors[EventBossKilled] {
+		t.Errorf("embed = %+v", embed)
+	}
+	if _, err := time.Parse(time.RFC3339, embed.Timestamp); err != nil {
+		t.Errorf("timestamp %q: %v", embed.Timestamp, err)
+	}
+	if got[1].Embeds[0].Title != "Player banned" {
+		t.Errorf("second post = %+v, want the ban", got[1])
+	}
+
+	bus.Publish(EventBan, "Player banned", "too late") // Ignored once closed
+}
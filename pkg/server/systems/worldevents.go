@@ -0,0 +1,358 @@
+package systems
+
+import (
+	"encoding/json"
+	"fmt"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+	"henry/pkg/storage"
+	"log"
+	"math/rand"
+	"os"
+)
+
+// WorldEventDef describes a scripted event loaded from data/events/world_events.json
+type WorldEventDef struct {
+	ID              string             `json:"id"`
+	Type            string             `json:"type"`     // "invasion", "rare_spawn", "world_boss"
+	Interval        float64            `json:"interval"` // Seconds between trigger rolls
+	Chance          float64            `json:"chance"`   // Probability (0-1) per roll
+	Duration        float64            `json:"duration"` // Seconds before leftover spawns retreat
+	Level           int                `json:"level"`
+	X               float64            `json:"x"`
+	Y               float64            `json:"y"`
+	Radius          float64            `json:"radius"` // Spawn scatter around X/Y
+	Spawns          []WorldEventSpawn  `json:"spawns"`
+	Announcement    string             `json:"announcement"`
+	EndAnnouncement string             `json:"end_announcement"`
+	Rewards         []WorldEventReward `json:"rewards"` // Given to every participant on success
+
+	// World bosses: spawns gain this share of their base health per contributor after
+	// the first, and rewards are mailed to players who dealt at least MinContribution
+	// (0-1) of the damage
+	HealthPerPlayer float64 `json:"health_per_player"`
+	MinContribution float64 `json:"min_contribution"`
+}
+
+type WorldEventSpawn struct {
+	CharacterID string `json:"character_id"`
+	Count       int    `json:"count"`
+}
+
+type WorldEventReward struct {
+	ItemID   string `json:"item_id"`
+	Quantity int    `json:"quantity"`
+}
+
+type activeWorldEvent struct {
+	Def          WorldEventDef
+	Entities     map[ecs.Entity]bool
+	Participants map[ecs.Entity]bool
+	TimeLeft     float64
+
+	// World bosses only
+	Contribution map[string]float64     // Username -> damage dealt
+	BaseHealth   map[ecs.Entity]float64 // Max health before scaling
+}
+
+type WorldEventSystem struct {
+	World *ecs.World
+	Maps  map[int]*world.Map
+	Defs  []WorldEventDef
+
+	// Hooks provided by the GameServer
+	Spawn        func(x, y float64, charID string) ecs.Entity
+	Announce     func(msg string)
+	AnnounceZone func(level int, x, y float64, msg string) // Players in the zone around a point
+	OnReward     func(id ecs.Entity)                       // Inventory changed
+	OnCleared    func(def WorldEventDef, participants int) // Every spawn was killed
+
+	Mail  *MailSystem // Delivers world boss rewards
+	Audit *ItemAudit  // Optional item audit trail
+
+	timers map[string]float64
+	active map[string]*activeWorldEvent
+}
+
+func NewWorldEventSystem(world *ecs.World, maps map[int]*world.Map, defs []WorldEventDef) *WorldEventSystem {
+	s := &WorldEventSystem{
+		World:  world,
+		Maps:   maps,
+		Defs:   defs,
+		timers: make(map[string]float64),
+		active: make(map[string]*activeWorldEvent),
+	}
+	for _, def := range defs {
+		s.timers[def.ID] = def.Interval
+	}
+	return s
+}
+
+// LoadWorldEvents reads event definitions from a JSON file
+func LoadWorldEvents(path string) ([]WorldEventDef, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var defs []WorldEventDef
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("failed to parse world events json: %w", err)
+	}
+	return defs, nil
+}
+
+func (s *WorldEventSystem) Update(dt float64) {
+	for _, def := range s.Defs {
+		if ev, ok := s.active[def.ID]; ok {
+			s.updateActive(ev, dt)
+			continue
+		}
+
+		s.timers[def.ID] -= dt
+		if s.timers[def.ID] > 0 {
+			continue
+		}
+		s.timers[def.ID] = def.Interval
+		if rand.Float64() < def.Chance {
+			s.Start(def.ID)
+		}
+	}
+}
+
+// Roll starts an event with its configured chance, like its own timer would.
+// Returns whether it started.
+func (s *WorldEventSystem) Roll(eventID string) bool {
+	for _, def := range s.Defs {
+		if def.ID == eventID {
+			return rand.Float64() < def.Chance && s.Start(eventID)
+		}
+	}
+	return false
+}
+
+// Start triggers an event immediately (ignores chance). Returns false if unknown or already running.
+func (s *WorldEventSystem) Start(eventID string) bool {
+	if _, running := s.active[eventID]; running {
+		return false
+	}
+
+	for _, def := range s.Defs {
+		if def.ID != eventID {
+			continue
+		}
+
+		ev := &activeWorldEvent{
+			Def:          def,
+			Entities:     make(map[ecs.Entity]bool),
+			Participants: make(map[ecs.Entity]bool),
+			TimeLeft:     def.Duration,
+			Contribution: make(map[string]float64),
+			BaseHealth:   make(map[ecs.Entity]float64),
+		}
+		for _, spawn := range def.Spawns {
+			for i := 0; i < spawn.Count; i++ {
+				x, y := s.pickSpawnPoint(def)
+				id := s.Spawn(x, y, spawn.CharacterID)
+				if id == 0 {
+					continue
+				}
+				// Event spawns don't come back
+				ecs.RemoveComponent[components.RespawnComponent](s.World, id)
+				ev.Entities[id] = true
+				if stats, ok := ecs.GetComponent[components.StatsComponent](s.World, id); ok {
+					ev.BaseHealth[id] = stats.MaxHealth
+				}
+			}
+		}
+
+		if len(ev.Entities) == 0 {
+			log.Printf("World event %s spawned nothing, skipping", def.ID)
+			return false
+		}
+
+		s.active[def.ID] = ev
+		log.Printf("World event %s started with %d spawns", def.ID, len(ev.Entities))
+		if def.Announcement != "" {
+			s.announce(def, def.Announcement)
+		}
+		return true
+	}
+	return false
+}
+
+// RecordDamage marks the attacker as a participant if the victim belongs to an event.
+// Damage by players against a world boss counts towards their contribution.
+func (s *WorldEventSystem) RecordDamage(attacker, victim ecs.Entity, damage float64) {
+	for _, ev := range s.active {
+		if !ev.Entities[victim] {
+			continue
+		}
+		ev.Participants[attacker] = true
+		if ev.Def.Type != "world_boss" || !ecs.HasTag(s.World, attacker, components.TagPlayer) {
+			return
+		}
+		name, ok := ecs.GetComponent[components.NameComponent](s.World, attacker)
+		if !ok {
+			return
+		}
+		_, known := ev.Contribution[name.Name]
+		ev.Contribution[name.Name] += damage
+		if !known {
+			s.scaleHealth(ev)
+		}
+		return
+	}
+}
+
+// Contribution returns the damage each player dealt to a running world boss event
+func (s *WorldEventSystem) Contribution(eventID string) map[string]float64 {
+	if ev, ok := s.active[eventID]; ok {
+		return ev.Contribution
+	}
+	return nil
+}
+
+// scaleHealth grows a world boss's spawns for its current number of contributors.
+// The extra health is added on top, so damage already dealt still counts.
+func (s *WorldEventSystem) scaleHealth(ev *activeWorldEvent) {
+	if ev.Def.HealthPerPlayer <= 0 || len(ev.Contribution) < 2 {
+		return
+	}
+	scale := 1 + ev.Def.HealthPerPlayer*float64(len(ev.Contribution)-1)
+	for id := range ev.Entities {
+		stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id)
+		base, ok := ev.BaseHealth[id]
+		if stats == nil || !ok || stats.CurrentHealth <= 0 {
+			continue
+		}
+		if scaled := base * scale; scaled > stats.MaxHealth {
+			stats.CurrentHealth += scaled - stats.MaxHealth
+			stats.MaxHealth = scaled
+			ecs.AddComponent(s.World, id, *stats)
+		}
+	}
+}
+
+func (s *WorldEventSystem) updateActive(ev *activeWorldEvent, dt float64) {
+	// Drop dead/removed spawns
+	for id := range ev.Entities {
+		stats, _ := ecs.GetComponent[components.StatsComponent](s.World, id)
+		if stats == nil || stats.CurrentHealth <= 0 {
+			delete(ev.Entities, id)
+		}
+	}
+
+	if len(ev.Entities) == 0 {
+		// Cleared!
+		delete(s.active, ev.Def.ID)
+		log.Printf("World event %s cleared by %d participants", ev.Def.ID, len(ev.Participants))
+		if s.OnCleared != nil {
+			s.OnCleared(ev.Def, len(ev.Participants))
+		}
+		if ev.Def.EndAnnouncement != "" {
+			s.announce(ev.Def, ev.Def.EndAnnouncement)
+		}
+		if ev.Def.Type == "world_boss" {
+			s.mailRewards(ev)
+		} else {
+			s.reward(ev)
+		}
+		return
+	}
+
+	ev.TimeLeft -= dt
+	if ev.Def.Duration > 0 && ev.TimeLeft <= 0 {
+		// Expired, survivors retreat
+		for id := range ev.Entities {
+			s.World.RemoveEntity(id)
+		}
+		delete(s.active, ev.Def.ID)
+		log.Printf("World event %s expired", ev.Def.ID)
+		s.announce(ev.Def, "The threat has withdrawn... for now.")
+	}
+}
+
+func (s *WorldEventSystem) reward(ev *activeWorldEvent) {
+	for id := range ev.Participants {
+		if _, ok := ecs.GetComponent[components.InventoryComponent](s.World, id); !ok {
+			continue // NPC helpers or disconnected players
+		}
+		for _, r := range ev.Def.Rewards {
+			if err := GiveItem(s.World, id, r.ItemID, r.Quantity); err != nil {
+				log.Printf("World event %s: could not reward %s to Entity %d: %v", ev.Def.ID, r.ItemID, id, err)
+				continue
+			}
+			s.Audit.Record("rewarded", id, ev.Def.ID, r.ItemID, "", r.Quantity)
+		}
+		if s.OnReward != nil {
+			s.OnReward(id)
+		}
+	}
+}
+
+// mailRewards sends the event rewards to every player who dealt a meaningful share of
+// the damage, wherever they are now (dead, elsewhere or logged out)
+func (s *WorldEventSystem) mailRewards(ev *activeWorldEvent) {
+	if s.Mail == nil || len(ev.Def.Rewards) == 0 {
+		return
+	}
+	total := 0.0
+	for _, dmg := range ev.Contribution {
+		total += dmg
+	}
+	attachments := make([]storage.MailItem, 0, len(ev.Def.Rewards))
+	for _, r := range ev.Def.Rewards {
+		attachments = append(attachments, storage.MailItem{ItemID: r.ItemID, Quantity: r.Quantity})
+	}
+
+	subject := ev.Def.EndAnnouncement
+	if subject == "" {
+		subject = "World boss defeated"
+	}
+	mailed := 0
+	for username, dmg := range ev.Contribution {
+		if dmg < total*ev.Def.MinContribution {
+			continue
+		}
+		s.Mail.Send(username, "World Events", subject, attachments)
+		mailed++
+	}
+	log.Printf("World event %s: mailed rewards to %d of %d contributors", ev.Def.ID, mailed, len(ev.Contribution))
+}
+
+// announce tells world boss news to the zone around the boss and everything else to the server
+func (s *WorldEventSystem) announce(def WorldEventDef, msg string) {
+	if def.Type == "world_boss" && s.AnnounceZone != nil {
+		s.AnnounceZone(def.Level, def.X, def.Y, msg)
+		return
+	}
+	if s.Announce != nil {
+		s.Announce(msg)
+	}
+}
+
+// pickSpawnPoint scatters spawns around the event origin, avoiding solid tiles
+func (s *WorldEventSystem) pickSpawnPoint(def WorldEventDef) (float64, float64) {
+	m, ok := s.Maps[def.Level]
+	if !ok || def.Radius <= 0 {
+		return def.X, def.Y
+	}
+
+	tileSize := float64(config.TileSize)
+	for attempt := 0; attempt < 10; attempt++ {
+		x := def.X + (rand.Float64()*2-1)*def.Radius
+		y := def.Y + (rand.Float64()*2-1)*def.Radius
+		tx := int((x + tileSize/2) / tileSize)
+		ty := int((y + tileSize/2) / tileSize)
+		if tx < 0 || tx >= m.Width || ty < 0 || ty >= m.Height {
+			continue
+		}
+		if m.Tiles[ty][tx].Type.IsSolid() || m.Objects[ty][tx] > 0 {
+			continue
+		}
+		return x, y
+	}
+	return def.X, def.Y
+}
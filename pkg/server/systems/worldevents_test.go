@@ -0,0 +1,105 @@
+package systems
+
+import (
+	"testing"
+
+	"henry/pkg/items"
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+// bossWorld starts a world boss event with a 100 HP boss and returns it
+func bossWorld(t *testing.T) (*WorldEventSystem, *MailSystem, ecs.Entity) {
+	t.Helper()
+	w := ecs.NewWorld()
+	def := WorldEventDef{
+		ID:              "boss",
+		Type:            "world_boss",
+		Spawns:          []WorldEventSpawn{{CharacterID: "boss", Count: 1}},
+		Rewards:         []WorldEventReward{{ItemID: "coin_gold", Quantity: 10}},
+		HealthPerPlayer: 0.5,
+		MinContribution: 0.1,
+	}
+	s := NewWorldEventSystem(w, nil, []WorldEventDef{def})
+	s.Mail = NewMailSystem(w, nil)
+	s.Spawn = func(x, y float64, charID string) ecs.Entity {
+		id := w.NewEntity()
+		ecs.AddComponent(w, id, components.StatsComponent{MaxHealth: 100, CurrentHealth: 100})
+		return id
+	}
+	if !s.Start("boss") {
+		t.Fatal("boss event did not start")
+	}
+	for id := range s.active["boss"].Entities {
+		return s, s.Mail, id
+	}
+	t.Fatal("no boss spawned")
+	return nil, nil, 0
+}
+
+func bossFighter(w *ecs.World, name string) ecs.Entity {
+	id := spawnTestPlayers(w, 1, 100, 100)[0]
+	ecs.AddComponent(w, id, components.NameComponent{Name: name})
+	return id
+}
+
+func hit(s *WorldEventSystem, attacker, boss ecs.Entity, damage float64) {
+	stats, _ := ecs.GetComponent[components.StatsComponent](s.World, boss)
+	stats.CurrentHealth -= damage
+	ecs.AddComponent(s.World, boss, *stats)
+	s.RecordDamage(attacker, boss, damage)
+}
+
+func TestWorldBossScalesWithContributors(t *testing.T) {
+	s, _, boss := bossWorld(t)
+	alice := bossFighter(s.World, "alice")
+	bob := bossFighter(s.World, "bob")
+
+	hit(s, alice, boss, 20)
+	hit(s, alice, boss, 20) // Same contributor again: no growth
+	hit(s, bob, boss, 10)
+
+	stats, _ := ecs.GetComponent[components.StatsComponent](s.World, boss)
+	if stats.MaxHealth != 150 {
+		t.Errorf("max health = %v, want 150 for two contributors", stats.MaxHealth)
+	}
+	if stats.CurrentHealth != 100 {
+		t.Errorf("health = %v, want 100 (50 damage dealt, 50 added)", stats.CurrentHealth)
+	}
+	if got := s.Contribution("boss")["alice"]; got != 40 {
+		t.Errorf("alice contributed %v, want 40", got)
+	}
+}
+
+func TestWorldBossMailsMeaningfulContributors(t *testing.T) {
+	s, mail, boss := bossWorld(t)
+	alice := bossFighter(s.World, "alice")
+	bob := bossFighter(s.World, "bob")
+	carol := bossFighter(s.World, "carol")
+
+	hit(s, bob, boss, 40)
+	hit(s, carol, boss, 1) // Under 10% of the damage
+	// Alice died and logged out before the kill
+	hit(s, alice, boss, 60)
+	s.World.RemoveEntity(alice)
+	hit(s, bob, boss, 100)
+	s.Update(0)
+
+	if len(s.active) != 0 {
+		t.Fatal("event should end with the boss dead")
+	}
+	if len(mail.Inbox("alice")) != 1 || len(mail.Inbox("bob")) != 1 {
+		t.Errorf("alice and bob should get one mail each (%d, %d)", len(mail.Inbox("alice")), len(mail.Inbox("bob")))
+	}
+	if len(mail.Inbox("carol")) != 0 {
+		t.Error("carol's token hit should not earn a reward")
+	}
+
+	if err := mail.Take(bob, "bob", mail.Inbox("bob")[0].ID); err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	inv, _ := ecs.GetComponent[components.InventoryComponent](s.World, bob)
+	if Gold(s.World, bob) != 10 || items.CountItem(inv, items.Gold) != 0 || len(mail.Inbox("bob")) != 0 {
+		t.Error("taking the mail should move the gold into the wallet and empty the mailbox")
+	}
+}
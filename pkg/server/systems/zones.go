@@ -0,0 +1,80 @@
+package systems
+
+import (
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	"henry/pkg/shared/world"
+)
+
+type ZoneSystem struct {
+	World *ecs.World
+	Maps  map[int]*world.Map
+
+	// Called when an entity enters a different zone (zone is nil for wilderness)
+	OnZoneChange func(id ecs.Entity, zone *world.Zone)
+
+	players map[levelZone]int // Players per zone as of the last Update
+}
+
+type levelZone struct {
+	Level int
+	Zone  string // "" for the level's wilderness
+}
+
+func NewZoneSystem(world *ecs.World, maps map[int]*world.Map) *ZoneSystem {
+	return &ZoneSystem{
+		World:   world,
+		Maps:    maps,
+		players: make(map[levelZone]int),
+	}
+}
+
+// PlayersAt counts the players in the zone around a point (the level's wilderness
+// counts as one zone)
+func (s *ZoneSystem) PlayersAt(level int, x, y float64) int {
+	m, ok := s.Maps[level]
+	if !ok {
+		return 0
+	}
+	key := levelZone{Level: level}
+	if zone := m.ZoneAt(x, y); zone != nil {
+		key.Zone = zone.ID
+	}
+	return s.players[key]
+}
+
+// Update checks every entity with a ZoneComponent against its map's zones and
+// recounts the players in each
+func (s *ZoneSystem) Update() {
+	clear(s.players)
+	entities := ecs.Query[components.ZoneComponent](s.World)
+	for _, id := range entities {
+		zc, _ := ecs.GetComponent[components.ZoneComponent](s.World, id)
+		trans, _ := ecs.GetComponent[components.TransformComponent](s.World, id)
+		if zc == nil || trans == nil {
+			continue
+		}
+
+		m, ok := s.Maps[trans.Z]
+		if !ok {
+			continue
+		}
+
+		// Use the tile center, not the top-left corner
+		zone := m.ZoneAt(trans.X+32, trans.Y+32)
+		zoneID := ""
+		if zone != nil {
+			zoneID = zone.ID
+		}
+		s.players[levelZone{trans.Z, zoneID}]++
+		if zoneID == zc.ZoneID {
+			continue
+		}
+
+		zc.ZoneID = zoneID
+		ecs.AddComponent(s.World, id, *zc)
+		if s.OnZoneChange != nil {
+			s.OnZoneChange(id, zone)
+		}
+	}
+}
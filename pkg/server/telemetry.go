@@ -0,0 +1,68 @@
+package server
+
+import (
+	"log"
+	"sort"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+)
+
+// recordDeath counts a player's death, or a player's NPC kill, in the zone where it
+// happened. killer is 0 for deaths to traps. Assumes s.Mutex is LOCKED.
+func (s *GameServer) recordDeath(victim, killer ecs.Entity) {
+	if !s.TelemetrySystem.Enabled() {
+		return
+	}
+	zone := s.zoneName(victim)
+	if _, ok := s.Players[victim]; ok {
+		s.TelemetrySystem.RecordDeath(zone)
+	} else if _, ok := s.Players[killer]; ok {
+		s.TelemetrySystem.RecordKill(zone)
+	}
+}
+
+// zoneName is the zone an entity stands in ("" outside every zone). Assumes s.Mutex is LOCKED.
+func (s *GameServer) zoneName(id ecs.Entity) string {
+	trans, ok := ecs.GetComponent[components.TransformComponent](s.World, id)
+	if !ok {
+		return ""
+	}
+	m, ok := s.Maps[trans.Z]
+	if !ok {
+		return ""
+	}
+	if zone := m.ZoneAt(trans.X+32, trans.Y+32); zone != nil {
+		return zone.Name
+	}
+	return ""
+}
+
+// logTelemetry prints the counts collected since the last export. Assumes s.Mutex is LOCKED.
+func (s *GameServer) logTelemetry() {
+	if !s.TelemetrySystem.Enabled() {
+		log.Printf("Telemetry is off (start the server with -telemetry <seconds>)")
+		return
+	}
+	report := s.TelemetrySystem.Current()
+	log.Printf("Telemetry since %s: %d login(s), %d unique player(s), peak %d online",
+		report.Start.Format("15:04:05"), report.Logins, report.UniquePlayers, report.PeakOnline)
+	for _, group := range []struct {
+		name   string
+		counts map[string]int
+	}{{"Deaths", report.Deaths}, {"Kills", report.Kills}, {"Items", report.Items}, {"Spells", report.Spells}} {
+		keys := make([]string, 0, len(group.counts))
+		for key := range group.counts {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if group.counts[keys[i]] != group.counts[keys[j]] {
+				return group.counts[keys[i]] > group.counts[keys[j]]
+			}
+			return keys[i] < keys[j]
+		})
+		for _, key := range keys {
+			log.Printf("  %-7s %-24s %d", group.name, key, group.counts[key])
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"log"
+
+	"henry/pkg/shared/components"
+	"henry/pkg/shared/ecs"
+	protocol "henry/pkg/shared/network"
+)
+
+func (s *GameServer) handleWardrobeAction(player *Player, req protocol.WardrobeActionPacket) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	changes, err := s.Service.SetLook(player.EntityID, req.Slot, req.ItemID)
+	if err != nil {
+		log.Printf("Player %s failed to show %q in slot %d: %v", player.Username, req.ItemID, req.Slot, err)
+		s.Notify(player, "Cannot change look: "+err.Error())
+	}
+	s.applyChanges(player, changes)
+}
+
+// SendWardrobeSync sends the player's collected looks. Assumes s.Mutex is LOCKED.
+func (s *GameServer) SendWardrobeSync(player *Player) {
+	cosmetics, _ := ecs.GetComponent[components.CosmeticsComponent](s.World, player.EntityID)
+	if cosmetics == nil {
+		return
+	}
+	player.Send(protocol.Packet{
+		Type: protocol.PacketWardrobeSync,
+		Data: protocol.WardrobeSyncPacket{Unlocked: cosmetics.Unlocked, Looks: cosmetics.Looks},
+	})
+}
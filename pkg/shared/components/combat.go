@@ -1,8 +1,10 @@
 package components
 
 import (
-	"henry/pkg/shared/ecs"
 	"math"
+
+	"henry/pkg/shared/config"
+	"henry/pkg/shared/ecs"
 )
 
 type AttackType int
@@ -22,26 +24,61 @@ type AttackComponent struct {
 
 type ProjectileComponent struct {
 	OwnerID  ecs.Entity
+	Faction  int // Owner's faction when fired (owner may be gone by impact)
 	Damage   float64
 	Lifetime float64
+	Pierce   bool                // Keeps going after a hit (melee sweeps)
+	HitList  map[ecs.Entity]bool // Targets already hit (Pierce only)
+	OnHit    []StatusEffect      // Put on each target hit
+}
+
+// StatusEffectComponent holds the timed effects on an entity: stat buffs from spells and
+// elixirs, and the slow, burn, shield and invisible statuses. How a new effect combines
+// with one already there depends on its kind (see systems.AddStatusEffect).
+type StatusEffectComponent struct {
+	Effects []StatusEffect
+}
+
+type StatusEffect struct {
+	Kind   string     // A buffed stat (BuffDamage, ...) or a status (StatusSlow, ...)
+	Source string     // Spell or item ID
+	Caster ecs.Entity // Who put it on (credited with burn damage)
+	// Multiplier for buffs and slows, damage per second per stack for burns, damage left
+	// to soak up for shields
+	Amount   float64
+	Stacks   int
+	TimeLeft float64 // Seconds
+	Duration float64 // Full length (the client's icon timer)
+	Timer    float64 // Seconds to the next burn tick
+}
+
+// Attributes are the primary stats characters and gear have. An entity's own attributes
+// plus those of its equipped gear make the totals systems.Stats derives combat stats from.
+type Attributes struct {
+	Str   int // Melee damage
+	Dex   int // Ranged damage
+	Int   int // Spell power and spell cooldown reduction
+	Haste int // Attack speed, in percent
+}
+
+// Add returns the sum of two sets of attributes
+func (a Attributes) Add(b Attributes) Attributes {
+	return Attributes{Str: a.Str + b.Str, Dex: a.Dex + b.Dex, Int: a.Int + b.Int, Haste: a.Haste + b.Haste}
+}
+
+// CombatLevel converts combat XP to a level (1 at 0 XP, 2 at 50, 5 at 800), up to
+// config.MaxCombatLevel
+func CombatLevel(xp int) int {
+	return min(1+int(math.Sqrt(float64(xp)/50)), config.MaxCombatLevel)
+}
+
+// XPForLevel is the combat XP a level starts at (the inverse of CombatLevel)
+func XPForLevel(level int) int {
+	steps := min(max(level, 1), config.MaxCombatLevel) - 1
+	return 50 * steps * steps
 }
 
-// Simple Collision Check (Circle/Point)
-func CheckCollision(x1, y1, r1, x2, y2, r2 float64) bool {
-	dx := x2 - x1
-	dy := y2 - y1
-	distSq := dx*dx + dy*dy
-	radiusSum := r1 + r2
-	return distSq <= radiusSum*radiusSum
-}
-
-// Calculate direction vector
-func Direction(x1, y1, x2, y2 float64) (float64, float64) {
-	dx := x2 - x1
-	dy := y2 - y1
-	msg := math.Sqrt(dx*dx + dy*dy)
-	if msg == 0 {
-		return 0, 0
-	}
-	return dx / msg, dy / msg
+// AttributesComponent holds an entity's own attributes, gear not included
+type AttributesComponent struct {
+	Base Attributes
 }
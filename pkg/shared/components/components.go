@@ -1,8 +1,10 @@
 package components
 
 import (
+	"henry/pkg/behavior"
 	"henry/pkg/shared/ecs"
 	"image/color"
+	"math"
 )
 
 // TransformComponent holds position and rotation
@@ -17,6 +19,12 @@ type PhysicsComponent struct {
 	VelX, VelY float64
 	AccX, AccY float64
 	Speed      float64 // Max speed or movement speed
+
+	// Collision (see physics.go)
+	Layer int     // Layer* bit this body is on
+	Mask  int     // Layers this body collides with
+	Shape int     // ShapeAABB or ShapeCircle
+	Size  float64 // Box side / circle diameter (0 = DefaultColliderSize)
 }
 
 type SpriteComponent struct {
@@ -27,6 +35,25 @@ type SpriteComponent struct {
 	CharType string
 }
 
+// Projectile textures and the way their art points (radians, 0 = right, clockwise on screen)
+const (
+	TextureArrow    = "arrow"    // Points up-right
+	TextureFireball = "fireball" // Flies down-right, tail up-left
+	TextureSlash    = "slash"    // Crescent bulging right
+)
+
+var textureFacing = map[string]float64{
+	TextureArrow:    -math.Pi / 4,
+	TextureFireball: math.Pi / 4,
+	TextureSlash:    0,
+}
+
+// TextureRotation is the TransformComponent.Rotation that turns a projectile texture to
+// face along (dirX, dirY)
+func TextureRotation(texture string, dirX, dirY float64) float64 {
+	return math.Atan2(dirY, dirX) - textureFacing[texture]
+}
+
 // InputComponent holds the current input state for an entity
 type InputComponent struct {
 	Up, Down, Left, Right bool
@@ -34,7 +61,23 @@ type InputComponent struct {
 	HotbarTriggers        [10]bool
 	MouseX, MouseY        float64
 	ActiveSpell           string // ID of the currently selected combat spell
-	IsRunning             bool
+	Stance                string // Requested movement stance (StanceWalk if empty)
+	Dodge                 bool   // Held on the frame the dodge key is pressed
+}
+
+// Movement stances
+const (
+	StanceWalk  = "walk"
+	StanceRun   = "run"   // Faster, drains stamina
+	StanceSneak = "sneak" // Slower, halves NPC aggro range against you
+	StanceDodge = "dodge" // Mid dodge roll (not requestable, see InputComponent.Dodge)
+)
+
+// StanceComponent is the stance a player is actually moving in (a run falls back to a walk
+// when out of stamina) and their stamina. Sent in snapshots so clients animate the right stance.
+type StanceComponent struct {
+	Stance  string
+	Stamina float64
 }
 
 // ... (other components)
@@ -45,17 +88,27 @@ type SpellbookComponent struct {
 	Cooldowns      map[string]float64 // spellID -> lastCastTime (unix timestamp seconds)
 }
 
+// ItemCooldownComponent remembers when a player last used each consumable
+type ItemCooldownComponent struct {
+	LastUse map[string]float64 // itemID -> last use (unix timestamp seconds)
+}
+
 // StatsComponent holds gameplay stats
 type StatsComponent struct {
 	MaxHealth     float64
 	CurrentHealth float64
 	Damage        float64
+	InvulnTimer   float64 // Seconds of post-hit immunity left
+	Level         int     // Combat level (players; see CombatLevel)
+	XP            int     // Combat XP (players)
 }
 
 // InventorySlot represents a single slot in an inventory
 type InventorySlot struct {
-	ItemID   string
-	Quantity int
+	ItemID     string
+	Quantity   int
+	Locked     bool   // Locked items cannot be dropped, sold or traded
+	InstanceID string // Unique per stack (see items.NewInstanceID)
 }
 
 // InventoryComponent holds the items for an entity
@@ -64,6 +117,19 @@ type InventoryComponent struct {
 	Capacity int
 }
 
+// WalletComponent holds a player's gold. Gold coins picked up or received go here
+// instead of taking up a bag slot (see systems.AddGold).
+type WalletComponent struct {
+	Gold int
+}
+
+// KeyringComponent holds a player's keys and quest items, one stack per item, apart from
+// their bag: they take no bag slots and can't be dropped, sold, mailed or traded
+// (see items.OnKeyring).
+type KeyringComponent struct {
+	Items []InventorySlot
+}
+
 // HotbarSlot represents a reference in the hotbar
 type HotbarSlot struct {
 	Type  string // "Item", "Spell", etc.
@@ -90,7 +156,8 @@ const (
 
 // EquipmentSlot represents a single worn item
 type EquipmentSlot struct {
-	ItemID string
+	ItemID     string
+	InstanceID string
 }
 
 // EquipmentComponent holds worn items
@@ -98,10 +165,61 @@ type EquipmentComponent struct {
 	Slots [9]EquipmentSlot
 }
 
+// CosmeticsComponent is a player's wardrobe: the looks they've collected and the one
+// shown in each equipment slot over the worn item (transmog). Stats always come from the
+// EquipmentComponent.
+type CosmeticsComponent struct {
+	Unlocked []string  // Item IDs
+	Looks    [9]string // Per equipment slot, "" = show the worn item
+}
+
+// AppearanceComponent is what an entity is seen wearing: the item ID shown in each
+// equipment slot (see systems.Appearance)
+type AppearanceComponent struct {
+	Slots [9]string
+}
+
+// ScheduleEntry sets an NPC activity for a span of the day
+type ScheduleEntry struct {
+	Start    float64 `json:"start"`    // Hour (0-24)
+	End      float64 `json:"end"`      // Hour, End < Start wraps past midnight
+	Activity string  `json:"activity"` // "patrol", "post", "trade"
+}
+
+// ActivityAt returns the scheduled activity for an hour ("" if unscheduled)
+func ActivityAt(schedule []ScheduleEntry, hour float64) string {
+	for _, e := range schedule {
+		if e.Start <= e.End {
+			if hour >= e.Start && hour < e.End {
+				return e.Activity
+			}
+		} else if hour >= e.Start || hour < e.End {
+			return e.Activity
+		}
+	}
+	return ""
+}
+
+// Factions
+const (
+	FactionPlayer   = 0
+	FactionGuards   = 1
+	FactionMonsters = 2
+	FactionNeutral  = 3 // Training dummies: anyone may hit them, they fight nobody
+)
+
+// IsHostile reports whether two factions fight on sight (monsters vs everyone else, never neutrals)
+func IsHostile(a, b int) bool {
+	if a == FactionNeutral || b == FactionNeutral {
+		return false
+	}
+	return a != b && (a == FactionMonsters || b == FactionMonsters)
+}
+
 // AIComponent holds state for NPC behavior
 type AIComponent struct {
 	Type           string     // "wander"
-	State          string     // "idle", "move", "chase", "attack"
+	State          string     // What its behavior tree is doing: "idle", "move", "patrol", "chase", "attack", "kite", "flee", "return"
 	StateTimer     float64    // Seconds remaining in current state
 	MoveDirection  int        // 0:Up, 1:Down, 2:Left, 3:Right
 	TargetID       ecs.Entity // Entity to attack
@@ -111,14 +229,85 @@ type AIComponent struct {
 	PathTimer      float64
 	SpawnX, SpawnY float64
 	LeashRange     float64
+	FleeThreshold  float64 // Health fraction below which the NPC flees (0 = never)
+	HelpRadius     float64 // Same-faction NPCs within this radius join the fight
+	HasFled        bool    // Flee only once per engagement
+	FleeX, FleeY   float64 // Flee destination (ally or spawn)
+	Schedule       []ScheduleEntry
+	Activity       string        // Current scheduled activity
+	AggroRange     float64       // Aggressive NPCs engage hostiles within this radius
+	Behavior       behavior.Node // Decision tree run by the AISystem (zero = behavior.Default)
+	Patrol         [][2]float64  // Route walked in a loop instead of wandering (world px, nil = wander)
+	PatrolIndex    int           // Patrol point it's walking to
+}
+
+// Home is where the NPC belongs when it isn't fighting: the patrol point it was walking
+// to, or its spawn point. The leash is measured from it and returning NPCs walk to it.
+func (ai *AIComponent) Home() (x, y float64) {
+	if len(ai.Patrol) > 0 {
+		p := ai.Patrol[ai.PatrolIndex%len(ai.Patrol)]
+		return p[0], p[1]
+	}
+	return ai.SpawnX, ai.SpawnY
+}
+
+// GroundItemComponent marks an entity as an item lying in the world
+type GroundItemComponent struct {
+	ItemID     string
+	InstanceID string // Kept from the inventory it was dropped from
+	Quantity   int
+	OwnerID    ecs.Entity // Only the owner may pick it up while OwnerTimer > 0
+	OwnerTimer float64    // Seconds of owner-only pickup left
+	Lifetime   float64    // Seconds until the item despawns
+}
+
+// LootEntry is one possible drop of a character's loot table
+type LootEntry struct {
+	ItemID   string
+	Quantity int
+	Chance   float64 // Probability (0-1) the entry drops
+}
+
+// LootComponent gives bosses/elites a loot table. Drops are rolled for (need/greed)
+// among the players who damaged them.
+type LootComponent struct {
+	Table []LootEntry
+}
+
+// AutoMoveComponent steers a player along a server-computed path (click-to-move / follow)
+type AutoMoveComponent struct {
+	Path      [][]float64
+	FollowID  ecs.Entity // If set, keep trailing this entity
+	PathTimer float64    // Seconds until the follow path is refreshed
 }
 
-// RespawnComponent handles entity death and respawning
+// DeadComponent marks an NPC that died and lies where it fell: AI, movement and collisions
+// pass it by until the DeathSystem despawns it, CorpseTimer seconds later
+type DeadComponent struct {
+	Killer      ecs.Entity // Landed the last hit (0 for the world)
+	CorpseTimer float64
+}
+
+// RespawnComponent makes a spawner NPC come back after dying. While it waits (IsDead) the
+// entity holds nothing else.
 type RespawnComponent struct {
 	CharID         string // NPC Type ID (e.g. "guard_melee")
 	SpawnX, SpawnY float64
+	SpawnZ         int // Level
 	RespawnTimer   float64
 	IsDead         bool
+	Schedule       []ScheduleEntry // Spawner schedule override (nil = use character definition)
+	Patrol         [][2]float64    // Spawner patrol route (world px, nil = the character's)
+	RespawnMin     float64         // Spawner respawn range in seconds, fastest with a crowd hunting...
+	RespawnMax     float64         // ...slowest with nobody around (0 = around the character's time)
+	RespawnDelay   float64         // The full wait RespawnTimer counts down from at the current player count
+}
+
+// SpawnRegionComponent marks an NPC a map spawn region keeps topped up. It doesn't
+// respawn itself; the region spawns a fresh one when it's gone.
+type SpawnRegionComponent struct {
+	Region string // "<level>/<region ID>"
+	CharID string
 }
 
 // UIStateComponent holds persistent UI visibility state
@@ -126,7 +315,140 @@ type UIStateComponent struct {
 	OpenMenus map[string]bool
 }
 
+// Entity tags (see ecs.TagComponent)
+const (
+	TagPlayer ecs.Tag = 1 << iota
+	TagNPC
+	TagProjectile
+	TagGroundItem
+	TagWaypoint
+	TagStructure
+)
+
+// NameComponent holds a display/debug name
+type NameComponent struct {
+	Name string
+}
+
+// Overhead marker flags (bitmask)
+const (
+	MarkerQuestAvailable = 1 << iota // "!" above quest givers
+	MarkerQuestTurnIn                // "?" above quest turn-in NPCs
+	MarkerVendor                     // Coin icon above vendors
+	MarkerArena                      // Crossed swords above arena masters (queue here)
+	MarkerHouse                      // House above the Housing Steward (deeds and visits)
+)
+
+// MarkerComponent holds overhead indicator flags for NPCs
+type MarkerComponent struct {
+	Flags int
+}
+
+// InteractableComponent lets players talk to an NPC (see systems.DialogueSystem)
+type InteractableComponent struct {
+	Dialogue string // Dialogue tree ID (data/dialogues)
+}
+
+// ShopItem is one line of a vendor's stock
+type ShopItem struct {
+	ItemID string
+	Price  int // Gold each (0 = the economy's buy price)
+}
+
+// ShopComponent makes an NPC a vendor players buy from and sell to (see service.ShopBuy)
+type ShopComponent struct {
+	Name  string
+	Stock []ShopItem
+}
+
 // KeybindingsComponent holds per-player key mapping
 type KeybindingsComponent struct {
 	Bindings map[string]int
 }
+
+// ZoneComponent tracks which named map zone an entity is standing in ("" = wilderness)
+type ZoneComponent struct {
+	ZoneID string
+}
+
+// WaypointComponent marks a fast travel waypoint object
+type WaypointComponent struct {
+	WaypointID string
+	Name       string
+}
+
+// SpectatorComponent marks a player watching instead of playing: invisible to everyone
+// else, not collidable or targetable, and glued to TargetID (0 = free-flying camera)
+type SpectatorComponent struct {
+	TargetID  ecs.Entity
+	ArenaOnly bool // Arena viewer (not a GM): may only watch arena instances
+	ReturnX   float64
+	ReturnY   float64
+	ReturnZ   int
+}
+
+// StructureComponent marks a player-built object placed from an inventory item
+type StructureComponent struct {
+	ItemID string
+	Owner  string // Builder's username (structures outlive sessions)
+	Solid  bool   // Blocks movement
+	Claim  bool   // Claims the land within ClaimRadius for Owner
+}
+
+// DifficultyComponent holds the level an NPC spawned at and the zone and level scaling
+// applied to it
+type DifficultyComponent struct {
+	Level       int
+	HealthScale float64 // Already applied to its max health
+	DamageScale float64 // Applied to the damage it deals
+	XP          int     // Combat XP for the player who kills it
+}
+
+// SkillsComponent holds a player's skill experience, gathering and combat (skill ID -> XP)
+type SkillsComponent struct {
+	XP map[string]int
+}
+
+// PlaytimeComponent holds a player's active (non-AFK) playtime in seconds
+type PlaytimeComponent struct {
+	Seconds float64
+}
+
+// CutscenesComponent holds the Once cutscenes a player has seen
+type CutscenesComponent struct {
+	Seen []string
+}
+
+// TravelComponent holds the waypoints a player has discovered
+type TravelComponent struct {
+	UnlockedWaypoints []string
+}
+
+// TriggerComponent is a non-solid area that fires when players enter or leave it
+type TriggerComponent struct {
+	TriggerID     string
+	Width, Height float64
+	Action        string  // "message", "damage", "teleport", "house", "cutscene"
+	Message       string  // Shown to the player on enter
+	Damage        float64 // "damage" (trap tiles)
+	TargetX       float64 // "teleport" destination
+	TargetY       float64
+	TargetZ       int
+	Cutscene      string              // "cutscene" to play
+	Once          bool                // Fire only the first time per player
+	Occupants     map[ecs.Entity]bool // Players currently inside
+	Fired         map[ecs.Entity]bool // Players that already triggered a Once trigger
+}
+
+// TrainingDummyComponent aggregates damage taken per attacker instead of losing health
+type TrainingDummyComponent struct {
+	Sessions map[ecs.Entity]*DummySession
+}
+
+// DummySession is one attacker's ongoing damage run against a dummy
+type DummySession struct {
+	Damage  float64
+	Hits    int
+	Start   float64 // Seconds (dummy system clock)
+	LastHit float64
+}
@@ -0,0 +1,28 @@
+package components
+
+import "henry/pkg/shared/ecs"
+
+// Gameplay events published on the ecs.World (ecs.Publish) for other systems to react to
+
+// DamageEvent is a hit that took Dealt health off Target (Amount before clamping to what
+// it had left). Attacker is 0 for the world. Killed hits are followed by a DeathEvent.
+type DamageEvent struct {
+	Attacker, Target ecs.Entity
+	Amount, Dealt    float64
+	Killed           bool
+}
+
+// DeathEvent is Victim's health reaching zero. Killer landed the last hit (0 for the
+// world). The server's handler despawning the victim subscribes last, so the others
+// still find it where it fell.
+type DeathEvent struct {
+	Killer, Victim ecs.Entity
+}
+
+// PickupEvent is a player picking up a ground item (gold included)
+type PickupEvent struct {
+	Player     ecs.Entity
+	ItemID     string
+	InstanceID string
+	Quantity   int
+}
@@ -0,0 +1,54 @@
+package components
+
+import "henry/pkg/shared/geom"
+
+// Collision layers (bitmask). A body collides with another when its Mask contains the other's Layer.
+const (
+	LayerPlayer = 1 << iota
+	LayerNPC
+	LayerProjectile
+	LayerTrigger
+	LayerWall // Map tiles and objects
+)
+
+// Collider shapes
+const (
+	ShapeAABB = iota
+	ShapeCircle
+)
+
+// DefaultColliderSize is the body size (px) used when PhysicsComponent.Size is 0
+const DefaultColliderSize = 24.0
+
+// Preset bodies
+const (
+	MaskCharacter  = LayerPlayer | LayerNPC | LayerWall
+	MaskProjectile = LayerPlayer | LayerNPC
+)
+
+// ColliderBounds returns the top-left corner and size of a body's collider,
+// centered in a cell of cellSize (usually the tile) whose top-left is at x, y.
+func ColliderBounds(x, y float64, phys *PhysicsComponent, cellSize float64) (float64, float64, float64) {
+	size := phys.Size
+	if size <= 0 {
+		size = DefaultColliderSize
+	}
+	offset := (cellSize - size) / 2.0
+	return x + offset, y + offset, size
+}
+
+// CollidersOverlap tests two colliders given their bounds (top-left + size) and shapes.
+// Circles use size as their diameter.
+func CollidersOverlap(ax, ay, aSize float64, aShape int, bx, by, bSize float64, bShape int) bool {
+	a, b := geom.Square(ax, ay, aSize), geom.Square(bx, by, bSize)
+	switch {
+	case aShape == ShapeCircle && bShape == ShapeCircle:
+		return geom.Inscribed(a).Overlaps(geom.Inscribed(b))
+	case aShape == ShapeCircle:
+		return geom.Inscribed(a).OverlapsAABB(b)
+	case bShape == ShapeCircle:
+		return geom.Inscribed(b).OverlapsAABB(a)
+	default:
+		return a.Overlaps(b)
+	}
+}
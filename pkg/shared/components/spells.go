@@ -3,25 +3,70 @@ package components
 import "image/color"
 
 type Spell struct {
-	ID          string // Unique ID (e.g. "fireball")
-	Name        string // Display Name
-	Description string // Tooltip text
-	Color       color.RGBA
-	Icon        string  // Placeholder for icon ref if needed later
-	CastTime    float64 // Seconds
-	Cooldown    float64 // Seconds
-	Type        string  // "combat", "instant"
+	ID          string        `json:"id"`          // Unique ID (e.g. "fireball")
+	Name        string        `json:"name"`        // Display Name
+	Description string        `json:"description"` // Tooltip text
+	Color       color.RGBA    `json:"color"`
+	Icon        string        `json:"icon"`      // Placeholder for icon ref if needed later
+	CastTime    float64       `json:"cast_time"` // Seconds
+	Cooldown    float64       `json:"cooldown"`  // Seconds
+	Type        string        `json:"type"`      // "combat", "instant"
+	Effects     []SpellEffect `json:"effects"`   // Applied in order on cast
 }
 
+// Spell effect primitives
+const (
+	EffectProjectile = "projectile" // Fires toward the target: Amount damage, Speed, Lifetime, Size, Texture
+	EffectHeal       = "heal"       // Heals the caster by Amount
+	EffectTeleport   = "teleport"   // Moves the caster Amount pixels toward the target
+	EffectBuff       = "buff"       // Multiplies the caster's Stat by Amount for Duration seconds
+	EffectStatus     = "status"     // Puts the Stat status on the caster (or, with OnHit, on whoever the spell's projectiles hit)
+)
+
+// Buffable stats
+const (
+	BuffDamage      = "damage"       // Damage dealt
+	BuffDamageTaken = "damage_taken" // Damage received
+	BuffSpeed       = "speed"        // Movement speed
+)
+
+// Statuses
+const (
+	StatusSlow      = "slow"      // Movement speed x Amount; only the strongest slow counts
+	StatusBurn      = "burn"      // Amount damage a second per stack; reapplying adds a stack
+	StatusShield    = "shield"    // Soaks up to Amount damage; reapplying keeps the larger shield
+	StatusInvisible = "invisible" // Unseen by other players and NPCs; ends on attacking
+)
+
+// SpellEffect is one step of a spell. Which fields are used depends on Type.
+type SpellEffect struct {
+	Type     string  `json:"type"`
+	Amount   float64 `json:"amount"`
+	Speed    float64 `json:"speed,omitempty"`    // Projectile pixels per tick
+	Lifetime float64 `json:"lifetime,omitempty"` // Projectile ticks
+	Size     float64 `json:"size,omitempty"`     // Projectile collider (the sprite is 2px larger)
+	Texture  string  `json:"texture,omitempty"`  // Projectile sprite
+	Stat     string  `json:"stat,omitempty"`     // Buffed stat or status
+	Duration float64 `json:"duration,omitempty"` // Buff or status seconds
+	OnHit    bool    `json:"on_hit,omitempty"`   // Status goes on the projectiles' targets
+}
+
+// Built-in spells. The server replaces and adds to these from data/spells and sends the
+// result to clients at login.
+
 var SpellRegistry = map[string]Spell{
 	"fireball": {
 		ID:          "fireball",
 		Name:        "Fireball",
-		Description: "Launches a fiery ball dealing damage.",
+		Description: "Launches a fiery ball dealing damage and setting the target alight.",
 		Color:       color.RGBA{255, 100, 50, 255}, // Orange/Red
 		Icon:        "fireball",
 		Cooldown:    2.0,
 		Type:        "combat",
+		Effects: []SpellEffect{
+			{Type: EffectProjectile, Amount: 25, Speed: 12, Lifetime: 60, Size: 10, Texture: TextureFireball},
+			{Type: EffectStatus, Stat: StatusBurn, Amount: 3, Duration: 4, OnHit: true},
+		},
 	},
 	"heal": {
 		ID:          "heal",
@@ -30,6 +75,7 @@ var SpellRegistry = map[string]Spell{
 		Color:       color.RGBA{100, 255, 100, 255}, // Green
 		Cooldown:    5.0,
 		Type:        "instant",
+		Effects:     []SpellEffect{{Type: EffectHeal, Amount: 20}},
 	},
 	"blink": {
 		ID:          "blink",
@@ -38,22 +84,25 @@ var SpellRegistry = map[string]Spell{
 		Color:       color.RGBA{100, 100, 255, 255}, // Blue
 		Cooldown:    8.0,
 		Type:        "instant",
+		Effects:     []SpellEffect{{Type: EffectTeleport, Amount: 100}},
 	},
 	"shield": {
 		ID:          "shield",
 		Name:        "Mana Shield",
-		Description: "Absorbs damage using mana.",
+		Description: "Absorbs the next 40 damage you take within 8 seconds.",
 		Color:       color.RGBA{200, 200, 255, 255}, // Light Blue
 		Cooldown:    15.0,
 		Type:        "instant",
+		Effects:     []SpellEffect{{Type: EffectStatus, Stat: StatusShield, Amount: 40, Duration: 8}},
 	},
 	"void": {
 		ID:          "void",
 		Name:        "Void Walk",
-		Description: "Become invisible for a short time.",
+		Description: "Become invisible for 6 seconds, or until you attack.",
 		Color:       color.RGBA{100, 0, 100, 255}, // Purple
 		Cooldown:    20.0,
 		Type:        "instant",
+		Effects:     []SpellEffect{{Type: EffectStatus, Stat: StatusInvisible, Duration: 6}},
 	},
 }
 
@@ -65,3 +114,23 @@ var SpellList = []string{
 	"shield",
 	"void",
 }
+
+// RegisterSpells adds spells to the registry, replacing built-ins with the same ID.
+// New spells go at the end of SpellList.
+func RegisterSpells(spells []Spell) {
+	for _, spell := range spells {
+		if _, exists := SpellRegistry[spell.ID]; !exists {
+			SpellList = append(SpellList, spell.ID)
+		}
+		SpellRegistry[spell.ID] = spell
+	}
+}
+
+// Spells lists the registry in SpellList order
+func Spells() []Spell {
+	spells := make([]Spell, 0, len(SpellList))
+	for _, id := range SpellList {
+		spells = append(spells, SpellRegistry[id])
+	}
+	return spells
+}
@@ -1,6 +1,9 @@
 package config
 
 const (
+	// Build
+	GameVersion = "0.1.0"
+
 	// Screen Dimensions
 	ScreenWidth  = 640
 	ScreenHeight = 480
@@ -15,13 +18,157 @@ const (
 	ActionLeft      = "Left"
 	ActionRight     = "Right"
 	ActionRun       = "Run"
+	ActionSneak     = "Sneak"
+	ActionDodge     = "Dodge"
 	ActionAttack    = "Attack"
 	ActionWeapon1   = "Weapon1"
 	ActionWeapon2   = "Weapon2"
 	ActionInventory = "Inventory"
 	ActionMenu      = "Menu"
+	ActionMusic     = "Music"
+	ActionInteract  = "Interact"
+
+	// Maps
+	MapDir             = "data/maps" // Default directory for level_*.json
+	DefaultMapSize     = 128         // Generated fallback map, in tiles per side
+	DefaultMapSpawners = 60
+	DefaultMapSeed     = 1
+	PlayerSpawnX       = 100.0 // New characters start here on level 0 (world px)
+	PlayerSpawnY       = 100.0
+
+	// World Clock
+	DayLengthSeconds = 1200.0 // 20 real minutes per in-game day
+	StartHour        = 8.0
+
+	// Persistence
+	AutosaveInterval      = 60.0  // Seconds between saves of changed players
+	NPCCheckpointInterval = 30.0  // Seconds between NPC state checkpoints (when enabled)
+	NPCStateMaxAge        = 600.0 // Older NPC checkpoints are ignored on startup
+
+	// Collision
+	ShorePushRange = 256.0 // Max distance (px) an entity stuck in water or walls is pushed out
+	TerrainSpeed   = true  // Tile move costs (world.TileType.MoveCost) also scale walking speed
+
+	// Click-to-move
+	MoveToCooldown = 0.25 // Seconds between click-to-move requests per player (each is a path search)
+
+	// Stances
+	RunSpeedMultiplier   = 2.0
+	SneakSpeedMultiplier = 0.5
+	SneakAggroMultiplier = 0.5 // NPC aggro range against sneaking players
+	MaxStamina           = 100.0
+	StaminaDrain         = 10.0 // Per second spent running
+	StaminaRegen         = 15.0 // Per second not running or dodging
+	StaminaToRun         = 25.0 // Needed to start running again once out of breath
+	DodgeStaminaCost     = 30.0
+	DodgeDuration        = 0.3 // Seconds of speed burst, invulnerable throughout
+	DodgeSpeedMultiplier = 3.0
+
+	// NPC Spawning
+	NPCRespawnSeconds  = 30.0 // Seconds before a slain spawner NPC comes back (characters can set their own)
+	CorpseSeconds      = 2.0  // Seconds a slain NPC lies where it fell before it's despawned
+	RespawnMinScale    = 0.5  // Default respawn range around that time: fastest with a crowd hunting...
+	RespawnMaxScale    = 1.5  // ...slowest with nobody around (one player gets the time itself)
+	RespawnBusyPlayers = 5    // Players in a zone for the fastest respawns
+
+	// Zone Difficulty
+	NPCLevelStep        = 0.1    // Health, damage and XP change per NPC level above (or below) its character's own
+	NPCLevelScaleRadius = 1200.0 // Players within this many px of a spawn set the level in level-scaled zones
+	MaxCombatLevel      = 30     // Combat XP keeps counting past it, the level doesn't
+
+	// Player Progression (per combat level above 1)
+	PlayerBaseHealth   = 100.0 // Max health at level 1
+	HealthPerLevel     = 10.0
+	LevelsPerAttribute = 3 // Levels between each +1 STR, DEX and INT
+
+	// Attributes
+	PlayerBaseAttribute  = 5    // STR, DEX and INT of every player before gear
+	DamagePerAttribute   = 0.02 // Melee (STR), ranged (DEX) and spell (INT) damage per point
+	CDRPerInt            = 0.01 // Spell cooldown taken off per INT point
+	MaxCooldownReduction = 0.4
+	MaxHaste             = 100 // Percent; attacks at most twice as fast
+
+	// AFK
+	AFKTimeout = 300.0 // Seconds without input before a player counts as AFK (playtime pauses)
+
+	// Fast Travel
+	WaypointTravelFee = 10 // Gold coins per teleport
+
+	// Ground Loot
+	GroundItemPickupDist = 96.0 // Max distance (px) between a player's center and an item they pick up
+	AutoPickupRetry      = 1.0  // Seconds before the loot filter asks for the same item again
+
+	// Building
+	BuildRange         = 192.0 // Max distance (px) between a builder and the tile they build on
+	ClaimRadius        = 384.0 // Land (px) around a claim banner only its owner may build on
+	MaxClaimsPerPlayer = 1
+
+	// Housing
+	HouseDeedPrice    = 500 // Gold the Housing Steward charges for a deed
+	HouseWidth        = 12  // Interior size in tiles, walls included
+	HouseHeight       = 10
+	HouseMaxFurniture = 40    // Pieces placed per house
+	HouseMaxGuests    = 20    // Usernames on a house's guest list
+	HouseStewardRange = 200.0 // Max distance (px) from the Housing Steward to buy a deed or visit
+
+	// Music
+	CombatLingerTime = 5.0 // Seconds a player stays in combat (music) after the last hostile gives up
+	MusicFadeTime    = 2.0 // Seconds to crossfade between tracks
+	MusicVolume      = 0.5
+	MusicSampleRate  = 44100
+
+	// Cutscenes
+	CutsceneMaxDuration = 20.0 // Seconds a cutscene may hold a player's input, however long its shots
+
+	// Bug Reports
+	BugReportLogLines = 50   // Client log lines attached to a report
+	BugReportCooldown = 30.0 // Seconds between reports per player
+
+	// Spawn Limits
+	MaxWorldEntities       = 4000 // Hard cap on live entities
+	MaxProjectilesPerOwner = 12   // Live projectiles/slashes per attacker
+	MaxProjectilesPerTick  = 64   // Projectile spawns per tick, world-wide
 
 	// Network
-	ServerPortTCP = ":8080"
-	ServerPortWS  = ":8081"
+	ServerTickRate = 30 // Simulation ticks per second
+	SnapshotRate   = 15 // State broadcasts per second (clients interpolate between them)
+	ServerPortTCP  = ":8080"
+	ServerPortWS   = ":8081"
+
+	MoveCorrectionInterval = 0.25 // Min seconds between movement corrections sent to a player
+	CorrectionBlendTime    = 0.15 // Seconds the client eases a corrected player onto the server's position
+	PredictionLead         = 0.1  // Seconds of held movement the client draws its player ahead by
+	PingInterval           = 1.0  // Seconds between the client's round trip pings
+
+	MaxConnectionsPerIP = 4 // Simultaneous connections (logged in or not) from one address
+
+	// Packets waiting to be written to one client; a client that falls this far behind is dropped
+	PlayerSendQueue = 512
+	// Received packets waiting for the simulation; readers block (and stop reading) while it's full
+	CommandQueue = 2048
+	// Seconds a disconnecting client gets to receive what's still queued for it
+	DisconnectFlushTimeout = 2.0
+
+	// Failed logins allowed per LoginFailureWindow before further attempts are refused
+	LoginFailuresPerAccount = 5
+	LoginFailuresPerAddress = 20
+	LoginFailureWindow      = 900.0 // Seconds
+
+	// Chat
+	ChatMaxLength         = 200    // Runes per message, longer ones are cut
+	ChatLocalRange        = 1280.0 // Px around the speaker that hear local chat (20 tiles)
+	ChatMessagesPerWindow = 5      // Sending more within ChatRateWindow mutes the sender
+	ChatRateWindow        = 10.0   // Seconds
+	ChatStrikesToMute     = 3      // Filtered messages within ChatStrikeWindow that mute the sender
+	ChatStrikeWindow      = 60.0   // Seconds
+	ChatMuteSeconds       = 30
+	ChatGlobalSlowMode    = 0.0 // Seconds a player waits between global messages (0 = off; console "chat slow")
+	ChatLogLines          = 500 // Lines per channel the server keeps for moderators (console "chat")
+	ChatHistoryLines      = 100 // Lines kept by the client's chat window
+	ChatBubbleMaxLength   = 60  // Runes; longer messages only go to the chat window
+	ChatBubbleSeconds     = 5.0 // Speech bubbles above the speaker fade after this long
+
+	// Login Queue
+	MaxPlayers               = 100 // Players online at once, later logins wait in a queue
+	LoginQueueUpdateInterval = 2.0 // Seconds between queue position updates
 )
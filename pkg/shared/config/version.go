@@ -0,0 +1,14 @@
+package config
+
+// Stamped into release builds by cmd/build, e.g.
+//
+//	-ldflags "-X henry/pkg/shared/config.Version=0.2.0 -X henry/pkg/shared/config.Commit=3f2a9c1"
+var (
+	Version = GameVersion // Release
+	Commit  = "dev"       // Source revision ("dev" for plain go build)
+)
+
+// BuildString names this build for people, e.g. "0.2.0 (3f2a9c1)"
+func BuildString() string {
+	return Version + " (" + Commit + ")"
+}
@@ -2,6 +2,7 @@ package ecs
 
 import (
 	"reflect"
+	"slices"
 	"sync/atomic"
 )
 
@@ -19,15 +20,25 @@ type Component interface{}
 // World manages entities and their components.
 type World struct {
 	nextEntityID uint64
-	// components maps ComponentType -> EntityID -> Component
-	components map[reflect.Type]map[Entity]Component
-	systems    []System
+	// stores maps ComponentType -> dense store of that type (a *store[T])
+	stores  map[reflect.Type]anyStore
+	systems []System
+
+	// Change tracking: tick of the last component change
+	tick    uint64
+	changed map[Entity]uint64
+
+	// Events (see events.go): subscribers by event type, and deliveries not yet made
+	handlers map[reflect.Type]any
+	events   []func()
 }
 
 func NewWorld() *World {
 	return &World{
-		components: make(map[reflect.Type]map[Entity]Component),
-		systems:    make([]System, 0),
+		stores:   make(map[reflect.Type]anyStore),
+		systems:  make([]System, 0),
+		changed:  make(map[Entity]uint64),
+		handlers: make(map[reflect.Type]any),
 	}
 }
 
@@ -39,68 +50,254 @@ func (w *World) NewEntity() Entity {
 
 // RemoveEntity removes all components associated with an entity.
 func (w *World) RemoveEntity(e Entity) {
-	for _, store := range w.components {
-		delete(store, e)
+	for _, s := range w.stores {
+		s.remove(e)
+		delete(s.changes(), e)
 	}
+	delete(w.changed, e)
 }
 
-// AddComponent attaches a component to an entity.
-func (w *World) AddComponent(e Entity, c Component) {
-	cType := reflect.TypeOf(c)
-	if _, ok := w.components[cType]; !ok {
-		w.components[cType] = make(map[Entity]Component)
+// storeOf returns the store of T, creating it when create is set (nil otherwise).
+func storeOf[T Component](w *World, create bool) *store[T] {
+	cType := reflect.TypeFor[T]()
+	if s, ok := w.stores[cType]; ok {
+		return s.(*store[T])
+	}
+	if !create {
+		return nil
 	}
-	w.components[cType][e] = c
+	if cType.Kind() == reflect.Interface {
+		panic("ecs: component type " + cType.String() + " is an interface, add the concrete value")
+	}
+	s := newStore[T]()
+	w.stores[cType] = s
+	return s
 }
 
-// RemoveComponent removes a component of type T from an entity.
-// It uses reflection on the passed 'c' argument to determine the type.
-func (w *World) RemoveComponent(e Entity, c Component) {
-	cType := reflect.TypeOf(c)
-	if store, ok := w.components[cType]; ok {
-		delete(store, e)
+// AddComponent attaches a component to an entity, replacing its current one of that type.
+// The value is stored unboxed in its type's dense store.
+func AddComponent[T Component](w *World, e Entity, c T) {
+	s := storeOf[T](w, true)
+	// Writing back an identical value (the usual Get -> modify -> Add pattern) is not a change
+	if s.put(e, c) {
+		w.markChanged(e, s.changed)
 	}
 }
 
-// GetComponent retrieves a component of type T for an entity.
-// It uses a helper function concept since Go generics don't allow method type parameters easily on struct methods yet in this way without wrapper.
-// But we can make a Generic helper function outside or use reflection wrapper.
-// Let's use a helper method that takes a pointer to the component type for reflection key,
-// OR we can make this generic if we use a free function or if we are on Go 1.18+.
-// Go 1.18+ supports generics.
+// RemoveComponent removes the component of type T from an entity.
+func RemoveComponent[T Component](w *World, e Entity) {
+	if s := storeOf[T](w, false); s != nil && s.remove(e) {
+		w.markChanged(e, s.changed)
+	}
+}
+
+func (w *World) markChanged(e Entity, changed map[Entity]uint64) {
+	changed[e] = w.tick
+	w.changed[e] = w.tick
+}
+
+// Tick returns the current change-tracking tick (advanced by Update).
+func (w *World) Tick() uint64 {
+	return w.tick
+}
+
+// EntityChangedSince reports whether any component of e was added, modified or removed at or after tick.
+func (w *World) EntityChangedSince(e Entity, tick uint64) bool {
+	t, ok := w.changed[e]
+	return ok && t >= tick
+}
+
+// GetComponent retrieves a copy of an entity's component of type T. Changes to it only
+// take effect (and count as changes) once written back with AddComponent.
 func GetComponent[T Component](w *World, e Entity) (*T, bool) {
-	var zero T
-	cType := reflect.TypeOf(zero)
-	if store, ok := w.components[cType]; ok {
-		if val, ok := store[e]; ok {
-			castVal := val.(T)
-			return &castVal, true
+	if s := storeOf[T](w, false); s != nil {
+		if c, ok := s.get(e); ok {
+			return &c, true
 		}
 	}
 	return nil, false
 }
 
+// HasComponent reports whether an entity has a component of type T.
+func HasComponent[T Component](w *World, e Entity) bool {
+	s := storeOf[T](w, false)
+	return s != nil && s.has(e)
+}
+
 // AddSystem adds a system to the world.
 func (w *World) AddSystem(s System) {
 	w.systems = append(w.systems, s)
 }
 
-// Update updates all systems.
+// Update updates all systems, delivering the events each publishes once it's done.
 func (w *World) Update(dt float64) {
 	for _, system := range w.systems {
 		system.Update(dt)
+		w.DispatchEvents()
 	}
+	w.DispatchEvents()
+	w.tick++
 }
 
-// Query returns all entities that have a specific component type.
-func Query[T Component](w *World) []Entity {
-	var zero T
-	cType := reflect.TypeOf(zero)
+// Query returns all entities that have a specific component type, in storage order
+// (the same for the same sequence of adds and removes). The slice is the caller's, so
+// components can be added and removed while ranging over it.
+func Query[T Component](w *World, filters ...Filter) []Entity {
+	if len(filters) > 0 {
+		return queryAll(w, []reflect.Type{reflect.TypeFor[T]()}, filters)
+	}
+	s := storeOf[T](w, false)
+	if s == nil || s.size() == 0 {
+		return nil
+	}
+	return slices.Clone(s.entities)
+}
+
+// Query2 returns the entities that have both an A and a B.
+func Query2[A, B Component](w *World, filters ...Filter) []Entity {
+	return queryAll(w, []reflect.Type{reflect.TypeFor[A](), reflect.TypeFor[B]()}, filters)
+}
+
+// Query3 returns the entities that have an A, a B and a C.
+func Query3[A, B, C Component](w *World, filters ...Filter) []Entity {
+	return queryAll(w, []reflect.Type{reflect.TypeFor[A](), reflect.TypeFor[B](), reflect.TypeFor[C]()}, filters)
+}
+
+// Filter narrows a query down to entities that also have, or don't have, a component
+// it doesn't fetch (see With and Without).
+type Filter struct {
+	t       reflect.Type
+	exclude bool
+}
+
+// With keeps the entities that also have a T, e.g. Query[AIComponent](w, With[StatsComponent]())
+func With[T Component]() Filter {
+	return Filter{t: reflect.TypeFor[T]()}
+}
+
+// Without drops the entities that have a T, e.g. Query[PhysicsComponent](w, Without[ProjectileComponent]())
+func Without[T Component]() Filter {
+	return Filter{t: reflect.TypeFor[T](), exclude: true}
+}
+
+// queryAll walks the smallest of the stores and keeps the entities all the others have
+// and none of the excluded ones do
+func queryAll(w *World, types []reflect.Type, filters []Filter) []Entity {
+	stores := make([]anyStore, 0, len(types)+len(filters))
+	var excluded []anyStore
+	for _, f := range filters {
+		if !f.exclude {
+			types = append(types, f.t)
+		} else if s, ok := w.stores[f.t]; ok && s.size() > 0 {
+			excluded = append(excluded, s)
+		}
+	}
+	for _, t := range types {
+		s, ok := w.stores[t]
+		if !ok || s.size() == 0 {
+			return nil
+		}
+		stores = append(stores, s)
+	}
+	smallest := slices.MinFunc(stores, func(a, b anyStore) int { return a.size() - b.size() })
+
+	var entities []Entity
+next:
+	for _, e := range smallest.owners() {
+		for _, s := range stores {
+			if s != smallest && !s.has(e) {
+				continue next
+			}
+		}
+		for _, s := range excluded {
+			if s.has(e) {
+				continue next
+			}
+		}
+		entities = append(entities, e)
+	}
+	return entities
+}
+
+// Each2 calls fn with every entity that has both an A and a B (see Query2), and copies of
+// the two. As with GetComponent, changes only stick once written back with AddComponent.
+// fn may add and remove components: entities that lost one before their turn are skipped.
+func Each2[A, B Component](w *World, fn func(e Entity, a *A, b *B), filters ...Filter) {
+	as, bs := storeOf[A](w, false), storeOf[B](w, false)
+	for _, e := range Query2[A, B](w, filters...) {
+		a, okA := as.get(e)
+		b, okB := bs.get(e)
+		if okA && okB {
+			fn(e, &a, &b)
+		}
+	}
+}
+
+// Each3 is Each2 for entities with an A, a B and a C
+func Each3[A, B, C Component](w *World, fn func(e Entity, a *A, b *B, c *C), filters ...Filter) {
+	as, bs, cs := storeOf[A](w, false), storeOf[B](w, false), storeOf[C](w, false)
+	for _, e := range Query3[A, B, C](w, filters...) {
+		a, okA := as.get(e)
+		b, okB := bs.get(e)
+		c, okC := cs.get(e)
+		if okA && okB && okC {
+			fn(e, &a, &b, &c)
+		}
+	}
+}
+
+// ChangedSince returns all entities whose component of type T changed at or after tick,
+// in ascending ID order.
+func ChangedSince[T Component](w *World, tick uint64) []Entity {
+	s := storeOf[T](w, false)
+	if s == nil {
+		return nil
+	}
 	var entities []Entity
-	if store, ok := w.components[cType]; ok {
-		for e := range store {
+	for e, t := range s.changed {
+		if t >= tick {
 			entities = append(entities, e)
 		}
 	}
+	slices.Sort(entities)
+	return entities
+}
+
+// Tag is a bitmask of entity categories (values are defined by the game, e.g. components.TagPlayer).
+type Tag uint64
+
+// TagComponent labels an entity with tags so systems can filter by category.
+type TagComponent struct {
+	Tags Tag
+}
+
+// AddTags sets additional tag bits on an entity.
+func (w *World) AddTags(e Entity, tags Tag) {
+	tc, _ := GetComponent[TagComponent](w, e)
+	if tc == nil {
+		tc = &TagComponent{}
+	}
+	tc.Tags |= tags
+	AddComponent(w, e, *tc)
+}
+
+// HasTag reports whether an entity has all of the given tag bits.
+func HasTag(w *World, e Entity, tags Tag) bool {
+	tc, ok := GetComponent[TagComponent](w, e)
+	return ok && tc.Tags&tags == tags
+}
+
+// QueryTagged returns all entities that have all of the given tag bits.
+func QueryTagged(w *World, tags Tag) []Entity {
+	s := storeOf[TagComponent](w, false)
+	if s == nil {
+		return nil
+	}
+	var entities []Entity
+	for i, tc := range s.dense {
+		if tc.Tags&tags == tags {
+			entities = append(entities, s.entities[i])
+		}
+	}
 	return entities
 }
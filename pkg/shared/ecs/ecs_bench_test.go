@@ -0,0 +1,75 @@
+package ecs
+
+import "testing"
+
+type benchPosition struct{ X, Y float64 }
+type benchHealth struct{ HP float64 }
+
+// benchWorld creates n entities with a position, half of them with health, every tenth tagged
+func benchWorld(n int) *World {
+	w := NewWorld()
+	for i := 0; i < n; i++ {
+		e := w.NewEntity()
+		AddComponent(w, e, benchPosition{X: float64(i), Y: float64(i)})
+		if i%2 == 0 {
+			AddComponent(w, e, benchHealth{HP: 100})
+		}
+		if i%10 == 0 {
+			w.AddTags(e, 1)
+		}
+	}
+	return w
+}
+
+func BenchmarkQuery1000(b *testing.B) {
+	w := benchWorld(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Query[benchPosition](w)
+	}
+}
+
+func BenchmarkQuery10000(b *testing.B) {
+	w := benchWorld(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Query[benchPosition](w)
+	}
+}
+
+// Query plus GetComponent per entity, the pattern most systems use
+func BenchmarkQueryAndGet1000(b *testing.B) {
+	w := benchWorld(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, e := range Query[benchHealth](w) {
+			if h, ok := GetComponent[benchHealth](w, e); !ok || h.HP <= 0 {
+				b.Fatal("missing health")
+			}
+		}
+	}
+}
+
+func BenchmarkQueryTagged1000(b *testing.B) {
+	w := benchWorld(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		QueryTagged(w, 1)
+	}
+}
+
+func BenchmarkAddComponentUnchanged(b *testing.B) {
+	w := benchWorld(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AddComponent(w, Entity(i%1000+1), benchPosition{X: float64(i % 1000), Y: float64(i % 1000)})
+	}
+}
+
+func BenchmarkQuery2_1000(b *testing.B) {
+	w := benchWorld(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Query2[benchHealth, TagComponent](w)
+	}
+}
@@ -0,0 +1,132 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStoresKeepComponentsThroughRemovals(t *testing.T) {
+	w := benchWorld(3000) // Spans a few sparse pages
+	for e := Entity(1); e <= 3000; e += 3 {
+		w.RemoveEntity(e)
+	}
+	for e := Entity(1); e <= 3000; e++ {
+		pos, ok := GetComponent[benchPosition](w, e)
+		if removed := e%3 == 1; ok == removed {
+			t.Fatalf("entity %d: has position %v, want %v", e, ok, !removed)
+		}
+		if ok && pos.X != float64(e-1) {
+			t.Fatalf("entity %d has the position of another: %v", e, *pos)
+		}
+	}
+	if n := len(Query[benchPosition](w)); n != 2000 {
+		t.Errorf("%d positions left, want 2000", n)
+	}
+
+	// Same adds and removes, same order
+	if !slices.Equal(Query[benchPosition](w), Query[benchPosition](benchWorldWithout(3000, 3))) {
+		t.Error("two identically built worlds query in different orders")
+	}
+
+	both := Query2[benchPosition, benchHealth](w)
+	for _, e := range both {
+		if !HasComponent[benchHealth](w, e) || !HasComponent[benchPosition](w, e) {
+			t.Fatalf("Query2 returned %d, which lacks a component", e)
+		}
+	}
+	if len(both) != len(Query[benchHealth](w)) {
+		t.Errorf("Query2 found %d, want every entity with health (%d)", len(both), len(Query[benchHealth](w)))
+	}
+	if tagged := Query3[benchPosition, benchHealth, TagComponent](w); len(tagged) != len(QueryTagged(w, 1)) {
+		t.Errorf("Query3 found %d, want the %d tagged entities (all have health)", len(tagged), len(QueryTagged(w, 1)))
+	}
+}
+
+func TestQueryFilters(t *testing.T) {
+	w := benchWorld(100) // Even entities (odd IDs) have health, every tenth is tagged
+
+	if n := len(Query[benchPosition](w, Without[benchHealth]())); n != 50 {
+		t.Errorf("%d positions without health, want 50", n)
+	}
+	if n := len(Query[benchPosition](w, With[benchHealth](), Without[TagComponent]())); n != 40 {
+		t.Errorf("%d untagged positions with health, want 40", n)
+	}
+	if n := len(Query[benchPosition](w, Without[hitEvent]())); n != 100 {
+		t.Errorf("excluding a type nobody has left %d of 100", n)
+	}
+	if got := Query2[benchPosition, benchHealth](w, With[hitEvent]()); got != nil {
+		t.Errorf("requiring a type nobody has found %v", got)
+	}
+
+	// Each hands out copies and skips entities that lost a component on the way
+	visited := 0
+	Each2(w, func(e Entity, pos *benchPosition, hp *benchHealth) {
+		visited++
+		hp.HP = 0 // Not written back
+		RemoveComponent[benchHealth](w, e+2)
+	})
+	if visited != 25 {
+		t.Errorf("visited %d entities, want every other one of 50", visited)
+	}
+	if hp, _ := GetComponent[benchHealth](w, 1); hp.HP != 100 {
+		t.Error("Each2 changed a component that wasn't written back")
+	}
+}
+
+func TestChangeTracking(t *testing.T) {
+	w := NewWorld()
+	e := w.NewEntity()
+	AddComponent(w, e, benchHealth{HP: 10})
+	w.Update(0)
+
+	AddComponent(w, e, benchHealth{HP: 10}) // Written back unchanged
+	if w.EntityChangedSince(e, w.Tick()) {
+		t.Error("an identical write-back counted as a change")
+	}
+	RemoveComponent[benchHealth](w, e)
+	if got := ChangedSince[benchHealth](w, w.Tick()); !slices.Equal(got, []Entity{e}) {
+		t.Errorf("ChangedSince after a removal = %v, want [%d]", got, e)
+	}
+}
+
+// benchWorldWithout is benchWorld(n) with every step-th entity removed again
+func benchWorldWithout(n, step int) *World {
+	w := benchWorld(n)
+	for e := Entity(1); e <= Entity(n); e += Entity(step) {
+		w.RemoveEntity(e)
+	}
+	return w
+}
+
+type hitEvent struct{ Target Entity }
+type deathEvent struct{ Victim Entity }
+
+func TestEventsAreDeliveredInPublishOrder(t *testing.T) {
+	w := NewWorld()
+	var got []string
+	Subscribe(w, func(e hitEvent) {
+		got = append(got, "hit")
+		if e.Target == 2 {
+			Publish(w, deathEvent{Victim: e.Target}) // Handled after what's already queued
+		}
+	})
+	Subscribe(w, func(e deathEvent) { got = append(got, "death") })
+	Subscribe(w, func(e deathEvent) { got = append(got, "despawn") })
+
+	Publish(w, hitEvent{Target: 1})
+	Publish(w, hitEvent{Target: 2})
+	Publish(w, hitEvent{Target: 3})
+	Publish(w, benchHealth{HP: 1}) // Nobody listens
+	if len(got) != 0 || w.PendingEvents() != 3 {
+		t.Fatalf("delivered %v before dispatch with %d pending, want nothing and 3", got, w.PendingEvents())
+	}
+
+	w.DispatchEvents()
+	want := []string{"hit", "hit", "hit", "death", "despawn"}
+	if !slices.Equal(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+	if w.PendingEvents() != 0 {
+		t.Errorf("%d events left after dispatch", w.PendingEvents())
+	}
+}
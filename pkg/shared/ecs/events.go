@@ -0,0 +1,52 @@
+package ecs
+
+import "reflect"
+
+// handlers holds the subscribers to one event type
+type handlers[E any] struct {
+	fns []func(E)
+}
+
+// Subscribe registers a handler for every event of type E published on the world.
+// Handlers run in the order they subscribed.
+func Subscribe[E any](w *World, handler func(E)) {
+	eType := reflect.TypeFor[E]()
+	h, ok := w.handlers[eType].(*handlers[E])
+	if !ok {
+		h = &handlers[E]{}
+		w.handlers[eType] = h
+	}
+	h.fns = append(h.fns, handler)
+}
+
+// Publish queues an event for DispatchEvents. The publisher carries on with its own
+// work first, so e.g. a death is handled once the hit that caused it is fully applied.
+// Events nobody subscribed to are dropped.
+func Publish[E any](w *World, event E) {
+	h, ok := w.handlers[reflect.TypeFor[E]()].(*handlers[E])
+	if !ok {
+		return
+	}
+	w.events = append(w.events, func() {
+		for _, fn := range h.fns {
+			fn(event)
+		}
+	})
+}
+
+// DispatchEvents delivers the queued events to their subscribers in the order they were
+// published, including those the handlers publish meanwhile. Update calls it after each
+// system; callers running systems themselves call it after each.
+func (w *World) DispatchEvents() {
+	for len(w.events) > 0 {
+		deliver := w.events[0]
+		w.events = w.events[1:] // Popped first: a panicking handler only loses its own event
+		deliver()
+	}
+	w.events = nil
+}
+
+// PendingEvents returns how many events are waiting for DispatchEvents
+func (w *World) PendingEvents() int {
+	return len(w.events)
+}
@@ -0,0 +1,138 @@
+package ecs
+
+import "reflect"
+
+// pageBits sizes the pages of a store's sparse index: 1024 entity IDs per page
+const pageBits = 10
+
+// store keeps every component of one type in a dense slice, with a sparse index from
+// entity to slot. Removing swaps the last component into the hole, so iteration order
+// only depends on the order of adds and removes.
+type store[T any] struct {
+	dense    []T
+	entities []Entity // Owner of each dense slot
+
+	// sparse maps an entity's ID to its slot + 1 (0 = none), in pages allocated on first
+	// use and dropped once empty so long-running servers don't keep dead ID ranges
+	sparse [][]int32
+	used   []int32 // Entries in use per page
+
+	// Tick of the last add, change or removal of each entity's component
+	changed map[Entity]uint64
+
+	comparable bool // Whether put can tell an unchanged write-back
+}
+
+// anyStore is what the World needs of a store without knowing its type
+type anyStore interface {
+	set(e Entity, c Component) bool
+	remove(e Entity) bool
+	has(e Entity) bool
+	size() int
+	owners() []Entity
+	changes() map[Entity]uint64
+}
+
+func newStore[T any]() *store[T] {
+	return &store[T]{
+		changed:    make(map[Entity]uint64),
+		comparable: reflect.TypeFor[T]().Comparable(),
+	}
+}
+
+func (s *store[T]) slot(e Entity) (int, bool) {
+	page, offset := int(e>>pageBits), int(e&(1<<pageBits-1))
+	if page >= len(s.sparse) || s.sparse[page] == nil {
+		return 0, false
+	}
+	i := s.sparse[page][offset]
+	return int(i) - 1, i != 0
+}
+
+func (s *store[T]) setSlot(e Entity, i int) {
+	page, offset := int(e>>pageBits), int(e&(1<<pageBits-1))
+	if page >= len(s.sparse) {
+		s.sparse = append(s.sparse, make([][]int32, page+1-len(s.sparse))...)
+		s.used = append(s.used, make([]int32, page+1-len(s.used))...)
+	}
+	if s.sparse[page] == nil {
+		s.sparse[page] = make([]int32, 1<<pageBits)
+	}
+	if s.sparse[page][offset] == 0 {
+		s.used[page]++
+	}
+	s.sparse[page][offset] = int32(i + 1)
+}
+
+func (s *store[T]) clearSlot(e Entity) {
+	page, offset := int(e>>pageBits), int(e&(1<<pageBits-1))
+	s.sparse[page][offset] = 0
+	if s.used[page]--; s.used[page] == 0 {
+		s.sparse[page] = nil
+	}
+}
+
+// get returns the entity's component, if it has one
+func (s *store[T]) get(e Entity) (T, bool) {
+	if i, ok := s.slot(e); ok {
+		return s.dense[i], true
+	}
+	var zero T
+	return zero, false
+}
+
+// put adds or replaces the entity's component. Reports false when it was already
+// equal to v (comparable types only), which isn't a change.
+func (s *store[T]) put(e Entity, v T) bool {
+	if i, ok := s.slot(e); ok {
+		if s.comparable && any(s.dense[i]) == any(v) {
+			return false
+		}
+		s.dense[i] = v
+		return true
+	}
+	s.setSlot(e, len(s.dense))
+	s.dense = append(s.dense, v)
+	s.entities = append(s.entities, e)
+	return true
+}
+
+func (s *store[T]) set(e Entity, c Component) bool {
+	return s.put(e, c.(T))
+}
+
+func (s *store[T]) remove(e Entity) bool {
+	i, ok := s.slot(e)
+	if !ok {
+		return false
+	}
+	last := len(s.dense) - 1
+	if i != last {
+		s.dense[i] = s.dense[last]
+		s.entities[i] = s.entities[last]
+		s.setSlot(s.entities[i], i)
+	}
+	var zero T
+	s.dense[last] = zero // Let the GC have whatever it pointed to
+	s.dense = s.dense[:last]
+	s.entities = s.entities[:last]
+	s.clearSlot(e)
+	return true
+}
+
+func (s *store[T]) has(e Entity) bool {
+	_, ok := s.slot(e)
+	return ok
+}
+
+func (s *store[T]) size() int {
+	return len(s.dense)
+}
+
+func (s *store[T]) owners() []Entity {
+	return s.entities
+}
+
+func (s *store[T]) changes() map[Entity]uint64 {
+	return s.changed
+}
@@ -0,0 +1,203 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestVectors(t *testing.T) {
+	v := Vec2{3, 4}
+	if v.Len() != 5 || v.Dist(Vec2{}) != 5 || Dist(0, 0, 3, 4) != 5 {
+		t.Errorf("length of %v = %v", v, v.Len())
+	}
+	if got := v.Add(Vec2{1, 1}).Sub(Vec2{2, 2}).Scale(2); got != (Vec2{4, 6}) {
+		t.Errorf("arithmetic = %v", got)
+	}
+	if got := v.Dot(Vec2{-4, 3}); got != 0 {
+		t.Errorf("perpendicular dot = %v", got)
+	}
+	if got := (Vec2{0, 0}).Lerp(Vec2{10, 20}, 0.25); got != (Vec2{2.5, 5}) {
+		t.Errorf("lerp = %v", got)
+	}
+	if n := v.Normalize(); !near(n.X, 0.6) || !near(n.Y, 0.8) {
+		t.Errorf("normalize = %v", n)
+	}
+	if x, y, l := Normalize(0, 0); x != 0 || y != 0 || l != 0 {
+		t.Errorf("zero vector normalized to %v, %v (%v)", x, y, l)
+	}
+	if x, y := Direction(10, 10, 10, -5); x != 0 || y != -1 {
+		t.Errorf("direction up = %v, %v", x, y)
+	}
+	if x, y := Direction(7, 7, 7, 7); x != 0 || y != 0 {
+		t.Errorf("direction to self = %v, %v", x, y)
+	}
+}
+
+func TestWithinIncludesTheEdge(t *testing.T) {
+	tests := []struct {
+		x, y, dist float64
+		want       bool
+	}{
+		{3, 4, 5, true},
+		{3, 4, 4.99, false},
+		{0, 0, 0, true},
+		{-30, 40, 50, true},
+	}
+	for _, tt := range tests {
+		if got := Within(0, 0, tt.x, tt.y, tt.dist); got != tt.want {
+			t.Errorf("Within(%v, %v, %v) = %v, want %v", tt.x, tt.y, tt.dist, got, tt.want)
+		}
+	}
+}
+
+func TestAABB(t *testing.T) {
+	b := AABB{10, 10, 20, 10}
+	if c := b.Center(); c != (Vec2{20, 15}) {
+		t.Errorf("center = %v", c)
+	}
+	for _, tt := range []struct {
+		x, y float64
+		want bool
+	}{
+		{10, 10, true}, {29.9, 19.9, true}, {30, 15, false}, {20, 20, false}, {9, 15, false},
+	} {
+		if got := b.Contains(tt.x, tt.y); got != tt.want {
+			t.Errorf("Contains(%v, %v) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+	for _, tt := range []struct {
+		o    AABB
+		want bool
+	}{
+		{AABB{25, 15, 10, 10}, true},   // Corner overlap
+		{AABB{15, 12, 2, 2}, true},     // Inside
+		{AABB{0, 0, 100, 100}, true},   // Around
+		{AABB{30, 10, 10, 10}, false},  // Touching the right edge
+		{AABB{10, -10, 20, 20}, false}, // Touching the top edge
+		{AABB{50, 50, 5, 5}, false},
+	} {
+		if got := b.Overlaps(tt.o); got != tt.want {
+			t.Errorf("Overlaps(%v) = %v, want %v", tt.o, got, tt.want)
+		}
+		if got := tt.o.Overlaps(b); got != tt.want {
+			t.Errorf("%v.Overlaps = %v, want %v (not symmetric)", tt.o, got, tt.want)
+		}
+	}
+	if p := b.Closest(0, 50); p != (Vec2{10, 20}) {
+		t.Errorf("closest = %v", p)
+	}
+	if s := Square(1, 2, 3); s != (AABB{1, 2, 3, 3}) {
+		t.Errorf("square = %v", s)
+	}
+}
+
+func TestCircles(t *testing.T) {
+	c := Circle{0, 0, 10}
+	if !c.Contains(6, 7.9) || c.Contains(6, 8) {
+		t.Error("contains: the edge is outside")
+	}
+	if !c.Overlaps(Circle{15, 0, 6}) || c.Overlaps(Circle{15, 0, 5}) {
+		t.Error("circles touching don't overlap, crossing ones do")
+	}
+	for _, tt := range []struct {
+		b    AABB
+		want bool
+	}{
+		{AABB{5, 5, 10, 10}, true},    // Corner within the radius
+		{AABB{8, 8, 10, 10}, false},   // Corner just out (8,8 is 11.3 away)
+		{AABB{-2, -2, 4, 4}, true},    // Center inside the rect
+		{AABB{10, -5, 10, 10}, false}, // Touching the side
+		{AABB{9, -5, 10, 10}, true},
+	} {
+		if got := c.OverlapsAABB(tt.b); got != tt.want {
+			t.Errorf("OverlapsAABB(%v) = %v, want %v", tt.b, got, tt.want)
+		}
+	}
+	if in := Inscribed(AABB{0, 0, 24, 24}); in != (Circle{12, 12, 12}) {
+		t.Errorf("inscribed = %v", in)
+	}
+}
+
+func TestPointInPolygon(t *testing.T) {
+	// An L shape: the notch at the top right is outside
+	l := [][2]float64{{0, 0}, {10, 0}, {10, 20}, {20, 20}, {20, 30}, {0, 30}}
+	for _, tt := range []struct {
+		x, y float64
+		want bool
+	}{
+		{5, 5, true}, {15, 25, true}, {15, 10, false}, {-1, 5, false}, {5, 31, false},
+	} {
+		if got := PointInPolygon(l, tt.x, tt.y); got != tt.want {
+			t.Errorf("PointInPolygon(%v, %v) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestMarch(t *testing.T) {
+	var points []Vec2
+	ok := March(0, 0, 32, 0, 8, func(x, y float64) bool {
+		points = append(points, Vec2{x, y})
+		return true
+	})
+	want := []Vec2{{8, 0}, {16, 0}, {24, 0}, {32, 0}}
+	if !ok || len(points) != len(want) {
+		t.Fatalf("marched %v (%v), want %v", points, ok, want)
+	}
+	for i := range want {
+		if points[i] != want[i] {
+			t.Errorf("point %d = %v, want %v", i, points[i], want[i])
+		}
+	}
+
+	visits := 0
+	if March(0, 0, 0, 100, 10, func(x, y float64) bool { visits++; return y < 30 }) || visits != 3 {
+		t.Errorf("blocked march went on for %d points", visits)
+	}
+	if !March(0, 0, 3, 0, 8, func(x, y float64) bool { t.Error("visited a point of a short segment"); return false }) {
+		t.Error("a segment shorter than a step is blocked")
+	}
+}
+
+func TestRays(t *testing.T) {
+	box := AABB{10, -5, 10, 10}
+	for _, tt := range []struct {
+		origin, dir Vec2
+		t           float64
+		hit         bool
+	}{
+		{Vec2{0, 0}, Vec2{1, 0}, 10, true},
+		{Vec2{0, 0}, Vec2{2, 0}, 5, true}, // In multiples of dir
+		{Vec2{0, 0}, Vec2{-1, 0}, 0, false},
+		{Vec2{0, 10}, Vec2{1, 0}, 0, false}, // Parallel, passes below
+		{Vec2{15, 0}, Vec2{0, 1}, 0, true},  // Starts inside
+		{Vec2{0, -20}, Vec2{1, 1}, 15, true},
+	} {
+		got, hit := RayAABB(tt.origin, tt.dir, box)
+		if hit != tt.hit || (hit && !near(got, tt.t)) {
+			t.Errorf("RayAABB(%v, %v) = %v, %v; want %v, %v", tt.origin, tt.dir, got, hit, tt.t, tt.hit)
+		}
+	}
+
+	if frac, hit := SegmentAABB(Vec2{0, 0}, Vec2{40, 0}, box); !hit || !near(frac, 0.25) {
+		t.Errorf("segment through the box = %v, %v", frac, hit)
+	}
+	if _, hit := SegmentAABB(Vec2{0, 0}, Vec2{9, 0}, box); hit {
+		t.Error("segment stopping short hit the box")
+	}
+
+	c := Circle{10, 0, 2}
+	if got, hit := RayCircle(Vec2{0, 0}, Vec2{1, 0}, c); !hit || !near(got, 8) {
+		t.Errorf("RayCircle = %v, %v", got, hit)
+	}
+	if _, hit := RayCircle(Vec2{0, 5}, Vec2{1, 0}, c); hit {
+		t.Error("ray passing above hit the circle")
+	}
+	if _, hit := RayCircle(Vec2{20, 0}, Vec2{1, 0}, c); hit {
+		t.Error("ray pointing away hit the circle")
+	}
+	if got, hit := RayCircle(Vec2{10, 1}, Vec2{1, 0}, c); !hit || got != 0 {
+		t.Errorf("ray from inside = %v, %v", got, hit)
+	}
+}
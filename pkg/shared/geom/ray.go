@@ -0,0 +1,75 @@
+package geom
+
+import "math"
+
+// March walks the segment from (x1, y1) to (x2, y2) in steps of about step px, calling
+// visit with each point after the start, the end included. It stops early and returns
+// false as soon as visit does; segments shorter than a step visit nothing.
+func March(x1, y1, x2, y2, step float64, visit func(x, y float64) bool) bool {
+	steps := int(Dist(x1, y1, x2, y2) / step)
+	if steps == 0 {
+		return true
+	}
+	dx := (x2 - x1) / float64(steps)
+	dy := (y2 - y1) / float64(steps)
+	for i := 1; i <= steps; i++ {
+		if !visit(x1+dx*float64(i), y1+dy*float64(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// RayAABB casts a ray from origin along dir (any length) and returns the distance along
+// it, in multiples of dir, to where it enters the rect. A ray starting inside hits at 0.
+func RayAABB(origin, dir Vec2, b AABB) (t float64, hit bool) {
+	tMin, tMax := 0.0, math.Inf(1)
+	for _, axis := range [2]struct{ o, d, lo, hi float64 }{
+		{origin.X, dir.X, b.X, b.X + b.W},
+		{origin.Y, dir.Y, b.Y, b.Y + b.H},
+	} {
+		if axis.d == 0 {
+			if axis.o < axis.lo || axis.o > axis.hi {
+				return 0, false // Parallel and outside the slab
+			}
+			continue
+		}
+		t1, t2 := (axis.lo-axis.o)/axis.d, (axis.hi-axis.o)/axis.d
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin, tMax = math.Max(tMin, t1), math.Min(tMax, t2)
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	return tMin, true
+}
+
+// SegmentAABB reports whether the segment from a to b passes through the rect, and the
+// fraction of the way along it (0-1) where it enters
+func SegmentAABB(a, b Vec2, box AABB) (t float64, hit bool) {
+	t, hit = RayAABB(a, b.Sub(a), box)
+	return t, hit && t <= 1
+}
+
+// RayCircle casts a ray from origin along dir (any length) and returns the distance
+// along it, in multiples of dir, to where it enters the circle. A ray starting inside
+// hits at 0.
+func RayCircle(origin, dir Vec2, c Circle) (t float64, hit bool) {
+	if c.Contains(origin.X, origin.Y) {
+		return 0, true
+	}
+	a := dir.Dot(dir)
+	if a == 0 {
+		return 0, false
+	}
+	f := origin.Sub(Vec2{c.X, c.Y})
+	b := 2 * f.Dot(dir)
+	disc := b*b - 4*a*(f.Dot(f)-c.R*c.R)
+	if disc < 0 {
+		return 0, false
+	}
+	t = (-b - math.Sqrt(disc)) / (2 * a)
+	return t, t >= 0
+}
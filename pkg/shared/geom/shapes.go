@@ -0,0 +1,76 @@
+package geom
+
+import "math"
+
+// Shapes overlap when they share some area: touching edges don't count, unlike the
+// distance checks of Within, where the edge of the range does.
+
+// AABB is an axis-aligned rect by its top-left corner and size
+type AABB struct {
+	X, Y, W, H float64
+}
+
+// Square is a size x size rect with its top-left corner at x, y
+func Square(x, y, size float64) AABB {
+	return AABB{x, y, size, size}
+}
+
+func (b AABB) Center() Vec2 {
+	return Vec2{b.X + b.W/2, b.Y + b.H/2}
+}
+
+// Contains reports whether a point lies inside (left and top edges in, right and bottom out)
+func (b AABB) Contains(x, y float64) bool {
+	return x >= b.X && x < b.X+b.W && y >= b.Y && y < b.Y+b.H
+}
+
+func (b AABB) Overlaps(o AABB) bool {
+	return b.X < o.X+o.W && b.X+b.W > o.X && b.Y < o.Y+o.H && b.Y+b.H > o.Y
+}
+
+// Closest is the point of the rect nearest to (x, y), the point itself if inside
+func (b AABB) Closest(x, y float64) Vec2 {
+	return Vec2{math.Max(b.X, math.Min(x, b.X+b.W)), math.Max(b.Y, math.Min(y, b.Y+b.H))}
+}
+
+// Circle is a circle by its center and radius
+type Circle struct {
+	X, Y, R float64
+}
+
+// Inscribed is the circle filling a square, how circular colliders are sized
+func Inscribed(b AABB) Circle {
+	c := b.Center()
+	return Circle{c.X, c.Y, math.Min(b.W, b.H) / 2}
+}
+
+func (c Circle) Contains(x, y float64) bool {
+	dx, dy := x-c.X, y-c.Y
+	return dx*dx+dy*dy < c.R*c.R
+}
+
+func (c Circle) Overlaps(o Circle) bool {
+	dx, dy := o.X-c.X, o.Y-c.Y
+	r := c.R + o.R
+	return dx*dx+dy*dy < r*r
+}
+
+func (c Circle) OverlapsAABB(b AABB) bool {
+	p := b.Closest(c.X, c.Y)
+	return c.Contains(p.X, p.Y)
+}
+
+// PointInPolygon reports whether a point lies inside a polygon (even-odd rule)
+func PointInPolygon(poly [][2]float64, x, y float64) bool {
+	inside := false
+	j := len(poly) - 1
+	for i := 0; i < len(poly); i++ {
+		xi, yi := poly[i][0], poly[i][1]
+		xj, yj := poly[j][0], poly[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+		j = i
+	}
+	return inside
+}
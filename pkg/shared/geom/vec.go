@@ -0,0 +1,52 @@
+// Package geom holds the 2D math shared by the server, the client and the tools:
+// vectors, distance checks, rect/circle overlap and rays. World positions are pixels
+// with Y pointing down.
+package geom
+
+import "math"
+
+// Vec2 is a point or a direction
+type Vec2 struct {
+	X, Y float64
+}
+
+func (v Vec2) Add(o Vec2) Vec2             { return Vec2{v.X + o.X, v.Y + o.Y} }
+func (v Vec2) Sub(o Vec2) Vec2             { return Vec2{v.X - o.X, v.Y - o.Y} }
+func (v Vec2) Scale(k float64) Vec2        { return Vec2{v.X * k, v.Y * k} }
+func (v Vec2) Dot(o Vec2) float64          { return v.X*o.X + v.Y*o.Y }
+func (v Vec2) Len() float64                { return math.Hypot(v.X, v.Y) }
+func (v Vec2) Dist(o Vec2) float64         { return v.Sub(o).Len() }
+func (v Vec2) Lerp(o Vec2, t float64) Vec2 { return v.Add(o.Sub(v).Scale(t)) }
+
+// Normalize returns the unit vector pointing the same way (the zero vector stays zero)
+func (v Vec2) Normalize() Vec2 {
+	x, y, _ := Normalize(v.X, v.Y)
+	return Vec2{x, y}
+}
+
+// Normalize scales (dx, dy) to length 1 and also returns its original length. The zero
+// vector stays zero.
+func Normalize(dx, dy float64) (x, y, length float64) {
+	length = math.Hypot(dx, dy)
+	if length == 0 {
+		return 0, 0, 0
+	}
+	return dx / length, dy / length, length
+}
+
+// Direction is the unit vector from (x1, y1) towards (x2, y2), zero if they're the same point
+func Direction(x1, y1, x2, y2 float64) (float64, float64) {
+	x, y, _ := Normalize(x2-x1, y2-y1)
+	return x, y
+}
+
+// Dist is the distance between two points
+func Dist(x1, y1, x2, y2 float64) float64 {
+	return math.Hypot(x2-x1, y2-y1)
+}
+
+// Within reports whether two points are at most dist apart (range checks: the edge counts)
+func Within(x1, y1, x2, y2, dist float64) bool {
+	dx, dy := x2-x1, y2-y1
+	return dx*dx+dy*dy <= dist*dist
+}
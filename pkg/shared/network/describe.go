@@ -0,0 +1,111 @@
+package network
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+var binaryMarshaler = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+
+// ProtocolDoc is a machine-readable description of the wire protocol: every packet with
+// its ID, direction and payload, and the fields of every named type the payloads use
+type ProtocolDoc struct {
+	Envelope string             `json:"envelope"` // Every packet is sent wrapped in this
+	Packets  []PacketDoc        `json:"packets"`
+	Types    map[string]TypeDoc `json:"types"`
+}
+
+type PacketDoc struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Direction string `json:"direction"`
+	Payload   string `json:"payload"`
+}
+
+type TypeDoc struct {
+	Kind   string     `json:"kind"`             // "struct", "binary" (encodes itself, e.g. time.Time) or the underlying kind ("int", ...)
+	Fields []FieldDoc `json:"fields,omitempty"` // Structs only, in declaration order
+}
+
+type FieldDoc struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// DescribeProtocol builds the description from Packets
+func DescribeProtocol() ProtocolDoc {
+	envelope := reflect.TypeOf(Packet{})
+	doc := ProtocolDoc{Envelope: envelope.String(), Types: make(map[string]TypeDoc)}
+	describeType(doc.Types, envelope)
+	for _, spec := range Packets {
+		t := reflect.TypeOf(spec.Data)
+		doc.Packets = append(doc.Packets, PacketDoc{ID: int(spec.Type), Name: spec.Name, Direction: spec.Direction, Payload: t.String()})
+		describeType(doc.Types, t)
+	}
+	return doc
+}
+
+var fingerprint = sync.OnceValue(func() string {
+	data, err := json.Marshal(DescribeProtocol())
+	if err != nil {
+		panic(err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+})
+
+// ProtocolFingerprint is a short hash of the protocol description. Client and server
+// compare theirs at login: builds that differ in any packet or payload field refuse
+// each other instead of failing to decode halfway through the game.
+func ProtocolFingerprint() string {
+	return fingerprint()
+}
+
+// describeType adds t and the named types it refers to. Only exported fields are listed,
+// as gob skips the rest.
+func describeType(types map[string]TypeDoc, t reflect.Type) {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		describeType(types, t.Elem())
+		return
+	case reflect.Map:
+		describeType(types, t.Key())
+		describeType(types, t.Elem())
+		return
+	}
+	if t.Name() == "" {
+		if t.Kind() == reflect.Struct { // Anonymous struct: its fields are in its name
+			for i := 0; i < t.NumField(); i++ {
+				describeType(types, t.Field(i).Type)
+			}
+		}
+		return
+	}
+	if t.PkgPath() == "" {
+		return // Builtin
+	}
+	if _, done := types[t.String()]; done {
+		return
+	}
+
+	doc := TypeDoc{Kind: t.Kind().String()}
+	if t.Implements(binaryMarshaler) {
+		doc.Kind = "binary"
+	}
+	types[t.String()] = doc
+	if doc.Kind == "struct" {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			doc.Fields = append(doc.Fields, FieldDoc{Name: field.Name, Type: field.Type.String()})
+			describeType(types, field.Type)
+		}
+		types[t.String()] = doc
+	}
+}
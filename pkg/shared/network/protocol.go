@@ -4,18 +4,14 @@ import (
 	"encoding/gob"
 	"henry/pkg/shared/components"
 	"henry/pkg/shared/ecs"
+	"image/color"
 )
 
 // RegisterGobTypes registers all types that will be sent over the wire.
 func RegisterGobTypes() {
-	gob.Register(LoginPacket{})
-	gob.Register(LoginResponsePacket{})
-	gob.Register(SignupPacket{})
-	gob.Register(SignupResponsePacket{})
-	gob.Register(UpdateKeybindingsPacket{})
-	gob.Register(UpdateDebugSettingsPacket{})
-	gob.Register(InputPacket{})
-	gob.Register(StateUpdatePacket{})
+	for _, spec := range Packets {
+		gob.Register(spec.Data)
+	}
 	gob.Register(components.TransformComponent{})
 	gob.Register(components.PhysicsComponent{})
 	gob.Register(components.SpriteComponent{})
@@ -23,18 +19,9 @@ func RegisterGobTypes() {
 	gob.Register(components.StatsComponent{})
 	gob.Register(components.AttackComponent{})
 	gob.Register(components.ProjectileComponent{})
-	gob.Register(InventorySyncPacket{})
-	gob.Register(InventoryActionPacket{})
-	gob.Register(HotbarSyncPacket{})
-	gob.Register(HotbarActionPacket{})
+	gob.Register(components.MarkerComponent{})
+	gob.Register(components.GroundItemComponent{})
 	gob.Register(HotbarSyncSlot{})
-	gob.Register(EquipmentSyncPacket{})
-	gob.Register(EquipmentActionPacket{})
-	gob.Register(EquipmentActionPacket{})
-	gob.Register(MapSyncPacket{})
-	gob.Register(CastSpellPacket{})
-	gob.Register(SpellbookSyncPacket{})
-	gob.Register(UpdateUIStatePacket{})
 }
 
 type PacketType int
@@ -58,8 +45,143 @@ const (
 	PacketCastSpell           PacketType = 16
 	PacketSpellbookSync       PacketType = 17
 	PacketUpdateUIState       PacketType = 18
+	PacketMoveTo              PacketType = 19
+	PacketFollow              PacketType = 20
+	PacketPickup              PacketType = 21
+	PacketAnnouncement        PacketType = 22
+	PacketZoneChange          PacketType = 23
+	PacketWaypointSync        PacketType = 24
+	PacketTravel              PacketType = 25
+	PacketBugReport           PacketType = 26
+	PacketLootRoll            PacketType = 27
+	PacketLootChoice          PacketType = 28
+	PacketDuel                PacketType = 29
+	PacketDuelInvite          PacketType = 30
+	PacketDuelState           PacketType = 31
+	PacketArena               PacketType = 32
+	PacketArenaState          PacketType = 33
+	PacketSpectate            PacketType = 34
+	PacketSpectateState       PacketType = 35
+	PacketBuild               PacketType = 36
+	PacketFarm                PacketType = 37
+	PacketObjectUpdate        PacketType = 38
+	PacketFish                PacketType = 39
+	PacketFishState           PacketType = 40
+	PacketMail                PacketType = 41
+	PacketMailbox             PacketType = 42
+	PacketCharacter           PacketType = 43
+	PacketCharacterSheet      PacketType = 44
+	PacketLoginQueue          PacketType = 45
+	PacketEntityEvent         PacketType = 46
+	PacketChatMessage         PacketType = 47
+	PacketChatBroadcast       PacketType = 48
+	PacketHouse               PacketType = 49
+	PacketHouseState          PacketType = 50
+	PacketCombatState         PacketType = 51
+	PacketCutscene            PacketType = 52
+	PacketCutsceneSkip        PacketType = 53
+	PacketMoveCorrection      PacketType = 54
+	PacketInteract            PacketType = 55
+	PacketDialogue            PacketType = 56
+	PacketDialogueChoice      PacketType = 57
+	PacketPing                PacketType = 58
+	PacketPong                PacketType = 59
+	PacketShopOpen            PacketType = 60
+	PacketShopTransaction     PacketType = 61
+	PacketWardrobeSync        PacketType = 62
+	PacketWardrobeAction      PacketType = 63
+	PacketHelpTopics          PacketType = 64
+	PacketEntityDespawn       PacketType = 65
+	PacketEntitySpawn         PacketType = 66
+	PacketAwaySummary         PacketType = 67
 )
 
+// Who sends a packet
+const (
+	ToServer = "to_server"
+	ToClient = "to_client"
+)
+
+// PacketSpec ties a packet type to its payload
+type PacketSpec struct {
+	Type      PacketType
+	Name      string
+	Direction string      // ToServer or ToClient
+	Data      interface{} // Zero payload
+}
+
+// Packets lists every packet type. New packets go here too: it registers their payload
+// with gob and feeds the protocol description (cmd/protodoc) and conformance tests.
+var Packets = []PacketSpec{
+	{PacketLogin, "Login", ToServer, LoginPacket{}},
+	{PacketLoginResponse, "LoginResponse", ToClient, LoginResponsePacket{}},
+	{PacketInput, "Input", ToServer, InputPacket{}},
+	{PacketStateUpdate, "StateUpdate", ToClient, StateUpdatePacket{}},
+	{PacketSignup, "Signup", ToServer, SignupPacket{}},
+	{PacketSignupResponse, "SignupResponse", ToClient, SignupResponsePacket{}},
+	{PacketUpdateKeybindings, "UpdateKeybindings", ToServer, UpdateKeybindingsPacket{}},
+	{PacketInventorySync, "InventorySync", ToClient, InventorySyncPacket{}},
+	{PacketInventoryAction, "InventoryAction", ToServer, InventoryActionPacket{}},
+	{PacketHotbarSync, "HotbarSync", ToClient, HotbarSyncPacket{}},
+	{PacketHotbarAction, "HotbarAction", ToServer, HotbarActionPacket{}},
+	{PacketEquipmentSync, "EquipmentSync", ToClient, EquipmentSyncPacket{}},
+	{PacketEquipmentAction, "EquipmentAction", ToServer, EquipmentActionPacket{}},
+	{PacketMapSync, "MapSync", ToClient, MapSyncPacket{}},
+	{PacketUpdateDebugSettings, "UpdateDebugSettings", ToServer, UpdateDebugSettingsPacket{}},
+	{PacketCastSpell, "CastSpell", ToServer, CastSpellPacket{}},
+	{PacketSpellbookSync, "SpellbookSync", ToClient, SpellbookSyncPacket{}},
+	{PacketUpdateUIState, "UpdateUIState", ToServer, UpdateUIStatePacket{}},
+	{PacketMoveTo, "MoveTo", ToServer, MoveToPacket{}},
+	{PacketFollow, "Follow", ToServer, FollowPacket{}},
+	{PacketPickup, "Pickup", ToServer, PickupPacket{}},
+	{PacketAnnouncement, "Announcement", ToClient, AnnouncementPacket{}},
+	{PacketZoneChange, "ZoneChange", ToClient, ZoneChangePacket{}},
+	{PacketWaypointSync, "WaypointSync", ToClient, WaypointSyncPacket{}},
+	{PacketTravel, "Travel", ToServer, TravelPacket{}},
+	{PacketBugReport, "BugReport", ToServer, BugReportPacket{}},
+	{PacketLootRoll, "LootRoll", ToClient, LootRollPacket{}},
+	{PacketLootChoice, "LootChoice", ToServer, LootChoicePacket{}},
+	{PacketDuel, "Duel", ToServer, DuelPacket{}},
+	{PacketDuelInvite, "DuelInvite", ToClient, DuelInvitePacket{}},
+	{PacketDuelState, "DuelState", ToClient, DuelStatePacket{}},
+	{PacketArena, "Arena", ToServer, ArenaPacket{}},
+	{PacketArenaState, "ArenaState", ToClient, ArenaStatePacket{}},
+	{PacketSpectate, "Spectate", ToServer, SpectatePacket{}},
+	{PacketSpectateState, "SpectateState", ToClient, SpectateStatePacket{}},
+	{PacketBuild, "Build", ToServer, BuildPacket{}},
+	{PacketFarm, "Farm", ToServer, FarmPacket{}},
+	{PacketObjectUpdate, "ObjectUpdate", ToClient, ObjectUpdatePacket{}},
+	{PacketFish, "Fish", ToServer, FishPacket{}},
+	{PacketFishState, "FishState", ToClient, FishStatePacket{}},
+	{PacketMail, "Mail", ToServer, MailPacket{}},
+	{PacketMailbox, "Mailbox", ToClient, MailboxPacket{}},
+	{PacketCharacter, "Character", ToServer, CharacterPacket{}},
+	{PacketCharacterSheet, "CharacterSheet", ToClient, CharacterSheetPacket{}},
+	{PacketLoginQueue, "LoginQueue", ToClient, LoginQueuePacket{}},
+	{PacketEntityEvent, "EntityEvent", ToClient, EntityEventPacket{}},
+	{PacketChatMessage, "ChatMessage", ToServer, ChatMessagePacket{}},
+	{PacketChatBroadcast, "ChatBroadcast", ToClient, ChatBroadcastPacket{}},
+	{PacketHouse, "House", ToServer, HousePacket{}},
+	{PacketHouseState, "HouseState", ToClient, HouseStatePacket{}},
+	{PacketCombatState, "CombatState", ToClient, CombatStatePacket{}},
+	{PacketCutscene, "Cutscene", ToClient, CutscenePacket{}},
+	{PacketCutsceneSkip, "CutsceneSkip", ToServer, CutsceneSkipPacket{}},
+	{PacketMoveCorrection, "MoveCorrection", ToClient, MoveCorrectionPacket{}},
+	{PacketInteract, "Interact", ToServer, InteractPacket{}},
+	{PacketDialogue, "Dialogue", ToClient, DialoguePacket{}},
+	{PacketDialogueChoice, "DialogueChoice", ToServer, DialogueChoicePacket{}},
+	{PacketPing, "Ping", ToServer, PingPacket{}},
+	{PacketPong, "Pong", ToClient, PongPacket{}},
+	{PacketShopOpen, "ShopOpen", ToClient, ShopOpenPacket{}},
+	{PacketShopTransaction, "ShopTransaction", ToServer, ShopTransactionPacket{}},
+	{PacketWardrobeSync, "WardrobeSync", ToClient, WardrobeSyncPacket{}},
+	{PacketWardrobeAction, "WardrobeAction", ToServer, WardrobeActionPacket{}},
+	{PacketHelpTopics, "HelpTopics", ToClient, HelpTopicsPacket{}},
+	{PacketEntityDespawn, "EntityDespawn", ToClient, EntityDespawnPacket{}},
+	{PacketEntitySpawn, "EntitySpawn", ToClient, EntitySpawnPacket{}},
+	{PacketAwaySummary, "AwaySummary", ToClient, AwaySummaryPacket{}},
+}
+
 // ... existing code ...
 
 // UpdateDebugSettingsPacket (Client -> Server)
@@ -101,16 +223,17 @@ type HotbarActionPacket struct {
 // EquipmentSyncPacket (Server -> Client)
 type EquipmentSyncPacket struct {
 	Slots [9]struct {
-		ItemID string
+		ItemID     string
+		InstanceID string
 	}
 }
 
 // EquipmentActionPacket (Client -> Server)
 type EquipmentActionPacket struct {
-	Action string // "Equip", "Unequip"
-	Slot   int    // Equipment Slot (0-8)
+	Action     string // "Equip", "Unequip"
+	InstanceID string // Inventory item to equip, or worn item to take off
 	// For Equip:
-	InvSlot int // Inventory Slot (0-24)
+	Slot int // Equipment Slot (0-8)
 }
 
 type Packet struct {
@@ -120,8 +243,10 @@ type Packet struct {
 
 // Client -> Server
 type LoginPacket struct {
-	Username string
-	Password string
+	Username      string
+	Password      string
+	ClientVersion string // config.BuildString() of the client
+	Protocol      string // ProtocolFingerprint() of the client
 }
 
 // Server -> Client
@@ -136,10 +261,13 @@ type LoginResponsePacket struct {
 	MapTiles       []int
 	MapObjects     []int
 	UnlockedSpells []string
+	Spells         []components.Spell // Every spell the server knows, in spellbook order
 	Keybindings    map[string]int
 	DebugSettings  map[string]bool
 	OpenMenus      map[string]bool
-	IsRunning      bool
+	Stance         string // Saved movement stance
+	IsAdmin        bool   // GM tools (spectate anyone, free camera)
+	ServerVersion  string // config.BuildString() of the server
 }
 
 // Client -> Server
@@ -167,33 +295,59 @@ type InputPacket struct {
 
 // Server -> Client
 type StateUpdatePacket struct {
-	Entities []EntitySnapshot
+	Seq       uint32 // Broadcast slot since the server started; gaps are broadcasts it missed
+	Entities  []EntitySnapshot
+	WorldHour float64      // Time of day (0-24)
+	Retained  []ecs.Entity // Unchanged since last snapshot (distant, skipped this broadcast)
 }
 
+// EntitySnapshot is what changes about an entity from tick to tick. What it is and looks
+// like comes once, in its EntitySpawnPacket.
 type EntitySnapshot struct {
 	ID        ecs.Entity
 	Transform *components.TransformComponent
 	Physics   *components.PhysicsComponent
+	Stats     *components.StatsComponent // MaxHealth is left to the EntitySpawnPacket
+	Item      *components.GroundItemComponent
+	Stance    *components.StanceComponent
+	// What the entity is seen wearing (gear or wardrobe looks)
+	Appearance *components.AppearanceComponent
+	Status     *components.StatusEffectComponent // Buffs and statuses (icons over the health bar)
+
+	// Static data, filled in by the client from the entity's EntitySpawnPacket. The server
+	// leaves these nil, so they cost nothing on the wire.
 	Sprite    *components.SpriteComponent
-	Stats     *components.StatsComponent
+	Marker    *components.MarkerComponent
+	Waypoint  *components.WaypointComponent
+	Structure *components.StructureComponent
 }
 
 // InventorySyncPacket (Server -> Client)
 type InventorySyncPacket struct {
 	Slots []struct {
-		Index    int
-		ItemID   string
-		Quantity int
+		Index      int
+		ItemID     string
+		Quantity   int
+		Locked     bool
+		InstanceID string
 	}
 	Capacity int
+	Gold     int           // Wallet
+	Keyring  []KeyringSlot // Keys and quest items, apart from the bag's slots
+}
+
+// KeyringSlot is one stack on a player's keyring
+type KeyringSlot struct {
+	ItemID     string
+	Quantity   int
+	InstanceID string
 }
 
 // InventoryActionPacket (Client -> Server)
 type InventoryActionPacket struct {
-	ActionType string // "Swap", "Drop", "Use"
-	SlotA      int
-	SlotB      int    // For swap
-	ItemID     string // For drop/use (optional verification)
+	ActionType string // "Swap", "Drop", "ToggleLock", "Primary"
+	InstanceID string // Item acted on
+	ToSlot     int    // For swap: where it goes (swapping with what's there)
 }
 
 // MapSyncPacket (Server -> Client)
@@ -211,6 +365,441 @@ type CastSpellPacket struct {
 
 // SpellbookSyncPacket (Server -> Client) - For Cooldowns and Unlocks
 type SpellbookSyncPacket struct {
-	UnlockedSpells []string
-	Cooldowns      map[string]float64
+	UnlockedSpells    []string
+	Cooldowns         map[string]float64
+	CooldownReduction float64 // Fraction the player's INT takes off every spell's cooldown
+}
+
+// MoveToPacket (Client -> Server) - Click-to-move destination in world coordinates
+type MoveToPacket struct {
+	X, Y float64
+}
+
+// FollowPacket (Client -> Server) - Trail another entity (0 = stop following)
+type FollowPacket struct {
+	TargetID ecs.Entity
+}
+
+// PickupPacket (Client -> Server) - Pick up a ground item entity. Auto is set when the
+// loot filter asks rather than a click, and only succeeds for items the filter takes.
+type PickupPacket struct {
+	EntityID ecs.Entity
+	Auto     bool
+}
+
+// AnnouncementPacket (Server -> Client) - Server-wide message shown as a banner
+type AnnouncementPacket struct {
+	Message string
+}
+
+// ZoneChangePacket (Server -> Client) - Player crossed a zone boundary (empty ZoneID = wilderness)
+type ZoneChangePacket struct {
+	ZoneID   string
+	Name     string
+	MinLevel int
+	MaxLevel int
+	PvP      bool
+	Music    string
+}
+
+// WaypointSyncPacket (Server -> Client) - Discovered fast travel waypoints
+type WaypointSyncPacket struct {
+	Waypoints []struct {
+		ID   string
+		Name string
+	}
+	Fee int // Gold per teleport
+}
+
+// TravelPacket (Client -> Server) - Teleport to an unlocked waypoint
+type TravelPacket struct {
+	WaypointID string
+}
+
+// BugReportPacket (Client -> Server) - Playtester report with captured client state
+type BugReportPacket struct {
+	Description string
+	X, Y        float64
+	FPS         float64
+	Version     string
+	Logs        []string // Recent client log lines (oldest first)
+}
+
+// LootRollPacket (Server -> Client) - A boss drop is up for a need/greed roll
+type LootRollPacket struct {
+	RollID   int
+	ItemID   string
+	ItemName string
+	Quantity int
+	Duration float64 // Seconds before unanswered rolls count as pass
+}
+
+// LootChoicePacket (Client -> Server) - Answer to a LootRollPacket: "need", "greed" or "pass"
+type LootChoicePacket struct {
+	RollID int
+	Choice string
+}
+
+// DuelPacket (Client -> Server) - Action is "request", "accept", "decline" or "forfeit".
+// TargetID is the challenged player (request) or the challenger (accept/decline).
+type DuelPacket struct {
+	Action   string
+	TargetID ecs.Entity
+}
+
+// DuelInvitePacket (Server -> Client) - Another player challenges you
+type DuelInvitePacket struct {
+	ChallengerID   ecs.Entity
+	ChallengerName string
+	Timeout        float64 // Seconds before the invite lapses
+}
+
+// DuelStatePacket (Server -> Client) - Current duel (Active=false clears it)
+type DuelStatePacket struct {
+	Active           bool
+	OpponentID       ecs.Entity
+	OpponentName     string
+	CenterX, CenterY float64
+	Radius           float64 // Leaving the ring forfeits
+	Countdown        float64 // Seconds until fighting starts (0 = started)
+}
+
+// ArenaPacket (Client -> Server) - Action is "join" (near an Arena Master) or "leave"
+type ArenaPacket struct {
+	Action string
+}
+
+// ArenaStatePacket (Server -> Client) - Queue position or current match (both false = idle)
+type ArenaStatePacket struct {
+	Queued      bool
+	QueueSize   int
+	QueueNeeded int
+
+	InMatch   bool
+	Team      int    // 0 or 1
+	Score     [2]int // Indexed by team
+	Round     int
+	Countdown float64 // Seconds until the round's fight starts (0 = fighting)
+	TimeLeft  float64
+}
+
+// SpectatePacket (Client -> Server) - Action is "follow" (TargetID), "free" (GM free camera),
+// "arena" (watch a running arena match) or "stop"
+type SpectatePacket struct {
+	Action   string
+	TargetID ecs.Entity
+}
+
+// SpectateStatePacket (Server -> Client) - Whether the player is spectating, and whom (0 = free camera)
+type SpectateStatePacket struct {
+	Active     bool
+	TargetID   ecs.Entity
+	TargetName string
+}
+
+// BuildPacket (Client -> Server) - Action is "place" (ItemID onto TileX/TileY) or
+// "demolish" (TargetID)
+type BuildPacket struct {
+	Action   string
+	ItemID   string
+	TileX    int
+	TileY    int
+	TargetID ecs.Entity
+}
+
+// FarmPacket (Client -> Server) - Action is "plant" (SeedID) or "harvest" on a farmland tile
+type FarmPacket struct {
+	Action string
+	SeedID string
+	TileX  int
+	TileY  int
+}
+
+// ObjectUpdatePacket (Server -> Client) - One object layer tile changed (e.g. a crop grew),
+// patching the map sent in MapSyncPacket
+type ObjectUpdatePacket struct {
+	Level  int
+	TileX  int
+	TileY  int
+	Object int
+}
+
+// FishPacket (Client -> Server) - Action is "cast", "hook" or "stop"
+type FishPacket struct {
+	Action string
+}
+
+// FishStatePacket (Server -> Client) - The fishing minigame state and the player's skill
+type FishStatePacket struct {
+	State  string  // "" (not fishing), "waiting" or "bite"
+	Window float64 // Seconds left to hook while State is "bite"
+	Level  int     // Fishing skill level
+	XP     int
+}
+
+// MailPacket (Client -> Server) - Action is "open" (request the mailbox) or "take" (MailID)
+type MailPacket struct {
+	Action string
+	MailID int
+}
+
+// MailboxPacket (Server -> Client) - The player's unclaimed mail, oldest first
+type MailboxPacket struct {
+	Mail []MailEntry
+}
+
+type MailEntry struct {
+	ID      int
+	From    string
+	Subject string
+	Items   []string // e.g. "50x Gold Coin"
+}
+
+// CharacterPacket (Client -> Server) - Requests the character sheet
+type CharacterPacket struct {
+	Leaderboard bool // Include the playtime leaderboard
+}
+
+// CharacterSheetPacket (Server -> Client) - The player's playtime, skills, attributes and rankings
+type CharacterSheetPacket struct {
+	Playtime    float64 // Active seconds played, AFK time excluded
+	AFK         bool
+	Skills      map[string]int // Skill ID -> level
+	TopPlaytime []PlaytimeRank // Best first
+
+	// Attribute totals (gear included) and the combat stats derived from them
+	Attributes        components.Attributes
+	MeleeDamage       float64 // Multipliers
+	RangedDamage      float64
+	SpellPower        float64
+	CooldownReduction float64 // Fraction off spell cooldowns
+	AttackSpeed       float64
+}
+
+type PlaytimeRank struct {
+	Username string
+	Playtime float64
+}
+
+// LoginQueuePacket (Server -> Client) - Sent instead of the login response while the
+// server is full, repeated as the queue moves. The LoginResponsePacket follows on admission.
+type LoginQueuePacket struct {
+	Position int // 1 = next in
+	Size     int
+}
+
+// Entity event kinds
+const (
+	EventHit     = "hit"      // A projectile struck something
+	EventDeath   = "death"    // An entity was killed
+	EventLevelUp = "level_up" // A player gained a combat level
+)
+
+// EntityEventPacket (Server -> Client) - A one-off combat event for visual effects, sent to
+// players near it. Snapshots alone can't tell a death from walking out of view.
+type EntityEventPacket struct {
+	Kind     string
+	EntityID ecs.Entity // Who was hit or killed (0 for projectiles hitting terrain)
+	X, Y     float64    // World px, center of the effect
+	Color    color.RGBA // Projectile color for hits, the victim's for deaths
+}
+
+// EntitySpawnPacket (Server -> Client) - Introduces an entity that came into view: what
+// it is and looks like, which snapshots leave out. Sent before its first snapshot, and
+// again whenever any of it changes (e.g. a level up raising MaxHealth).
+type EntitySpawnPacket struct {
+	ID        ecs.Entity
+	Name      string
+	Sprite    components.SpriteComponent // Size, color and CharType
+	MaxHealth float64
+	Marker    *components.MarkerComponent
+	Waypoint  *components.WaypointComponent
+	Structure *components.StructureComponent
+}
+
+// EntityDespawnPacket (Server -> Client) - An entity the client was introduced to is gone:
+// it left the world (a corpse despawned) or the client's view. The client forgets it and
+// its EntitySpawnPacket at once.
+type EntityDespawnPacket struct {
+	EntityID ecs.Entity
+}
+
+// Chat channels
+const (
+	ChatGlobal  = "global"  // Everyone online
+	ChatLocal   = "local"   // Players nearby on the same level
+	ChatZone    = "zone"    // Players in a zone of the same name, on any level
+	ChatWhisper = "whisper" // One player, by name
+	ChatNotice  = "notice"  // From the server to one player (never sent by clients)
+)
+
+// ChatMessagePacket (Client -> Server) - A line typed into chat. To names the player
+// for whispers.
+type ChatMessagePacket struct {
+	Channel string
+	To      string
+	Text    string
+}
+
+// ChatBroadcastPacket (Server -> Client) - A chat line for the chat window. Whispers go
+// to both ends, so the sender sees what was sent.
+type ChatBroadcastPacket struct {
+	Channel string
+	From    string     // Sender's name ("" for notices)
+	To      string     // Whispers only
+	Text    string     // Already filtered
+	Speaker ecs.Entity // Sender standing near the receiver, for a speech bubble (0 = none)
+}
+
+// HousePacket (Client -> Server) - Action is "buy" (a deed, near the Housing Steward),
+// "enter" (the player's own house, moving in with a deed the first time), "visit"
+// (Name's house, near the steward), "leave", or "invite"/"uninvite" (Name)
+type HousePacket struct {
+	Action string
+	Name   string
+}
+
+// HouseStatePacket (Server -> Client) - The player's house and the houses they may visit
+type HouseStatePacket struct {
+	Owned     bool
+	Guests    []string // Invited to the player's house
+	InvitedTo []string // Owners whose houses the player may visit
+	Inside    string   // Owner of the house the player is in ("" = outside)
+}
+
+// CutscenePacket (Server -> Client) - Takes over the camera for a story beat. No shots
+// ends the cutscene early.
+type CutscenePacket struct {
+	ID        string
+	Shots     []CutsceneShot
+	Letterbox bool // Black bars top and bottom
+	Skippable bool // The player may skip it (CutsceneSkipPacket)
+}
+
+// CutsceneShot is one camera move of a cutscene
+type CutsceneShot struct {
+	Duration float64    // Seconds the shot lasts
+	Pan      float64    // Seconds of it spent moving the camera from where it was (0 = cut)
+	Focus    ecs.Entity // Entity the camera follows (0 = hold on X, Y)
+	X, Y     float64    // Camera center (world px) when not focusing an entity
+	Speaker  string     // Shown before the subtitle
+	Subtitle string
+}
+
+// CutsceneSkipPacket (Client -> Server) - Skip the cutscene playing
+type CutsceneSkipPacket struct {
+	ID string
+}
+
+// CombatStatePacket (Server -> Client) - Whether hostile NPCs are after the player (picks the music)
+type CombatStatePacket struct {
+	InCombat bool
+	Boss     bool
+}
+
+// Why the server didn't move a player the way their input asked
+const (
+	CorrectionBlocked = "blocked" // Ran into a wall or another body
+	CorrectionPushed  = "pushed"  // Pushed out of terrain they were stuck in
+	CorrectionSpeed   = "speed"   // Moved slower than asked (too tired to keep running)
+)
+
+// MoveCorrectionPacket (Server -> Client) - The server rejected part of the player's
+// movement; X, Y, Z is where they really are, so the client can reconcile right away
+// instead of jittering until the next snapshot
+type MoveCorrectionPacket struct {
+	X, Y   float64
+	Z      int
+	Reason string // Correction*
+}
+
+// InteractPacket (Client -> Server) - Talk to an NPC (TargetID 0 = the nearest one)
+type InteractPacket struct {
+	TargetID ecs.Entity
+}
+
+// DialoguePacket (Server -> Client) - What the NPC the player talks to says and the
+// replies on offer. NPC 0 ends the conversation.
+type DialoguePacket struct {
+	NPC     ecs.Entity
+	Speaker string
+	Text    string
+	Options []string
+}
+
+// DialogueChoicePacket (Client -> Server) - Pick a reply by index (-1 = goodbye)
+type DialogueChoicePacket struct {
+	Option int
+}
+
+// PingPacket (Client -> Server) - Measures the round trip; answered right away with a PongPacket
+type PingPacket struct {
+	Sent int64 // Client clock (Unix ns), echoed back
+}
+
+// PongPacket (Server -> Client) - Answer to a PingPacket
+type PongPacket struct {
+	Sent int64
+}
+
+// ShopEntry is one item a vendor sells
+type ShopEntry struct {
+	ItemID string
+	Price  int
+}
+
+// ShopOpenPacket (Server -> Client) - Opens a vendor's shop window (NPC 0 = close it).
+// SellPrices lists what the vendor pays for each item it buys.
+type ShopOpenPacket struct {
+	NPC        ecs.Entity
+	Name       string
+	Stock      []ShopEntry
+	SellPrices map[string]int
+}
+
+// ShopTransactionPacket (Client -> Server) - Buy ItemID from, or sell the stack InstanceID
+// to, a vendor
+type ShopTransactionPacket struct {
+	NPC        ecs.Entity
+	Action     string // "buy" or "sell"
+	ItemID     string // buy
+	InstanceID string // sell
+	Quantity   int
+}
+
+// WardrobeSyncPacket (Server -> Client) - The player's collected looks and the look chosen
+// for each equipment slot ("" = the worn item)
+type WardrobeSyncPacket struct {
+	Unlocked []string
+	Looks    [9]string
+}
+
+// WardrobeActionPacket (Client -> Server) - Shows a collected look in an equipment slot
+// (ItemID "" shows the worn item again)
+type WardrobeActionPacket struct {
+	Slot   int
+	ItemID string
+}
+
+// HelpTopic is one page of the in-game help
+type HelpTopic struct {
+	ID       string // File name without extension; windows link to it with their "?" button
+	Title    string
+	Keywords []string // Extra search terms
+	Body     string   // Markdown-ish: "# " headings and "- " bullets
+}
+
+// HelpTopicsPacket (Server -> Client) - The help pages, sent at login
+type HelpTopicsPacket struct {
+	Topics []HelpTopic
+}
+
+// AwaySummaryPacket (Server -> Client) - What happened while the player was offline,
+// sent at login when there's something to tell (shown as a dialog)
+type AwaySummaryPacket struct {
+	AwaySeconds int64
+	Mail        int // Mail received while away
+	MailGold    int // Gold attached to it
+	MailItems   int // Items attached to it, gold aside
+	RipeCrops   int // Their crops ready to harvest
 }
@@ -0,0 +1,144 @@
+package network
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// go test ./pkg/shared/network -update rewrites the golden files after a deliberate
+// protocol change
+var update = flag.Bool("update", false, "Rewrite the golden files in testdata")
+
+func TestPacketTable(t *testing.T) {
+	ids := make(map[PacketType]string)
+	for _, spec := range Packets {
+		if other, dup := ids[spec.Type]; dup {
+			t.Errorf("packet ID %d is used by %s and %s", spec.Type, other, spec.Name)
+		}
+		ids[spec.Type] = spec.Name
+		if spec.Direction != ToServer && spec.Direction != ToClient {
+			t.Errorf("%s: direction %q", spec.Name, spec.Direction)
+		}
+	}
+}
+
+// TestProtocolDescription fails when a packet or anything it carries changes shape.
+// Old clients and servers can't read the new layout, so check the change is intended,
+// then rerun with -update and commit the new description.
+func TestProtocolDescription(t *testing.T) {
+	got, err := json.MarshalIndent(DescribeProtocol(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+	golden := filepath.Join("testdata", "protocol.json")
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("the protocol no longer matches %s (make protodoc | diff pkg/shared/network/%s -)", golden, golden)
+	}
+}
+
+// TestGoldenPackets decodes a packet of every type encoded by the build that wrote the
+// golden files, and checks the current build encodes and decodes the same packet intact
+func TestGoldenPackets(t *testing.T) {
+	RegisterGobTypes()
+	for _, spec := range Packets {
+		want := Packet{Type: spec.Type, Data: samplePayload(spec.Data)}
+
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(want); err != nil {
+			t.Errorf("%s: encode: %v", spec.Name, err)
+			continue
+		}
+		golden := filepath.Join("testdata", "packets", spec.Name+".gob")
+		if *update {
+			if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, source := range []string{"current build", golden} {
+			data := buf.Bytes()
+			if source == golden {
+				var err error
+				if data, err = os.ReadFile(golden); err != nil {
+					t.Errorf("%s: %v", spec.Name, err)
+					continue
+				}
+			}
+			var got Packet
+			if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&got); err != nil {
+				t.Errorf("%s: decode from %s: %v", spec.Name, source, err)
+				continue
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s from %s decodes as\n%+v\nwant\n%+v", spec.Name, source, got.Data, want.Data)
+			}
+		}
+	}
+}
+
+// samplePayload returns a payload of the same type as zero with every exported field
+// set, so a renamed or retyped field shows up as a difference
+func samplePayload(zero interface{}) interface{} {
+	v := reflect.New(reflect.TypeOf(zero)).Elem()
+	n := 0
+	fillSample(v, "", &n)
+	return v.Interface()
+}
+
+func fillSample(v reflect.Value, name string, n *int) {
+	*n++
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(*n % 100))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(*n % 100))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(*n) + 0.5)
+	case reflect.String:
+		v.SetString(name)
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillSample(v.Elem(), name, n)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillSample(v.Index(0), name, n)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fillSample(v.Index(i), name, n)
+		}
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		fillSample(key, name+"Key", n)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fillSample(elem, name, n)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, elem)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() {
+				fillSample(v.Field(i), field.Name, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package world
+
+// Clock tracks the in-game time of day (hours, 0-24)
+type Clock struct {
+	Hour      float64
+	DayLength float64 // Real seconds per in-game day
+}
+
+func NewClock(startHour, dayLength float64) *Clock {
+	return &Clock{
+		Hour:      startHour,
+		DayLength: dayLength,
+	}
+}
+
+// Advance moves the clock forward by dt real seconds
+func (c *Clock) Advance(dt float64) {
+	if c.DayLength <= 0 {
+		return
+	}
+	c.Hour += dt * 24.0 / c.DayLength
+	for c.Hour >= 24 {
+		c.Hour -= 24
+	}
+}
+
+// IsDay reports whether the sun is up (06:00 - 20:00)
+func (c *Clock) IsDay() bool {
+	return IsDayHour(c.Hour)
+}
+
+func IsDayHour(hour float64) bool {
+	return hour >= 6 && hour < 20
+}
+
+// DaylightFactor returns 1 at full day, 0 at full night, blending through dawn (5-7) and dusk (19-21)
+func DaylightFactor(hour float64) float64 {
+	switch {
+	case hour >= 7 && hour < 19:
+		return 1
+	case hour >= 5 && hour < 7:
+		return (hour - 5) / 2
+	case hour >= 19 && hour < 21:
+		return 1 - (hour-19)/2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,108 @@
+package world
+
+import "math"
+
+// Collision grid: every tile is split into CollisionCells x CollisionCells sub-cells, and
+// its solid area is a bitmask over them (bit row*CollisionCells+col, row 0 at the top).
+// Water edges, corners and trees block only the part of the tile they cover, whatever the
+// tile size in pixels.
+const CollisionCells = 4
+
+const (
+	collisionFull   uint16 = 0xFFFF
+	collisionTop    uint16 = 0x00FF // Rows 0-1
+	collisionBottom uint16 = 0xFF00 // Rows 2-3
+	collisionLeft   uint16 = 0x3333 // Columns 0-1
+	collisionRight  uint16 = 0xCCCC // Columns 2-3
+	collisionCenter uint16 = 0x0660 // Middle 2x2 (tree trunks)
+)
+
+// CollisionMask returns the solid sub-cells of a tile type (0 = walkable).
+// Edges and corners are named after the side of the lake they border, so the water
+// (the solid part) lies on the opposite side: an edge-top tile is water in its bottom half.
+func (t TileType) CollisionMask() uint16 {
+	switch t {
+	case TileWaterEdgeTop:
+		return collisionBottom
+	case TileWaterEdgeBottom:
+		return collisionTop
+	case TileWaterEdgeLeft:
+		return collisionRight
+	case TileWaterEdgeRight:
+		return collisionLeft
+	case TileWaterCornerTL:
+		return collisionBottom & collisionRight
+	case TileWaterCornerTR:
+		return collisionBottom & collisionLeft
+	case TileWaterCornerBL:
+		return collisionTop & collisionRight
+	case TileWaterCornerBR:
+		return collisionTop & collisionLeft
+	case TileTree:
+		return collisionCenter
+	}
+	if t.IsSolid() {
+		return collisionFull
+	}
+	return 0
+}
+
+// CollisionMaskAt returns the solid sub-cells of a tile, terrain and object combined.
+// Out of bounds is fully solid.
+func (m *Map) CollisionMaskAt(tx, ty int) uint16 {
+	if tx < 0 || tx >= m.Width || ty < 0 || ty >= m.Height {
+		return collisionFull
+	}
+	mask := m.Tiles[ty][tx].Type.CollisionMask()
+	if IsSolidObject(m.Objects[ty][tx]) {
+		mask |= collisionCenter
+	}
+	return mask
+}
+
+// Collides reports whether a rect (world px, top-left + size) overlaps a solid sub-cell
+func (m *Map) Collides(x, y, w, h, tileSize float64) bool {
+	cell := tileSize / CollisionCells
+	startCX, endCX := int(math.Floor(x/cell)), int(math.Ceil((x+w)/cell))-1
+	startCY, endCY := int(math.Floor(y/cell)), int(math.Ceil((y+h)/cell))-1
+
+	for cy := startCY; cy <= endCY; cy++ {
+		for cx := startCX; cx <= endCX; cx++ {
+			tx, col := floorDiv(cx, CollisionCells)
+			ty, row := floorDiv(cy, CollisionCells)
+			if m.CollisionMaskAt(tx, ty)&(1<<(row*CollisionCells+col)) != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Depenetrate finds the shortest move (within maxDist px) that takes a rect stuck in solid
+// sub-cells back out onto open ground. ok is false if the rect is already free, or if
+// there is no open ground within reach.
+func (m *Map) Depenetrate(x, y, w, h, tileSize, maxDist float64) (dx, dy float64, ok bool) {
+	if !m.Collides(x, y, w, h, tileSize) {
+		return 0, 0, false
+	}
+	// Straight pushes first at each distance, then diagonals
+	dirs := [8][2]float64{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {0.7071, 0.7071}, {-0.7071, 0.7071}, {0.7071, -0.7071}, {-0.7071, -0.7071}}
+	for dist := 1.0; dist <= maxDist; dist++ {
+		for _, d := range dirs {
+			dx, dy = d[0]*dist, d[1]*dist
+			if !m.Collides(x+dx, y+dy, w, h, tileSize) {
+				return dx, dy, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// floorDiv splits a cell index into its tile and the cell within the tile (for negatives too)
+func floorDiv(cell, n int) (int, int) {
+	q, r := cell/n, cell%n
+	if r < 0 {
+		q, r = q-1, r+n
+	}
+	return q, r
+}
@@ -0,0 +1,134 @@
+package world
+
+import (
+	"math/rand"
+
+	"henry/pkg/shared/config"
+)
+
+// GenerateMap builds a deterministic synthetic map (grass, tree clumps, a lake and
+// a dirt crossroads) with spawners cycling through characterIDs. Used by the headless
+// simulation so no map files are needed.
+func GenerateMap(width, height, spawners int, characterIDs []string, seed int64) *Map {
+	m := NewMap(width, height)
+	rng := rand.New(rand.NewSource(seed))
+
+	// Tree clumps (~8% of tiles)
+	for i := 0; i < width*height/100; i++ {
+		cx, cy := rng.Intn(width), rng.Intn(height)
+		for j := 0; j < 8; j++ {
+			x, y := cx+rng.Intn(5)-2, cy+rng.Intn(5)-2
+			if x >= 0 && x < width && y >= 0 && y < height {
+				m.Tiles[y][x].Type = TileTree
+			}
+		}
+	}
+
+	// A lake with a sandy shore in one quadrant
+	lakeX, lakeY := width/4+rng.Intn(width/4+1), height/4+rng.Intn(height/4+1)
+	radius := min(width, height) / 10
+	for y := lakeY - radius - 1; y <= lakeY+radius+1; y++ {
+		for x := lakeX - radius - 1; x <= lakeX+radius+1; x++ {
+			if x < 0 || x >= width || y < 0 || y >= height {
+				continue
+			}
+			d := (x-lakeX)*(x-lakeX) + (y-lakeY)*(y-lakeY)
+			switch {
+			case d <= radius*radius:
+				m.Tiles[y][x].Type = TileWater
+			case d <= (radius+1)*(radius+1):
+				m.Tiles[y][x].Type = TileSand
+			}
+		}
+	}
+
+	// Crossroads through the middle keep the halves connected
+	for x := 0; x < width; x++ {
+		m.Tiles[height/2][x].Type = TileDirtPath
+	}
+	for y := 0; y < height; y++ {
+		m.Tiles[y][width/2].Type = TileDirtPath
+	}
+
+	if len(characterIDs) == 0 {
+		return m
+	}
+	tile := float64(config.TileSize)
+	for i := 0; i < spawners; i++ {
+		x, y := m.RandomWalkable(rng)
+		m.Spawners = append(m.Spawners, Spawner{
+			X:           float64(x) * tile,
+			Y:           float64(y) * tile,
+			CharacterID: characterIDs[i%len(characterIDs)],
+		})
+	}
+	return m
+}
+
+// RandomWalkable picks a random non-solid tile (falls back to the map center)
+func (m *Map) RandomWalkable(rng *rand.Rand) (int, int) {
+	for tries := 0; tries < 100; tries++ {
+		x, y := rng.Intn(m.Width), rng.Intn(m.Height)
+		if !m.Tiles[y][x].Type.IsSolid() {
+			return x, y
+		}
+	}
+	return m.Width / 2, m.Height / 2
+}
+
+// GenerateArena builds a small stone-floored PvP arena walled in by trees, covered by
+// a single "Arena" zone
+func GenerateArena(width, height int) *Map {
+	m := NewMap(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x == 0 || y == 0 || x == width-1 || y == height-1 {
+				m.Tiles[y][x].Type = TileTree
+			} else {
+				m.Tiles[y][x].Type = TileStoneFloor
+			}
+		}
+	}
+
+	tile := float64(config.TileSize)
+	m.Zones = append(m.Zones, Zone{
+		ID:     "arena",
+		Name:   "Arena",
+		X:      0,
+		Y:      0,
+		Width:  float64(width) * tile,
+		Height: float64(height) * tile,
+	})
+	return m
+}
+
+// GenerateHouse builds a wood-floored room walled in by trees with a doormat (stone)
+// in the middle of the bottom wall, covered by a single zone named after the owner
+func GenerateHouse(width, height int, name string) *Map {
+	m := NewMap(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x == 0 || y == 0 || x == width-1 || y == height-1 {
+				m.Tiles[y][x].Type = TileTree
+			} else {
+				m.Tiles[y][x].Type = TileWoodFloor
+			}
+		}
+	}
+	doorX, doorY := HouseDoor(width, height)
+	m.Tiles[doorY][doorX].Type = TileStoneFloor
+
+	tile := float64(config.TileSize)
+	m.Zones = append(m.Zones, Zone{
+		ID:     "house",
+		Name:   name,
+		Width:  float64(width) * tile,
+		Height: float64(height) * tile,
+	})
+	return m
+}
+
+// HouseDoor is the doormat tile of a GenerateHouse map: stepping on it leaves the house
+func HouseDoor(width, height int) (int, int) {
+	return width / 2, height - 2
+}
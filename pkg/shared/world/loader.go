@@ -3,15 +3,23 @@ package world
 import (
 	"encoding/json"
 	"fmt"
+	"henry/pkg/shared/components"
 	"os"
+	"path/filepath"
 )
 
 type MapDefinition struct {
-	Level    int          `json:"level"`
-	Width    int          `json:"width"`
-	Height   int          `json:"height"`
-	Layers   MapLayers    `json:"layers"`
-	Spawners []SpawnerDef `json:"spawners"`
+	Level        int              `json:"level"`
+	Width        int              `json:"width"`
+	Height       int              `json:"height"`
+	Layers       MapLayers        `json:"layers"`
+	Spawners     []SpawnerDef     `json:"spawners"`
+	SpawnRegions []SpawnRegionDef `json:"spawn_regions,omitempty"`
+	Zones        []ZoneDef        `json:"zones,omitempty"`
+	Waypoints    []WaypointDef    `json:"waypoints,omitempty"`
+	Triggers     []TriggerDef     `json:"triggers,omitempty"`
+
+	FriendlyFire bool `json:"friendly_fire,omitempty"`
 }
 
 type MapLayers struct {
@@ -20,9 +28,70 @@ type MapLayers struct {
 }
 
 type SpawnerDef struct {
-	X           float64 `json:"x"`
-	Y           float64 `json:"y"`
-	CharacterID string  `json:"character_id"`
+	X           float64                    `json:"x"`
+	Y           float64                    `json:"y"`
+	CharacterID string                     `json:"character_id"`
+	Schedule    []components.ScheduleEntry `json:"schedule,omitempty"`    // Overrides the character schedule
+	Patrol      [][2]float64               `json:"patrol,omitempty"`      // Overrides the character patrol (world px)
+	RespawnMin  float64                    `json:"respawn_min,omitempty"` // Respawn seconds with a crowd hunting
+	RespawnMax  float64                    `json:"respawn_max,omitempty"` // Respawn seconds with nobody around
+}
+
+// SpawnRegionDef keeps a rect stocked with up to MaxPopulation NPCs picked from
+// CharacterIDs, bringing one back every RespawnSeconds while it's short
+type SpawnRegionDef struct {
+	ID             string   `json:"id"`
+	X              float64  `json:"x"`
+	Y              float64  `json:"y"`
+	Width          float64  `json:"width"`
+	Height         float64  `json:"height"`
+	CharacterIDs   []string `json:"character_ids"`
+	MaxPopulation  int      `json:"max_population"`
+	RespawnSeconds float64  `json:"respawn_seconds"`
+}
+
+// ZoneDef is a named region given either as a rect (x/y/width/height) or a polygon
+type ZoneDef struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	MinLevel     int          `json:"min_level"`
+	MaxLevel     int          `json:"max_level"`
+	PvP          bool         `json:"pvp"`
+	Music        string       `json:"music"`
+	HealthScale  float64      `json:"health_scale,omitempty"`
+	DamageScale  float64      `json:"damage_scale,omitempty"`
+	XPScale      float64      `json:"xp_scale,omitempty"`
+	LevelScaling bool         `json:"level_scaling,omitempty"`
+	X            float64      `json:"x"`
+	Y            float64      `json:"y"`
+	Width        float64      `json:"width"`
+	Height       float64      `json:"height"`
+	Polygon      [][2]float64 `json:"polygon,omitempty"`
+}
+
+type WaypointDef struct {
+	ID   string  `json:"id"`
+	Name string  `json:"name"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+}
+
+// TriggerDef is a scripted area. Action is "message", "damage", "teleport", "house"
+// (the player's own house; Target* is where they come back out) or "cutscene".
+type TriggerDef struct {
+	ID       string  `json:"id"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Width    float64 `json:"width"`
+	Height   float64 `json:"height"`
+	Action   string  `json:"action"`
+	Message  string  `json:"message,omitempty"`
+	Damage   float64 `json:"damage,omitempty"`
+	TargetX  float64 `json:"target_x,omitempty"`
+	TargetY  float64 `json:"target_y,omitempty"`
+	TargetZ  int     `json:"target_z,omitempty"`
+	Cutscene string  `json:"cutscene,omitempty"` // "cutscene" (pkg/cutscenes ID)
+	Once     bool    `json:"once,omitempty"`
 }
 
 func LoadMap(path string) (*Map, error) {
@@ -38,6 +107,7 @@ func LoadMap(path string) (*Map, error) {
 
 	m := NewMap(def.Width, def.Height)
 	m.Level = def.Level
+	m.FriendlyFire = def.FriendlyFire
 
 	// Populate Spawners
 	for _, s := range def.Spawners {
@@ -45,6 +115,74 @@ func LoadMap(path string) (*Map, error) {
 			X:           s.X,
 			Y:           s.Y,
 			CharacterID: s.CharacterID,
+			Schedule:    s.Schedule,
+			Patrol:      s.Patrol,
+			RespawnMin:  s.RespawnMin,
+			RespawnMax:  s.RespawnMax,
+		})
+	}
+
+	// Populate Spawn Regions
+	for _, r := range def.SpawnRegions {
+		m.SpawnRegions = append(m.SpawnRegions, SpawnRegion{
+			ID:             r.ID,
+			X:              r.X,
+			Y:              r.Y,
+			Width:          r.Width,
+			Height:         r.Height,
+			CharacterIDs:   r.CharacterIDs,
+			MaxPopulation:  r.MaxPopulation,
+			RespawnSeconds: r.RespawnSeconds,
+		})
+	}
+
+	// Populate Zones
+	for _, z := range def.Zones {
+		m.Zones = append(m.Zones, Zone{
+			ID:           z.ID,
+			Name:         z.Name,
+			MinLevel:     z.MinLevel,
+			MaxLevel:     z.MaxLevel,
+			PvP:          z.PvP,
+			Music:        z.Music,
+			HealthScale:  z.HealthScale,
+			DamageScale:  z.DamageScale,
+			XPScale:      z.XPScale,
+			LevelScaling: z.LevelScaling,
+			X:            z.X,
+			Y:            z.Y,
+			Width:        z.Width,
+			Height:       z.Height,
+			Polygon:      z.Polygon,
+		})
+	}
+
+	// Populate Waypoints
+	for _, w := range def.Waypoints {
+		m.Waypoints = append(m.Waypoints, Waypoint{
+			ID:   w.ID,
+			Name: w.Name,
+			X:    w.X,
+			Y:    w.Y,
+		})
+	}
+
+	// Populate Triggers
+	for _, t := range def.Triggers {
+		m.Triggers = append(m.Triggers, Trigger{
+			ID:       t.ID,
+			X:        t.X,
+			Y:        t.Y,
+			Width:    t.Width,
+			Height:   t.Height,
+			Action:   t.Action,
+			Message:  t.Message,
+			Damage:   t.Damage,
+			TargetX:  t.TargetX,
+			TargetY:  t.TargetY,
+			TargetZ:  t.TargetZ,
+			Cutscene: t.Cutscene,
+			Once:     t.Once,
 		})
 	}
 
@@ -80,3 +218,24 @@ func LoadMap(path string) (*Map, error) {
 
 	return m, nil
 }
+
+// LoadMaps loads every level_*.json in dir, keyed by each map's level.
+// A missing directory yields an empty set; unreadable or invalid files are errors.
+func LoadMaps(dir string) (map[int]*Map, error) {
+	maps := make(map[int]*Map)
+	paths, err := filepath.Glob(filepath.Join(dir, "level_*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		m, err := LoadMap(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if _, dup := maps[m.Level]; dup {
+			return nil, fmt.Errorf("%s: duplicate map for level %d", path, m.Level)
+		}
+		maps[m.Level] = m
+	}
+	return maps, nil
+}
@@ -1,5 +1,7 @@
 package world
 
+import "henry/pkg/shared/components"
+
 type TileType int
 
 const (
@@ -26,6 +28,9 @@ const (
 	TileLava
 	TileStoneFloor
 	TileWoodFloor
+	TileFarmland // Tilled soil, seeds can be planted here
+	TileBridge   // Wooden bridge over deep water, walkable
+	TileDock     // Wooden planks over shallow water, walkable
 )
 
 func (t TileType) IsSolid() bool {
@@ -37,22 +42,98 @@ func (t TileType) IsSolid() bool {
 	}
 }
 
+// BlocksProjectiles reports whether projectiles stop at a tile. Water, bridges and docks
+// are flat, so shots fly over them; only trees stop them.
+func (t TileType) BlocksProjectiles() bool {
+	return t == TileTree
+}
+
+// MinMoveCost is the cheapest MoveCost of any tile (keeps A*'s heuristic admissible)
+const MinMoveCost = 0.7
+
+// MoveCost is how expensive a walkable tile is to cross, relative to grass: roads and
+// planks are quicker, loose or slippery ground slower. Pathfinding weighs steps by it
+// and movement divides speed by it.
+func (t TileType) MoveCost() float64 {
+	switch t {
+	case TileCobblePath:
+		return MinMoveCost
+	case TileDirtPath, TileBridge, TileDock, TileStoneFloor, TileWoodFloor:
+		return 0.8
+	case TileFarmland:
+		return 1.2
+	case TileSand, TileIce:
+		return 1.4
+	case TileSnow, TileWaterShallow:
+		return 1.6
+	default:
+		return 1
+	}
+}
+
+// MoveCostAt returns the move cost of a tile (grass cost out of bounds)
+func (m *Map) MoveCostAt(tx, ty int) float64 {
+	if tx < 0 || tx >= m.Width || ty < 0 || ty >= m.Height {
+		return 1
+	}
+	return m.Tiles[ty][tx].Type.MoveCost()
+}
+
 type Tile struct {
 	Type TileType
 }
 
 type Map struct {
-	Level    int
-	Width    int
-	Height   int
-	Tiles    [][]Tile // Ground Layer
-	Objects  [][]int  // Object Layer (0=Empty, >0=ID)
-	Spawners []Spawner
+	Level        int
+	Width        int
+	Height       int
+	Tiles        [][]Tile // Ground Layer
+	Objects      [][]int  // Object Layer (0=Empty, >0=ID, see objects.go)
+	Spawners     []Spawner
+	SpawnRegions []SpawnRegion
+	Zones        []Zone
+	Waypoints    []Waypoint
+	Triggers     []Trigger
+
+	FriendlyFire bool // Same-faction projectiles hit each other
+}
+
+// Waypoint is a fast travel point players unlock by walking up to it
+type Waypoint struct {
+	ID   string
+	Name string
+	X, Y float64
+}
+
+// Trigger is a scripted rect area (tutorial popups, traps, teleports)
+type Trigger struct {
+	ID                  string
+	X, Y, Width, Height float64
+	Action              string
+	Message             string
+	Damage              float64
+	TargetX, TargetY    float64
+	TargetZ             int
+	Cutscene            string
+	Once                bool
 }
 
 type Spawner struct {
 	X, Y        float64
 	CharacterID string
+	Schedule    []components.ScheduleEntry
+	Patrol      [][2]float64 // World px
+	RespawnMin  float64      // Seconds (0 = the character's default range)
+	RespawnMax  float64
+}
+
+// SpawnRegion is a rect kept stocked with hostile NPCs (see SpawnRegionDef)
+type SpawnRegion struct {
+	ID                  string
+	X, Y, Width, Height float64
+	CharacterIDs        []string
+	MaxPopulation       int
+	RespawnSeconds      float64
 }
 
 func NewMap(width, height int) *Map {
@@ -0,0 +1,36 @@
+package world
+
+// Object layer IDs (Map.Objects). IDs below ObjectCropBase are decorations: trees and rocks
+// are obstacles, flowers, reeds and the seasonal pumpkins and lanterns are walked through. Crops are walkable and encode their
+// kind and growth stage in the ID, so growing them only needs an object layer update.
+const (
+	ObjectTree    = 2 // Same value as TileTree, which maps have always used for trees
+	ObjectRock    = 3
+	ObjectFlowers = 4
+	ObjectReeds   = 5
+	ObjectPumpkin = 6 // Seasonal decorations (see systems.SeasonalSystem)
+	ObjectLantern = 7
+
+	ObjectCropBase = 100
+	CropStages     = 4 // Seeded, sprouting, growing, ripe
+	CropRipe       = CropStages - 1
+)
+
+// CropObject returns the object ID of a crop kind at a growth stage
+func CropObject(kind, stage int) int {
+	return ObjectCropBase + kind*CropStages + stage
+}
+
+// CropFromObject splits a crop object ID into kind and stage (ok is false for non-crops)
+func CropFromObject(id int) (kind, stage int, ok bool) {
+	if id < ObjectCropBase {
+		return 0, 0, false
+	}
+	return (id - ObjectCropBase) / CropStages, (id - ObjectCropBase) % CropStages, true
+}
+
+// IsSolidObject reports whether an object blocks movement (trees and rocks)
+func IsSolidObject(id int) bool {
+	return id > 0 && id < ObjectCropBase && id != ObjectFlowers && id != ObjectReeds &&
+		id != ObjectPumpkin && id != ObjectLantern
+}
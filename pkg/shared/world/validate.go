@@ -0,0 +1,202 @@
+package world
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// MapPoint is a place players must be able to reach: the player spawn, NPC spawners,
+// waypoints and teleport triggers
+type MapPoint struct {
+	Kind string // "spawn", "spawner", "patrol", "waypoint", "teleport"
+	Name string
+	Z    int
+	X, Y float64 // World px (transform position; trigger rect top-left for teleports)
+}
+
+func (p MapPoint) String() string {
+	return fmt.Sprintf("%s %s (level %d at %.0f,%.0f)", p.Kind, p.Name, p.Z, p.X, p.Y)
+}
+
+// Pocket is a walkable region no player can reach
+type Pocket struct {
+	Z      int
+	TX, TY int // The tile it starts in
+	Cells  int // Size in collision sub-cells (CollisionCells² per tile)
+}
+
+// MapReport is the outcome of ValidateMaps
+type MapReport struct {
+	Unreachable []MapPoint // Errors: places players can never get to
+	Pockets     []Pocket   // Warnings: walled-off walkable areas, largest first
+}
+
+// OK reports whether every point of interest is reachable
+func (r *MapReport) OK() bool {
+	return len(r.Unreachable) == 0
+}
+
+// ValidateMaps flood-fills where a body of bodySize px can walk (the same sub-cell
+// collision as movement) from the player spawn, following teleports to their targets,
+// and reports every spawner, waypoint and teleport outside the reached area plus any
+// walkable pockets left over. Points inside solid ground count from wherever the shore
+// push (up to pushRange px) would put them.
+func ValidateMaps(maps map[int]*Map, spawn MapPoint, tileSize, bodySize, pushRange float64) *MapReport {
+	levels := make([]int, 0, len(maps))
+	grids := make(map[int]*reachGrid, len(maps))
+	for z, m := range maps {
+		levels = append(levels, z)
+		grids[z] = newReachGrid(m, tileSize, bodySize)
+	}
+	sort.Ints(levels)
+
+	// The node a body standing at a transform position ends up on
+	nodeOf := func(z int, x, y float64) (int, int, bool) {
+		g, ok := grids[z]
+		if !ok {
+			return 0, 0, false
+		}
+		bx, by := x+(tileSize-bodySize)/2, y+(tileSize-bodySize)/2
+		if dx, dy, pushed := g.m.Depenetrate(bx, by, bodySize, bodySize, tileSize, pushRange); pushed {
+			bx, by = bx+dx, by+dy
+		}
+		return int(math.Floor(bx / g.cell)), int(math.Floor(by / g.cell)), true
+	}
+	isReached := func(z int, x, y float64) bool {
+		nx, ny, ok := nodeOf(z, x, y)
+		return ok && grids[z].reached(nx, ny)
+	}
+	// A trigger fires when the body overlaps its rect
+	triggerReached := func(z int, t Trigger) bool {
+		g := grids[z]
+		for ny := int(math.Floor((t.Y - bodySize) / g.cell)); float64(ny)*g.cell < t.Y+t.Height; ny++ {
+			for nx := int(math.Floor((t.X - bodySize) / g.cell)); float64(nx)*g.cell < t.X+t.Width; nx++ {
+				bx, by := float64(nx)*g.cell, float64(ny)*g.cell
+				overlaps := bx < t.X+t.Width && bx+bodySize > t.X && by < t.Y+t.Height && by+bodySize > t.Y
+				if overlaps && g.reached(nx, ny) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	// Flood from the spawn, then from the target of every teleport the flood reaches,
+	// until no new teleport opens up
+	if nx, ny, ok := nodeOf(spawn.Z, spawn.X, spawn.Y); ok {
+		grids[spawn.Z].fill(nx, ny)
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, z := range levels {
+			for _, t := range maps[z].Triggers {
+				if t.Action != "teleport" || !triggerReached(z, t) {
+					continue
+				}
+				if nx, ny, ok := nodeOf(t.TargetZ, t.TargetX, t.TargetY); ok && grids[t.TargetZ].fill(nx, ny) > 0 {
+					changed = true
+				}
+			}
+		}
+	}
+
+	report := &MapReport{}
+	if !isReached(spawn.Z, spawn.X, spawn.Y) {
+		report.Unreachable = append(report.Unreachable, spawn)
+	}
+	for _, z := range levels {
+		m, g := maps[z], grids[z]
+		for _, sp := range m.Spawners {
+			if !isReached(z, sp.X, sp.Y) {
+				report.Unreachable = append(report.Unreachable, MapPoint{Kind: "spawner", Name: sp.CharacterID, Z: z, X: sp.X, Y: sp.Y})
+			}
+			for _, p := range sp.Patrol {
+				if !isReached(z, p[0], p[1]) {
+					report.Unreachable = append(report.Unreachable, MapPoint{Kind: "patrol", Name: sp.CharacterID, Z: z, X: p[0], Y: p[1]})
+				}
+			}
+		}
+		for _, wp := range m.Waypoints {
+			if !isReached(z, wp.X, wp.Y) {
+				report.Unreachable = append(report.Unreachable, MapPoint{Kind: "waypoint", Name: wp.ID, Z: z, X: wp.X, Y: wp.Y})
+			}
+		}
+		for _, t := range m.Triggers {
+			if t.Action == "teleport" && !triggerReached(z, t) {
+				report.Unreachable = append(report.Unreachable, MapPoint{Kind: "teleport", Name: t.ID, Z: z, X: t.X, Y: t.Y})
+			}
+		}
+
+		// Whatever open ground is left unreached forms pockets
+		for ny := 0; ny < g.height; ny++ {
+			for nx := 0; nx < g.width; nx++ {
+				if g.open(nx, ny) && !g.reached(nx, ny) {
+					tx, ty := int(float64(nx)*g.cell/tileSize), int(float64(ny)*g.cell/tileSize)
+					report.Pockets = append(report.Pockets, Pocket{Z: z, TX: tx, TY: ty, Cells: g.fill(nx, ny)})
+				}
+			}
+		}
+	}
+	sort.SliceStable(report.Pockets, func(i, j int) bool { return report.Pockets[i].Cells > report.Pockets[j].Cells })
+	return report
+}
+
+// reachGrid holds one node per collision sub-cell: a body whose top-left corner sits
+// on the node. Bodies move between neighbouring nodes in steps smaller than themselves,
+// so two open neighbours are always connected.
+type reachGrid struct {
+	m             *Map
+	cell, body    float64
+	tileSize      float64
+	width, height int
+	seen          []bool
+}
+
+func newReachGrid(m *Map, tileSize, bodySize float64) *reachGrid {
+	return &reachGrid{
+		m:        m,
+		cell:     tileSize / CollisionCells,
+		body:     bodySize,
+		tileSize: tileSize,
+		width:    m.Width * CollisionCells,
+		height:   m.Height * CollisionCells,
+		seen:     make([]bool, m.Width*m.Height*CollisionCells*CollisionCells),
+	}
+}
+
+func (g *reachGrid) inBounds(nx, ny int) bool {
+	return nx >= 0 && ny >= 0 && nx < g.width && ny < g.height
+}
+
+// open reports whether a body fits at a node
+func (g *reachGrid) open(nx, ny int) bool {
+	return g.inBounds(nx, ny) && !g.m.Collides(float64(nx)*g.cell, float64(ny)*g.cell, g.body, g.body, g.tileSize)
+}
+
+func (g *reachGrid) reached(nx, ny int) bool {
+	return g.inBounds(nx, ny) && g.seen[ny*g.width+nx]
+}
+
+// fill marks the open nodes 4-connected to (nx, ny) and returns how many it marked
+func (g *reachGrid) fill(nx, ny int) int {
+	if !g.open(nx, ny) || g.reached(nx, ny) {
+		return 0
+	}
+	count := 0
+	stack := [][2]int{{nx, ny}}
+	g.seen[ny*g.width+nx] = true
+	for len(stack) > 0 {
+		x, y := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+		count++
+		for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			x2, y2 := x+d[0], y+d[1]
+			if g.open(x2, y2) && !g.reached(x2, y2) {
+				g.seen[y2*g.width+x2] = true
+				stack = append(stack, [2]int{x2, y2})
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,42 @@
+package world
+
+import "henry/pkg/shared/geom"
+
+// Zone is a named region of a map with gameplay metadata
+type Zone struct {
+	ID       string
+	Name     string
+	MinLevel int
+	MaxLevel int
+	PvP      bool
+	Music    string // Music playlist ID (client/assets/music.go)
+
+	// Difficulty of NPCs spawned in the zone: multipliers (0 = 1), and LevelScaling to
+	// match their level to the players nearby (within MinLevel..MaxLevel when set)
+	HealthScale  float64
+	DamageScale  float64
+	XPScale      float64
+	LevelScaling bool
+
+	// Shape: Polygon if set, otherwise the rect
+	X, Y, Width, Height float64
+	Polygon             [][2]float64
+}
+
+// Contains reports whether a world position lies inside the zone
+func (z *Zone) Contains(x, y float64) bool {
+	if len(z.Polygon) >= 3 {
+		return geom.PointInPolygon(z.Polygon, x, y)
+	}
+	return geom.AABB{X: z.X, Y: z.Y, W: z.Width, H: z.Height}.Contains(x, y)
+}
+
+// ZoneAt returns the first zone containing the position (nil = unnamed wilderness)
+func (m *Map) ZoneAt(x, y float64) *Zone {
+	for i := range m.Zones {
+		if m.Zones[i].Contains(x, y) {
+			return &m.Zones[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package storage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DataRoot holds everything the server saves (players, NPC checkpoint, shared world files)
+// and the designer data it loads (maps, economy, spells, world events, schedule)
+const DataRoot = "data"
+
+// ExportDir is where realm exports go unless another path is given
+const ExportDir = "data/exports"
+
+// Folders under DataRoot left out of exports: host-local history and earlier exports
+var exportSkip = []string{BackupDir, LeaderboardSnapshotDir, BugReportDir, TelemetryDir, ExportDir}
+
+// Bumped when the archive layout changes
+const exportVersion = 1
+
+// exportManifestName is the first entry of every export, next to the data folder
+const exportManifestName = "realm.json"
+
+// ExportManifest describes a realm export
+type ExportManifest struct {
+	Version int
+	Created time.Time
+	Files   int
+}
+
+// ExportRealm packs DataRoot into a gzipped tar at file (ExportPath(now) for the default
+// name) and returns how many files it holds
+func ExportRealm(file string, now time.Time) (int, error) {
+	var files []string
+	err := filepath.WalkDir(DataRoot, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			for _, skip := range exportSkip {
+				if filepath.Clean(p) == filepath.Clean(skip) {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		if d.Type().IsRegular() && !strings.HasSuffix(p, ".tmp") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return 0, err
+	}
+	tmp := file + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	if err := writeRealm(out, files, now); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return 0, err
+	}
+	if err := out.Close(); err != nil {
+		return 0, err
+	}
+	return len(files), os.Rename(tmp, file)
+}
+
+// ExportPath is the default export file for a time
+func ExportPath(now time.Time) string {
+	return filepath.Join(ExportDir, "realm-"+now.Format("20060102-150405")+".tar.gz")
+}
+
+func writeRealm(w io.Writer, files []string, now time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest, err := json.MarshalIndent(ExportManifest{Version: exportVersion, Created: now, Files: len(files)}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: exportManifestName, Mode: 0644, Size: int64(len(manifest)), ModTime: now}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifest); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{Name: filepath.ToSlash(file), Mode: 0644, Size: int64(len(data)), ModTime: now}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ImportRealm replaces DataRoot with the contents of an export. The archive is unpacked
+// next to it first, so a bad archive leaves the current data alone; the old folder is
+// kept under a timestamped name, which is returned with the number of files imported.
+// Only call while no server is running on this data.
+func ImportRealm(archive string, now time.Time) (string, int, error) {
+	staging := DataRoot + ".import"
+	if err := os.RemoveAll(staging); err != nil {
+		return "", 0, err
+	}
+	n, err := unpackRealm(archive, staging)
+	if err != nil {
+		os.RemoveAll(staging)
+		return "", 0, fmt.Errorf("%s: %w", archive, err)
+	}
+
+	previous := ""
+	if _, err := os.Stat(DataRoot); err == nil {
+		previous = DataRoot + "-before-import-" + now.Format("20060102-150405")
+		if err := os.Rename(DataRoot, previous); err != nil {
+			return "", 0, err
+		}
+	}
+	if err := os.Rename(filepath.Join(staging, DataRoot), DataRoot); err != nil {
+		if previous != "" {
+			os.Rename(previous, DataRoot) // Put the old data back
+		}
+		return "", 0, err
+	}
+	return previous, n, os.RemoveAll(staging)
+}
+
+// unpackRealm extracts an export's data folder into dir, checking the manifest and that
+// no entry escapes the data folder
+func unpackRealm(archive, dir string) (int, error) {
+	in, err := os.Open(archive)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return 0, err
+	}
+	tr := tar.NewReader(gz)
+
+	var manifest *ExportManifest
+	files := 0
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return files, err
+		}
+		if header.Name == exportManifestName {
+			manifest = &ExportManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return files, fmt.Errorf("bad manifest: %w", err)
+			}
+			if manifest.Version != exportVersion {
+				return files, fmt.Errorf("export version %d, this server reads %d", manifest.Version, exportVersion)
+			}
+			continue
+		}
+		if manifest == nil {
+			return files, errors.New("not a realm export (no manifest)")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(header.Name)
+		if !strings.HasPrefix(name, DataRoot+"/") || strings.Contains(name, "..") {
+			return files, fmt.Errorf("entry %q is outside the data folder", header.Name)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return files, err
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			return files, err
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return files, err
+		}
+		if err := out.Close(); err != nil {
+			return files, err
+		}
+		files++
+	}
+	if manifest == nil {
+		return 0, errors.New("not a realm export (no manifest)")
+	}
+	if files == 0 {
+		return 0, errors.New("export holds no files")
+	}
+	if files != manifest.Files {
+		return files, fmt.Errorf("archive holds %d files, manifest says %d", files, manifest.Files)
+	}
+	return files, nil
+}
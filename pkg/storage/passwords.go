@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"henry/pkg/auth"
+)
+
+// MigratePasswords hashes the plaintext passwords left in player saves by older servers
+// and returns how many saves it rewrote. Saves it can't read are skipped (and reported in
+// the error) so one broken file doesn't keep the rest in plain text.
+func MigratePasswords() (int, error) {
+	files, err := filepath.Glob(filepath.Join(DataDir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	migrated := 0
+	var firstErr error
+	for _, file := range files {
+		data, err := LoadPlayer(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err == nil && data != nil && data.Password != "" {
+			err = HashPassword(data)
+			if err == nil {
+				err = SavePlayer(*data)
+			}
+			if err == nil {
+				migrated++
+			}
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return migrated, firstErr
+}
+
+// HashPassword replaces a save's legacy plaintext password with its hash. Saves that
+// already have a hash just lose the plaintext.
+func HashPassword(data *PlayerSaveData) error {
+	if data.PasswordHash == "" {
+		hash, err := auth.Hash(data.Password)
+		if err != nil {
+			return err
+		}
+		data.PasswordHash = hash
+	}
+	data.Password = ""
+	return nil
+}
@@ -1,32 +1,55 @@
 package storage
 
 import (
+	"bufio"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
 )
 
 const DataDir = "data/players"
 
 type PlayerSaveData struct {
 	Username       string
-	Password       string // Plaintext for now as requested (TODO: Hash)
+	PasswordHash   string // argon2id, see pkg/auth
+	Password       string `json:",omitempty"` // Legacy plaintext, replaced by PasswordHash (see MigratePasswords)
+	IsAdmin        bool   // May log in during maintenance
+	Banned         bool   `json:",omitempty"` // Logins are refused (console "ban")
+	BanReason      string `json:",omitempty"`
 	X, Y           float64
 	Health         float64
 	Keybindings    map[string]int  // Action -> Ebiten Key ID
 	DebugSettings  map[string]bool // Toggle -> Enabled
 	Inventory      []InventorySlotSave
+	Gold           int                 // Wallet (coins in the Inventory of older saves move here on load)
+	Keyring        []InventorySlotSave `json:",omitempty"` // Keys and quest items (Index unused)
 	Hotbar         [10]HotbarSlotSave
 	Equipment      [9]EquipmentSlotSave
+	Cosmetics      []string  // Collected looks (item IDs)
+	Looks          [9]string // Look shown per equipment slot, "" = the worn item
 	UnlockedSpells []string
+	Waypoints      []string        // Discovered waypoint IDs
+	Skills         map[string]int  // Skill ID -> XP
+	XP             int             // Combat XP (the level follows from it)
+	Playtime       float64         // Active seconds played, AFK time excluded
+	LastSeen       int64           `json:",omitempty"` // Unix seconds of the last save (the logout, once offline)
+	Cutscenes      []string        `json:",omitempty"` // IDs of the Once cutscenes already seen
 	OpenMenus      map[string]bool // WindowName -> IsVisible
-	IsRunning      bool
+	Stance         string          // Movement stance ("walk", "run", "sneak")
+	IsRunning      bool            `json:",omitempty"` // Legacy, replaced by Stance
 }
 
 type InventorySlotSave struct {
-	Index    int
-	ItemID   string
-	Quantity int
+	Index      int
+	ItemID     string
+	Quantity   int
+	Locked     bool
+	InstanceID string `json:",omitempty"` // Missing in saves from before instance IDs
 }
 
 type HotbarSlotSave struct {
@@ -35,7 +58,8 @@ type HotbarSlotSave struct {
 }
 
 type EquipmentSlotSave struct {
-	ItemID string
+	ItemID     string
+	InstanceID string `json:",omitempty"`
 }
 
 func GetFilePath(username string) string {
@@ -74,5 +98,610 @@ func LoadPlayer(username string) (*PlayerSaveData, error) {
 	if err := json.NewDecoder(file).Decode(&data); err != nil {
 		return nil, err
 	}
+	if data.IsRunning && data.Stance == "" {
+		data.Stance = "run"
+	}
+	data.IsRunning = false
+	return &data, nil
+}
+
+// World state checkpoint (optional, see GameServer.PersistNPCs)
+const WorldStateFile = "data/world/npcs.json"
+
+type WorldSaveData struct {
+	SavedAt int64 // Unix seconds
+	NPCs    []NPCSave
+}
+
+// NPCSave is keyed by the spawner that owns the NPC (CharID + spawn point)
+type NPCSave struct {
+	CharID         string
+	SpawnX, SpawnY float64
+	X, Y           float64
+	Z              int
+	Health         float64
+	IsDead         bool
+	RespawnTimer   float64
+}
+
+func SaveWorldState(data WorldSaveData) error {
+	if err := os.MkdirAll(filepath.Dir(WorldStateFile), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temp file first so a crash mid-save doesn't leave a truncated checkpoint
+	tmp := WorldStateFile + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, WorldStateFile)
+}
+
+// LoadWorldState returns nil, nil when no checkpoint exists
+func LoadWorldState() (*WorldSaveData, error) {
+	file, err := os.Open(WorldStateFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var data WorldSaveData
+	if err := json.NewDecoder(file).Decode(&data); err != nil {
+		return nil, err
+	}
 	return &data, nil
 }
+
+// Bug reports are stored one file per report
+const BugReportDir = "data/bugreports"
+
+type BugReport struct {
+	Username    string
+	ReportedAt  time.Time
+	Description string
+	X, Y        float64
+	FPS         float64
+	Version     string
+	Logs        []string
+}
+
+// SaveBugReport writes the report and returns its ID (the file name without extension)
+func SaveBugReport(report BugReport) (string, error) {
+	if err := os.MkdirAll(BugReportDir, 0755); err != nil {
+		return "", err
+	}
+
+	id := fmt.Sprintf("%s_%s", report.ReportedAt.Format("20060102-150405"), report.Username)
+	file, err := os.Create(filepath.Join(BugReportDir, id+".json"))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return id, encoder.Encode(report)
+}
+
+// Leaderboard stats live in one file, keyed by username
+const LeaderboardFile = "data/leaderboard.json"
+
+type LeaderboardEntry struct {
+	Username   string
+	DuelWins   int
+	DuelLosses int
+	DuelDraws  int
+
+	ArenaWins   int
+	ArenaLosses int
+
+	Playtime float64 // Active seconds played (mirrors the player save)
+}
+
+type Leaderboard struct {
+	Entries map[string]*LeaderboardEntry
+}
+
+// LoadLeaderboard returns an empty leaderboard when none has been saved yet
+func LoadLeaderboard() (*Leaderboard, error) {
+	lb := &Leaderboard{Entries: make(map[string]*LeaderboardEntry)}
+	file, err := os.Open(LeaderboardFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lb, nil
+		}
+		return lb, err
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(lb); err != nil {
+		return lb, err
+	}
+	if lb.Entries == nil {
+		lb.Entries = make(map[string]*LeaderboardEntry)
+	}
+	return lb, nil
+}
+
+// SaveLeaderboard writes via a temp file so a crash mid-save keeps the old standings
+func SaveLeaderboard(lb *Leaderboard) error {
+	return writeJSONAtomic(LeaderboardFile, lb)
+}
+
+// writeJSONAtomic writes v as indented JSON to a temp file and renames it over path
+func writeJSONAtomic(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Entry returns (creating if needed) a player's row
+func (lb *Leaderboard) Entry(username string) *LeaderboardEntry {
+	e, ok := lb.Entries[username]
+	if !ok {
+		e = &LeaderboardEntry{Username: username}
+		lb.Entries[username] = e
+	}
+	return e
+}
+
+// RecordDuel counts a finished duel (draw = neither side won)
+func (lb *Leaderboard) RecordDuel(winner, loser string, draw bool) {
+	if draw {
+		lb.Entry(winner).DuelDraws++
+		lb.Entry(loser).DuelDraws++
+		return
+	}
+	lb.Entry(winner).DuelWins++
+	lb.Entry(loser).DuelLosses++
+}
+
+// RecordArena counts a finished arena match for one player
+func (lb *Leaderboard) RecordArena(username string, won bool) {
+	if won {
+		lb.Entry(username).ArenaWins++
+	} else {
+		lb.Entry(username).ArenaLosses++
+	}
+}
+
+// RecordPlaytime stores a player's current active playtime
+func (lb *Leaderboard) RecordPlaytime(username string, seconds float64) {
+	lb.Entry(username).Playtime = seconds
+}
+
+// TopPlaytime returns up to n players ordered by active playtime
+func (lb *Leaderboard) TopPlaytime(n int) []LeaderboardEntry {
+	var rows []LeaderboardEntry
+	for _, e := range lb.Entries {
+		if e.Playtime > 0 {
+			rows = append(rows, *e)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Playtime != rows[j].Playtime {
+			return rows[i].Playtime > rows[j].Playtime
+		}
+		return rows[i].Username < rows[j].Username
+	})
+	if len(rows) > n {
+		rows = rows[:n]
+	}
+	return rows
+}
+
+// TopDuelists returns up to n players ordered by wins (fewer losses breaks ties)
+func (lb *Leaderboard) TopDuelists(n int) []LeaderboardEntry {
+	var rows []LeaderboardEntry
+	for _, e := range lb.Entries {
+		if e.DuelWins+e.DuelLosses+e.DuelDraws > 0 {
+			rows = append(rows, *e)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].DuelWins != rows[j].DuelWins {
+			return rows[i].DuelWins > rows[j].DuelWins
+		}
+		if rows[i].DuelLosses != rows[j].DuelLosses {
+			return rows[i].DuelLosses < rows[j].DuelLosses
+		}
+		return rows[i].Username < rows[j].Username
+	})
+	if len(rows) > n {
+		rows = rows[:n]
+	}
+	return rows
+}
+
+// Player-built structures (walls, campfires, claim banners) survive restarts in one file
+const StructuresFile = "data/structures.json"
+
+type StructureSave struct {
+	ItemID string
+	Owner  string // Builder's username
+	X, Y   float64
+	Z      int
+}
+
+// LoadStructures returns nothing (and no error) when none have been built yet
+func LoadStructures() ([]StructureSave, error) {
+	data, err := os.ReadFile(StructuresFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var structures []StructureSave
+	if err := json.Unmarshal(data, &structures); err != nil {
+		return nil, fmt.Errorf("failed to parse structures json: %w", err)
+	}
+	return structures, nil
+}
+
+// SaveStructures replaces the saved structures (via a temp file, like the leaderboard)
+func SaveStructures(structures []StructureSave) error {
+	return writeJSONAtomic(StructuresFile, structures)
+}
+
+// Crops planted on farmland keep growing across restarts
+const CropsFile = "data/crops.json"
+
+type CropSave struct {
+	SeedID string
+	Owner  string // Planter's username
+	Level  int
+	TileX  int
+	TileY  int
+	Growth float64 // Seconds grown so far
+}
+
+// LoadCrops returns nothing (and no error) when nothing has been planted yet
+func LoadCrops() ([]CropSave, error) {
+	data, err := os.ReadFile(CropsFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var crops []CropSave
+	if err := json.Unmarshal(data, &crops); err != nil {
+		return nil, fmt.Errorf("failed to parse crops json: %w", err)
+	}
+	return crops, nil
+}
+
+// SaveCrops replaces the saved crops
+func SaveCrops(crops []CropSave) error {
+	return writeJSONAtomic(CropsFile, crops)
+}
+
+// Mailboxes of every player live in one file, so mail reaches offline players too
+const MailFile = "data/mail.json"
+
+type MailItem struct {
+	ItemID     string
+	Quantity   int
+	InstanceID string `json:",omitempty"` // Empty for gold, and in mail from before instance IDs
+}
+
+type Mail struct {
+	ID      int
+	From    string
+	Subject string
+	Items   []MailItem
+	Sent    int64 `json:",omitempty"` // Unix seconds (missing in mail from before it was kept)
+}
+
+type MailStore struct {
+	NextID int
+	Boxes  map[string][]Mail // Username -> unclaimed mail, oldest first
+}
+
+// LoadMail returns an empty store when no mail has been sent yet
+func LoadMail() (*MailStore, error) {
+	store := &MailStore{NextID: 1, Boxes: make(map[string][]Mail)}
+	data, err := os.ReadFile(MailFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return store, err
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return store, fmt.Errorf("failed to parse mail json: %w", err)
+	}
+	if store.Boxes == nil {
+		store.Boxes = make(map[string][]Mail)
+	}
+	return store, nil
+}
+
+// SaveMail replaces the saved mailboxes
+func SaveMail(store *MailStore) error {
+	return writeJSONAtomic(MailFile, store)
+}
+
+// Player houses (owner, guest list and furniture) live in one file; the interiors are
+// rebuilt from it whenever someone walks in
+const HousesFile = "data/houses.json"
+
+type FurnitureSave struct {
+	ItemID       string
+	TileX, TileY int
+}
+
+type HouseSave struct {
+	Owner     string
+	Guests    []string // Usernames allowed in besides the owner
+	Furniture []FurnitureSave
+}
+
+// LoadHouses returns nothing (and no error) when no house has been bought yet
+func LoadHouses() ([]HouseSave, error) {
+	data, err := os.ReadFile(HousesFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var houses []HouseSave
+	if err := json.Unmarshal(data, &houses); err != nil {
+		return nil, fmt.Errorf("failed to parse houses json: %w", err)
+	}
+	return houses, nil
+}
+
+// SaveHouses replaces the saved houses
+func SaveHouses(houses []HouseSave) error {
+	return writeJSONAtomic(HousesFile, houses)
+}
+
+// Leaderboard snapshots and backups are written by scheduled tasks
+const (
+	LeaderboardSnapshotDir = "data/leaderboard_snapshots"
+	BackupDir              = "data/backups"
+)
+
+// SnapshotLeaderboard copies the current standings to a file named after the date
+func SnapshotLeaderboard(lb *Leaderboard, now time.Time) (string, error) {
+	path := filepath.Join(LeaderboardSnapshotDir, "leaderboard-"+now.Format("2006-01-02")+".json")
+	return path, writeJSONAtomic(path, lb)
+}
+
+// Backup copies the player saves and the shared world files into a timestamped folder
+func Backup(now time.Time) (string, error) {
+	dir := filepath.Join(BackupDir, now.Format("20060102-150405"))
+	files, err := filepath.Glob(filepath.Join(DataDir, "*.json"))
+	if err != nil {
+		return dir, err
+	}
+	files = append(files, LeaderboardFile, StructuresFile, CropsFile, MailFile, HousesFile)
+
+	for _, src := range files {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // Nothing saved yet
+			}
+			return dir, err
+		}
+		dst := filepath.Join(dir, src)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return dir, err
+		}
+		if err := os.WriteFile(dst, data, 0644); err != nil {
+			return dir, err
+		}
+	}
+	return dir, nil
+}
+
+// TelemetryDir receives the gameplay telemetry exports (only written when telemetry is on)
+const TelemetryDir = "data/telemetry"
+
+// TelemetryReport is what players did during one export interval
+type TelemetryReport struct {
+	Start         time.Time
+	End           time.Time
+	Logins        int
+	UniquePlayers int
+	PeakOnline    int
+	Deaths        map[string]int // Zone -> player deaths
+	Kills         map[string]int // Zone -> NPCs killed by players
+	Items         map[string]int // Item ID -> uses
+	Spells        map[string]int // Spell ID -> casts
+}
+
+// SaveTelemetry writes a report as JSON and as CSV (metric,key,count rows) into dir,
+// named after its end time. Returns the JSON path.
+func SaveTelemetry(dir string, report TelemetryReport) (string, error) {
+	base := filepath.Join(dir, "telemetry-"+report.End.Format("20060102-150405"))
+	if err := writeJSONAtomic(base+".json", report); err != nil {
+		return base + ".json", err
+	}
+
+	rows := [][]string{
+		{"metric", "key", "count"},
+		{"logins", "", strconv.Itoa(report.Logins)},
+		{"unique_players", "", strconv.Itoa(report.UniquePlayers)},
+		{"peak_online", "", strconv.Itoa(report.PeakOnline)},
+	}
+	for _, group := range []struct {
+		metric string
+		counts map[string]int
+	}{{"deaths", report.Deaths}, {"kills", report.Kills}, {"items", report.Items}, {"spells", report.Spells}} {
+		keys := make([]string, 0, len(group.counts))
+		for key := range group.counts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			rows = append(rows, []string{group.metric, key, strconv.Itoa(group.counts[key])})
+		}
+	}
+	file, err := os.Create(base + ".csv")
+	if err != nil {
+		return base + ".json", err
+	}
+	if err := csv.NewWriter(file).WriteAll(rows); err != nil {
+		file.Close()
+		return base + ".json", err
+	}
+	return base + ".json", file.Close()
+}
+
+// MacroFlagsFile is the admin report of accounts the macro heuristics flagged for review
+const MacroFlagsFile = "data/reports/macros.json"
+
+// MacroFlag is one suspicion raised about an account. Nothing acts on it automatically;
+// an admin reviews the report and decides.
+type MacroFlag struct {
+	Username  string
+	Reason    string // periodic_input, inhuman_reaction or nonstop_activity
+	Detail    string // The numbers behind it, for the reviewer
+	FlaggedAt time.Time
+}
+
+// LoadMacroFlags returns nothing (and no error) when no account has been flagged yet
+func LoadMacroFlags() ([]MacroFlag, error) {
+	data, err := os.ReadFile(MacroFlagsFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var flags []MacroFlag
+	if err := json.Unmarshal(data, &flags); err != nil {
+		return nil, fmt.Errorf("failed to parse macro flags json: %w", err)
+	}
+	return flags, nil
+}
+
+// SaveMacroFlags replaces the macro report
+func SaveMacroFlags(flags []MacroFlag) error {
+	return writeJSONAtomic(MacroFlagsFile, flags)
+}
+
+// ChatLogFile keeps the latest chat of every channel for moderators
+const ChatLogFile = "data/chat_log.json"
+
+// ChatLine is one chat message as moderators see it
+type ChatLine struct {
+	Time     time.Time
+	Channel  string
+	From     string
+	To       string `json:",omitempty"` // Whisper recipient
+	Text     string // As sent, filtered words masked
+	Original string `json:",omitempty"` // As typed, when the filter masked some of it
+}
+
+// LoadChatLog returns nothing (and no error) when nobody has chatted yet
+func LoadChatLog() (map[string][]ChatLine, error) {
+	data, err := os.ReadFile(ChatLogFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var channels map[string][]ChatLine
+	if err := json.Unmarshal(data, &channels); err != nil {
+		return nil, fmt.Errorf("failed to parse chat log json: %w", err)
+	}
+	return channels, nil
+}
+
+// SaveChatLog replaces the chat log (channel -> lines, oldest first)
+func SaveChatLog(channels map[string][]ChatLine) error {
+	return writeJSONAtomic(ChatLogFile, channels)
+}
+
+// ItemAuditFile is the append-only trail of items created, destroyed and changing hands,
+// one JSON entry per line
+const ItemAuditFile = "data/audit/items.jsonl"
+
+// ItemAuditEntry is one item event. Account gained or lost the items; Other is who or
+// what was on the other side (a vendor, a mail sender, a dropped item's owner).
+type ItemAuditEntry struct {
+	Time       time.Time
+	Action     string // e.g. bought, sold, dropped, picked_up, mailed, claimed, used, despawned
+	Account    string `json:",omitempty"` // Empty when no player holds the items (e.g. despawned off the ground)
+	Other      string `json:",omitempty"`
+	ItemID     string
+	InstanceID string `json:",omitempty"` // Known when a single stack moves
+	Quantity   int
+}
+
+// AppendItemAudit adds entries to the end of the audit file
+func AppendItemAudit(path string, entries []ItemAuditEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(file)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// ReadItemAudit returns the audit entries match accepts, oldest first. A missing file
+// has no entries; lines that don't parse (e.g. cut off by a crash) are skipped.
+func ReadItemAudit(path string, match func(ItemAuditEntry) bool) ([]ItemAuditEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []ItemAuditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry ItemAuditEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil && match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
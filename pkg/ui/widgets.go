@@ -2,6 +2,7 @@ package ui
 
 import (
 	"henry/pkg/client/assets"
+	"henry/pkg/items"
 	"henry/pkg/shared/components"
 	"image/color"
 	"strings"
@@ -60,6 +61,7 @@ type Window struct {
 	ContentHeight            float64
 	FooterHeight             float64
 	ShowScrollbar            bool
+	OnHelp                   func() // Shows a "?" button in the title bar when set
 }
 
 func NewWindow(x, y, w, h float64, title string) *Window {
@@ -111,6 +113,12 @@ func (w *Window) Update() (bool, error) {
 	consumed := false
 	mx, my := ebiten.CursorPosition()
 
+	// Help Button
+	if w.OnHelp != nil && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && w.overHelp(mx, my) {
+		w.OnHelp()
+		return true, nil
+	}
+
 	// Handle Dragging
 	if w.Draggable && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
 		if mx >= int(w.X) && mx <= int(w.X+w.Width) && my >= int(w.Y) && my <= int(w.Y+20) {
@@ -208,6 +216,15 @@ func (w *Window) Draw(screen *ebiten.Image) {
 	// Draw Title Bar (Overlay to hide scrolled-up items)
 	ebitenutil.DrawRect(screen, w.X, w.Y, w.Width, 20, color.RGBA{80, 80, 80, 255})
 	ebitenutil.DebugPrintAt(screen, w.Title, int(w.X+5), int(w.Y+2))
+	if w.OnHelp != nil {
+		mx, my := ebiten.CursorPosition()
+		c := color.RGBA{110, 110, 110, 255}
+		if w.overHelp(mx, my) {
+			c = color.RGBA{140, 140, 140, 255}
+		}
+		ebitenutil.DrawRect(screen, w.X+w.Width-18, w.Y+2, 16, 16, c)
+		ebitenutil.DebugPrintAt(screen, "?", int(w.X+w.Width-13), int(w.Y+2))
+	}
 
 	// Draw Bottom Overlay? (To hide scrolled-down items peeking)
 	// Optional, but clean.
@@ -240,6 +257,11 @@ func (w *Window) Draw(screen *ebiten.Image) {
 	}
 }
 
+// overHelp reports whether the point is on the title bar's "?" button
+func (w *Window) overHelp(x, y int) bool {
+	return float64(x) >= w.X+w.Width-18 && float64(x) <= w.X+w.Width-2 && float64(y) >= w.Y+2 && float64(y) <= w.Y+18
+}
+
 func (w *Window) HandleInput(x, y int) bool {
 	if !w.Visible {
 		return false
@@ -259,6 +281,7 @@ func (w *Window) HandleInput(x, y int) bool {
 type InventoryWidget struct {
 	BaseElement
 	Slots    []string // Item IDs
+	Locked   []bool   // Per-slot lock flags
 	SlotSize float64
 	Cols     int
 
@@ -287,6 +310,7 @@ func NewInventoryWidget(x, y float64, cols, rows int, slotSize float64) *Invento
 	return &InventoryWidget{
 		BaseElement: BaseElement{X: x, Y: y, Width: w, Height: h, Visible: true},
 		Slots:       make([]string, cols*rows),
+		Locked:      make([]bool, cols*rows),
 		SlotSize:    slotSize,
 		Cols:        cols,
 		HiddenIndex: -1,
@@ -381,10 +405,10 @@ func (cm *ContextMenu) Show(x, y float64, options []MenuOption, minX, minY, maxX
 		// Capture action for closure
 		action := opt.Action
 		btn := NewButton(0, 0, 100, 25, opt.Text, func() {
+			cm.Visible = false // Auto-close (before action so it can open a follow-up menu)
 			if action != nil {
 				action()
 			}
-			cm.Visible = false // Auto-close
 		})
 		btn.Style = ButtonStyleSecondary // Darker style
 		cm.Buttons = append(cm.Buttons, btn)
@@ -523,6 +547,14 @@ func (iw *InventoryWidget) Draw(screen *ebiten.Image) {
 				ebitenutil.DrawRect(screen, sx+5, sy+5, iw.SlotSize-10, iw.SlotSize-10, color.RGBA{200, 100, 100, 255})
 				ebitenutil.DebugPrintAt(screen, itemID[:1], int(sx+10), int(sy+10))
 			}
+
+			// Lock Badge (Bottom Right)
+			if i < len(iw.Locked) && iw.Locked[i] {
+				ebitenutil.DrawRect(screen, sx+iw.SlotSize-12, sy+iw.SlotSize-10, 9, 7, color.RGBA{220, 180, 40, 255})
+				ebitenutil.DrawLine(screen, sx+iw.SlotSize-10, sy+iw.SlotSize-10, sx+iw.SlotSize-10, sy+iw.SlotSize-14, color.RGBA{220, 180, 40, 255})
+				ebitenutil.DrawLine(screen, sx+iw.SlotSize-10, sy+iw.SlotSize-14, sx+iw.SlotSize-5, sy+iw.SlotSize-14, color.RGBA{220, 180, 40, 255})
+				ebitenutil.DrawLine(screen, sx+iw.SlotSize-5, sy+iw.SlotSize-14, sx+iw.SlotSize-5, sy+iw.SlotSize-10, color.RGBA{220, 180, 40, 255})
+			}
 		}
 
 		// Draw Hotkey Number
@@ -535,7 +567,24 @@ func (iw *InventoryWidget) Draw(screen *ebiten.Image) {
 		// Border
 		ebitenutil.DrawLine(screen, sx, sy, sx+iw.SlotSize, sy, color.Gray{100})
 		ebitenutil.DrawLine(screen, sx, sy, sx, sy+iw.SlotSize, color.Gray{100})
+		if i != iw.HiddenIndex {
+			drawRarityBorder(screen, itemID, sx, sy, iw.SlotSize)
+		}
+	}
+}
+
+// drawRarityBorder outlines a slot in the color of its item's rarity (Common items and
+// spells keep the plain border)
+func drawRarityBorder(screen *ebiten.Image, itemID string, sx, sy, size float64) {
+	def, ok := items.Get(itemID)
+	if !ok || def.Rarity == items.RarityCommon {
+		return
 	}
+	c := def.Rarity.Color()
+	ebitenutil.DrawRect(screen, sx+1, sy+1, size-2, 2, c)
+	ebitenutil.DrawRect(screen, sx+1, sy+size-3, size-2, 2, c)
+	ebitenutil.DrawRect(screen, sx+1, sy+1, 2, size-2, c)
+	ebitenutil.DrawRect(screen, sx+size-3, sy+1, 2, size-2, c)
 }
 
 func (iw *InventoryWidget) HandleInput(x, y int) bool {
@@ -543,6 +592,9 @@ func (iw *InventoryWidget) HandleInput(x, y int) bool {
 }
 
 func (iw *InventoryWidget) IsHovered(x, y int) bool {
+	if !iw.Visible {
+		return false // e.g. on a tab that isn't shown
+	}
 	return float64(x) >= iw.X && float64(x) <= iw.X+iw.Width && float64(y) >= iw.Y && float64(y) <= iw.Y+iw.Height
 }
 
@@ -652,9 +704,10 @@ type SpellsWidget struct {
 	SlotSize float64
 
 	// Logic
-	UnlockedSpells map[string]bool
-	Cooldowns      map[string]float64
-	ActiveSpellID  string
+	UnlockedSpells    map[string]bool
+	Cooldowns         map[string]float64
+	CooldownReduction float64 // Fraction taken off every spell's cooldown
+	ActiveSpellID     string
 
 	// Tooltip State
 	HoveredSpellID     string
@@ -785,7 +838,7 @@ func (sw *SpellsWidget) Draw(screen *ebiten.Image) {
 		if lastCast, ok := sw.Cooldowns[spellID]; ok && lastCast > 0 {
 			now := float64(time.Now().UnixMilli()) / 1000.0
 			elapsed := now - lastCast
-			cd := spellDef.Cooldown
+			cd := spellDef.Cooldown * (1 - sw.CooldownReduction)
 			if elapsed < cd {
 				pct := 1.0 - (elapsed / cd)
 				h := sw.SlotSize * pct
@@ -878,6 +931,9 @@ func (ew *EquipmentWidget) Draw(screen *ebiten.Image) {
 		// Border
 		ebitenutil.DrawLine(screen, sx, sy, sx+ew.SlotSize, sy, color.Gray{100})
 		ebitenutil.DrawLine(screen, sx, sy, sx, sy+ew.SlotSize, color.Gray{100})
+		if i != ew.HiddenIndex {
+			drawRarityBorder(screen, itemID, sx, sy, ew.SlotSize)
+		}
 	}
 }
 